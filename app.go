@@ -1,7 +1,10 @@
 package wails
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"sync"
 	"syscall"
 
 	"github.com/syossan27/tebata"
@@ -11,7 +14,9 @@ import (
 	"github.com/wailsapp/wails/lib/interfaces"
 	"github.com/wailsapp/wails/lib/ipc"
 	"github.com/wailsapp/wails/lib/logger"
+	"github.com/wailsapp/wails/lib/perf"
 	"github.com/wailsapp/wails/lib/renderer"
+	"github.com/wailsapp/wails/pkg/cli"
 	wailsruntime "github.com/wailsapp/wails/runtime"
 )
 
@@ -27,9 +32,59 @@ type Runtime = wailsruntime.Runtime
 // the front end
 type Store = wailsruntime.Store
 
+// Stream sends the results of a bound method to the frontend as they are
+// made
+type Stream = wailsruntime.Stream
+
 // CustomLogger is a specialised logger
 type CustomLogger = logger.CustomLogger
 
+// BillingProvider connects in-app purchases to a store's billing API
+type BillingProvider = wailsruntime.BillingProvider
+
+// CalendarProvider connects the runtime to the user's calendars
+type CalendarProvider = wailsruntime.CalendarProvider
+
+// CalendarEvent is an event in one of the user's calendars
+type CalendarEvent = wailsruntime.CalendarEvent
+
+// RemindersProvider connects the runtime to the user's reminders
+type RemindersProvider = wailsruntime.RemindersProvider
+
+// Reminder is an item in one of the user's reminder lists
+type Reminder = wailsruntime.Reminder
+
+// Menu is a menu bar for the app
+type Menu = wailsruntime.Menu
+
+// MenuItem is an item in a Menu
+type MenuItem = wailsruntime.MenuItem
+
+// MenuRole is a standard menu item's behaviour, EG: wailsruntime.MenuRoleCopy
+type MenuRole = wailsruntime.MenuRole
+
+// BindOption customises how an object is bound
+type BindOption = interfaces.BindOption
+
+// Subsystems turns off optional subsystems of the app
+type Subsystems = interfaces.Subsystems
+
+// KeyEvent is a key pressed or released in a window
+type KeyEvent = interfaces.KeyEvent
+
+// GestureEvent is a mouse side button click, sideways scroll or swipe in a
+// window
+type GestureEvent = interfaces.GestureEvent
+
+// Backdrop is how the desktop shows through a transparent window
+type Backdrop = interfaces.Backdrop
+
+// The backdrops of transparent windows. See AppConfig.Transparent.
+const (
+	BackdropTransparent = interfaces.BackdropTransparent
+	BackdropTranslucent = interfaces.BackdropTranslucent
+)
+
 // ----------------------------------------------------------------------------------
 
 // App defines the main application struct
@@ -43,6 +98,18 @@ type App struct {
 	bindingManager interfaces.BindingManager // Handles binding of Go code to renderer
 	eventManager   interfaces.EventManager   // Handles all the events
 	runtime        interfaces.Runtime        // The runtime object for registered structs
+	flags          *cli.Flags                // The flags the app was launched with
+	windows        []*Window                 // The additional windows that have been opened
+	windowsLock    sync.Mutex                // Guards windows and running
+	running        bool                      // Set once additional windows can be opened
+	embedded       bool                      // Set when a host app runs the UI loop
+	closing        int32                     // Set while OnBeforeClose runs
+	stopOnce       sync.Once                 // Shuts an embedded app down once
+	perfReport     bool                      // Set by --perf in debug builds
+	recorder       *perf.Recorder            // Records the timings for --perf and the dev overlay
+	latency        string                    // Set by --latency in debug builds
+	jitter         string                    // Set by --jitter in debug builds
+	failureRate    string                    // Set by --failure-rate in debug builds
 }
 
 // CreateApp creates the application window with the given configuration
@@ -64,9 +131,15 @@ func CreateApp(optionalConfig ...*AppConfig) *App {
 
 	appconfig, err := newConfig(userConfig)
 	if err != nil {
-		result.log.Fatalf("Cannot use custom HTML: %s", err.Error())
+		result.log.Fatalf("Invalid configuration: %s", err.Error())
 	}
 	result.config = appconfig
+	result.bindingManager.SetBinaryResults(appconfig.GetBinaryData())
+
+	// Events are queued unless buffering is disabled
+	if appconfig.Subsystems.DisableEventBuffering {
+		result.eventManager = event.NewManagerWithBuffer(0)
+	}
 
 	// Set up the CLI if not in release mode
 	if BuildMode != cmd.BuildModeProd {
@@ -85,12 +158,50 @@ func CreateApp(optionalConfig ...*AppConfig) *App {
 // Run the app
 func (a *App) Run() error {
 
+	// Host an isolated service if we were started to do so
+	if service := os.Getenv(binding.IsolatedServiceEnv); service != "" {
+		return a.serveIsolated(service)
+	}
+
+	// Describe the bound methods and exit if asked to
+	if os.Getenv(binding.APISpecEnv) != "" {
+		return a.writeAPISpec()
+	}
+
+	// Write the output to the terminal the app was started from
+	if a.config.AttachConsole {
+		attachConsole()
+	}
+
+	// Parse the flags before the GUI starts
+	flags, err := cli.Parse(os.Args[1:])
+	if err != nil {
+		a.log.Error(err.Error())
+		return err
+	}
+	a.flags = flags
+
+	// Call a bound method and exit if asked to
+	if flags.HeadlessCommand != "" {
+		return a.runHeadless()
+	}
+
+	// Log what the app prints
+	if a.config.CaptureOutput {
+		err = captureOutput()
+		if err != nil {
+			a.log.Error(err.Error())
+			return err
+		}
+	}
+
 	if BuildMode != cmd.BuildModeProd {
-		return a.cli.Run()
+		// The flags parsed above have been removed from the arguments
+		return a.cli.RunArgs(flags.Args)
 	}
 
 	a.logLevel = "error"
-	err := a.start()
+	err = a.start()
 	if err != nil {
 		a.log.Error(err.Error())
 	}
@@ -98,6 +209,31 @@ func (a *App) Run() error {
 }
 
 func (a *App) start() error {
+	// Quit for the copy moved to the Applications folder to start
+	if a.checkInstallation() {
+		return nil
+	}
+
+	err := a.launch()
+	if err != nil {
+		return err
+	}
+
+	// Defer the shutdown
+	defer a.shutdown()
+
+	// Run the renderer
+	err = a.renderer.Run()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// launch initialises the renderer and starts the managers, ready for the
+// UI loop to run
+func (a *App) launch() error {
 
 	// Set the log level
 	logger.SetLogLevel(a.logLevel)
@@ -105,13 +241,22 @@ func (a *App) start() error {
 	// Log starup
 	a.log.Info("Starting")
 
+	// Switch to the latest asset patch before the assets are verified
+	a.applyAssetPatch()
+
+	// Verify the frontend assets haven't been tampered with
+	err := a.verifyIntegrity()
+	if err != nil {
+		return err
+	}
+
 	// Check if we are to run in bridge mode
 	if BuildMode == cmd.BuildModeBridge {
 		a.renderer = renderer.NewBridge()
 	}
 
 	// Initialise the renderer
-	err := a.renderer.Initialise(a.config, a.ipc, a.eventManager)
+	err = a.renderer.Initialise(a.config, a.ipc, a.eventManager)
 	if err != nil {
 		return err
 	}
@@ -126,11 +271,115 @@ func (a *App) start() error {
 	// Start event manager and give it our renderer
 	a.eventManager.Start(a.renderer)
 
+	// Minimise the window once it is shown if launched with --minimized
+	if a.flags != nil && a.flags.Minimized {
+		a.eventManager.Once("wails:ready", func(...interface{}) {
+			a.renderer.Minimise()
+		})
+	}
+
+	// The renderer verifies the assets again before injecting them, leaving
+	// out those that fail until the OnIntegrityFailure hook has decided what
+	// to do. Assets that failed at startup and were accepted are trusted, so
+	// the hook is called once for each failure.
+	a.eventManager.On("wails:integrity:failed", func(...interface{}) {
+		err := a.verifyIntegrity()
+		if err != nil {
+			a.log.Error(err.Error())
+			a.renderer.Close()
+			return
+		}
+		// Reload the page to inject the repaired or accepted assets
+		a.renderer.Reload()
+	})
+
+	// Pass new tab requests from the tab bar to the app
+	if a.config.OnNewTab != nil {
+		a.eventManager.On("wails:window:newtab", func(...interface{}) {
+			a.config.OnNewTab()
+		})
+	}
+
+	// Ask the app before closing the main window
+	if a.config.OnBeforeClose != nil {
+		a.eventManager.On("wails:window:closing", func(...interface{}) {
+			a.confirmClose()
+		})
+	}
+
+	// Recover from webview crashes
+	a.eventManager.On("wails:webview-crashed", func(data ...interface{}) {
+		reason := ""
+		if len(data) > 0 {
+			reason, _ = data[0].(string)
+		}
+		a.handleWebViewCrash(reason)
+	})
+
+	// Record the frontend's timings and how long calls take
+	if a.perfReport {
+		a.recordPerformance()
+	}
+
+	// Trace the calls and events for the dev overlay
+	if a.config.DevOverlay && BuildMode != cmd.BuildModeProd {
+		a.startDevOverlay()
+	}
+
+	// Simulate a slow or unreliable app
+	err = a.injectFaults()
+	if err != nil {
+		return err
+	}
+
 	// Start the IPC Manager and give it the event manager and binding manager
+	a.ipc.SetMaxPayloadSize(a.config.GetMaxPayloadSize())
+	a.ipc.SetBinaryResults(a.config.GetBinaryData())
 	a.ipc.Start(a.eventManager, a.bindingManager)
 
 	// Create the runtime
-	a.runtime = wailsruntime.NewRuntime(a.eventManager, a.renderer)
+	rt := wailsruntime.NewRuntime(a.eventManager, a.renderer, a.config)
+	rt.Flags = a.flags
+	a.runtime = rt
+
+	// Regain access to the folders the user chose in previous launches
+	rt.Bookmarks.Restore()
+
+	// Queue the files shared with the app when it was launched to receive
+	// them
+	if a.flags != nil && a.flags.Share {
+		a.eventManager.Emit(wailsruntime.ShareEvent, &wailsruntime.SharedContent{Files: a.flags.Args})
+	}
+
+	// Connect in-app purchases to the store
+	if a.config.Billing != nil {
+		err = rt.Purchases.SetProvider(a.config.Billing)
+		if err != nil {
+			a.log.Errorf("Unable to start billing: %s", err.Error())
+		}
+	}
+
+	// Connect the user's calendars and reminders
+	rt.Calendar.SetProvider(a.config.Calendar)
+	rt.Reminders.SetProvider(a.config.Reminders)
+
+	// Show the menu bar
+	if a.config.Menu != nil {
+		rt.MenuBar.Set(a.config.Menu)
+	}
+
+	// Show the tray icon once the UI loop runs
+	if len(a.config.TrayIcon) > 0 {
+		if a.config.TrayOnly {
+			rt.Tray.OnClick(rt.Window.Show)
+		}
+		go func() {
+			err := rt.Tray.SetIcon(a.config.TrayIcon)
+			if err != nil {
+				a.log.Errorf("Unable to show the tray icon: %s", err.Error())
+			}
+		}()
+	}
 
 	// Start binding manager and give it our renderer
 	err = a.bindingManager.Start(a.renderer, a.runtime)
@@ -138,14 +387,18 @@ func (a *App) start() error {
 		return err
 	}
 
-	// Defer the shutdown
-	defer a.shutdown()
+	// Let other applications call the scriptable methods
+	if a.config.AutomationID != "" {
+		a.renderer.StartAutomation(a.config.AutomationID, a.bindingManager.AutomationCall)
+	}
 
-	// Run the renderer
-	err = a.renderer.Run()
-	if err != nil {
-		return err
+	// Open the windows waiting for the main window
+	a.windowsLock.Lock()
+	a.running = true
+	for _, window := range a.windows {
+		window.create()
 	}
+	a.windowsLock.Unlock()
 
 	return nil
 }
@@ -155,6 +408,13 @@ func (a *App) shutdown() {
 	// Make sure this is only called once
 	a.log.Debug("Shutting down")
 
+	// Shutdown the additional windows
+	a.windowsLock.Lock()
+	for _, window := range a.windows {
+		window.shutdown()
+	}
+	a.windowsLock.Unlock()
+
 	// Shutdown Binding Manager
 	a.bindingManager.Shutdown()
 
@@ -164,11 +424,125 @@ func (a *App) shutdown() {
 	// Shutdown Event Manager
 	a.eventManager.Shutdown()
 
+	a.reportPerformance()
+
 	a.log.Debug("Cleanly Shutdown")
 }
 
 // Bind allows the user to bind the given object
-// with the application
-func (a *App) Bind(object interface{}) {
-	a.bindingManager.Bind(object)
+// with the application. Structs can implement OnStartup(ctx) error,
+// OnDomReady(ctx) and OnShutdown(ctx) to be told about the lifecycle of
+// the app. See RuntimeFromContext.
+func (a *App) Bind(object interface{}, options ...BindOption) {
+	a.bindingManager.Bind(object, options...)
+}
+
+// RuntimeFromContext returns the runtime from the context given to the
+// lifecycle hooks of bound structs
+func RuntimeFromContext(ctx context.Context) *Runtime {
+	return wailsruntime.FromContext(ctx)
+}
+
+// Isolated is a Bind option that hosts the bound struct in a child process, so
+// a crash or runaway memory use in it cannot take down the app. Calls are
+// proxied to the child, which is restarted if it exits. The methods' parameters
+// and results must be JSON serialisable. WailsInit, WailsShutdown and the
+// lifecycle hooks are not called as the runtime is not available in the child.
+func Isolated() BindOption {
+	return func(options *interfaces.BindOptions) {
+		options.Isolated = true
+	}
+}
+
+// serveIsolated hosts the named isolated service over stdin and stdout.
+// Anything else written to stdout is redirected to stderr.
+func (a *App) serveIsolated(service string) error {
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	logger.GlobalLogger.SetOutput(os.Stderr)
+	if BuildMode == cmd.BuildModeProd {
+		a.logLevel = "error"
+	}
+	logger.SetLogLevel(a.logLevel)
+	return a.bindingManager.ServeIsolated(service, os.Stdin, out)
+}
+
+// writeAPISpec writes the description of the bound methods to stdout for
+// 'wails generate api-spec'. Anything else written to stdout is redirected
+// to stderr.
+func (a *App) writeAPISpec() error {
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	logger.GlobalLogger.SetOutput(os.Stderr)
+	logger.SetLogLevel("error")
+	return a.bindingManager.WriteAPISpec(out)
+}
+
+// runHeadless calls the bound method given by --headless-command with the
+// remaining arguments and prints the result as JSON, without starting the GUI.
+// Logs are written to stderr so the output can be used in scripts.
+func (a *App) runHeadless() error {
+	logger.GlobalLogger.SetOutput(os.Stderr)
+	if BuildMode == cmd.BuildModeProd {
+		a.logLevel = "error"
+	}
+	logger.SetLogLevel(a.logLevel)
+	result, err := a.bindingManager.CallHeadless(a.flags.HeadlessCommand, a.flags.HeadlessArgs())
+	if err != nil {
+		a.log.Error(err.Error())
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+// MainThreadMethods is a Bind option that makes the named methods of the bound
+// struct execute on the main (UI) thread, as needed for some native calls.
+// Calls are queued and run one at a time, in the order they were made.
+func MainThreadMethods(methods ...string) BindOption {
+	return func(options *interfaces.BindOptions) {
+		options.MainThreadMethods = append(options.MainThreadMethods, methods...)
+	}
+}
+
+// Constructor is a Bind option for binding the struct made by the given
+// constructor, EG: app.Bind(NewTodos, wails.Constructor()). The constructor
+// returns a struct pointer and optionally an error, and its parameters are
+// the services the struct depends on: other bound structs, *wails.CustomLogger
+// for a logger named after the struct, *runtime.StoreProvider or
+// *wails.Runtime. The constructors are called when the app starts, after
+// those of the structs they depend on. Dependency cycles stop the app from
+// starting.
+func Constructor() BindOption {
+	return func(options *interfaces.BindOptions) {
+		options.Constructor = true
+	}
+}
+
+// MaxPayloadSize is a Bind option that sets the size in bytes above which the
+// results of the bound struct's methods are streamed to the frontend in
+// chunks, overriding AppConfig.MaxPayloadSize. Use -1 to send them whole.
+func MaxPayloadSize(size int) BindOption {
+	return func(options *interfaces.BindOptions) {
+		options.MaxPayloadSize = size
+	}
+}
+
+// Scriptable is a Bind option that lets other applications call the named
+// methods of the bound struct, or all of them if none are named. Requires
+// AppConfig.AutomationID. Arguments are passed as a JSON array and results
+// are returned as JSON:
+//
+//	Linux:   gdbus call --session --dest com.example.MyApp --object-path /com/example/MyApp \
+//	           --method com.example.MyApp.Call "Counter.Add" "[1, 2]"
+//	MacOS:   tell application "MyApp" to «event WailCall» "Counter.Add" given «class Args»:"[1, 2]"
+//	Windows: GetObject(, "com.example.MyApp").Call("Counter.Add", "[1, 2]")
+//
+// List (or Call with an empty method, or «event WailList») returns the names
+// of the scriptable methods.
+func Scriptable(methods ...string) BindOption {
+	return func(options *interfaces.BindOptions) {
+		options.Scriptable = true
+		options.ScriptableMethods = append(options.ScriptableMethods, methods...)
+	}
 }
@@ -5,3 +5,8 @@ package wails
 func platformInit() {
 
 }
+
+// attachConsole does nothing as apps started from a terminal already write to it
+func attachConsole() {
+
+}
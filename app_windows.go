@@ -5,16 +5,47 @@ package wails
 import (
 	"fmt"
 	"log"
+	"os"
 	"syscall"
+
+	"github.com/wailsapp/wails/lib/logger"
 )
 
 func platformInit() {
+	// Scale windows for the display they are on, so they aren't blurry
+	// after being dragged to a display with a different scale
+	if setProcessPerMonitorDPIAware() {
+		return
+	}
 	err := SetProcessDPIAware()
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
 }
 
+// setProcessPerMonitorDPIAware makes the app per monitor DPI aware, with
+// SetProcessDpiAwarenessContext on Windows 10 or SetProcessDpiAwareness on
+// Windows 8.1. It returns false on older versions of Windows.
+// https://docs.microsoft.com/en-us/windows/win32/hidpi/high-dpi-desktop-application-development-on-windows
+func setProcessPerMonitorDPIAware() bool {
+	const perMonitorAwareV2 = ^uintptr(3) // DPI_AWARENESS_CONTEXT_PER_MONITOR_AWARE_V2
+	setContext := syscall.NewLazyDLL("user32.dll").NewProc("SetProcessDpiAwarenessContext")
+	if setContext.Find() == nil {
+		status, _, _ := setContext.Call(perMonitorAwareV2)
+		if status != 0 {
+			return true
+		}
+	}
+
+	const perMonitorAware = 2 // PROCESS_PER_MONITOR_DPI_AWARE
+	setAwareness := syscall.NewLazyDLL("shcore.dll").NewProc("SetProcessDpiAwareness")
+	if setAwareness.Find() == nil {
+		result, _, _ := setAwareness.Call(perMonitorAware)
+		return result == 0
+	}
+	return false
+}
+
 // SetProcessDPIAware via user32.dll
 // https://docs.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-setprocessdpiaware
 // Also, thanks Jack Mordaunt! https://github.com/wailsapp/wails/issues/293
@@ -25,3 +56,29 @@ func SetProcessDPIAware() error {
 	}
 	return nil
 }
+
+// attachConsole writes stdout, stderr and the log to the console of the
+// process that started the app, if any. Apps built with -H windowsgui don't
+// get a console, so their output is otherwise lost.
+// https://docs.microsoft.com/en-us/windows/console/attachconsole
+func attachConsole() {
+	// Keep the output if it has been redirected, EG: to a file
+	handle, err := syscall.GetStdHandle(syscall.STD_OUTPUT_HANDLE)
+	if err == nil && handle != 0 && handle != syscall.InvalidHandle {
+		return
+	}
+
+	const attachParentProcess = ^uintptr(0)
+	status, _, _ := syscall.NewLazyDLL("kernel32.dll").NewProc("AttachConsole").Call(attachParentProcess)
+	if status == 0 {
+		// Not started from a terminal
+		return
+	}
+	console, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	os.Stdout = console
+	os.Stderr = console
+	logger.GlobalLogger.SetOutput(console)
+}
@@ -14,6 +14,10 @@ func (app *App) setupCli() *cmd.Cli {
 	// Setup cli to handle loglevel
 	result.
 		StringFlag("loglevel", "Sets the log level [debug|info|error|panic|fatal]. Default debug", &app.logLevel).
+		BoolFlag("perf", "Prints the frontend's performance entries and the latency of calls on exit", &app.perfReport).
+		StringFlag("latency", "Delays calls to bound methods and events by the given duration, EG: 300ms", &app.latency).
+		StringFlag("jitter", "Delays calls and events by up to the given duration more, at random", &app.jitter).
+		StringFlag("failure-rate", "Fails calls and drops events at random at the given rate, EG: 0.1 or 10%", &app.failureRate).
 		Action(app.start)
 
 	// Banner
@@ -0,0 +1,22 @@
+package wails
+
+import (
+	"sync/atomic"
+
+	wailsruntime "github.com/wailsapp/wails/runtime"
+)
+
+// confirmClose closes the app if the OnBeforeClose hook allows it. Closing
+// the window again while the hook runs, EG: while it shows a dialog, is
+// ignored.
+func (a *App) confirmClose() {
+	if !atomic.CompareAndSwapInt32(&a.closing, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&a.closing, 0)
+	rt, _ := a.runtime.(*wailsruntime.Runtime)
+	if a.config.OnBeforeClose(rt) {
+		a.log.Debug("Close confirmed")
+		a.renderer.Close()
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/wailsapp/wails/lib/binding"
+	"gopkg.in/yaml.v3"
+)
+
+// GenerateAPISpec describes the bound methods of the project's app and the
+// models they use, in the given format: "json" or "yaml". The app is run
+// with binding.APISpecEnv set, so it writes the description instead of
+// opening a window.
+func GenerateAPISpec(projectDir string, projectOptions *ProjectOptions, format string) ([]byte, error) {
+	if format != "json" && format != "yaml" {
+		return nil, fmt.Errorf("unknown format '%s'. Please use json or yaml", format)
+	}
+
+	args := []string{"run"}
+	if projectOptions.Tags != "" {
+		args = append(args, "--tags", projectOptions.Tags)
+	}
+	args = append(args, ".")
+	command := exec.Command("go", args...)
+	command.Dir = projectDir
+	command.Env = append(os.Environ(), "GO111MODULE=on", binding.APISpecEnv+"=1")
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	err := command.Run()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("unable to describe the bound methods: %s", message)
+	}
+
+	if format == "json" {
+		return stdout.Bytes(), nil
+	}
+	return apiSpecToYAML(stdout.Bytes())
+}
+
+// apiSpecToYAML converts the JSON description of the bound methods to YAML
+func apiSpecToYAML(data []byte) ([]byte, error) {
+	var spec interface{}
+	err := json.Unmarshal(data, &spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid description of the bound methods: %s", err.Error())
+	}
+	return yaml.Marshal(spec)
+}
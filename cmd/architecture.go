@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// The xgo image doesn't target windows/arm64, so it is built with the
+// llvm-mingw toolchain, whose tools are prefixed with the target
+const (
+	windowsArm64Compiler = "aarch64-w64-mingw32-gcc"
+	windowsArm64Windres  = "aarch64-w64-mingw32-windres"
+	windowsArm64Help     = "Please install llvm-mingw from here and try again: https://github.com/mstorsjo/llvm-mingw/releases. You will need to add its bin directory to your path"
+)
+
+// windows/arm64 was added in Go 1.17
+const windowsArm64GoMinor = 17
+
+// TargetArchitecture returns the architecture being built for, which is the
+// current one unless cross-compiling
+func (po *ProjectOptions) TargetArchitecture() string {
+	if po.Architecture != "" {
+		return po.Architecture
+	}
+	return runtime.GOARCH
+}
+
+// IsWindowsArm64 returns true when building for windows/arm64
+func (po *ProjectOptions) IsWindowsArm64() bool {
+	return po.Platform == "windows" && po.TargetArchitecture() == "arm64"
+}
+
+// CrossCompilesLocally returns true if the target is cross-compiled with a
+// local toolchain rather than in the xgo docker image
+func (po *ProjectOptions) CrossCompilesLocally() bool {
+	return po.CrossCompile && po.IsWindowsArm64()
+}
+
+// crossCompileEnv returns the environment go build is run with to
+// cross-compile locally
+func crossCompileEnv(po *ProjectOptions) []string {
+	return []string{
+		"GOOS=" + po.Platform,
+		"GOARCH=" + po.TargetArchitecture(),
+		"CGO_ENABLED=1",
+		"CC=" + windowsArm64Compiler,
+	}
+}
+
+// IsArm64Host returns true when running on 64-bit ARM, even when this
+// binary is emulated, as x64 binaries are on Windows on ARM
+func IsArm64Host() bool {
+	if runtime.GOARCH == "arm64" {
+		return true
+	}
+	if runtime.GOOS == "windows" {
+		for _, name := range []string{"PROCESSOR_ARCHITEW6432", "PROCESSOR_ARCHITECTURE"} {
+			if strings.EqualFold(os.Getenv(name), "ARM64") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goEnv returns the value of the given variable from 'go env'
+func goEnv(name string) (string, error) {
+	goProgram := NewProgramHelper().FindProgram("go")
+	if goProgram == nil {
+		return "", fmt.Errorf("unable to find go")
+	}
+	stdout, _, _, err := goProgram.Run("env", name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// goMinorVersion returns the minor version of the installed Go, EG: 17 for
+// go1.17.3
+func goMinorVersion() (int, error) {
+	version, err := goEnv("GOVERSION")
+	if err != nil {
+		return 0, err
+	}
+	parts := strings.Split(strings.TrimPrefix(version, "go"), ".")
+	if len(parts) < 2 {
+		return 0, fmt.Errorf("unable to parse go version '%s'", version)
+	}
+	return strconv.Atoi(strings.TrimFunc(parts[1], func(r rune) bool {
+		return r < '0' || r > '9'
+	}))
+}
+
+// CheckWindowsArm64 checks that Go and the llvm-mingw toolchain can build
+// for windows/arm64
+func CheckWindowsArm64() error {
+	minor, err := goMinorVersion()
+	if err != nil {
+		return err
+	}
+	if minor < windowsArm64GoMinor {
+		return fmt.Errorf("building for windows/arm64 requires Go 1.%d or later", windowsArm64GoMinor)
+	}
+	programHelper := NewProgramHelper()
+	for _, program := range []string{windowsArm64Compiler, windowsArm64Windres} {
+		if !programHelper.IsInstalled(program) {
+			return fmt.Errorf("%s not installed. %s", program, windowsArm64Help)
+		}
+	}
+	return nil
+}
+
+// manifestArchitecture returns the processorArchitecture of a Windows
+// manifest for the given Go architecture
+func manifestArchitecture(goarch string) string {
+	switch goarch {
+	case "386":
+		return "x86"
+	case "arm", "arm64":
+		return goarch
+	default:
+		return "amd64"
+	}
+}
+
+var processorArchitecture = regexp.MustCompile(`processorArchitecture="[^"]*"`)
+
+// setManifestArchitecture sets the architecture of the app in the manifest,
+// which is the first assemblyIdentity, as Windows refuses to start an app
+// whose manifest names another architecture
+func setManifestArchitecture(manifest string, goarch string) string {
+	location := processorArchitecture.FindStringIndex(manifest)
+	if location == nil {
+		return manifest
+	}
+	attribute := fmt.Sprintf(`processorArchitecture="%s"`, manifestArchitecture(goarch))
+	return manifest[:location[0]] + attribute + manifest[location[1]:]
+}
+
+// checkWindowsArm64Host reports whether apps can be built natively on
+// Windows on ARM. An x64 Go or gcc still works, but builds x64 apps that
+// run emulated.
+func checkWindowsArm64Host(logger *Logger) bool {
+	if err := CheckWindowsArm64(); err != nil {
+		logger.Error("%s", err)
+		return false
+	}
+	logger.Green("Toolchain for windows/arm64 found")
+	if goarch, err := goEnv("GOARCH"); err == nil && goarch != "arm64" {
+		logger.Yellow("Go builds for windows/%s, so apps will run emulated. Install the arm64 version of Go to build native apps.", goarch)
+	}
+	gcc := NewProgramHelper().FindProgram("gcc")
+	if gcc != nil {
+		stdout, _, _, err := gcc.Run("-dumpmachine")
+		if err == nil && !strings.HasPrefix(strings.TrimSpace(stdout), "aarch64") {
+			logger.Yellow("gcc builds for %s. %s", strings.TrimSpace(stdout), windowsArm64Help)
+		}
+	}
+	return true
+}
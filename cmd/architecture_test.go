@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestSetManifestArchitecture(t *testing.T) {
+	manifest := `<assemblyIdentity type="win32" name="MyApplication" processorArchitecture="amd64"/>
+<dependentAssembly><assemblyIdentity name="Microsoft.Windows.Common-Controls" processorArchitecture="*"/></dependentAssembly>`
+	tests := []struct {
+		name   string
+		goarch string
+		want   string
+	}{
+		{"arm64", "arm64", `processorArchitecture="arm64"/>`},
+		{"amd64", "amd64", `processorArchitecture="amd64"/>`},
+		{"386", "386", `processorArchitecture="x86"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := setManifestArchitecture(manifest, tt.goarch)
+			want := `<assemblyIdentity type="win32" name="MyApplication" ` + tt.want + `
+<dependentAssembly><assemblyIdentity name="Microsoft.Windows.Common-Controls" processorArchitecture="*"/></dependentAssembly>`
+			if got != want {
+				t.Errorf("setManifestArchitecture() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestProjectOptions_CrossCompilesLocally(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		arch     string
+		want     bool
+	}{
+		{"windows/arm64", "windows", "arm64", true},
+		{"windows/amd64", "windows", "amd64", false},
+		{"linux/arm64", "linux", "arm64", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			po := &ProjectOptions{CrossCompile: true, Platform: tt.platform, Architecture: tt.arch}
+			if got := po.CrossCompilesLocally(); got != tt.want {
+				t.Errorf("CrossCompilesLocally() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
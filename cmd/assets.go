@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Defaults for the 'assets' section of project.json
+const (
+	defaultAssetsDir    = "assets"
+	defaultAssetsOutput = "build/assets"
+)
+
+// EnvTransform is the built in asset transform that substitutes ${NAME}
+// references in html files with the value of the environment variable NAME
+const EnvTransform = "env"
+
+// envReference matches ${NAME} references substituted by EnvTransform
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+type assets struct {
+	Dir        string   `json:"dir,omitempty"`
+	Output     string   `json:"output,omitempty"`
+	Transforms []string `json:"transforms,omitempty"`
+}
+
+// dir returns the base asset directory, relative to the project directory
+func (a *assets) dir() string {
+	if a.Dir == "" {
+		return defaultAssetsDir
+	}
+	return a.Dir
+}
+
+// output returns the directory the merged assets are written to, relative
+// to the project directory
+func (a *assets) output() string {
+	if a.Output == "" {
+		return defaultAssetsOutput
+	}
+	return a.Output
+}
+
+// PrepareAssets merges the base asset directory of the project with the
+// overlay for the target platform (eg: assets_darwin) into the output
+// directory, then runs the configured transforms over the result. Files in
+// the overlay replace those with the same path in the base directory.
+// Projects without an 'assets' section in project.json are left untouched.
+func PrepareAssets(projectDir string, projectOptions *ProjectOptions) error {
+	config := projectOptions.Assets
+	if config == nil {
+		return nil
+	}
+
+	baseDir := filepath.Join(projectDir, config.dir())
+	if !fs.DirExists(baseDir) {
+		return fmt.Errorf("asset directory '%s' does not exist", config.dir())
+	}
+	outputDir := filepath.Join(projectDir, config.output())
+	if err := os.RemoveAll(outputDir); err != nil {
+		return err
+	}
+
+	sources := []string{baseDir}
+	overlayDir := baseDir + "_" + projectOptions.Platform
+	if fs.DirExists(overlayDir) {
+		sources = append(sources, overlayDir)
+	}
+	for _, source := range sources {
+		if err := copyAssets(source, outputDir); err != nil {
+			return err
+		}
+	}
+
+	program := NewProgramHelper(projectOptions.Verbose)
+	for _, transform := range config.Transforms {
+		var err error
+		if transform == EnvTransform {
+			err = substituteEnv(outputDir)
+		} else {
+			err = program.RunCommandArray(strings.Fields(transform), outputDir)
+		}
+		if err != nil {
+			return fmt.Errorf("asset transform '%s' failed: %s", transform, err)
+		}
+	}
+	return nil
+}
+
+// copyAssets copies the files under source to the same paths under target
+func copyAssets(source, target string) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		destination := filepath.Join(target, relative)
+		if info.IsDir() {
+			return fs.MkDirs(destination, 0755)
+		}
+		return fs.CopyFile(path, destination)
+	})
+}
+
+// substituteEnv replaces ${NAME} references in the html files under dir with
+// the value of the environment variable NAME. References to variables that
+// aren't set are left as they are.
+func substituteEnv(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.EqualFold(filepath.Ext(path), ".html") {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		result := envReference.ReplaceAllFunc(data, func(reference []byte) []byte {
+			name := envReference.FindSubmatch(reference)[1]
+			if value, ok := os.LookupEnv(string(name)); ok {
+				return []byte(value)
+			}
+			return reference
+		})
+		return ioutil.WriteFile(path, result, info.Mode())
+	})
+}
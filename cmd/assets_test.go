@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeAsset(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readAsset(t *testing.T, path string) string {
+	t.Helper()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestPrepareAssets(t *testing.T) {
+	projectDir := t.TempDir()
+	writeAsset(t, filepath.Join(projectDir, "assets", "index.html"), "<title>${WAILS_TEST_TITLE} ${WAILS_TEST_UNSET}</title>")
+	writeAsset(t, filepath.Join(projectDir, "assets", "css", "app.css"), "body {}")
+	writeAsset(t, filepath.Join(projectDir, "assets_darwin", "css", "app.css"), "body { font: -apple-system; }")
+	writeAsset(t, filepath.Join(projectDir, "assets_windows", "css", "app.css"), "body { font: Segoe UI; }")
+	writeAsset(t, filepath.Join(projectDir, "build", "assets", "stale.js"), "")
+	os.Setenv("WAILS_TEST_TITLE", "Hello")
+	defer os.Unsetenv("WAILS_TEST_TITLE")
+
+	projectOptions := &ProjectOptions{
+		Platform: "darwin",
+		Assets:   &assets{Transforms: []string{EnvTransform}},
+	}
+	if err := PrepareAssets(projectDir, projectOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(projectDir, "build", "assets")
+	if got := readAsset(t, filepath.Join(output, "css", "app.css")); got != "body { font: -apple-system; }" {
+		t.Errorf("expected the darwin overlay, got %q", got)
+	}
+	if got := readAsset(t, filepath.Join(output, "index.html")); got != "<title>Hello ${WAILS_TEST_UNSET}</title>" {
+		t.Errorf("unexpected index.html: %q", got)
+	}
+	if fs.FileExists(filepath.Join(output, "stale.js")) {
+		t.Errorf("expected stale assets to be removed")
+	}
+
+	projectOptions.Platform = "linux"
+	if err := PrepareAssets(projectDir, projectOptions); err != nil {
+		t.Fatal(err)
+	}
+	if got := readAsset(t, filepath.Join(output, "css", "app.css")); got != "body {}" {
+		t.Errorf("expected the base asset without an overlay, got %q", got)
+	}
+}
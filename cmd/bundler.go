@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"text/template"
+
+	wailsruntime "github.com/wailsapp/wails/runtime"
+)
+
+// BridgePort is the port apps built in bridge mode listen on for the frontend
+const BridgePort = 34115
+
+// BindingsFile is where apps served in bridge mode write the Typescript
+// definitions of their bound methods, relative to the project directory
+const BindingsFile = ".wails/bindings.d.ts"
+
+// bundlerPluginDir is where the bundler plugins are written, relative to the
+// frontend directory
+const bundlerPluginDir = "wails"
+
+// bundlerPluginHelpers are shared by the bundler plugins. They copy the
+// bindings written by the app to the frontend whenever they change.
+const bundlerPluginHelpers = `// Generated by 'wails generate'. Any changes will be overwritten.
+const fs = require('fs');
+const path = require('path');
+
+// The port the app listens on when run with 'wails serve'
+const bridgePort = {{.BridgePort}};
+
+// The runtime used when the frontend is served by the bundler
+const bridgeRuntime = path.join(__dirname, 'bridge.js');
+
+// The Typescript definitions written by the app when run with 'wails serve'
+const bindingsFile = path.join(__dirname, {{printf "%q" .BindingsFile}});
+
+function copyBindings(target) {
+	if (!fs.existsSync(bindingsFile)) {
+		return;
+	}
+	fs.mkdirSync(path.dirname(target), { recursive: true });
+	fs.copyFileSync(bindingsFile, target);
+}
+
+function watchBindings(target) {
+	copyBindings(target);
+	fs.watchFile(bindingsFile, { interval: 500 }, function () {
+		copyBindings(target);
+	});
+}
+
+// Returns true if the request is the runtime's init module, which is replaced
+// with the bridge runtime in development
+function isRuntimeInit(request, context) {
+	return /^\.\/init(\.js)?$/.test(request) &&
+		context.split(path.sep).join('/').indexOf('@wailsapp/runtime') !== -1;
+}
+`
+
+var vitePluginTemplate = template.Must(template.New("vite").Parse(bundlerPluginHelpers + `
+/**
+ * Vite plugin for Wails apps. When serving, it proxies /bridge to the app,
+ * uses the bridge runtime and copies the app's bindings to the given file.
+ *
+ * @param {{"{"}}{bindings: string}{{"}"}} options
+ */
+module.exports = function wails(options) {
+	options = options || {};
+	const bindingsTarget = path.resolve(options.bindings || 'src/wails/backend.d.ts');
+	return {
+		name: 'wails',
+		apply: 'serve',
+		config: function () {
+			return {
+				define: { __WAILS_BRIDGE_PORT__: bridgePort },
+				optimizeDeps: { exclude: ['@wailsapp/runtime'] },
+				server: {
+					proxy: {
+						'/bridge': { target: 'ws://localhost:' + bridgePort, ws: true }
+					}
+				}
+			};
+		},
+		resolveId: function (source, importer) {
+			if (importer && isRuntimeInit(source, path.dirname(importer))) {
+				return bridgeRuntime;
+			}
+			return null;
+		},
+		configureServer: function () {
+			watchBindings(bindingsTarget);
+		}
+	};
+};
+`))
+
+var webpackPluginTemplate = template.Must(template.New("webpack").Parse(bundlerPluginHelpers + `
+/**
+ * Webpack plugin for Wails apps. In development mode, it proxies /bridge to
+ * the app, uses the bridge runtime and copies the app's bindings to the
+ * given file.
+ */
+class WailsPlugin {
+	/**
+	 * @param {{"{"}}{bindings: string}{{"}"}} options
+	 */
+	constructor(options) {
+		options = options || {};
+		this.bindingsTarget = path.resolve(options.bindings || 'src/wails/backend.d.ts');
+	}
+
+	apply(compiler) {
+		if (compiler.options.mode !== 'development') {
+			return;
+		}
+		const webpack = compiler.webpack || require('webpack');
+		new webpack.NormalModuleReplacementPlugin(/^\.\/init(\.js)?$/, function (resource) {
+			if (isRuntimeInit(resource.request, resource.context)) {
+				resource.request = bridgeRuntime;
+			}
+		}).apply(compiler);
+		new webpack.DefinePlugin({ __WAILS_BRIDGE_PORT__: bridgePort }).apply(compiler);
+
+		const devServer = compiler.options.devServer || (compiler.options.devServer = {});
+		devServer.proxy = Object.assign({
+			'/bridge': { target: 'ws://localhost:' + bridgePort, ws: true }
+		}, devServer.proxy);
+
+		let watching = false;
+		compiler.hooks.watchRun.tap('WailsPlugin', () => {
+			if (!watching) {
+				watching = true;
+				watchBindings(this.bindingsTarget);
+			}
+		});
+	}
+}
+
+module.exports = WailsPlugin;
+`))
+
+// GenerateBundlerPlugins writes the Vite and webpack plugins for the project's
+// frontend, with the bridge runtime they use in development. The plugins are
+// written to the "wails" directory of the frontend.
+func GenerateBundlerPlugins(projectDir string, projectOptions *ProjectOptions) error {
+	if projectOptions.FrontEnd == nil {
+		return fmt.Errorf("No frontend specified in project options")
+	}
+	pluginDir := filepath.Join(projectDir, projectOptions.FrontEnd.Dir, bundlerPluginDir)
+
+	// The plugins find the bindings relative to themselves
+	bindingsFile, err := filepath.Rel(pluginDir, filepath.Join(projectDir, BindingsFile))
+	if err != nil {
+		return err
+	}
+	data := struct {
+		BridgePort   int
+		BindingsFile string
+	}{BridgePort, filepath.ToSlash(bindingsFile)}
+
+	err = fs.CreateFile(filepath.Join(pluginDir, "bridge.js"), wailsruntime.BridgeJS)
+	if err != nil {
+		return err
+	}
+	plugins := map[string]*template.Template{
+		"vite.js":    vitePluginTemplate,
+		"webpack.js": webpackPluginTemplate,
+	}
+	for filename, tmpl := range plugins {
+		var buffer bytes.Buffer
+		err = tmpl.Execute(&buffer, data)
+		if err != nil {
+			return err
+		}
+		err = fs.CreateFile(filepath.Join(pluginDir, filename), buffer.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateBundlerPlugins(t *testing.T) {
+	projectDir := t.TempDir()
+	projectOptions := &ProjectOptions{FrontEnd: &frontend{Dir: "frontend"}}
+	err := GenerateBundlerPlugins(projectDir, projectOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range []string{"vite.js", "webpack.js"} {
+		data, err := ioutil.ReadFile(filepath.Join(projectDir, "frontend", "wails", filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		plugin := string(data)
+		for _, want := range []string{"const bridgePort = 34115;", `path.join(__dirname, "../../.wails/bindings.d.ts")`, "module.exports"} {
+			if !strings.Contains(plugin, want) {
+				t.Errorf("%s does not contain %s", filename, want)
+			}
+		}
+	}
+	if !isFile(filepath.Join(projectDir, "frontend", "wails", "bridge.js")) {
+		t.Error("bridge.js was not written")
+	}
+}
@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/png" // Decodes appicon.png
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CheckProblem is a problem with a project found by CheckProject
+type CheckProblem struct {
+	// What was checked, EG: "project.json"
+	Check string
+
+	// What is wrong and how to fix it
+	Message string
+}
+
+func (p *CheckProblem) String() string {
+	return p.Check + ": " + p.Message
+}
+
+// projectChecker collects the problems found in a project
+type projectChecker struct {
+	dir      string
+	options  *ProjectOptions
+	problems []*CheckProblem
+}
+
+func (c *projectChecker) problem(check string, format string, args ...interface{}) {
+	c.problems = append(c.problems, &CheckProblem{Check: check, Message: fmt.Sprintf(format, args...)})
+}
+
+// CheckProject validates the project in the given directory without
+// building it: the project config, icon, entitlements, file associations,
+// frontend build outputs and Go code that binds to the frontend.
+// The problems found are returned.
+func CheckProject(projectDir string) []*CheckProblem {
+	c := &projectChecker{dir: projectDir}
+	c.checkConfig()
+	c.checkIcon()
+	c.checkEntitlements()
+	c.checkFileAssociations()
+	c.checkEmbeddedFiles()
+	c.checkGoCode()
+	return c.problems
+}
+
+// checkConfig checks project.json
+func (c *projectChecker) checkConfig() {
+	const check = "project.json"
+	data, err := ioutil.ReadFile(filepath.Join(c.dir, "project.json"))
+	if err != nil {
+		c.problem(check, "unable to read the project config. Please check you are in a Wails project directory")
+		return
+	}
+
+	// Unknown fields are most likely typos
+	options := &ProjectOptions{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(options)
+	if err != nil && strings.Contains(err.Error(), "unknown field") {
+		c.problem(check, "%s. Please check the spelling", err.Error())
+		options = &ProjectOptions{}
+		err = json.Unmarshal(data, options)
+	}
+	if err != nil {
+		c.problem(check, "invalid JSON: %s", err.Error())
+		return
+	}
+	c.options = options
+
+	if options.Name == "" {
+		c.problem(check, "'name' is not set")
+	}
+	if options.BinaryName == "" {
+		c.problem(check, "'binaryname' is not set")
+	} else if strings.ContainsAny(options.BinaryName, `/\: `) {
+		c.problem(check, "'binaryname' (%s) must not contain spaces, colons or path separators", options.BinaryName)
+	}
+
+	if options.FrontEnd == nil {
+		return
+	}
+	if options.FrontEnd.Dir == "" {
+		c.problem(check, "'frontend.dir' is not set")
+	} else if !isDir(filepath.Join(c.dir, options.FrontEnd.Dir)) {
+		c.problem(check, "the frontend directory '%s' does not exist", options.FrontEnd.Dir)
+	} else if options.FrontEnd.Install != "" && !isFile(filepath.Join(c.dir, options.FrontEnd.Dir, "package.json")) {
+		c.problem(check, "'%s' has no package.json for the install command '%s'", options.FrontEnd.Dir, options.FrontEnd.Install)
+	}
+	if options.FrontEnd.Build == "" {
+		c.problem(check, "'frontend.build' is not set")
+	}
+}
+
+// checkIcon checks appicon.png, if there is one. The default icon is used otherwise.
+func (c *projectChecker) checkIcon() {
+	const check = "appicon.png"
+	file, err := os.Open(filepath.Join(c.dir, "appicon.png"))
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	config, format, err := image.DecodeConfig(file)
+	if err != nil || format != "png" {
+		c.problem(check, "not a valid PNG image. Please save the icon as a PNG")
+		return
+	}
+	if config.Width != config.Height {
+		c.problem(check, "the icon is %dx%d but must be square", config.Width, config.Height)
+	}
+	if config.Width < 256 {
+		c.problem(check, "the icon is %dx%d but should be at least 256x256", config.Width, config.Height)
+	}
+}
+
+// checkEntitlements checks the MacOS entitlements files are valid plists
+func (c *projectChecker) checkEntitlements() {
+	files, _ := filepath.Glob(filepath.Join(c.dir, "*.entitlements"))
+	for _, filename := range files {
+		check := filepath.Base(filename)
+		entitlements, err := readPlist(filename)
+		if err != nil {
+			c.problem(check, "invalid plist: %s", err.Error())
+			continue
+		}
+		if _, ok := entitlements.(map[string]interface{}); !ok {
+			c.problem(check, "the entitlements must be a <dict>")
+		}
+	}
+}
+
+// checkFileAssociations checks the document types declared in a custom info.plist
+func (c *projectChecker) checkFileAssociations() {
+	const check = "info.plist"
+	filename := filepath.Join(c.dir, "info.plist")
+	if !isFile(filename) {
+		return
+	}
+	plist, err := readPlist(filename)
+	if err != nil {
+		c.problem(check, "invalid plist: %s", err.Error())
+		return
+	}
+	info, ok := plist.(map[string]interface{})
+	if !ok {
+		c.problem(check, "the plist must be a <dict>")
+		return
+	}
+	checkShareServices(c, info)
+	if info["CFBundleDocumentTypes"] == nil {
+		return
+	}
+	documentTypes, ok := info["CFBundleDocumentTypes"].([]interface{})
+	if !ok {
+		c.problem(check, "CFBundleDocumentTypes must be an <array>")
+		return
+	}
+	for index, entry := range documentTypes {
+		documentType, ok := entry.(map[string]interface{})
+		if !ok {
+			c.problem(check, "CFBundleDocumentTypes item %d must be a <dict>", index)
+			continue
+		}
+		name, _ := documentType["CFBundleTypeName"].(string)
+		if name == "" {
+			c.problem(check, "CFBundleDocumentTypes item %d has no CFBundleTypeName", index)
+			name = fmt.Sprintf("item %d", index)
+		}
+		if documentType["CFBundleTypeExtensions"] == nil && documentType["LSItemContentTypes"] == nil {
+			c.problem(check, "document type '%s' needs CFBundleTypeExtensions or LSItemContentTypes to associate any files", name)
+		}
+		if role, ok := documentType["CFBundleTypeRole"].(string); ok && role != "Editor" && role != "Viewer" && role != "Shell" && role != "None" {
+			c.problem(check, "document type '%s' has an invalid CFBundleTypeRole '%s'. Use Editor, Viewer, Shell or None", name, role)
+		}
+	}
+}
+
+// checkShareServices checks the services that receive what other apps share
+// with the app, which are sent to runtime.Share if their NSMessage is "share"
+func checkShareServices(c *projectChecker, info map[string]interface{}) {
+	const check = "info.plist"
+	if info["NSServices"] == nil {
+		return
+	}
+	services, ok := info["NSServices"].([]interface{})
+	if !ok {
+		c.problem(check, "NSServices must be an <array>")
+		return
+	}
+	for index, entry := range services {
+		service, ok := entry.(map[string]interface{})
+		if !ok {
+			c.problem(check, "NSServices item %d must be a <dict>", index)
+			continue
+		}
+		if message, _ := service["NSMessage"].(string); message != "share" {
+			c.problem(check, "NSServices item %d has NSMessage '%s'. Use 'share' to receive it with runtime.Share", index, message)
+		}
+		if service["NSPortName"] == nil {
+			c.problem(check, "NSServices item %d has no NSPortName. Use the app's CFBundleName", index)
+		}
+		if service["NSSendTypes"] == nil && service["NSSendFileTypes"] == nil {
+			c.problem(check, "NSServices item %d needs NSSendTypes or NSSendFileTypes to receive anything", index)
+		}
+	}
+}
+
+var embedDirective = regexp.MustCompile(`(?m)^//go:embed\s+(.+)$`)
+
+// checkEmbeddedFiles checks the files embedded with //go:embed exist, which
+// are normally the frontend build outputs
+func (c *projectChecker) checkEmbeddedFiles() {
+	files, _ := filepath.Glob(filepath.Join(c.dir, "*.go"))
+	for _, filename := range files {
+		source, err := ioutil.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+		for _, match := range embedDirective.FindAllStringSubmatch(string(source), -1) {
+			for _, pattern := range strings.Fields(match[1]) {
+				pattern = strings.Trim(pattern, "\"`")
+				matches, _ := filepath.Glob(filepath.Join(c.dir, filepath.FromSlash(pattern)))
+				if len(matches) > 0 {
+					continue
+				}
+				message := fmt.Sprintf("'%s' is embedded but does not exist.", pattern)
+				if c.options != nil && c.options.FrontEnd != nil && c.options.FrontEnd.Build != "" {
+					message += fmt.Sprintf(" Please build the frontend by running '%s' in '%s'", c.options.FrontEnd.Build, c.options.FrontEnd.Dir)
+				}
+				c.problem(filepath.Base(filename), "%s", message)
+			}
+		}
+	}
+}
+
+// checkGoCode checks the Go code compiles, so the bound methods can be
+// generated for the frontend
+func (c *projectChecker) checkGoCode() {
+	const check = "go vet"
+	_, stderr, err := NewShellHelper().RunInDirectory(c.dir, "go", "vet", ".")
+	if err != nil {
+		message := strings.TrimSpace(stderr)
+		if message == "" {
+			message = err.Error()
+		}
+		c.problem(check, "%s", message)
+	}
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// readPlist reads the given XML property list. Dicts are returned as
+// map[string]interface{}, arrays as []interface{} and everything else as
+// strings, except booleans.
+func readPlist(filename string) (interface{}, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no <plist> element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			if start.Name.Local != "plist" {
+				return nil, fmt.Errorf("expected <plist> but found <%s>", start.Name.Local)
+			}
+			value, _, err := readPlistValue(decoder)
+			return value, err
+		}
+	}
+}
+
+// readPlistValue reads the next value. The end element is returned when
+// there are no more values in the enclosing element.
+func readPlistValue(decoder *xml.Decoder) (interface{}, *xml.EndElement, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch token := token.(type) {
+		case xml.EndElement:
+			return nil, &token, nil
+		case xml.StartElement:
+			switch token.Name.Local {
+			case "dict":
+				result := make(map[string]interface{})
+				for {
+					key, end, err := readPlistValue(decoder)
+					if err != nil {
+						return nil, nil, err
+					}
+					if end != nil {
+						return result, nil, nil
+					}
+					name, ok := key.(plistKey)
+					if !ok {
+						return nil, nil, fmt.Errorf("expected <key> in <dict>")
+					}
+					value, end, err := readPlistValue(decoder)
+					if err != nil {
+						return nil, nil, err
+					}
+					if end != nil {
+						return nil, nil, fmt.Errorf("no value for key '%s'", name)
+					}
+					result[string(name)] = value
+				}
+			case "array":
+				result := []interface{}{}
+				for {
+					value, end, err := readPlistValue(decoder)
+					if err != nil {
+						return nil, nil, err
+					}
+					if end != nil {
+						return result, nil, nil
+					}
+					result = append(result, value)
+				}
+			case "true", "false":
+				err = decoder.Skip()
+				return token.Name.Local == "true", nil, err
+			case "key":
+				var key string
+				err = decoder.DecodeElement(&key, &token)
+				return plistKey(key), nil, err
+			case "string", "integer", "real", "date", "data":
+				var value string
+				err = decoder.DecodeElement(&value, &token)
+				return value, nil, err
+			default:
+				return nil, nil, fmt.Errorf("unknown element <%s>", token.Name.Local)
+			}
+		}
+	}
+}
+
+// plistKey distinguishes keys from string values while reading a dict
+type plistKey string
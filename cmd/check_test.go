@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckProject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wailscheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"project.json": `{"name":"test","binaryname":"test","frontend":{"dir":"frontend","build":"npm run build"},"nmae":"typo"}`,
+		"main.go":      "package main\n\n//go:embed frontend/dist/app.js\nvar js string\n\nfunc main() {}\n",
+		"info.plist": `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>CFBundleDocumentTypes</key>
+	<array>
+		<dict>
+			<key>CFBundleTypeName</key>
+			<string>Text</string>
+			<key>CFBundleTypeRole</key>
+			<string>Owner</string>
+		</dict>
+	</array>
+	<key>NSServices</key>
+	<array>
+		<dict>
+			<key>NSMessage</key>
+			<string>open</string>
+			<key>NSSendTypes</key>
+			<array><string>NSStringPboardType</string></array>
+		</dict>
+	</array>
+</dict>
+</plist>`,
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = os.MkdirAll(filepath.Join(dir, "frontend"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &projectChecker{dir: dir}
+	c.checkConfig()
+	c.checkFileAssociations()
+	c.checkEmbeddedFiles()
+
+	want := []string{
+		`project.json: json: unknown field "nmae"`,
+		"info.plist: NSServices item 0 has NSMessage 'open'",
+		"info.plist: NSServices item 0 has no NSPortName",
+		"info.plist: document type 'Text' needs CFBundleTypeExtensions or LSItemContentTypes",
+		"info.plist: document type 'Text' has an invalid CFBundleTypeRole 'Owner'",
+		"main.go: 'frontend/dist/app.js' is embedded but does not exist. Please build the frontend by running 'npm run build' in 'frontend'",
+	}
+	if len(c.problems) != len(want) {
+		t.Fatalf("got %d problems, want %d: %v", len(c.problems), len(want), c.problems)
+	}
+	for index, problem := range c.problems {
+		if !strings.HasPrefix(problem.String(), want[index]) {
+			t.Errorf("problem %d = %q, want prefix %q", index, problem.String(), want[index])
+		}
+	}
+}
@@ -34,16 +34,23 @@ func (c *Cli) PrintHelp() {
 }
 
 // Run - Runs the application with the given arguments
+// If none given, os.Args is used
 func (c *Cli) Run(args ...string) error {
+	if args == nil {
+		args = os.Args[1:]
+	}
+	return c.RunArgs(args)
+}
+
+// RunArgs - Runs the application with exactly the given arguments,
+// which may be empty
+func (c *Cli) RunArgs(args []string) error {
 	if c.preRunCommand != nil {
 		err := c.preRunCommand(c)
 		if err != nil {
 			return err
 		}
 	}
-	if len(args) == 0 {
-		args = os.Args[1:]
-	}
 	return c.rootCommand.Run(args)
 }
 
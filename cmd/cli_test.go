@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCliRunArgs(t *testing.T) {
+	// Arguments the cli doesn't know, which must not be parsed
+	args := os.Args
+	os.Args = []string{"app", "--minimized", "--profile", "work"}
+	defer func() { os.Args = args }()
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantLevel string
+		wantErr   bool
+	}{
+		{"nil", nil, "debug", false},
+		{"empty", []string{}, "debug", false},
+		{"flags", []string{"-loglevel", "info"}, "info", false},
+		{"unknown flag", []string{"--minimized"}, "debug", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, ran := "debug", false
+			cli := NewCli("App", "Test")
+			cli.StringFlag("loglevel", "Sets the log level", &level).
+				Action(func() error {
+					ran = true
+					return nil
+				})
+
+			err := cli.RunArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RunArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ran == tt.wantErr {
+				t.Errorf("RunArgs() ran the action = %v, want %v", ran, !tt.wantErr)
+			}
+			if level != tt.wantLevel {
+				t.Errorf("loglevel = %q, want %q", level, tt.wantLevel)
+			}
+		})
+	}
+}
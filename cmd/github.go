@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 )
 
 // GitHubHelper is a utility class for interacting with GitHub
@@ -106,3 +111,120 @@ func (g *GitHubHelper) IsValidTag(tagVersion string) (bool, error) {
 	}
 	return false, nil
 }
+
+// GitHubRelease holds the details of a release created on GitHub
+type GitHubRelease struct {
+	ID        int64  `json:"id"`
+	TagName   string `json:"tag_name"`
+	HTMLURL   string `json:"html_url"`
+	UploadURL string `json:"upload_url"`
+}
+
+// GitHubAsset holds the details of an asset uploaded to a GitHub release
+type GitHubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// ParseGitHubRepository extracts the "owner/repo" slug from the given
+// repository string. Both slugs and git remote URLs are accepted.
+func ParseGitHubRepository(repository string) (string, error) {
+	slug := strings.TrimSpace(repository)
+	slug = strings.TrimSuffix(slug, ".git")
+	for _, prefix := range []string{"git@github.com:", "https://github.com/", "http://github.com/", "ssh://git@github.com/"} {
+		slug = strings.TrimPrefix(slug, prefix)
+	}
+	parts := strings.Split(slug, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("unable to determine GitHub repository from '%s'", repository)
+	}
+	return slug, nil
+}
+
+// githubToken returns the token used to authenticate with the GitHub API
+func githubToken() (string, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return "", fmt.Errorf("GITHUB_TOKEN not set. Please set it to a token with 'repo' scope")
+	}
+	return token, nil
+}
+
+// doRequest performs an authenticated request against the GitHub API and
+// decodes the JSON response into result
+func (g *GitHubHelper) doRequest(method, requestURL, contentType string, body []byte, result interface{}) error {
+	token, err := githubToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("GitHub API returned %s: %s", resp.Status, string(data))
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// CreateRelease creates a new release for the given tag on the given repository
+func (g *GitHubHelper) CreateRelease(repository, tag, name, notes string, draft, prerelease bool) (*GitHubRelease, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"tag_name":   tag,
+		"name":       name,
+		"body":       notes,
+		"draft":      draft,
+		"prerelease": prerelease,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GitHubRelease{}
+	requestURL := fmt.Sprintf("https://api.github.com/repos/%s/releases", repository)
+	err = g.doRequest("POST", requestURL, "application/json", payload, result)
+	return result, err
+}
+
+// DeleteRelease deletes the given release from the given repository. Its tag
+// is kept.
+func (g *GitHubHelper) DeleteRelease(repository string, release *GitHubRelease) error {
+	requestURL := fmt.Sprintf("https://api.github.com/repos/%s/releases/%d", repository, release.ID)
+	return g.doRequest("DELETE", requestURL, "application/json", nil, nil)
+}
+
+// UploadReleaseAsset uploads the given file to the given release
+func (g *GitHubHelper) UploadReleaseAsset(release *GitHubRelease, filename string) (*GitHubAsset, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// The upload URL is a URI template: strip the {?name,label} suffix
+	uploadURL := strings.Split(release.UploadURL, "{")[0]
+	uploadURL += "?name=" + url.QueryEscape(filepath.Base(filename))
+
+	result := &GitHubAsset{}
+	err = g.doRequest("POST", uploadURL, "application/octet-stream", data, result)
+	return result, err
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestParseGitHubRepository(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		want       string
+		wantErr    bool
+	}{
+		{"slug", "wailsapp/wails", "wailsapp/wails", false},
+		{"https", "https://github.com/wailsapp/wails.git", "wailsapp/wails", false},
+		{"ssh", "git@github.com:wailsapp/wails.git", "wailsapp/wails", false},
+		{"ssh url", "ssh://git@github.com/wailsapp/wails", "wailsapp/wails", false},
+		{"missing repo", "wailsapp", "", true},
+		{"empty", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGitHubRepository(tt.repository)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseGitHubRepository() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseGitHubRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
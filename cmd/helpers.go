@@ -211,6 +211,12 @@ func BuildNative(binaryName string, forceRebuild bool, buildMode string, project
 				binaryName = strings.TrimSuffix(binaryName, ".exe")
 			}
 		}
+		// Cross-compiled binaries are named like xgo's, <name>-<platform>-<arch>
+		if projectOptions.CrossCompile {
+			ext := filepath.Ext(binaryName)
+			binaryName = fmt.Sprintf("%s-%s-%s%s", strings.TrimSuffix(binaryName, ext),
+				projectOptions.Platform, projectOptions.Architecture, ext)
+		}
 		buildCommand.Add("-o", filepath.Join("build", binaryName))
 	}
 
@@ -229,7 +235,11 @@ func BuildNative(binaryName string, forceRebuild bool, buildMode string, project
 		fmt.Printf("Command: %v\n", buildCommand.AsSlice())
 	}
 
-	err := NewProgramHelper(projectOptions.Verbose).RunCommandArray(buildCommand.AsSlice())
+	program := NewProgramHelper(projectOptions.Verbose)
+	if projectOptions.CrossCompile {
+		program.SetEnv(crossCompileEnv(projectOptions)...)
+	}
+	err := program.RunCommandArray(buildCommand.AsSlice())
 	if err != nil {
 		if packSpinner != nil {
 			packSpinner.Error()
@@ -246,13 +256,23 @@ func BuildNative(binaryName string, forceRebuild bool, buildMode string, project
 // BuildApplication will attempt to build the project based on the given inputs
 func BuildApplication(binaryName string, forceRebuild bool, buildMode string, packageApp bool, projectOptions *ProjectOptions) error {
 	var err error
+	started := time.Now()
 
-	if projectOptions.CrossCompile {
+	if projectOptions.IsWindowsArm64() {
+		if err := CheckWindowsArm64(); err != nil {
+			return err
+		}
+	} else if projectOptions.CrossCompile {
 		if err := InitializeCrossCompilation(projectOptions.Verbose); err != nil {
 			return err
 		}
 	}
 
+	// Merge the platform's asset overlay before the assets are embedded
+	if err := PrepareAssets(fs.Cwd(), projectOptions); err != nil {
+		return err
+	}
+
 	helper := NewPackageHelper(projectOptions.Platform)
 
 	// Generate windows resources
@@ -262,7 +282,7 @@ func BuildApplication(binaryName string, forceRebuild bool, buildMode string, pa
 		}
 	}
 
-	if projectOptions.CrossCompile {
+	if projectOptions.CrossCompile && !projectOptions.CrossCompilesLocally() {
 		err = BuildDocker(binaryName, buildMode, projectOptions)
 	} else {
 		err = BuildNative(binaryName, forceRebuild, buildMode, projectOptions)
@@ -271,6 +291,24 @@ func BuildApplication(binaryName string, forceRebuild bool, buildMode string, pa
 		return err
 	}
 
+	// Emit checksum and provenance for distributable builds
+	if buildMode != BuildModeBridge {
+		artifact, err := BuildArtifactPath(projectOptions)
+		if err != nil {
+			return err
+		}
+		_, err = WriteChecksumFile(artifact)
+		if err != nil {
+			return err
+		}
+		if projectOptions.Provenance {
+			_, err = WriteProvenance(artifact, buildMode, started, projectOptions)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if packageApp {
 		err = PackageApplication(projectOptions)
 		if err != nil {
@@ -281,6 +319,33 @@ func BuildApplication(binaryName string, forceRebuild bool, buildMode string, pa
 	return nil
 }
 
+// BuildArtifactPath returns the path of the binary produced by the last build
+func BuildArtifactPath(projectOptions *ProjectOptions) (string, error) {
+	buildDirectory := filepath.Join(fs.Cwd(), "build")
+	if projectOptions.CrossCompile {
+		// xgo names its output <name>-<platform>-<arch>
+		files, err := filepath.Glob(filepath.Join(buildDirectory, "*"+projectOptions.Platform+"-*"+projectOptions.Architecture+"*"))
+		if err != nil {
+			return "", err
+		}
+		for _, file := range files {
+			if fs.FileExists(file) && !strings.HasSuffix(file, ".sha256") && !strings.HasSuffix(file, ".intoto.json") {
+				return file, nil
+			}
+		}
+		return "", fmt.Errorf("unable to find build output for %s/%s", projectOptions.Platform, projectOptions.Architecture)
+	}
+	binaryName := projectOptions.BinaryName
+	if projectOptions.Platform == "windows" && !strings.HasSuffix(binaryName, ".exe") {
+		binaryName += ".exe"
+	}
+	source := filepath.Join(buildDirectory, binaryName)
+	if !fs.FileExists(source) {
+		return "", fmt.Errorf("target '%s' not available. Has it been compiled yet?", source)
+	}
+	return source, nil
+}
+
 // PackageApplication will attempt to package the application in a platform dependent way
 func PackageApplication(projectOptions *ProjectOptions) error {
 	var packageSpinner *spinner.Spinner
@@ -447,16 +512,12 @@ func InstallFrontendDeps(projectDir string, projectOptions *ProjectOptions, forc
 
 // InstallBridge installs the relevant bridge javascript library
 func InstallBridge(projectDir string, projectOptions *ProjectOptions) error {
-	bridgeFileTarget := filepath.Join(projectDir, projectOptions.FrontEnd.Dir, "node_modules", "@wailsapp", "runtime", "init.js")
-	err := fs.CreateFile(bridgeFileTarget, wailsruntime.BridgeJS)
-	return err
+	return installRuntime(filepath.Join(projectDir, projectOptions.FrontEnd.Dir), wailsruntime.BridgeJS)
 }
 
 // InstallProdRuntime installs the production runtime
 func InstallProdRuntime(projectDir string, projectOptions *ProjectOptions) error {
-	bridgeFileTarget := filepath.Join(projectDir, projectOptions.FrontEnd.Dir, "node_modules", "@wailsapp", "runtime", "init.js")
-	err := fs.CreateFile(bridgeFileTarget, wailsruntime.InitJS)
-	return err
+	return installRuntime(filepath.Join(projectDir, projectOptions.FrontEnd.Dir), wailsruntime.InitJS)
 }
 
 // ServeProject attempts to serve up the current project so that it may be connected to
@@ -469,6 +530,15 @@ func ServeProject(projectOptions *ProjectOptions, logger *Logger) error {
 		}
 		logger.Green(">>>>> To connect, you will need to run '" + projectOptions.FrontEnd.Serve + "' in the '" + projectOptions.FrontEnd.Dir + "' directory <<<<<")
 	}()
+
+	// Pick up changes to project.json while serving
+	watcher := NewConfigWatcher(NewFSHelper().Cwd(), projectOptions, logger, func(setting string) {
+		if setting == "frontend.serve" {
+			logger.Green(">>>>> To connect, you will need to run '" + projectOptions.FrontEnd.Serve + "' in the '" + projectOptions.FrontEnd.Dir + "' directory <<<<<")
+		}
+	})
+	watcher.Start()
+	defer watcher.Stop()
 	location, err := filepath.Abs(filepath.Join("build", projectOptions.BinaryName))
 	if err != nil {
 		return err
@@ -509,7 +579,7 @@ func ldFlags(po *ProjectOptions, buildMode string) string {
 	}
 
 	// Add windows flags
-	if po.Platform == "windows" && buildMode == BuildModeProd {
+	if po.Platform == "windows" && buildMode == BuildModeProd && !po.WindowsConsole {
 		ldflags += "-H windowsgui "
 	}
 
@@ -519,6 +589,14 @@ func ldFlags(po *ProjectOptions, buildMode string) string {
 
 	ldflags += "-X github.com/wailsapp/wails.BuildMode=" + buildMode
 
+	// Record the digests of the embedded assets so the app can verify them at startup
+	if buildMode == BuildModeProd {
+		digests, err := AssetDigests(fs.Cwd())
+		if err == nil && digests != "" {
+			ldflags += " -X github.com/wailsapp/wails/lib/integrity.assetDigests=" + digests
+		}
+	}
+
 	// Add additional ldflags passed in via the `ldflags` cli flag
 	if len(po.LdFlags) > 0 {
 		ldflags += " " + po.LdFlags
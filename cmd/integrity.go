@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AssetDigests returns a comma separated list of the sha256 digests of the
+// files embedded using //go:embed directives in the Go files of the given
+// project directory. The list is injected into the application so that it
+// may verify its frontend assets at startup.
+func AssetDigests(projectDir string) (string, error) {
+	goFiles, err := filepath.Glob(filepath.Join(projectDir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+
+	digests := map[string]bool{}
+	for _, goFile := range goFiles {
+		patterns, err := embedPatterns(goFile)
+		if err != nil {
+			return "", err
+		}
+		for _, pattern := range patterns {
+			matches, err := filepath.Glob(filepath.Join(projectDir, pattern))
+			if err != nil {
+				return "", err
+			}
+			for _, match := range matches {
+				if !fs.FileExists(match) {
+					continue
+				}
+				digest, err := fs.FileSHA256(match)
+				if err != nil {
+					return "", err
+				}
+				digests[digest] = true
+			}
+		}
+	}
+
+	result := make([]string, 0, len(digests))
+	for digest := range digests {
+		result = append(result, digest)
+	}
+	sort.Strings(result)
+	return strings.Join(result, ","), nil
+}
+
+// embedPatterns returns the file patterns given to //go:embed directives in the given file
+func embedPatterns(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var result []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "//go:embed ") {
+			continue
+		}
+		for _, pattern := range strings.Fields(strings.TrimPrefix(line, "//go:embed ")) {
+			result = append(result, strings.Trim(pattern, "\"`"))
+		}
+	}
+	return result, scanner.Err()
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/wailsapp/wails/lib/binding"
+)
+
+// moduleName is the name of the generated module, without its extension
+const moduleName = "backend"
+
+// GenerateModule writes a JS module wrapping the bound methods of the
+// project's app, and its Typescript definitions, to the given directory,
+// which is the "wails" directory of the frontend if it is empty. It returns
+// the directory.
+func GenerateModule(projectDir string, projectOptions *ProjectOptions, dir string) (string, error) {
+	data, err := GenerateAPISpec(projectDir, projectOptions, "json")
+	if err != nil {
+		return "", err
+	}
+	var spec binding.APISpec
+	err = json.Unmarshal(data, &spec)
+	if err != nil {
+		return "", fmt.Errorf("invalid description of the bound methods: %s", err.Error())
+	}
+
+	if dir == "" {
+		if projectOptions.FrontEnd == nil {
+			return "", fmt.Errorf("frontend directory not set in project.json")
+		}
+		dir = filepath.Join(projectDir, projectOptions.FrontEnd.Dir, bundlerPluginDir)
+	}
+	err = fs.MkDirs(dir)
+	if err != nil {
+		return "", err
+	}
+	err = fs.CreateFile(filepath.Join(dir, moduleName+".js"), spec.Module())
+	if err != nil {
+		return "", err
+	}
+	err = fs.CreateFile(filepath.Join(dir, moduleName+".d.ts"), spec.Typescript())
+	if err != nil {
+		return "", err
+	}
+	return dir, nil
+}
@@ -330,6 +330,19 @@ func (b *PackageHelper) PackageWindows(po *ProjectOptions, cleanUp bool) error {
 		}
 	}
 
+	// The manifest names the architecture being built for
+	manifest, err := ioutil.ReadFile(tgtManifestFile)
+	if err != nil {
+		return err
+	}
+	updated := setManifestArchitecture(string(manifest), po.TargetArchitecture())
+	if updated != string(manifest) {
+		err = ioutil.WriteFile(tgtManifestFile, []byte(updated), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Copy rc file
 	tgtRCFile := filepath.Join(outputDir, basename+".rc")
 	if !b.fs.FileExists(tgtRCFile) {
@@ -348,6 +361,13 @@ func (b *PackageHelper) PackageWindows(po *ProjectOptions, cleanUp bool) error {
 	// Build syso
 	sysofile := filepath.Join(outputDir, basename+"-res.syso")
 
+	// windows/arm64 resources are compiled by llvm-mingw's windres, as
+	// binutils' windres doesn't target arm64
+	if po.IsWindowsArm64() {
+		windresCommand := []string{windowsArm64Windres, "-o", sysofile, tgtRCFile}
+		return NewProgramHelper().RunCommandArray(windresCommand)
+	}
+
 	// cross-compile
 	if b.platform != runtime.GOOS {
 		args := []string{
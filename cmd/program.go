@@ -30,6 +30,12 @@ func NewProgramHelper(verbose ...bool) *ProgramHelper {
 	return result
 }
 
+// SetEnv adds the given "KEY=value" variables to the environment of the
+// commands that are run
+func (p *ProgramHelper) SetEnv(env ...string) {
+	p.shell.SetEnv(env...)
+}
+
 // IsInstalled tries to determine if the given binary name is installed
 func (p *ProgramHelper) IsInstalled(programName string) bool {
 	_, err := exec.LookPath(programName)
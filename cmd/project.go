@@ -38,6 +38,14 @@ type frontend struct {
 	Serve   string `json:"serve"`
 }
 
+type release struct {
+	GitHub     string   `json:"github,omitempty"`
+	Targets    []string `json:"targets,omitempty"`
+	Draft      bool     `json:"draft,omitempty"`
+	Prerelease bool     `json:"prerelease,omitempty"`
+	SigningKey string   `json:"signingkey,omitempty"`
+}
+
 type framework struct {
 	Name     string            `json:"name"`
 	BuildTag string            `json:"buildtag"`
@@ -92,6 +100,14 @@ func (ph *ProjectHelper) GenerateProject(projectOptions *ProjectOptions) error {
 		return err
 	}
 
+	// Let the frontend's bundler serve the app in development
+	if projectOptions.FrontEnd != nil {
+		err = GenerateBundlerPlugins(projectPath, projectOptions)
+		if err != nil {
+			return err
+		}
+	}
+
 	// // If we are on windows, dump a windows_resource.json
 	// if runtime.GOOS == "windows" {
 	// 	ph.GenerateWindowsResourceConfig(projectOptions)
@@ -151,6 +167,8 @@ type ProjectOptions struct {
 	Template               string    `json:"-"`
 	BinaryName             string    `json:"binaryname"`
 	FrontEnd               *frontend `json:"frontend,omitempty"`
+	Assets                 *assets   `json:"assets,omitempty"`
+	Release                *release  `json:"release,omitempty"`
 	Tags                   string    `json:"tags"`
 	NPMProjectName         string    `json:"-"`
 	system                 *SystemHelper
@@ -166,6 +184,11 @@ type ProjectOptions struct {
 	LdFlags                string
 	GoPath                 string
 	UseFirebug             bool
+	Provenance             bool `json:"-"`
+
+	// Builds Windows release builds as console apps, so a console window is
+	// allocated for them even when they aren't started from a terminal
+	WindowsConsole bool `json:"windowsconsole,omitempty"`
 
 	// Supported platforms
 	Platforms []string `json:"platforms,omitempty"`
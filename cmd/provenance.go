@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	inTotoStatementType = "https://in-toto.io/Statement/v0.1"
+	slsaPredicateType   = "https://slsa.dev/provenance/v0.2"
+	wailsBuildType      = "https://wails.app/build@v1"
+)
+
+// provenanceDigest maps a digest algorithm to a hex encoded digest
+type provenanceDigest map[string]string
+
+type provenanceSubject struct {
+	Name   string           `json:"name"`
+	Digest provenanceDigest `json:"digest"`
+}
+
+type provenanceMaterial struct {
+	URI    string           `json:"uri"`
+	Digest provenanceDigest `json:"digest,omitempty"`
+}
+
+type provenancePredicate struct {
+	Builder struct {
+		ID string `json:"id"`
+	} `json:"builder"`
+	BuildType  string `json:"buildType"`
+	Invocation struct {
+		Parameters map[string]string `json:"parameters"`
+	} `json:"invocation"`
+	Metadata struct {
+		BuildStartedOn  string `json:"buildStartedOn"`
+		BuildFinishedOn string `json:"buildFinishedOn"`
+	} `json:"metadata"`
+	Materials []provenanceMaterial `json:"materials,omitempty"`
+}
+
+// Provenance is an in-toto statement carrying a SLSA provenance predicate
+// that describes how a build artifact was produced
+type Provenance struct {
+	Type          string              `json:"_type"`
+	Subject       []provenanceSubject `json:"subject"`
+	PredicateType string              `json:"predicateType"`
+	Predicate     provenancePredicate `json:"predicate"`
+}
+
+// ChecksumFilename returns the name of the checksum file for the given artifact
+func ChecksumFilename(artifact string) string {
+	return artifact + ".sha256"
+}
+
+// ProvenanceFilename returns the name of the provenance file for the given artifact
+func ProvenanceFilename(artifact string) string {
+	return artifact + ".intoto.json"
+}
+
+// WriteChecksumFile writes a sha256sum compatible checksum file next to
+// the given artifact and returns its path
+func WriteChecksumFile(artifact string) (string, error) {
+	checksum, err := fs.FileSHA256(artifact)
+	if err != nil {
+		return "", err
+	}
+	filename := ChecksumFilename(artifact)
+	data := fmt.Sprintf("%s  %s\n", checksum, filepath.Base(artifact))
+	return filename, fs.CreateFile(filename, []byte(data))
+}
+
+// WriteProvenance writes a provenance statement next to the given artifact
+// and returns its path
+func WriteProvenance(artifact string, buildMode string, started time.Time, po *ProjectOptions) (string, error) {
+	checksum, err := fs.FileSHA256(artifact)
+	if err != nil {
+		return "", err
+	}
+
+	result := &Provenance{
+		Type:          inTotoStatementType,
+		PredicateType: slsaPredicateType,
+		Subject: []provenanceSubject{
+			{Name: filepath.Base(artifact), Digest: provenanceDigest{"sha256": checksum}},
+		},
+	}
+
+	predicate := &result.Predicate
+	predicate.Builder.ID = fmt.Sprintf("https://wails.app/cli@%s", Version)
+	predicate.BuildType = wailsBuildType
+	predicate.Invocation.Parameters = map[string]string{
+		"buildMode":    buildMode,
+		"platform":     po.Platform,
+		"architecture": defaultString(po.Architecture, runtime.GOARCH),
+		"tags":         po.Tags,
+		"ldflags":      po.LdFlags,
+		"goVersion":    runtime.Version(),
+	}
+	predicate.Metadata.BuildStartedOn = started.UTC().Format(time.RFC3339)
+	predicate.Metadata.BuildFinishedOn = time.Now().UTC().Format(time.RFC3339)
+
+	// Record the source revision if we are in a git repository
+	remote, _ := getGitConfigValue("remote.origin.url")
+	if commit, err := gitRevision(); err == nil && commit != "" {
+		predicate.Materials = append(predicate.Materials, provenanceMaterial{
+			URI:    "git+" + defaultString(remote, fs.Cwd()),
+			Digest: provenanceDigest{"sha1": commit},
+		})
+	}
+
+	filename := ProvenanceFilename(artifact)
+	return filename, fs.SaveAsJSON(result, filename)
+}
+
+// VerifyChecksum checks the given artifact against the given checksum file.
+// The checksum file may contain multiple entries, as produced by 'wails release'.
+func VerifyChecksum(artifact, checksumFile string) error {
+	data, err := ioutil.ReadFile(checksumFile)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(artifact)
+	expected := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.TrimPrefix(fields[1], "*") == name {
+			expected = fields[0]
+			break
+		}
+	}
+	if expected == "" {
+		return fmt.Errorf("no checksum for '%s' found in '%s'", name, checksumFile)
+	}
+
+	actual, err := fs.FileSHA256(artifact)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch for '%s': expected %s, got %s", name, expected, actual)
+	}
+	return nil
+}
+
+// VerifyProvenance checks that the given artifact is a subject of the
+// given provenance statement
+func VerifyProvenance(artifact, provenanceFile string) (*Provenance, error) {
+	data, err := ioutil.ReadFile(provenanceFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Provenance
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Type != inTotoStatementType || result.PredicateType != slsaPredicateType {
+		return nil, fmt.Errorf("'%s' is not a supported provenance statement", provenanceFile)
+	}
+
+	actual, err := fs.FileSHA256(artifact)
+	if err != nil {
+		return nil, err
+	}
+	for _, subject := range result.Subject {
+		if subject.Name == filepath.Base(artifact) && strings.EqualFold(subject.Digest["sha256"], actual) {
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("'%s' does not match any subject in '%s'", filepath.Base(artifact), provenanceFile)
+}
+
+// gitRevision returns the commit hash of the current HEAD
+func gitRevision() (string, error) {
+	stdout, _, err := NewShellHelper().Run("git", "rev-parse", "HEAD")
+	return strings.TrimSpace(stdout), err
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wails-verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	artifact := filepath.Join(dir, "myapp")
+	err = ioutil.WriteFile(artifact, []byte("binary"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksumFile, err := WriteChecksumFile(artifact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyChecksum(artifact, checksumFile); err != nil {
+		t.Errorf("VerifyChecksum() error = %v", err)
+	}
+
+	provenanceFile, err := WriteProvenance(artifact, BuildModeProd, time.Now(), &ProjectOptions{Platform: "linux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyProvenance(artifact, provenanceFile); err != nil {
+		t.Errorf("VerifyProvenance() error = %v", err)
+	}
+
+	// Tamper with the artifact
+	err = ioutil.WriteFile(artifact, []byte("tampered"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyChecksum(artifact, checksumFile); err == nil {
+		t.Errorf("VerifyChecksum() expected error for tampered artifact")
+	}
+	if _, err := VerifyProvenance(artifact, provenanceFile); err == nil {
+		t.Errorf("VerifyProvenance() expected error for tampered artifact")
+	}
+}
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leaanthony/spinner"
+)
+
+// ReleaseArtifact describes a single file produced for a release
+type ReleaseArtifact struct {
+	Name     string `json:"name"`
+	Platform string `json:"platform,omitempty"`
+	SHA256   string `json:"sha256"`
+	URL      string `json:"url,omitempty"`
+	path     string
+}
+
+// UpdateManifest describes a release so that applications can check for updates
+type UpdateManifest struct {
+	Name      string             `json:"name"`
+	Version   string             `json:"version"`
+	Published string             `json:"published"`
+	Notes     string             `json:"notes,omitempty"`
+	Artifacts []*ReleaseArtifact `json:"artifacts"`
+}
+
+// releasePublisher creates GitHub releases and uploads their assets
+type releasePublisher interface {
+	CreateRelease(repository, tag, name, notes string, draft, prerelease bool) (*GitHubRelease, error)
+	UploadReleaseAsset(release *GitHubRelease, filename string) (*GitHubAsset, error)
+	DeleteRelease(repository string, release *GitHubRelease) error
+}
+
+// releaseTagger creates and deletes the release tag, locally and on the
+// origin remote
+type releaseTagger interface {
+	CreateTag(tag string) error
+	DeleteTag(tag string) error
+}
+
+// ReleaseHelper helps with the 'wails release' command
+type ReleaseHelper struct {
+	fs             *FSHelper
+	log            *Logger
+	projectOptions *ProjectOptions
+	buildDirectory string
+	github         releasePublisher
+	git            releaseTagger
+}
+
+// NewReleaseHelper creates a new ReleaseHelper for the given project
+func NewReleaseHelper(projectOptions *ProjectOptions) *ReleaseHelper {
+	fs := NewFSHelper()
+	return &ReleaseHelper{
+		fs:             fs,
+		log:            NewLogger(),
+		projectOptions: projectOptions,
+		buildDirectory: filepath.Join(fs.Cwd(), "build"),
+		github:         NewGitHubHelper(),
+		git:            &gitTagger{verbose: projectOptions.Verbose},
+	}
+}
+
+// Tag returns the git tag used for the release
+func (r *ReleaseHelper) Tag() string {
+	return "v" + strings.TrimPrefix(r.projectOptions.Version, "v")
+}
+
+// Targets returns the platform/architecture targets configured for release.
+// If none are configured, the current platform is used.
+func (r *ReleaseHelper) Targets() []string {
+	if r.projectOptions.Release != nil && len(r.projectOptions.Release.Targets) > 0 {
+		return r.projectOptions.Release.Targets
+	}
+	return []string{runtime.GOOS + "/" + runtime.GOARCH}
+}
+
+// Repository returns the "owner/repo" slug of the GitHub repository to publish to.
+// It is read from the project config, falling back to the git origin remote.
+func (r *ReleaseHelper) Repository() (string, error) {
+	if r.projectOptions.Release != nil && r.projectOptions.Release.GitHub != "" {
+		return ParseGitHubRepository(r.projectOptions.Release.GitHub)
+	}
+	remote, err := getGitConfigValue("remote.origin.url")
+	if err != nil || remote == "" {
+		return "", fmt.Errorf("no GitHub repository set in project.json and no git origin remote found")
+	}
+	return ParseGitHubRepository(remote)
+}
+
+// BuildTargets builds the application for each configured target and
+// returns the resulting artifacts
+func (r *ReleaseHelper) BuildTargets() ([]*ReleaseArtifact, error) {
+	var result []*ReleaseArtifact
+	po := r.projectOptions
+
+	for _, target := range r.Targets() {
+		plat := strings.Split(target, "/")
+		if len(plat) != 2 {
+			return nil, fmt.Errorf("invalid release target '%s'. Expected <platform>/<architecture>", target)
+		}
+		po.Platform = plat[0]
+		po.Architecture = plat[1]
+		po.CrossCompile = target != runtime.GOOS+"/"+runtime.GOARCH
+
+		err := BuildApplication(po.BinaryName, false, BuildModeProd, false, po)
+		if err != nil {
+			return nil, err
+		}
+
+		source, err := BuildArtifactPath(po)
+		if err != nil {
+			return nil, err
+		}
+
+		// Give each artifact a unique, descriptive name
+		ext := ""
+		if po.Platform == "windows" {
+			ext = ".exe"
+		}
+		name := fmt.Sprintf("%s-%s-%s-%s%s", strings.TrimSuffix(po.BinaryName, ".exe"), r.Tag(), po.Platform, po.Architecture, ext)
+		destination := filepath.Join(r.buildDirectory, "release", name)
+		err = r.fs.MkDirs(filepath.Dir(destination), 0755)
+		if err != nil {
+			return nil, err
+		}
+		err = r.fs.CopyFile(source, destination)
+		if err != nil {
+			return nil, err
+		}
+
+		checksum, err := r.fs.FileSHA256(destination)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &ReleaseArtifact{
+			Name:     name,
+			Platform: target,
+			SHA256:   checksum,
+			path:     destination,
+		})
+	}
+
+	return result, nil
+}
+
+// WriteChecksums writes a sha256sum compatible checksums file for the
+// given artifacts and returns its path
+func (r *ReleaseHelper) WriteChecksums(artifacts []*ReleaseArtifact) (string, error) {
+	var b strings.Builder
+	sorted := make([]*ReleaseArtifact, len(artifacts))
+	copy(sorted, artifacts)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	for _, artifact := range sorted {
+		fmt.Fprintf(&b, "%s  %s\n", artifact.SHA256, artifact.Name)
+	}
+	filename := filepath.Join(r.buildDirectory, "release", "checksums.txt")
+	return filename, r.fs.CreateFile(filename, []byte(b.String()))
+}
+
+// SignFile creates a detached, armored gpg signature for the given file
+// using the signing key in the project config. If no key is configured,
+// no signature is produced and an empty path is returned.
+func (r *ReleaseHelper) SignFile(filename string) (string, error) {
+	if r.projectOptions.Release == nil || r.projectOptions.Release.SigningKey == "" {
+		return "", nil
+	}
+	if err := CheckIfInstalled("gpg"); err != nil {
+		return "", err
+	}
+	signature := filename + ".asc"
+	os.Remove(signature)
+	err := NewProgramHelper(r.projectOptions.Verbose).RunCommandArray([]string{
+		"gpg", "--batch", "--yes", "--armor",
+		"--local-user", r.projectOptions.Release.SigningKey,
+		"--output", signature,
+		"--detach-sign", filename,
+	})
+	return signature, err
+}
+
+// WriteUpdateManifest writes the update manifest for the given artifacts
+// and returns its path
+func (r *ReleaseHelper) WriteUpdateManifest(artifacts []*ReleaseArtifact) (string, error) {
+	manifest := &UpdateManifest{
+		Name:      r.projectOptions.Name,
+		Version:   r.projectOptions.Version,
+		Published: time.Now().UTC().Format(time.RFC3339),
+		Artifacts: artifacts,
+	}
+	filename := filepath.Join(r.buildDirectory, "release", "update.json")
+	return filename, r.fs.SaveAsJSON(manifest, filename)
+}
+
+// gitTagger tags releases with git
+type gitTagger struct {
+	verbose bool
+}
+
+// CreateTag creates the tag and pushes it to the origin remote. The local
+// tag is deleted if it can't be pushed.
+func (g *gitTagger) CreateTag(tag string) error {
+	program := NewProgramHelper(g.verbose)
+	err := program.RunCommandArray([]string{"git", "tag", "-a", tag, "-m", "Release " + tag})
+	if err != nil {
+		return err
+	}
+	err = program.RunCommandArray([]string{"git", "push", "origin", tag})
+	if err != nil {
+		program.RunCommandArray([]string{"git", "tag", "-d", tag})
+	}
+	return err
+}
+
+// DeleteTag deletes the tag from the origin remote and locally
+func (g *gitTagger) DeleteTag(tag string) error {
+	program := NewProgramHelper(g.verbose)
+	err := program.RunCommandArray([]string{"git", "push", "--delete", "origin", tag})
+	if localErr := program.RunCommandArray([]string{"git", "tag", "-d", tag}); err == nil {
+		err = localErr
+	}
+	return err
+}
+
+// TagRelease creates the release tag and pushes it to the origin remote
+func (r *ReleaseHelper) TagRelease() error {
+	return r.git.CreateTag(r.Tag())
+}
+
+// PublishGitHub tags the project, creates a GitHub release and uploads the
+// given artifacts, the checksums file, its signature and the update manifest.
+// If publishing fails, the release and tag are deleted so it can be run again.
+func (r *ReleaseHelper) PublishGitHub(artifacts []*ReleaseArtifact, checksums, signature string) (result *GitHubRelease, err error) {
+	repository, err := r.Repository()
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail early if we can't authenticate
+	if _, err := githubToken(); err != nil {
+		return nil, err
+	}
+
+	err = r.TagRelease()
+	if err != nil {
+		return nil, err
+	}
+
+	var release *GitHubRelease
+	defer func() {
+		if err != nil {
+			r.rollback(repository, release)
+		}
+	}()
+
+	var draft, prerelease bool
+	if r.projectOptions.Release != nil {
+		draft = r.projectOptions.Release.Draft
+		prerelease = r.projectOptions.Release.Prerelease
+	}
+
+	github := r.github
+	release, err = github.CreateRelease(repository, r.Tag(), r.projectOptions.Name+" "+r.Tag(), r.projectOptions.Description, draft, prerelease)
+	if err != nil {
+		release = nil
+		return nil, err
+	}
+
+	for _, artifact := range artifacts {
+		uploadSpinner := spinner.New("Uploading " + artifact.Name + "...")
+		uploadSpinner.SetSpinSpeed(50)
+		uploadSpinner.Start()
+		asset, err := github.UploadReleaseAsset(release, artifact.path)
+		if err != nil {
+			uploadSpinner.Error()
+			return nil, err
+		}
+		artifact.URL = asset.BrowserDownloadURL
+		uploadSpinner.Success()
+	}
+
+	// The manifest contains the download URLs so is written after the upload
+	manifest, err := r.WriteUpdateManifest(artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, filename := range []string{checksums, signature, manifest} {
+		if filename == "" {
+			continue
+		}
+		_, err = github.UploadReleaseAsset(release, filename)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// rollback deletes the given release, if it was created, and the release tag
+// after publishing failed
+func (r *ReleaseHelper) rollback(repository string, release *GitHubRelease) {
+	if release != nil {
+		if err := r.github.DeleteRelease(repository, release); err != nil {
+			r.log.Error("Unable to delete release %s: %s", r.Tag(), err)
+		}
+	}
+	if err := r.git.DeleteTag(r.Tag()); err != nil {
+		r.log.Error("Unable to delete tag %s: %s", r.Tag(), err)
+	}
+}
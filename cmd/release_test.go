@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakeGitHub records the calls made to publish a release, failing the
+// named one
+type fakeGitHub struct {
+	fail  string
+	calls []string
+}
+
+func (f *fakeGitHub) call(name string) error {
+	f.calls = append(f.calls, name)
+	if name == f.fail {
+		return fmt.Errorf("%s failed", name)
+	}
+	return nil
+}
+
+func (f *fakeGitHub) CreateRelease(repository, tag, name, notes string, draft, prerelease bool) (*GitHubRelease, error) {
+	return &GitHubRelease{ID: 1, TagName: tag}, f.call("create " + tag)
+}
+
+func (f *fakeGitHub) UploadReleaseAsset(release *GitHubRelease, filename string) (*GitHubAsset, error) {
+	name := filepath.Base(filename)
+	return &GitHubAsset{Name: name, BrowserDownloadURL: "https://example.com/" + name}, f.call("upload " + name)
+}
+
+func (f *fakeGitHub) DeleteRelease(repository string, release *GitHubRelease) error {
+	return f.call("delete release")
+}
+
+func (f *fakeGitHub) CreateTag(tag string) error {
+	return f.call("tag " + tag)
+}
+
+func (f *fakeGitHub) DeleteTag(tag string) error {
+	return f.call("delete tag " + tag)
+}
+
+func TestPublishGitHub(t *testing.T) {
+	token, hadToken := os.LookupEnv("GITHUB_TOKEN")
+	os.Setenv("GITHUB_TOKEN", "token")
+	defer func() {
+		if hadToken {
+			os.Setenv("GITHUB_TOKEN", token)
+		} else {
+			os.Unsetenv("GITHUB_TOKEN")
+		}
+	}()
+
+	tests := []struct {
+		name  string
+		fail  string
+		calls []string
+	}{
+		{"published", "", []string{"tag v1.0.0", "create v1.0.0", "upload app", "upload checksums.txt", "upload update.json"}},
+		{"tag fails", "tag v1.0.0", []string{"tag v1.0.0"}},
+		{"release fails", "create v1.0.0", []string{"tag v1.0.0", "create v1.0.0", "delete tag v1.0.0"}},
+		{"upload fails", "upload app", []string{"tag v1.0.0", "create v1.0.0", "upload app", "delete release", "delete tag v1.0.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buildDirectory := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(buildDirectory, "release"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			fake := &fakeGitHub{fail: tt.fail}
+			helper := &ReleaseHelper{
+				fs:             NewFSHelper(),
+				log:            NewLogger(),
+				projectOptions: &ProjectOptions{Name: "App", Version: "1.0.0", Release: &release{GitHub: "wailsapp/app"}},
+				buildDirectory: buildDirectory,
+				github:         fake,
+				git:            fake,
+			}
+			artifacts := []*ReleaseArtifact{{Name: "app", path: filepath.Join(buildDirectory, "release", "app")}}
+
+			_, err := helper.PublishGitHub(artifacts, filepath.Join(buildDirectory, "release", "checksums.txt"), "")
+			if (err != nil) != (tt.fail != "") {
+				t.Fatalf("PublishGitHub() error = %v, expected failure: %q", err, tt.fail)
+			}
+			if !reflect.DeepEqual(fake.calls, tt.calls) {
+				t.Errorf("PublishGitHub() made calls %q, want %q", fake.calls, tt.calls)
+			}
+		})
+	}
+}
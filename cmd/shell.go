@@ -9,6 +9,7 @@ import (
 // ShellHelper helps with Shell commands
 type ShellHelper struct {
 	verbose bool
+	env     []string
 }
 
 // NewShellHelper creates a new ShellHelper!
@@ -21,10 +22,17 @@ func (sh *ShellHelper) SetVerbose() {
 	sh.verbose = true
 }
 
+// SetEnv adds the given "KEY=value" variables to the environment of the
+// commands that are run
+func (sh *ShellHelper) SetEnv(env ...string) {
+	sh.env = append(sh.env, env...)
+}
+
 // Run the given command
 func (sh *ShellHelper) Run(command string, vars ...string) (stdout, stderr string, err error) {
 	cmd := exec.Command(command, vars...)
 	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	cmd.Env = append(cmd.Env, sh.env...)
 	if !sh.verbose {
 		var stdo, stde bytes.Buffer
 		cmd.Stdout = &stdo
@@ -45,6 +53,7 @@ func (sh *ShellHelper) RunInDirectory(dir string, command string, vars ...string
 	cmd := exec.Command(command, vars...)
 	cmd.Dir = dir
 	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	cmd.Env = append(cmd.Env, sh.env...)
 	if !sh.verbose {
 		var stdo, stde bytes.Buffer
 		cmd.Stdout = &stdo
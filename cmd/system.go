@@ -267,6 +267,13 @@ func CheckDependencies(logger *Logger) (bool, error) {
 		}
 	}
 
+	// Windows on ARM needs an arm64 toolchain for native builds
+	if runtime.GOOS == "windows" && IsArm64Host() {
+		if !checkWindowsArm64Host(logger) {
+			errors = true
+		}
+	}
+
 	// Linux has library deps
 	if runtime.GOOS == "linux" {
 		// Check library prerequisites
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wailsapp/wails/cmd"
+)
+
+func init() {
+
+	var publishGitHub = false
+	var verbose = false
+
+	commandDescription := `This command builds the application for every target listed in the "release" section of project.json, generates checksums (signed if a signing key is configured) and an update manifest. With -github, the project is tagged and the artifacts are uploaded to a new GitHub release. The GITHUB_TOKEN environment variable must be set to publish.`
+	releaseCmd := app.Command("release", "Builds and publishes a release of your Wails project").
+		LongDescription(commandDescription).
+		BoolFlag("github", "Tag the project and publish the release to GitHub", &publishGitHub).
+		BoolFlag("verbose", "Verbose output", &verbose)
+
+	releaseCmd.Action(func() error {
+
+		logger.PrintSmallBanner("Releasing Application")
+		fmt.Println()
+
+		// Check we are in project directory
+		// Check project.json loads correctly
+		projectOptions := &cmd.ProjectOptions{}
+		projectOptions.Verbose = verbose
+		fs := cmd.NewFSHelper()
+		err := projectOptions.LoadConfig(fs.Cwd())
+		if err != nil {
+			return fmt.Errorf("unable to find 'project.json'. Please check you are in a Wails project directory")
+		}
+
+		err = cmd.ValidateFrontendConfig(projectOptions)
+		if err != nil {
+			return err
+		}
+
+		release := cmd.NewReleaseHelper(projectOptions)
+
+		// Check we have somewhere to publish to before spending time building
+		if publishGitHub {
+			repository, err := release.Repository()
+			if err != nil {
+				return err
+			}
+			logger.Yellow("Publishing %s to https://github.com/%s", release.Tag(), repository)
+		}
+
+		// Save project directory
+		projectDir := fs.Cwd()
+
+		// Install deps and build frontend
+		err = cmd.InstallFrontendDeps(projectDir, projectOptions, false, "build")
+		if err != nil {
+			return err
+		}
+
+		// Move to project directory
+		err = os.Chdir(projectDir)
+		if err != nil {
+			return err
+		}
+
+		err = cmd.InstallGoDependencies(projectOptions.Verbose)
+		if err != nil {
+			return err
+		}
+
+		artifacts, err := release.BuildTargets()
+		if err != nil {
+			return err
+		}
+
+		checksums, err := release.WriteChecksums(artifacts)
+		if err != nil {
+			return err
+		}
+
+		signature, err := release.SignFile(checksums)
+		if err != nil {
+			return err
+		}
+
+		if !publishGitHub {
+			manifest, err := release.WriteUpdateManifest(artifacts)
+			if err != nil {
+				return err
+			}
+			logger.Yellow("Release %s built. Checksums: %s, Manifest: %s", release.Tag(), checksums, manifest)
+			return nil
+		}
+
+		githubRelease, err := release.PublishGitHub(artifacts, checksums, signature)
+		if err != nil {
+			return err
+		}
+
+		logger.Yellow("Awesome! Release %s published: %s", release.Tag(), githubRelease.HTMLURL)
+		return nil
+	})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/wailsapp/wails/cmd"
+)
+
+func init() {
+
+	var artifact = ""
+	var checksumFile = ""
+	var provenanceFile = ""
+
+	commandDescription := `Verifies a downloaded build artifact against its checksum file and, optionally, its provenance statement. By default, the checksum file is expected next to the artifact with a '.sha256' extension.`
+	verifyCmd := app.Command("verify", "Verifies the checksum and provenance of a build artifact").
+		LongDescription(commandDescription).
+		StringFlag("file", "The artifact to verify", &artifact).
+		StringFlag("checksum", "The checksum file to verify against (eg checksums.txt)", &checksumFile).
+		StringFlag("provenance", "The provenance statement to verify against", &provenanceFile)
+
+	verifyCmd.Action(func() error {
+
+		logger.PrintSmallBanner("Verifying Artifact")
+		fmt.Println()
+
+		if artifact == "" {
+			return fmt.Errorf("no artifact given. Please use -file to specify the file to verify")
+		}
+
+		if checksumFile == "" {
+			checksumFile = cmd.ChecksumFilename(artifact)
+		}
+
+		err := cmd.VerifyChecksum(artifact, checksumFile)
+		if err != nil {
+			return err
+		}
+		logger.Green("Checksum OK")
+
+		if provenanceFile != "" {
+			provenance, err := cmd.VerifyProvenance(artifact, provenanceFile)
+			if err != nil {
+				return err
+			}
+			logger.Green("Provenance OK (built by %s)", provenance.Predicate.Builder.ID)
+		}
+
+		logger.Yellow("'%s' verified!", artifact)
+		return nil
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/wailsapp/wails/cmd"
+)
+
+func init() {
+
+	commandDescription := `Validates the project config, app icon, entitlements, file associations, frontend build outputs and Go code without building the app, so problems are found before a long build.`
+	checkCmd := app.Command("check", "Checks the project for problems before building").
+		LongDescription(commandDescription)
+
+	checkCmd.Action(func() error {
+
+		logger.PrintSmallBanner("Checking Project")
+		fmt.Println()
+
+		fs := cmd.NewFSHelper()
+		problems := cmd.CheckProject(fs.Cwd())
+		for _, problem := range problems {
+			logger.Red("%s", problem)
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("found %d problem(s)", len(problems))
+		}
+
+		logger.Yellow("No problems found!")
+		return nil
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wailsapp/wails/cmd"
+)
+
+func init() {
+
+	commandDescription := `Generates the Vite and webpack plugins in the "wails" directory of the frontend. When the frontend is served by the bundler, the plugins proxy the bridge to the app started with 'wails serve', use the bridge runtime and copy the app's Typescript bindings to the frontend whenever they change.`
+	generateCmd := app.Command("generate", "Generates the frontend bundler plugins").
+		LongDescription(commandDescription)
+
+	generateCmd.Action(func() error {
+
+		logger.PrintSmallBanner("Generating Bundler Plugins")
+		fmt.Println()
+
+		projectOptions := &cmd.ProjectOptions{}
+		fs := cmd.NewFSHelper()
+		err := projectOptions.LoadConfig(fs.Cwd())
+		if err != nil {
+			return err
+		}
+
+		err = cmd.GenerateBundlerPlugins(fs.Cwd(), projectOptions)
+		if err != nil {
+			return err
+		}
+
+		logger.Yellow("Plugins written to '%s/wails'", projectOptions.FrontEnd.Dir)
+		return nil
+	})
+
+	var outputFile string
+	format := "json"
+	apiSpecDescription := `Describes the bound methods of the app, their parameters and results, and the structs they use, as JSON schemas. The description can be used to generate clients for apps served over the bridge, or documentation. It is written to stdout unless an output file is given.`
+	apiSpecCmd := generateCmd.Command("api-spec", "Generates a description of the bound methods").
+		LongDescription(apiSpecDescription).
+		StringFlag("o", "Output file", &outputFile).
+		StringFlag("format", "Output format: json or yaml", &format)
+
+	apiSpecCmd.Action(func() error {
+
+		projectOptions := &cmd.ProjectOptions{}
+		fs := cmd.NewFSHelper()
+		err := projectOptions.LoadConfig(fs.Cwd())
+		if err != nil {
+			return err
+		}
+
+		spec, err := cmd.GenerateAPISpec(fs.Cwd(), projectOptions, format)
+		if err != nil {
+			return err
+		}
+
+		if outputFile == "" {
+			_, err = os.Stdout.Write(spec)
+			return err
+		}
+		err = fs.CreateFile(outputFile, spec)
+		if err != nil {
+			return err
+		}
+		logger.Yellow("API spec written to '%s'", outputFile)
+		return nil
+	})
+
+	var outputDir string
+	moduleDescription := `Generates a JS module exporting the bound structs and functions of the app, which call window.backend, and its Typescript definitions, with the parameter and result types of the Go methods and interfaces for the structs they use. They are written to the "wails" directory of the frontend unless an output directory is given.`
+	moduleCmd := generateCmd.Command("module", "Generates a JS module and Typescript definitions for the bound methods").
+		LongDescription(moduleDescription).
+		StringFlag("o", "Output directory", &outputDir)
+
+	moduleCmd.Action(func() error {
+
+		logger.PrintSmallBanner("Generating Module")
+		fmt.Println()
+
+		projectOptions := &cmd.ProjectOptions{}
+		fs := cmd.NewFSHelper()
+		err := projectOptions.LoadConfig(fs.Cwd())
+		if err != nil {
+			return err
+		}
+
+		dir, err := cmd.GenerateModule(fs.Cwd(), projectOptions, outputDir)
+		if err != nil {
+			return err
+		}
+		logger.Yellow("Module written to '%s'", dir)
+		return nil
+	})
+}
@@ -19,6 +19,7 @@ func getSupportedPlatforms() []string {
 		"linux/amd64",
 		"linux/arm-7",
 		"windows/amd64",
+		"windows/arm64",
 	}
 }
 
@@ -34,6 +35,9 @@ func init() {
 	var platform = ""
 	var ldflags = ""
 	var tags = ""
+	var provenance = false
+	var console = false
+	var appName = ""
 
 	buildSpinner := spinner.NewSpinner()
 	buildSpinner.SetSpinSpeed(50)
@@ -46,6 +50,9 @@ func init() {
 		BoolFlag("d", "Build in Debug mode", &debugMode).
 		BoolFlag("firebug", "Enable firebug console for debug builds", &usefirebug).
 		BoolFlag("verbose", "Verbose output", &verbose).
+		BoolFlag("provenance", "Generate a SLSA provenance statement for the built artifact", &provenance).
+		BoolFlag("console", "Allocate a console window for Windows release builds", &console).
+		StringFlag("app", "The app to build in a workspace (see wails.workspace.json)", &appName).
 		StringFlag("t", "Generate Typescript definitions to given file (at runtime)", &typescriptFilename).
 		StringFlag("ldflags", "Extra options for -ldflags", &ldflags).
 		StringFlag("gopath", "Specify your GOPATH location. Mounted to /go during cross-compilation.", &gopath).
@@ -78,10 +85,27 @@ func init() {
 		projectOptions := &cmd.ProjectOptions{}
 		projectOptions.Verbose = verbose
 		projectOptions.UseFirebug = usefirebug
+		projectOptions.Provenance = provenance
+
+		// Move to the directory of the selected app in the workspace
+		fs := cmd.NewFSHelper()
+		if appName != "" {
+			workspace, err := cmd.FindWorkspace(fs.Cwd())
+			if err != nil {
+				return err
+			}
+			appDir, err := workspace.AppDir(appName)
+			if err != nil {
+				return err
+			}
+			err = os.Chdir(appDir)
+			if err != nil {
+				return err
+			}
+		}
 
 		// Check we are in project directory
 		// Check project.json loads correctly
-		fs := cmd.NewFSHelper()
 		err := projectOptions.LoadConfig(fs.Cwd())
 		if err != nil {
 			return fmt.Errorf("unable to find 'project.json'. Please check you are in a Wails project directory")
@@ -90,6 +114,11 @@ func init() {
 		// Set firebug flag
 		projectOptions.UseFirebug = usefirebug
 
+		// Keep the console on Windows
+		if console {
+			projectOptions.WindowsConsole = true
+		}
+
 		// Check that this platform is supported
 		if !projectOptions.PlatformSupported() {
 			logger.Yellow("WARNING: This project is unsupported on %s - it probably won't work!\n         Valid platforms: %s\n", runtime.GOOS, strings.Join(projectOptions.Platforms, ", "))
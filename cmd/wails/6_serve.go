@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 	"runtime"
 
 	"github.com/leaanthony/spinner"
@@ -51,6 +52,14 @@ func init() {
 			return err
 		}
 
+		// Write the bindings for the bundler plugins to copy. The filename
+		// is relative to the frontend directory.
+		bindingsFile, err := filepath.Rel(filepath.Join(projectDir, projectOptions.FrontEnd.Dir), filepath.Join(projectDir, cmd.BindingsFile))
+		if err != nil {
+			return err
+		}
+		projectOptions.SetTypescriptDefsFilename(bindingsFile)
+
 		// Install dependencies
 		err = cmd.InstallGoDependencies(projectOptions.Verbose)
 		if err != nil {
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// configPollInterval is how often project.json is checked for changes
+const configPollInterval = time.Second
+
+// projectSetting is a setting in project.json that is compared when the
+// file changes. Live settings are applied while the project is served,
+// the others need it to be served again.
+type projectSetting struct {
+	name  string
+	value func(*ProjectOptions) string
+	live  bool
+}
+
+var projectSettings = []projectSetting{
+	{"name", func(po *ProjectOptions) string { return po.Name }, false},
+	{"version", func(po *ProjectOptions) string { return po.Version }, false},
+	{"binaryname", func(po *ProjectOptions) string { return po.BinaryName }, false},
+	{"tags", func(po *ProjectOptions) string { return po.Tags }, false},
+	{"frontend.dir", func(po *ProjectOptions) string { return frontendOf(po).Dir }, false},
+	{"frontend.install", func(po *ProjectOptions) string { return frontendOf(po).Install }, false},
+	{"frontend.build", func(po *ProjectOptions) string { return frontendOf(po).Build }, false},
+	{"frontend.bridge", func(po *ProjectOptions) string { return frontendOf(po).Bridge }, false},
+	{"frontend.serve", func(po *ProjectOptions) string { return frontendOf(po).Serve }, true},
+}
+
+// frontendOf returns the frontend settings, which may be missing
+func frontendOf(po *ProjectOptions) frontend {
+	if po.FrontEnd == nil {
+		return frontend{}
+	}
+	return *po.FrontEnd
+}
+
+// changedSettings returns the settings that differ between the options
+func changedSettings(old, updated *ProjectOptions) []projectSetting {
+	var result []projectSetting
+	for _, setting := range projectSettings {
+		if setting.value(old) != setting.value(updated) {
+			result = append(result, setting)
+		}
+	}
+	return result
+}
+
+// ConfigWatcher reloads project.json when it changes while the project is
+// served. Live settings are copied to the project options and the others
+// are logged as needing a restart.
+type ConfigWatcher struct {
+	projectDir string
+	options    *ProjectOptions
+	log        *Logger
+	modified   time.Time
+	onChange   func(setting string)
+	quit       chan struct{}
+}
+
+// NewConfigWatcher creates a watcher for the project.json in the project
+// directory. onChange is called with the name of each live setting that
+// changes.
+func NewConfigWatcher(projectDir string, options *ProjectOptions, log *Logger, onChange func(setting string)) *ConfigWatcher {
+	return &ConfigWatcher{
+		projectDir: projectDir,
+		options:    options,
+		log:        log,
+		onChange:   onChange,
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start polls project.json until Stop is called
+func (c *ConfigWatcher) Start() {
+	c.modified = c.modTime()
+	go func() {
+		ticker := time.NewTicker(configPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.quit:
+				return
+			case <-ticker.C:
+				modified := c.modTime()
+				if !modified.Equal(c.modified) {
+					c.modified = modified
+					c.reload()
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops watching project.json
+func (c *ConfigWatcher) Stop() {
+	close(c.quit)
+}
+
+func (c *ConfigWatcher) modTime() time.Time {
+	info, err := os.Stat(filepath.Join(c.projectDir, "project.json"))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// reload applies the live settings from project.json and
+// logs the others that have changed
+func (c *ConfigWatcher) reload() {
+	updated := &ProjectOptions{}
+	err := updated.LoadConfig(c.projectDir)
+	if err != nil {
+		c.log.Red("Unable to reload project.json: %s", err.Error())
+		return
+	}
+
+	for _, setting := range changedSettings(c.options, updated) {
+		if !setting.live {
+			c.log.Yellow("project.json: '%s' has changed. Restart 'wails serve' to apply it.", setting.name)
+			continue
+		}
+		switch setting.name {
+		case "frontend.serve":
+			if c.options.FrontEnd == nil {
+				c.options.FrontEnd = &frontend{}
+			}
+			c.options.FrontEnd.Serve = frontendOf(updated).Serve
+		}
+		c.log.Green("project.json: '%s' has been updated", setting.name)
+		if c.onChange != nil {
+			c.onChange(setting.name)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestChangedSettings(t *testing.T) {
+	old := &ProjectOptions{Name: "app", FrontEnd: &frontend{Dir: "frontend", Serve: "npm run serve"}}
+	updated := &ProjectOptions{Name: "app", FrontEnd: &frontend{Dir: "web", Serve: "npm run dev"}, Tags: "debug"}
+
+	changed := changedSettings(old, updated)
+	names := map[string]bool{}
+	for _, setting := range changed {
+		names[setting.name] = setting.live
+	}
+	if len(names) != 3 {
+		t.Fatalf("expected 3 changed settings, got %v", names)
+	}
+	if live, ok := names["frontend.serve"]; !ok || !live {
+		t.Errorf("expected frontend.serve to change live")
+	}
+	if live, ok := names["frontend.dir"]; !ok || live {
+		t.Errorf("expected frontend.dir to need a restart")
+	}
+	if _, ok := names["tags"]; !ok {
+		t.Errorf("expected tags to have changed")
+	}
+
+	if len(changedSettings(old, &ProjectOptions{Name: "app", FrontEnd: &frontend{Dir: "frontend", Serve: "npm run serve"}})) != 0 {
+		t.Errorf("expected no changes")
+	}
+}
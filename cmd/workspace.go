@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WorkspaceFilename is the name of the config at the root of a repository
+// holding several Wails apps
+const WorkspaceFilename = "wails.workspace.json"
+
+// Workspace is a repository holding several Wails apps, which share its Go
+// modules (EG: with go.work) and frontend packages (EG: with npm workspaces)
+type Workspace struct {
+	// The directory holding the workspace config
+	Dir string `json:"-"`
+
+	// The directory of each app's project.json, relative to the workspace,
+	// by app name
+	Apps map[string]string `json:"apps"`
+}
+
+// FindWorkspace returns the workspace holding the given directory, looking
+// for the workspace config in it and each of its parents
+func FindWorkspace(dir string) (*Workspace, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		filename := filepath.Join(dir, WorkspaceFilename)
+		data, err := ioutil.ReadFile(filename)
+		if err == nil {
+			workspace := &Workspace{Dir: dir}
+			err = json.Unmarshal(data, workspace)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read '%s': %s", filename, err.Error())
+			}
+			return workspace, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("unable to find '%s'. Please check you are in a Wails workspace", WorkspaceFilename)
+		}
+		dir = parent
+	}
+}
+
+// AppNames returns the names of the apps in the workspace, sorted
+func (w *Workspace) AppNames() []string {
+	var result []string
+	for name := range w.Apps {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// AppDir returns the directory of the named app
+func (w *Workspace) AppDir(name string) (string, error) {
+	dir, ok := w.Apps[name]
+	if !ok {
+		return "", fmt.Errorf("there is no app named '%s' in the workspace. Valid apps: %s", name, strings.Join(w.AppNames(), ", "))
+	}
+	return filepath.Join(w.Dir, filepath.FromSlash(dir)), nil
+}
+
+// runtimePackageDir returns the directory of the @wailsapp/runtime package
+// used by the given frontend. Like node, the node_modules directories of the
+// frontend and its parents are searched, as workspaces hoist shared packages.
+// The frontend's own node_modules is used if the package isn't installed.
+func runtimePackageDir(frontendDir string) string {
+	packageDir := filepath.Join("node_modules", "@wailsapp", "runtime")
+	dir, err := filepath.Abs(frontendDir)
+	if err != nil {
+		return filepath.Join(frontendDir, packageDir)
+	}
+	for {
+		if fs.DirExists(filepath.Join(dir, packageDir)) {
+			return filepath.Join(dir, packageDir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return filepath.Join(frontendDir, packageDir)
+		}
+		dir = parent
+	}
+}
+
+// installRuntime writes the runtime's init.js for the given frontend. The
+// file may be shared by the apps of a workspace, so it is only written when
+// it changes, leaving the bundlers' caches of the other apps intact.
+func installRuntime(frontendDir string, data []byte) error {
+	target := filepath.Join(runtimePackageDir(frontendDir), "init.js")
+	current, err := ioutil.ReadFile(target)
+	if err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	return fs.CreateFile(target, data)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindWorkspace(t *testing.T) {
+	root := t.TempDir()
+	config := `{"apps": {"admin": "apps/admin", "shop": "apps/shop"}}`
+	err := ioutil.WriteFile(filepath.Join(root, WorkspaceFilename), []byte(config), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appDir := filepath.Join(root, "apps", "shop")
+	err = os.MkdirAll(appDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workspace, err := FindWorkspace(appDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := workspace.AppDir("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "apps", "admin"); dir != want {
+		t.Errorf("AppDir() = %s, want %s", dir, want)
+	}
+	if _, err := workspace.AppDir("missing"); err == nil {
+		t.Error("AppDir() expected an error for an unknown app")
+	}
+}
+
+func TestRuntimePackageDirHoisted(t *testing.T) {
+	root := t.TempDir()
+	hoisted := filepath.Join(root, "node_modules", "@wailsapp", "runtime")
+	frontendDir := filepath.Join(root, "apps", "shop", "frontend")
+	for _, dir := range []string{hoisted, frontendDir} {
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := runtimePackageDir(frontendDir); got != hoisted {
+		t.Errorf("runtimePackageDir() = %s, want %s", got, hoisted)
+	}
+}
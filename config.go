@@ -1,9 +1,18 @@
 package wails
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
 	"net/url"
+	goruntime "runtime"
 	"strings"
 
+	"github.com/wailsapp/wails/lib/renderer"
 	"github.com/wailsapp/wails/runtime"
 )
 
@@ -19,6 +28,19 @@ type AppConfig struct {
 	// <!DOCTYPE html><html lang="en"><head><meta charset="utf-8"><meta http-equiv="IE=edge" content="IE=edge"></head><body><div id="app"></div><script type="text/javascript"></script></body></html>
 	HTML string
 
+	// Renders the HTML as a html/template when the window loads it, so the
+	// page can bootstrap without calling the backend. The template is given
+	// the app's .Title and .Version, a random .Nonce for each launch, EG: for
+	// a Content-Security-Policy, and .State, the result of InitialState.
+	// Values are escaped for where they appear, so
+	// <script>var state = {{.State}}</script> gives the state as JSON.
+	TemplateHTML bool
+
+	// Called each time the frontend loads. The JSON of its result is set as
+	// window.__WAILS_STATE__ before the app's JS runs, so the first render
+	// has data without waiting for a call to the backend.
+	InitialState func() interface{}
+
 	// The Javascript your app should use. Normally this should be generated by a bundler.
 	JS string
 
@@ -28,6 +50,19 @@ type AppConfig struct {
 	// The colour of your window. Can take "#fff", "rgb(255,255,255)", "rgba(255,255,255,1)" formats
 	Colour string
 
+	// The background colour of the window and webview, including its alpha.
+	// Overrides Colour.
+	RGBA *color.RGBA
+
+	// Makes the background colour see-through, EG: for HUD style overlays.
+	// Linux needs a compositing window manager. On Windows, the page must
+	// paint its background in the same colour as it is cut out.
+	Transparent bool
+
+	// How the desktop shows through a transparent window on MacOS and Windows
+	MacBackdrop     Backdrop
+	WindowsBackdrop Backdrop
+
 	// Indicates whether your app should be resizable
 	Resizable bool
 
@@ -45,6 +80,215 @@ type AppConfig struct {
 
 	// Indicated if the devtools should be disabled
 	DisableInspector bool
+
+	// The version of your application. Included in bug reports.
+	Version string
+
+	// The copyright notice shown by the about dialog with the app's Title
+	// and Version, EG: "© 2021 My Company"
+	Copyright string
+
+	// The PNG image shown by the about dialog instead of the app's icon
+	AboutIcon []byte
+
+	// The URL of your issue tracker's "new issue" page, EG: https://github.com/me/myapp/issues/new
+	// Bug reports will be prefilled using "title" and "body" query parameters.
+	IssueTracker string
+
+	// The email address support requests should be sent to
+	SupportEmail string
+
+	// Called when the frontend assets fail the integrity check performed at startup
+	// and before they are injected. The returned IntegrityAction decides whether the
+	// app continues to run, stops or repairs its assets. Assets that fail are only
+	// injected if IntegrityWarn is returned. If not set, failures are logged.
+	OnIntegrityFailure func(*IntegrityError) IntegrityAction
+
+	// Called when OnIntegrityFailure returns IntegrityRepair. It returns an asset
+	// patch and its signature, EG: downloaded from the app's update server, which
+	// replace the assets that failed. The patch must be signed with the AssetPatchKey.
+	RepairAssets func(*IntegrityError) (archive, signature []byte, err error)
+
+	// The hex encoded ed25519 public key that asset patches are signed with.
+	// Setting it lets the app update its HTML, JS and CSS without replacing
+	// the binary: patches passed to runtime.Patches.Stage are verified and
+	// switched to when the app next starts.
+	AssetPatchKey string
+
+	// Hides the title bar so the content extends under the window buttons, for apps
+	// drawing their own title bar (MacOS). The area covered by the buttons is set as
+	// the --wails-titlebar-x, -y, -width and -height CSS variables.
+	TitleBarOverlay bool
+
+	// The offset of the window buttons from the top left of the window (MacOS)
+	TrafficLightPosition image.Point
+
+	// Opens the window centered on the display the mouse cursor is on
+	OpenOnCursorDisplay bool
+
+	// The position of the top left of the window when it opens, relative to
+	// the top left of the primary display, EG: from runtime.Window.GetPosition.
+	// If both are 0, the window is placed by the platform.
+	StartX, StartY int
+
+	// Opens the window centred on the display. StartX and StartY are ignored.
+	Centre bool
+
+	// Saves the size, position, maximised state and display of the window
+	// when it closes, in the user's config directory under the app's title,
+	// and restores them when the app starts. If the display has gone, the
+	// window is centred on the display it opens on. Width, Height, StartX,
+	// StartY and Centre only apply until the state has been saved.
+	PersistWindowState bool
+
+	// Keeps the window above all other windows. It can be changed at runtime
+	// with runtime.Window.SetAlwaysOnTop.
+	AlwaysOnTop bool
+
+	// Turns off the system's pen and touch gestures over the page, EG: for
+	// drawing apps. It can be changed at runtime with
+	// runtime.Window.SetSystemGestures.
+	DisableSystemGestures bool
+
+	// Opens the window fullscreen and above all other windows, EG: for
+	// digital signage. The user can't close the window or leave it with the
+	// keyboard, so the app must quit itself with runtime.Window.Close.
+	// On MacOS, the dock and menu bar are hidden and app switching is off.
+	Kiosk bool
+
+	// Starts the app without showing its window, EG: for apps that live in
+	// the tray. The window is shown with runtime.Window.Show. The app keeps
+	// running while its window is hidden, so windows made with App.NewWindow
+	// can be opened and closed on demand, and it quits when the hidden window
+	// is closed with runtime.Window.Close.
+	StartHidden bool
+
+	// Runs the app from the system tray, with no window shown when it starts.
+	// The main window is created hidden and is hidden again when the user
+	// closes it, so it can be shown on demand with runtime.Window.Show. By
+	// default, clicking the tray icon shows it; runtime.Tray.OnClick replaces
+	// this. The app quits when runtime.Window.Close is called. Needs TrayIcon.
+	TrayOnly bool
+
+	// The PNG or ICO data shown in the system tray when the app starts. It can
+	// be changed at runtime with runtime.Tray.SetIcon.
+	TrayIcon []byte
+
+	// Shown in the window as soon as it opens, until the frontend is ready,
+	// instead of a blank page. Either a small fragment of HTML or an image
+	// as a data URL, EG: "data:image/png;base64,...". It is centred on the
+	// window's colour.
+	SplashScreen string
+
+	// Writes stdout, stderr and the log to the console of the terminal the app
+	// was started from (Windows). Release builds have no console of their own
+	// unless built with "wails build -console".
+	AttachConsole bool
+
+	// Writes anything printed to os.Stdout and os.Stderr to the log, so it is
+	// kept with the app's other messages. Stdout is logged as info and stderr
+	// as errors.
+	CaptureOutput bool
+
+	// Adds a panel to debug builds, toggled with Ctrl+Shift+D, listing the
+	// recent calls to bound methods with their durations and payload sizes,
+	// and the events emitted by the frontend and the backend
+	DevOverlay bool
+
+	// Asks the user to move the app to the Applications folder when it's
+	// opened from where it was downloaded (MacOS). MacOS runs such apps from
+	// a random, read-only copy, so files next to the app aren't found and
+	// updates can't be installed.
+	MoveToApplications bool
+
+	// Allows the window to be merged into tabs with other windows of the app (MacOS only)
+	WindowTabbing bool
+
+	// Called when the "+" button in the window's tab bar is clicked (MacOS only).
+	// Requires WindowTabbing.
+	OnNewTab func()
+
+	// Called when the process rendering the page crashes or is killed for using
+	// too much memory (Linux only, as the webview runs in the app's process on
+	// Windows and MacOS). The reason is "crashed" or "exceeded-memory-limit".
+	// The returned WebViewCrashAction decides how the app recovers. If not set,
+	// the page is reloaded.
+	OnWebViewCrash func(reason string) WebViewCrashAction
+
+	// Called when the user closes the main window or quits the app from the
+	// menu. Return false to keep the app open, EG: when the user chooses not
+	// to lose unsaved changes. The window stays open while the hook runs, so
+	// it can ask with the runtime's dialogs.
+	OnBeforeClose func(*runtime.Runtime) bool
+
+	// Captures the keys pressed in the app's windows before the page sees
+	// them, even when the focus is in an iframe, EG: for app-wide shortcuts.
+	// Return true to keep the key from the page. Each key is also emitted
+	// as the "wails:key" event. It is called on the UI thread, so it must
+	// return quickly and can't wait for the window.
+	OnKey func(KeyEvent) bool
+
+	// Captures the mouse's back and forward buttons, sideways scrolling and
+	// swipes in the app's windows before the page sees them, as webviews
+	// ignore some of them and handle others differently on each platform.
+	// Return true to keep the gesture from the page. Each gesture is also
+	// emitted as the "wails:gesture" event. It is called on the UI thread,
+	// like OnKey.
+	OnGesture func(GestureEvent) bool
+
+	// Decides whether the page at the given origin may have the user's
+	// location with navigator.geolocation, EG: by asking the user with the
+	// runtime's dialogs. It runs off the UI thread. Pages are denied the
+	// location if it isn't set. The location comes from GeoClue on Linux
+	// and CoreLocation on MacOS, where the app's Info.plist must explain
+	// its use with NSLocationUsageDescription. Not supported on Windows.
+	OnGeolocation func(origin string) bool
+
+	// Connects in-app purchases to a store's billing API. If not set,
+	// purchases are unavailable. See runtime.Purchases.
+	Billing BillingProvider
+
+	// Connect the user's calendars and reminders, EG: through EventKit. If
+	// not set, they are unavailable. See runtime.Calendar and
+	// runtime.Reminders.
+	Calendar  CalendarProvider
+	Reminders RemindersProvider
+
+	// The menu bar, EG: with File, Edit and Help menus. It can be changed
+	// while the app runs with its methods or Runtime.MenuBar. On MacOS, its
+	// menus follow the app menu and the Edit menu.
+	Menu *Menu
+
+	// Publishes the methods bound with the Scriptable option so other
+	// applications can script the app. Use a reverse domain name, EG:
+	// "com.example.MyApp". It is the D-Bus service name on Linux and the
+	// COM ProgID on Windows. On MacOS the methods are called with Apple Events.
+	AutomationID string
+
+	// The size in bytes above which call arguments and results are streamed
+	// between the frontend and the app in chunks of this size, instead of as
+	// one message that freezes the webview while it is built and parsed,
+	// EG: 1 << 20 for 1MB. Off by default. It can be set for the results of
+	// a bound struct's methods with the MaxPayloadSize option.
+	MaxPayloadSize int
+
+	// Compresses messages to the frontend in bridge mode that are at least
+	// this many bytes, EG: large tables of data. The browser agrees to
+	// deflate compression when it connects, so the frontend needs no
+	// changes. 0 turns compression off.
+	BridgeCompressionThreshold int
+
+	// Sends the results of bound methods that are byte slices, EG: images,
+	// to the frontend as Uint8Arrays rather than as base64 strings in JSON.
+	// Only bridge mode sends them as binary, in websocket frames. The webview
+	// can only be sent strings, so there they are still sent as base64, but
+	// outside the JSON and decoded for the frontend. The generated Typescript
+	// definitions type them as Uint8Array.
+	BinaryData bool
+
+	// Turns off optional subsystems the app doesn't use. Calls to a disabled
+	// subsystem return an error matching runtime.ErrSubsystemDisabled.
+	Subsystems Subsystems
 }
 
 // GetWidth returns the desired width
@@ -62,14 +306,70 @@ func (a *AppConfig) GetTitle() string {
 	return a.Title
 }
 
-// GetHTML returns the default HTML
-func (a *AppConfig) GetHTML() string {
-	if len(a.HTML) > 0 {
-		a.HTML = url.QueryEscape(a.HTML)
-		a.HTML = "data:text/html," + strings.ReplaceAll(a.HTML, "+", "%20")
-		a.HTML = strings.ReplaceAll(a.HTML, "%3D", "=")
+// GetHTML returns the URL of the HTML for the window to load. If TemplateHTML
+// is set, the template is rendered each time the HTML is loaded.
+func (a *AppConfig) GetHTML() (string, error) {
+	html := a.HTML
+	if len(html) == 0 {
+		return html, nil
+	}
+	if a.TemplateHTML {
+		var err error
+		html, err = a.renderHTML(html)
+		if err != nil {
+			return "", fmt.Errorf("cannot render the HTML template: %s", err.Error())
+		}
+	}
+	html = renderer.SplashScreenHTML(html, a.SplashScreen, a.splashScreenBackground())
+	html = url.QueryEscape(html)
+	html = "data:text/html," + strings.ReplaceAll(html, "+", "%20")
+	return strings.ReplaceAll(html, "%3D", "="), nil
+}
+
+// htmlTemplateData is given to the HTML when TemplateHTML is set
+type htmlTemplateData struct {
+	Title   string
+	Version string
+	Nonce   string
+	State   interface{}
+}
+
+// renderHTML executes the given HTML template
+func (a *AppConfig) renderHTML(html string) (string, error) {
+	tmpl, err := template.New("html").Parse(html)
+	if err != nil {
+		return "", err
 	}
-	return a.HTML
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	var state interface{}
+	if a.InitialState != nil {
+		state = a.InitialState()
+	}
+	var result bytes.Buffer
+	err = tmpl.Execute(&result, &htmlTemplateData{
+		Title:   a.Title,
+		Version: a.Version,
+		Nonce:   base64.StdEncoding.EncodeToString(nonce),
+		State:   state,
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// splashScreenBackground returns the colour of the window as CSS
+func (a *AppConfig) splashScreenBackground() string {
+	if a.RGBA != nil {
+		return fmt.Sprintf("rgba(%d,%d,%d,%.3f)", a.RGBA.R, a.RGBA.G, a.RGBA.B, float64(a.RGBA.A)/255)
+	}
+	if a.Colour != "" {
+		return a.Colour
+	}
+	return "#fff"
 }
 
 // GetResizable returns true if the window should be resizable
@@ -102,11 +402,196 @@ func (a *AppConfig) GetDisableInspector() bool {
 	return a.DisableInspector
 }
 
+// GetVersion returns the application version
+func (a *AppConfig) GetVersion() string {
+	return a.Version
+}
+
+// GetCopyright returns the copyright notice shown by the about dialog
+func (a *AppConfig) GetCopyright() string {
+	return a.Copyright
+}
+
+// GetAboutIcon returns the image shown by the about dialog
+func (a *AppConfig) GetAboutIcon() []byte {
+	return a.AboutIcon
+}
+
+// GetIssueTracker returns the URL used to file new issues
+func (a *AppConfig) GetIssueTracker() string {
+	return a.IssueTracker
+}
+
+// GetSupportEmail returns the support email address
+func (a *AppConfig) GetSupportEmail() string {
+	return a.SupportEmail
+}
+
+// GetAssetPatchKey returns the key asset patches are signed with
+func (a *AppConfig) GetAssetPatchKey() string {
+	return a.AssetPatchKey
+}
+
+// GetWindowTabbing returns true if the window may be merged into tabs
+func (a *AppConfig) GetWindowTabbing() bool {
+	return a.WindowTabbing
+}
+
+// GetTitleBarOverlay returns true if the title bar should be hidden
+func (a *AppConfig) GetTitleBarOverlay() bool {
+	return a.TitleBarOverlay
+}
+
+// GetTrafficLightPosition returns the offset of the window buttons
+func (a *AppConfig) GetTrafficLightPosition() image.Point {
+	return a.TrafficLightPosition
+}
+
+// GetOpenOnCursorDisplay returns true if the window should
+// open on the display the mouse cursor is on
+func (a *AppConfig) GetOpenOnCursorDisplay() bool {
+	return a.OpenOnCursorDisplay
+}
+
+// GetStartX returns the horizontal position the window opens at
+func (a *AppConfig) GetStartX() int {
+	return a.StartX
+}
+
+// GetStartY returns the vertical position the window opens at
+func (a *AppConfig) GetStartY() int {
+	return a.StartY
+}
+
+// GetCentre returns true if the window should open centred
+func (a *AppConfig) GetCentre() bool {
+	return a.Centre
+}
+
+// GetPersistWindowState returns true if the window should reopen
+// as it was when it last closed
+func (a *AppConfig) GetPersistWindowState() bool {
+	return a.PersistWindowState
+}
+
+// GetConfirmClose returns true if the OnBeforeClose hook must
+// confirm that the main window can close
+func (a *AppConfig) GetConfirmClose() bool {
+	return a.OnBeforeClose != nil
+}
+
+// GetOnKey returns the function given the keys
+// pressed in the app's windows, or nil if they
+// aren't captured
+func (a *AppConfig) GetOnKey() func(KeyEvent) bool {
+	return a.OnKey
+}
+
+// GetOnGesture returns the function given the
+// gestures made in the app's windows, or nil if
+// they aren't captured
+func (a *AppConfig) GetOnGesture() func(GestureEvent) bool {
+	return a.OnGesture
+}
+
+// GetOnGeolocation returns the function deciding
+// which pages may have the user's location, or nil
+// if they are denied it
+func (a *AppConfig) GetOnGeolocation() func(string) bool {
+	return a.OnGeolocation
+}
+
+// GetMaxPayloadSize returns the size in bytes above
+// which call payloads are streamed in chunks
+func (a *AppConfig) GetMaxPayloadSize() int {
+	return a.MaxPayloadSize
+}
+
+// GetBridgeCompressionThreshold returns the size in bytes from
+// which bridge messages are compressed, or 0 if they aren't
+func (a *AppConfig) GetBridgeCompressionThreshold() int {
+	return a.BridgeCompressionThreshold
+}
+
+// GetBinaryData returns true if results that are byte
+// slices are sent to the frontend as binary
+func (a *AppConfig) GetBinaryData() bool {
+	return a.BinaryData
+}
+
+// GetKiosk returns true if the window should open in kiosk mode
+func (a *AppConfig) GetKiosk() bool {
+	return a.Kiosk
+}
+
+// GetStartHidden returns true if the window should
+// not be shown when the app starts
+func (a *AppConfig) GetStartHidden() bool {
+	return a.StartHidden || a.TrayOnly
+}
+
+// GetTrayOnly returns true if the app runs from the tray,
+// hiding the main window rather than closing it
+func (a *AppConfig) GetTrayOnly() bool {
+	return a.TrayOnly
+}
+
+// GetSplashScreen returns the HTML or image shown
+// until the frontend is ready
+func (a *AppConfig) GetSplashScreen() string {
+	return a.SplashScreen
+}
+
+// GetInitialState returns the function giving the
+// frontend its initial state, or nil if it has none
+func (a *AppConfig) GetInitialState() func() interface{} {
+	return a.InitialState
+}
+
+// GetAlwaysOnTop returns true if the window should
+// be kept above all other windows
+func (a *AppConfig) GetAlwaysOnTop() bool {
+	return a.AlwaysOnTop
+}
+
+// GetDisableSystemGestures returns true if the system's pen and
+// touch gestures should be turned off
+func (a *AppConfig) GetDisableSystemGestures() bool {
+	return a.DisableSystemGestures
+}
+
+// GetSubsystems returns the subsystems that are turned off
+func (a *AppConfig) GetSubsystems() Subsystems {
+	return a.Subsystems
+}
+
 // GetColour returns the colour
 func (a *AppConfig) GetColour() string {
 	return a.Colour
 }
 
+// GetRGBA returns the background colour, if set
+func (a *AppConfig) GetRGBA() *color.RGBA {
+	return a.RGBA
+}
+
+// GetTransparent returns true if the window should be transparent
+func (a *AppConfig) GetTransparent() bool {
+	return a.Transparent
+}
+
+// GetBackdrop returns the backdrop of a transparent window
+// on the current platform
+func (a *AppConfig) GetBackdrop() Backdrop {
+	switch goruntime.GOOS {
+	case "darwin":
+		return a.MacBackdrop
+	case "windows":
+		return a.WindowsBackdrop
+	}
+	return BackdropTransparent
+}
+
 // GetCSS returns the user CSS
 func (a *AppConfig) GetCSS() string {
 	return a.CSS
@@ -133,6 +618,15 @@ func (a *AppConfig) merge(in *AppConfig) error {
 		a.HTML = in.HTML
 	}
 
+	// Catch mistakes in the template when the app is created
+	if in.TemplateHTML {
+		if _, err := template.New("html").Parse(a.HTML); err != nil {
+			return err
+		}
+	}
+	a.TemplateHTML = in.TemplateHTML
+	a.InitialState = in.InitialState
+
 	if in.JS != "" {
 		a.JS = in.JS
 	}
@@ -164,8 +658,133 @@ func (a *AppConfig) merge(in *AppConfig) error {
 		a.MaxHeight = in.MaxHeight
 	}
 
+	if in.StartX != 0 {
+		a.StartX = in.StartX
+	}
+
+	if in.StartY != 0 {
+		a.StartY = in.StartY
+	}
+
+	if in.Version != "" {
+		a.Version = in.Version
+	}
+
+	if in.Copyright != "" {
+		a.Copyright = in.Copyright
+	}
+
+	if in.AboutIcon != nil {
+		a.AboutIcon = in.AboutIcon
+	}
+
+	if in.IssueTracker != "" {
+		a.IssueTracker = in.IssueTracker
+	}
+
+	if in.SupportEmail != "" {
+		a.SupportEmail = in.SupportEmail
+	}
+
+	if in.OnIntegrityFailure != nil {
+		a.OnIntegrityFailure = in.OnIntegrityFailure
+	}
+
+	if in.RepairAssets != nil {
+		a.RepairAssets = in.RepairAssets
+	}
+
+	if in.AssetPatchKey != "" {
+		a.AssetPatchKey = in.AssetPatchKey
+	}
+
+	if in.OnNewTab != nil {
+		a.OnNewTab = in.OnNewTab
+	}
+
+	if in.OnWebViewCrash != nil {
+		a.OnWebViewCrash = in.OnWebViewCrash
+	}
+
+	if in.OnBeforeClose != nil {
+		a.OnBeforeClose = in.OnBeforeClose
+	}
+
+	if in.OnKey != nil {
+		a.OnKey = in.OnKey
+	}
+
+	if in.OnGesture != nil {
+		a.OnGesture = in.OnGesture
+	}
+
+	if in.OnGeolocation != nil {
+		a.OnGeolocation = in.OnGeolocation
+	}
+
+	if in.Billing != nil {
+		a.Billing = in.Billing
+	}
+
+	if in.Calendar != nil {
+		a.Calendar = in.Calendar
+	}
+
+	if in.Reminders != nil {
+		a.Reminders = in.Reminders
+	}
+
+	if in.Menu != nil {
+		a.Menu = in.Menu
+	}
+
+	if in.AutomationID != "" {
+		a.AutomationID = in.AutomationID
+	}
+
+	if in.RGBA != nil {
+		a.RGBA = in.RGBA
+	}
+
+	if in.MaxPayloadSize != 0 {
+		a.MaxPayloadSize = in.MaxPayloadSize
+	}
+
+	if in.BridgeCompressionThreshold > 0 {
+		a.BridgeCompressionThreshold = in.BridgeCompressionThreshold
+	}
+
+	a.BinaryData = in.BinaryData
+
 	a.Resizable = in.Resizable
 	a.DisableInspector = in.DisableInspector
+	a.WindowTabbing = in.WindowTabbing
+	a.OpenOnCursorDisplay = in.OpenOnCursorDisplay
+	a.Centre = in.Centre
+	a.PersistWindowState = in.PersistWindowState
+	a.AlwaysOnTop = in.AlwaysOnTop
+	a.DisableSystemGestures = in.DisableSystemGestures
+	a.Kiosk = in.Kiosk
+	a.StartHidden = in.StartHidden
+	a.TrayOnly = in.TrayOnly
+	a.TrayIcon = in.TrayIcon
+	if a.TrayOnly && len(a.TrayIcon) == 0 {
+		return fmt.Errorf("TrayOnly needs a TrayIcon to show in the tray")
+	}
+	a.SplashScreen = in.SplashScreen
+	a.AttachConsole = in.AttachConsole
+	a.CaptureOutput = in.CaptureOutput
+	a.DevOverlay = in.DevOverlay
+	a.MoveToApplications = in.MoveToApplications
+	a.Subsystems = in.Subsystems
+	a.Transparent = in.Transparent
+	a.MacBackdrop = in.MacBackdrop
+	a.WindowsBackdrop = in.WindowsBackdrop
+	a.TitleBarOverlay = in.TitleBarOverlay
+
+	if in.TrafficLightPosition != (image.Point{}) {
+		a.TrafficLightPosition = in.TrafficLightPosition
+	}
 
 	return nil
 }
@@ -0,0 +1,65 @@
+package wails
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGetHTMLTemplate(t *testing.T) {
+	count := 0
+	config, err := newConfig(&AppConfig{
+		Title:        "App",
+		HTML:         `<html><body data-nonce="{{.Nonce}}">{{.Title}} {{.State}}</body></html>`,
+		TemplateHTML: true,
+		InitialState: func() interface{} {
+			count++
+			return count
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := config.HTML
+
+	render := func() string {
+		html, err := config.GetHTML()
+		if err != nil {
+			t.Fatal(err)
+		}
+		html, err = url.PathUnescape(strings.TrimPrefix(html, "data:text/html,"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return html
+	}
+	first, second := render(), render()
+	if !strings.Contains(first, "App 1") || !strings.Contains(second, "App 2") {
+		t.Errorf("GetHTML() rendered %q then %q, want the state of each load", first, second)
+	}
+	if first[:strings.Index(first, ">App")] == second[:strings.Index(second, ">App")] {
+		t.Errorf("GetHTML() used the same nonce for each load")
+	}
+	if config.HTML != template {
+		t.Errorf("GetHTML() changed the HTML to %q", config.HTML)
+	}
+
+	// Errors executing the template are returned
+	config.HTML = `{{.Missing}}`
+	if _, err := config.GetHTML(); err == nil {
+		t.Errorf("GetHTML() expected an error")
+	}
+}
+
+func TestTrayOnly(t *testing.T) {
+	if _, err := newConfig(&AppConfig{TrayOnly: true}); err == nil {
+		t.Errorf("newConfig() expected an error without a TrayIcon")
+	}
+	config, err := newConfig(&AppConfig{TrayOnly: true, TrayIcon: []byte{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.GetStartHidden() || !config.GetTrayOnly() {
+		t.Errorf("a tray only app must start hidden")
+	}
+}
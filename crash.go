@@ -0,0 +1,35 @@
+package wails
+
+// WebViewCrashAction is returned by the OnWebViewCrash hook to decide
+// how the app recovers when the webview's process terminates
+type WebViewCrashAction int
+
+const (
+	// WebViewReload reloads the page. Stores are sent their
+	// current data once the page is ready.
+	WebViewReload WebViewCrashAction = iota
+
+	// WebViewShowError shows a native error dialog then quits the app
+	WebViewShowError
+
+	// WebViewIgnore does nothing, leaving the app to recover
+	// by listening for the "wails:webview-crashed" event
+	WebViewIgnore
+)
+
+// handleWebViewCrash calls the OnWebViewCrash hook with the reason
+// the webview's process terminated and carries out its action
+func (a *App) handleWebViewCrash(reason string) {
+	action := WebViewReload
+	if a.config.OnWebViewCrash != nil {
+		action = a.config.OnWebViewCrash(reason)
+	}
+	switch action {
+	case WebViewReload:
+		a.log.Info("Reloading the webview")
+		a.renderer.Reload()
+	case WebViewShowError:
+		a.renderer.ShowError(a.config.Title, "The app stopped unexpectedly and needs to close.")
+		a.renderer.Close()
+	}
+}
@@ -0,0 +1,36 @@
+package wails
+
+import (
+	"github.com/wailsapp/wails/lib/perf"
+)
+
+// The dev overlay asks for the traces recorded after the last one it has,
+// and is sent them, see EnableDevOverlay in the JS runtime
+const (
+	devOverlayEnableEvent = perf.OverlayEventPrefix + "enable"
+	devOverlayFetchEvent  = perf.OverlayEventPrefix + "fetch"
+	devOverlayTracesEvent = perf.OverlayEventPrefix + "traces"
+)
+
+// startDevOverlay traces the calls and events, sharing the recorder with
+// --perf, and enables the overlay each time the frontend is ready
+func (a *App) startDevOverlay() {
+	if a.recorder == nil {
+		a.recorder = perf.NewRecorder()
+		a.ipc.RecordPerformance(a.recorder)
+	}
+	a.eventManager.RecordPerformance(a.recorder)
+
+	a.eventManager.On("wails:ready", func(...interface{}) {
+		a.eventManager.Emit(devOverlayEnableEvent)
+	})
+	a.eventManager.On(devOverlayFetchEvent, func(data ...interface{}) {
+		after := 0
+		if len(data) > 0 {
+			if seq, ok := data[0].(float64); ok {
+				after = int(seq)
+			}
+		}
+		a.eventManager.Emit(devOverlayTracesEvent, a.recorder.Traces(after))
+	})
+}
@@ -0,0 +1,70 @@
+package wails
+
+import (
+	"fmt"
+
+	"github.com/wailsapp/wails/cmd"
+	"github.com/wailsapp/wails/lib/renderer"
+	"github.com/wailsapp/wails/pkg/cli"
+)
+
+// Start opens the window and starts the app without running the UI loop,
+// for host apps that own the main thread, EG: native apps or game engines
+// embedding Wails. It is called instead of Run, on the main thread. The host
+// then calls Tick from its loop, unless the loop already processes the
+// platform's UI events, and Stop to close the window. The host's menus are
+// left alone on MacOS.
+func (a *App) Start() error {
+	if BuildMode == cmd.BuildModeBridge {
+		return fmt.Errorf("apps cannot be embedded in bridge mode")
+	}
+	if a.embedded {
+		return fmt.Errorf("the app has already been started")
+	}
+
+	// The host's command line is not for the app
+	flags, err := cli.Parse(nil)
+	if err != nil {
+		return err
+	}
+	a.flags = flags
+	if BuildMode == cmd.BuildModeProd {
+		a.logLevel = "error"
+	}
+
+	a.renderer = renderer.NewEmbeddedWebView()
+	err = a.launch()
+	if err != nil {
+		a.log.Error(err.Error())
+		return err
+	}
+	a.embedded = true
+
+	// Returns once the runtime has been injected
+	return a.renderer.Run()
+}
+
+// Tick processes the pending UI events of an app started with Start. If
+// blocking, it waits for the next event. It returns false once the window
+// has closed, after which the app has been shut down. It must be called on
+// the main thread.
+func (a *App) Tick(blocking bool) bool {
+	if !a.embedded {
+		return false
+	}
+	if a.renderer.(*renderer.WebView).Tick(blocking) {
+		return true
+	}
+	a.stopOnce.Do(a.shutdown)
+	return false
+}
+
+// Stop closes the window of an app started with Start and shuts the app
+// down. It must be called on the main thread.
+func (a *App) Stop() {
+	if !a.embedded {
+		return
+	}
+	a.renderer.(*renderer.WebView).Stop()
+	a.stopOnce.Do(a.shutdown)
+}
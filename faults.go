@@ -0,0 +1,45 @@
+package wails
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wailsapp/wails/lib/perf"
+)
+
+// injectFaults slows down and fails calls and events as set by --latency,
+// --jitter and --failure-rate, if any of them were given
+func (a *App) injectFaults() error {
+	if a.latency == "" && a.jitter == "" && a.failureRate == "" {
+		return nil
+	}
+	var latency, jitter time.Duration
+	var failureRate float64
+	var err error
+	if a.latency != "" {
+		latency, err = time.ParseDuration(a.latency)
+		if err != nil {
+			return fmt.Errorf("invalid --latency: %s", err.Error())
+		}
+	}
+	if a.jitter != "" {
+		jitter, err = time.ParseDuration(a.jitter)
+		if err != nil {
+			return fmt.Errorf("invalid --jitter: %s", err.Error())
+		}
+	}
+	if a.failureRate != "" {
+		failureRate, err = perf.ParseFailureRate(a.failureRate)
+		if err != nil {
+			return err
+		}
+	}
+	faults, err := perf.NewFaults(latency, jitter, failureRate)
+	if err != nil {
+		return err
+	}
+	a.log.Infof("Injecting %s latency, up to %s jitter and failing %.0f%% of calls and events", latency, jitter, failureRate*100)
+	a.ipc.InjectFaults(faults)
+	a.eventManager.InjectFaults(faults)
+	return nil
+}
@@ -0,0 +1,31 @@
+package wails
+
+import (
+	wailsruntime "github.com/wailsapp/wails/runtime"
+)
+
+// checkInstallation warns when MacOS has translocated the app, as it runs
+// from a read-only copy that breaks relative paths and updates. With
+// MoveToApplications, the user is offered to move the app instead. Returns
+// true if it was moved, so this instance should quit.
+func (a *App) checkInstallation() bool {
+	system := wailsruntime.NewSystem(a.eventManager, a.config)
+	installation, err := system.Installation()
+	if err != nil {
+		a.log.Errorf("Unable to read the app's installation: %s", err.Error())
+		return false
+	}
+	if !installation.Translocated {
+		return false
+	}
+	a.log.Warnf("The app is translocated to %s, as it was opened from %s", installation.Path, installation.OriginalPath)
+	if !a.config.MoveToApplications {
+		return false
+	}
+	moved, err := system.OfferMoveToApplications()
+	if err != nil {
+		a.log.Errorf("Unable to move the app to the Applications folder: %s", err.Error())
+		return false
+	}
+	return moved
+}
@@ -0,0 +1,162 @@
+package wails
+
+import (
+	"github.com/wailsapp/wails/lib/integrity"
+	"github.com/wailsapp/wails/lib/patch"
+)
+
+// IntegrityAction is returned by the OnIntegrityFailure hook to decide
+// what happens when the frontend assets fail verification
+type IntegrityAction int
+
+const (
+	// IntegrityWarn logs the failure and continues to run the app with the
+	// assets that failed
+	IntegrityWarn IntegrityAction = iota
+
+	// IntegrityRefuse stops the app from running
+	IntegrityRefuse
+
+	// IntegrityRepair fetches a signed asset patch using the RepairAssets hook
+	// and switches to it. The app stops if the assets can't be repaired.
+	IntegrityRepair
+)
+
+// IntegrityError is passed to the OnIntegrityFailure hook. It lists
+// the assets that failed verification.
+type IntegrityError = integrity.Error
+
+// verifyIntegrity checks the frontend assets against the digests recorded at
+// build time. If they fail, the OnIntegrityFailure hook decides what to do.
+// A nil error is returned if the app may continue to run.
+//
+// The digests are part of the binary they guard, so this detects assets that
+// have been swapped or corrupted on disk or in a patch, not a rewritten binary.
+// Code signing the app is what protects the binary itself.
+func (a *App) verifyIntegrity() error {
+	err := a.verifyAssets()
+	if err == nil {
+		return nil
+	}
+	return a.handleIntegrityFailure(err.(*IntegrityError))
+}
+
+// verifyAssets checks the JS and CSS against the digests recorded at build time
+func (a *App) verifyAssets() error {
+	return integrity.Verify(map[string]string{
+		"JS":  a.config.JS,
+		"CSS": a.config.CSS,
+	})
+}
+
+// handleIntegrityFailure calls the OnIntegrityFailure hook with the given error.
+// Without a hook, failures are logged and the app continues to run.
+func (a *App) handleIntegrityFailure(err *IntegrityError) error {
+	action := IntegrityWarn
+	if a.config.OnIntegrityFailure != nil {
+		action = a.config.OnIntegrityFailure(err)
+	}
+	switch action {
+	case IntegrityRefuse:
+		return err
+	case IntegrityRepair:
+		return a.repairAssets(err)
+	}
+	a.log.Error(err.Error())
+
+	// The assets are trusted for the rest of the run, so the renderer
+	// injects them without reporting them again
+	for _, asset := range err.Assets {
+		switch asset {
+		case "JS":
+			integrity.Trust(a.config.JS)
+		case "CSS":
+			integrity.Trust(a.config.CSS)
+		}
+	}
+	return nil
+}
+
+// repairAssets replaces the assets that failed verification with those in the
+// asset patch returned by the RepairAssets hook. The patch must be signed with
+// the AssetPatchKey. The original error is returned if there is no patch to
+// repair the assets with.
+func (a *App) repairAssets(failure *IntegrityError) error {
+	if a.config.RepairAssets == nil || a.config.AssetPatchKey == "" {
+		a.log.Error("Unable to repair the assets: RepairAssets and AssetPatchKey must be set")
+		return failure
+	}
+	archive, signature, err := a.config.RepairAssets(failure)
+	if err != nil {
+		a.log.Errorf("Unable to repair the assets: %s", err.Error())
+		return failure
+	}
+	dir, err := patch.Dir(a.config.Title)
+	if err != nil {
+		a.log.Errorf("Unable to find the asset patches: %s", err.Error())
+		return failure
+	}
+	patcher, err := patch.New(dir, a.config.AssetPatchKey, a.config.Version)
+	if err != nil {
+		a.log.Error(err.Error())
+		return failure
+	}
+	err = patcher.Stage(archive, signature)
+	if err != nil {
+		a.log.Errorf("Unable to stage the repaired assets: %s", err.Error())
+		return failure
+	}
+
+	// Switch to the patch now rather than when the app next starts
+	a.applyAssetPatch()
+	err = a.verifyAssets()
+	if err != nil {
+		return err
+	}
+	a.log.Info("Repaired the assets")
+	return nil
+}
+
+// applyAssetPatch swaps the frontend assets for those in the current asset
+// patch, switching to a staged patch first. The patched assets are trusted
+// by the integrity check as their signature has been verified. If the patch
+// can't be loaded, it is discarded and the app's own assets are used.
+func (a *App) applyAssetPatch() {
+	if a.config.AssetPatchKey == "" {
+		return
+	}
+	dir, err := patch.Dir(a.config.Title)
+	if err != nil {
+		a.log.Errorf("Unable to find the asset patches: %s", err.Error())
+		return
+	}
+	patcher, err := patch.New(dir, a.config.AssetPatchKey, a.config.Version)
+	if err != nil {
+		a.log.Error(err.Error())
+		return
+	}
+	assets, err := patcher.Load()
+	if err != nil {
+		a.log.Errorf("Unable to load the asset patch: %s", err.Error())
+		patcher.Discard()
+		return
+	}
+	if assets == nil {
+		return
+	}
+
+	for _, asset := range []struct {
+		patched string
+		target  *string
+	}{
+		{assets.HTML, &a.config.HTML},
+		{assets.JS, &a.config.JS},
+		{assets.CSS, &a.config.CSS},
+	} {
+		if asset.patched != "" {
+			integrity.Trust(asset.patched)
+			*asset.target = asset.patched
+		}
+	}
+	a.log.Info("Applied the asset patch")
+}
@@ -0,0 +1,133 @@
+package wails
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/wailsapp/wails/lib/integrity"
+	"github.com/wailsapp/wails/lib/logger"
+	"github.com/wailsapp/wails/lib/patch"
+)
+
+// setConfigDir points the user's config directory at a temporary directory
+// so asset patches are staged there
+func setConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"HOME", "XDG_CONFIG_HOME", "AppData"} {
+		value, ok := os.LookupEnv(name)
+		os.Setenv(name, dir)
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(name, value)
+			} else {
+				os.Unsetenv(name)
+			}
+		})
+	}
+}
+
+func makePatch(t *testing.T, privateKey ed25519.PrivateKey, js string) ([]byte, []byte) {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, contents := range map[string]string{patch.ManifestFile: `{"version":"1.0.0"}`, patch.JSFile: js} {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Write([]byte(contents))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes(), ed25519.Sign(privateKey, buffer.Bytes())
+}
+
+func TestHandleIntegrityFailure(t *testing.T) {
+	setConfigDir(t)
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := hex.EncodeToString(publicKey)
+
+	repairWith := func(js string) func(*IntegrityError) ([]byte, []byte, error) {
+		return func(*IntegrityError) ([]byte, []byte, error) {
+			archive, signature := makePatch(t, privateKey, js)
+			return archive, signature, nil
+		}
+	}
+
+	tests := []struct {
+		name         string
+		action       IntegrityAction
+		key          string
+		repairAssets func(*IntegrityError) ([]byte, []byte, error)
+		wantErr      bool
+		wantJS       string
+	}{
+		{name: "warn", action: IntegrityWarn, wantJS: "tampered()"},
+		{name: "refuse", action: IntegrityRefuse, wantErr: true, wantJS: "tampered()"},
+		{name: "repair without hook", action: IntegrityRepair, key: key, wantErr: true, wantJS: "tampered()"},
+		{name: "repair without key", action: IntegrityRepair, repairAssets: repairWith("fixed()"), wantErr: true, wantJS: "tampered()"},
+		{
+			name:   "repair fails",
+			action: IntegrityRepair,
+			key:    key,
+			repairAssets: func(*IntegrityError) ([]byte, []byte, error) {
+				return nil, nil, errors.New("offline")
+			},
+			wantErr: true,
+			wantJS:  "tampered()",
+		},
+		{
+			name:   "repair with unsigned patch",
+			action: IntegrityRepair,
+			key:    key,
+			repairAssets: func(failure *IntegrityError) ([]byte, []byte, error) {
+				archive, _, _ := repairWith("fixed()")(failure)
+				return archive, make([]byte, ed25519.SignatureSize), nil
+			},
+			wantErr: true,
+			wantJS:  "tampered()",
+		},
+		{name: "repaired", action: IntegrityRepair, key: key, repairAssets: repairWith("fixed()"), wantJS: "fixed()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{
+				config: &AppConfig{
+					Title:         "Integrity " + tt.name,
+					Version:       "1.0.0",
+					JS:            "tampered()",
+					AssetPatchKey: tt.key,
+					RepairAssets:  tt.repairAssets,
+					OnIntegrityFailure: func(*IntegrityError) IntegrityAction {
+						return tt.action
+					},
+				},
+				log: logger.NewCustomLogger("App"),
+			}
+			failure := &IntegrityError{Assets: []string{"JS"}}
+
+			err := a.handleIntegrityFailure(failure)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handleIntegrityFailure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && err != failure {
+				t.Errorf("handleIntegrityFailure() error = %v, want %v", err, failure)
+			}
+			if a.config.JS != tt.wantJS {
+				t.Errorf("JS = %q, want %q", a.config.JS, tt.wantJS)
+			}
+			// Accepted assets are injected without calling the hook again
+			if tt.action == IntegrityWarn && !integrity.Trusted(a.config.JS) {
+				t.Errorf("the JS wasn't trusted after a warning")
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package binding
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// APISpecEnv is set when the app is started to write the description of its
+// bound methods to stdout, instead of running
+const APISpecEnv = "WAILS_API_SPEC"
+
+// APISpec describes the bound methods of an app and the models they use, so
+// clients and documentation can be generated for them
+type APISpec struct {
+	// The bound methods by binding name, EG: "main.Counter.Add"
+	Methods map[string]*APIMethod `json:"methods"`
+
+	// The structs used by the methods by type name, EG: "main.Todo"
+	Models map[string]*APISchema `json:"models"`
+
+	// Set if results that are byte slices are sent as binary
+	binaryResults bool
+}
+
+// APIMethod describes a bound method. The parameters are passed to the
+// method as a JSON array.
+type APIMethod struct {
+	Params []*APISchema `json:"params"`
+
+	// The result, unless the method only returns an error
+	Result *APISchema `json:"result,omitempty"`
+
+	// Set if the method returns an error, which fails the call
+	Errors bool `json:"errors,omitempty"`
+
+	// Set if the method takes a context, which is cancelled when the
+	// frontend cancels the call, or streams its results
+	Cancellable bool `json:"cancellable,omitempty"`
+
+	// The results the method streams before it returns, if it does
+	Stream *APISchema `json:"stream,omitempty"`
+}
+
+// APISchema is a JSON schema describing a value. Structs are described once
+// in the models and referenced with "#/models/<name>".
+type APISchema struct {
+	Ref                  string                `json:"$ref,omitempty"`
+	Type                 string                `json:"type,omitempty"`
+	Format               string                `json:"format,omitempty"`
+	Items                *APISchema            `json:"items,omitempty"`
+	Properties           map[string]*APISchema `json:"properties,omitempty"`
+	AdditionalProperties *APISchema            `json:"additionalProperties,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// APISpec describes the bound methods and functions. WailsInit,
+// WailsShutdown and the lifecycle hooks are not included as they
+// can't be called.
+func (b *Manager) APISpec() (*APISpec, error) {
+	spec := &APISpec{
+		Methods:       make(map[string]*APIMethod),
+		Models:        make(map[string]*APISchema),
+		binaryResults: b.binaryResults,
+	}
+
+	for index, object := range b.objectsToBind {
+		if object == nil {
+			return nil, fmt.Errorf("attempted to bind nil object")
+		}
+
+		// Describe the structs made by constructors without making them
+		if b.bindOptions[index].Constructor && !b.constructed {
+			serviceType, err := checkConstructor(object)
+			if err != nil {
+				return nil, err
+			}
+			object = reflect.Zero(serviceType).Interface()
+		}
+		objectType := reflect.TypeOf(object)
+		switch objectType.Kind() {
+		case reflect.Ptr:
+			baseName := strings.TrimPrefix(objectType.String(), "*")
+			for i := 0; i < objectType.NumMethod(); i++ {
+				methodName := objectType.Method(i).Name
+				if !unicode.IsUpper([]rune(methodName)[0]) || isLifecycleHook(object, methodName) {
+					continue
+				}
+				fullMethodName := baseName + "." + methodName
+				method, err := newBoundMethod(methodName, fullMethodName, reflect.ValueOf(object).MethodByName(methodName), objectType)
+				if err != nil {
+					return nil, err
+				}
+				if method.isWailsInit || method.isWailsShutdown {
+					continue
+				}
+				spec.Methods[fullMethodName] = spec.method(method.inputs, method.returnTypes, method.hasErrorReturnType, method.needsContext, method.needsStream)
+			}
+		case reflect.Func:
+			function, err := newBoundFunction(object)
+			if err != nil {
+				return nil, err
+			}
+			spec.Methods[function.fullName] = spec.method(function.inputs, function.returnTypes, function.hasErrorReturnType, function.needsContext, function.needsStream)
+		default:
+			return nil, fmt.Errorf("cannot bind object of type '%s'", objectType.Kind().String())
+		}
+	}
+	return spec, nil
+}
+
+// WriteAPISpec writes the description of the bound methods as JSON
+func (b *Manager) WriteAPISpec(out io.Writer) error {
+	spec, err := b.APISpec()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(data, '\n'))
+	return err
+}
+
+// method describes a method with the given parameters and return types.
+// Methods taking a stream may stream anything, and those returning a
+// channel stream its values. Results that are byte slices have the format
+// "binary" if they are sent as binary.
+func (s *APISpec) method(inputs []reflect.Type, returnTypes []reflect.Type, hasErrorReturnType bool, needsContext bool, needsStream bool) *APIMethod {
+	result := &APIMethod{
+		Params:      []*APISchema{},
+		Errors:      hasErrorReturnType,
+		Cancellable: needsContext || needsStream,
+	}
+	for _, input := range inputs {
+		result.Params = append(result.Params, s.schema(input))
+	}
+	if needsStream {
+		result.Stream = &APISchema{}
+	}
+	if hasErrorReturnType {
+		returnTypes = returnTypes[:len(returnTypes)-1]
+	}
+	if len(returnTypes) > 0 && returnTypes[0].Kind() == reflect.Chan {
+		result.Stream = s.schema(returnTypes[0].Elem())
+		result.Cancellable = true
+	} else if len(returnTypes) > 0 && s.binaryResults && isBinary(returnTypes[0]) {
+		result.Result = &APISchema{Type: "string", Format: "binary"}
+	} else if len(returnTypes) > 0 {
+		result.Result = s.schema(returnTypes[0])
+	}
+	return result
+}
+
+// schema describes the given type as it is encoded to JSON, adding the
+// structs it uses to the models
+func (s *APISpec) schema(typ reflect.Type) *APISchema {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == timeType {
+		return &APISchema{Type: "string", Format: "date-time"}
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &APISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &APISchema{Type: "integer", Format: typ.Kind().String()}
+	case reflect.Float32:
+		return &APISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &APISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &APISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// Byte slices are encoded as base64 strings
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+			return &APISchema{Type: "string", Format: "byte"}
+		}
+		return &APISchema{Type: "array", Items: s.schema(typ.Elem())}
+	case reflect.Map:
+		return &APISchema{Type: "object", AdditionalProperties: s.schema(typ.Elem())}
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return s.object(typ)
+		}
+		name := typ.String()
+		if _, ok := s.Models[name]; !ok {
+			// Add the model before its fields, which may refer to it
+			s.Models[name] = &APISchema{}
+			*s.Models[name] = *s.object(typ)
+		}
+		return &APISchema{Ref: "#/models/" + name}
+	}
+	// Interfaces may hold any value
+	return &APISchema{}
+}
+
+// object describes the fields of the given struct, named as in its JSON.
+// The fields of embedded structs are promoted, as with encoding/json.
+func (s *APISpec) object(typ reflect.Type) *APISchema {
+	result := &APISchema{Type: "object", Properties: make(map[string]*APISchema)}
+	var embedded []reflect.Type
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded = append(embedded, fieldType)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		result.Properties[name] = s.schema(field.Type)
+	}
+
+	// The struct's own fields take precedence
+	for _, embeddedType := range embedded {
+		for name, schema := range s.object(embeddedType).Properties {
+			if _, ok := result.Properties[name]; !ok {
+				result.Properties[name] = schema
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package binding
+
+import (
+	"testing"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/runtime"
+)
+
+type specBase struct {
+	ID      int `json:"id"`
+	Created time.Time
+}
+
+type specTodo struct {
+	specBase
+	Title    string            `json:"title"`
+	Done     bool              `json:"done,omitempty"`
+	Tags     []string          `json:"tags"`
+	Children []*specTodo       `json:"children"`
+	Meta     map[string]string `json:"-"`
+	secret   string
+}
+
+type specTodos struct{}
+
+func (t *specTodos) WailsInit(runtime *wailsruntime.Runtime) error { return nil }
+func (t *specTodos) Get(id int) (*specTodo, error)                 { return nil, nil }
+func (t *specTodos) Add(todo specTodo) error                       { return nil }
+func (t *specTodos) Data(data []byte) map[string]uint32            { return nil }
+
+func TestAPISpec(t *testing.T) {
+	manager := NewManager().(*Manager)
+	manager.Bind(&specTodos{})
+	spec, err := manager.APISpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(spec.Methods) != 3 {
+		t.Fatalf("expected 3 methods, got %d", len(spec.Methods))
+	}
+	get := spec.Methods["binding.specTodos.Get"]
+	if get == nil || !get.Errors || len(get.Params) != 1 || get.Params[0].Type != "integer" || get.Result.Ref != "#/models/binding.specTodo" {
+		t.Errorf("unexpected description of Get: %+v", get)
+	}
+	add := spec.Methods["binding.specTodos.Add"]
+	if add == nil || !add.Errors || add.Result != nil || add.Params[0].Ref != "#/models/binding.specTodo" {
+		t.Errorf("unexpected description of Add: %+v", add)
+	}
+	data := spec.Methods["binding.specTodos.Data"]
+	if data == nil || data.Errors || data.Params[0].Format != "byte" || data.Result.AdditionalProperties.Format != "uint32" {
+		t.Errorf("unexpected description of Data: %+v", data)
+	}
+
+	todo := spec.Models["binding.specTodo"]
+	if todo == nil {
+		t.Fatal("expected a model for specTodo")
+	}
+	for _, name := range []string{"id", "Created", "title", "done", "tags", "children"} {
+		if todo.Properties[name] == nil {
+			t.Errorf("expected property '%s'", name)
+		}
+	}
+	if len(todo.Properties) != 6 {
+		t.Errorf("expected 6 properties, got %d", len(todo.Properties))
+	}
+	if todo.Properties["Created"].Format != "date-time" {
+		t.Errorf("expected times to be date-time strings")
+	}
+	if todo.Properties["children"].Items.Ref != "#/models/binding.specTodo" {
+		t.Errorf("expected children to refer to the model")
+	}
+}
@@ -0,0 +1,42 @@
+package binding
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// binaryKey marks the arguments of a call that are byte slices sent with it
+// rather than in its JSON, EG: {"$binary": 0} for the first byte slice
+const binaryKey = "$binary"
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// isBinary returns true for the byte slices sent to the frontend as binary.
+// JSON that is already encoded isn't.
+func isBinary(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 && typ != rawMessageType
+}
+
+// binaryArgs replaces the arguments of a call that refer to the byte slices
+// sent with it by the byte slices
+func binaryArgs(args []interface{}, binary [][]byte) error {
+	if len(binary) == 0 {
+		return nil
+	}
+	for index, arg := range args {
+		placeholder, ok := arg.(map[string]interface{})
+		if !ok || len(placeholder) != 1 {
+			continue
+		}
+		ref, ok := placeholder[binaryKey].(float64)
+		if !ok {
+			continue
+		}
+		if ref < 0 || int(ref) >= len(binary) {
+			return fmt.Errorf("Invalid binary data given for parameter %d", index+1)
+		}
+		args[index] = binary[int(ref)]
+	}
+	return nil
+}
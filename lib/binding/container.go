@@ -0,0 +1,191 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+	wailsruntime "github.com/wailsapp/wails/runtime"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	loggerType  = reflect.TypeOf((*logger.CustomLogger)(nil))
+	runtimeType = reflect.TypeOf((*wailsruntime.Runtime)(nil))
+	storeType   = reflect.TypeOf((*wailsruntime.StoreProvider)(nil))
+)
+
+// The states of the services while they are resolved
+const (
+	unresolved = iota
+	resolving
+	resolved
+)
+
+// container makes the services bound with constructors. A constructor's
+// parameters are the services it depends on: other bound structs, a logger
+// named after the service, the store provider or the runtime.
+type container struct {
+	manager   *Manager
+	providers map[reflect.Type]int // The index of the object providing each struct type
+	states    []int
+	services  []interface{} // The bound structs, by the index of their object
+	order     []int         // The indexes of the objects, after their dependencies
+}
+
+// checkConstructor returns the type of the struct made by the constructor,
+// which must return a struct pointer and optionally an error
+func checkConstructor(constructor interface{}) (reflect.Type, error) {
+	constructorType := reflect.TypeOf(constructor)
+	if constructorType == nil || constructorType.Kind() != reflect.Func {
+		return nil, fmt.Errorf("constructor must be a function, got '%v'", constructorType)
+	}
+	numOut := constructorType.NumOut()
+	if numOut == 0 || numOut > 2 || (numOut == 2 && constructorType.Out(1) != errorType) {
+		return nil, fmt.Errorf("constructor '%s' must return a struct pointer and optionally an error", constructorType)
+	}
+	serviceType := constructorType.Out(0)
+	if serviceType.Kind() != reflect.Ptr || serviceType.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("constructor '%s' must return a struct pointer", constructorType)
+	}
+	return serviceType, nil
+}
+
+// resolveConstructors calls the constructors of the bound services with the
+// services they depend on, replacing them with the structs they make. The
+// objects are reordered so each comes after its dependencies.
+func (b *Manager) resolveConstructors() error {
+	if b.constructed {
+		return nil
+	}
+
+	c := &container{
+		manager:   b,
+		providers: make(map[reflect.Type]int),
+		states:    make([]int, len(b.objectsToBind)),
+		services:  make([]interface{}, len(b.objectsToBind)),
+	}
+	for index, object := range b.objectsToBind {
+		objectType := reflect.TypeOf(object)
+		if b.bindOptions[index].Constructor {
+			var err error
+			objectType, err = checkConstructor(object)
+			if err != nil {
+				return err
+			}
+		}
+		if _, exists := c.providers[objectType]; !exists && objectType != nil {
+			c.providers[objectType] = index
+		}
+	}
+
+	for index := range b.objectsToBind {
+		err := c.resolve(index, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	objects := make([]interface{}, 0, len(c.order))
+	options := make([]*interfaces.BindOptions, 0, len(c.order))
+	for _, index := range c.order {
+		objects = append(objects, c.services[index])
+		options = append(options, b.bindOptions[index])
+	}
+	b.objectsToBind = objects
+	b.bindOptions = options
+	b.constructed = true
+	return nil
+}
+
+// resolve makes the service at index after the services it depends on. The
+// path is the services waiting for it, to report dependency cycles.
+func (c *container) resolve(index int, path []reflect.Type) error {
+	switch c.states[index] {
+	case resolved:
+		return nil
+	case resolving:
+		cycleType := reflect.TypeOf(c.manager.objectsToBind[index]).Out(0)
+		var names []string
+		for i := len(path) - 1; i >= 0; i-- {
+			names = append([]string{path[i].String()}, names...)
+			if path[i] == cycleType {
+				break
+			}
+		}
+		names = append(names, cycleType.String())
+		return fmt.Errorf("dependency cycle: %s", strings.Join(names, " -> "))
+	}
+
+	object := c.manager.objectsToBind[index]
+	if !c.manager.bindOptions[index].Constructor {
+		c.services[index] = object
+		c.states[index] = resolved
+		c.order = append(c.order, index)
+		return nil
+	}
+
+	c.states[index] = resolving
+	constructor := reflect.ValueOf(object)
+	constructorType := constructor.Type()
+	serviceType := constructorType.Out(0)
+	path = append(path, serviceType)
+
+	args := make([]reflect.Value, constructorType.NumIn())
+	for i := range args {
+		arg, err := c.dependency(constructorType.In(i), path)
+		if err != nil {
+			return err
+		}
+		args[i] = arg
+	}
+
+	c.manager.log.Debugf("Creating %s", serviceType)
+	results := constructor.Call(args)
+	if len(results) == 2 && !results[1].IsNil() {
+		return fmt.Errorf("unable to create %s: %s", serviceType, results[1].Interface().(error).Error())
+	}
+	if results[0].IsNil() {
+		return fmt.Errorf("the constructor of %s returned nil", serviceType)
+	}
+
+	c.services[index] = results[0].Interface()
+	c.states[index] = resolved
+	c.order = append(c.order, index)
+	return nil
+}
+
+// dependency returns the value to give a constructor for a parameter of the
+// given type. The last service in the path is the one being made.
+func (c *container) dependency(dependencyType reflect.Type, path []reflect.Type) (reflect.Value, error) {
+	serviceType := path[len(path)-1]
+	rt, _ := c.manager.runtime.(*wailsruntime.Runtime)
+
+	switch dependencyType {
+	case loggerType:
+		name := strings.TrimPrefix(strings.TrimPrefix(serviceType.String(), "*"), "main.")
+		return reflect.ValueOf(logger.NewCustomLogger(name)), nil
+	case runtimeType:
+		if rt == nil {
+			return reflect.Value{}, fmt.Errorf("%s depends on the runtime, which isn't available", serviceType)
+		}
+		return reflect.ValueOf(rt), nil
+	case storeType:
+		if rt == nil {
+			return reflect.Value{}, fmt.Errorf("%s depends on the store, which isn't available", serviceType)
+		}
+		return reflect.ValueOf(rt.Store), nil
+	}
+
+	index, exists := c.providers[dependencyType]
+	if !exists {
+		return reflect.Value{}, fmt.Errorf("%s depends on %s, which isn't bound", serviceType, dependencyType)
+	}
+	err := c.resolve(index, path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(c.services[index]), nil
+}
@@ -0,0 +1,62 @@
+package binding
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+type containerDB struct{}
+
+type containerRepo struct {
+	db  *containerDB
+	log *logger.CustomLogger
+}
+
+type containerCycleA struct{}
+
+type containerCycleB struct{}
+
+func constructorOption(options *interfaces.BindOptions) {
+	options.Constructor = true
+}
+
+func TestResolveConstructors(t *testing.T) {
+	db := &containerDB{}
+	manager := NewManager().(*Manager)
+	manager.Bind(func(db *containerDB, log *logger.CustomLogger) *containerRepo {
+		return &containerRepo{db: db, log: log}
+	}, constructorOption)
+	manager.Bind(db)
+	err := manager.resolveConstructors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manager.objectsToBind) != 2 || manager.objectsToBind[0] != db {
+		t.Fatalf("expected the repo to come after the db, got %v", manager.objectsToBind)
+	}
+	repo, ok := manager.objectsToBind[1].(*containerRepo)
+	if !ok || repo.db != db || repo.log == nil {
+		t.Errorf("expected the repo to be given its dependencies, got %+v", manager.objectsToBind[1])
+	}
+}
+
+func TestResolveConstructorsErrors(t *testing.T) {
+	manager := NewManager().(*Manager)
+	manager.Bind(func(*containerCycleB) *containerCycleA { return &containerCycleA{} }, constructorOption)
+	manager.Bind(func(*containerCycleA) *containerCycleB { return &containerCycleB{} }, constructorOption)
+	err := manager.resolveConstructors()
+	if err == nil || !strings.Contains(err.Error(), "*binding.containerCycleA -> *binding.containerCycleB -> *binding.containerCycleA") {
+		t.Errorf("expected a dependency cycle, got %v", err)
+	}
+
+	manager = NewManager().(*Manager)
+	manager.Bind(func(*containerDB) *containerRepo { return &containerRepo{} }, constructorOption)
+	err = manager.resolveConstructors()
+	if err == nil || !strings.Contains(err.Error(), "isn't bound") {
+		t.Errorf("expected a missing dependency, got %v", err)
+	}
+}
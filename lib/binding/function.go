@@ -2,6 +2,7 @@ package binding
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/wailsapp/wails/lib/logger"
+	wailsruntime "github.com/wailsapp/wails/runtime"
 )
 
 type boundFunction struct {
@@ -19,6 +21,9 @@ type boundFunction struct {
 	returnTypes        []reflect.Type
 	log                *logger.CustomLogger
 	hasErrorReturnType bool
+	needsContext       bool // The first parameter is a context.Context, which isn't sent by the frontend
+	needsStream        bool // The next parameter is a *runtime.Stream, which isn't sent by the frontend
+	returnsChannel     bool // The result is a channel, whose values are streamed
 }
 
 // Creates a new bound function based on the given method + type
@@ -52,11 +57,23 @@ func (b *boundFunction) processParameters() error {
 
 	// Input parameters
 	inputParamCount := functionType.NumIn()
-	if inputParamCount > 0 {
-		b.inputs = make([]reflect.Type, inputParamCount)
+
+	// A context given as the first param is cancelled with the call, and
+	// a stream given next sends results before the call returns
+	firstParam := 0
+	if inputParamCount > firstParam && functionType.In(firstParam) == contextType {
+		b.needsContext = true
+		firstParam++
+	}
+	if inputParamCount > firstParam && functionType.In(firstParam) == streamType {
+		b.needsStream = true
+		firstParam++
+	}
+	if inputParamCount > firstParam {
+		b.inputs = make([]reflect.Type, inputParamCount-firstParam)
 		// We start at 1 as the first param is the struct
-		for index := 0; index < inputParamCount; index++ {
-			param := functionType.In(index)
+		for index := 0; index < len(b.inputs); index++ {
+			param := functionType.In(index + firstParam)
 			name := param.Name()
 			kind := param.Kind()
 			b.inputs[index] = param
@@ -109,11 +126,18 @@ func (b *boundFunction) processParameters() error {
 		return fmt.Errorf("cannot register method '%s' with %d return parameters. Please use up to 2", b.fullName, returnParamsCount)
 	}
 
+	// The values received from a returned channel are streamed
+	if len(b.returnTypes) > 0 {
+		result := b.returnTypes[0]
+		b.returnsChannel = result.Kind() == reflect.Chan && result.ChanDir()&reflect.RecvDir != 0
+	}
+
 	return nil
 }
 
-// call the method with the given data
-func (b *boundFunction) call(data string) ([]reflect.Value, error) {
+// call the function with the given data and the byte slices sent with it, and the
+// context and stream if it takes them
+func (b *boundFunction) call(ctx context.Context, stream *wailsruntime.Stream, data string, binary [][]byte) ([]reflect.Value, error) {
 
 	// The data will be an array of values so we will decode the
 	// input data into
@@ -124,6 +148,10 @@ func (b *boundFunction) call(data string) ([]reflect.Value, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Invalid data passed to method call: %s", err.Error())
 	}
+	err = binaryArgs(jsArgs, binary)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check correct number of inputs
 	if len(jsArgs) != len(b.inputs) {
@@ -141,6 +169,12 @@ func (b *boundFunction) call(data string) ([]reflect.Value, error) {
 		}
 		args[index] = value
 	}
+	if b.needsStream {
+		args = append([]reflect.Value{reflect.ValueOf(stream)}, args...)
+	}
+	if b.needsContext {
+		args = append([]reflect.Value{reflect.ValueOf(ctx)}, args...)
+	}
 	b.log.Debugf("Unmarshalled Args: %+v\n", jsArgs)
 	b.log.Debugf("Converted Args: %+v\n", args)
 	results := b.function.Call(args)
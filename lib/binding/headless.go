@@ -0,0 +1,35 @@
+package binding
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/wailsapp/wails/lib/messages"
+)
+
+// CallHeadless calls the given bound method without a renderer and returns
+// the result as JSON. The method is named as in the frontend, EG:
+// "Counter.Add", and the arguments are a JSON array. WailsInit is not called
+// as the runtime needs the renderer.
+func (b *Manager) CallHeadless(method string, args string) (string, error) {
+	index := strings.LastIndex(method, ".")
+	if index <= 0 {
+		return "", fmt.Errorf("invalid method name '%s'", method)
+	}
+	structName := method[:index]
+	if !strings.Contains(structName, ".") {
+		structName = "main." + structName
+	}
+	err := b.bindWithoutRenderer(structName)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := b.ProcessCall(&messages.CallData{BindingName: structName + method[index:], Data: args})
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(result)
+	return string(data), err
+}
@@ -1,17 +1,37 @@
 package binding
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/wailsapp/wails/lib/logger"
 	"github.com/wailsapp/wails/lib/messages"
+	"github.com/wailsapp/wails/pkg/cli"
 	"github.com/wailsapp/wails/runtime"
 )
 
 type internalMethods struct {
-	log     *logger.CustomLogger
-	browser *runtime.Browser
+	log         *logger.CustomLogger
+	browser     *runtime.Browser
+	support     *runtime.Support
+	permissions *runtime.Permissions
+	dialog      *runtime.Dialog
+	window      *runtime.Window
+	fonts       *runtime.Fonts
+	system      *runtime.System
+	purchases   *runtime.Purchases
+	contextMenu *runtime.ContextMenu
+	share       *runtime.Share
+	calendar    *runtime.Calendar
+	reminders   *runtime.Reminders
+	power       *runtime.Power
+	feedback    *runtime.Feedback
+	touch       *runtime.Touch
+	clipboard   *runtime.Clipboard
+	gamepads    *runtime.Gamepads
+	flags       *cli.Flags
 }
 
 func newInternalMethods() *internalMethods {
@@ -36,6 +56,38 @@ func (i *internalMethods) processCall(callData *messages.CallData) (interface{},
 	switch group {
 	case "Browser":
 		return i.processBrowserCommand(splitCall[1], callData.Data)
+	case "Support":
+		return i.processSupportCommand(splitCall[1], callData.Data)
+	case "Permissions":
+		return i.processPermissionsCommand(splitCall[1], callData.Data)
+	case "Dialog":
+		return i.processDialogCommand(splitCall[1], callData.Data)
+	case "Window":
+		return i.processWindowCommand(splitCall[1], callData.Data)
+	case "Fonts":
+		return i.processFontsCommand(splitCall[1], callData.Data)
+	case "System":
+		return i.processSystemCommand(splitCall[1], callData.Data)
+	case "Purchases":
+		return i.processPurchasesCommand(splitCall[1], callData.Data)
+	case "Menu":
+		return i.processMenuCommand(splitCall[1], callData.Data)
+	case "Share":
+		return i.processShareCommand(splitCall[1], callData.Data)
+	case "Calendar":
+		return i.processCalendarCommand(splitCall[1], callData.Data)
+	case "Reminders":
+		return i.processRemindersCommand(splitCall[1], callData.Data)
+	case "Power":
+		return i.processPowerCommand(splitCall[1], callData.Data)
+	case "Feedback":
+		return i.processFeedbackCommand(splitCall[1], callData.Data)
+	case "Touch":
+		return i.processTouchCommand(splitCall[1], callData.Data)
+	case "Clipboard":
+		return i.processClipboardCommand(splitCall[1], callData.Data)
+	case "Gamepads":
+		return i.processGamepadsCommand(splitCall[1], callData.Data)
 	default:
 		return nil, fmt.Errorf("Unknown internal command group '%s'", group)
 	}
@@ -69,3 +121,600 @@ func (i *internalMethods) processBrowserCommand(command string, data interface{}
 		return nil, fmt.Errorf("Unknown Browser command '%s'", command)
 	}
 }
+
+func (i *internalMethods) processSupportCommand(command string, data interface{}) (interface{}, error) {
+	if i.support == nil {
+		return nil, fmt.Errorf("Support runtime not available")
+	}
+	switch command {
+	case "SystemReport":
+		return i.support.SystemReport(), nil
+	case "ReportBug", "EmailSupport":
+		var report struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		}
+		err := json.Unmarshal([]byte(data.(string)), &report)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Support.%s with '%s'", command, report.Title)
+		if command == "EmailSupport" {
+			return i.support.EmailSupport(report.Title, report.Description)
+		}
+		return nil, i.support.ReportBug(report.Title, report.Description)
+	default:
+		return nil, fmt.Errorf("Unknown Support command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processPermissionsCommand(command string, data interface{}) (interface{}, error) {
+	if i.permissions == nil {
+		return nil, fmt.Errorf("Permissions runtime not available")
+	}
+	var permission runtime.Permission
+	err := json.Unmarshal([]byte(data.(string)), &permission)
+	if err != nil {
+		return nil, err
+	}
+	i.log.Debugf("Calling Permissions.%s with '%s'", command, permission)
+	switch command {
+	case "Status":
+		return i.permissions.Status(permission)
+	case "Request":
+		return i.permissions.Request(permission)
+	case "OpenSettings":
+		return nil, i.permissions.OpenSettings(permission)
+	default:
+		return nil, fmt.Errorf("Unknown Permissions command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processDialogCommand(command string, data interface{}) (interface{}, error) {
+	if i.dialog == nil {
+		return nil, fmt.Errorf("Dialog runtime not available")
+	}
+	switch command {
+	case "ColorPicker":
+		var initial string
+		if raw, ok := data.(string); ok && raw != "" {
+			err := json.Unmarshal([]byte(raw), &initial)
+			if err != nil {
+				return nil, err
+			}
+		}
+		i.log.Debugf("Calling Dialog.ColorPicker with '%s'", initial)
+		return i.dialog.ColorPicker(initial)
+	case "FontPicker":
+		var initial *runtime.Font
+		if raw, ok := data.(string); ok && raw != "" {
+			err := json.Unmarshal([]byte(raw), &initial)
+			if err != nil {
+				return nil, err
+			}
+		}
+		i.log.Debug("Calling Dialog.FontPicker")
+		return i.dialog.FontPicker(initial), nil
+	case "OpenFile":
+		var options runtime.OpenDialogOptions
+		if raw, ok := data.(string); ok && raw != "" {
+			err := json.Unmarshal([]byte(raw), &options)
+			if err != nil {
+				return nil, err
+			}
+		}
+		i.log.Debugf("Calling Dialog.OpenFile with '%s'", options.Title)
+		return i.dialog.OpenFile(&options)
+	case "About":
+		i.log.Debug("Calling Dialog.About")
+		i.dialog.About()
+		return nil, nil
+	case "Message":
+		var options struct {
+			Type    runtime.MessageType `json:"type"`
+			Title   string              `json:"title"`
+			Message string              `json:"message"`
+			Buttons []string            `json:"buttons"`
+		}
+		err := json.Unmarshal([]byte(data.(string)), &options)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Dialog.Message with '%s'", options.Title)
+		return i.dialog.Message(options.Type, options.Title, options.Message, options.Buttons)
+	case "OpenDirectory":
+		var options runtime.DirectoryDialogOptions
+		if raw, ok := data.(string); ok && raw != "" {
+			err := json.Unmarshal([]byte(raw), &options)
+			if err != nil {
+				return nil, err
+			}
+		}
+		i.log.Debugf("Calling Dialog.OpenDirectory with '%s'", options.Title)
+		return i.dialog.OpenDirectory(&options)
+	case "SaveFile":
+		var options runtime.SaveDialogOptions
+		if raw, ok := data.(string); ok && raw != "" {
+			err := json.Unmarshal([]byte(raw), &options)
+			if err != nil {
+				return nil, err
+			}
+		}
+		i.log.Debugf("Calling Dialog.SaveFile with '%s'", options.Title)
+		return i.dialog.SaveFile(&options)
+	default:
+		return nil, fmt.Errorf("Unknown Dialog command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processWindowCommand(command string, data interface{}) (interface{}, error) {
+	if i.window == nil {
+		return nil, fmt.Errorf("Window runtime not available")
+	}
+	switch command {
+	case "ShowEmojiPicker":
+		i.log.Debug("Calling Window.ShowEmojiPicker")
+		i.window.ShowEmojiPicker()
+		return nil, nil
+	case "Fullscreen":
+		i.log.Debug("Calling Window.Fullscreen")
+		i.window.Fullscreen()
+		return nil, nil
+	case "UnFullscreen":
+		i.log.Debug("Calling Window.UnFullscreen")
+		i.window.UnFullscreen()
+		return nil, nil
+	case "Minimise":
+		i.log.Debug("Calling Window.Minimise")
+		i.window.Minimise()
+		return nil, nil
+	case "Maximise":
+		i.log.Debug("Calling Window.Maximise")
+		i.window.Maximise()
+		return nil, nil
+	case "Restore":
+		i.log.Debug("Calling Window.Restore")
+		i.window.Restore()
+		return nil, nil
+	case "SetSystemGestures":
+		var enabled bool
+		err := json.Unmarshal([]byte(data.(string)), &enabled)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Window.SetSystemGestures with %t", enabled)
+		i.window.SetSystemGestures(enabled)
+		return nil, nil
+	case "StartDrag":
+		var files []string
+		err := json.Unmarshal([]byte(data.(string)), &files)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Window.StartDrag with %v", files)
+		return nil, i.window.StartDrag(files...)
+	case "RequestUserAttention":
+		var critical bool
+		if raw, ok := data.(string); ok && raw != "" {
+			err := json.Unmarshal([]byte(raw), &critical)
+			if err != nil {
+				return nil, err
+			}
+		}
+		i.log.Debugf("Calling Window.RequestUserAttention with %t", critical)
+		i.window.RequestUserAttention(critical)
+		return nil, nil
+	case "SetOpacity":
+		var opacity float64
+		err := json.Unmarshal([]byte(data.(string)), &opacity)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Window.SetOpacity with %f", opacity)
+		i.window.SetOpacity(opacity)
+		return nil, nil
+	case "SetProgress":
+		var progress float64
+		err := json.Unmarshal([]byte(data.(string)), &progress)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Window.SetProgress with %f", progress)
+		i.window.SetProgress(progress)
+		return nil, nil
+	case "SetBadge":
+		var badge string
+		err := json.Unmarshal([]byte(data.(string)), &badge)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Window.SetBadge with %s", badge)
+		i.window.SetBadge(badge)
+		return nil, nil
+	case "SetMaterial":
+		var material string
+		err := json.Unmarshal([]byte(data.(string)), &material)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Window.SetMaterial with %s", material)
+		return nil, i.window.SetMaterial(runtime.Material(material))
+	case "Materials":
+		i.log.Debug("Calling Window.Materials")
+		return i.window.Materials(), nil
+	case "Displays":
+		i.log.Debug("Calling Window.Displays")
+		return i.window.Displays(), nil
+	case "Show":
+		i.log.Debug("Calling Window.Show")
+		i.window.Show()
+		return nil, nil
+	case "Hide":
+		i.log.Debug("Calling Window.Hide")
+		i.window.Hide()
+		return nil, nil
+	case "SetMinSize", "SetMaxSize", "SetAspectRatio":
+		var size struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		}
+		err := json.Unmarshal([]byte(data.(string)), &size)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Window.%s with %dx%d", command, size.Width, size.Height)
+		switch command {
+		case "SetMinSize":
+			i.window.SetMinSize(size.Width, size.Height)
+		case "SetMaxSize":
+			i.window.SetMaxSize(size.Width, size.Height)
+		default:
+			i.window.SetAspectRatio(size.Width, size.Height)
+		}
+		return nil, nil
+	case "SetTitle":
+		var title string
+		err := json.Unmarshal([]byte(data.(string)), &title)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debugf("Calling Window.SetTitle with '%s'", title)
+		i.window.SetTitle(title)
+		return nil, nil
+	case "SetIcon":
+		// The icon is sent as base64
+		var icon []byte
+		err := json.Unmarshal([]byte(data.(string)), &icon)
+		if err != nil {
+			return nil, err
+		}
+		i.log.Debug("Calling Window.SetIcon")
+		return nil, i.window.SetIcon(icon)
+	case "PlaceOverlay":
+		var placement struct {
+			ID      string `json:"id"`
+			X       int    `json:"x"`
+			Y       int    `json:"y"`
+			Width   int    `json:"width"`
+			Height  int    `json:"height"`
+			Visible bool   `json:"visible"`
+		}
+		err := json.Unmarshal([]byte(data.(string)), &placement)
+		if err != nil {
+			return nil, err
+		}
+		i.window.PlaceOverlay(placement.ID, placement.X, placement.Y, placement.Width, placement.Height, placement.Visible)
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("Unknown Window command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processFontsCommand(command string, data interface{}) (interface{}, error) {
+	if i.fonts == nil {
+		return nil, fmt.Errorf("Fonts runtime not available")
+	}
+	i.log.Debugf("Calling Fonts.%s", command)
+	switch command {
+	case "List":
+		return i.fonts.List()
+	case "Families":
+		return i.fonts.Families()
+	default:
+		return nil, fmt.Errorf("Unknown Fonts command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processSystemCommand(command string, data interface{}) (interface{}, error) {
+	if i.system == nil {
+		return nil, fmt.Errorf("System runtime not available")
+	}
+	i.log.Debugf("Calling System.%s", command)
+	switch command {
+	case "Locale":
+		return i.system.Locale(), nil
+	case "MachineID":
+		return i.system.MachineID()
+	case "NewID":
+		return i.system.NewID(), nil
+	case "Stats":
+		return i.system.Stats()
+	case "WatchStats":
+		var milliseconds int
+		err := json.Unmarshal([]byte(data.(string)), &milliseconds)
+		if err != nil {
+			return nil, err
+		}
+		i.system.WatchStats(time.Duration(milliseconds) * time.Millisecond)
+		return nil, nil
+	case "StopWatchingStats":
+		i.system.StopWatchingStats()
+		return nil, nil
+	case "Flags":
+		return i.flags, nil
+	case "Installation":
+		return i.system.Installation()
+	case "MoveToApplications":
+		return i.system.MoveToApplications()
+	default:
+		return nil, fmt.Errorf("Unknown System command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processPurchasesCommand(command string, data interface{}) (interface{}, error) {
+	if i.purchases == nil {
+		return nil, fmt.Errorf("Purchases runtime not available")
+	}
+	i.log.Debugf("Calling Purchases.%s", command)
+	switch command {
+	case "CanMakePayments":
+		return i.purchases.CanMakePayments(), nil
+	case "Products":
+		var ids []string
+		err := json.Unmarshal([]byte(data.(string)), &ids)
+		if err != nil {
+			return nil, err
+		}
+		return i.purchases.Products(ids...)
+	case "Purchase":
+		var productID string
+		err := json.Unmarshal([]byte(data.(string)), &productID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, i.purchases.Purchase(productID)
+	case "Restore":
+		return nil, i.purchases.Restore()
+	default:
+		return nil, fmt.Errorf("Unknown Purchases command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processMenuCommand(command string, data interface{}) (interface{}, error) {
+	if i.contextMenu == nil {
+		return nil, fmt.Errorf("Menu runtime not available")
+	}
+	i.log.Debugf("Calling Menu.%s", command)
+	switch command {
+	case "ShowContextMenu":
+		var request struct {
+			Menu []*runtime.ContextMenuItem `json:"menu"`
+			X    int                        `json:"x"`
+			Y    int                        `json:"y"`
+		}
+		err := json.Unmarshal([]byte(data.(string)), &request)
+		if err != nil {
+			return nil, err
+		}
+		i.contextMenu.Show(request.Menu, request.X, request.Y)
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("Unknown Menu command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processShareCommand(command string, data interface{}) (interface{}, error) {
+	if i.share == nil {
+		return nil, fmt.Errorf("Share runtime not available")
+	}
+	i.log.Debugf("Calling Share.%s", command)
+	switch command {
+	case "Pending":
+		return i.share.Pending(), nil
+	default:
+		return nil, fmt.Errorf("Unknown Share command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processCalendarCommand(command string, data interface{}) (interface{}, error) {
+	if i.calendar == nil {
+		return nil, fmt.Errorf("Calendar runtime not available")
+	}
+	i.log.Debugf("Calling Calendar.%s", command)
+	switch command {
+	case "Status":
+		return i.calendar.Status(), nil
+	case "Request":
+		return i.calendar.Request()
+	case "Events":
+		var period struct {
+			Start time.Time `json:"start"`
+			End   time.Time `json:"end"`
+		}
+		err := json.Unmarshal([]byte(data.(string)), &period)
+		if err != nil {
+			return nil, err
+		}
+		return i.calendar.Events(period.Start, period.End)
+	case "SaveEvent":
+		var event runtime.CalendarEvent
+		err := json.Unmarshal([]byte(data.(string)), &event)
+		if err != nil {
+			return nil, err
+		}
+		return i.calendar.SaveEvent(&event)
+	case "DeleteEvent":
+		var id string
+		err := json.Unmarshal([]byte(data.(string)), &id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, i.calendar.DeleteEvent(id)
+	default:
+		return nil, fmt.Errorf("Unknown Calendar command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processRemindersCommand(command string, data interface{}) (interface{}, error) {
+	if i.reminders == nil {
+		return nil, fmt.Errorf("Reminders runtime not available")
+	}
+	i.log.Debugf("Calling Reminders.%s", command)
+	switch command {
+	case "Status":
+		return i.reminders.Status(), nil
+	case "Request":
+		return i.reminders.Request()
+	case "List":
+		var includeCompleted bool
+		err := json.Unmarshal([]byte(data.(string)), &includeCompleted)
+		if err != nil {
+			return nil, err
+		}
+		return i.reminders.List(includeCompleted)
+	case "Save":
+		var reminder runtime.Reminder
+		err := json.Unmarshal([]byte(data.(string)), &reminder)
+		if err != nil {
+			return nil, err
+		}
+		return i.reminders.Save(&reminder)
+	case "Delete":
+		var id string
+		err := json.Unmarshal([]byte(data.(string)), &id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, i.reminders.Delete(id)
+	default:
+		return nil, fmt.Errorf("Unknown Reminders command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processPowerCommand(command string, data interface{}) (interface{}, error) {
+	if i.power == nil {
+		return nil, fmt.Errorf("Power runtime not available")
+	}
+	i.log.Debugf("Calling Power.%s", command)
+	switch command {
+	case "Status":
+		return i.power.Status()
+	case "Watch":
+		i.power.Watch()
+		return nil, nil
+	case "StopWatching":
+		i.power.StopWatching()
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("Unknown Power command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processFeedbackCommand(command string, data interface{}) (interface{}, error) {
+	if i.feedback == nil {
+		return nil, fmt.Errorf("Feedback runtime not available")
+	}
+	i.log.Debugf("Calling Feedback.%s", command)
+	switch command {
+	case "Beep":
+		var kind runtime.BeepKind
+		if raw, ok := data.(string); ok && raw != "" {
+			err := json.Unmarshal([]byte(raw), &kind)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, i.feedback.Beep(kind)
+	case "Haptic":
+		var pattern runtime.HapticPattern
+		if raw, ok := data.(string); ok && raw != "" {
+			err := json.Unmarshal([]byte(raw), &pattern)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, i.feedback.Haptic(pattern)
+	default:
+		return nil, fmt.Errorf("Unknown Feedback command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processTouchCommand(command string, data interface{}) (interface{}, error) {
+	if i.touch == nil {
+		return nil, fmt.Errorf("Touch runtime not available")
+	}
+	i.log.Debugf("Calling Touch.%s", command)
+	switch command {
+	case "Status":
+		return i.touch.Status()
+	case "Watch":
+		i.touch.Watch()
+		return nil, nil
+	case "StopWatching":
+		i.touch.StopWatching()
+		return nil, nil
+	case "ShowKeyboard":
+		return nil, i.touch.ShowKeyboard()
+	default:
+		return nil, fmt.Errorf("Unknown Touch command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processClipboardCommand(command string, data interface{}) (interface{}, error) {
+	if i.clipboard == nil {
+		return nil, fmt.Errorf("Clipboard runtime not available")
+	}
+	i.log.Debugf("Calling Clipboard.%s", command)
+	switch command {
+	case "GetText":
+		return i.clipboard.GetText()
+	case "SetText":
+		var text string
+		err := json.Unmarshal([]byte(data.(string)), &text)
+		if err != nil {
+			return nil, err
+		}
+		return nil, i.clipboard.SetText(text)
+	case "GetImage":
+		// The image is sent as base64
+		return i.clipboard.GetImage()
+	case "SetImage":
+		var image []byte
+		err := json.Unmarshal([]byte(data.(string)), &image)
+		if err != nil {
+			return nil, err
+		}
+		return nil, i.clipboard.SetImage(image)
+	default:
+		return nil, fmt.Errorf("Unknown Clipboard command '%s'", command)
+	}
+}
+
+func (i *internalMethods) processGamepadsCommand(command string, data interface{}) (interface{}, error) {
+	if i.gamepads == nil {
+		return nil, fmt.Errorf("Gamepads runtime not available")
+	}
+	i.log.Debugf("Calling Gamepads.%s", command)
+	switch command {
+	case "List":
+		return i.gamepads.List()
+	case "Watch":
+		i.gamepads.Watch()
+		return nil, nil
+	case "StopWatching":
+		i.gamepads.StopWatching()
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("Unknown Gamepads command '%s'", command)
+	}
+}
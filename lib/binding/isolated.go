@@ -0,0 +1,331 @@
+package binding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/lib/logger"
+	"github.com/wailsapp/wails/lib/messages"
+)
+
+// IsolatedServiceEnv is set to the name of the bound struct when the app is
+// started to host an isolated service
+const IsolatedServiceEnv = "WAILS_ISOLATED_SERVICE"
+
+// isolatedRequest is a method call sent to an isolated service, or the
+// cancellation of the call with the same ID
+type isolatedRequest struct {
+	ID     uint64 `json:"id"`
+	Method string `json:"method,omitempty"`
+	Data   string `json:"data,omitempty"`
+	Cancel bool   `json:"cancel,omitempty"`
+
+	// Set if the results the method streams are sent as they are made,
+	// rather than returned as its result
+	Stream bool `json:"stream,omitempty"`
+
+	// The byte slices sent with the call
+	Binary [][]byte `json:"binary,omitempty"`
+}
+
+// isolatedResponse is the result of a method call sent from an isolated
+// service, or a result it streamed before it returns
+type isolatedResponse struct {
+	ID       uint64          `json:"id"`
+	Result   json.RawMessage `json:"result,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Streamed bool            `json:"streamed,omitempty"`
+
+	// Set if the result is a byte slice, so it is returned as one
+	Binary bool `json:"binary,omitempty"`
+}
+
+// isolatedService proxies method calls to a bound struct hosted in a child
+// process, so a panic or runaway memory use in the struct cannot take down
+// the app. The child is a copy of the app started with IsolatedServiceEnv set.
+// If it exits, pending calls fail and it is restarted on the next call.
+type isolatedService struct {
+	name string
+	log  *logger.CustomLogger
+
+	lock    sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	encoder *json.Encoder
+	pending map[uint64]chan *isolatedResponse
+	streams map[uint64]func(data interface{}) error
+	nextID  uint64
+}
+
+func newIsolatedService(name string) *isolatedService {
+	return &isolatedService{
+		name: name,
+		log:  logger.NewCustomLogger("Isolated:" + name),
+	}
+}
+
+// start launches the child process. The lock must be held.
+func (s *isolatedService) start() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(executable)
+	cmd.Env = append(os.Environ(), IsolatedServiceEnv+"="+s.name)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	s.log.Debugf("Started service process %d", cmd.Process.Pid)
+
+	s.cmd = cmd
+	s.stdin = stdin
+	s.encoder = json.NewEncoder(stdin)
+	s.pending = make(map[uint64]chan *isolatedResponse)
+	s.streams = make(map[uint64]func(data interface{}) error)
+	go s.readResponses(cmd, stdout)
+	return nil
+}
+
+// readResponses passes responses to the waiting calls until the child exits
+func (s *isolatedService) readResponses(cmd *exec.Cmd, stdout io.Reader) {
+	decoder := json.NewDecoder(stdout)
+	for {
+		var response isolatedResponse
+		err := decoder.Decode(&response)
+		if err != nil {
+			break
+		}
+		s.lock.Lock()
+		if response.Streamed {
+			stream := s.streams[response.ID]
+			s.lock.Unlock()
+			if stream != nil {
+				stream(response.Result)
+			}
+			continue
+		}
+		result := s.pending[response.ID]
+		delete(s.pending, response.ID)
+		delete(s.streams, response.ID)
+		s.lock.Unlock()
+		if result != nil {
+			result <- &response
+		}
+	}
+
+	err := cmd.Wait()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.cmd != cmd {
+		return
+	}
+	if err != nil {
+		s.log.Errorf("Service stopped unexpectedly: %s", err.Error())
+	}
+	for id, result := range s.pending {
+		result <- &isolatedResponse{ID: id, Error: fmt.Sprintf("isolated service '%s' stopped unexpectedly", s.name)}
+	}
+	s.cmd = nil
+	s.pending = nil
+	s.streams = nil
+}
+
+// call makes the given call in the child process, starting it if needed.
+// The result is returned as JSON, unless it is a byte slice, and the results
+// it streams are sent as JSON with the given function, if set. If the context
+// is cancelled, the call is cancelled in the child, which still returns its
+// result.
+func (s *isolatedService) call(ctx context.Context, callData *messages.CallData, stream func(data interface{}) error) (interface{}, error) {
+	s.lock.Lock()
+	if s.cmd == nil {
+		err := s.start()
+		if err != nil {
+			s.lock.Unlock()
+			return nil, fmt.Errorf("unable to start isolated service '%s': %s", s.name, err.Error())
+		}
+	}
+	s.nextID++
+	id := s.nextID
+	result := make(chan *isolatedResponse, 1)
+	s.pending[id] = result
+	if stream != nil {
+		s.streams[id] = stream
+	}
+	err := s.encoder.Encode(&isolatedRequest{
+		ID:     id,
+		Method: callData.BindingName,
+		Data:   callData.Data,
+		Stream: stream != nil,
+		Binary: callData.Binary,
+	})
+	if err != nil {
+		delete(s.pending, id)
+		delete(s.streams, id)
+		s.lock.Unlock()
+		return nil, err
+	}
+	s.lock.Unlock()
+
+	var response *isolatedResponse
+	select {
+	case response = <-result:
+	case <-ctx.Done():
+		s.lock.Lock()
+		if s.pending[id] != nil {
+			s.encoder.Encode(&isolatedRequest{ID: id, Cancel: true})
+		}
+		s.lock.Unlock()
+		response = <-result
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s", response.Error)
+	}
+	if response.Binary {
+		var data []byte
+		err = json.Unmarshal(response.Result, &data)
+		return data, err
+	}
+	return response.Result, nil
+}
+
+// stop closes the child's input, which it exits on
+func (s *isolatedService) stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.cmd != nil {
+		s.stdin.Close()
+	}
+}
+
+// bindWithoutRenderer binds the methods of the named struct without informing
+// the renderer, for when there isn't one
+func (b *Manager) bindWithoutRenderer(name string) error {
+	err := b.resolveConstructors()
+	if err != nil {
+		return err
+	}
+
+	var object interface{}
+	for _, candidate := range b.objectsToBind {
+		if strings.TrimPrefix(reflect.TypeOf(candidate).String(), "*") == name {
+			object = candidate
+			break
+		}
+	}
+	if object == nil {
+		return fmt.Errorf("no bound struct named '%s'", name)
+	}
+
+	methods, err := b.structMethods(object, name)
+	if err != nil {
+		return err
+	}
+	for _, method := range methods {
+		// As in the app, WailsInit and WailsShutdown aren't
+		// called for isolated structs
+		if method.isWailsInit || method.isWailsShutdown {
+			continue
+		}
+		b.methods[method.fullName] = method
+	}
+	return nil
+}
+
+// ServeIsolated hosts the bound struct with the given name, handling the
+// method calls sent by the app on in and writing the results to out.
+// It returns when in is closed.
+func (b *Manager) ServeIsolated(name string, in io.Reader, out io.Writer) error {
+	err := b.bindWithoutRenderer(name)
+	if err != nil {
+		return err
+	}
+	b.log.Infof("Serving isolated service %s", name)
+
+	var writeLock sync.Mutex
+	encoder := json.NewEncoder(out)
+	decoder := json.NewDecoder(in)
+
+	// Cancels the calls being processed, by ID
+	var callsLock sync.Mutex
+	calls := make(map[uint64]context.CancelFunc)
+	for {
+		var request isolatedRequest
+		err := decoder.Decode(&request)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		callsLock.Lock()
+		if request.Cancel {
+			if cancel := calls[request.ID]; cancel != nil {
+				cancel()
+			}
+			callsLock.Unlock()
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		calls[request.ID] = cancel
+		callsLock.Unlock()
+		go func() {
+			defer func() {
+				callsLock.Lock()
+				delete(calls, request.ID)
+				callsLock.Unlock()
+				cancel()
+			}()
+			// Send the results the method streams as they are made
+			var stream func(data interface{}) error
+			if request.Stream {
+				stream = func(data interface{}) error {
+					result, err := json.Marshal(data)
+					if err != nil {
+						return err
+					}
+					writeLock.Lock()
+					defer writeLock.Unlock()
+					return encoder.Encode(&isolatedResponse{ID: request.ID, Result: result, Streamed: true})
+				}
+			}
+			response := &isolatedResponse{ID: request.ID}
+			result, err := b.ProcessCall(&messages.CallData{
+				BindingName: request.Method,
+				Data:        request.Data,
+				Context:     ctx,
+				Stream:      stream,
+				Binary:      request.Binary,
+			})
+			if err == nil {
+				_, response.Binary = result.([]byte)
+				response.Result, err = json.Marshal(result)
+			}
+			if err != nil {
+				response.Error = err.Error()
+			}
+			writeLock.Lock()
+			defer writeLock.Unlock()
+			err = encoder.Encode(response)
+			if err != nil {
+				b.log.Errorf("Unable to send result of %s: %s", request.Method, err.Error())
+			}
+		}()
+	}
+}
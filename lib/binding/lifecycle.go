@@ -0,0 +1,85 @@
+package binding
+
+import "context"
+
+// Bound structs can implement any of these to be told about the lifecycle
+// of the app. The hooks are called in the order the structs were bound, with
+// the structs made by constructors after their dependencies, so a struct can
+// rely on those before it, except OnShutdown which is called in the reverse
+// order. The hooks aren't bound to the frontend.
+
+// startupHook is called once the bindings are set up. Returning an error
+// stops the app from starting.
+type startupHook interface {
+	OnStartup(ctx context.Context) error
+}
+
+// domReadyHook is called each time the frontend has loaded
+type domReadyHook interface {
+	OnDomReady(ctx context.Context)
+}
+
+// shutdownHook is called when the app or window is shutting down
+type shutdownHook interface {
+	OnShutdown(ctx context.Context)
+}
+
+// isLifecycleHook returns true if the named method is one
+// of the lifecycle hooks implemented by the object
+func isLifecycleHook(object interface{}, methodName string) bool {
+	var ok bool
+	switch methodName {
+	case "OnStartup":
+		_, ok = object.(startupHook)
+	case "OnDomReady":
+		_, ok = object.(domReadyHook)
+	case "OnShutdown":
+		_, ok = object.(shutdownHook)
+	}
+	return ok
+}
+
+// hasLifecycleHooks returns true if the object implements any of the hooks
+func hasLifecycleHooks(object interface{}) bool {
+	switch object.(type) {
+	case startupHook, domReadyHook, shutdownHook:
+		return true
+	}
+	return false
+}
+
+// callStartupHooks calls OnStartup in the order the structs were bound
+func (b *Manager) callStartupHooks() error {
+	for _, object := range b.hookedObjects {
+		if hook, ok := object.(startupHook); ok {
+			b.log.Debugf("Calling OnStartup for %T", object)
+			err := hook.OnStartup(b.ctx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// callDomReadyHooks calls OnDomReady in the order the structs were bound
+func (b *Manager) callDomReadyHooks() {
+	for _, object := range b.hookedObjects {
+		if hook, ok := object.(domReadyHook); ok {
+			b.log.Debugf("Calling OnDomReady for %T", object)
+			hook.OnDomReady(b.ctx)
+		}
+	}
+}
+
+// callShutdownHooks calls OnShutdown in the reverse order
+// the structs were bound
+func (b *Manager) callShutdownHooks() {
+	for i := len(b.hookedObjects) - 1; i >= 0; i-- {
+		object := b.hookedObjects[i]
+		if hook, ok := object.(shutdownHook); ok {
+			b.log.Debugf("Calling OnShutdown for %T", object)
+			hook.OnShutdown(b.ctx)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package binding
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type hookedService struct {
+	name  string
+	calls *[]string
+}
+
+func (s *hookedService) OnStartup(ctx context.Context) error {
+	*s.calls = append(*s.calls, s.name+".OnStartup")
+	return nil
+}
+
+func (s *hookedService) OnShutdown(ctx context.Context) {
+	*s.calls = append(*s.calls, s.name+".OnShutdown")
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	var calls []string
+	manager := NewManager().(*Manager)
+	manager.Bind(&hookedService{name: "db", calls: &calls})
+	manager.Bind(&hookedService{name: "cache", calls: &calls})
+	err := manager.initialise()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manager.methods) != 0 {
+		t.Errorf("expected the hooks not to be bound, got %d methods", len(manager.methods))
+	}
+
+	err = manager.callStartupHooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager.Shutdown()
+
+	expected := []string{"db.OnStartup", "cache.OnStartup", "cache.OnShutdown", "db.OnShutdown"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+	if manager.ctx.Err() == nil {
+		t.Errorf("expected the context to be cancelled after shutdown")
+	}
+}
+
+type hookedQueryService struct {
+	hookedService
+}
+
+func (s *hookedQueryService) Query() string {
+	return s.name
+}
+
+func TestIsolatedLifecycleHooks(t *testing.T) {
+	var calls []string
+	manager := NewManager().(*Manager)
+	manager.Bind(&hookedQueryService{hookedService{name: "db", calls: &calls}})
+	err := manager.bindWithoutRenderer("binding.hookedQueryService")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bound []string
+	for name := range manager.methods {
+		bound = append(bound, name)
+	}
+	if expected := []string{"binding.hookedQueryService.Query"}; !reflect.DeepEqual(bound, expected) {
+		t.Errorf("expected the isolated service to bind %v, got %v", expected, bound)
+	}
+}
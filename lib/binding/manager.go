@@ -1,17 +1,21 @@
 package binding
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"unicode"
 
 	"github.com/wailsapp/wails/lib/interfaces"
 	"github.com/wailsapp/wails/lib/logger"
 	"github.com/wailsapp/wails/lib/messages"
+	wailsruntime "github.com/wailsapp/wails/runtime"
 )
 
 var typescriptDefinitionFilename = ""
@@ -20,6 +24,7 @@ var typescriptDefinitionFilename = ""
 type Manager struct {
 	methods          map[string]*boundMethod
 	functions        map[string]*boundFunction
+	scriptable       map[string]*boundMethod // The methods exposed to automation, by their name without the package
 	internalMethods  *internalMethods
 	initMethods      []*boundMethod
 	shutdownMethods  []*boundMethod
@@ -27,8 +32,14 @@ type Manager struct {
 	renderer         interfaces.Renderer
 	runtime          interfaces.Runtime // The runtime object to pass to bound structs
 	objectsToBind    []interface{}
-	bindPackageNames bool                // Package name should be considered when binding
-	structList       map[string][]string // structList["mystruct"] = []string{"Method1", "Method2"}
+	bindOptions      []*interfaces.BindOptions // The options for each of objectsToBind
+	bindPackageNames bool                      // Package name should be considered when binding
+	isolatedServices []*isolatedService
+	hookedObjects    []interface{}      // The bound structs with lifecycle hooks, in the order they were bound
+	ctx              context.Context    // The context given to the lifecycle hooks
+	cancel           context.CancelFunc // Cancels ctx once the hooks have shut down
+	constructed      bool               // The constructors in objectsToBind have been replaced by the structs they made
+	binaryResults    bool               // Results that are byte slices are sent to the frontend as binary
 }
 
 // NewManager creates a new Manager struct
@@ -37,13 +48,20 @@ func NewManager() interfaces.BindingManager {
 	result := &Manager{
 		methods:         make(map[string]*boundMethod),
 		functions:       make(map[string]*boundFunction),
+		scriptable:      make(map[string]*boundMethod),
 		log:             logger.NewCustomLogger("Bind"),
 		internalMethods: newInternalMethods(),
-		structList:      make(map[string][]string),
 	}
+	result.ctx, result.cancel = context.WithCancel(context.Background())
 	return result
 }
 
+// SetBinaryResults sets whether the results that are byte slices are sent to
+// the frontend as binary, which the API spec describes
+func (b *Manager) SetBinaryResults(enabled bool) {
+	b.binaryResults = enabled
+}
+
 // BindPackageNames sets a flag to indicate package names should be considered when binding
 func (b *Manager) BindPackageNames() {
 	b.bindPackageNames = true
@@ -54,13 +72,47 @@ func (b *Manager) Start(renderer interfaces.Renderer, runtime interfaces.Runtime
 	b.log.Info("Starting")
 	b.renderer = renderer
 	b.runtime = runtime
+	if rt, ok := runtime.(*wailsruntime.Runtime); ok {
+		b.internalMethods.support = rt.Support
+		b.internalMethods.permissions = rt.Permissions
+		b.internalMethods.dialog = rt.Dialog
+		b.internalMethods.window = rt.Window
+		b.internalMethods.fonts = rt.Fonts
+		b.internalMethods.system = rt.System
+		b.internalMethods.purchases = rt.Purchases
+		b.internalMethods.contextMenu = rt.ContextMenu
+		b.internalMethods.share = rt.Share
+		b.internalMethods.calendar = rt.Calendar
+		b.internalMethods.reminders = rt.Reminders
+		b.internalMethods.power = rt.Power
+		b.internalMethods.feedback = rt.Feedback
+		b.internalMethods.touch = rt.Touch
+		b.internalMethods.clipboard = rt.Clipboard
+		b.internalMethods.gamepads = rt.Gamepads
+		b.internalMethods.flags = rt.Flags
+		b.ctx = wailsruntime.NewContext(b.ctx, rt)
+	}
 	err := b.initialise()
 	if err != nil {
 		b.log.Errorf("Binding error: %s", err.Error())
 		return err
 	}
 	err = b.callWailsInitMethods()
-	return err
+	if err != nil {
+		return err
+	}
+	err = b.callStartupHooks()
+	if err != nil {
+		return err
+	}
+
+	// Tell the structs each time the frontend has loaded
+	if rt, ok := runtime.(*wailsruntime.Runtime); ok && len(b.hookedObjects) > 0 {
+		rt.Events.On("wails:ready", func(...interface{}) {
+			b.callDomReadyHooks()
+		})
+	}
+	return nil
 }
 
 func (b *Manager) initialise() error {
@@ -70,8 +122,14 @@ func (b *Manager) initialise() error {
 
 	b.log.Info("Binding Go Functions/Methods")
 
+	// Make the structs bound with constructors
+	err = b.resolveConstructors()
+	if err != nil {
+		return err
+	}
+
 	// Create bindings for objects
-	for _, object := range b.objectsToBind {
+	for index, object := range b.objectsToBind {
 
 		// Safeguard against nils
 		if object == nil {
@@ -84,7 +142,7 @@ func (b *Manager) initialise() error {
 
 		switch objectKind {
 		case reflect.Ptr:
-			err = b.bindMethod(object)
+			err = b.bindMethod(object, b.bindOptions[index])
 		case reflect.Func:
 			// spew.Dump(result.objectType.String())
 			err = b.bindFunction(object)
@@ -108,63 +166,38 @@ func (b *Manager) initialise() error {
 	return nil
 }
 
-// Generate typescript
+// generateTypescriptDefinitions writes the Typescript definitions of the
+// bound methods, and a JS module wrapping them next to them, EG: backend.js
+// for backend.d.ts
 func (b *Manager) generateTypescriptDefinitions() error {
-
-	var output strings.Builder
-
-	for structname, methodList := range b.structList {
-		structname = strings.SplitN(structname, ".", 2)[1]
-		output.WriteString(fmt.Sprintf("interface %s {\n", structname))
-		for _, method := range methodList {
-			output.WriteString(fmt.Sprintf("\t%s(...args : any[]):Promise<any>\n", method))
-		}
-		output.WriteString("}\n")
-	}
-
-	output.WriteString("\n")
-	output.WriteString("interface Backend {\n")
-
-	for structname := range b.structList {
-		structname = strings.SplitN(structname, ".", 2)[1]
-		output.WriteString(fmt.Sprintf("\t%[1]s: %[1]s\n", structname))
+	spec, err := b.APISpec()
+	if err != nil {
+		return err
 	}
-	output.WriteString("}\n")
 
-	globals := `
-declare global {
-	interface Window {
-		backend: Backend;
+	dir := filepath.Dir(typescriptDefinitionFilename)
+	os.MkdirAll(dir, 0755)
+	err = ioutil.WriteFile(typescriptDefinitionFilename, spec.Typescript(), 0644)
+	if err != nil {
+		return err
 	}
-}
-export {};`
-	output.WriteString(globals)
-
 	b.log.Info("Written Typescript file: " + typescriptDefinitionFilename)
 
-	dir := filepath.Dir(typescriptDefinitionFilename)
-	os.MkdirAll(dir, 0755)
-	return ioutil.WriteFile(typescriptDefinitionFilename, []byte(output.String()), 0755)
+	base := strings.TrimSuffix(strings.TrimSuffix(typescriptDefinitionFilename, ".ts"), ".d")
+	moduleFilename := base + ".js"
+	err = ioutil.WriteFile(moduleFilename, spec.Module(), 0644)
+	if err != nil {
+		return err
+	}
+	b.log.Info("Written JS module: " + moduleFilename)
+	return nil
 }
 
-// bind the given struct method
-func (b *Manager) bindMethod(object interface{}) error {
-
+// structMethods returns a boundMethod for each of the exported methods of
+// the given struct, skipping its lifecycle hooks
+func (b *Manager) structMethods(object interface{}, baseName string) ([]*boundMethod, error) {
+	var result []*boundMethod
 	objectType := reflect.TypeOf(object)
-	baseName := objectType.String()
-
-	// Strip pointer if there
-	if baseName[0] == '*' {
-		baseName = baseName[1:]
-	}
-
-	b.log.Debugf("Processing struct: %s", baseName)
-
-	// Calc actual name
-	actualName := strings.TrimPrefix(baseName, "main.")
-	if b.structList[actualName] == nil {
-		b.structList[actualName] = []string{}
-	}
 
 	// Iterate over method definitions
 	for i := 0; i < objectType.NumMethod(); i++ {
@@ -175,21 +208,68 @@ func (b *Manager) bindMethod(object interface{}) error {
 		fullMethodName := baseName + "." + methodName
 		method := reflect.ValueOf(object).MethodByName(methodName)
 
-		b.structList[actualName] = append(b.structList[actualName], methodName)
-
 		// Skip unexported methods
 		if !unicode.IsUpper([]rune(methodName)[0]) {
 			continue
 		}
 
+		// Skip lifecycle hooks
+		if isLifecycleHook(object, methodName) {
+			b.log.Debugf("Detected %s hook: %s", methodName, fullMethodName)
+			continue
+		}
+
 		// Create a new boundMethod
 		newMethod, err := newBoundMethod(methodName, fullMethodName, method, objectType)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		result = append(result, newMethod)
+	}
+	return result, nil
+}
 
-		// Check if it's a wails init function
-		if newMethod.isWailsInit {
+// bind the given struct method
+func (b *Manager) bindMethod(object interface{}, options *interfaces.BindOptions) error {
+
+	objectType := reflect.TypeOf(object)
+	baseName := objectType.String()
+
+	// Strip pointer if there
+	if baseName[0] == '*' {
+		baseName = baseName[1:]
+	}
+
+	b.log.Debugf("Processing struct: %s", baseName)
+
+	var service *isolatedService
+	if options.Isolated {
+		service = newIsolatedService(baseName)
+		b.isolatedServices = append(b.isolatedServices, service)
+	}
+
+	// Calc actual name
+	actualName := strings.TrimPrefix(baseName, "main.")
+
+	// Lifecycle hooks aren't called for isolated structs
+	// for the same reason as WailsInit
+	if service == nil && hasLifecycleHooks(object) {
+		b.hookedObjects = append(b.hookedObjects, object)
+	}
+
+	methods, err := b.structMethods(object, baseName)
+	if err != nil {
+		return err
+	}
+	for _, newMethod := range methods {
+		fullMethodName := newMethod.fullName
+
+		// Check if it's a wails init function. They aren't
+		// called for isolated structs as the runtime is only
+		// available in the app's process.
+		if service != nil && (newMethod.isWailsInit || newMethod.isWailsShutdown) {
+			b.log.Debugf("Skipping %s for isolated struct", fullMethodName)
+		} else if newMethod.isWailsInit {
 			b.log.Debugf("Detected WailsInit function: %s", fullMethodName)
 			b.initMethods = append(b.initMethods, newMethod)
 		} else if newMethod.isWailsShutdown {
@@ -198,6 +278,8 @@ func (b *Manager) bindMethod(object interface{}) error {
 		} else {
 			// Save boundMethod
 			b.log.Infof("Bound Method: %s()", fullMethodName)
+			newMethod.service = service
+			newMethod.maxPayloadSize = options.MaxPayloadSize
 			b.methods[fullMethodName] = newMethod
 
 			// Inform renderer of new binding
@@ -205,6 +287,37 @@ func (b *Manager) bindMethod(object interface{}) error {
 		}
 	}
 
+	// Mark the methods that must be called on the main thread
+	for _, methodName := range options.MainThreadMethods {
+		method := b.methods[baseName+"."+methodName]
+		if method == nil {
+			return fmt.Errorf("cannot run unknown method '%s.%s' on the main thread", baseName, methodName)
+		}
+		b.log.Debugf("Method %s() will be called on the main thread", method.fullName)
+		method.mainThread = true
+	}
+
+	// Expose the methods to automation
+	if options.Scriptable {
+		methodNames := options.ScriptableMethods
+		if len(methodNames) == 0 {
+			for i := 0; i < objectType.NumMethod(); i++ {
+				method := b.methods[baseName+"."+objectType.Method(i).Name]
+				if method != nil {
+					methodNames = append(methodNames, method.Name)
+				}
+			}
+		}
+		for _, methodName := range methodNames {
+			method := b.methods[baseName+"."+methodName]
+			if method == nil {
+				return fmt.Errorf("cannot expose unknown method '%s.%s' to automation", baseName, methodName)
+			}
+			b.log.Debugf("Method %s() is scriptable", method.fullName)
+			b.scriptable[actualName+"."+methodName] = method
+		}
+	}
+
 	return nil
 }
 
@@ -227,9 +340,71 @@ func (b *Manager) bindFunction(object interface{}) error {
 }
 
 // Bind saves the given object to be bound at start time
-func (b *Manager) Bind(object interface{}) {
+func (b *Manager) Bind(object interface{}, options ...interfaces.BindOption) {
+	bindOptions := &interfaces.BindOptions{}
+	for _, option := range options {
+		option(bindOptions)
+	}
+
 	// Store binding
 	b.objectsToBind = append(b.objectsToBind, object)
+	b.bindOptions = append(b.bindOptions, bindOptions)
+}
+
+// mainThreadResult is the result of a method called on the main thread
+type mainThreadResult struct {
+	result []reflect.Value
+	err    error
+}
+
+// callOnMainThread calls the method on the main thread with the renderer's
+// Dispatch and waits for the result. The main thread runs the dispatched
+// calls in the order they were made. It is blocked while the method runs,
+// so the method must not wait for the main thread itself, EG: by showing a
+// dialog, using the window's getters or calling Window.OnMainThread, as the
+// app would deadlock. Calls that are cancelled, or made as the app shuts
+// down, before the main thread gets to them aren't run.
+func (b *Manager) callOnMainThread(ctx context.Context, stream *wailsruntime.Stream, method *boundMethod, data string, binary [][]byte) ([]reflect.Value, error) {
+	done := make(chan mainThreadResult, 1)
+	b.renderer.Dispatch(func() {
+		var call mainThreadResult
+		defer func() {
+			// Panics must not reach the main thread's event loop
+			if r := recover(); r != nil {
+				call.err = fmt.Errorf("%v", r)
+			}
+			done <- call
+		}()
+		if call.err = ctx.Err(); call.err == nil {
+			call.result, call.err = method.call(ctx, stream, data, binary)
+		}
+	})
+	select {
+	case call := <-done:
+		return call.result, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// callContext returns the context bound methods are called with, which
+// carries the runtime like the context given to the lifecycle hooks. It is
+// cancelled when the frontend cancels the call, the app shuts down or the
+// returned function is called, which must be once the call returns.
+func (b *Manager) callContext(callData *messages.CallData) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(b.ctx)
+	if callData.Context != nil && callData.Context.Err() != nil {
+		cancel()
+	} else if callData.Context != nil {
+		go func() {
+			select {
+			case <-callData.Context.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ctx, cancel
 }
 
 func (b *Manager) processInternalCall(callData *messages.CallData) (interface{}, error) {
@@ -246,7 +421,15 @@ func (b *Manager) processFunctionCall(callData *messages.CallData) (interface{},
 	if function == nil {
 		return nil, fmt.Errorf("Invalid function name '%s'", callData.BindingName)
 	}
-	result, err = function.call(callData.Data)
+	ctx, cancel := b.callContext(callData)
+	defer cancel()
+	var stream *callStream
+	var runtimeStream *wailsruntime.Stream
+	if function.needsStream || function.returnsChannel {
+		stream = newCallStream(ctx, callData)
+		runtimeStream = stream.stream
+	}
+	result, err = function.call(ctx, runtimeStream, callData.Data, callData.Binary)
 	if err != nil {
 		return nil, err
 	}
@@ -264,10 +447,7 @@ func (b *Manager) processFunctionCall(callData *messages.CallData) (interface{},
 		}
 	}
 	// fmt.Printf("result = '%+v'\n", result)
-	if len(result) > 0 {
-		return result[0].Interface(), nil
-	}
-	return nil, nil
+	return callResult(ctx, stream, function.returnsChannel, result)
 }
 
 func (b *Manager) processMethodCall(callData *messages.CallData) (interface{}, error) {
@@ -281,7 +461,24 @@ func (b *Manager) processMethodCall(callData *messages.CallData) (interface{}, e
 		return nil, fmt.Errorf("Invalid method name '%s'", callData.BindingName)
 	}
 
-	result, err = method.call(callData.Data)
+	ctx, cancel := b.callContext(callData)
+	defer cancel()
+
+	if method.service != nil {
+		return method.service.call(ctx, callData, callData.Stream)
+	}
+
+	var stream *callStream
+	var runtimeStream *wailsruntime.Stream
+	if method.needsStream || method.returnsChannel {
+		stream = newCallStream(ctx, callData)
+		runtimeStream = stream.stream
+	}
+	if method.mainThread {
+		result, err = b.callOnMainThread(ctx, runtimeStream, method, callData.Data, callData.Binary)
+	} else {
+		result, err = method.call(ctx, runtimeStream, callData.Data, callData.Binary)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -298,10 +495,7 @@ func (b *Manager) processMethodCall(callData *messages.CallData) (interface{}, e
 			return nil, errorResult.Interface().(error)
 		}
 	}
-	if result != nil {
-		return result[0].Interface(), nil
-	}
-	return nil, nil
+	return callResult(ctx, stream, method.returnsChannel, result)
 }
 
 // ProcessCall processes the given call request
@@ -341,6 +535,46 @@ func (b *Manager) ProcessCall(callData *messages.CallData) (result interface{},
 	return
 }
 
+// MaxPayloadSize returns the size in bytes above which the results of the
+// given binding are streamed to the frontend, or 0 to use the app's setting
+func (b *Manager) MaxPayloadSize(bindingName string) int {
+	method := b.methods[bindingName]
+	if method == nil {
+		return 0
+	}
+	return method.maxPayloadSize
+}
+
+// AutomationCall calls a scriptable method on behalf of another application.
+// The arguments are a JSON array and the result is returned as JSON. An empty
+// method returns the names of the scriptable methods.
+func (b *Manager) AutomationCall(method string, args string) (string, error) {
+	if method == "" {
+		names := make([]string, 0, len(b.scriptable))
+		for name := range b.scriptable {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result, err := json.Marshal(names)
+		return string(result), err
+	}
+
+	boundMethod := b.scriptable[method]
+	if boundMethod == nil {
+		return "", fmt.Errorf("method '%s' is not scriptable", method)
+	}
+	if args == "" {
+		args = "[]"
+	}
+	b.log.Debugf("Automation call to %s", boundMethod.fullName)
+	result, err := b.ProcessCall(&messages.CallData{BindingName: boundMethod.fullName, Data: args})
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(result)
+	return string(data), err
+}
+
 // callWailsInitMethods calls all of the WailsInit methods that were
 // registered with the runtime object
 func (b *Manager) callWailsInitMethods() error {
@@ -364,9 +598,14 @@ func (b *Manager) callWailsInitMethods() error {
 // Shutdown the binding manager
 func (b *Manager) Shutdown() {
 	b.log.Debug("Shutdown called")
+	b.callShutdownHooks()
 	for _, method := range b.shutdownMethods {
 		b.log.Debugf("Calling Shutdown for method: %s", method.fullName)
-		method.call("[]")
+		method.call(b.ctx, nil, "[]", nil)
+	}
+	b.cancel()
+	for _, service := range b.isolatedServices {
+		service.stop()
 	}
 	b.log.Debug("Shutdown complete")
 }
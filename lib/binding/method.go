@@ -2,11 +2,13 @@ package binding
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
 
 	"github.com/wailsapp/wails/lib/logger"
+	wailsruntime "github.com/wailsapp/wails/runtime"
 )
 
 type boundMethod struct {
@@ -17,10 +19,20 @@ type boundMethod struct {
 	returnTypes        []reflect.Type
 	log                *logger.CustomLogger
 	hasErrorReturnType bool // Indicates if there is an error return type
+	needsContext       bool // The first parameter is a context.Context, which isn't sent by the frontend
+	needsStream        bool // The next parameter is a *runtime.Stream, which isn't sent by the frontend
+	returnsChannel     bool // The result is a channel, whose values are streamed
 	isWailsInit        bool
 	isWailsShutdown    bool
+	mainThread         bool             // Indicates if the method must be called on the main thread
+	service            *isolatedService // The child process hosting the method, if isolated
+	maxPayloadSize     int              // The size above which results are streamed, if not the app's
 }
 
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+var streamType = reflect.TypeOf((*wailsruntime.Stream)(nil))
+
 // Creates a new bound method based on the given method + type
 func newBoundMethod(name string, fullName string, method reflect.Value, objectType reflect.Type) (*boundMethod, error) {
 	result := &boundMethod{
@@ -55,11 +67,23 @@ func (b *boundMethod) processParameters() error {
 
 	// Input parameters
 	inputParamCount := methodType.NumIn()
-	if inputParamCount > 0 {
-		b.inputs = make([]reflect.Type, inputParamCount)
+
+	// A context given as the first param is cancelled with the call, and
+	// a stream given next sends results before the call returns
+	firstParam := 0
+	if inputParamCount > firstParam && methodType.In(firstParam) == contextType {
+		b.needsContext = true
+		firstParam++
+	}
+	if inputParamCount > firstParam && methodType.In(firstParam) == streamType {
+		b.needsStream = true
+		firstParam++
+	}
+	if inputParamCount > firstParam {
+		b.inputs = make([]reflect.Type, inputParamCount-firstParam)
 		// We start at 1 as the first param is the struct
-		for index := 0; index < inputParamCount; index++ {
-			param := methodType.In(index)
+		for index := 0; index < len(b.inputs); index++ {
+			param := methodType.In(index + firstParam)
 			name := param.Name()
 			kind := param.Kind()
 			b.inputs[index] = param
@@ -112,11 +136,18 @@ func (b *boundMethod) processParameters() error {
 		return fmt.Errorf("cannot register method '%s' with %d return parameters. Please use up to 2", b.Name, returnParamsCount)
 	}
 
+	// The values received from a returned channel are streamed
+	if len(b.returnTypes) > 0 {
+		result := b.returnTypes[0]
+		b.returnsChannel = result.Kind() == reflect.Chan && result.ChanDir()&reflect.RecvDir != 0
+	}
+
 	return nil
 }
 
-// call the method with the given data
-func (b *boundMethod) call(data string) ([]reflect.Value, error) {
+// call the method with the given data and the byte slices sent with it, and the
+// context and stream if it takes them
+func (b *boundMethod) call(ctx context.Context, stream *wailsruntime.Stream, data string, binary [][]byte) ([]reflect.Value, error) {
 
 	// The data will be an array of values so we will decode the
 	// input data into
@@ -127,6 +158,10 @@ func (b *boundMethod) call(data string) ([]reflect.Value, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Invalid data passed to method call: %s", err.Error())
 	}
+	err = binaryArgs(jsArgs, binary)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check correct number of inputs
 	if len(jsArgs) != len(b.inputs) {
@@ -144,6 +179,12 @@ func (b *boundMethod) call(data string) ([]reflect.Value, error) {
 		}
 		args[index] = value
 	}
+	if b.needsStream {
+		args = append([]reflect.Value{reflect.ValueOf(stream)}, args...)
+	}
+	if b.needsContext {
+		args = append([]reflect.Value{reflect.ValueOf(ctx)}, args...)
+	}
 	b.log.Debugf("Unmarshalled Args: %+v\n", jsArgs)
 	b.log.Debugf("Converted Args: %+v\n", args)
 	results := b.method.Call(args)
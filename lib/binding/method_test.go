@@ -0,0 +1,235 @@
+package binding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/messages"
+	wailsruntime "github.com/wailsapp/wails/runtime"
+)
+
+type specSearches struct{}
+
+func (s *specSearches) Search(ctx context.Context, query string) (bool, error) {
+	return ctx.Err() != nil, nil
+}
+
+func TestBoundMethodContext(t *testing.T) {
+	object := &specSearches{}
+	method, err := newBoundMethod("Search", "binding.specSearches.Search", reflect.ValueOf(object).MethodByName("Search"), reflect.TypeOf(object))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !method.needsContext || len(method.inputs) != 1 {
+		t.Fatalf("expected the context not to be one of the inputs, got %v", method.inputs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tests := []struct {
+		name      string
+		cancel    bool
+		cancelled bool
+	}{
+		{"running", false, false},
+		{"cancelled", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cancel {
+				cancel()
+			}
+			result, err := method.call(ctx, nil, `["query"]`, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result[0].Bool(); got != tt.cancelled {
+				t.Errorf("expected the method to see the context cancelled = %v, got %v", tt.cancelled, got)
+			}
+		})
+	}
+	cancel()
+}
+
+func TestProcessCallCancelled(t *testing.T) {
+	manager := NewManager().(*Manager)
+	manager.Bind(&specSearches{})
+	if err := manager.bindWithoutRenderer("binding.specSearches"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := manager.ProcessCall(&messages.CallData{BindingName: "binding.specSearches.Search", Data: `["query"]`, Context: ctx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != true {
+		t.Errorf("expected the method's context to be cancelled with the call")
+	}
+}
+
+func (s *specSearches) Tail(count int) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for i := 0; i < count; i++ {
+			lines <- "line"
+		}
+	}()
+	return lines
+}
+
+func (s *specSearches) Progress(stream *wailsruntime.Stream, steps int) error {
+	for i := 1; i <= steps; i++ {
+		if err := stream.Send(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestProcessCallStreams(t *testing.T) {
+	manager := NewManager().(*Manager)
+	manager.Bind(&specSearches{})
+	if err := manager.bindWithoutRenderer("binding.specSearches"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		binding   string
+		streaming bool
+		want      int
+	}{
+		{"channel", "binding.specSearches.Tail", true, 3},
+		{"channel collected", "binding.specSearches.Tail", false, 3},
+		{"stream", "binding.specSearches.Progress", true, 3},
+		{"stream collected", "binding.specSearches.Progress", false, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var streamed []interface{}
+			callData := &messages.CallData{BindingName: tt.binding, Data: `[3]`}
+			if tt.streaming {
+				callData.Stream = func(data interface{}) error {
+					streamed = append(streamed, data)
+					return nil
+				}
+			}
+			result, err := manager.ProcessCall(callData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.streaming {
+				if result != nil || len(streamed) != tt.want {
+					t.Errorf("expected %d results streamed, got %v and the result %v", tt.want, streamed, result)
+				}
+				return
+			}
+			if collected, ok := result.([]interface{}); !ok || len(collected) != tt.want {
+				t.Errorf("expected the %d results to be returned, got %v", tt.want, result)
+			}
+		})
+	}
+}
+
+func (s *specSearches) Size(image []byte, name string) string {
+	return fmt.Sprintf("%s:%d", name, len(image))
+}
+
+func TestProcessCallBinary(t *testing.T) {
+	manager := NewManager().(*Manager)
+	manager.Bind(&specSearches{})
+	if err := manager.bindWithoutRenderer("binding.specSearches"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		binary  [][]byte
+		want    interface{}
+		wantErr bool
+	}{
+		{"binary", `[{"$binary":0},"photo"]`, [][]byte{{1, 2, 3}}, "photo:3", false},
+		{"missing", `[{"$binary":1},"photo"]`, [][]byte{{1}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := manager.ProcessCall(&messages.CallData{BindingName: "binding.specSearches.Size", Data: tt.data, Binary: tt.binary})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessCall() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.want {
+				t.Errorf("ProcessCall() = %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+type mainThreadCounter struct {
+	calls int
+}
+
+func (c *mainThreadCounter) Count() int {
+	c.calls++
+	return c.calls
+}
+
+// fakeDispatchRenderer passes the functions dispatched to the main thread to the test
+type fakeDispatchRenderer struct {
+	interfaces.Renderer
+	dispatched chan func()
+}
+
+func (f *fakeDispatchRenderer) Dispatch(fn func()) {
+	f.dispatched <- fn
+}
+
+func TestCallOnMainThread(t *testing.T) {
+	counter := &mainThreadCounter{}
+	manager := NewManager().(*Manager)
+	manager.Bind(counter)
+	if err := manager.bindWithoutRenderer("binding.mainThreadCounter"); err != nil {
+		t.Fatal(err)
+	}
+	manager.methods["binding.mainThreadCounter.Count"].mainThread = true
+	renderer := &fakeDispatchRenderer{dispatched: make(chan func())}
+	manager.renderer = renderer
+
+	type callResult struct {
+		result interface{}
+		err    error
+	}
+	call := func() chan callResult {
+		done := make(chan callResult, 1)
+		go func() {
+			result, err := manager.ProcessCall(&messages.CallData{BindingName: "binding.mainThreadCounter.Count", Data: `[]`})
+			done <- callResult{result, err}
+		}()
+		return done
+	}
+
+	// The method runs when the main thread runs the dispatched function
+	done := call()
+	(<-renderer.dispatched)()
+	if got := <-done; got.err != nil || got.result != 1 {
+		t.Errorf("expected the call to return 1, got %v, %v", got.result, got.err)
+	}
+
+	// Calls waiting for the main thread return when the app shuts down,
+	// and aren't run once it gets to them
+	done = call()
+	queued := <-renderer.dispatched
+	manager.Shutdown()
+	if got := <-done; got.err == nil {
+		t.Errorf("expected the call to fail once the app shut down, got %v", got.result)
+	}
+	queued()
+	if counter.calls != 1 {
+		t.Errorf("expected the queued call not to run after shutdown, it ran %d times", counter.calls)
+	}
+}
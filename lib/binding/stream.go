@@ -0,0 +1,98 @@
+package binding
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/wailsapp/wails/lib/messages"
+	wailsruntime "github.com/wailsapp/wails/runtime"
+)
+
+// callStream streams the results of a call to a bound method that takes a
+// stream or returns a channel. Without a frontend to send them to, EG: for
+// headless, automation and isolated calls, the results are collected and
+// returned when the call returns.
+type callStream struct {
+	stream     *wailsruntime.Stream
+	collecting bool
+	collected  []interface{}
+	lock       sync.Mutex
+}
+
+// newCallStream returns the stream for the given call
+func newCallStream(ctx context.Context, callData *messages.CallData) *callStream {
+	result := &callStream{}
+	send := callData.Stream
+	if send == nil {
+		result.collecting = true
+		result.collected = []interface{}{}
+		send = result.collect
+	}
+	result.stream = wailsruntime.NewStream(ctx, send)
+	return result
+}
+
+func (s *callStream) collect(data interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.collected = append(s.collected, data)
+	return nil
+}
+
+// drain streams the values received from the channel returned by a bound
+// method until it is closed or the call is cancelled
+func (s *callStream) drain(ctx context.Context, channel reflect.Value) error {
+	if channel.IsNil() {
+		return nil
+	}
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: channel},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	}
+	for {
+		chosen, value, ok := reflect.Select(cases)
+		if chosen == 1 {
+			return ctx.Err()
+		}
+		if !ok {
+			return nil
+		}
+		err := s.stream.Send(value.Interface())
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// result returns the result of a call that streamed its results, given the
+// method's own result. The results are returned if they were collected and
+// the method has none.
+func (s *callStream) result(result interface{}) interface{} {
+	if !s.collecting || result != nil {
+		return result
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.collected
+}
+
+// callResult returns the result of a call given the values returned by the
+// bound method, once the values of a returned channel have been streamed
+func callResult(ctx context.Context, stream *callStream, returnsChannel bool, result []reflect.Value) (interface{}, error) {
+	if returnsChannel {
+		err := stream.drain(ctx, result[0])
+		if err != nil {
+			return nil, err
+		}
+		return stream.result(nil), nil
+	}
+	var value interface{}
+	if len(result) > 0 {
+		value = result[0].Interface()
+	}
+	if stream != nil {
+		return stream.result(value), nil
+	}
+	return value, nil
+}
@@ -0,0 +1,290 @@
+package binding
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The header of the generated Typescript definitions and JS module
+const generatedHeader = "// Code generated by Wails. DO NOT EDIT.\n"
+
+// binding is a bound method or function, by its path in window.backend
+type binding struct {
+	path   []string
+	method *APIMethod
+}
+
+// bindings returns the bound methods and functions sorted by path. The
+// package of a binding isn't part of its path, EG: "main.Counter.Add" is
+// window.backend.Counter.Add.
+func (s *APISpec) bindings() []*binding {
+	var result []*binding
+	for name, method := range s.Methods {
+		path := strings.Split(name, ".")[1:]
+		result = append(result, &binding{path: path, method: method})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.Join(result[i].path, ".") < strings.Join(result[j].path, ".")
+	})
+	return result
+}
+
+// groups returns the bindings grouped by the first part of their path, EG:
+// the bound struct, in order
+func groups(bindings []*binding) ([]string, map[string][]*binding) {
+	var names []string
+	result := make(map[string][]*binding)
+	for _, binding := range bindings {
+		name := binding.path[0]
+		if result[name] == nil {
+			names = append(names, name)
+		}
+		result[name] = append(result[name], binding)
+	}
+	return names, result
+}
+
+// Typescript returns the Typescript definitions of the bound methods. Each
+// bound struct is declared as a constant with its methods and each bound
+// function as a function, as exported by Module, and they are added to
+// window.backend. The models are interfaces in a namespace for their
+// package, EG: main.Todo.
+func (s *APISpec) Typescript() []byte {
+	var output strings.Builder
+	output.WriteString(generatedHeader)
+	s.writeModels(&output)
+
+	names, grouped := groups(s.bindings())
+	for _, name := range names {
+		output.WriteString("\n")
+		bindings := grouped[name]
+		if len(bindings[0].path) == 1 {
+			for _, signature := range signatures(bindings[0].method) {
+				fmt.Fprintf(&output, "export declare function %s%s;\n", name, signature)
+			}
+			continue
+		}
+		fmt.Fprintf(&output, "export declare const %s: {\n", name)
+		writeMembers(&output, bindings, 1, "\t")
+		output.WriteString("};\n")
+	}
+
+	output.WriteString("\ndeclare global {\n\tinterface Window {\n\t\tbackend: {\n")
+	for _, name := range names {
+		fmt.Fprintf(&output, "\t\t\t%[1]s: typeof %[1]s;\n", name)
+	}
+	output.WriteString("\t\t};\n\t}\n}\n")
+	return []byte(output.String())
+}
+
+// writeMembers writes the methods of the given bindings, from the given
+// part of their paths, nesting the parts that aren't the method's name
+func writeMembers(output *strings.Builder, bindings []*binding, depth int, indent string) {
+	var names []string
+	nested := make(map[string][]*binding)
+	for _, binding := range bindings {
+		name := binding.path[depth]
+		if depth == len(binding.path)-1 {
+			for _, signature := range signatures(binding.method) {
+				fmt.Fprintf(output, "%s%s%s;\n", indent, name, signature)
+			}
+			continue
+		}
+		if nested[name] == nil {
+			names = append(names, name)
+		}
+		nested[name] = append(nested[name], binding)
+	}
+	for _, name := range names {
+		fmt.Fprintf(output, "%s%s: {\n", indent, name)
+		writeMembers(output, nested[name], depth+1, indent+"\t")
+		fmt.Fprintf(output, "%s};\n", indent)
+	}
+}
+
+// writeModels writes the models as interfaces, in a namespace for each
+// package
+func (s *APISpec) writeModels(output *strings.Builder) {
+	packages := make(map[string][]string)
+	var packageNames []string
+	for name := range s.Models {
+		parts := strings.SplitN(name, ".", 2)
+		if packages[parts[0]] == nil {
+			packageNames = append(packageNames, parts[0])
+		}
+		packages[parts[0]] = append(packages[parts[0]], name)
+	}
+	sort.Strings(packageNames)
+
+	for _, packageName := range packageNames {
+		models := packages[packageName]
+		sort.Strings(models)
+		fmt.Fprintf(output, "\nexport namespace %s {\n", packageName)
+		for index, name := range models {
+			if index > 0 {
+				output.WriteString("\n")
+			}
+			model := s.Models[name]
+			fmt.Fprintf(output, "\texport interface %s {\n", strings.SplitN(name, ".", 2)[1])
+			for _, property := range sortedProperties(model) {
+				fmt.Fprintf(output, "\t\t%s: %s;\n", propertyName(property), tsType(model.Properties[property]))
+			}
+			output.WriteString("\t}\n")
+		}
+		output.WriteString("}\n")
+	}
+}
+
+func sortedProperties(schema *APISchema) []string {
+	var result []string
+	for name := range schema.Properties {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// propertyName quotes JSON names that aren't identifiers
+func propertyName(name string) string {
+	for index, r := range name {
+		identifier := r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(index > 0 && r >= '0' && r <= '9')
+		if !identifier {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
+
+// signatures returns the parameters and result of a method. Go doesn't keep
+// the names of parameters, so they are numbered. Methods that can be
+// cancelled can be given an AbortSignal after their parameters. Methods
+// that stream their results are overloaded: given a function after their
+// parameters, it is called with each result, and otherwise the results
+// are returned once the method returns, unless it has a result of its own.
+func signatures(method *APIMethod) []string {
+	var params []string
+	for index, param := range method.Params {
+		params = append(params, fmt.Sprintf("arg%d: %s", index+1, tsType(param)))
+	}
+	var signal []string
+	if method.Cancellable {
+		signal = append(signal, "signal?: AbortSignal")
+	}
+	result := "void"
+	if method.Result != nil {
+		result = tsType(method.Result)
+	}
+	if method.Stream == nil {
+		return []string{signature(append(params, signal...), result)}
+	}
+
+	stream := tsType(method.Stream)
+	onData := fmt.Sprintf("onData: (data: %s) => void", stream)
+	withHandler := append(append(params[:len(params):len(params)], onData), signal...)
+	collected := result
+	if method.Result == nil {
+		if strings.ContainsAny(stream, " |") {
+			stream = "(" + stream + ")"
+		}
+		collected = stream + "[]"
+	}
+	return []string{
+		signature(withHandler, result),
+		signature(append(params, signal...), collected),
+	}
+}
+
+// signature returns the given parameters and result
+func signature(params []string, result string) string {
+	return fmt.Sprintf("(%s): Promise<%s>", strings.Join(params, ", "), result)
+}
+
+// tsType returns the Typescript type of the values described by the schema
+func tsType(schema *APISchema) string {
+	if schema.Ref != "" {
+		return strings.TrimPrefix(schema.Ref, "#/models/")
+	}
+	switch schema.Type {
+	case "boolean":
+		return "boolean"
+	case "integer", "number":
+		return "number"
+	case "string":
+		if schema.Format == "binary" {
+			return "Uint8Array"
+		}
+		return "string"
+	case "array":
+		item := tsType(schema.Items)
+		if strings.ContainsAny(item, " |") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object":
+		if schema.AdditionalProperties != nil {
+			return fmt.Sprintf("{ [key: string]: %s }", tsType(schema.AdditionalProperties))
+		}
+		var properties []string
+		for _, property := range sortedProperties(schema) {
+			properties = append(properties, fmt.Sprintf("%s: %s;", propertyName(property), tsType(schema.Properties[property])))
+		}
+		if len(properties) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(properties, " ") + " }"
+	}
+	return "any"
+}
+
+// Module returns a JS module exporting the bound structs and functions, as
+// declared by Typescript, which call window.backend
+func (s *APISpec) Module() []byte {
+	var output strings.Builder
+	output.WriteString(generatedHeader)
+
+	names, grouped := groups(s.bindings())
+	for _, name := range names {
+		output.WriteString("\n")
+		bindings := grouped[name]
+		if len(bindings[0].path) == 1 {
+			fmt.Fprintf(&output, "export function %s() {\n", name)
+			fmt.Fprintf(&output, "\treturn window.backend.%s.apply(null, arguments);\n", name)
+			output.WriteString("}\n")
+			continue
+		}
+		fmt.Fprintf(&output, "export const %s = {\n", name)
+		writeWrappers(&output, bindings, 1, "\t")
+		output.WriteString("};\n")
+	}
+	return []byte(output.String())
+}
+
+// writeWrappers writes the functions calling the given bindings, nested as
+// in writeMembers
+func writeWrappers(output *strings.Builder, bindings []*binding, depth int, indent string) {
+	var names []string
+	nested := make(map[string][]*binding)
+	for _, binding := range bindings {
+		name := binding.path[depth]
+		if depth == len(binding.path)-1 {
+			fmt.Fprintf(output, "%s%s: function () {\n", indent, name)
+			fmt.Fprintf(output, "%s\treturn window.backend.%s.apply(null, arguments);\n", indent, strings.Join(binding.path, "."))
+			fmt.Fprintf(output, "%s},\n", indent)
+			continue
+		}
+		if nested[name] == nil {
+			names = append(names, name)
+		}
+		nested[name] = append(nested[name], binding)
+	}
+	for _, name := range names {
+		fmt.Fprintf(output, "%s%s: {\n", indent, name)
+		writeWrappers(output, nested[name], depth+1, indent+"\t")
+		fmt.Fprintf(output, "%s},\n", indent)
+	}
+}
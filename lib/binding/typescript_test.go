@@ -0,0 +1,60 @@
+package binding
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func specGreet(name string) string { return "Hello " + name }
+
+func specSearch(ctx context.Context, query string) ([]string, error) { return nil, nil }
+
+func specTail(path string) (<-chan string, error) { return nil, nil }
+
+func specThumbnail(path string) ([]byte, error) { return nil, nil }
+
+func TestTypescript(t *testing.T) {
+	manager := NewManager().(*Manager)
+	manager.Bind(&specTodos{})
+	manager.Bind(specGreet)
+	manager.Bind(specSearch)
+	manager.Bind(specTail)
+	manager.Bind(specThumbnail)
+	manager.SetBinaryResults(true)
+	spec, err := manager.APISpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	definitions := string(spec.Typescript())
+	for _, expected := range []string{
+		"export namespace binding {\n\texport interface specTodo {\n",
+		"\t\tchildren: binding.specTodo[];\n",
+		"\t\tid: number;\n",
+		"export declare const specTodos: {\n",
+		"\tAdd(arg1: binding.specTodo): Promise<void>;\n",
+		"\tData(arg1: string): Promise<{ [key: string]: number }>;\n",
+		"\tGet(arg1: number): Promise<binding.specTodo>;\n",
+		"export declare function specGreet(arg1: string): Promise<string>;\n",
+		"export declare function specSearch(arg1: string, signal?: AbortSignal): Promise<string[]>;\n",
+		"export declare function specTail(arg1: string, onData: (data: string) => void, signal?: AbortSignal): Promise<void>;\n" +
+			"export declare function specTail(arg1: string, signal?: AbortSignal): Promise<string[]>;\n",
+		"export declare function specThumbnail(arg1: string): Promise<Uint8Array>;\n",
+		"\t\t\tspecTodos: typeof specTodos;\n",
+	} {
+		if !strings.Contains(definitions, expected) {
+			t.Errorf("expected the definitions to contain %q, got:\n%s", expected, definitions)
+		}
+	}
+
+	module := string(spec.Module())
+	for _, expected := range []string{
+		"export const specTodos = {\n\tAdd: function () {\n\t\treturn window.backend.specTodos.Add.apply(null, arguments);\n\t},\n",
+		"export function specGreet() {\n\treturn window.backend.specGreet.apply(null, arguments);\n}\n",
+	} {
+		if !strings.Contains(module, expected) {
+			t.Errorf("expected the module to contain %q, got:\n%s", expected, module)
+		}
+	}
+}
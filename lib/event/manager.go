@@ -7,6 +7,7 @@ import (
 	"github.com/wailsapp/wails/lib/interfaces"
 	"github.com/wailsapp/wails/lib/logger"
 	"github.com/wailsapp/wails/lib/messages"
+	"github.com/wailsapp/wails/lib/perf"
 )
 
 // Manager handles and processes events
@@ -19,12 +20,25 @@ type Manager struct {
 	renderer       interfaces.Renderer // Messages will be dispatched to the frontend
 	wg             sync.WaitGroup
 	mu             sync.Mutex
+
+	// Traces the events, if set
+	recorder *perf.Recorder
+
+	// Delays and drops events, if set
+	faults *perf.Faults
 }
 
 // NewManager creates a new event manager with a 100 event buffer
 func NewManager() interfaces.EventManager {
+	return NewManagerWithBuffer(100)
+}
+
+// NewManagerWithBuffer creates a new event manager that queues up to the
+// given number of events. With no buffer, emitting an event waits until
+// the manager takes it.
+func NewManagerWithBuffer(size int) interfaces.EventManager {
 	return &Manager{
-		incomingEvents: make(chan *messages.EventData, 100),
+		incomingEvents: make(chan *messages.EventData, size),
 		quitChannel:    make(chan struct{}, 1),
 		listeners:      make(map[string][]*eventListener),
 		running:        false,
@@ -32,9 +46,58 @@ func NewManager() interfaces.EventManager {
 	}
 }
 
-// PushEvent places the given event on to the event queue
+// RecordPerformance traces the events emitted from now on with the given
+// recorder
+func (e *Manager) RecordPerformance(recorder *perf.Recorder) {
+	e.mu.Lock()
+	e.recorder = recorder
+	e.mu.Unlock()
+}
+
+// InjectFaults delays and drops the events emitted from now on as set by
+// the given faults
+func (e *Manager) InjectFaults(faults *perf.Faults) {
+	e.mu.Lock()
+	e.faults = faults
+	e.mu.Unlock()
+}
+
+// queue places the event on the event queue, unless the faults drop it
+func (e *Manager) queue(eventData *messages.EventData) {
+	e.mu.Lock()
+	faults := e.faults
+	e.mu.Unlock()
+	if faults == nil {
+		e.incomingEvents <- eventData
+		return
+	}
+	delivered := faults.Event(eventData.Name, func() {
+		e.incomingEvents <- eventData
+	})
+	if !delivered {
+		e.log.Infof("Dropped event '%s' (--failure-rate)", eventData.Name)
+	}
+}
+
+// trace records the event if it is being traced
+func (e *Manager) trace(eventData *messages.EventData, source string) {
+	e.mu.Lock()
+	recorder := e.recorder
+	e.mu.Unlock()
+	if recorder == nil {
+		return
+	}
+	data := eventData.Data
+	if values, ok := data.([]interface{}); ok && len(values) == 0 {
+		data = nil
+	}
+	recorder.TraceEvent(eventData.Name, source, data)
+}
+
+// PushEvent places the given event, from the frontend, on to the event queue
 func (e *Manager) PushEvent(eventData *messages.EventData) {
-	e.incomingEvents <- eventData
+	e.trace(eventData, "frontend")
+	e.queue(eventData)
 }
 
 // eventListener holds a callback function which is invoked when
@@ -104,7 +167,9 @@ func (e *Manager) OnMultiple(eventName string, callback func(...interface{}), co
 
 // Emit broadcasts the given event to the subscribed listeners
 func (e *Manager) Emit(eventName string, optionalData ...interface{}) {
-	e.incomingEvents <- &messages.EventData{Name: eventName, Data: optionalData}
+	eventData := &messages.EventData{Name: eventName, Data: optionalData}
+	e.trace(eventData, "backend")
+	e.queue(eventData)
 }
 
 // Start the event manager's queue processing
@@ -0,0 +1,59 @@
+package event
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/lib/messages"
+)
+
+// The most events a Queue holds, and how long it holds them for
+const (
+	queueSize = 100
+	queueTTL  = time.Minute
+)
+
+// Queue holds the events emitted while the frontend is reloading, so they
+// can be delivered once its listeners are registered again. Once full, the
+// oldest events are dropped, as are events older than a minute.
+type Queue struct {
+	events []queuedEvent
+	lock   sync.Mutex
+	now    func() time.Time
+}
+
+type queuedEvent struct {
+	event   *messages.EventData
+	emitted time.Time
+}
+
+// NewQueue creates a new, empty Queue
+func NewQueue() *Queue {
+	return &Queue{now: time.Now}
+}
+
+// Push adds the given event to the queue
+func (q *Queue) Push(event *messages.EventData) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.events) == queueSize {
+		q.events = q.events[1:]
+	}
+	q.events = append(q.events, queuedEvent{event: event, emitted: q.now()})
+}
+
+// Drain empties the queue, returning the events that haven't
+// expired in the order they were emitted
+func (q *Queue) Drain() []*messages.EventData {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	var result []*messages.EventData
+	expiry := q.now().Add(-queueTTL)
+	for _, queued := range q.events {
+		if queued.emitted.After(expiry) {
+			result = append(result, queued.event)
+		}
+	}
+	q.events = nil
+	return result
+}
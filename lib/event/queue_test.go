@@ -0,0 +1,37 @@
+package event
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wailsapp/wails/lib/messages"
+)
+
+func TestQueue(t *testing.T) {
+	now := time.Now()
+	queue := NewQueue()
+	queue.now = func() time.Time { return now }
+
+	queue.Push(&messages.EventData{Name: "expired"})
+	now = now.Add(queueTTL)
+	queue.Push(&messages.EventData{Name: "current"})
+	events := queue.Drain()
+	if len(events) != 1 || events[0].Name != "current" {
+		t.Errorf("expected only the current event, got %d events", len(events))
+	}
+	if len(queue.Drain()) != 0 {
+		t.Errorf("expected the queue to be empty once drained")
+	}
+
+	for i := 0; i < queueSize+1; i++ {
+		queue.Push(&messages.EventData{Name: fmt.Sprintf("event%d", i)})
+	}
+	events = queue.Drain()
+	if len(events) != queueSize {
+		t.Fatalf("expected %d events, got %d", queueSize, len(events))
+	}
+	if events[0].Name != "event1" || events[queueSize-1].Name != fmt.Sprintf("event%d", queueSize) {
+		t.Errorf("expected the latest events in order, got %s to %s", events[0].Name, events[queueSize-1].Name)
+	}
+}
@@ -0,0 +1,96 @@
+// Package integrity verifies that the frontend assets of an application
+// have not been modified since it was built.
+//
+// The digests are embedded in the same binary as the assets, so an attacker
+// who can rewrite the binary can replace both. Verification guards against
+// corrupted or swapped assets; code signing guards the binary.
+package integrity
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// assetDigests is a comma separated list of the sha256 digests of the
+// assets embedded in the application. It is set at build time by the
+// Wails CLI using -ldflags.
+var assetDigests = ""
+
+// trustedDigests are the digests of assets verified since the app was
+// built, EG: those in a signed asset patch
+var (
+	trustedDigests = map[string]bool{}
+	trustedLock    sync.RWMutex
+)
+
+// Error is returned when an asset fails verification
+type Error struct {
+	// The names of the assets that failed verification
+	Assets []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("integrity check failed for: %s", strings.Join(e.Assets, ", "))
+}
+
+// Enabled returns true if the application was built with asset digests
+func Enabled() bool {
+	return assetDigests != ""
+}
+
+// Digest returns the hex encoded sha256 digest of the given data
+func Digest(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// Trust adds the given asset to those that pass verification. It is used for
+// assets that have been verified another way, EG: by their signature.
+func Trust(data string) {
+	trustedLock.Lock()
+	defer trustedLock.Unlock()
+	trustedDigests[Digest([]byte(data))] = true
+}
+
+// Trusted returns true if the given asset has been added with Trust
+func Trusted(data string) bool {
+	trustedLock.RLock()
+	defer trustedLock.RUnlock()
+	return trustedDigests[Digest([]byte(data))]
+}
+
+// Verify checks each of the given named assets against the digests recorded
+// at build time. Empty assets are ignored, as are all assets if the
+// application was not built with digests.
+func Verify(assets map[string]string) error {
+	if !Enabled() {
+		return nil
+	}
+
+	known := map[string]bool{}
+	for _, digest := range strings.Split(assetDigests, ",") {
+		known[strings.TrimSpace(digest)] = true
+	}
+	trustedLock.RLock()
+	for digest := range trustedDigests {
+		known[digest] = true
+	}
+	trustedLock.RUnlock()
+
+	var failed []string
+	for name, data := range assets {
+		if data == "" {
+			continue
+		}
+		if !known[Digest([]byte(data))] {
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return &Error{Assets: failed}
+	}
+	return nil
+}
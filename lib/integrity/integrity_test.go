@@ -0,0 +1,44 @@
+package integrity
+
+import "testing"
+
+func TestVerify(t *testing.T) {
+	defer func(saved string) { assetDigests = saved }(assetDigests)
+
+	js, css := "console.log('hello')", "body{}"
+
+	// Without digests, nothing is verified
+	assetDigests = ""
+	if err := Verify(map[string]string{"JS": "tampered"}); err != nil {
+		t.Errorf("Verify() without digests error = %v", err)
+	}
+
+	assetDigests = Digest([]byte(js)) + "," + Digest([]byte(css))
+	if err := Verify(map[string]string{"JS": js, "CSS": css, "HTML": ""}); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	err := Verify(map[string]string{"JS": js + ";", "CSS": css})
+	integrityErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Verify() expected *Error, got %v", err)
+	}
+	if len(integrityErr.Assets) != 1 || integrityErr.Assets[0] != "JS" {
+		t.Errorf("Verify() failed assets = %v, want [JS]", integrityErr.Assets)
+	}
+}
+
+func TestTrust(t *testing.T) {
+	defer func(saved string) { assetDigests = saved }(assetDigests)
+	defer func() { trustedDigests = map[string]bool{} }()
+
+	assetDigests = Digest([]byte("body{}"))
+	patched := "console.log('patched')"
+	if err := Verify(map[string]string{"JS": patched}); err == nil || Trusted(patched) {
+		t.Fatal("Verify() expected an error before the asset is trusted")
+	}
+	Trust(patched)
+	if err := Verify(map[string]string{"JS": patched}); err != nil || !Trusted(patched) {
+		t.Errorf("Verify() error = %v after the asset is trusted", err)
+	}
+}
@@ -1,5 +1,69 @@
 package interfaces
 
+import (
+	"image"
+	"image/color"
+)
+
+// Backdrop is how the desktop shows through a transparent window
+type Backdrop int
+
+const (
+	// BackdropTransparent makes the background colour see-through. Its alpha
+	// is used on Linux and MacOS. On Windows, the colour is cut out.
+	BackdropTransparent Backdrop = iota
+
+	// BackdropTranslucent blurs the desktop behind the window on MacOS. On
+	// Windows, the whole window is made as opaque as the background colour.
+	BackdropTranslucent
+)
+
+// Subsystems turns off optional subsystems. All are enabled by default.
+type Subsystems struct {
+	// Emitting an event waits until the event manager takes it, rather
+	// than queueing up to 100 events
+	DisableEventBuffering bool
+
+	// The runtime's Permissions, Bookmarks, Fonts, Purchases and System.Stats
+	DisablePermissions bool
+	DisableBookmarks   bool
+	DisableFonts       bool
+	DisablePurchases   bool
+	DisableStats       bool
+}
+
+// KeyEvent is a key pressed or released in a window, see AppConfig.OnKey
+type KeyEvent struct {
+	// The key as it's typed without Shift, EG: "a" or "5", or what it
+	// does, EG: "Enter", "Escape", "Tab", "Backspace", "Delete", "Insert",
+	// "Home", "End", "PageUp", "PageDown", "ArrowLeft", "Space" or "F5"
+	Key string `json:"key"`
+
+	// The modifier keys held. Super is the Command key on MacOS and the
+	// Windows key on Windows.
+	Shift   bool `json:"shift"`
+	Control bool `json:"control"`
+	Alt     bool `json:"alt"`
+	Super   bool `json:"super"`
+
+	// Set when the key is pressed, or repeats while it's held
+	Down bool `json:"down"`
+}
+
+// GestureEvent is a mouse side button click, sideways scroll or swipe in a
+// window, see AppConfig.OnGesture
+type GestureEvent struct {
+	// "back" or "forward" for the mouse's side buttons, "scroll" when the
+	// page is scrolled sideways or "swipe" for a swipe on a touchpad
+	Gesture string `json:"gesture"`
+
+	// How far the page is scrolled, in steps of the wheel, or -1, 0 or 1
+	// for the direction of a swipe. They are positive to the right and
+	// down.
+	DeltaX float64 `json:"deltaX"`
+	DeltaY float64 `json:"deltaY"`
+}
+
 // AppConfig is the application config interface
 type AppConfig interface {
 	GetWidth() int
@@ -10,9 +74,41 @@ type AppConfig interface {
 	GetMaxWidth() int
 	GetMaxHeight() int
 	GetResizable() bool
-	GetHTML() string
+	GetHTML() (string, error)
 	GetDisableInspector() bool
 	GetColour() string
 	GetCSS() string
 	GetJS() string
+	GetVersion() string
+	GetCopyright() string
+	GetAboutIcon() []byte
+	GetIssueTracker() string
+	GetSupportEmail() string
+	GetAssetPatchKey() string
+	GetWindowTabbing() bool
+	GetOpenOnCursorDisplay() bool
+	GetTitleBarOverlay() bool
+	GetTrafficLightPosition() image.Point
+	GetAlwaysOnTop() bool
+	GetDisableSystemGestures() bool
+	GetKiosk() bool
+	GetStartHidden() bool
+	GetTrayOnly() bool
+	GetSplashScreen() string
+	GetInitialState() func() interface{}
+	GetConfirmClose() bool
+	GetOnKey() func(KeyEvent) bool
+	GetOnGesture() func(GestureEvent) bool
+	GetOnGeolocation() func(string) bool
+	GetMaxPayloadSize() int
+	GetBridgeCompressionThreshold() int
+	GetBinaryData() bool
+	GetStartX() int
+	GetStartY() int
+	GetCentre() bool
+	GetPersistWindowState() bool
+	GetSubsystems() Subsystems
+	GetTransparent() bool
+	GetBackdrop() Backdrop
+	GetRGBA() *color.RGBA
 }
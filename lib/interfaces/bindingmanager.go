@@ -1,11 +1,48 @@
 package interfaces
 
-import "github.com/wailsapp/wails/lib/messages"
+import (
+	"io"
+
+	"github.com/wailsapp/wails/lib/messages"
+)
+
+// BindOptions customise how an object is bound
+type BindOptions struct {
+	// The names of the methods to execute on the main thread. The main
+	// thread waits for them, so they must not show dialogs, use the
+	// window's getters or call Window.OnMainThread, which wait for it.
+	MainThreadMethods []string
+
+	// Hosts the object in a child process
+	Isolated bool
+
+	// The object is a constructor that makes the struct to bind. Its
+	// parameters are the services the struct depends on.
+	Constructor bool
+
+	// Exposes the object's methods to other applications through the
+	// automation interface. If ScriptableMethods is empty, all are exposed.
+	Scriptable        bool
+	ScriptableMethods []string
+
+	// The size in bytes above which the results of the object's methods
+	// are streamed to the frontend in chunks. Overrides the app's setting.
+	MaxPayloadSize int
+}
+
+// BindOption sets one of the BindOptions
+type BindOption func(*BindOptions)
 
 // BindingManager is the binding manager interface
 type BindingManager interface {
-	Bind(object interface{})
+	Bind(object interface{}, options ...BindOption)
 	Start(renderer Renderer, runtime Runtime) error
 	ProcessCall(callData *messages.CallData) (result interface{}, err error)
+	MaxPayloadSize(bindingName string) int
+	SetBinaryResults(enabled bool)
+	ServeIsolated(name string, in io.Reader, out io.Writer) error
+	AutomationCall(method string, args string) (string, error)
+	CallHeadless(method string, args string) (string, error)
+	WriteAPISpec(out io.Writer) error
 	Shutdown()
 }
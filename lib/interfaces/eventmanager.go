@@ -1,6 +1,9 @@
 package interfaces
 
-import "github.com/wailsapp/wails/lib/messages"
+import (
+	"github.com/wailsapp/wails/lib/messages"
+	"github.com/wailsapp/wails/lib/perf"
+)
 
 // EventManager is the event manager interface
 type EventManager interface {
@@ -9,6 +12,8 @@ type EventManager interface {
 	OnMultiple(eventName string, callback func(...interface{}), counter uint)
 	Once(eventName string, callback func(...interface{}))
 	On(eventName string, callback func(...interface{}))
+	RecordPerformance(recorder *perf.Recorder)
+	InjectFaults(faults *perf.Faults)
 	Start(Renderer)
 	Shutdown()
 }
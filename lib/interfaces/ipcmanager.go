@@ -1,13 +1,24 @@
 package interfaces
 
+import "github.com/wailsapp/wails/lib/perf"
+
 // CallbackFunc defines the signature of a function required to be provided to the
 // Dispatch function so that the response may be returned
 type CallbackFunc func(string) error
 
+// BinaryCallbackFunc defines the signature of a function that sends a response
+// whose data is binary to the frontend. The header is the response as JSON.
+type BinaryCallbackFunc func(header string, data []byte) error
+
 // IPCManager is the event manager interface
 type IPCManager interface {
 	BindRenderer(Renderer)
-	Dispatch(message string, f CallbackFunc)
+	Dispatch(message string, f CallbackFunc, binary BinaryCallbackFunc)
+	DispatchBinary(frame []byte, f CallbackFunc, binary BinaryCallbackFunc)
+	SetBinaryResults(enabled bool)
 	Start(eventManager EventManager, bindingManager BindingManager)
+	SetMaxPayloadSize(size int)
+	RecordPerformance(recorder *perf.Recorder)
+	InjectFaults(faults *perf.Faults)
 	Shutdown()
 }
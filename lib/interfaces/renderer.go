@@ -1,6 +1,10 @@
 package interfaces
 
 import (
+	"image"
+	"image/color"
+	"unsafe"
+
 	"github.com/wailsapp/wails/lib/messages"
 )
 
@@ -17,17 +21,91 @@ type Renderer interface {
 
 	// Dialog Runtime
 	SelectFile(title string, filter string) string
+	SelectFiles(title string, directory string, formats string, multiple bool) []string
 	SelectDirectory() string
+	SelectDirectories(title string) []string
+	SelectDirectoriesWithOptions(title string, directory string, multiple bool) []string
 	SelectSaveFile(title string, filter string) string
+	SelectSaveFileWithFormats(title string, directory string, filename string, formats string, createDirectories, confirmOverwrite bool) (string, int)
+	ShowMessage(messageType string, title string, message string, buttons []string) int
+	ColorPicker(title string, initial color.RGBA) (color.RGBA, bool)
+	FontPicker(title string, initial FontDescription) (FontDescription, bool)
 
 	// Window Runtime
 	SetColour(string) error
 
 	SetMinSize(width, height int)
 	SetMaxSize(width, height int)
+	SetAspectRatio(width, height int)
 
 	Fullscreen()
 	UnFullscreen()
+	Minimise()
+	Maximise()
+	Restore()
+	Show()
+	Hide()
 	SetTitle(title string)
+	SetIcon(data []byte) error
+	ShowAbout()
+	SetSystemGestures(enabled bool)
+	StartDrag(files []string) bool
+	SetAlwaysOnTop(onTop bool)
+	SetBorderless(borderless bool)
+	SetSize(width, height int)
+	SnapToCorner(corner, margin int)
+	SetIgnoreMouseEvents(ignore bool, forwardMove bool)
+	SetInputRegions(regions []image.Rectangle)
+	Displays() []Display
+	CursorDisplay() int
+	PlaceOnDisplay(display, edge, margin int)
+	SetPosition(x, y int)
+	Position() (x, y int)
+	Size() (width, height int)
+	SetTrafficLightPosition(x, y int)
+	TitleBarButtonArea() image.Rectangle
+	SetTitleBarColour(background, symbol string) error
+	ShowEmojiPicker()
+	RequestUserAttention(critical bool)
+	SetOpacity(opacity float64)
+	SetProgress(progress float64)
+	SetBadge(badge string)
+	SetMaterial(material string) error
+	MaterialSupported(material string) bool
+	SetTrayIcon(data []byte) error
+	SetTrayTooltip(tooltip string)
+	SetTrayMenu(menu string)
+	RemoveTray()
+	Notify(id, title, body string, silent bool) error
+	SetMenu(menu string)
+	ShowContextMenu(menu string, x, y int)
+	AttachOverlay(id string, view unsafe.Pointer, selector string)
+	PlaceOverlay(id string, x, y, width, height int, visible bool)
+	DetachOverlay(id string)
+	Reload()
+	ShowError(title, message string)
+	Dispatch(f func())
+
+	// Automation
+	StartAutomation(name string, handler func(method, args string) (string, error))
+
 	Close()
 }
+
+// FontDescription describes a font shown or chosen in the font picker
+type FontDescription struct {
+	Family string
+	Face   string // The face's name, EG: "Semibold Italic". Empty on Windows.
+	Size   int    // In points
+	Weight int    // From 100 to 900, as in CSS
+	Italic bool
+}
+
+// Display describes a display attached to the system. Areas are relative to
+// the top left of the primary display.
+type Display struct {
+	Bounds   image.Rectangle
+	WorkArea image.Rectangle // EG: excluding the taskbar or menu bar
+	Scale    float64         // Physical pixels per logical pixel
+	Primary  bool
+}
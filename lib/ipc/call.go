@@ -1,6 +1,7 @@
 package ipc
 
 import (
+	"encoding/base64"
 	"fmt"
 
 	"github.com/wailsapp/wails/lib/messages"
@@ -28,6 +29,35 @@ func processCallData(message *ipcMessage) (*ipcMessage, error) {
 		payload.Data = payloadMap["data"].(string)
 	}
 
+	// Check if the results are streamed
+	if stream, ok := payloadMap["stream"].(bool); ok {
+		payload.StreamResults = stream
+	}
+
+	// The byte slices sent with the call are given by their length when
+	// they follow the message in a binary frame, or as base64 otherwise
+	binary, _ := payloadMap["binary"].([]interface{})
+	offset := 0
+	for _, item := range binary {
+		switch value := item.(type) {
+		case string:
+			data, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid binary data in call: %s", err.Error())
+			}
+			payload.Binary = append(payload.Binary, data)
+		case float64:
+			size := int(value)
+			if size < 0 || offset+size > len(message.frame) {
+				return nil, fmt.Errorf("invalid binary data in call: the frame is too short")
+			}
+			payload.Binary = append(payload.Binary, message.frame[offset:offset+size])
+			offset += size
+		default:
+			return nil, fmt.Errorf("invalid binary data in call")
+		}
+	}
+
 	// Reassign payload to decoded data
 	message.Payload = &payload
 
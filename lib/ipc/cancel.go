@@ -0,0 +1,18 @@
+package ipc
+
+// Register the message handler
+func init() {
+	messageProcessors["cancel"] = processCancelData
+}
+
+// This processes the cancellation of the call with the same callback ID,
+// which cancels the context the bound method was given
+func processCancelData(message *ipcMessage) (*ipcMessage, error) {
+
+	err := message.hasCallbackID()
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
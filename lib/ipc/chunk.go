@@ -0,0 +1,78 @@
+package ipc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Register the message handler
+func init() {
+	messageProcessors["chunk"] = processChunkData
+}
+
+// This processes part of the data of a call too large to send in one
+// message. The chunks are joined with the call that has the same callback ID.
+func processChunkData(message *ipcMessage) (*ipcMessage, error) {
+
+	err := message.hasCallbackID()
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode chunk data
+	payloadMap, ok := message.Payload.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid chunk payload")
+	}
+	data, ok := payloadMap["data"].(string)
+	if !ok {
+		return nil, fmt.Errorf("data not given in chunk")
+	}
+
+	// Reassign payload to decoded data
+	message.Payload = data
+
+	return message, nil
+}
+
+// Chunks are discarded if the call they belong to hasn't arrived
+// within this time of the last chunk, EG: if the page was reloaded
+const chunkTimeout = time.Minute
+
+// pendingChunks is the data sent ahead in chunks for a call
+type pendingChunks struct {
+	data     strings.Builder
+	received time.Time
+}
+
+// addChunk adds the given chunk to the data of the call with the given
+// callback ID. The chunks of calls that never arrived are discarded.
+func (i *Manager) addChunk(callbackID string, data string, now time.Time) {
+	for id, chunks := range i.chunks {
+		if now.Sub(chunks.received) > chunkTimeout {
+			i.log.Debugf("Discarding the chunks of call %s", id)
+			delete(i.chunks, id)
+		}
+	}
+
+	chunks := i.chunks[callbackID]
+	if chunks == nil {
+		chunks = &pendingChunks{}
+		i.chunks[callbackID] = chunks
+	}
+	chunks.data.WriteString(data)
+	chunks.received = now
+}
+
+// joinChunks returns the given call data with the chunks sent
+// ahead of it for the call with the given callback ID
+func (i *Manager) joinChunks(callbackID string, data string) string {
+	chunks := i.chunks[callbackID]
+	if chunks == nil {
+		return data
+	}
+	delete(i.chunks, callbackID)
+	chunks.data.WriteString(data)
+	return chunks.data.String()
+}
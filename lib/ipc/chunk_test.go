@@ -0,0 +1,123 @@
+package ipc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+func TestChunkRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		chunkSize int
+		split     bool
+	}{
+		{"off", strings.Repeat("a", 100), 0, false},
+		{"small", "abc", 64, false},
+		{"split", strings.Repeat("abcdefgh", 10), 16, true},
+		{"multibyte", strings.Repeat("€", 20), 16, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Split the result as the app does
+			response := newSuccessResponse("main.Data.Get-1", tt.data)
+			response.chunkSize = tt.chunkSize
+			serialised, err := response.SerialiseChunks()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (len(serialised) > 1) != tt.split {
+				t.Fatalf("SerialiseChunks() returned %d chunks, want split %v", len(serialised), tt.split)
+			}
+
+			// Send each chunk ahead of the call, as the frontend does
+			manager := &Manager{chunks: make(map[string]*pendingChunks), log: logger.NewCustomLogger("IPC")}
+			var joined string
+			for index, chunk := range serialised {
+				decoded, err := hex.DecodeString(chunk)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !tt.split {
+					joined = string(decoded)
+					break
+				}
+				var message ipcResponseChunk
+				if err := json.Unmarshal(decoded, &message); err != nil {
+					t.Fatal(err)
+				}
+				if !utf8.ValidString(message.Chunk) {
+					t.Errorf("chunk %d splits a character: %q", index, message.Chunk)
+				}
+				if message.More != (index < len(serialised)-1) {
+					t.Errorf("chunk %d more = %v", index, message.More)
+				}
+				if index == len(serialised)-1 {
+					joined = manager.joinChunks(message.CallbackID, message.Chunk)
+					break
+				}
+				incoming, err := json.Marshal(map[string]interface{}{
+					"type":       "chunk",
+					"callbackID": message.CallbackID,
+					"payload":    map[string]interface{}{"data": message.Chunk},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				parsed, err := newIPCMessage(string(incoming), nil, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				manager.addChunk(parsed.CallbackID, parsed.Payload.(string), time.Now())
+			}
+
+			// Reassemble the chunks
+			var result ipcResponse
+			if err := json.Unmarshal([]byte(joined), &result); err != nil {
+				t.Fatalf("joined chunks %q: %v", joined, err)
+			}
+			if result.Data != tt.data {
+				t.Errorf("joined data = %q, want %q", result.Data, tt.data)
+			}
+			if len(manager.chunks) != 0 {
+				t.Errorf("%d calls' chunks were left behind", len(manager.chunks))
+			}
+		})
+	}
+}
+
+func TestChunkInvalid(t *testing.T) {
+	for _, message := range []string{
+		`{"type":"chunk","callbackID":"main.Data.Set-1","payload":"data"}`,
+		`{"type":"chunk","callbackID":"main.Data.Set-1","payload":{"data":1}}`,
+		`{"type":"chunk","payload":{"data":"data"}}`,
+	} {
+		if _, err := newIPCMessage(message, nil, nil); err == nil {
+			t.Errorf("newIPCMessage(%s) expected an error", message)
+		}
+	}
+}
+
+func TestChunkTimeout(t *testing.T) {
+	manager := &Manager{chunks: make(map[string]*pendingChunks), log: logger.NewCustomLogger("IPC")}
+	start := time.Now()
+	manager.addChunk("main.Data.Set-1", "abandoned", start)
+	manager.addChunk("main.Data.Set-2", "first ", start.Add(chunkTimeout/2))
+	manager.addChunk("main.Data.Set-2", "second ", start.Add(chunkTimeout+time.Second))
+
+	if _, ok := manager.chunks["main.Data.Set-1"]; ok {
+		t.Errorf("the chunks of a call that never arrived were kept")
+	}
+	if got := manager.joinChunks("main.Data.Set-2", "last"); got != "first second last" {
+		t.Errorf("joinChunks() = %q, want %q", got, "first second last")
+	}
+	if got := manager.joinChunks("main.Data.Set-3", "whole"); got != "whole" {
+		t.Errorf("joinChunks() without chunks = %q, want %q", got, "whole")
+	}
+}
@@ -0,0 +1,51 @@
+package ipc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Binary frames carry byte slices between the frontend and the backend
+// without encoding them as strings, where the transport allows it, EG: the
+// bridge's websocket. A frame is the length of its header as a big endian
+// uint32, the header, which is a message or response as JSON, then the bytes.
+const frameHeaderSize = 4
+
+// EncodeFrame returns the frame with the given header and bytes
+func EncodeFrame(header string, data []byte) []byte {
+	result := make([]byte, frameHeaderSize+len(header)+len(data))
+	binary.BigEndian.PutUint32(result, uint32(len(header)))
+	copy(result[frameHeaderSize:], header)
+	copy(result[frameHeaderSize+len(header):], data)
+	return result
+}
+
+// DecodeFrame returns the header and bytes of the given frame
+func DecodeFrame(frame []byte) (string, []byte, error) {
+	if len(frame) < frameHeaderSize {
+		return "", nil, fmt.Errorf("binary frame too short")
+	}
+	size := binary.BigEndian.Uint32(frame)
+	if uint64(size) > uint64(len(frame)-frameHeaderSize) {
+		return "", nil, fmt.Errorf("binary frame header too long")
+	}
+	end := frameHeaderSize + int(size)
+	return string(frame[frameHeaderSize:end]), frame[end:], nil
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// binaryResult returns the bytes of a call's result if it is a byte slice.
+// Results that are already JSON, EG: from isolated services, aren't.
+func binaryResult(result interface{}) ([]byte, bool) {
+	if result == nil {
+		return nil, false
+	}
+	value := reflect.ValueOf(result)
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() != reflect.Uint8 || value.Type() == rawMessageType {
+		return nil, false
+	}
+	return value.Bytes(), true
+}
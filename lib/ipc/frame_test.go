@@ -0,0 +1,89 @@
+package ipc
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/wailsapp/wails/lib/messages"
+)
+
+func TestFrame(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		data   []byte
+	}{
+		{"bytes", `{"callbackid":"main.Images.Load-1","binary":true}`, []byte{0, 1, 2, 255}},
+		{"empty", `{"callbackid":"main.Images.Load-2","binary":true}`, []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, data, err := DecodeFrame(EncodeFrame(tt.header, tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header != tt.header || !bytes.Equal(data, tt.data) {
+				t.Errorf("DecodeFrame() = %q, %v, want %q, %v", header, data, tt.header, tt.data)
+			}
+		})
+	}
+	if _, _, err := DecodeFrame([]byte{0, 0, 1, 0, '{'}); err == nil {
+		t.Errorf("expected an error for a truncated frame")
+	}
+}
+
+func TestBinaryResult(t *testing.T) {
+	type image []byte
+	tests := []struct {
+		name   string
+		result interface{}
+		want   bool
+	}{
+		{"bytes", []byte("png"), true},
+		{"named bytes", image("png"), true},
+		{"json", json.RawMessage(`"cG5n"`), false},
+		{"string", "png", false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := binaryResult(tt.result); got != tt.want {
+				t.Errorf("binaryResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		frame   []byte
+		want    [][]byte
+	}{
+		{"frame", `{"type":"call","callbackID":"main.Images.Save-1","payload":{"bindingName":"main.Images.Save","data":"[{\"$binary\":0},{\"$binary\":1}]","binary":[2,1]}}`, []byte{1, 2, 3}, [][]byte{{1, 2}, {3}}},
+		{"base64", `{"type":"call","callbackID":"main.Images.Save-2","payload":{"bindingName":"main.Images.Save","data":"[{\"$binary\":0}]","binary":["AQID"]}}`, nil, [][]byte{{1, 2, 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := newIPCMessage(tt.message, tt.frame, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := message.Payload.(*messages.CallData).Binary
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d byte slices, got %v", len(tt.want), got)
+			}
+			for index := range got {
+				if !bytes.Equal(got[index], tt.want[index]) {
+					t.Errorf("byte slice %d = %v, want %v", index, got[index], tt.want[index])
+				}
+			}
+		})
+	}
+	_, err := newIPCMessage(`{"type":"call","callbackID":"main.Images.Save-3","payload":{"bindingName":"main.Images.Save","data":"[]","binary":[4]}}`, []byte{1}, nil)
+	if err == nil {
+		t.Errorf("expected an error for a frame shorter than its byte slices")
+	}
+}
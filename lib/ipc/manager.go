@@ -1,12 +1,16 @@
 package ipc
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/wailsapp/wails/lib/interfaces"
 	"github.com/wailsapp/wails/lib/logger"
 	"github.com/wailsapp/wails/lib/messages"
+	"github.com/wailsapp/wails/lib/perf"
 )
 
 // Manager manages the IPC subsystem
@@ -20,6 +24,26 @@ type Manager struct {
 	bindingManager interfaces.BindingManager
 	running        bool
 	wg             sync.WaitGroup
+
+	// The size above which responses are sent in chunks
+	maxPayloadSize int
+
+	// The chunks received for calls too large to send in one message,
+	// by callback ID
+	chunks map[string]*pendingChunks
+
+	// Cancels the calls being processed, by callback ID
+	calls     map[string]context.CancelFunc
+	callsLock sync.Mutex
+
+	// Records how long calls take, if set
+	recorder *perf.Recorder
+
+	// Slows down and fails calls, if set
+	faults *perf.Faults
+
+	// Set if the results that are byte slices are sent as binary
+	binaryResults bool
 }
 
 // NewManager creates a new IPC Manager
@@ -27,6 +51,8 @@ func NewManager() interfaces.IPCManager {
 	result := &Manager{
 		messageQueue: make(chan *ipcMessage, 100),
 		quitChannel:  make(chan struct{}),
+		chunks:       make(map[string]*pendingChunks),
+		calls:        make(map[string]context.CancelFunc),
 		// 		signals:      make(chan os.Signal, 1),
 		log: logger.NewCustomLogger("IPC"),
 	}
@@ -38,6 +64,30 @@ func (i *Manager) BindRenderer(renderer interfaces.Renderer) {
 	i.renderer = renderer
 }
 
+// SetMaxPayloadSize sets the size in bytes above which call results are
+// streamed to the frontend in chunks. Bound methods may override it.
+func (i *Manager) SetMaxPayloadSize(size int) {
+	i.maxPayloadSize = size
+}
+
+// SetBinaryResults sets whether the results of calls that are byte slices
+// are sent to the frontend as binary, rather than as base64 in JSON, where
+// the renderer allows it
+func (i *Manager) SetBinaryResults(enabled bool) {
+	i.binaryResults = enabled
+}
+
+// RecordPerformance records how long each call takes with the given recorder
+func (i *Manager) RecordPerformance(recorder *perf.Recorder) {
+	i.recorder = recorder
+}
+
+// InjectFaults slows down and fails calls to bound methods as set by the
+// given faults
+func (i *Manager) InjectFaults(faults *perf.Faults) {
+	i.faults = faults
+}
+
 // Start the IPC Manager
 func (i *Manager) Start(eventManager interfaces.EventManager, bindingManager interfaces.BindingManager) {
 
@@ -61,16 +111,43 @@ func (i *Manager) Start(eventManager interfaces.EventManager, bindingManager int
 				switch incomingMessage.Type {
 				case "call":
 					callData := incomingMessage.Payload.(*messages.CallData)
+
+					// Join the data sent ahead in chunks
+					callData.Data = i.joinChunks(incomingMessage.CallbackID, callData.Data)
+
+					incomingMessage.maxPayloadSize = bindingManager.MaxPayloadSize(callData.BindingName)
+					if incomingMessage.maxPayloadSize == 0 {
+						incomingMessage.maxPayloadSize = i.maxPayloadSize
+					}
 					i.log.DebugFields("Processing call", logger.Fields{
 						"1D":          &incomingMessage,
 						"bindingName": callData.BindingName,
 						"data":        callData.Data,
 					})
+					callData.Context = i.startCall(incomingMessage.CallbackID)
+					if callData.StreamResults && incomingMessage.CallbackID != "" {
+						callData.Stream = incomingMessage.ReturnStream
+					}
 					go func() {
-						result, err := bindingManager.ProcessCall(callData)
+						defer i.endCall(incomingMessage.CallbackID)
+						started := time.Now()
+						var result interface{}
+						var err error
+						if i.faults != nil {
+							err = i.faults.Call(callData.Context, callData.BindingName)
+						}
+						if err == nil {
+							result, err = bindingManager.ProcessCall(callData)
+						}
+						if i.recorder != nil {
+							i.recorder.RecordCall(callData.BindingName, time.Since(started))
+							i.recorder.TraceCall(callData.BindingName, callData.Data, started, result, err)
+						}
 						i.log.DebugFields("processed call", logger.Fields{"result": result, "err": err})
 						if err != nil {
 							incomingMessage.ReturnError(err.Error())
+						} else if data, ok := binaryResult(result); ok && i.binaryResults {
+							incomingMessage.ReturnBinary(data)
 						} else {
 							incomingMessage.ReturnSuccess(result)
 						}
@@ -78,6 +155,12 @@ func (i *Manager) Start(eventManager interfaces.EventManager, bindingManager int
 							"1D": &incomingMessage,
 						})
 					}()
+				case "chunk":
+					i.addChunk(incomingMessage.CallbackID, incomingMessage.Payload.(string), time.Now())
+				case "cancel":
+					i.log.Debugf("Cancelling call %s", incomingMessage.CallbackID)
+					delete(i.chunks, incomingMessage.CallbackID)
+					i.cancelCall(incomingMessage.CallbackID)
 				case "event":
 
 					// Extract event data
@@ -132,13 +215,65 @@ func (i *Manager) Start(eventManager interfaces.EventManager, bindingManager int
 	}()
 }
 
+// startCall returns the context of the call with the given callback ID,
+// which is cancelled if the frontend cancels the call
+func (i *Manager) startCall(callbackID string) context.Context {
+	// Calls without a callback ID can't be cancelled
+	if callbackID == "" {
+		return context.Background()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	i.callsLock.Lock()
+	i.calls[callbackID] = cancel
+	i.callsLock.Unlock()
+	return ctx
+}
+
+// endCall releases the context of the call once it has returned
+func (i *Manager) endCall(callbackID string) {
+	i.callsLock.Lock()
+	cancel := i.calls[callbackID]
+	delete(i.calls, callbackID)
+	i.callsLock.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// cancelCall cancels the context of the call with the given callback ID.
+// Calls that have already returned are ignored.
+func (i *Manager) cancelCall(callbackID string) {
+	i.callsLock.Lock()
+	cancel := i.calls[callbackID]
+	i.callsLock.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
 // Dispatch receives JSON encoded messages from the renderer.
 // It processes the message to ensure that it is valid and places
-// the processed message on the message queue
-func (i *Manager) Dispatch(message string, cb interfaces.CallbackFunc) {
+// the processed message on the message queue. Binary responses are
+// sent with the binary callback, if the renderer gives one.
+func (i *Manager) Dispatch(message string, cb interfaces.CallbackFunc, binary interfaces.BinaryCallbackFunc) {
+	i.dispatch(message, nil, cb, binary)
+}
+
+// DispatchBinary receives messages from the renderer in binary frames,
+// which carry the byte slices sent with calls after the message
+func (i *Manager) DispatchBinary(frame []byte, cb interfaces.CallbackFunc, binary interfaces.BinaryCallbackFunc) {
+	message, data, err := DecodeFrame(frame)
+	if err != nil {
+		i.log.Errorf("Could not understand incoming frame: %s", err.Error())
+		return
+	}
+	i.dispatch(message, data, cb, binary)
+}
+
+func (i *Manager) dispatch(message string, frame []byte, cb interfaces.CallbackFunc, binary interfaces.BinaryCallbackFunc) {
 
 	// Create a new IPC Message
-	incomingMessage, err := newIPCMessage(message, i.SendResponse(cb))
+	incomingMessage, err := newIPCMessage(message, frame, i.SendResponse(cb))
 	if err != nil {
 		i.log.ErrorFields("Could not understand incoming message! ", map[string]interface{}{
 			"message": message,
@@ -146,6 +281,9 @@ func (i *Manager) Dispatch(message string, cb interfaces.CallbackFunc) {
 		})
 		return
 	}
+	if binary != nil {
+		incomingMessage.sendBinary = i.SendBinaryResponse(binary)
+	}
 
 	// Put message on queue
 	i.log.DebugFields("Message received", map[string]interface{}{
@@ -163,16 +301,35 @@ func (i *Manager) SendResponse(cb interfaces.CallbackFunc) func(i *ipcResponse)
 
 	return func(response *ipcResponse) error {
 		// Serialise the Message
-		data, err := response.Serialise()
+		data, err := response.SerialiseChunks()
 		if err != nil {
 			fmt.Printf(err.Error())
 			return err
 		}
-		return cb(data)
+		for _, chunk := range data {
+			err = cb(chunk)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
 }
 
+// SendBinaryResponse sends the given binary response back to the frontend
+// with the provided callback function
+func (i *Manager) SendBinaryResponse(cb interfaces.BinaryCallbackFunc) func(i *ipcResponse) error {
+
+	return func(response *ipcResponse) error {
+		header, err := json.Marshal(response)
+		if err != nil {
+			return err
+		}
+		return cb(string(header), response.binaryData)
+	}
+}
+
 // Shutdown is called when exiting the Application
 func (i *Manager) Shutdown() {
 	i.log.Debug("Shutdown called")
@@ -3,6 +3,7 @@ package ipc
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 )
 
 // Message handler
@@ -17,6 +18,15 @@ type ipcMessage struct {
 	Payload      interface{} `json:"payload"`
 	CallbackID   string      `json:"callbackid,omitempty"`
 	sendResponse func(*ipcResponse) error
+	sendBinary   func(*ipcResponse) error // Sends binary responses, if the renderer can
+	frame        []byte                   // The bytes after the message, if it came in a binary frame
+
+	// The size above which the response is sent in chunks
+	maxPayloadSize int
+
+	// Keeps the chunks of the responses to a call from being interleaved
+	// when it streams results from several goroutines
+	sendLock sync.Mutex
 }
 
 func parseMessage(incomingMessage string) (*ipcMessage, error) {
@@ -26,13 +36,14 @@ func parseMessage(incomingMessage string) (*ipcMessage, error) {
 	return &message, err
 }
 
-func newIPCMessage(incomingMessage string, responseFunction func(*ipcResponse) error) (*ipcMessage, error) {
+func newIPCMessage(incomingMessage string, frame []byte, responseFunction func(*ipcResponse) error) (*ipcMessage, error) {
 
 	// Parse the Message
 	message, err := parseMessage(incomingMessage)
 	if err != nil {
 		return nil, err
 	}
+	message.frame = frame
 
 	// Check message type is valid
 	messageProcessor := messageProcessors[message.Type]
@@ -71,9 +82,10 @@ func (m *ipcMessage) ReturnError(format string, args ...interface{}) error {
 
 	// Create response
 	response := newErrorResponse(m.CallbackID, fmt.Sprintf(format, args...))
+	response.chunkSize = m.maxPayloadSize
 
 	// Send response
-	return m.sendResponse(response)
+	return m.send(response)
 }
 
 // ReturnSuccess returns a success message back with the given data
@@ -87,7 +99,47 @@ func (m *ipcMessage) ReturnSuccess(data interface{}) error {
 
 	// Create the response
 	response := newSuccessResponse(m.CallbackID, data)
+	response.chunkSize = m.maxPayloadSize
 
 	// Send response
+	return m.send(response)
+}
+
+// ReturnStream sends data streamed by the call before it returns
+func (m *ipcMessage) ReturnStream(data interface{}) error {
+
+	err := m.hasCallbackID()
+	if err != nil {
+		return err
+	}
+
+	response := newStreamResponse(m.CallbackID, data)
+	response.chunkSize = m.maxPayloadSize
+
+	return m.send(response)
+}
+
+// ReturnBinary returns the given bytes as the call's result, without
+// encoding them as JSON if the renderer can send binary responses
+func (m *ipcMessage) ReturnBinary(data []byte) error {
+
+	if m.sendBinary == nil {
+		return m.ReturnSuccess(data)
+	}
+
+	err := m.hasCallbackID()
+	if err != nil {
+		return err
+	}
+
+	m.sendLock.Lock()
+	defer m.sendLock.Unlock()
+	return m.sendBinary(newBinaryResponse(m.CallbackID, data))
+}
+
+// send sends a response to the frontend, one at a time
+func (m *ipcMessage) send(response *ipcResponse) error {
+	m.sendLock.Lock()
+	defer m.sendLock.Unlock()
 	return m.sendResponse(response)
 }
@@ -3,6 +3,7 @@ package ipc
 import (
 	"encoding/hex"
 	"encoding/json"
+	"unicode/utf8"
 )
 
 // ipcResponse contains the response data from an RPC call
@@ -10,6 +11,18 @@ type ipcResponse struct {
 	CallbackID   string      `json:"callbackid"`
 	ErrorMessage string      `json:"error,omitempty"`
 	Data         interface{} `json:"data,omitempty"`
+	Stream       bool        `json:"stream,omitempty"` // Set for the results streamed before the call returns
+	Binary       bool        `json:"binary,omitempty"` // Set if the data is sent as bytes after the response
+	chunkSize    int         // The size above which the response is sent in chunks
+	binaryData   []byte      // The data of a binary response
+}
+
+// ipcResponseChunk holds part of a serialised response. The frontend joins
+// the chunks for a callback until one has no more after it.
+type ipcResponseChunk struct {
+	CallbackID string `json:"callbackid"`
+	Chunk      string `json:"chunk"`
+	More       bool   `json:"more,omitempty"`
 }
 
 // newErrorResponse returns the given error message to the frontend with the callbackid
@@ -34,6 +47,26 @@ func newSuccessResponse(callbackID string, data interface{}) *ipcResponse {
 	return result
 }
 
+// newStreamResponse returns the given data streamed by a call to the
+// frontend with the callbackid
+func newStreamResponse(callbackID string, data interface{}) *ipcResponse {
+	return &ipcResponse{
+		CallbackID: callbackID,
+		Data:       data,
+		Stream:     true,
+	}
+}
+
+// newBinaryResponse returns the given bytes to the frontend with the
+// callbackid, without encoding them in the response's JSON
+func newBinaryResponse(callbackID string, data []byte) *ipcResponse {
+	return &ipcResponse{
+		CallbackID: callbackID,
+		Binary:     true,
+		binaryData: data,
+	}
+}
+
 // Serialise formats the response to a string
 func (i *ipcResponse) Serialise() (string, error) {
 	b, err := json.Marshal(i)
@@ -43,3 +76,39 @@ func (i *ipcResponse) Serialise() (string, error) {
 	result := hex.EncodeToString(b)
 	return result, err
 }
+
+// SerialiseChunks formats the response to a list of strings to send in order.
+// A response larger than its chunk size is split into several messages, so
+// the frontend isn't frozen building and parsing one huge message.
+func (i *ipcResponse) SerialiseChunks() ([]string, error) {
+	b, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+	if i.chunkSize <= 0 || len(b) <= i.chunkSize {
+		return []string{hex.EncodeToString(b)}, nil
+	}
+
+	var result []string
+	for len(b) > 0 {
+		// Don't split a character between chunks
+		size := len(b)
+		if size > i.chunkSize {
+			size = i.chunkSize
+			for size > 1 && !utf8.RuneStart(b[size]) {
+				size--
+			}
+		}
+		chunk, err := json.Marshal(&ipcResponseChunk{
+			CallbackID: i.CallbackID,
+			Chunk:      string(b[:size]),
+			More:       size < len(b),
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, hex.EncodeToString(chunk))
+		b = b[size:]
+	}
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxRecentErrors is the number of error messages retained for diagnostics
+const maxRecentErrors = 20
+
+// recentErrorsHook is a logrus hook that keeps the most recent error
+// messages so they may be included in bug reports
+type recentErrorsHook struct {
+	lock     sync.Mutex
+	messages []string
+}
+
+func (h *recentErrorsHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel}
+}
+
+func (h *recentErrorsHook) Fire(entry *logrus.Entry) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.messages = append(h.messages, entry.Time.Format("2006-01-02T15:04:05Z07:00")+" "+entry.Message)
+	if len(h.messages) > maxRecentErrors {
+		h.messages = h.messages[len(h.messages)-maxRecentErrors:]
+	}
+	return nil
+}
+
+var recentErrors = &recentErrorsHook{}
+
+func init() {
+	GlobalLogger.AddHook(recentErrors)
+}
+
+// RecentErrors returns the most recent error messages logged, oldest first
+func RecentErrors() []string {
+	recentErrors.lock.Lock()
+	defer recentErrors.lock.Unlock()
+	result := make([]string, len(recentErrors.messages))
+	copy(result, recentErrors.messages)
+	return result
+}
@@ -1,7 +1,23 @@
 package messages
 
+import "context"
+
 // CallData represents a call to a Go function/method
 type CallData struct {
 	BindingName string `json:"bindingName"`
 	Data        string `json:"data,omitempty"`
+
+	// Set if the frontend handles the results streamed by the call, which
+	// are otherwise returned as its result
+	StreamResults bool `json:"stream,omitempty"`
+
+	// Cancelled when the frontend cancels the call, if set
+	Context context.Context `json:"-"`
+
+	// Sends the results streamed by the call before it returns, if set
+	Stream func(data interface{}) error `json:"-"`
+
+	// The byte slices sent with the call, which are referred to in Data
+	// by {"$binary": <index>}
+	Binary [][]byte `json:"-"`
 }
@@ -0,0 +1,216 @@
+// Package patch updates the frontend assets of an application without
+// replacing its binary. A patch is a zip archive of the new assets, signed
+// with the developer's ed25519 key. Patches are staged while the app runs
+// and switched to when it next starts.
+package patch
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The files in a patch archive
+const (
+	ManifestFile = "patch.json"
+	HTMLFile     = "index.html"
+	JSFile       = "app.js"
+	CSSFile      = "app.css"
+)
+
+// The patches are saved with their signature before the archive, so each is
+// switched with a single rename
+const (
+	stagedFilename  = "staged.patch"
+	currentFilename = "current.patch"
+)
+
+// ErrInvalidSignature is returned for patches that weren't signed with the
+// app's key or have been modified since
+var ErrInvalidSignature = errors.New("the asset patch has an invalid signature")
+
+// errOtherVersion is returned for patches made for a different version of
+// the app, EG: after a full update has replaced the binary
+var errOtherVersion = errors.New("the asset patch is for another version of the app")
+
+// Manifest describes a patch
+type Manifest struct {
+	// The version of the app the patch applies to, matching AppConfig.Version
+	Version string `json:"version"`
+}
+
+// Assets are the frontend assets in a patch. Assets that aren't
+// in the patch are empty.
+type Assets struct {
+	HTML string
+	JS   string
+	CSS  string
+}
+
+// Patcher stages and loads the patches of an app
+type Patcher struct {
+	dir     string
+	key     ed25519.PublicKey
+	version string
+}
+
+// New creates a Patcher that keeps its patches in the given directory. The
+// key is the hex encoded ed25519 public key the patches are signed with,
+// and the version is the app's version.
+func New(dir string, key string, version string) (*Patcher, error) {
+	publicKey, err := hex.DecodeString(key)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid asset patch key: expected %d hex encoded bytes", ed25519.PublicKeySize)
+	}
+	return &Patcher{
+		dir:     dir,
+		key:     publicKey,
+		version: version,
+	}, nil
+}
+
+// Dir returns the directory the patches of the app with the given title are
+// kept in, under the app's title in the user's config directory
+func Dir(title string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	name := strings.ToLower(strings.Replace(title, " ", "-", -1))
+	return filepath.Join(configDir, name, "patches"), nil
+}
+
+// Stage verifies the patch and saves it to be loaded the next time the app
+// starts, replacing any patch that is already staged
+func (p *Patcher) Stage(archive, signature []byte) error {
+	_, err := p.read(archive, signature)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(p.dir, 0700)
+	if err != nil {
+		return err
+	}
+	temp, err := ioutil.TempFile(p.dir, stagedFilename+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	_, err = temp.Write(append(append([]byte{}, signature...), archive...))
+	if err == nil {
+		err = temp.Sync()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), filepath.Join(p.dir, stagedFilename))
+}
+
+// Load switches to the staged patch, if there is one, and returns the
+// assets of the current patch. It returns nil if there is no patch or it is
+// for another version of the app, in which case it is discarded.
+func (p *Patcher) Load() (*Assets, error) {
+	current := filepath.Join(p.dir, currentFilename)
+	staged := filepath.Join(p.dir, stagedFilename)
+	if _, err := os.Stat(staged); err == nil {
+		err = os.Rename(staged, current)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := ioutil.ReadFile(current)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < ed25519.SignatureSize {
+		return nil, ErrInvalidSignature
+	}
+
+	assets, err := p.read(data[ed25519.SignatureSize:], data[:ed25519.SignatureSize])
+	if err == errOtherVersion {
+		return nil, p.Discard()
+	}
+	return assets, err
+}
+
+// Discard removes the current and staged patches, so the app's own assets
+// are used when it next starts
+func (p *Patcher) Discard() error {
+	for _, filename := range []string{stagedFilename, currentFilename} {
+		err := os.Remove(filepath.Join(p.dir, filename))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// read verifies the patch and returns its assets
+func (p *Patcher) read(archive, signature []byte) (*Assets, error) {
+	if !ed25519.Verify(p.key, archive, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		switch file.Name {
+		case ManifestFile, HTMLFile, JSFile, CSSFile:
+		default:
+			continue
+		}
+		contents, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		files[file.Name] = contents
+	}
+
+	manifestData, ok := files[ManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("the asset patch has no %s", ManifestFile)
+	}
+	var manifest Manifest
+	err = json.Unmarshal([]byte(manifestData), &manifest)
+	if err != nil {
+		return nil, err
+	}
+	if p.version != "" && manifest.Version != p.version {
+		return nil, errOtherVersion
+	}
+
+	return &Assets{
+		HTML: files[HTMLFile],
+		JS:   files[JSFile],
+		CSS:  files[CSSFile],
+	}, nil
+}
+
+func readZipFile(file *zip.File) (string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	return string(data), err
+}
@@ -0,0 +1,66 @@
+package patch
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+)
+
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, contents := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Write([]byte(contents))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestStageAndLoad(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patcher, err := New(t.TempDir(), hex.EncodeToString(publicKey), "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive := makeArchive(t, map[string]string{ManifestFile: `{"version":"1.0.0"}`, JSFile: "fixed()"})
+	signature := ed25519.Sign(privateKey, archive)
+	if err := patcher.Stage(archive[1:], signature); err != ErrInvalidSignature {
+		t.Errorf("Stage() of a modified archive error = %v, want ErrInvalidSignature", err)
+	}
+	if err := patcher.Stage(archive, signature); err != nil {
+		t.Fatal(err)
+	}
+
+	assets, err := patcher.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assets == nil || assets.JS != "fixed()" || assets.CSS != "" {
+		t.Fatalf("Load() = %+v, want the patched JS", assets)
+	}
+
+	// The patch stays current after the switch
+	assets, err = patcher.Load()
+	if err != nil || assets == nil || assets.JS != "fixed()" {
+		t.Errorf("Load() after the switch = %+v, %v", assets, err)
+	}
+
+	// Patches for other versions are discarded
+	patcher.version = "1.1.0"
+	assets, err = patcher.Load()
+	if err != nil || assets != nil {
+		t.Errorf("Load() for another version = %+v, %v, want nil", assets, err)
+	}
+}
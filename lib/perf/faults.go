@@ -0,0 +1,132 @@
+package perf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFailure is returned for the calls failed by Faults
+var ErrInjectedFailure = errors.New("failure injected by --failure-rate")
+
+// Faults slows down and fails the calls and events between the frontend and
+// the backend at random, so the frontend's loading states and error handling
+// can be tried before it runs on a slow machine. It is set with --latency,
+// --jitter and --failure-rate in debug builds. The runtime's own calls and
+// events aren't affected. It is safe for concurrent use.
+type Faults struct {
+	// Added to each call and event
+	Latency time.Duration
+
+	// Up to this much more is added at random
+	Jitter time.Duration
+
+	// The fraction of calls that fail and of events that are dropped, from
+	// 0 to 1
+	FailureRate float64
+
+	lock   sync.Mutex
+	random *rand.Rand
+}
+
+// NewFaults returns the faults with the given latency, jitter and failure
+// rate
+func NewFaults(latency, jitter time.Duration, failureRate float64) (*Faults, error) {
+	if latency < 0 || jitter < 0 {
+		return nil, fmt.Errorf("the latency and jitter must not be negative")
+	}
+	if failureRate < 0 || failureRate > 1 {
+		return nil, fmt.Errorf("the failure rate must be between 0 and 1, or 0%% and 100%%")
+	}
+	return &Faults{
+		Latency:     latency,
+		Jitter:      jitter,
+		FailureRate: failureRate,
+		random:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// ParseFailureRate parses a failure rate given as a fraction, EG: 0.1, or
+// as a percentage, EG: 10%
+func ParseFailureRate(value string) (float64, error) {
+	divisor := 1.0
+	if strings.HasSuffix(value, "%") {
+		value = strings.TrimSuffix(value, "%")
+		divisor = 100
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid failure rate '%s'", value)
+	}
+	return rate / divisor, nil
+}
+
+// affects returns true if the faults apply to the named binding or event,
+// which is the case unless it belongs to the runtime
+func affects(name string) bool {
+	return !strings.HasPrefix(name, ".wails.") && !strings.HasPrefix(name, "wails:")
+}
+
+// delay returns how long to delay a call or event
+func (f *Faults) delay() time.Duration {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	result := f.Latency
+	if f.Jitter > 0 {
+		result += time.Duration(f.random.Int63n(int64(f.Jitter) + 1))
+	}
+	return result
+}
+
+// fail returns true as often as the failure rate
+func (f *Faults) fail() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.random.Float64() < f.FailureRate
+}
+
+// Call delays a call to the named binding, returning early with the
+// context's error if it is cancelled, then fails it as often as the failure
+// rate with ErrInjectedFailure
+func (f *Faults) Call(ctx context.Context, bindingName string) error {
+	if !affects(bindingName) {
+		return nil
+	}
+	if delay := f.delay(); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if f.fail() {
+		return ErrInjectedFailure
+	}
+	return nil
+}
+
+// Event delivers the named event with the given function once it has been
+// delayed, unless it is dropped as often as the failure rate, in which case
+// it returns false. Delayed events are delivered in the background.
+func (f *Faults) Event(eventName string, deliver func()) bool {
+	if !affects(eventName) {
+		deliver()
+		return true
+	}
+	if f.fail() {
+		return false
+	}
+	if delay := f.delay(); delay > 0 {
+		time.AfterFunc(delay, deliver)
+		return true
+	}
+	deliver()
+	return true
+}
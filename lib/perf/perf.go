@@ -0,0 +1,138 @@
+// Package perf records the latency of calls to bound methods alongside the
+// frontend's performance entries, for the report printed when a debug build
+// run with --perf exits, traces the recent calls and events for the dev
+// overlay, and slows down and fails calls and events on purpose.
+package perf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ObserveEvent asks the frontend to start sending its performance entries
+const ObserveEvent = "wails:perf:observe"
+
+// EntriesEvent is emitted by the frontend with a batch of its entries
+const EntriesEvent = "wails:perf:entries"
+
+// Entry is a frontend PerformanceEntry. Times are in milliseconds since the
+// page started loading.
+type Entry struct {
+	Name      string  `json:"name"`
+	EntryType string  `json:"entryType"`
+	StartTime float64 `json:"startTime"`
+	Duration  float64 `json:"duration"`
+}
+
+// calls holds the latency of the calls to a bound method
+type calls struct {
+	count int
+	total time.Duration
+	max   time.Duration
+}
+
+// Recorder collects the calls and entries for the report. It is safe for
+// concurrent use.
+type Recorder struct {
+	lock    sync.Mutex
+	calls   map[string]*calls
+	entries []Entry
+
+	// The recent calls and events, see Traces
+	traces []Trace
+	seq    int
+}
+
+// NewRecorder creates a new Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		calls: make(map[string]*calls),
+	}
+}
+
+// RecordCall records a call to the named bound method that took the given
+// time to process
+func (r *Recorder) RecordCall(name string, duration time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	stats := r.calls[name]
+	if stats == nil {
+		stats = &calls{}
+		r.calls[name] = stats
+	}
+	stats.count++
+	stats.total += duration
+	if duration > stats.max {
+		stats.max = duration
+	}
+}
+
+// RecordEntries records entries sent by the frontend
+func (r *Recorder) RecordEntries(entries []Entry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.entries = append(r.entries, entries...)
+}
+
+// Report writes the frontend's timings, in the order they happened, then the
+// bound methods, slowest in total first
+func (r *Recorder) Report(w io.Writer) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entries := append([]Entry{}, r.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartTime < entries[j].StartTime
+	})
+
+	var longTasks int
+	var longTaskTime, longestTask float64
+	lines := []string{"Frontend:"}
+	for _, entry := range entries {
+		switch entry.EntryType {
+		case "longtask":
+			longTasks++
+			longTaskTime += entry.Duration
+			if entry.Duration > longestTask {
+				longestTask = entry.Duration
+			}
+		case "mark":
+			lines = append(lines, fmt.Sprintf("  %9.1fms  mark     %s", entry.StartTime, entry.Name))
+		default:
+			lines = append(lines, fmt.Sprintf("  %9.1fms  %-8s %s took %.1fms", entry.StartTime, entry.EntryType, entry.Name, entry.Duration))
+		}
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "  No entries were received")
+	}
+	if longTasks > 0 {
+		lines = append(lines, fmt.Sprintf("  %d long tasks blocked the page for %.1fms, the longest for %.1fms", longTasks, longTaskTime, longestTask))
+	}
+
+	names := make([]string, 0, len(r.calls))
+	for name := range r.calls {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return r.calls[names[i]].total > r.calls[names[j]].total
+	})
+	lines = append(lines, "", "Backend calls:")
+	for _, name := range names {
+		stats := r.calls[name]
+		average := stats.total / time.Duration(stats.count)
+		lines = append(lines, fmt.Sprintf("  %-40s %6d calls  avg %-10s max %-10s total %s", name, stats.count, average, stats.max, stats.total))
+	}
+	if len(names) == 0 {
+		lines = append(lines, "  No calls were made")
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
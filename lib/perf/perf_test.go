@@ -0,0 +1,128 @@
+package perf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	recorder := NewRecorder()
+	recorder.RecordCall("main.Counter.Add", 2*time.Millisecond)
+	recorder.RecordCall("main.Counter.Add", 4*time.Millisecond)
+	recorder.RecordCall("main.Files.List", 20*time.Millisecond)
+	recorder.RecordEntries([]Entry{
+		{Name: "rendered", EntryType: "mark", StartTime: 250},
+		{Name: "document", EntryType: "navigation", StartTime: 0, Duration: 180},
+		{Name: "self", EntryType: "longtask", StartTime: 300, Duration: 120},
+		{Name: "self", EntryType: "longtask", StartTime: 600, Duration: 60},
+	})
+
+	var report strings.Builder
+	if err := recorder.Report(&report); err != nil {
+		t.Fatal(err)
+	}
+	output := report.String()
+
+	if strings.Index(output, "navigation") > strings.Index(output, "rendered") {
+		t.Errorf("expected the entries in the order they happened:\n%s", output)
+	}
+	if !strings.Contains(output, "2 long tasks blocked the page for 180.0ms, the longest for 120.0ms") {
+		t.Errorf("expected the long tasks to be summarised:\n%s", output)
+	}
+	if strings.Index(output, "main.Files.List") > strings.Index(output, "main.Counter.Add") {
+		t.Errorf("expected the slowest method first:\n%s", output)
+	}
+	if !strings.Contains(output, "avg 3ms") {
+		t.Errorf("expected the average latency of main.Counter.Add:\n%s", output)
+	}
+}
+
+func TestTraces(t *testing.T) {
+	recorder := NewRecorder()
+	recorder.TraceCall("main.Counter.Add", `[1]`, time.Now(), 2, nil)
+	recorder.TraceEvent("counter:changed", "backend", []interface{}{2})
+	recorder.TraceEvent(OverlayEventPrefix+"fetch", "frontend", nil)
+	recorder.TraceCall("main.Files.List", `["/"]`, time.Now(), nil, fmt.Errorf("not found"))
+
+	traces := recorder.Traces(0)
+	if len(traces) != 3 {
+		t.Fatalf("expected 3 traces without the overlay's event, got %+v", traces)
+	}
+	if traces[0].Size != 3 || traces[0].ResultSize != 1 {
+		t.Errorf("expected the sizes of the call's parameters and result, got %+v", traces[0])
+	}
+	if traces[1].Kind != "event" || traces[1].Source != "backend" || traces[1].Size != 3 {
+		t.Errorf("expected the backend's event, got %+v", traces[1])
+	}
+	if traces[2].Error != "not found" {
+		t.Errorf("expected the call's error, got %+v", traces[2])
+	}
+	if after := recorder.Traces(traces[1].Seq); len(after) != 1 || after[0].Name != "main.Files.List" {
+		t.Errorf("expected the traces after the second, got %+v", after)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		bindingName string
+		failureRate float64
+		want        error
+	}{
+		{"failing", "main.Counter.Add", 1, ErrInjectedFailure},
+		{"passing", "main.Counter.Add", 0, nil},
+		{"runtime", ".wails.Window.Fullscreen", 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faults, err := NewFaults(time.Millisecond, time.Millisecond, tt.failureRate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := faults.Call(context.Background(), tt.bindingName); got != tt.want {
+				t.Errorf("Call() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	faults, _ := NewFaults(time.Hour, 0, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := faults.Call(ctx, "main.Counter.Add"); err != context.Canceled {
+		t.Errorf("expected the delay to end when the call is cancelled, got %v", err)
+	}
+
+	faults, _ = NewFaults(0, 0, 1)
+	delivered := false
+	if faults.Event("counter:changed", func() { delivered = true }) || delivered {
+		t.Errorf("expected the event to be dropped")
+	}
+	if !faults.Event("wails:ready", func() { delivered = true }) || !delivered {
+		t.Errorf("expected the runtime's event to be delivered")
+	}
+}
+
+func TestParseFailureRate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"0.25", 0.25},
+		{"25%", 0.25},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseFailureRate(tt.value)
+			if err != nil || got != tt.want {
+				t.Errorf("ParseFailureRate() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+	if _, err := ParseFailureRate("often"); err == nil {
+		t.Errorf("expected an error for an invalid rate")
+	}
+}
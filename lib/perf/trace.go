@@ -0,0 +1,117 @@
+package perf
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// OverlayEventPrefix starts the events of the dev overlay, which aren't
+// traced so that it doesn't list its own events
+const OverlayEventPrefix = "wails:devoverlay:"
+
+// The number of traces kept for the dev overlay, the oldest being dropped
+const maxTraces = 1000
+
+// Trace is a call to a bound method or an event, as listed by the dev
+// overlay
+type Trace struct {
+	// Numbers the traces in the order they were recorded, from 1
+	Seq int `json:"seq"`
+
+	// "call" or "event"
+	Kind string `json:"kind"`
+
+	// The bound method called or the event's name
+	Name string `json:"name"`
+
+	// Whether the event was emitted by the "frontend" or the "backend"
+	Source string `json:"source,omitempty"`
+
+	// When the call started or the event was emitted, in milliseconds since
+	// the Unix epoch
+	Time int64 `json:"time"`
+
+	// How long the call took, in milliseconds
+	Duration float64 `json:"duration"`
+
+	// The size of the call's parameters or the event's data, and of the
+	// call's result, in bytes of JSON
+	Size       int `json:"size"`
+	ResultSize int `json:"resultSize"`
+
+	// The error the call failed with
+	Error string `json:"error,omitempty"`
+}
+
+// jsonSize returns the size of the value encoded as JSON
+func jsonSize(value interface{}) int {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// addTrace numbers the trace and keeps it
+func (r *Recorder) addTrace(trace Trace) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.seq++
+	trace.Seq = r.seq
+	r.traces = append(r.traces, trace)
+	if len(r.traces) > maxTraces {
+		r.traces = append([]Trace{}, r.traces[len(r.traces)-maxTraces:]...)
+	}
+}
+
+// TraceCall records a call to the named bound method with the given JSON
+// encoded parameters, which started at the given time and returned the
+// result or failed with the error
+func (r *Recorder) TraceCall(name string, params string, started time.Time, result interface{}, err error) {
+	trace := Trace{
+		Kind:     "call",
+		Name:     name,
+		Time:     started.UnixNano() / int64(time.Millisecond),
+		Duration: float64(time.Since(started)) / float64(time.Millisecond),
+		Size:     len(params),
+	}
+	if err != nil {
+		trace.Error = err.Error()
+	} else if result != nil {
+		trace.ResultSize = jsonSize(result)
+	}
+	r.addTrace(trace)
+}
+
+// TraceEvent records an event emitted by the given source, "frontend" or
+// "backend", with the given data
+func (r *Recorder) TraceEvent(name string, source string, data interface{}) {
+	if strings.HasPrefix(name, OverlayEventPrefix) {
+		return
+	}
+	trace := Trace{
+		Kind:   "event",
+		Name:   name,
+		Source: source,
+		Time:   time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	if data != nil {
+		trace.Size = jsonSize(data)
+	}
+	r.addTrace(trace)
+}
+
+// Traces returns the traces recorded after the one with the given number,
+// oldest first
+func (r *Recorder) Traces(after int) []Trace {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := []Trace{}
+	for _, trace := range r.traces {
+		if trace.Seq > after {
+			result = append(result, trace)
+		}
+	}
+	return result
+}
@@ -3,10 +3,14 @@ package renderer
 import (
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
 	"net/http"
 	"sync"
+	"unsafe"
 
 	"github.com/gorilla/websocket"
+	"github.com/wailsapp/wails/lib/event"
 	"github.com/wailsapp/wails/lib/interfaces"
 	"github.com/wailsapp/wails/lib/logger"
 	"github.com/wailsapp/wails/lib/messages"
@@ -43,11 +47,16 @@ type Bridge struct {
 
 	lock     sync.Mutex
 	sessions map[string]*session
+
+	// The events emitted while no frontend is connected, EG: while the
+	// page reloads. They are delivered once the next one has loaded.
+	eventQueue *event.Queue
 }
 
 // Initialise the Bridge Renderer
 func (h *Bridge) Initialise(appConfig interfaces.AppConfig, ipcManager interfaces.IPCManager, eventManager interfaces.EventManager) error {
 	h.sessions = map[string]*session{}
+	h.eventQueue = event.NewQueue()
 	h.ipcManager = ipcManager
 	h.appConfig = appConfig
 	h.eventManager = eventManager
@@ -57,7 +66,18 @@ func (h *Bridge) Initialise(appConfig interfaces.AppConfig, ipcManager interface
 }
 
 func (h *Bridge) wsBridgeHandler(w http.ResponseWriter, r *http.Request) {
-	conn, err := websocket.Upgrade(w, r, w.Header(), 1024, 1024)
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+
+		// Offer the browser per message compression
+		EnableCompression: h.appConfig.GetBridgeCompressionThreshold() > 0,
+
+		// Errors are returned below and all origins are allowed
+		Error:       func(http.ResponseWriter, *http.Request, int, error) {},
+		CheckOrigin: func(*http.Request) bool { return true },
+	}
+	conn, err := upgrader.Upgrade(w, r, w.Header())
 	if err != nil {
 		http.Error(w, "Could not open websocket connection", http.StatusBadRequest)
 	}
@@ -70,7 +90,8 @@ func (h *Bridge) startSession(conn *websocket.Conn) {
 		h.bindingCache,
 		h.ipcManager,
 		logger.NewCustomLogger("BridgeSession"),
-		h.eventManager)
+		h.eventManager,
+		h.appConfig.GetBridgeCompressionThreshold())
 
 	conn.SetCloseHandler(func(int, string) error {
 		h.log.Infof("Connection dropped [%s].", s.Identifier())
@@ -115,6 +136,13 @@ func (h *Bridge) SelectFile(title string, filter string) string {
 	return ""
 }
 
+// SelectFiles is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SelectFiles(title string, directory string, formats string, multiple bool) []string {
+	h.log.Warn("SelectFiles() unsupported in bridge mode")
+	return nil
+}
+
 // SelectDirectory is unsupported for Bridge but required
 // for the Renderer interface
 func (h *Bridge) SelectDirectory() string {
@@ -122,6 +150,41 @@ func (h *Bridge) SelectDirectory() string {
 	return ""
 }
 
+// SelectDirectories is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SelectDirectories(title string) []string {
+	h.log.Warn("SelectDirectories() unsupported in bridge mode")
+	return nil
+}
+
+// SelectDirectoriesWithOptions is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SelectDirectoriesWithOptions(title string, directory string, multiple bool) []string {
+	h.log.Warn("SelectDirectoriesWithOptions() unsupported in bridge mode")
+	return nil
+}
+
+// ShowMessage is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) ShowMessage(messageType string, title string, message string, buttons []string) int {
+	h.log.Warn("ShowMessage() unsupported in bridge mode")
+	return -1
+}
+
+// ColorPicker is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) ColorPicker(title string, initial color.RGBA) (color.RGBA, bool) {
+	h.log.Warn("ColorPicker() unsupported in bridge mode")
+	return initial, false
+}
+
+// FontPicker is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) FontPicker(title string, initial interfaces.FontDescription) (interfaces.FontDescription, bool) {
+	h.log.Warn("FontPicker() unsupported in bridge mode")
+	return interfaces.FontDescription{}, false
+}
+
 // SelectSaveFile is unsupported for Bridge but required
 // for the Renderer interface
 func (h *Bridge) SelectSaveFile(title string, filter string) string {
@@ -129,6 +192,13 @@ func (h *Bridge) SelectSaveFile(title string, filter string) string {
 	return ""
 }
 
+// SelectSaveFileWithFormats is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SelectSaveFileWithFormats(title string, directory string, filename string, formats string, createDirectories, confirmOverwrite bool) (string, int) {
+	h.log.Warn("SelectSaveFileWithFormats() unsupported in bridge mode")
+	return "", -1
+}
+
 // NotifyEvent notifies the frontend of an event
 func (h *Bridge) NotifyEvent(event *messages.EventData) error {
 
@@ -140,6 +210,19 @@ func (h *Bridge) NotifyEvent(event *messages.EventData) error {
 		return err
 	}
 
+	// Hold the events emitted while no frontend is connected, and
+	// deliver them once the next one has loaded
+	h.lock.Lock()
+	connected := len(h.sessions) > 0
+	h.lock.Unlock()
+	if !connected {
+		h.eventQueue.Push(event)
+		return nil
+	}
+	if event.Name == "wails:loaded" {
+		defer h.deliverQueuedEvents()
+	}
+
 	// Default data is a blank array
 	data := []byte("[]")
 
@@ -179,6 +262,13 @@ func (h *Bridge) NotifyEvent(event *messages.EventData) error {
 	return nil
 }
 
+// deliverQueuedEvents sends the events emitted while no frontend was connected
+func (h *Bridge) deliverQueuedEvents() {
+	for _, event := range h.eventQueue.Drain() {
+		h.NotifyEvent(event)
+	}
+}
+
 // SetColour is unsupported for Bridge but required
 // for the Renderer interface
 func (h *Bridge) SetColour(colour string) error {
@@ -198,6 +288,12 @@ func (h *Bridge) SetMaxSize(width, height int) {
 	h.log.Warn("SetMaxSize() unsupported in bridge mode")
 }
 
+// SetAspectRatio is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetAspectRatio(width, height int) {
+	h.log.Warn("SetAspectRatio() unsupported in bridge mode")
+}
+
 // Fullscreen is unsupported for Bridge but required
 // for the Renderer interface
 func (h *Bridge) Fullscreen() {
@@ -210,12 +306,294 @@ func (h *Bridge) UnFullscreen() {
 	h.log.Warn("UnFullscreen() unsupported in bridge mode")
 }
 
+// Minimise is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Minimise() {
+	h.log.Warn("Minimise() unsupported in bridge mode")
+}
+
+// Maximise is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Maximise() {
+	h.log.Warn("Maximise() unsupported in bridge mode")
+}
+
+// Show is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Show() {
+	h.log.Warn("Show() unsupported in bridge mode")
+}
+
+// Hide is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Hide() {
+	h.log.Warn("Hide() unsupported in bridge mode")
+}
+
+// AttachOverlay is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) AttachOverlay(id string, view unsafe.Pointer, selector string) {
+	h.log.WarnFields("AttachOverlay() unsupported in bridge mode", logger.Fields{"id": id})
+}
+
+// PlaceOverlay is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) PlaceOverlay(id string, x, y, width, height int, visible bool) {
+	h.log.Warn("PlaceOverlay() unsupported in bridge mode")
+}
+
+// DetachOverlay is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) DetachOverlay(id string) {
+	h.log.WarnFields("DetachOverlay() unsupported in bridge mode", logger.Fields{"id": id})
+}
+
+// Restore is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Restore() {
+	h.log.Warn("Restore() unsupported in bridge mode")
+}
+
 // SetTitle is currently unsupported for Bridge but required
 // for the Renderer interface
 func (h *Bridge) SetTitle(title string) {
 	h.log.WarnFields("SetTitle() unsupported in bridge mode", logger.Fields{"title": title})
 }
 
+// SetSystemGestures is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetSystemGestures(enabled bool) {
+	h.log.Warn("SetSystemGestures() unsupported in bridge mode")
+}
+
+// StartDrag is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) StartDrag(files []string) bool {
+	h.log.Warn("StartDrag() unsupported in bridge mode")
+	return false
+}
+
+// ShowAbout is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) ShowAbout() {
+	h.log.Warn("ShowAbout() unsupported in bridge mode")
+}
+
+// SetIcon is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetIcon(data []byte) error {
+	h.log.Warn("SetIcon() unsupported in bridge mode")
+	return nil
+}
+
+// SetAlwaysOnTop is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetAlwaysOnTop(onTop bool) {
+	h.log.Warn("SetAlwaysOnTop() unsupported in bridge mode")
+}
+
+// SetBorderless is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetBorderless(borderless bool) {
+	h.log.Warn("SetBorderless() unsupported in bridge mode")
+}
+
+// SetSize is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetSize(width, height int) {
+	h.log.Warn("SetSize() unsupported in bridge mode")
+}
+
+// SnapToCorner is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SnapToCorner(corner, margin int) {
+	h.log.Warn("SnapToCorner() unsupported in bridge mode")
+}
+
+// SetIgnoreMouseEvents is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetIgnoreMouseEvents(ignore bool, forwardMove bool) {
+	h.log.Warn("SetIgnoreMouseEvents() unsupported in bridge mode")
+}
+
+// SetInputRegions is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetInputRegions(regions []image.Rectangle) {
+	h.log.Warn("SetInputRegions() unsupported in bridge mode")
+}
+
+// Displays is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Displays() []interfaces.Display {
+	h.log.Warn("Displays() unsupported in bridge mode")
+	return nil
+}
+
+// CursorDisplay is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) CursorDisplay() int {
+	h.log.Warn("CursorDisplay() unsupported in bridge mode")
+	return 0
+}
+
+// PlaceOnDisplay is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) PlaceOnDisplay(display, edge, margin int) {
+	h.log.Warn("PlaceOnDisplay() unsupported in bridge mode")
+}
+
+// SetPosition is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetPosition(x, y int) {
+	h.log.Warn("SetPosition() unsupported in bridge mode")
+}
+
+// Position is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Position() (x, y int) {
+	h.log.Warn("Position() unsupported in bridge mode")
+	return 0, 0
+}
+
+// Size is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Size() (width, height int) {
+	h.log.Warn("Size() unsupported in bridge mode")
+	return 0, 0
+}
+
+// SetTrafficLightPosition is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetTrafficLightPosition(x, y int) {
+	h.log.Warn("SetTrafficLightPosition() unsupported in bridge mode")
+}
+
+// TitleBarButtonArea is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) TitleBarButtonArea() image.Rectangle {
+	h.log.Warn("TitleBarButtonArea() unsupported in bridge mode")
+	return image.Rectangle{}
+}
+
+// SetTitleBarColour is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetTitleBarColour(background, symbol string) error {
+	h.log.Warn("SetTitleBarColour() unsupported in bridge mode")
+	return nil
+}
+
+// Reload is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Reload() {
+	h.log.Warn("Reload() unsupported in bridge mode")
+}
+
+// ShowError is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) ShowError(title, message string) {
+	h.log.Warn("ShowError() unsupported in bridge mode")
+}
+
+// ShowEmojiPicker is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) ShowEmojiPicker() {
+	h.log.Warn("ShowEmojiPicker() unsupported in bridge mode")
+}
+
+// RequestUserAttention is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) RequestUserAttention(critical bool) {
+	h.log.Warn("RequestUserAttention() unsupported in bridge mode")
+}
+
+// SetOpacity is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetOpacity(opacity float64) {
+	h.log.Warn("SetOpacity() unsupported in bridge mode")
+}
+
+// SetProgress is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetProgress(progress float64) {
+	h.log.Warn("SetProgress() unsupported in bridge mode")
+}
+
+// SetBadge is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetBadge(badge string) {
+	h.log.Warn("SetBadge() unsupported in bridge mode")
+}
+
+// SetMaterial is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetMaterial(material string) error {
+	h.log.Warn("SetMaterial() unsupported in bridge mode")
+	return nil
+}
+
+// MaterialSupported is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) MaterialSupported(material string) bool {
+	h.log.Warn("MaterialSupported() unsupported in bridge mode")
+	return false
+}
+
+// SetTrayIcon is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetTrayIcon(data []byte) error {
+	h.log.Warn("SetTrayIcon() unsupported in bridge mode")
+	return nil
+}
+
+// SetTrayTooltip is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetTrayTooltip(tooltip string) {
+	h.log.Warn("SetTrayTooltip() unsupported in bridge mode")
+}
+
+// SetTrayMenu is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetTrayMenu(menu string) {
+	h.log.Warn("SetTrayMenu() unsupported in bridge mode")
+}
+
+// RemoveTray is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) RemoveTray() {
+	h.log.Warn("RemoveTray() unsupported in bridge mode")
+}
+
+// Notify is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) Notify(id, title, body string, silent bool) error {
+	h.log.Warn("Notify() unsupported in bridge mode")
+	return nil
+}
+
+// SetMenu is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) SetMenu(menu string) {
+	h.log.Warn("SetMenu() unsupported in bridge mode")
+}
+
+// ShowContextMenu is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) ShowContextMenu(menu string, x, y int) {
+	h.log.Warn("ShowContextMenu() unsupported in bridge mode")
+}
+
+// StartAutomation is unsupported for Bridge but required
+// for the Renderer interface
+func (h *Bridge) StartAutomation(name string, handler func(method, args string) (string, error)) {
+	h.log.Warn("StartAutomation() unsupported in bridge mode")
+}
+
+// Dispatch calls the given function directly as Bridge
+// has no main thread to run it on
+func (h *Bridge) Dispatch(f func()) {
+	f()
+}
+
 // Close is unsupported for Bridge but required
 // for the Renderer interface
 func (h *Bridge) Close() {
@@ -7,11 +7,19 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/ipc"
 	"github.com/wailsapp/wails/lib/logger"
 )
 
 // TODO Move this back into bridge.go
 
+// wsMessage is a message queued to be written to the socket, which is a
+// websocket.TextMessage or websocket.BinaryMessage
+type wsMessage struct {
+	messageType int
+	data        []byte
+}
+
 // session represents a single websocket session
 type session struct {
 	bindingCache []string
@@ -22,12 +30,16 @@ type session struct {
 
 	// Mutex for writing to the socket
 	shutdown  chan bool
-	writeChan chan []byte
+	writeChan chan wsMessage
 
 	done bool
+
+	// Messages of at least this size are compressed, if the
+	// frontend agreed to it. 0 turns compression off.
+	compressionThreshold int
 }
 
-func newSession(conn *websocket.Conn, bindingCache []string, ipc interfaces.IPCManager, logger *logger.CustomLogger, eventMgr interfaces.EventManager) *session {
+func newSession(conn *websocket.Conn, bindingCache []string, ipc interfaces.IPCManager, logger *logger.CustomLogger, eventMgr interfaces.EventManager, compressionThreshold int) *session {
 	return &session{
 		conn:         conn,
 		bindingCache: bindingCache,
@@ -35,7 +47,9 @@ func newSession(conn *websocket.Conn, bindingCache []string, ipc interfaces.IPCM
 		log:          logger,
 		eventManager: eventMgr,
 		shutdown:     make(chan bool),
-		writeChan:    make(chan []byte, 100),
+		writeChan:    make(chan wsMessage, 100),
+
+		compressionThreshold: compressionThreshold,
 	}
 }
 
@@ -50,7 +64,14 @@ func (s *session) Identifier() string {
 
 func (s *session) sendMessage(msg string) error {
 	if !s.done {
-		s.writeChan <- []byte(msg)
+		s.writeChan <- wsMessage{websocket.TextMessage, []byte(msg)}
+	}
+	return nil
+}
+
+func (s *session) sendBinary(data []byte) error {
+	if !s.done {
+		s.writeChan <- wsMessage{websocket.BinaryMessage, data}
 	}
 	return nil
 }
@@ -82,9 +103,14 @@ func (s *session) start(firstSession bool) {
 			continue
 		}
 
-		s.log.Debugf("Got message: %#v\n", string(buffer))
-
-		s.ipc.Dispatch(string(buffer), s.Callback)
+		// Calls with binary data come in binary frames
+		if messageType == websocket.BinaryMessage {
+			s.log.Debugf("Got binary message of %d bytes", len(buffer))
+			s.ipc.DispatchBinary(buffer, s.Callback, s.CallbackBinary)
+		} else {
+			s.log.Debugf("Got message: %#v\n", string(buffer))
+			s.ipc.Dispatch(string(buffer), s.Callback, s.CallbackBinary)
+		}
 
 		if s.done {
 			break
@@ -97,6 +123,12 @@ func (s *session) Callback(data string) error {
 	return s.evalJS(data, callbackMessage)
 }
 
+// CallbackBinary sends a callback whose data is binary to the frontend in a
+// binary frame
+func (s *session) CallbackBinary(header string, data []byte) error {
+	return s.sendBinary(ipc.EncodeFrame(header, data))
+}
+
 func (s *session) evalJS(js string, mtype messageType) error {
 	// Prepend message type to message
 	return s.sendMessage(mtype.toString() + js)
@@ -123,7 +155,8 @@ func (s *session) writePump() {
 				return
 			}
 
-			if err := s.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			s.conn.EnableWriteCompression(s.compressionThreshold > 0 && len(msg.data) >= s.compressionThreshold)
+			if err := s.conn.WriteMessage(msg.messageType, msg.data); err != nil {
 				s.log.Debug(err.Error())
 				return
 			}
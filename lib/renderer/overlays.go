@@ -0,0 +1,74 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// overlay is a native view kept over an element of the page
+type overlay struct {
+	view     unsafe.Pointer
+	selector string
+}
+
+// trackOverlayJS returns the Javascript that has the frontend report where
+// the overlay's element is. It does nothing until the runtime has loaded.
+func trackOverlayJS(id string, selector string) string {
+	encodedID, _ := json.Marshal(id)
+	encodedSelector, _ := json.Marshal(selector)
+	return fmt.Sprintf("if(window.wails&&window.wails._)window.wails._.TrackOverlay(%s,%s);", encodedID, encodedSelector)
+}
+
+// AttachOverlay shows the native view over the element of the page matching
+// the selector. The view is placed as the frontend reports where the
+// element is. Attaching another view with the same ID replaces it.
+func (w *WebView) AttachOverlay(id string, view unsafe.Pointer, selector string) {
+	w.DetachOverlay(id)
+	w.overlaysLock.Lock()
+	if w.overlays == nil {
+		w.overlays = make(map[string]*overlay)
+	}
+	w.overlays[id] = &overlay{view: view, selector: selector}
+	w.overlaysLock.Unlock()
+	w.evalJS(trackOverlayJS(id, selector))
+}
+
+// PlaceOverlay moves the overlay to the given position in the page, in CSS
+// pixels, and shows or hides it
+func (w *WebView) PlaceOverlay(id string, x, y, width, height int, visible bool) {
+	w.overlaysLock.Lock()
+	overlay := w.overlays[id]
+	w.overlaysLock.Unlock()
+	if overlay == nil {
+		return
+	}
+	w.window.Dispatch(func() {
+		w.window.PlaceOverlay(overlay.view, x, y, width, height, visible)
+	})
+}
+
+// DetachOverlay takes the overlay off the window
+func (w *WebView) DetachOverlay(id string) {
+	w.overlaysLock.Lock()
+	overlay := w.overlays[id]
+	delete(w.overlays, id)
+	w.overlaysLock.Unlock()
+	if overlay == nil {
+		return
+	}
+	encodedID, _ := json.Marshal(id)
+	w.evalJS(fmt.Sprintf("if(window.wails&&window.wails._)window.wails._.UntrackOverlay(%s);", encodedID))
+	w.window.Dispatch(func() {
+		w.window.RemoveOverlay(overlay.view)
+	})
+}
+
+// trackOverlays has a reloaded page report where the overlays' elements are
+func (w *WebView) trackOverlays() {
+	w.overlaysLock.Lock()
+	defer w.overlaysLock.Unlock()
+	for id, overlay := range w.overlays {
+		w.evalJS(trackOverlayJS(id, overlay.selector))
+	}
+}
@@ -0,0 +1,142 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"time"
+)
+
+// How often the cursor is checked while input passthrough needs tracking
+const passthroughInterval = 30 * time.Millisecond
+
+// passthrough holds the state of the window's mouse input passthrough
+type passthrough struct {
+	lock        sync.Mutex
+	ignore      bool
+	forwardMove bool
+	regions     []image.Rectangle
+	ignoring    bool          // Whether the window was last set to ignore mouse input
+	stop        chan struct{} // Stops the cursor tracking, nil when it isn't running
+}
+
+// SetIgnoreMouseEvents makes the window pass mouse input through to the
+// windows below it. If forwardMove is set, mouse moves are still delivered
+// to the frontend as 'mousemove' events, EG: so it can highlight elements.
+func (w *WebView) SetIgnoreMouseEvents(ignore bool, forwardMove bool) {
+	w.passthrough.lock.Lock()
+	defer w.passthrough.lock.Unlock()
+	w.passthrough.ignore = ignore
+	w.passthrough.forwardMove = forwardMove
+
+	// Input regions take precedence over the whole window setting
+	if len(w.passthrough.regions) == 0 {
+		w.setIgnoreMouseEvents(ignore)
+	}
+	w.updatePassthroughTracking()
+}
+
+// SetInputRegions limits the parts of the window that receive mouse input to
+// the given regions, in window coordinates. Input outside of the regions passes
+// through to the windows below. Passing no regions removes the limit.
+func (w *WebView) SetInputRegions(regions []image.Rectangle) {
+	w.passthrough.lock.Lock()
+	defer w.passthrough.lock.Unlock()
+	w.passthrough.regions = regions
+
+	// The tracking is stopped before the whole window setting is restored
+	// so it can't be overridden
+	w.updatePassthroughTracking()
+	if len(regions) == 0 {
+		w.setIgnoreMouseEvents(w.passthrough.ignore)
+	}
+}
+
+// setIgnoreMouseEvents sets whether the window ignores mouse input. The
+// passthrough lock must be held.
+func (w *WebView) setIgnoreMouseEvents(ignore bool) {
+	w.passthrough.ignoring = ignore
+	w.window.Dispatch(func() {
+		w.window.SetIgnoreMouseEvents(ignore)
+	})
+}
+
+// cursorPosition returns the position of the cursor relative to the window
+func (w *WebView) cursorPosition() image.Point {
+	result := make(chan image.Point, 1)
+	w.window.Dispatch(func() {
+		x, y := w.window.CursorPosition()
+		result <- image.Pt(x, y)
+	})
+	return <-result
+}
+
+// updatePassthroughTracking starts tracking the cursor if input regions are
+// set or mouse moves need forwarding, and stops it when neither applies. The
+// passthrough lock must be held.
+func (w *WebView) updatePassthroughTracking() {
+	p := &w.passthrough
+	if !p.needsTracking() {
+		if p.stop != nil {
+			close(p.stop)
+			p.stop = nil
+		}
+		return
+	}
+	if p.stop == nil {
+		p.stop = make(chan struct{})
+		go w.trackPassthrough(p.stop)
+	}
+}
+
+func (p *passthrough) needsTracking() bool {
+	return len(p.regions) > 0 || (p.ignore && p.forwardMove)
+}
+
+// trackPassthrough polls the cursor, toggling whether the window ignores
+// mouse input as it enters and leaves the input regions, until stop is closed
+func (w *WebView) trackPassthrough(stop chan struct{}) {
+	var last image.Point
+	ticker := time.NewTicker(passthroughInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		// The cursor is found on the main thread, so the lock isn't held
+		// while waiting for it
+		position := w.cursorPosition()
+
+		w.passthrough.lock.Lock()
+		if w.passthrough.stop != stop {
+			w.passthrough.lock.Unlock()
+			return
+		}
+
+		// Only accept input when the cursor is over one of the regions.
+		// Without regions, the window is left as SetIgnoreMouseEvents set it.
+		if regions := w.passthrough.regions; len(regions) > 0 {
+			ignore := true
+			for _, region := range regions {
+				if position.In(region) {
+					ignore = false
+					break
+				}
+			}
+			if ignore != w.passthrough.ignoring {
+				w.setIgnoreMouseEvents(ignore)
+			}
+		}
+		forward := w.passthrough.ignoring && w.passthrough.forwardMove && position != last
+		w.passthrough.lock.Unlock()
+
+		if forward {
+			w.evalJS(fmt.Sprintf(`(function(x,y){var e=document.elementFromPoint(x,y)||document;e.dispatchEvent(new MouseEvent('mousemove',{clientX:x,clientY:y,bubbles:true}));})(%d,%d);`, position.X, position.Y))
+		}
+		last = position
+	}
+}
@@ -0,0 +1,40 @@
+package renderer
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// splashScreenID is the id of the element covering the page until the
+// frontend is ready
+const splashScreenID = "wails-splash"
+
+// removeSplashScreen is run once the frontend is ready
+var removeSplashScreen = fmt.Sprintf(`(function(e){if(e){e.parentNode.removeChild(e);}})(document.getElementById('%s'));`, splashScreenID)
+
+// SplashScreenHTML adds the splash screen to the given page, so it is shown
+// as soon as the page loads and covers it until the frontend is ready. The
+// splash screen is either a fragment of HTML or an image as a data URL. It
+// is centred on the given background colour.
+func SplashScreenHTML(page, splash, background string) string {
+	if splash == "" {
+		return page
+	}
+	if strings.HasPrefix(splash, "data:image/") {
+		splash = fmt.Sprintf(`<img src="%s" style="max-width:100%%;max-height:100%%" alt="">`, html.EscapeString(splash))
+	}
+	element := fmt.Sprintf(`<div id="%s" style="position:fixed;top:0;right:0;bottom:0;left:0;z-index:2147483647;display:flex;align-items:center;justify-content:center;background:%s">%s</div>`,
+		splashScreenID, html.EscapeString(background), splash)
+
+	// The splash screen goes first in the body, so it shows before the rest
+	// of the page is parsed
+	lower := strings.ToLower(page)
+	if start := strings.Index(lower, "<body"); start != -1 {
+		if end := strings.Index(lower[start:], ">"); end != -1 {
+			at := start + end + 1
+			return page[:at] + element + page[at:]
+		}
+	}
+	return element + page
+}
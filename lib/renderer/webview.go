@@ -1,16 +1,25 @@
 package renderer
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
+	"math"
 	"math/rand"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/wailsapp/wails/runtime"
 
 	"github.com/go-playground/colors"
+	"github.com/wailsapp/wails/lib/event"
+	"github.com/wailsapp/wails/lib/integrity"
 	"github.com/wailsapp/wails/lib/interfaces"
 	"github.com/wailsapp/wails/lib/logger"
 	"github.com/wailsapp/wails/lib/messages"
@@ -24,13 +33,42 @@ import (
 var UseFirebug = ""
 
 type WebView struct {
-	window       wv.WebView // The webview object
-	ipc          interfaces.IPCManager
-	log          *logger.CustomLogger
-	config       interfaces.AppConfig
-	eventManager interfaces.EventManager
-	bindingCache []string
+	window         wv.WebView // The webview object
+	ipc            interfaces.IPCManager
+	log            *logger.CustomLogger
+	config         interfaces.AppConfig
+	eventManager   interfaces.EventManager
+	bindingCache   []string
 	maximumSizeSet bool
+	passthrough    passthrough
+	reloading      int32   // Set while the page is reloaded after a crash
+	secondary      bool    // Set for windows opened after the main window
+	embedded       bool    // Set when a host app runs the UI loop
+	closed         int32   // Set once the window has closed
+	running        int32   // Set once Run has been called
+	noGestures     int32   // Set while the system's pen and touch gestures are off
+	displayCount   int     // The number of displays when they last changed
+	scale          float64 // The scale of the display the window is on
+
+	// Set while the page is reloading, when events for it are queued
+	// until it is ready
+	unloaded   int32
+	eventQueue *event.Queue
+
+	// The webview whose window a pane is docked in, how it is docked and
+	// its width or height
+	host     *WebView
+	vertical bool
+	paneSize int
+
+	// Native views kept over elements of the page, by ID
+	overlays     map[string]*overlay
+	overlaysLock sync.Mutex
+
+	// Where the window state is saved, if it is persisted, and the
+	// state when it was last saved
+	windowStateFile string
+	windowState     *windowState
 }
 
 // NewWebView returns a new WebView struct
@@ -38,6 +76,29 @@ func NewWebView() *WebView {
 	return &WebView{}
 }
 
+// NewWindowWebView returns a WebView for an additional window. It must be
+// initialised on the main thread once the main window is running. Its Run()
+// returns straight away as the main window runs the UI loop.
+func NewWindowWebView() *WebView {
+	return &WebView{secondary: true}
+}
+
+// NewEmbeddedWebView returns a WebView for an app whose UI loop is run by a
+// host app. Its Run() returns straight away and the host calls Tick().
+func NewEmbeddedWebView() *WebView {
+	return &WebView{embedded: true}
+}
+
+// DockInto makes a WebView from NewWindowWebView a pane in the host's window,
+// beside the host's webview or below it when vertical. The size is the
+// pane's width, or its height when vertical. Without a size the pane takes
+// half of the window. It must be called before Initialise.
+func (w *WebView) DockInto(host *WebView, vertical bool, size int) {
+	w.host = host
+	w.vertical = vertical
+	w.paneSize = size
+}
+
 // Initialise sets up the WebView
 func (w *WebView) Initialise(config interfaces.AppConfig, ipc interfaces.IPCManager, eventManager interfaces.EventManager) error {
 
@@ -51,12 +112,26 @@ func (w *WebView) Initialise(config interfaces.AppConfig, ipc interfaces.IPCMana
 	w.ipc = ipc
 	ipc.BindRenderer(w)
 
+	w.eventQueue = event.NewQueue()
+
 	// Save the config
 	w.config = config
 
 	width := config.GetWidth()
 	height := config.GetHeight()
 
+	// Reopen the main window as it was when it last closed
+	var savedState *windowState
+	if config.GetPersistWindowState() && !w.secondary {
+		w.windowStateFile = windowStateFilename(config.GetTitle())
+		savedState = loadWindowState(w.windowStateFile)
+		w.windowState = &windowState{Width: width, Height: height, Display: -1}
+		if savedState != nil {
+			width, height = savedState.Width, savedState.Height
+			w.windowState = savedState
+		}
+	}
+
 	// Clamp width and height
 	minWidth, minHeight := config.GetMinWidth(), config.GetMinHeight()
 	maxWidth, maxHeight := config.GetMaxWidth(), config.GetMaxHeight()
@@ -81,19 +156,116 @@ func (w *WebView) Initialise(config interfaces.AppConfig, ipc interfaces.IPCMana
 		}
 	}
 
+	// Panes take half of the host's window unless they are given a size
+	var host wv.WebView
+	if w.host != nil {
+		if w.host.window == nil || atomic.LoadInt32(&w.host.closed) == 1 {
+			return fmt.Errorf("the host window of the pane is not open")
+		}
+		host = w.host.window
+		hostWidth, hostHeight := host.Size()
+		width, height = hostWidth/2, hostHeight/2
+		if w.paneSize > 0 {
+			width, height = w.paneSize, w.paneSize
+		}
+	}
+
+	// Render the HTML as the window loads it
+	html, err := config.GetHTML()
+	if err != nil {
+		return err
+	}
+
+	// The about dialog describes the app, so it's set by the main window
+	if !w.secondary && w.host == nil {
+		wv.SetAbout(config.GetTitle(), config.GetVersion(), config.GetCopyright(), config.GetAboutIcon())
+	}
+
 	// Create the WebView instance
 	w.window = wv.NewWebview(wv.Settings{
-		Width:     width,
-		Height:    height,
-		Title:     config.GetTitle(),
-		Resizable: config.GetResizable(),
-		URL:       config.GetHTML(),
-		Debug:     !config.GetDisableInspector(),
+		Width:           width,
+		Height:          height,
+		Title:           config.GetTitle(),
+		Resizable:       config.GetResizable(),
+		URL:             html,
+		Debug:           !config.GetDisableInspector(),
+		Tabbing:         config.GetWindowTabbing(),
+		TitleBarOverlay: config.GetTitleBarOverlay(),
+		Secondary:       w.secondary,
+		Transparent:     config.GetTransparent(),
+		Backdrop:        wv.Backdrop(config.GetBackdrop()),
+		Kiosk:           config.GetKiosk() && !w.secondary,
+		Hidden:          config.GetStartHidden() && !w.secondary,
+		Embedded:        w.embedded,
+		Host:            host,
+		Vertical:        w.vertical,
 		ExternalInvokeCallback: func(_ wv.WebView, message string) {
-			w.ipc.Dispatch(message, w.callback)
+			w.ipc.Dispatch(message, w.callback, w.callbackBinary)
+		},
+		NewTabCallback: func(_ wv.WebView) {
+			w.eventManager.Emit("wails:window:newtab")
+		},
+		ProcessTerminatedCallback: func(_ wv.WebView, reason string) {
+			w.log.Errorf("The webview process terminated: %s", reason)
+			atomic.StoreInt32(&w.unloaded, 1)
+			w.eventManager.Emit("wails:webview-crashed", reason)
+		},
+		ClosedCallback: func(_ wv.WebView) {
+			atomic.StoreInt32(&w.closed, 1)
+			w.eventManager.Emit("wails:window:closed")
+		},
+		ClosingCallback: func(_ wv.WebView) bool {
+			// Apps in the tray keep their window to show it again
+			if config.GetTrayOnly() && !w.secondary {
+				w.saveWindowState()
+				w.window.SetVisible(false)
+				return true
+			}
+
+			// The app closes the window itself once the close is confirmed
+			if config.GetConfirmClose() {
+				w.eventManager.Emit("wails:window:closing")
+				return true
+			}
+			w.saveWindowState()
+			return false
+		},
+		WindowEventCallback: func(_ wv.WebView, event wv.WindowEvent) {
+			w.windowEvent(event)
+		},
+		TrayCallback: func(_ wv.WebView, item int) {
+			w.eventManager.Emit(runtime.TrayClickEvent, item)
+		},
+		MenuCallback: func(_ wv.WebView, item int) {
+			w.eventManager.Emit(runtime.MenuClickEvent, item)
+		},
+		ContextMenuCallback: func(_ wv.WebView, item int) {
+			w.eventManager.Emit(runtime.ContextMenuChosenEvent, item)
+		},
+		KeyCallback: func(_ wv.WebView, key string, modifiers wv.KeyModifier, down bool) bool {
+			return w.keyEvent(key, modifiers, down)
+		},
+		GestureCallback: func(_ wv.WebView, gesture wv.Gesture, dx, dy float64) bool {
+			return w.gestureEvent(gesture, dx, dy)
+		},
+		NotificationCallback: func(_ wv.WebView, id string) {
+			w.eventManager.Emit(runtime.NotificationClickEvent, id)
+		},
+		ShareCallback: func(_ wv.WebView, text string, files []string) {
+			w.eventManager.Emit(runtime.ShareEvent, &runtime.SharedContent{Text: text, Files: files})
+		},
+		GeolocationCallback: w.geolocationRequest,
+		FileDropCallback: func(_ wv.WebView, files []string, x, y int) {
+			w.eventManager.Emit(runtime.FileDropEvent, &runtime.FileDrop{Files: files, X: x, Y: y})
 		},
 	})
 
+	// Panes leave the host's window as it is
+	if w.host != nil {
+		w.log.Info("Initialised")
+		return nil
+	}
+
 	// Set minimum and maximum sizes
 	if setMinSize {
 		w.SetMinSize(minWidth, minHeight)
@@ -110,11 +282,70 @@ func (w *WebView) Initialise(config interfaces.AppConfig, ipc interfaces.IPCMana
 		w.SetMaxSize(maxWidth, maxHeight)
 	}
 
+	// Position the window buttons
+	if position := config.GetTrafficLightPosition(); position != (image.Point{}) {
+		w.SetTrafficLightPosition(position.X, position.Y)
+	}
+
+	// Open on the display with the cursor
+	if config.GetOpenOnCursorDisplay() {
+		w.window.Dispatch(func() {
+			w.window.Place(w.window.CursorDisplay(), wv.EdgeNone, 0)
+		})
+	}
+
+	// Open where the window last closed, at the given position, or centred
+	// on the display the window is on
+	if savedState != nil {
+		w.window.Dispatch(func() {
+			w.restoreWindowState(savedState)
+		})
+	} else if config.GetCentre() {
+		w.PlaceOnDisplay(-1, int(wv.EdgeNone), 0)
+	} else if x, y := config.GetStartX(), config.GetStartY(); x != 0 || y != 0 {
+		w.SetPosition(x, y)
+	}
+
+	// Keep the window above all other windows
+	if config.GetAlwaysOnTop() {
+		w.SetAlwaysOnTop(true)
+	}
+
+	// Give the keys pressed in the window to the app before the page
+	if config.GetOnKey() != nil {
+		w.window.Dispatch(func() {
+			w.window.CaptureKeys(true)
+		})
+	}
+
+	// Keep the system's pen and touch gestures from drawing apps
+	if config.GetDisableSystemGestures() {
+		atomic.StoreInt32(&w.noGestures, 1)
+	}
+
+	// Give the mouse's side buttons and swipes to the app before the page
+	if config.GetOnGesture() != nil {
+		w.window.Dispatch(func() {
+			w.window.CaptureGestures(true)
+		})
+	}
+
+	// Count the displays to tell when they are added or removed, and
+	// when the window moves to a display with a different scale
+	w.window.Dispatch(func() {
+		w.displayCount = w.window.DisplayCount()
+		w.scale = w.window.Scale()
+	})
+
 	// SignalManager.OnExit(w.Exit)
 	
 	// Set colour
 	color := config.GetColour()
-	if color != "" {
+	if rgba := config.GetRGBA(); rgba != nil {
+		w.window.Dispatch(func() {
+			w.window.SetColor(rgba.R, rgba.G, rgba.B, rgba.A)
+		})
+	} else if color != "" {
 		err := w.SetColour(color)
 		if err != nil {
 			return err
@@ -125,6 +356,105 @@ func (w *WebView) Initialise(config interfaces.AppConfig, ipc interfaces.IPCMana
 	return nil
 }
 
+// keyEvent emits the key as the "wails:key" event and returns true if the
+// app's OnKey hook keeps it from the page. It is called on the main thread.
+func (w *WebView) keyEvent(key string, modifiers wv.KeyModifier, down bool) bool {
+	event := interfaces.KeyEvent{
+		Key:     key,
+		Shift:   modifiers&wv.KeyShift != 0,
+		Control: modifiers&wv.KeyControl != 0,
+		Alt:     modifiers&wv.KeyAlt != 0,
+		Super:   modifiers&wv.KeySuper != 0,
+		Down:    down,
+	}
+	w.eventManager.Emit("wails:key", event)
+	onKey := w.config.GetOnKey()
+	return onKey != nil && onKey(event)
+}
+
+// geolocationRequest asks the app's OnGeolocation hook whether the page may
+// have the user's location. The hook runs off the main thread, so it may ask
+// the user with the runtime's dialogs, and the answer is sent back on the
+// main thread.
+func (w *WebView) geolocationRequest(window wv.WebView, origin string, request unsafe.Pointer) {
+	onGeolocation := w.config.GetOnGeolocation()
+	if onGeolocation == nil {
+		w.log.Debugf("Denied location to '%s' without an OnGeolocation hook", origin)
+		window.GeolocationReply(request, false)
+		return
+	}
+	go func() {
+		allow := onGeolocation(origin)
+		w.log.Debugf("Location allowed to '%s': %t", origin, allow)
+		window.Dispatch(func() {
+			window.GeolocationReply(request, allow)
+		})
+	}()
+}
+
+// gestureNames are the names of the gestures in GestureEvent
+var gestureNames = map[wv.Gesture]string{
+	wv.GestureBack:    "back",
+	wv.GestureForward: "forward",
+	wv.GestureScroll:  "scroll",
+	wv.GestureSwipe:   "swipe",
+}
+
+// gestureEvent emits the gesture as the "wails:gesture" event and returns
+// true if the app's OnGesture hook keeps it from the page. It is called on
+// the main thread.
+func (w *WebView) gestureEvent(gesture wv.Gesture, dx, dy float64) bool {
+	event := interfaces.GestureEvent{
+		Gesture: gestureNames[gesture],
+		DeltaX:  dx,
+		DeltaY:  dy,
+	}
+	w.eventManager.Emit("wails:gesture", event)
+	onGesture := w.config.GetOnGesture()
+	return onGesture != nil && onGesture(event)
+}
+
+// windowEvent emits the change to the window as an event, with the window's
+// new size or position. Changes made while the window is created aren't
+// emitted. It is called on the main thread.
+func (w *WebView) windowEvent(event wv.WindowEvent) {
+	if atomic.LoadInt32(&w.running) == 0 {
+		return
+	}
+	switch event {
+	case wv.WindowResized:
+		width, height := w.window.Size()
+		w.eventManager.Emit("wails:window:resize", width, height)
+	case wv.WindowMoved:
+		x, y := w.window.Position()
+		w.eventManager.Emit("wails:window:move", x, y)
+	case wv.WindowFocused:
+		w.eventManager.Emit("wails:window:focus")
+	case wv.WindowBlurred:
+		w.eventManager.Emit("wails:window:blur")
+	case wv.WindowMinimised:
+		w.eventManager.Emit("wails:window:minimise")
+	case wv.WindowMaximised:
+		w.eventManager.Emit("wails:window:maximise")
+	case wv.WindowRestored:
+		w.eventManager.Emit("wails:window:restore")
+	case wv.WindowDisplaysChanged:
+		count := w.window.DisplayCount()
+		if count > w.displayCount {
+			w.eventManager.Emit("wails:screen:added")
+		} else if count < w.displayCount {
+			w.eventManager.Emit("wails:screen:removed")
+		}
+		w.displayCount = count
+		w.eventManager.Emit("wails:screen:changed")
+	case wv.WindowScaleChanged:
+		if scale := w.window.Scale(); scale != w.scale {
+			w.scale = scale
+			w.eventManager.Emit("wails:dpi-changed", scale)
+		}
+	}
+}
+
 // SetColour sets the window colour
 func (w *WebView) SetColour(colour string) error {
 	color, err := colors.Parse(colour)
@@ -223,6 +553,7 @@ func (w *WebView) Exit() {
 func (w *WebView) Run() error {
 
 	w.log.Info("Running...")
+	atomic.StoreInt32(&w.running, 1)
 
 	// Inject firebug in debug mode on Windows
 	if UseFirebug != "" {
@@ -240,19 +571,53 @@ func (w *WebView) Run() error {
 		// Run this in a different go routine to free up the main process
 		go func() {
 
+			// Tell the frontend when to send call data in chunks
+			if size := w.config.GetMaxPayloadSize(); size > 0 {
+				w.evalJSSync(fmt.Sprintf("window.wails._.SetMaxPayloadSize(%d);", size))
+			}
+
 			// Inject Bindings
 			for _, binding := range w.bindingCache {
 				w.evalJSSync(binding)
 			}
 
+			// Keep the overlays over their elements
+			w.trackOverlays()
+
+			// The page and the browser's windows are new, so the gestures
+			// are turned off again
+			if atomic.LoadInt32(&w.noGestures) == 1 {
+				w.applySystemGestures(false)
+			}
+
+			// Verify the user assets before injecting them. Those that fail
+			// are left out and the app decides what to do, reloading the
+			// page if they may be injected.
+			userJS, userCSS := w.config.GetJS(), w.config.GetCSS()
+			err := integrity.Verify(map[string]string{
+				"JS":  userJS,
+				"CSS": userCSS,
+			})
+			if err != nil {
+				for _, asset := range err.(*integrity.Error).Assets {
+					switch asset {
+					case "JS":
+						userJS = ""
+					case "CSS":
+						userCSS = ""
+					}
+				}
+				w.eventManager.Emit("wails:integrity:failed", err.Error())
+			}
+
 			// Inject user CSS
-			if w.config.GetCSS() != "" {
-				outputCSS := fmt.Sprintf("%.45s", w.config.GetCSS())
+			if userCSS != "" {
+				outputCSS := fmt.Sprintf("%.45s", userCSS)
 				if len(outputCSS) > 45 {
 					outputCSS += "..."
 				}
 				w.log.DebugFields("Inject User CSS", logger.Fields{"css": outputCSS})
-				w.injectCSS(w.config.GetCSS())
+				w.injectCSS(userCSS)
 			} else {
 				// Use default wails css
 
@@ -260,21 +625,53 @@ func (w *WebView) Run() error {
 				w.injectCSS(runtime.WailsCSS)
 			}
 
+			// Give the user JS its initial state before it runs
+			if initialState := w.config.GetInitialState(); initialState != nil {
+				state, err := json.Marshal(initialState())
+				if err != nil {
+					w.log.Errorf("Cannot marshal the initial state: %s", err.Error())
+				} else {
+					w.evalJSSync("window.__WAILS_STATE__ = " + string(state) + ";")
+				}
+			}
+
 			// Inject user JS
-			if w.config.GetJS() != "" {
-				outputJS := fmt.Sprintf("%.45s", w.config.GetJS())
+			if userJS != "" {
+				outputJS := fmt.Sprintf("%.45s", userJS)
 				if len(outputJS) > 45 {
 					outputJS += "..."
 				}
 				w.log.DebugFields("Inject User JS", logger.Fields{"js": outputJS})
-				w.evalJSSync(w.config.GetJS())
+				w.evalJSSync(userJS)
+			}
+
+			// Let the frontend reserve space for the window buttons
+			if w.config.GetTitleBarOverlay() {
+				area := w.TitleBarButtonArea()
+				w.evalJS(fmt.Sprintf(`(function(s){s.setProperty('--wails-titlebar-x','%dpx');s.setProperty('--wails-titlebar-y','%dpx');s.setProperty('--wails-titlebar-width','%dpx');s.setProperty('--wails-titlebar-height','%dpx');})(document.documentElement.style);`,
+					area.Min.X, area.Min.Y, area.Dx(), area.Dy()))
+			}
+
+			// Uncover the page
+			if w.config.GetSplashScreen() != "" {
+				w.evalJS(removeSplashScreen)
 			}
 
 			// Emit that everything is loaded and ready
 			w.eventManager.Emit("wails:ready")
+
+			// Let stores resend their state to a page reloaded after a crash
+			if atomic.CompareAndSwapInt32(&w.reloading, 1, 0) {
+				w.eventManager.Emit("wails:webview-restored")
+			}
 		}()
 	})
 
+	// The main window runs the loop for all windows, unless it is embedded
+	if w.secondary || w.embedded {
+		return nil
+	}
+
 	// Kick off main window loop
 	w.window.Run()
 
@@ -331,6 +728,162 @@ func (w *WebView) SelectDirectory() string {
 	return result
 }
 
+// SelectFiles opens a dialog that allows the user to select one or, if multiple
+// is true, more files, starting in the given directory. Formats are given as
+// "name|*.ext;*.ext" lines.
+func (w *WebView) SelectFiles(title string, directory string, formats string, multiple bool) []string {
+	var result []string
+	flags := 0
+	if multiple {
+		flags |= wv.OpenFlagMultiple
+	}
+	// We need to run this on the main thread, however Dispatch is
+	// non-blocking so we launch this in a goroutine and wait for
+	// dispatch to finish before returning the result
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		w.window.Dispatch(func() {
+			result = w.window.OpenDialog(title, directory, formats, flags)
+			wg.Done()
+		})
+	}()
+
+	defer w.focus() // Ensure the main window is put back into focus afterwards
+
+	wg.Wait()
+	return result
+}
+
+// ShowMessage shows a message dialog of the given type, "info", "warning",
+// "error" or "question", with the given buttons, returning the index of the
+// pressed button or -1 if the dialog was dismissed
+func (w *WebView) ShowMessage(messageType string, title string, message string, buttons []string) int {
+	var result int
+	var kind int
+	switch messageType {
+	case "warning":
+		kind = wv.MessageWarning
+	case "error":
+		kind = wv.MessageError
+	case "question":
+		kind = wv.MessageQuestion
+	default:
+		kind = wv.MessageInfo
+	}
+	// We need to run this on the main thread, however Dispatch is
+	// non-blocking so we launch this in a goroutine and wait for
+	// dispatch to finish before returning the result
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		w.window.Dispatch(func() {
+			result = w.window.MessageDialog(kind, title, message, buttons)
+			wg.Done()
+		})
+	}()
+
+	defer w.focus() // Ensure the main window is put back into focus afterwards
+
+	wg.Wait()
+	return result
+}
+
+// SelectDirectoriesWithOptions opens a dialog that allows the user to select one
+// or, if multiple is true, more directories, starting in the given directory
+func (w *WebView) SelectDirectoriesWithOptions(title string, directory string, multiple bool) []string {
+	var result []string
+	flags := wv.OpenFlagDirectory
+	if multiple {
+		flags |= wv.OpenFlagMultiple
+	}
+	// We need to run this on the main thread, however Dispatch is
+	// non-blocking so we launch this in a goroutine and wait for
+	// dispatch to finish before returning the result
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		w.window.Dispatch(func() {
+			result = w.window.OpenDialog(title, directory, "", flags)
+			wg.Done()
+		})
+	}()
+
+	defer w.focus() // Ensure the main window is put back into focus afterwards
+
+	wg.Wait()
+	return result
+}
+
+// SelectDirectories opens a dialog that allows the user to select multiple directories
+func (w *WebView) SelectDirectories(title string) []string {
+	var result []string
+	// We need to run this on the main thread, however Dispatch is
+	// non-blocking so we launch this in a goroutine and wait for
+	// dispatch to finish before returning the result
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		w.window.Dispatch(func() {
+			result = w.window.SelectDirectories(title)
+			wg.Done()
+		})
+	}()
+
+	defer w.focus() // Ensure the main window is put back into focus afterwards
+
+	wg.Wait()
+	return result
+}
+
+// ColorPicker opens a native colour picker showing the initial colour.
+// The chosen colour is returned, with false if the user cancelled.
+func (w *WebView) ColorPicker(title string, initial color.RGBA) (color.RGBA, bool) {
+	result := initial
+	var ok bool
+	// We need to run this on the main thread, however Dispatch is
+	// non-blocking so we launch this in a goroutine and wait for
+	// dispatch to finish before returning the result
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		w.window.Dispatch(func() {
+			result.R, result.G, result.B, result.A, ok = w.window.ColorPicker(title, initial.R, initial.G, initial.B, initial.A)
+			wg.Done()
+		})
+	}()
+
+	defer w.focus() // Ensure the main window is put back into focus afterwards
+
+	wg.Wait()
+	return result, ok
+}
+
+// FontPicker opens a native font picker showing the initial font, if it
+// has a family. The chosen font is returned, with false if the user
+// cancelled.
+func (w *WebView) FontPicker(title string, initial interfaces.FontDescription) (interfaces.FontDescription, bool) {
+	var result interfaces.FontDescription
+	var ok bool
+	// We need to run this on the main thread, however Dispatch is
+	// non-blocking so we launch this in a goroutine and wait for
+	// dispatch to finish before returning the result
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		w.window.Dispatch(func() {
+			result.Family, result.Face, result.Size, result.Weight, result.Italic, ok =
+				w.window.FontPicker(title, initial.Family, initial.Size, initial.Weight, initial.Italic)
+			wg.Done()
+		})
+	}()
+
+	defer w.focus() // Ensure the main window is put back into focus afterwards
+
+	wg.Wait()
+	return result, ok
+}
+
 // SelectSaveFile opens a dialog that allows the user to select a file to save
 func (w *WebView) SelectSaveFile(title string, filter string) string {
 	var result string
@@ -352,6 +905,37 @@ func (w *WebView) SelectSaveFile(title string, filter string) string {
 	return result
 }
 
+// SelectSaveFileWithFormats opens a dialog that allows the user to select a file
+// to save and the format to save it in. Formats are given as "name|*.ext;*.ext"
+// lines. The index of the chosen format is returned with the filename.
+func (w *WebView) SelectSaveFileWithFormats(title string, directory string, filename string, formats string, createDirectories, confirmOverwrite bool) (string, int) {
+	var result string
+	var format int
+	flags := 0
+	if createDirectories {
+		flags |= wv.SaveFlagCreateDirectories
+	}
+	if confirmOverwrite {
+		flags |= wv.SaveFlagConfirmOverwrite
+	}
+	// We need to run this on the main thread, however Dispatch is
+	// non-blocking so we launch this in a goroutine and wait for
+	// dispatch to finish before returning the result
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		w.window.Dispatch(func() {
+			result, format = w.window.SaveDialog(title, directory, filename, formats, flags)
+			wg.Done()
+		})
+	}()
+
+	defer w.focus() // Ensure the main window is put back into focus afterwards
+
+	wg.Wait()
+	return result, format
+}
+
 // focus puts the main window into focus
 func (w *WebView) focus() {
 	w.window.Dispatch(func() {
@@ -365,6 +949,14 @@ func (w *WebView) callback(data string) error {
 	return w.evalJS(callbackCMD)
 }
 
+// callbackBinary sends a response whose data is binary. The webview has no
+// binary channel, only strings, so the data is always sent base64 encoded,
+// though not as JSON. Only the bridge sends it as binary.
+func (w *WebView) callbackBinary(header string, data []byte) error {
+	callbackCMD := fmt.Sprintf("window.wails._.CallbackBinary('%s','%s');", hex.EncodeToString([]byte(header)), base64.StdEncoding.EncodeToString(data))
+	return w.evalJS(callbackCMD)
+}
+
 // NotifyEvent notifies the frontend about a backend runtime event
 func (w *WebView) NotifyEvent(event *messages.EventData) error {
 
@@ -376,6 +968,23 @@ func (w *WebView) NotifyEvent(event *messages.EventData) error {
 		return err
 	}
 
+	// Hold the events emitted while the page reloads until it is ready,
+	// then deliver them after "wails:ready"
+	switch {
+	case event.Name == "wails:unloading":
+		atomic.StoreInt32(&w.unloaded, 1)
+		return nil
+	case event.Name == "wails:ready":
+		if atomic.CompareAndSwapInt32(&w.unloaded, 1, 0) {
+			defer w.deliverQueuedEvents()
+		}
+	case event.Name == "wails:loaded":
+		// Sent by the reloaded page itself
+	case atomic.LoadInt32(&w.unloaded) == 1:
+		w.eventQueue.Push(event)
+		return nil
+	}
+
 	// Default data is a blank array
 	data := []byte("[]")
 
@@ -400,6 +1009,13 @@ func (w *WebView) NotifyEvent(event *messages.EventData) error {
 	return w.evalJS(message)
 }
 
+// deliverQueuedEvents sends the page the events emitted while it reloaded
+func (w *WebView) deliverQueuedEvents() {
+	for _, event := range w.eventQueue.Drain() {
+		w.NotifyEvent(event)
+	}
+}
+
 // SetMinSize sets the minimum size of a resizable window
 func (w *WebView) SetMinSize(width, height int) {
 	if w.config.GetResizable() == false {
@@ -423,6 +1039,18 @@ func (w *WebView) SetMaxSize(width, height int) {
 	})
 }
 
+// SetAspectRatio keeps the content of a resizable window at the ratio of the
+// given width and height, or unlocks it if they are 0
+func (w *WebView) SetAspectRatio(width, height int) {
+	if w.config.GetResizable() == false {
+		w.log.Warn("Cannot call SetAspectRatio() - App.Resizable = false")
+		return
+	}
+	w.window.Dispatch(func() {
+		w.window.SetAspectRatio(width, height)
+	})
+}
+
 // Fullscreen makes the main window go fullscreen
 func (w *WebView) Fullscreen() {
 	if w.config.GetResizable() == false {
@@ -448,6 +1076,46 @@ func (w *WebView) UnFullscreen() {
 	})
 }
 
+// Minimise minimises the window
+func (w *WebView) Minimise() {
+	w.window.Dispatch(func() {
+		w.window.SetMinimised(true)
+	})
+}
+
+// Maximise maximises the window
+func (w *WebView) Maximise() {
+	if w.config.GetResizable() == false {
+		w.log.Warn("Cannot call Maximise() - App.Resizable = false")
+		return
+	}
+	w.window.Dispatch(func() {
+		w.window.SetMaximised(true)
+	})
+}
+
+// Restore returns a minimised or maximised window to its previous size
+func (w *WebView) Restore() {
+	w.window.Dispatch(func() {
+		w.window.SetMinimised(false)
+		w.window.SetMaximised(false)
+	})
+}
+
+// Show shows the window and brings it to the front
+func (w *WebView) Show() {
+	w.window.Dispatch(func() {
+		w.window.SetVisible(true)
+	})
+}
+
+// Hide hides the window
+func (w *WebView) Hide() {
+	w.window.Dispatch(func() {
+		w.window.SetVisible(false)
+	})
+}
+
 // SetTitle sets the window title
 func (w *WebView) SetTitle(title string) {
 	w.window.Dispatch(func() {
@@ -455,9 +1123,418 @@ func (w *WebView) SetTitle(title string) {
 	})
 }
 
-// Close closes the window
-func (w *WebView) Close() {
+// ShowAbout shows the platform's about dialog for the app
+func (w *WebView) ShowAbout() {
 	w.window.Dispatch(func() {
-		w.window.Terminate()
+		w.window.ShowAbout()
 	})
 }
+
+// SetIcon sets the window icon to the given PNG or ICO data,
+// or restores the app's icon if the data is empty
+func (w *WebView) SetIcon(data []byte) error {
+	result := make(chan bool, 1)
+	w.window.Dispatch(func() {
+		result <- w.window.SetIcon(data)
+	})
+	if !<-result {
+		return fmt.Errorf("unable to read the icon")
+	}
+	return nil
+}
+
+// SetAlwaysOnTop keeps the window above all other windows
+func (w *WebView) SetAlwaysOnTop(onTop bool) {
+	w.window.Dispatch(func() {
+		w.window.SetAlwaysOnTop(onTop)
+	})
+}
+
+// SetSystemGestures turns the system's pen and touch gestures over the page,
+// EG: panning, press-and-hold and flicks, on or off
+func (w *WebView) SetSystemGestures(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&w.noGestures, 0)
+	} else {
+		atomic.StoreInt32(&w.noGestures, 1)
+	}
+	w.applySystemGestures(enabled)
+}
+
+// applySystemGestures sets the gestures in the window and the page
+func (w *WebView) applySystemGestures(enabled bool) {
+	w.window.Dispatch(func() {
+		w.window.SetSystemGestures(enabled)
+	})
+	w.evalJS(fmt.Sprintf("window.wails._.SetSystemGestures(%t);", enabled))
+}
+
+// StartDrag starts dragging the files out of the window, returning false if
+// no drag was started
+func (w *WebView) StartDrag(files []string) bool {
+	var started bool
+	var wg sync.WaitGroup
+	wg.Add(1)
+	w.window.Dispatch(func() {
+		started = w.window.StartDrag(files)
+		wg.Done()
+	})
+	wg.Wait()
+	return started
+}
+
+// SetBorderless removes or restores the window decorations
+func (w *WebView) SetBorderless(borderless bool) {
+	w.window.Dispatch(func() {
+		w.window.SetBorderless(borderless)
+	})
+}
+
+// SetSize sets the size of the window
+func (w *WebView) SetSize(width, height int) {
+	w.window.Dispatch(func() {
+		w.window.SetSize(width, height)
+	})
+}
+
+// SnapToCorner moves the window to the given corner of the
+// screen it is on, leaving the given margin
+func (w *WebView) SnapToCorner(corner, margin int) {
+	w.window.Dispatch(func() {
+		w.window.SnapToCorner(wv.Corner(corner), margin)
+	})
+}
+
+// Displays returns the displays attached to the system, indexed by display ID
+func (w *WebView) Displays() []interfaces.Display {
+	result := make(chan []interfaces.Display, 1)
+	w.window.Dispatch(func() {
+		workAreas := w.displays()
+		displays := make([]interfaces.Display, len(workAreas))
+		for display, workArea := range workAreas {
+			x, y, width, height, scale, primary := w.window.DisplayBounds(display)
+			displays[display] = interfaces.Display{
+				Bounds:   image.Rect(x, y, x+width, y+height),
+				WorkArea: workArea,
+				Scale:    scale,
+				Primary:  primary,
+			}
+		}
+		result <- displays
+	})
+	return <-result
+}
+
+// displays returns the work area of each display. It must be
+// called on the main thread.
+func (w *WebView) displays() []image.Rectangle {
+	displays := make([]image.Rectangle, w.window.DisplayCount())
+	for display := range displays {
+		x, y, width, height := w.window.DisplayWorkArea(display)
+		displays[display] = image.Rect(x, y, x+width, y+height)
+	}
+	return displays
+}
+
+// CursorDisplay returns the ID of the display the mouse cursor is on
+func (w *WebView) CursorDisplay() int {
+	result := make(chan int, 1)
+	w.window.Dispatch(func() {
+		result <- w.window.CursorDisplay()
+	})
+	return <-result
+}
+
+// PlaceOnDisplay moves the window to the given edge of the given display.
+// An edge of 0 centers the window and a display of -1 is the current display.
+func (w *WebView) PlaceOnDisplay(display, edge, margin int) {
+	w.window.Dispatch(func() {
+		w.window.Place(display, wv.Edge(edge), margin)
+	})
+}
+
+// SetPosition moves the top left of the window to the given
+// position, relative to the top left of the primary display
+func (w *WebView) SetPosition(x, y int) {
+	w.window.Dispatch(func() {
+		w.window.SetPosition(x, y)
+	})
+}
+
+// Position returns the position of the top left of the window
+func (w *WebView) Position() (x, y int) {
+	result := make(chan image.Point, 1)
+	w.window.Dispatch(func() {
+		x, y := w.window.Position()
+		result <- image.Pt(x, y)
+	})
+	position := <-result
+	return position.X, position.Y
+}
+
+// Size returns the size of the window's content
+func (w *WebView) Size() (width, height int) {
+	result := make(chan image.Point, 1)
+	w.window.Dispatch(func() {
+		width, height := w.window.Size()
+		result <- image.Pt(width, height)
+	})
+	size := <-result
+	return size.X, size.Y
+}
+
+// SetTrafficLightPosition moves the window buttons to the
+// given offset from the top left of the window (MacOS)
+func (w *WebView) SetTrafficLightPosition(x, y int) {
+	w.window.Dispatch(func() {
+		w.window.SetTrafficLightPosition(x, y)
+	})
+}
+
+// TitleBarButtonArea returns the area of the window content
+// covered by the window buttons when the title bar is hidden
+func (w *WebView) TitleBarButtonArea() image.Rectangle {
+	result := make(chan image.Rectangle, 1)
+	w.window.Dispatch(func() {
+		x, y, width, height := w.window.TitleBarButtonArea()
+		result <- image.Rect(x, y, x+width, y+height)
+	})
+	return <-result
+}
+
+// SetTitleBarColour sets the background and symbol colours of the title bar
+func (w *WebView) SetTitleBarColour(background, symbol string) error {
+	backgroundColour, err := colors.Parse(background)
+	if err != nil {
+		return err
+	}
+	symbolColour, err := colors.Parse(symbol)
+	if err != nil {
+		return err
+	}
+	bg, fg := backgroundColour.ToRGBA(), symbolColour.ToRGBA()
+	w.window.Dispatch(func() {
+		w.window.SetTitleBarColor(bg.R, bg.G, bg.B, fg.R, fg.G, fg.B)
+	})
+	return nil
+}
+
+// Reload reloads the page after the webview crashed or its assets were
+// repaired. The HTML is rendered again, so a template gets a new nonce and
+// state, then the runtime, bindings and user assets are injected again and
+// "wails:webview-restored" is emitted once the app is ready.
+func (w *WebView) Reload() {
+	atomic.StoreInt32(&w.reloading, 1)
+	atomic.StoreInt32(&w.unloaded, 1)
+	html, err := w.config.GetHTML()
+	if err != nil {
+		w.log.Errorf("Unable to render the HTML, reloading the page: %s", err.Error())
+	}
+	w.window.Dispatch(func() {
+		if err != nil {
+			w.window.Reload()
+			return
+		}
+		w.window.Navigate(html)
+	})
+	// Eval waits for the page to load
+	w.evalJS(runtime.WailsJS)
+}
+
+// ShowError shows a native error dialog and waits for it to be closed
+func (w *WebView) ShowError(title, message string) {
+	// We need to run this on the main thread, however Dispatch is
+	// non-blocking so we launch this in a goroutine and wait for
+	// dispatch to finish before returning the result
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		w.window.Dispatch(func() {
+			w.window.Dialog(wv.DialogTypeAlert, wv.DialogFlagError, title, message, "")
+			wg.Done()
+		})
+	}()
+	wg.Wait()
+}
+
+// ShowEmojiPicker opens the system emoji and character picker
+func (w *WebView) ShowEmojiPicker() {
+	w.window.Dispatch(func() {
+		w.window.ShowEmojiPicker()
+	})
+}
+
+// RequestUserAttention draws the user's attention to the window
+func (w *WebView) RequestUserAttention(critical bool) {
+	w.window.Dispatch(func() {
+		w.window.RequestAttention(critical)
+	})
+}
+
+// SetOpacity fades the window, from 0 for invisible to 1 for opaque
+func (w *WebView) SetOpacity(opacity float64) {
+	opacity = math.Max(0, math.Min(1, opacity))
+	w.window.Dispatch(func() {
+		w.window.SetOpacity(opacity)
+	})
+}
+
+// SetProgress shows the progress of a long task, from 0 to 1, or hides it
+// if negative
+func (w *WebView) SetProgress(progress float64) {
+	if progress > 1 {
+		progress = 1
+	}
+	w.window.Dispatch(func() {
+		w.window.SetProgress(progress)
+	})
+}
+
+// SetBadge shows a short label with the app's icon, or hides it if empty
+func (w *WebView) SetBadge(badge string) {
+	w.window.Dispatch(func() {
+		w.window.SetBadge(badge)
+	})
+}
+
+// SetMaterial fills the window's background with the material
+func (w *WebView) SetMaterial(material string) error {
+	result := make(chan bool, 1)
+	w.window.Dispatch(func() {
+		result <- w.window.SetMaterial(material)
+	})
+	if !<-result {
+		return fmt.Errorf("the %q material is unsupported", material)
+	}
+	return nil
+}
+
+// MaterialSupported returns true if the window can have the material
+func (w *WebView) MaterialSupported(material string) bool {
+	result := make(chan bool, 1)
+	w.window.Dispatch(func() {
+		result <- w.window.MaterialSupported(material)
+	})
+	return <-result
+}
+
+// SetTrayIcon shows the icon in the system tray, adding it the first time
+func (w *WebView) SetTrayIcon(data []byte) error {
+	result := make(chan bool, 1)
+	w.window.Dispatch(func() {
+		result <- w.window.SetTrayIcon(data)
+	})
+	if !<-result {
+		return fmt.Errorf("unable to read the tray icon")
+	}
+	return nil
+}
+
+// SetTrayTooltip sets the text shown when hovering over the tray icon
+func (w *WebView) SetTrayTooltip(tooltip string) {
+	w.window.Dispatch(func() {
+		w.window.SetTrayTooltip(tooltip)
+	})
+}
+
+// SetTrayMenu sets the menu of the tray icon, encoded as described by
+// wv.WebView.SetTrayMenu
+func (w *WebView) SetTrayMenu(menu string) {
+	w.window.Dispatch(func() {
+		w.window.SetTrayMenu(menu)
+	})
+}
+
+// RemoveTray removes the tray icon
+func (w *WebView) RemoveTray() {
+	w.window.Dispatch(func() {
+		w.window.RemoveTray()
+	})
+}
+
+// Notify shows a notification from the app. Clicking it emits
+// runtime.NotificationClickEvent with the id.
+func (w *WebView) Notify(id, title, body string, silent bool) error {
+	result := make(chan bool, 1)
+	w.window.Dispatch(func() {
+		result <- w.window.Notify(id, title, body, silent)
+	})
+	if !<-result {
+		return fmt.Errorf("unable to show the notification")
+	}
+	return nil
+}
+
+// SetMenu sets the menu bar, encoded as described by wv.WebView.SetMenu
+func (w *WebView) SetMenu(menu string) {
+	w.window.Dispatch(func() {
+		w.window.SetMenu(menu)
+	})
+}
+
+// ShowContextMenu shows a menu, encoded as described by wv.WebView.SetMenu,
+// at the given position in the page
+func (w *WebView) ShowContextMenu(menu string, x, y int) {
+	w.window.Dispatch(func() {
+		w.window.ShowContextMenu(menu, x, y)
+	})
+}
+
+// Dispatch runs the given function on the main thread. It does not wait for
+// the function to complete.
+func (w *WebView) Dispatch(f func()) {
+	w.window.Dispatch(f)
+}
+
+// StartAutomation publishes the automation interface under the given name.
+// Calls are handled off the main thread, so the handler may use the runtime,
+// and the result is sent back on the main thread.
+func (w *WebView) StartAutomation(name string, handler func(method, args string) (string, error)) {
+	w.window.Dispatch(func() {
+		w.window.StartAutomation(name, func(window wv.WebView, method, args string, reply unsafe.Pointer) {
+			go func() {
+				result, err := handler(method, args)
+				window.Dispatch(func() {
+					window.AutomationReply(reply, result, err)
+				})
+			}()
+		})
+	})
+}
+
+// Close closes the window. Closing the main window ends the app.
+func (w *WebView) Close() {
+	w.window.Dispatch(w.close)
+}
+
+// Tick runs a single iteration of the UI loop of an embedded window. If
+// blocking, it waits for the next event. It returns false once the window
+// has closed. It must be called on the main thread.
+func (w *WebView) Tick(blocking bool) bool {
+	if atomic.LoadInt32(&w.closed) == 1 {
+		return false
+	}
+	w.window.Loop(blocking)
+	return atomic.LoadInt32(&w.closed) == 0
+}
+
+// Stop closes an embedded window. It must be called on the main thread.
+func (w *WebView) Stop() {
+	if atomic.LoadInt32(&w.closed) == 0 {
+		w.close()
+	}
+}
+
+// close closes the window. It must be called on the main thread.
+func (w *WebView) close() {
+	if w.secondary {
+		w.window.Close()
+		return
+	}
+	w.saveWindowState()
+	if w.embedded {
+		w.window.Close()
+		return
+	}
+	w.window.Terminate()
+}
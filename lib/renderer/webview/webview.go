@@ -14,10 +14,10 @@ package webview
 #cgo linux openbsd freebsd pkg-config: gtk+-3.0 webkit2gtk-4.0
 
 #cgo windows CFLAGS: -DWEBVIEW_WINAPI=1 -std=c99
-#cgo windows LDFLAGS: -lole32 -lcomctl32 -loleaut32 -luuid -lgdi32
+#cgo windows LDFLAGS: -lole32 -lcomctl32 -loleaut32 -luuid -lgdi32 -lcomdlg32
 
 #cgo darwin CFLAGS: -DWEBVIEW_COCOA=1 -x objective-c
-#cgo darwin LDFLAGS: -framework Cocoa -framework WebKit
+#cgo darwin LDFLAGS: -framework Cocoa -framework CoreLocation -framework WebKit
 
 #include <stdlib.h>
 #include <stdint.h>
@@ -26,6 +26,21 @@ package webview
 #include "webview.h"
 
 extern void _webviewExternalInvokeCallback(void *, void *);
+extern void _webviewNewTabCallback(void *);
+extern void _webviewProcessTerminatedCallback(void *, void *);
+extern void _webviewAutomationCallback(void *, void *, void *, void *);
+extern void _webviewClosedCallback(void *);
+extern int _webviewClosingCallback(void *);
+extern void _webviewWindowEventCallback(void *, int);
+extern void _webviewTrayCallback(void *, int);
+extern void _webviewMenuCallback(void *, int);
+extern void _webviewContextMenuCallback(void *, int);
+extern int _webviewKeyCallback(void *, char *, int, int);
+extern int _webviewGestureCallback(void *, int, double, double);
+extern void _webviewNotificationCallback(void *, char *);
+extern void _webviewShareCallback(void *, char *, char *);
+extern void _webviewGeolocationCallback(void *, char *, void *);
+extern void _webviewFileDropCallback(void *, char *, int, int);
 
 static inline void CgoWebViewFree(void *w) {
 	free((void *)((struct webview *)w)->title);
@@ -33,7 +48,7 @@ static inline void CgoWebViewFree(void *w) {
 	free(w);
 }
 
-static inline void *CgoWebViewCreate(int width, int height, char *title, char *url, int resizable, int debug) {
+static inline void *CgoWebViewCreate(int width, int height, char *title, char *url, int resizable, int debug, int tabbing, int transparentTitlebar, int secondary, int transparent, int backdrop, int kiosk, int hidden, int embedded, void *host, int vertical) {
 	struct webview *w = (struct webview *) calloc(1, sizeof(*w));
 	w->width = width;
 	w->height = height;
@@ -41,8 +56,34 @@ static inline void *CgoWebViewCreate(int width, int height, char *title, char *u
 	w->url = url;
 	w->resizable = resizable;
 	w->debug = debug;
+	w->tabbing = tabbing;
+	w->transparentTitlebar = transparentTitlebar;
+	w->secondary = secondary;
+	w->transparent = transparent;
+	w->backdrop = backdrop;
+	w->kiosk = kiosk;
+	w->hidden = hidden;
+	w->embedded = embedded;
+	w->host = (struct webview *)host;
+	w->vertical = vertical;
 	w->external_invoke_cb = (webview_external_invoke_cb_t) _webviewExternalInvokeCallback;
-	if (webview_init(w) != 0) {
+	w->new_tab_cb = (webview_new_tab_cb_t) _webviewNewTabCallback;
+	w->process_terminated_cb = (webview_process_terminated_cb_t) _webviewProcessTerminatedCallback;
+	w->automation_cb = (webview_automation_cb_t) _webviewAutomationCallback;
+	w->closed_cb = (webview_closed_cb_t) _webviewClosedCallback;
+	w->closing_cb = (webview_closing_cb_t) _webviewClosingCallback;
+	w->window_event_cb = (webview_window_event_cb_t) _webviewWindowEventCallback;
+	w->tray_cb = (webview_tray_cb_t) _webviewTrayCallback;
+	w->menu_cb = (webview_menu_cb_t) _webviewMenuCallback;
+	w->context_menu_cb = (webview_context_menu_cb_t) _webviewContextMenuCallback;
+	w->key_cb = (webview_key_cb_t) _webviewKeyCallback;
+	w->gesture_cb = (webview_gesture_cb_t) _webviewGestureCallback;
+	w->notification_cb = (webview_notification_cb_t) _webviewNotificationCallback;
+	w->share_cb = (webview_share_cb_t) _webviewShareCallback;
+	w->geolocation_cb = (webview_geolocation_cb_t) _webviewGeolocationCallback;
+	w->file_drop_cb = (webview_file_drop_cb_t) _webviewFileDropCallback;
+	int result = host != NULL ? webview_init_pane(w) : webview_init(w);
+	if (result != 0) {
 		CgoWebViewFree(w);
 		return NULL;
 	}
@@ -61,10 +102,26 @@ static inline void CgoWebViewExit(void *w) {
 	webview_exit((struct webview *)w);
 }
 
+static inline void CgoWebViewClose(void *w) {
+	webview_close((struct webview *)w);
+}
+
 static inline void CgoWebViewSetTitle(void *w, char *title) {
 	webview_set_title((struct webview *)w, title);
 }
 
+static inline void CgoSetAbout(char *name, char *version, char *copyright, void *icon, int iconsz) {
+	webview_set_about((const char*)name, (const char*)version, (const char*)copyright, (const uint8_t *)icon, iconsz);
+}
+
+static inline void CgoWebViewShowAbout(void *w) {
+	webview_show_about((struct webview *)w);
+}
+
+static inline int CgoWebViewSetIcon(void *w, void *data, int size) {
+	return webview_set_icon((struct webview *)w, (const uint8_t *)data, size);
+}
+
 static inline void CgoWebViewFocus(void *w) {
 	webview_focus((struct webview *)w);
 }
@@ -77,10 +134,202 @@ static inline void CgoWebViewMaxSize(void *w, int width, int height) {
 	webview_maxsize((struct webview *)w, width, height);
 }
 
+static inline void CgoWebViewSetAspectRatio(void *w, int width, int height) {
+	webview_set_aspect_ratio((struct webview *)w, width, height);
+}
+
 static inline void CgoWebViewSetFullscreen(void *w, int fullscreen) {
 	webview_set_fullscreen((struct webview *)w, fullscreen);
 }
 
+static inline void CgoWebViewSetAlwaysOnTop(void *w, int onTop) {
+	webview_set_always_on_top((struct webview *)w, onTop);
+}
+
+static inline void CgoWebViewSetBorderless(void *w, int borderless) {
+	webview_set_borderless((struct webview *)w, borderless);
+}
+
+static inline void CgoWebViewSetSize(void *w, int width, int height) {
+	webview_set_size((struct webview *)w, width, height);
+}
+
+static inline void CgoWebViewSnapToCorner(void *w, int corner, int margin) {
+	webview_snap_to_corner((struct webview *)w, corner, margin);
+}
+
+static inline void CgoWebViewSetIgnoreMouseEvents(void *w, int ignore) {
+	webview_set_ignore_mouse_events((struct webview *)w, ignore);
+}
+
+static inline void CgoWebViewCursorPosition(void *w, int *x, int *y) {
+	webview_cursor_position((struct webview *)w, x, y);
+}
+
+static inline int CgoWebViewDisplayCount(void *w) {
+	return webview_display_count((struct webview *)w);
+}
+
+static inline void CgoWebViewDisplayWorkArea(void *w, int display, int *x, int *y, int *width, int *height) {
+	webview_display_workarea((struct webview *)w, display, x, y, width, height);
+}
+
+static inline void CgoWebViewDisplayBounds(void *w, int display, int *x, int *y, int *width, int *height, double *scale, int *primary) {
+	webview_display_bounds((struct webview *)w, display, x, y, width, height, scale, primary);
+}
+
+static inline int CgoWebViewCursorDisplay(void *w) {
+	return webview_cursor_display((struct webview *)w);
+}
+
+static inline double CgoWebViewGetScale(void *w) {
+	return webview_get_scale((struct webview *)w);
+}
+
+static inline void CgoWebViewPlace(void *w, int display, int edge, int margin) {
+	webview_place((struct webview *)w, display, edge, margin);
+}
+
+static inline void CgoWebViewSetPosition(void *w, int x, int y) {
+	webview_set_position((struct webview *)w, x, y);
+}
+
+static inline void CgoWebViewGetPosition(void *w, int *x, int *y) {
+	webview_get_position((struct webview *)w, x, y);
+}
+
+static inline void CgoWebViewGetSize(void *w, int *width, int *height) {
+	webview_get_size((struct webview *)w, width, height);
+}
+
+static inline void CgoWebViewSetMaximised(void *w, int maximised) {
+	webview_set_maximised((struct webview *)w, maximised);
+}
+
+static inline int CgoWebViewIsMaximised(void *w) {
+	return webview_is_maximised((struct webview *)w);
+}
+
+static inline void CgoWebViewSetMinimised(void *w, int minimised) {
+	webview_set_minimised((struct webview *)w, minimised);
+}
+
+static inline void CgoWebViewSetVisible(void *w, int visible) {
+	webview_set_visible((struct webview *)w, visible);
+}
+
+static inline void CgoWebViewSetTrafficLightPosition(void *w, int x, int y) {
+	webview_set_traffic_light_position((struct webview *)w, x, y);
+}
+
+static inline void CgoWebViewTitleBarButtonArea(void *w, int *x, int *y, int *width, int *height) {
+	webview_titlebar_button_area((struct webview *)w, x, y, width, height);
+}
+
+static inline void CgoWebViewSetTitleBarColor(void *w, uint8_t r, uint8_t g, uint8_t b, uint8_t sr, uint8_t sg, uint8_t sb) {
+	webview_set_titlebar_color((struct webview *)w, r, g, b, sr, sg, sb);
+}
+
+static inline void CgoWebViewShowEmojiPicker(void *w) {
+	webview_show_emoji_picker((struct webview *)w);
+}
+
+static inline void CgoWebViewRequestAttention(void *w, int critical) {
+	webview_request_attention((struct webview *)w, critical);
+}
+
+static inline void CgoWebViewSetProgress(void *w, double progress) {
+	webview_set_progress((struct webview *)w, progress);
+}
+
+static inline void CgoWebViewSetBadge(void *w, char *badge) {
+	webview_set_badge((struct webview *)w, badge);
+}
+
+static inline void CgoWebViewSetOpacity(void *w, double opacity) {
+	webview_set_opacity((struct webview *)w, opacity);
+}
+
+static inline int CgoWebViewSetMaterial(void *w, char *material) {
+	return webview_set_material((struct webview *)w, material);
+}
+
+static inline int CgoWebViewMaterialSupported(void *w, char *material) {
+	return webview_material_supported((struct webview *)w, material);
+}
+
+static inline int CgoWebViewTraySetIcon(void *w, void *data, int size) {
+	return webview_tray_set_icon((struct webview *)w, (const uint8_t *)data, size);
+}
+
+static inline void CgoWebViewTraySetTooltip(void *w, char *tooltip) {
+	webview_tray_set_tooltip((struct webview *)w, tooltip);
+}
+
+static inline void CgoWebViewTraySetMenu(void *w, char *menu) {
+	webview_tray_set_menu((struct webview *)w, menu);
+}
+
+static inline void CgoWebViewTrayRemove(void *w) {
+	webview_tray_remove((struct webview *)w);
+}
+
+static inline int CgoWebViewNotify(void *w, char *id, char *title, char *body, int silent) {
+	return webview_notify((struct webview *)w, id, title, body, silent);
+}
+
+static inline void CgoWebViewSetMenu(void *w, char *menu) {
+	webview_set_menu((struct webview *)w, menu);
+}
+
+static inline void CgoWebViewShowContextMenu(void *w, char *menu, int x, int y) {
+	webview_show_context_menu((struct webview *)w, menu, x, y);
+}
+
+static inline void CgoWebViewCaptureKeys(void *w, int capture) {
+	webview_capture_keys((struct webview *)w, capture);
+}
+
+static inline void CgoWebViewCaptureGestures(void *w, int capture) {
+	webview_capture_gestures((struct webview *)w, capture);
+}
+
+static inline void CgoWebViewSetSystemGestures(void *w, int enabled) {
+	webview_set_system_gestures((struct webview *)w, enabled);
+}
+
+static inline int CgoWebViewStartDrag(void *w, const char *files) {
+	return webview_start_drag((struct webview *)w, files);
+}
+
+static inline void CgoWebViewPlaceOverlay(void *w, void *view, int x, int y, int width, int height, int visible) {
+	webview_place_overlay((struct webview *)w, view, x, y, width, height, visible);
+}
+
+static inline void CgoWebViewRemoveOverlay(void *w, void *view) {
+	webview_remove_overlay((struct webview *)w, view);
+}
+
+static inline void CgoWebViewReload(void *w) {
+	webview_reload((struct webview *)w);
+}
+
+static inline void CgoWebViewNavigate(void *w, char *url) {
+	webview_navigate((struct webview *)w, url);
+}
+
+static inline void CgoWebViewAutomationStart(void *w, char *name) {
+	webview_automation_start((struct webview *)w, name);
+}
+
+static inline void CgoWebViewAutomationReply(void *w, void *reply, char *result, char *error) {
+	webview_automation_reply((struct webview *)w, reply, result, error);
+}
+
+static inline void CgoWebViewGeolocationReply(void *w, void *request, int allow) {
+	webview_geolocation_reply((struct webview *)w, request, allow);
+}
+
 static inline void CgoWebViewSetColor(void *w, uint8_t r, uint8_t g, uint8_t b, uint8_t a) {
 	webview_set_color((struct webview *)w, r, g, b, a);
 }
@@ -91,6 +340,30 @@ char *title, char *arg, char *res, size_t ressz, char *filter) {
 	(const char*)title, (const char*) arg, res, ressz, filter);
 }
 
+static inline int CgoSaveDialog(void *w, char *title, char *directory, char *filename, char *formats, int flags, char *res, size_t ressz) {
+	return webview_save_dialog((struct webview *)w, (const char*)title, (const char*)directory, (const char*)filename, (const char*)formats, flags, res, ressz);
+}
+
+static inline void CgoOpenDialog(void *w, char *title, char *directory, char *formats, int flags, char *res, size_t ressz) {
+	webview_open_dialog((struct webview *)w, (const char*)title, (const char*)directory, (const char*)formats, flags, res, ressz);
+}
+
+static inline int CgoMessageDialog(void *w, int type, char *title, char *message, char *buttons) {
+	return webview_message_dialog((struct webview *)w, type, (const char*)title, (const char*)message, (const char*)buttons);
+}
+
+static inline void CgoSelectDirectories(void *w, char *title, char *res, size_t ressz) {
+	webview_select_directories((struct webview *)w, (const char*)title, res, ressz);
+}
+
+static inline int CgoColorPicker(void *w, char *title, uint8_t *r, uint8_t *g, uint8_t *b, uint8_t *a) {
+	return webview_color_picker((struct webview *)w, (const char*)title, r, g, b, a);
+}
+
+static inline int CgoFontPicker(void *w, char *title, char *family, size_t familysz, char *face, size_t facesz, int *size, int *weight, int *italic) {
+	return webview_font_picker((struct webview *)w, (const char*)title, family, familysz, face, facesz, size, weight, italic);
+}
+
 static inline int CgoWebViewEval(void *w, char *js) {
 	return webview_eval((struct webview *)w, js);
 }
@@ -111,7 +384,9 @@ import "C"
 import (
 	"errors"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -120,6 +395,26 @@ func init() {
 	runtime.LockOSThread()
 }
 
+// SetAbout sets what the about dialog shows for every window of the app: the
+// app's name, which defaults to the window's title, its version, copyright
+// and icon as PNG data, which defaults to the app's. It should be called
+// before the first window is created, so the About item of the MacOS app
+// menu is named after the app.
+func SetAbout(name, version, copyright string, icon []byte) {
+	nameStr := C.CString(name)
+	defer C.free(unsafe.Pointer(nameStr))
+	versionStr := C.CString(version)
+	defer C.free(unsafe.Pointer(versionStr))
+	copyrightStr := C.CString(copyright)
+	defer C.free(unsafe.Pointer(copyrightStr))
+	var iconPtr unsafe.Pointer
+	if len(icon) > 0 {
+		iconPtr = C.CBytes(icon)
+		defer C.free(iconPtr)
+	}
+	C.CgoSetAbout(nameStr, versionStr, copyrightStr, iconPtr, C.int(len(icon)))
+}
+
 // Open is a simplified API to open a single native window with a full-size webview in
 // it. It can be helpful if you want to communicate with the core app using XHR
 // or WebSockets (as opposed to using JavaScript bindings).
@@ -152,6 +447,77 @@ func Open(title, url string, w, h int, resizable bool) error {
 // string can be used.
 type ExternalInvokeCallbackFunc func(w WebView, data string)
 
+// NewTabCallbackFunc is a function type that is called when the user
+// requests a new tab using the tab bar's "+" button.
+type NewTabCallbackFunc func(w WebView)
+
+// ProcessTerminatedCallbackFunc is a function type that is called when the
+// process rendering the page crashes or is killed. The reason is either
+// "crashed" or "exceeded-memory-limit".
+type ProcessTerminatedCallbackFunc func(w WebView, reason string)
+
+// AutomationCallbackFunc is a function type that is called when another
+// application calls a method through the automation interface. An empty method
+// asks for the list of methods. The result must be sent with AutomationReply()
+// and the given reply.
+type AutomationCallbackFunc func(w WebView, method string, args string, reply unsafe.Pointer)
+
+// ClosedCallbackFunc is a function type that is called when the window has
+// been closed, by the user or with Close()
+type ClosedCallbackFunc func(w WebView)
+
+// ClosingCallbackFunc is a function type that is called on the main thread
+// when the user closes the window, before it is destroyed. It returns true
+// to keep the window open.
+type ClosingCallbackFunc func(w WebView) bool
+
+// WindowEventCallbackFunc is a function type that is called on the main
+// thread when the window is resized, moved, focused, minimised and so on
+type WindowEventCallbackFunc func(w WebView, event WindowEvent)
+
+// TrayCallbackFunc is a function type that is called on the main thread
+// when the tray icon is clicked, with -1, or an item of its menu is chosen
+type TrayCallbackFunc func(w WebView, item int)
+
+// MenuCallbackFunc is a function type that is called on the main thread
+// when an item of the menu bar is chosen
+type MenuCallbackFunc func(w WebView, item int)
+
+// ContextMenuCallbackFunc is a function type that is called on the main
+// thread when an item of a menu shown with ShowContextMenu() is chosen
+type ContextMenuCallbackFunc func(w WebView, item int)
+
+// KeyCallbackFunc is a function type that is called on the main thread when
+// a key is pressed or released in a window that captures keys, before the
+// page sees it. It returns true to keep the key from the page.
+type KeyCallbackFunc func(w WebView, key string, modifiers KeyModifier, down bool) bool
+
+// GestureCallbackFunc is a function type that is called on the main thread
+// when a mouse side button is clicked, the page is scrolled sideways or a
+// swipe is made in a window that captures gestures, before the page sees
+// it. See Gesture for dx and dy. It returns true to keep the gesture from
+// the page.
+type GestureCallbackFunc func(w WebView, gesture Gesture, dx, dy float64) bool
+
+// NotificationCallbackFunc is a function type that is called on the main
+// thread when a notification shown with Notify() is clicked
+type NotificationCallbackFunc func(w WebView, id string)
+
+// ShareCallbackFunc is a function type that is called on the main thread
+// when another app shares text or files with this one (MacOS)
+type ShareCallbackFunc func(w WebView, text string, files []string)
+
+// GeolocationCallbackFunc is a function type that is called on the main
+// thread when the page asks for the user's location, with the page's origin
+// (Linux/BSD, MacOS). The answer must be sent with GeolocationReply() and
+// the given request. Pages are denied the location without a callback.
+type GeolocationCallbackFunc func(w WebView, origin string, request unsafe.Pointer)
+
+// FileDropCallbackFunc is a function type that is called on the main thread
+// when files are dragged from the desktop and dropped on the webview, with
+// their paths and where they were dropped in the page's coordinates
+type FileDropCallbackFunc func(w WebView, files []string, x, y int)
+
 // Settings is a set of parameters to customize the initial WebView appearance
 // and behavior. It is passed into the webview.New() constructor.
 type Settings struct {
@@ -167,8 +533,67 @@ type Settings struct {
 	Resizable bool
 	// Enable debugging tools (Linux/BSD/MacOS, on Windows use Firebug)
 	Debug bool
+	// Allows the window to be merged into tabs (MacOS)
+	Tabbing bool
+	// Hides the title bar, extending the content under the window buttons (MacOS)
+	TitleBarOverlay bool
 	// A callback that is executed when JavaScript calls "window.external.invoke()"
 	ExternalInvokeCallback ExternalInvokeCallbackFunc
+	// A callback that is executed when the "+" button in the tab bar is clicked (MacOS)
+	NewTabCallback NewTabCallbackFunc
+	// A callback that is executed when the web process terminates (Linux/BSD)
+	ProcessTerminatedCallback ProcessTerminatedCallbackFunc
+	// A callback that is executed when the window has been closed
+	ClosedCallback ClosedCallbackFunc
+	// A callback that is executed when the user closes the window, while it
+	// can still be queried
+	ClosingCallback ClosingCallbackFunc
+	// Called when the window is resized, moved, focused, minimised and so on
+	WindowEventCallback WindowEventCallbackFunc
+	// Called when the tray icon is clicked or an item of its menu is chosen
+	TrayCallback TrayCallbackFunc
+	// Called when an item of the menu bar is chosen
+	MenuCallback MenuCallbackFunc
+	// Called when an item of a context menu is chosen
+	ContextMenuCallback ContextMenuCallbackFunc
+	// Called when a key is pressed or released, once CaptureKeys() is called
+	KeyCallback KeyCallbackFunc
+	// Called when a side button is clicked, the page is scrolled sideways
+	// or a swipe is made, once CaptureGestures() is called
+	GestureCallback GestureCallbackFunc
+	// Called when a notification shown with Notify() is clicked
+	NotificationCallback NotificationCallbackFunc
+	// Called when another app shares text or files with the main window
+	// through a service in the app's Info.plist whose NSMessage is "share"
+	// (MacOS)
+	ShareCallback ShareCallbackFunc
+	// Called when the page asks for the user's location with
+	// navigator.geolocation (Linux/BSD, MacOS)
+	GeolocationCallback GeolocationCallbackFunc
+	// Called when files are dropped on the webview from the desktop
+	FileDropCallback FileDropCallbackFunc
+	// Opens an additional window. Closing it doesn't end the main UI loop,
+	// which is run by the first window.
+	Secondary bool
+	// Shows the desktop through the background colour set with SetColor()
+	Transparent bool
+	// How the desktop is shown through a transparent window
+	Backdrop Backdrop
+	// Fills the screen and stays on top. The window can't be closed or left
+	// with the keyboard, EG: with Alt+F4 or Cmd+Q.
+	Kiosk bool
+	// Creates the window without showing it, until SetVisible(true) is called
+	Hidden bool
+	// Opens the window in a host app that runs the main UI loop. Closing it
+	// doesn't end the loop.
+	Embedded bool
+	// Docks the webview as a pane in the host's window, after the host's
+	// webview and its other panes, with a splitter between them. The pane
+	// is given the width, or the height when Vertical. Closing the pane only
+	// removes it from the window.
+	Host WebView
+	// Docks the pane below the host's webview instead of beside it
+	Vertical bool
 }
 
 // WebView is an interface that wraps the basic methods for controlling the UI
@@ -182,6 +607,15 @@ type WebView interface {
 	// SetTitle() changes window title. This method must be called from the main
 	// thread only. See Dispatch() for more details.
 	SetTitle(title string)
+	// SetIcon() changes the window icon to the given PNG or ICO data, or back
+	// to the app's icon if it is empty. On MacOS, the dock icon is changed.
+	// It returns false if the data isn't an image. This method must be called
+	// from the main thread only. See Dispatch() for more details.
+	SetIcon(data []byte) bool
+	// ShowAbout() shows the about dialog with what was given to SetAbout().
+	// This method must be called from the main thread only. See Dispatch()
+	// for more details.
+	ShowAbout()
 
 	// Focus() puts the main window into focus
 	Focus()
@@ -192,9 +626,217 @@ type WebView interface {
 	// SetMaxSize() sets the maximum size of the window
 	SetMaxSize(width, height int)
 
+	// SetAspectRatio() keeps the content at the ratio of the given width and
+	// height as the window is resized, or unlocks it if they are 0. This
+	// method must be called from the main thread only. See Dispatch() for
+	// more details.
+	SetAspectRatio(width, height int)
+
 	// SetFullscreen() controls window full-screen mode. This method must be
 	// called from the main thread only. See Dispatch() for more details.
 	SetFullscreen(fullscreen bool)
+	// SetAlwaysOnTop() keeps the window above all other windows. This method
+	// must be called from the main thread only. See Dispatch() for more details.
+	SetAlwaysOnTop(onTop bool)
+	// SetBorderless() removes or restores the window decorations. This method
+	// must be called from the main thread only. See Dispatch() for more details.
+	SetBorderless(borderless bool)
+	// SetSize() sets the size of the window. This method must be called from
+	// the main thread only. See Dispatch() for more details.
+	SetSize(width, height int)
+	// SnapToCorner() moves the window to the given corner of the screen, leaving
+	// the given margin. This method must be called from the main thread only.
+	// See Dispatch() for more details.
+	SnapToCorner(corner Corner, margin int)
+	// SetIgnoreMouseEvents() makes the window pass mouse input to the windows
+	// below it. This method must be called from the main thread only.
+	// See Dispatch() for more details.
+	SetIgnoreMouseEvents(ignore bool)
+	// CursorPosition() returns the position of the mouse cursor relative to the
+	// top left of the window's content. This method must be called from the
+	// main thread only. See Dispatch() for more details.
+	CursorPosition() (x, y int)
+	// DisplayCount() returns the number of displays. This method must be called
+	// from the main thread only. See Dispatch() for more details.
+	DisplayCount() int
+	// DisplayWorkArea() returns the area of the given display that windows may
+	// use. This method must be called from the main thread only. See Dispatch()
+	// for more details.
+	DisplayWorkArea(display int) (x, y, width, height int)
+	// DisplayBounds() returns the whole area of the given display, the number
+	// of physical pixels per logical pixel and whether it is the primary
+	// display. This method must be called from the main thread only. See
+	// Dispatch() for more details.
+	DisplayBounds(display int) (x, y, width, height int, scale float64, primary bool)
+	// CursorDisplay() returns the display the mouse cursor is on. This method
+	// must be called from the main thread only. See Dispatch() for more details.
+	CursorDisplay() int
+	// Scale() returns the number of physical pixels per logical pixel on the
+	// display the window is on. This method must be called from the main
+	// thread only. See Dispatch() for more details.
+	Scale() float64
+	// Place() moves the window to the given edge of the given display, leaving
+	// the given margin. EdgeNone centers the window and a display of -1 is the
+	// display the window is on. This method must be called from the main thread
+	// only. See Dispatch() for more details.
+	Place(display int, edge Edge, margin int)
+	// SetPosition() moves the top left of the window to the given position,
+	// relative to the top left of the primary display. This method must be
+	// called from the main thread only. See Dispatch() for more details.
+	SetPosition(x, y int)
+	// Position() returns the position of the top left of the window. This
+	// method must be called from the main thread only. See Dispatch() for
+	// more details.
+	Position() (x, y int)
+	// Size() returns the size of the window's content, in the units used by
+	// SetSize(). This method must be called from the main thread only. See
+	// Dispatch() for more details.
+	Size() (width, height int)
+	// SetMaximised() maximises or restores the window. This method must be
+	// called from the main thread only. See Dispatch() for more details.
+	SetMaximised(maximised bool)
+	// Maximised() returns true if the window is maximised. This method must be
+	// called from the main thread only. See Dispatch() for more details.
+	Maximised() bool
+	// SetMinimised() minimises or restores the window. This method must be
+	// called from the main thread only. See Dispatch() for more details.
+	SetMinimised(minimised bool)
+	// SetVisible() shows and focuses the window, or hides it. This method
+	// must be called from the main thread only. See Dispatch() for more
+	// details.
+	SetVisible(visible bool)
+	// SetTrafficLightPosition() moves the window buttons to the given offset
+	// from the top left of the window (MacOS). This method must be called from
+	// the main thread only. See Dispatch() for more details.
+	SetTrafficLightPosition(x, y int)
+	// TitleBarButtonArea() returns the area of the window content covered by the
+	// window buttons. This method must be called from the main thread only.
+	// See Dispatch() for more details.
+	TitleBarButtonArea() (x, y, width, height int)
+	// SetTitleBarColor() sets the background and text colours of the title bar
+	// (Windows 11). This method must be called from the main thread only.
+	// See Dispatch() for more details.
+	SetTitleBarColor(r, g, b, sr, sg, sb uint8)
+	// ShowEmojiPicker() opens the system emoji and character picker for the
+	// focused input. This method must be called from the main thread only.
+	// See Dispatch() for more details.
+	ShowEmojiPicker()
+	// RequestAttention() flashes the window's taskbar button, bounces the
+	// dock icon or marks the window as urgent, until the user switches to it
+	// if critical. This method must be called from the main thread only. See
+	// Dispatch() for more details.
+	RequestAttention(critical bool)
+	// SetOpacity() fades the whole window, from 0 for invisible to 1 for
+	// opaque. This method must be called from the main thread only. See
+	// Dispatch() for more details.
+	SetOpacity(opacity float64)
+	// SetProgress() shows the progress of a long task, from 0 to 1, on the
+	// window's taskbar button (Windows), the app's dock icon (MacOS) or its
+	// launcher entry (Linux). Negative progress hides it. This method must
+	// be called from the main thread only. See Dispatch() for more details.
+	SetProgress(progress float64)
+	// SetBadge() shows a short label, EG: an unread count, where
+	// SetProgress() shows the progress. An empty badge hides it. This
+	// method must be called from the main thread only. See Dispatch() for
+	// more details.
+	SetBadge(badge string)
+	// SetMaterial() fills the window's background with one of the
+	// platform's materials, shown where the page leaves its background
+	// transparent, or removes it if the material is "none". Materials are
+	// named as NSVisualEffectMaterial on MacOS, EG: "sidebar", and are
+	// "mica", "acrylic" or "tabbed" on Windows 11. It returns false if the
+	// material isn't supported. This method must be called from the main
+	// thread only. See Dispatch() for more details.
+	SetMaterial(material string) bool
+	// MaterialSupported() returns true if SetMaterial() supports the
+	// material. This method must be called from the main thread only. See
+	// Dispatch() for more details.
+	MaterialSupported(material string) bool
+	// SetTrayIcon() shows the given PNG or ICO data as the window's icon in
+	// the system tray, or the menu bar on MacOS, adding it the first time. It
+	// returns false if the data isn't an image. This method must be called
+	// from the main thread only. See Dispatch() for more details.
+	SetTrayIcon(data []byte) bool
+	// SetTrayTooltip() sets the text shown when hovering over the tray icon.
+	// This method must be called from the main thread only. See Dispatch()
+	// for more details.
+	SetTrayTooltip(tooltip string)
+	// SetTrayMenu() sets the menu of the tray icon, with one item per line.
+	// A line of "-" is a separator, otherwise it's whether the item is
+	// enabled then whether it's checked, each "0" or "1", followed by its
+	// label. This method must be called from the main thread only. See
+	// Dispatch() for more details.
+	SetTrayMenu(menu string)
+	// RemoveTray() removes the tray icon. This method must be called from the
+	// main thread only. See Dispatch() for more details.
+	RemoveTray()
+	// Notify() shows a notification from the app, in the desktop's
+	// notification area on Linux and MacOS and as a balloon from the tray
+	// icon on Windows. Clicking it calls the NotificationCallback with the
+	// id. It returns false if the notification couldn't be shown. This
+	// method must be called from the main thread only. See Dispatch() for
+	// more details.
+	Notify(id, title, body string, silent bool) bool
+	// SetMenu() sets the window's menu bar, or the app's on MacOS, with one
+	// item per line, encoded as for SetTrayMenu() and indented with a tab per
+	// level. Unindented items are the menus in the bar and an item's submenu
+	// follows it, indented one more level. An empty menu removes the menu
+	// bar. This method must be called from the main thread only. See
+	// Dispatch() for more details.
+	SetMenu(menu string)
+	// ShowContextMenu() shows a menu, encoded as for SetMenu(), at the given
+	// position in the page, in CSS pixels. This method must be called from
+	// the main thread only. See Dispatch() for more details.
+	ShowContextMenu(menu string, x, y int)
+	// CaptureKeys() gives the keys pressed in the window to the KeyCallback
+	// before the page. Keys are named as they are typed without Shift, EG:
+	// "a" or "5", or by what they do, EG: "Enter", "Escape", "ArrowLeft",
+	// "Space" or "F5". This method must be called from the main thread
+	// only. See Dispatch() for more details.
+	CaptureKeys(capture bool)
+	// CaptureGestures() gives the mouse's back and forward buttons,
+	// sideways scrolling and swipes in the window to the GestureCallback
+	// before the page. This method must be called from the main thread
+	// only. See Dispatch() for more details.
+	CaptureGestures(capture bool)
+	// SetSystemGestures() turns the system's pen and touch gestures in the
+	// window, EG: press-and-hold and flicks, on or off. This method must be
+	// called from the main thread only. See Dispatch() for more details.
+	SetSystemGestures(enabled bool)
+	// StartDrag() starts dragging the files out of the window, EG: to the
+	// file manager, and returns false if it didn't start as the primary
+	// mouse button isn't held. This method must be called from the main
+	// thread only. See Dispatch() for more details.
+	StartDrag(files []string) bool
+	// PlaceOverlay() shows a native view over the webview at the given
+	// position in the page, in CSS pixels, adding it the first time. The
+	// view is an NSView* on MacOS, an HWND on Windows and a GtkWidget* on
+	// Linux. This method must be called from the main thread only. See
+	// Dispatch() for more details.
+	PlaceOverlay(view unsafe.Pointer, x, y, width, height int, visible bool)
+	// RemoveOverlay() takes a view placed with PlaceOverlay() off the
+	// webview. This method must be called from the main thread only. See
+	// Dispatch() for more details.
+	RemoveOverlay(view unsafe.Pointer)
+	// Reload() reloads the page. This method must be called from the main
+	// thread only. See Dispatch() for more details.
+	Reload()
+	// Navigate() loads the given URL in place of the page. This method must
+	// be called from the main thread only. See Dispatch() for more details.
+	Navigate(url string)
+	// StartAutomation() publishes the automation interface under the given
+	// name: a D-Bus service (Linux/BSD), Apple Events (MacOS) or a COM object
+	// with that ProgID (Windows). Calls are passed to the callback. This method
+	// must be called from the main thread only. See Dispatch() for more details.
+	StartAutomation(name string, callback AutomationCallbackFunc)
+	// AutomationReply() sends the result of an automation call. If err is not
+	// nil, the call fails with its message. This method must be called from
+	// the main thread only. See Dispatch() for more details.
+	AutomationReply(reply unsafe.Pointer, result string, err error)
+	// GeolocationReply() allows or denies the page the user's location, as
+	// asked by the GeolocationCallback. This method must be called from the
+	// main thread only. See Dispatch() for more details.
+	GeolocationReply(request unsafe.Pointer, allow bool)
 	// SetColor() changes window background color. This method must be called from
 	// the main thread only. See Dispatch() for more details.
 	SetColor(r, g, b, a uint8)
@@ -209,6 +851,31 @@ type WebView interface {
 	// argument can be provided for certain dialogs, such as alert boxes. For
 	// alert boxes argument is a message inside the dialog box.
 	Dialog(dlgType DialogType, flags int, title string, arg string, filter string) string
+	// SaveDialog() opens a save dialog offering the given formats, given as
+	// "name|*.ext;*.ext" lines, starting in the given directory with the
+	// given filename if they aren't empty. The path and the index of the
+	// chosen format are returned.
+	SaveDialog(title string, directory string, filename string, formats string, flags int) (string, int)
+	// OpenDialog() opens a dialog that allows the user to select files, or
+	// directories with OpenFlagDirectory, starting in the given directory and
+	// offering the given formats like SaveDialog(). The selected paths are
+	// returned.
+	OpenDialog(title string, directory string, formats string, flags int) []string
+	// MessageDialog() shows a message with an icon for the given message
+	// type and the given buttons, or OK if there are none. The index of the
+	// pressed button is returned, or -1 if the dialog was dismissed.
+	MessageDialog(messageType int, title string, message string, buttons []string) int
+	// SelectDirectories() opens a dialog that allows the user to select
+	// multiple directories. The selected directories are returned.
+	SelectDirectories(title string) []string
+	// ColorPicker() opens a colour picker showing the given colour. The chosen
+	// colour is returned, with false if the user cancelled.
+	ColorPicker(title string, r, g, b, a uint8) (uint8, uint8, uint8, uint8, bool)
+	// FontPicker() opens a font picker showing the given font, if family is
+	// not empty, with its weight from 100 to 900 as in CSS. The chosen font
+	// is returned with the name of its face, if the platform has one, and
+	// false if the user cancelled.
+	FontPicker(title string, family string, size int, weight int, italic bool) (string, string, int, int, bool, bool)
 	// Terminate() breaks the main UI loop. This method must be called from the main thread
 	// only. See Dispatch() for more details.
 	Terminate()
@@ -219,6 +886,10 @@ type WebView interface {
 	// Exit() closes the window and cleans up the resources. Use Terminate() to
 	// forcefully break out of the main UI loop.
 	Exit()
+	// Close() closes a secondary window. Functions dispatched to the window
+	// after it has closed are dropped. This method must be called from the
+	// main thread only. See Dispatch() for more details.
+	Close()
 }
 
 // DialogType is an enumeration of all supported system dialog types
@@ -233,6 +904,109 @@ const (
 	DialogTypeAlert
 )
 
+// Corner is an enumeration of the corners of the screen
+type Corner int
+
+const (
+	// CornerTopLeft is the top left corner of the screen
+	CornerTopLeft Corner = C.WEBVIEW_CORNER_TOP_LEFT
+	// CornerTopRight is the top right corner of the screen
+	CornerTopRight Corner = C.WEBVIEW_CORNER_TOP_RIGHT
+	// CornerBottomLeft is the bottom left corner of the screen
+	CornerBottomLeft Corner = C.WEBVIEW_CORNER_BOTTOM_LEFT
+	// CornerBottomRight is the bottom right corner of the screen
+	CornerBottomRight Corner = C.WEBVIEW_CORNER_BOTTOM_RIGHT
+)
+
+// Backdrop is an enumeration of the ways the desktop is shown through a
+// transparent window
+type Backdrop int
+
+const (
+	// BackdropTransparent makes the background colour see-through. Its
+	// alpha is used on Linux and MacOS and it is cut out on Windows.
+	BackdropTransparent Backdrop = C.WEBVIEW_BACKDROP_TRANSPARENT
+	// BackdropTranslucent blurs the desktop behind the window on MacOS and
+	// makes the whole window as opaque as the background colour on Windows
+	BackdropTranslucent Backdrop = C.WEBVIEW_BACKDROP_TRANSLUCENT
+)
+
+// WindowEvent is an enumeration of the changes to a window passed to the
+// WindowEventCallback
+type WindowEvent int
+
+const (
+	// WindowResized is sent when the window changes size
+	WindowResized WindowEvent = C.WEBVIEW_WINDOW_EVENT_RESIZE
+	// WindowMoved is sent when the window moves
+	WindowMoved WindowEvent = C.WEBVIEW_WINDOW_EVENT_MOVE
+	// WindowFocused is sent when the window gains the keyboard focus
+	WindowFocused WindowEvent = C.WEBVIEW_WINDOW_EVENT_FOCUS
+	// WindowBlurred is sent when the window loses the keyboard focus
+	WindowBlurred WindowEvent = C.WEBVIEW_WINDOW_EVENT_BLUR
+	// WindowMinimised is sent when the window is minimised
+	WindowMinimised WindowEvent = C.WEBVIEW_WINDOW_EVENT_MINIMISE
+	// WindowMaximised is sent when the window is maximised or zoomed
+	WindowMaximised WindowEvent = C.WEBVIEW_WINDOW_EVENT_MAXIMISE
+	// WindowRestored is sent when the window is no longer minimised or
+	// maximised
+	WindowRestored WindowEvent = C.WEBVIEW_WINDOW_EVENT_RESTORE
+	// WindowDisplaysChanged is sent when displays are added, removed or
+	// rearranged
+	WindowDisplaysChanged WindowEvent = C.WEBVIEW_WINDOW_EVENT_DISPLAYS
+	// WindowScaleChanged is sent when the window may have moved to a
+	// display with a different scale
+	WindowScaleChanged WindowEvent = C.WEBVIEW_WINDOW_EVENT_SCALE
+)
+
+// KeyModifier is a set of flags for the modifier keys held while a key is
+// pressed
+type KeyModifier int
+
+const (
+	// KeyShift is set while Shift is held
+	KeyShift KeyModifier = C.WEBVIEW_KEY_SHIFT
+	// KeyControl is set while Control is held
+	KeyControl KeyModifier = C.WEBVIEW_KEY_CONTROL
+	// KeyAlt is set while Alt, or Option on MacOS, is held
+	KeyAlt KeyModifier = C.WEBVIEW_KEY_ALT
+	// KeySuper is set while the Command key on MacOS, or the Windows key on
+	// Windows, is held
+	KeySuper KeyModifier = C.WEBVIEW_KEY_SUPER
+)
+
+// Gesture is an enumeration of the gestures given to the GestureCallback
+type Gesture int
+
+const (
+	// GestureBack is sent when the mouse's back button is clicked
+	GestureBack Gesture = C.WEBVIEW_GESTURE_BACK
+	// GestureForward is sent when the mouse's forward button is clicked
+	GestureForward Gesture = C.WEBVIEW_GESTURE_FORWARD
+	// GestureScroll is sent when the page is scrolled sideways by dx steps
+	// of the wheel, to the right if positive
+	GestureScroll Gesture = C.WEBVIEW_GESTURE_SCROLL
+	// GestureSwipe is sent when a swipe is made on a touchpad, with dx and
+	// dy -1, 0 or 1 for its direction. Windows has no swipes.
+	GestureSwipe Gesture = C.WEBVIEW_GESTURE_SWIPE
+)
+
+// Edge is an enumeration of the edges of the screen
+type Edge int
+
+const (
+	// EdgeNone centers the window on the screen
+	EdgeNone Edge = C.WEBVIEW_EDGE_NONE
+	// EdgeTop is the top edge of the screen
+	EdgeTop Edge = C.WEBVIEW_EDGE_TOP
+	// EdgeBottom is the bottom edge of the screen
+	EdgeBottom Edge = C.WEBVIEW_EDGE_BOTTOM
+	// EdgeLeft is the left edge of the screen
+	EdgeLeft Edge = C.WEBVIEW_EDGE_LEFT
+	// EdgeRight is the right edge of the screen
+	EdgeRight Edge = C.WEBVIEW_EDGE_RIGHT
+)
+
 const (
 	// DialogFlagFile is a normal file picker dialog
 	DialogFlagFile = C.WEBVIEW_DIALOG_FLAG_FILE
@@ -244,6 +1018,23 @@ const (
 	DialogFlagWarning = C.WEBVIEW_DIALOG_FLAG_WARNING
 	// DialogFlagError is an error dialog
 	DialogFlagError = C.WEBVIEW_DIALOG_FLAG_ERROR
+	// SaveFlagCreateDirectories allows creating directories in a save dialog
+	SaveFlagCreateDirectories = C.WEBVIEW_SAVE_FLAG_CREATE_DIRECTORIES
+	// SaveFlagConfirmOverwrite asks before overwriting a file in a save dialog
+	SaveFlagConfirmOverwrite = C.WEBVIEW_SAVE_FLAG_CONFIRM_OVERWRITE
+	// OpenFlagMultiple allows selecting more than one file in an open dialog
+	OpenFlagMultiple = C.WEBVIEW_OPEN_FLAG_MULTIPLE
+	// OpenFlagDirectory selects directories rather than files in an open
+	// dialog
+	OpenFlagDirectory = C.WEBVIEW_OPEN_FLAG_DIRECTORY
+	// MessageInfo is a message dialog with an information icon
+	MessageInfo = C.WEBVIEW_MESSAGE_INFO
+	// MessageWarning is a message dialog with a warning icon
+	MessageWarning = C.WEBVIEW_MESSAGE_WARNING
+	// MessageError is a message dialog with an error icon
+	MessageError = C.WEBVIEW_MESSAGE_ERROR
+	// MessageQuestion is a message dialog asking the user to choose
+	MessageQuestion = C.WEBVIEW_MESSAGE_QUESTION
 )
 
 var (
@@ -251,10 +1042,27 @@ var (
 	index uintptr
 	fns   = map[uintptr]func(){}
 	cbs   = map[WebView]ExternalInvokeCallbackFunc{}
+	tabs  = map[WebView]NewTabCallbackFunc{}
+	crash = map[WebView]ProcessTerminatedCallbackFunc{}
+	auto  = map[WebView]AutomationCallbackFunc{}
+	gone  = map[WebView]ClosedCallbackFunc{}
+	leave = map[WebView]ClosingCallbackFunc{}
+	moves = map[WebView]WindowEventCallbackFunc{}
+	trays = map[WebView]TrayCallbackFunc{}
+	menus = map[WebView]MenuCallbackFunc{}
+	popup = map[WebView]ContextMenuCallbackFunc{}
+	keys  = map[WebView]KeyCallbackFunc{}
+	swipe = map[WebView]GestureCallbackFunc{}
+	toast = map[WebView]NotificationCallbackFunc{}
+	share = map[WebView]ShareCallbackFunc{}
+	where = map[WebView]GeolocationCallbackFunc{}
+	drops = map[WebView]FileDropCallbackFunc{}
 )
 
 type webview struct {
-	w unsafe.Pointer
+	w      unsafe.Pointer
+	closed int32   // Set when the window has been closed
+	url    *C.char // The URL last navigated to, kept while it is loaded
 }
 
 var _ WebView = &webview{}
@@ -279,16 +1087,67 @@ func NewWebview(settings Settings) WebView {
 	if settings.Title == "" {
 		settings.Title = "WebView"
 	}
+	var host unsafe.Pointer
+	if settings.Host != nil {
+		host = settings.Host.(*webview).w
+	}
 	w := &webview{}
 	w.w = C.CgoWebViewCreate(C.int(settings.Width), C.int(settings.Height),
 		C.CString(settings.Title), C.CString(settings.URL),
-		C.int(boolToInt(settings.Resizable)), C.int(boolToInt(settings.Debug)))
+		C.int(boolToInt(settings.Resizable)), C.int(boolToInt(settings.Debug)),
+		C.int(boolToInt(settings.Tabbing)), C.int(boolToInt(settings.TitleBarOverlay)),
+		C.int(boolToInt(settings.Secondary)), C.int(boolToInt(settings.Transparent)),
+		C.int(settings.Backdrop), C.int(boolToInt(settings.Kiosk)),
+		C.int(boolToInt(settings.Hidden)), C.int(boolToInt(settings.Embedded)), host,
+		C.int(boolToInt(settings.Vertical)))
 	m.Lock()
 	if settings.ExternalInvokeCallback != nil {
 		cbs[w] = settings.ExternalInvokeCallback
 	} else {
 		cbs[w] = func(w WebView, data string) {}
 	}
+	if settings.NewTabCallback != nil {
+		tabs[w] = settings.NewTabCallback
+	}
+	if settings.ProcessTerminatedCallback != nil {
+		crash[w] = settings.ProcessTerminatedCallback
+	}
+	if settings.ClosedCallback != nil {
+		gone[w] = settings.ClosedCallback
+	}
+	if settings.ClosingCallback != nil {
+		leave[w] = settings.ClosingCallback
+	}
+	if settings.WindowEventCallback != nil {
+		moves[w] = settings.WindowEventCallback
+	}
+	if settings.TrayCallback != nil {
+		trays[w] = settings.TrayCallback
+	}
+	if settings.MenuCallback != nil {
+		menus[w] = settings.MenuCallback
+	}
+	if settings.ContextMenuCallback != nil {
+		popup[w] = settings.ContextMenuCallback
+	}
+	if settings.KeyCallback != nil {
+		keys[w] = settings.KeyCallback
+	}
+	if settings.GestureCallback != nil {
+		swipe[w] = settings.GestureCallback
+	}
+	if settings.NotificationCallback != nil {
+		toast[w] = settings.NotificationCallback
+	}
+	if settings.ShareCallback != nil {
+		share[w] = settings.ShareCallback
+	}
+	if settings.GeolocationCallback != nil {
+		where[w] = settings.GeolocationCallback
+	}
+	if settings.FileDropCallback != nil {
+		drops[w] = settings.FileDropCallback
+	}
 	m.Unlock()
 	return w
 }
@@ -311,10 +1170,18 @@ func (w *webview) Exit() {
 }
 
 func (w *webview) Dispatch(f func()) {
+	if atomic.LoadInt32(&w.closed) == 1 {
+		return
+	}
 	m.Lock()
 	for ; fns[index] != nil; index++ {
 	}
-	fns[index] = f
+	// The window may close before the function runs
+	fns[index] = func() {
+		if atomic.LoadInt32(&w.closed) == 0 {
+			f()
+		}
+	}
 	m.Unlock()
 	C.CgoWebViewDispatch(w.w, C.uintptr_t(index))
 }
@@ -325,6 +1192,16 @@ func (w *webview) SetTitle(title string) {
 	C.CgoWebViewSetTitle(w.w, p)
 }
 
+func (w *webview) SetIcon(data []byte) bool {
+	p := C.CBytes(data)
+	defer C.free(p)
+	return C.CgoWebViewSetIcon(w.w, p, C.int(len(data))) == 0
+}
+
+func (w *webview) ShowAbout() {
+	C.CgoWebViewShowAbout(w.w)
+}
+
 func (w *webview) SetColor(r, g, b, a uint8) {
 	C.CgoWebViewSetColor(w.w, C.uint8_t(r), C.uint8_t(g), C.uint8_t(b), C.uint8_t(a))
 }
@@ -341,10 +1218,257 @@ func (w *webview) SetMaxSize(width, height int) {
 	C.CgoWebViewMaxSize(w.w, C.int(width), C.int(height))
 }
 
+func (w *webview) SetAspectRatio(width, height int) {
+	C.CgoWebViewSetAspectRatio(w.w, C.int(width), C.int(height))
+}
+
 func (w *webview) SetFullscreen(fullscreen bool) {
 	C.CgoWebViewSetFullscreen(w.w, C.int(boolToInt(fullscreen)))
 }
 
+func (w *webview) SetAlwaysOnTop(onTop bool) {
+	C.CgoWebViewSetAlwaysOnTop(w.w, C.int(boolToInt(onTop)))
+}
+
+func (w *webview) SetBorderless(borderless bool) {
+	C.CgoWebViewSetBorderless(w.w, C.int(boolToInt(borderless)))
+}
+
+func (w *webview) SetSize(width, height int) {
+	C.CgoWebViewSetSize(w.w, C.int(width), C.int(height))
+}
+
+func (w *webview) SnapToCorner(corner Corner, margin int) {
+	C.CgoWebViewSnapToCorner(w.w, C.int(corner), C.int(margin))
+}
+
+func (w *webview) SetIgnoreMouseEvents(ignore bool) {
+	C.CgoWebViewSetIgnoreMouseEvents(w.w, C.int(boolToInt(ignore)))
+}
+
+func (w *webview) CursorPosition() (x, y int) {
+	var cx, cy C.int
+	C.CgoWebViewCursorPosition(w.w, &cx, &cy)
+	return int(cx), int(cy)
+}
+
+func (w *webview) DisplayCount() int {
+	return int(C.CgoWebViewDisplayCount(w.w))
+}
+
+func (w *webview) DisplayWorkArea(display int) (x, y, width, height int) {
+	var cx, cy, cwidth, cheight C.int
+	C.CgoWebViewDisplayWorkArea(w.w, C.int(display), &cx, &cy, &cwidth, &cheight)
+	return int(cx), int(cy), int(cwidth), int(cheight)
+}
+
+func (w *webview) DisplayBounds(display int) (x, y, width, height int, scale float64, primary bool) {
+	var cx, cy, cwidth, cheight, cprimary C.int
+	var cscale C.double
+	C.CgoWebViewDisplayBounds(w.w, C.int(display), &cx, &cy, &cwidth, &cheight, &cscale, &cprimary)
+	return int(cx), int(cy), int(cwidth), int(cheight), float64(cscale), cprimary != 0
+}
+
+func (w *webview) CursorDisplay() int {
+	return int(C.CgoWebViewCursorDisplay(w.w))
+}
+
+func (w *webview) Scale() float64 {
+	return float64(C.CgoWebViewGetScale(w.w))
+}
+
+func (w *webview) Place(display int, edge Edge, margin int) {
+	C.CgoWebViewPlace(w.w, C.int(display), C.int(edge), C.int(margin))
+}
+
+func (w *webview) SetPosition(x, y int) {
+	C.CgoWebViewSetPosition(w.w, C.int(x), C.int(y))
+}
+
+func (w *webview) Position() (x, y int) {
+	var cx, cy C.int
+	C.CgoWebViewGetPosition(w.w, &cx, &cy)
+	return int(cx), int(cy)
+}
+
+func (w *webview) Size() (width, height int) {
+	var cwidth, cheight C.int
+	C.CgoWebViewGetSize(w.w, &cwidth, &cheight)
+	return int(cwidth), int(cheight)
+}
+
+func (w *webview) SetMaximised(maximised bool) {
+	C.CgoWebViewSetMaximised(w.w, C.int(boolToInt(maximised)))
+}
+
+func (w *webview) Maximised() bool {
+	return C.CgoWebViewIsMaximised(w.w) != 0
+}
+
+func (w *webview) SetMinimised(minimised bool) {
+	C.CgoWebViewSetMinimised(w.w, C.int(boolToInt(minimised)))
+}
+
+func (w *webview) SetVisible(visible bool) {
+	C.CgoWebViewSetVisible(w.w, C.int(boolToInt(visible)))
+}
+
+func (w *webview) SetTrafficLightPosition(x, y int) {
+	C.CgoWebViewSetTrafficLightPosition(w.w, C.int(x), C.int(y))
+}
+
+func (w *webview) TitleBarButtonArea() (x, y, width, height int) {
+	var cx, cy, cwidth, cheight C.int
+	C.CgoWebViewTitleBarButtonArea(w.w, &cx, &cy, &cwidth, &cheight)
+	return int(cx), int(cy), int(cwidth), int(cheight)
+}
+
+func (w *webview) SetTitleBarColor(r, g, b, sr, sg, sb uint8) {
+	C.CgoWebViewSetTitleBarColor(w.w, C.uint8_t(r), C.uint8_t(g), C.uint8_t(b),
+		C.uint8_t(sr), C.uint8_t(sg), C.uint8_t(sb))
+}
+
+func (w *webview) Reload() {
+	C.CgoWebViewReload(w.w)
+}
+
+func (w *webview) Navigate(url string) {
+	previous := w.url
+	w.url = C.CString(url)
+	C.CgoWebViewNavigate(w.w, w.url)
+	if previous != nil {
+		C.free(unsafe.Pointer(previous))
+	}
+}
+
+func (w *webview) ShowEmojiPicker() {
+	C.CgoWebViewShowEmojiPicker(w.w)
+}
+
+func (w *webview) RequestAttention(critical bool) {
+	C.CgoWebViewRequestAttention(w.w, C.int(boolToInt(critical)))
+}
+
+func (w *webview) SetOpacity(opacity float64) {
+	C.CgoWebViewSetOpacity(w.w, C.double(opacity))
+}
+
+func (w *webview) SetProgress(progress float64) {
+	C.CgoWebViewSetProgress(w.w, C.double(progress))
+}
+
+func (w *webview) SetBadge(badge string) {
+	p := C.CString(badge)
+	defer C.free(unsafe.Pointer(p))
+	C.CgoWebViewSetBadge(w.w, p)
+}
+
+func (w *webview) SetMaterial(material string) bool {
+	p := C.CString(material)
+	defer C.free(unsafe.Pointer(p))
+	return C.CgoWebViewSetMaterial(w.w, p) != 0
+}
+
+func (w *webview) MaterialSupported(material string) bool {
+	p := C.CString(material)
+	defer C.free(unsafe.Pointer(p))
+	return C.CgoWebViewMaterialSupported(w.w, p) != 0
+}
+
+func (w *webview) SetTrayIcon(data []byte) bool {
+	p := C.CBytes(data)
+	defer C.free(p)
+	return C.CgoWebViewTraySetIcon(w.w, p, C.int(len(data))) == 0
+}
+
+func (w *webview) SetTrayTooltip(tooltip string) {
+	p := C.CString(tooltip)
+	defer C.free(unsafe.Pointer(p))
+	C.CgoWebViewTraySetTooltip(w.w, p)
+}
+
+func (w *webview) SetTrayMenu(menu string) {
+	p := C.CString(menu)
+	defer C.free(unsafe.Pointer(p))
+	C.CgoWebViewTraySetMenu(w.w, p)
+}
+
+func (w *webview) RemoveTray() {
+	C.CgoWebViewTrayRemove(w.w)
+}
+
+func (w *webview) Notify(id, title, body string, silent bool) bool {
+	i := C.CString(id)
+	defer C.free(unsafe.Pointer(i))
+	t := C.CString(title)
+	defer C.free(unsafe.Pointer(t))
+	b := C.CString(body)
+	defer C.free(unsafe.Pointer(b))
+	return C.CgoWebViewNotify(w.w, i, t, b, C.int(boolToInt(silent))) != 0
+}
+
+func (w *webview) SetMenu(menu string) {
+	p := C.CString(menu)
+	defer C.free(unsafe.Pointer(p))
+	C.CgoWebViewSetMenu(w.w, p)
+}
+
+func (w *webview) ShowContextMenu(menu string, x, y int) {
+	p := C.CString(menu)
+	defer C.free(unsafe.Pointer(p))
+	C.CgoWebViewShowContextMenu(w.w, p, C.int(x), C.int(y))
+}
+
+func (w *webview) CaptureKeys(capture bool) {
+	C.CgoWebViewCaptureKeys(w.w, C.int(boolToInt(capture)))
+}
+
+func (w *webview) CaptureGestures(capture bool) {
+	C.CgoWebViewCaptureGestures(w.w, C.int(boolToInt(capture)))
+}
+
+func (w *webview) SetSystemGestures(enabled bool) {
+	C.CgoWebViewSetSystemGestures(w.w, C.int(boolToInt(enabled)))
+}
+
+func (w *webview) StartDrag(files []string) bool {
+	s := C.CString(strings.Join(files, "\n"))
+	defer C.free(unsafe.Pointer(s))
+	return C.CgoWebViewStartDrag(w.w, s) != 0
+}
+
+func (w *webview) PlaceOverlay(view unsafe.Pointer, x, y, width, height int, visible bool) {
+	C.CgoWebViewPlaceOverlay(w.w, view, C.int(x), C.int(y), C.int(width), C.int(height), C.int(boolToInt(visible)))
+}
+
+func (w *webview) RemoveOverlay(view unsafe.Pointer) {
+	C.CgoWebViewRemoveOverlay(w.w, view)
+}
+
+func (w *webview) StartAutomation(name string, callback AutomationCallbackFunc) {
+	m.Lock()
+	auto[w] = callback
+	m.Unlock()
+	namePtr := C.CString(name)
+	defer C.free(unsafe.Pointer(namePtr))
+	C.CgoWebViewAutomationStart(w.w, namePtr)
+}
+
+func (w *webview) AutomationReply(reply unsafe.Pointer, result string, err error) {
+	resultPtr := C.CString(result)
+	defer C.free(unsafe.Pointer(resultPtr))
+	var errorPtr *C.char
+	if err != nil {
+		errorPtr = C.CString(err.Error())
+		defer C.free(unsafe.Pointer(errorPtr))
+	}
+	C.CgoWebViewAutomationReply(w.w, reply, resultPtr, errorPtr)
+}
+
+func (w *webview) GeolocationReply(request unsafe.Pointer, allow bool) {
+	C.CgoWebViewGeolocationReply(w.w, request, C.int(boolToInt(allow)))
+}
+
 func (w *webview) Dialog(dlgType DialogType, flags int, title string, arg string, filter string) string {
 	const maxPath = 4096
 	titlePtr := C.CString(title)
@@ -360,6 +1484,91 @@ func (w *webview) Dialog(dlgType DialogType, flags int, title string, arg string
 	return C.GoString(resultPtr)
 }
 
+func (w *webview) SaveDialog(title string, directory string, filename string, formats string, flags int) (string, int) {
+	const maxPath = 4096
+	titlePtr := C.CString(title)
+	defer C.free(unsafe.Pointer(titlePtr))
+	directoryPtr := C.CString(directory)
+	defer C.free(unsafe.Pointer(directoryPtr))
+	filenamePtr := C.CString(filename)
+	defer C.free(unsafe.Pointer(filenamePtr))
+	formatsPtr := C.CString(formats)
+	defer C.free(unsafe.Pointer(formatsPtr))
+	resultPtr := (*C.char)(C.calloc((C.size_t)(unsafe.Sizeof((*C.char)(nil))), (C.size_t)(maxPath)))
+	defer C.free(unsafe.Pointer(resultPtr))
+	format := C.CgoSaveDialog(w.w, titlePtr, directoryPtr, filenamePtr, formatsPtr, C.int(flags), resultPtr, C.size_t(maxPath))
+	return C.GoString(resultPtr), int(format)
+}
+
+func (w *webview) OpenDialog(title string, directory string, formats string, flags int) []string {
+	const maxResult = 64 * 1024
+	titlePtr := C.CString(title)
+	defer C.free(unsafe.Pointer(titlePtr))
+	directoryPtr := C.CString(directory)
+	defer C.free(unsafe.Pointer(directoryPtr))
+	formatsPtr := C.CString(formats)
+	defer C.free(unsafe.Pointer(formatsPtr))
+	resultPtr := (*C.char)(C.calloc(1, (C.size_t)(maxResult)))
+	defer C.free(unsafe.Pointer(resultPtr))
+	C.CgoOpenDialog(w.w, titlePtr, directoryPtr, formatsPtr, C.int(flags), resultPtr, C.size_t(maxResult))
+	result := C.GoString(resultPtr)
+	if result == "" {
+		return nil
+	}
+	return strings.Split(result, "\n")
+}
+
+func (w *webview) MessageDialog(messageType int, title string, message string, buttons []string) int {
+	titlePtr := C.CString(title)
+	defer C.free(unsafe.Pointer(titlePtr))
+	messagePtr := C.CString(message)
+	defer C.free(unsafe.Pointer(messagePtr))
+	buttonsPtr := C.CString(strings.Join(buttons, "\n"))
+	defer C.free(unsafe.Pointer(buttonsPtr))
+	return int(C.CgoMessageDialog(w.w, C.int(messageType), titlePtr, messagePtr, buttonsPtr))
+}
+
+func (w *webview) SelectDirectories(title string) []string {
+	const maxResult = 64 * 1024
+	titlePtr := C.CString(title)
+	defer C.free(unsafe.Pointer(titlePtr))
+	resultPtr := (*C.char)(C.calloc(1, (C.size_t)(maxResult)))
+	defer C.free(unsafe.Pointer(resultPtr))
+	C.CgoSelectDirectories(w.w, titlePtr, resultPtr, C.size_t(maxResult))
+	result := C.GoString(resultPtr)
+	if result == "" {
+		return nil
+	}
+	return strings.Split(result, "\n")
+}
+
+func (w *webview) ColorPicker(title string, r, g, b, a uint8) (uint8, uint8, uint8, uint8, bool) {
+	titlePtr := C.CString(title)
+	defer C.free(unsafe.Pointer(titlePtr))
+	cr, cg, cb, ca := C.uint8_t(r), C.uint8_t(g), C.uint8_t(b), C.uint8_t(a)
+	ok := C.CgoColorPicker(w.w, titlePtr, &cr, &cg, &cb, &ca) == 1
+	return uint8(cr), uint8(cg), uint8(cb), uint8(ca), ok
+}
+
+func (w *webview) FontPicker(title string, family string, size int, weight int, italic bool) (string, string, int, int, bool, bool) {
+	const maxName = 256
+	titlePtr := C.CString(title)
+	defer C.free(unsafe.Pointer(titlePtr))
+	familyPtr := (*C.char)(C.calloc(1, (C.size_t)(maxName)))
+	defer C.free(unsafe.Pointer(familyPtr))
+	facePtr := (*C.char)(C.calloc(1, (C.size_t)(maxName)))
+	defer C.free(unsafe.Pointer(facePtr))
+	initial := C.CString(family)
+	C.strncpy(familyPtr, initial, maxName-1)
+	C.free(unsafe.Pointer(initial))
+	csize, cweight, citalic := C.int(size), C.int(weight), C.int(0)
+	if italic {
+		citalic = 1
+	}
+	ok := C.CgoFontPicker(w.w, titlePtr, familyPtr, C.size_t(maxName), facePtr, C.size_t(maxName), &csize, &cweight, &citalic) == 1
+	return C.GoString(familyPtr), C.GoString(facePtr), int(csize), int(cweight), citalic == 1, ok
+}
+
 func (w *webview) Eval(js string) error {
 	p := C.CString(js)
 	defer C.free(unsafe.Pointer(p))
@@ -380,6 +1589,12 @@ func (w *webview) Terminate() {
 	C.CgoWebViewTerminate(w.w)
 }
 
+func (w *webview) Close() {
+	if atomic.LoadInt32(&w.closed) == 0 {
+		C.CgoWebViewClose(w.w)
+	}
+}
+
 //export _webviewDispatchGoCallback
 func _webviewDispatchGoCallback(index unsafe.Pointer) {
 	var f func()
@@ -409,3 +1624,289 @@ func _webviewExternalInvokeCallback(w unsafe.Pointer, data unsafe.Pointer) {
 		cb(wv, C.GoString((*C.char)(data)))
 	}
 }
+
+//export _webviewNewTabCallback
+func _webviewNewTabCallback(w unsafe.Pointer) {
+	m.Lock()
+	var cb NewTabCallbackFunc
+	var wv WebView
+	for view, callback := range tabs {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil {
+		cb(wv)
+	}
+}
+
+//export _webviewProcessTerminatedCallback
+func _webviewProcessTerminatedCallback(w unsafe.Pointer, reason unsafe.Pointer) {
+	m.Lock()
+	var cb ProcessTerminatedCallbackFunc
+	var wv WebView
+	for view, callback := range crash {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil {
+		cb(wv, C.GoString((*C.char)(reason)))
+	}
+}
+
+//export _webviewAutomationCallback
+func _webviewAutomationCallback(w unsafe.Pointer, method unsafe.Pointer, args unsafe.Pointer, reply unsafe.Pointer) {
+	m.Lock()
+	var cb AutomationCallbackFunc
+	var wv WebView
+	for view, callback := range auto {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil {
+		cb(wv, C.GoString((*C.char)(method)), C.GoString((*C.char)(args)), reply)
+	}
+}
+
+//export _webviewClosingCallback
+func _webviewClosingCallback(w unsafe.Pointer) C.int {
+	m.Lock()
+	var cb ClosingCallbackFunc
+	var wv WebView
+	for view, callback := range leave {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil && cb(wv) {
+		return 1
+	}
+	return 0
+}
+
+//export _webviewWindowEventCallback
+func _webviewWindowEventCallback(w unsafe.Pointer, event C.int) {
+	m.Lock()
+	var cb WindowEventCallbackFunc
+	var wv WebView
+	for view, callback := range moves {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil {
+		cb(wv, WindowEvent(event))
+	}
+}
+
+//export _webviewTrayCallback
+func _webviewTrayCallback(w unsafe.Pointer, item C.int) {
+	m.Lock()
+	var cb TrayCallbackFunc
+	var wv WebView
+	for view, callback := range trays {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil {
+		cb(wv, int(item))
+	}
+}
+
+//export _webviewMenuCallback
+func _webviewMenuCallback(w unsafe.Pointer, item C.int) {
+	m.Lock()
+	var cb MenuCallbackFunc
+	var wv WebView
+	for view, callback := range menus {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil {
+		cb(wv, int(item))
+	}
+}
+
+//export _webviewContextMenuCallback
+func _webviewContextMenuCallback(w unsafe.Pointer, item C.int) {
+	m.Lock()
+	var cb ContextMenuCallbackFunc
+	var wv WebView
+	for view, callback := range popup {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil {
+		cb(wv, int(item))
+	}
+}
+
+//export _webviewKeyCallback
+func _webviewKeyCallback(w unsafe.Pointer, key *C.char, modifiers C.int, down C.int) C.int {
+	m.Lock()
+	var cb KeyCallbackFunc
+	var wv WebView
+	for view, callback := range keys {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil && cb(wv, C.GoString(key), KeyModifier(modifiers), down != 0) {
+		return 1
+	}
+	return 0
+}
+
+//export _webviewGestureCallback
+func _webviewGestureCallback(w unsafe.Pointer, gesture C.int, dx, dy C.double) C.int {
+	m.Lock()
+	var cb GestureCallbackFunc
+	var wv WebView
+	for view, callback := range swipe {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil && cb(wv, Gesture(gesture), float64(dx), float64(dy)) {
+		return 1
+	}
+	return 0
+}
+
+//export _webviewNotificationCallback
+func _webviewNotificationCallback(w unsafe.Pointer, id *C.char) {
+	m.Lock()
+	var cb NotificationCallbackFunc
+	var wv WebView
+	for view, callback := range toast {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb != nil {
+		cb(wv, C.GoString(id))
+	}
+}
+
+//export _webviewShareCallback
+func _webviewShareCallback(w unsafe.Pointer, text *C.char, files *C.char) {
+	m.Lock()
+	var cb ShareCallbackFunc
+	var wv WebView
+	for view, callback := range share {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb == nil {
+		return
+	}
+	var paths []string
+	if list := C.GoString(files); list != "" {
+		paths = strings.Split(list, "\n")
+	}
+	cb(wv, C.GoString(text), paths)
+}
+
+//export _webviewGeolocationCallback
+func _webviewGeolocationCallback(w unsafe.Pointer, origin *C.char, request unsafe.Pointer) {
+	m.Lock()
+	var cb GeolocationCallbackFunc
+	var wv WebView
+	for view, callback := range where {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb == nil {
+		C.CgoWebViewGeolocationReply(w, request, 0)
+		return
+	}
+	cb(wv, C.GoString(origin), request)
+}
+
+//export _webviewFileDropCallback
+func _webviewFileDropCallback(w unsafe.Pointer, files *C.char, x, y C.int) {
+	m.Lock()
+	var cb FileDropCallbackFunc
+	var wv WebView
+	for view, callback := range drops {
+		if view.(*webview).w == w {
+			wv, cb = view, callback
+			break
+		}
+	}
+	m.Unlock()
+	if cb == nil {
+		return
+	}
+	cb(wv, strings.Split(C.GoString(files), "\n"), int(x), int(y))
+}
+
+//export _webviewClosedCallback
+func _webviewClosedCallback(w unsafe.Pointer) {
+	m.Lock()
+	var cb ClosedCallbackFunc
+	var wv WebView
+	for view := range cbs {
+		if view.(*webview).w == w {
+			wv = view
+			break
+		}
+	}
+	if wv != nil {
+		atomic.StoreInt32(&wv.(*webview).closed, 1)
+		cb = gone[wv]
+		delete(cbs, wv)
+		delete(tabs, wv)
+		delete(crash, wv)
+		delete(auto, wv)
+		delete(gone, wv)
+		delete(leave, wv)
+		delete(moves, wv)
+		delete(trays, wv)
+		delete(menus, wv)
+		delete(popup, wv)
+		delete(keys, wv)
+		delete(swipe, wv)
+		delete(toast, wv)
+		delete(share, wv)
+		delete(where, wv)
+		delete(drops, wv)
+	}
+	m.Unlock()
+	if cb != nil {
+		cb(wv)
+	}
+}
@@ -47,10 +47,13 @@ extern "C"
   struct webview_priv
   {
     GtkWidget *window;
+    GtkWidget *overlay;
     GtkWidget *scroller;
     GtkWidget *webview;
     GtkWidget *inspector_window;
     GAsyncQueue *queue;
+    // The position and size of the window when it was last configured
+    GdkRectangle geometry;
     int ready;
     int js_busy;
     int should_exit;
@@ -59,6 +62,37 @@ extern "C"
     int min_height;
     int max_width;
     int max_height;
+    // The width of the window divided by its height, or 0 if it isn't locked
+    double aspect;
+    // The tray icon and its menu, see webview_tray_set_icon
+    GtkStatusIcon *tray;
+    GtkWidget *tray_menu;
+    // The window's content is packed in a box below its menu bar, which is
+    // added by webview_set_menu
+    GtkWidget *box;
+    GtkWidget *menubar;
+    // Holds the accelerators of the menu bar's items
+    GtkAccelGroup *accel_group;
+    // The menu shown by webview_show_context_menu
+    GtkWidget *context_menu;
+    // How far the fingers have moved in a swipe, see webview_swipe_cb
+    double swipe_dx;
+    double swipe_dy;
+    // The connection to the notification server, its signals and the ids of
+    // the notifications shown by webview_notify, by the server's numbers
+    GDBusConnection *notifications;
+    guint notification_signals;
+    GHashTable *notification_ids;
+    // The paths of the files being dragged over the webview, and where they
+    // were dropped while WebKit waits for them, see webview_drag_drop_cb
+    char *drop_files;
+    int drop_pending;
+    int drop_x;
+    int drop_y;
+    // The URIs of the files being dragged out of the webview, see
+    // webview_start_drag
+    gchar **drag_uris;
+    int drag_source;
   };
 #elif defined(WEBVIEW_WINAPI)
 #define CINTERFACE
@@ -68,7 +102,10 @@ extern "C"
 #include <exdisp.h>
 #include <mshtmhst.h>
 #include <mshtml.h>
+#include <shellapi.h>
+#include <shlobj.h>
 #include <shobjidl.h>
+#include <uxtheme.h>
 
 #include <stdio.h>
 
@@ -80,15 +117,51 @@ struct webview_priv
   DWORD saved_style;
   DWORD saved_ex_style;
   RECT saved_rect;
+  DWORD automation_cookie;
+
+  // The width of a pane, or its height when vertical, and where the
+  // splitter before it is in the host's window
+  int pane_size;
+  int splitter;
+  // The pane after the splitter being dragged in a host
+  struct webview *dragging;
+  // The type of the last WM_SIZE, EG: SIZE_MINIMIZED
+  WPARAM size_type;
+  // The icons set with webview_set_icon, destroyed when they are replaced
+  HICON icon;
+  HICON small_icon;
 
   int min_width;
   int min_height;
   int max_width;
   int max_height;
+  // The width of the content divided by its height, or 0 if it isn't locked
+  double aspect;
+  // How far webview_set_opacity has faded the window, so that windows start
+  // opaque, and the background set with webview_set_color. Both are applied
+  // together by webview_update_layered.
+  BYTE transparency;
+  COLORREF color;
+  BYTE color_alpha;
+  // The tray icon, added when its cbSize is set, and its menu
+  NOTIFYICONDATAW tray;
+  HMENU tray_menu;
+  // The id of the notification shown in the tray icon's balloon, and whether
+  // the icon was only added to show it
+  char *notification;
+  BOOL notification_icon;
+  // The menu bar set with webview_set_menu and its items' accelerators
+  HMENU menu;
+  HACCEL accelerators;
+  // Shows the progress and badge on the taskbar button, created when first
+  // needed
+  ITaskbarList3 *taskbar;
 };
 #elif defined(WEBVIEW_COCOA)
 #import <Cocoa/Cocoa.h>
+#import <CoreLocation/CoreLocation.h>
 #import <WebKit/WebKit.h>
+#import <objc/message.h>
 #import <objc/runtime.h>
 
 struct webview_priv
@@ -98,6 +171,15 @@ struct webview_priv
   WebView *webview;
   id delegate;
   int should_exit;
+  int zoomed;
+  int traffic_light_set;
+  int traffic_light_x;
+  int traffic_light_y;
+  NSStatusItem *tray;
+  // The menus the window added to the app's menu bar
+  NSArray *menus;
+  // The view showing the window's material behind the page, if it has one
+  NSVisualEffectView *effect;
 };
 #else
 #error "Define one of: WEBVIEW_GTK, WEBVIEW_COCOA or WEBVIEW_WINAPI"
@@ -108,6 +190,75 @@ struct webview_priv
   typedef void (*webview_external_invoke_cb_t)(struct webview *w,
                                                const char *arg);
 
+  typedef void (*webview_new_tab_cb_t)(struct webview *w);
+
+  typedef void (*webview_process_terminated_cb_t)(struct webview *w,
+                                                  const char *reason);
+
+  // reply identifies the pending call and is passed to webview_automation_reply
+  typedef void (*webview_automation_cb_t)(struct webview *w, const char *method,
+                                          const char *args, void *reply);
+
+  typedef void (*webview_closed_cb_t)(struct webview *w);
+
+  // Called when the user closes the window, while it can still be queried.
+  // Returns non-zero to keep the window open.
+  typedef int (*webview_closing_cb_t)(struct webview *w);
+
+  // Called when the window is resized, moved, focused, minimised and so on.
+  // The event is one of enum webview_window_event.
+  typedef void (*webview_window_event_cb_t)(struct webview *w, int event);
+
+  // Called when the tray icon is clicked, with -1, or an item of its menu is
+  // chosen, with the item's number
+  typedef void (*webview_tray_cb_t)(struct webview *w, int item);
+
+  // Called when an item of the menu bar is chosen, with the item's number
+  typedef void (*webview_menu_cb_t)(struct webview *w, int item);
+
+  // Called when an item of the menu shown by webview_show_context_menu is
+  // chosen, with the item's number
+  typedef void (*webview_context_menu_cb_t)(struct webview *w, int item);
+
+  // Called when a key is pressed or released in a window that captures
+  // keys, before the page sees it. The key is named as described by
+  // webview_capture_keys and the modifiers are enum webview_key_modifier
+  // flags. Returns non-zero to keep the key from the page.
+  typedef int (*webview_key_cb_t)(struct webview *w, const char *key,
+                                  int modifiers, int down);
+
+  // Called when a mouse side button is clicked, the page is scrolled
+  // sideways or a swipe gesture is made in a window that captures gestures,
+  // before the page sees it. The gesture is one of enum webview_gesture.
+  // Returns non-zero to keep it from the page.
+  typedef int (*webview_gesture_cb_t)(struct webview *w, int gesture,
+                                      double dx, double dy);
+
+  // Called when a notification shown by webview_notify is clicked, with its
+  // id
+  typedef void (*webview_notification_cb_t)(struct webview *w,
+                                            const char *id);
+
+  // Called when another app shares text or files with this one, with the
+  // files' paths one per line. Only MacOS shares with the main window, through
+  // the services in the app's Info.plist whose NSMessage is "share".
+  typedef void (*webview_share_cb_t)(struct webview *w, const char *text,
+                                     const char *files);
+
+  // Called when the page asks for the user's location with
+  // navigator.geolocation, with the page's origin. request identifies the
+  // pending request and is passed to webview_geolocation_reply. The location
+  // comes from GeoClue on Linux and CoreLocation on MacOS. MSHTML has no
+  // permission hook, so it isn't called on Windows.
+  typedef void (*webview_geolocation_cb_t)(struct webview *w,
+                                           const char *origin, void *request);
+
+  // Called when files are dragged from the desktop and dropped on the
+  // webview, with their paths one per line and where they were dropped in
+  // the page's coordinates. The page gets the drop as well.
+  typedef void (*webview_file_drop_cb_t)(struct webview *w, const char *files,
+                                         int x, int y);
+
   struct webview
   {
     const char *url;
@@ -117,7 +268,47 @@ struct webview_priv
     int resizable;
     int transparentTitlebar;
     int debug;
+    int tabbing;
+    // Secondary windows don't end the main loop when they are closed
+    int secondary;
+    // Transparent windows show the desktop through the background colour.
+    // The backdrop is one of enum webview_backdrop.
+    int transparent;
+    int backdrop;
+    // Kiosk windows fill the screen, stay above other windows and can't be
+    // closed or left with the keyboard
+    int kiosk;
+    // Hidden windows are created without being shown, until
+    // webview_set_visible is called
+    int hidden;
+    // Embedded windows run in a host app that owns the main loop. Closing
+    // them doesn't end the loop and the app's menus are left alone.
+    int embedded;
+    // Panes are docked in their host's window after its webview and the
+    // panes before them, with splitters between them. Vertical panes are
+    // docked below. The host keeps its panes in a list.
+    struct webview *host;
+    struct webview *next_pane;
+    int vertical;
+    // Set by webview_capture_keys and webview_capture_gestures
+    int capture_keys;
+    int capture_gestures;
     webview_external_invoke_cb_t external_invoke_cb;
+    webview_new_tab_cb_t new_tab_cb;
+    webview_process_terminated_cb_t process_terminated_cb;
+    webview_automation_cb_t automation_cb;
+    webview_closed_cb_t closed_cb;
+    webview_closing_cb_t closing_cb;
+    webview_window_event_cb_t window_event_cb;
+    webview_tray_cb_t tray_cb;
+    webview_menu_cb_t menu_cb;
+    webview_context_menu_cb_t context_menu_cb;
+    webview_key_cb_t key_cb;
+    webview_gesture_cb_t gesture_cb;
+    webview_notification_cb_t notification_cb;
+    webview_share_cb_t share_cb;
+    webview_geolocation_cb_t geolocation_cb;
+    webview_file_drop_cb_t file_drop_cb;
     struct webview_priv priv;
     void *userdata;
   };
@@ -137,6 +328,130 @@ struct webview_priv
 #define WEBVIEW_DIALOG_FLAG_ERROR (3 << 1)
 #define WEBVIEW_DIALOG_FLAG_ALERT_MASK (3 << 1)
 
+/* Save dialog formats are given as "name|*.ext;*.ext" lines. The dialog
+   starts in the given directory with the given filename, if not empty. */
+#define WEBVIEW_SAVE_FLAG_CREATE_DIRECTORIES (1 << 0)
+#define WEBVIEW_SAVE_FLAG_CONFIRM_OVERWRITE (1 << 1)
+
+/* Open dialogs take the same formats and return the files as lines */
+#define WEBVIEW_OPEN_FLAG_MULTIPLE (1 << 0)
+#define WEBVIEW_OPEN_FLAG_DIRECTORY (1 << 1)
+
+/* Message dialogs show the buttons given as lines, or OK, and return the
+   index of the pressed one, or -1 if the dialog was dismissed */
+#define WEBVIEW_MESSAGE_INFO 0
+#define WEBVIEW_MESSAGE_WARNING 1
+#define WEBVIEW_MESSAGE_ERROR 2
+#define WEBVIEW_MESSAGE_QUESTION 3
+
+  enum webview_corner
+  {
+    WEBVIEW_CORNER_TOP_LEFT = 0,
+    WEBVIEW_CORNER_TOP_RIGHT = 1,
+    WEBVIEW_CORNER_BOTTOM_LEFT = 2,
+    WEBVIEW_CORNER_BOTTOM_RIGHT = 3
+  };
+
+  enum webview_backdrop
+  {
+    // Areas painted in the background colour are see-through. Uses the
+    // colour's alpha on Linux and MacOS and cuts out the colour on Windows.
+    WEBVIEW_BACKDROP_TRANSPARENT = 0,
+    // The desktop is blurred behind the window on MacOS and the whole window
+    // is made as opaque as the background colour on Windows
+    WEBVIEW_BACKDROP_TRANSLUCENT = 1
+  };
+
+  enum webview_window_event
+  {
+    WEBVIEW_WINDOW_EVENT_RESIZE = 0,
+    WEBVIEW_WINDOW_EVENT_MOVE = 1,
+    WEBVIEW_WINDOW_EVENT_FOCUS = 2,
+    WEBVIEW_WINDOW_EVENT_BLUR = 3,
+    WEBVIEW_WINDOW_EVENT_MINIMISE = 4,
+    WEBVIEW_WINDOW_EVENT_MAXIMISE = 5,
+    // The window is no longer minimised or maximised
+    WEBVIEW_WINDOW_EVENT_RESTORE = 6,
+    // Displays were added, removed or rearranged
+    WEBVIEW_WINDOW_EVENT_DISPLAYS = 7,
+    // The window may have moved to a display with a different scale
+    WEBVIEW_WINDOW_EVENT_SCALE = 8
+  };
+
+  enum webview_key_modifier
+  {
+    WEBVIEW_KEY_SHIFT = 1,
+    WEBVIEW_KEY_CONTROL = 2,
+    WEBVIEW_KEY_ALT = 4,
+    // The Command key on MacOS and the Windows key on Windows
+    WEBVIEW_KEY_SUPER = 8
+  };
+
+  enum webview_gesture
+  {
+    // The mouse's back and forward side buttons
+    WEBVIEW_GESTURE_BACK = 0,
+    WEBVIEW_GESTURE_FORWARD = 1,
+    // The page is scrolled sideways by dx steps of the wheel, to the right
+    // if positive
+    WEBVIEW_GESTURE_SCROLL = 2,
+    // A swipe on a touchpad, where dx and dy are -1, 0 or 1 for its
+    // direction, EG: dx is 1 for a swipe to the right. Windows has none.
+    WEBVIEW_GESTURE_SWIPE = 3
+  };
+
+  enum webview_edge
+  {
+    WEBVIEW_EDGE_NONE = 0,
+    WEBVIEW_EDGE_TOP = 1,
+    WEBVIEW_EDGE_BOTTOM = 2,
+    WEBVIEW_EDGE_LEFT = 3,
+    WEBVIEW_EDGE_RIGHT = 4
+  };
+
+  // Returns the position of a window of the given size snapped to the
+  // given edge of the given area. With no edge, the window is centered.
+  static void webview_edge_position(int edge, int margin, int areaX, int areaY,
+                                    int areaWidth, int areaHeight, int width,
+                                    int height, int *x, int *y)
+  {
+    *x = areaX + (areaWidth - width) / 2;
+    *y = areaY + (areaHeight - height) / 2;
+    switch (edge)
+    {
+    case WEBVIEW_EDGE_TOP:
+      *y = areaY + margin;
+      break;
+    case WEBVIEW_EDGE_BOTTOM:
+      *y = areaY + areaHeight - height - margin;
+      break;
+    case WEBVIEW_EDGE_LEFT:
+      *x = areaX + margin;
+      break;
+    case WEBVIEW_EDGE_RIGHT:
+      *x = areaX + areaWidth - width - margin;
+      break;
+    }
+  }
+
+  // Returns the position of a window of the given size snapped
+  // to the given corner of the given area
+  static void webview_corner_position(int corner, int margin, int areaX, int areaY,
+                                      int areaWidth, int areaHeight, int width,
+                                      int height, int *x, int *y)
+  {
+    *x = areaX + margin;
+    *y = areaY + margin;
+    if (corner == WEBVIEW_CORNER_TOP_RIGHT || corner == WEBVIEW_CORNER_BOTTOM_RIGHT)
+    {
+      *x = areaX + areaWidth - width - margin;
+    }
+    if (corner == WEBVIEW_CORNER_BOTTOM_LEFT || corner == WEBVIEW_CORNER_BOTTOM_RIGHT)
+    {
+      *y = areaY + areaHeight - height - margin;
+    }
+  }
+
   typedef void (*webview_dispatch_fn)(struct webview *w, void *arg);
 
   struct webview_dispatch_arg
@@ -174,24 +489,173 @@ struct webview_priv
                           int height, int resizable);
 
   WEBVIEW_API int webview_init(struct webview *w);
+  WEBVIEW_API int webview_init_pane(struct webview *w);
   WEBVIEW_API int webview_loop(struct webview *w, int blocking);
   WEBVIEW_API int webview_eval(struct webview *w, const char *js);
   WEBVIEW_API int webview_inject_css(struct webview *w, const char *css);
   WEBVIEW_API void webview_set_title(struct webview *w, const char *title);
+  WEBVIEW_API int webview_set_icon(struct webview *w, const uint8_t *data,
+                                   int size);
+  // Sets what the about dialog shows for every window of the app. The name
+  // defaults to the window's title and the icon, given as PNG data, to the
+  // app's. On MacOS, it's set before the first window is created so the
+  // app menu's About item is named after the app.
+  WEBVIEW_API void webview_set_about(const char *name, const char *version,
+                                     const char *copyright,
+                                     const uint8_t *icon, int iconsz);
+  WEBVIEW_API void webview_show_about(struct webview *w);
   WEBVIEW_API void webview_focus(struct webview *w);
+  WEBVIEW_API void webview_reload(struct webview *w);
+  WEBVIEW_API void webview_navigate(struct webview *w, const char *url);
   WEBVIEW_API void webview_minsize(struct webview *w, int width, int height);  
   WEBVIEW_API void webview_maxsize(struct webview *w, int width, int height);
+  WEBVIEW_API void webview_set_aspect_ratio(struct webview *w, int width,
+                                            int height);
   WEBVIEW_API void webview_set_fullscreen(struct webview *w, int fullscreen);
+  WEBVIEW_API void webview_set_always_on_top(struct webview *w, int onTop);
+  WEBVIEW_API void webview_set_borderless(struct webview *w, int borderless);
+  WEBVIEW_API void webview_set_size(struct webview *w, int width, int height);
+  WEBVIEW_API void webview_snap_to_corner(struct webview *w, int corner, int margin);
+  WEBVIEW_API void webview_set_ignore_mouse_events(struct webview *w, int ignore);
+  WEBVIEW_API void webview_cursor_position(struct webview *w, int *x, int *y);
+  WEBVIEW_API int webview_display_count(struct webview *w);
+  WEBVIEW_API void webview_display_workarea(struct webview *w, int display, int *x,
+                                            int *y, int *width, int *height);
+  WEBVIEW_API void webview_display_bounds(struct webview *w, int display, int *x,
+                                         int *y, int *width, int *height,
+                                         double *scale, int *primary);
+  WEBVIEW_API int webview_cursor_display(struct webview *w);
+  WEBVIEW_API double webview_get_scale(struct webview *w);
+  WEBVIEW_API void webview_place(struct webview *w, int display, int edge, int margin);
+  WEBVIEW_API void webview_set_position(struct webview *w, int x, int y);
+  WEBVIEW_API void webview_get_position(struct webview *w, int *x, int *y);
+  WEBVIEW_API void webview_get_size(struct webview *w, int *width, int *height);
+  WEBVIEW_API void webview_set_maximised(struct webview *w, int maximised);
+  WEBVIEW_API int webview_is_maximised(struct webview *w);
+  WEBVIEW_API void webview_set_minimised(struct webview *w, int minimised);
+  WEBVIEW_API void webview_set_visible(struct webview *w, int visible);
+  WEBVIEW_API void webview_set_traffic_light_position(struct webview *w, int x, int y);
+  WEBVIEW_API void webview_titlebar_button_area(struct webview *w, int *x, int *y,
+                                                int *width, int *height);
+  WEBVIEW_API void webview_set_titlebar_color(struct webview *w, uint8_t r,
+                                              uint8_t g, uint8_t b, uint8_t sr,
+                                              uint8_t sg, uint8_t sb);
+  WEBVIEW_API void webview_show_emoji_picker(struct webview *w);
+  WEBVIEW_API void webview_request_attention(struct webview *w, int critical);
+  WEBVIEW_API void webview_set_opacity(struct webview *w, double opacity);
+  // Shows the progress of a long task, from 0 to 1, on the window's taskbar
+  // button on Windows, the app's dock icon on MacOS and its launcher entry
+  // on Linux, where the desktop supports the Unity launcher API. Negative
+  // progress hides it.
+  WEBVIEW_API void webview_set_progress(struct webview *w, double progress);
+  // Shows a short label, EG: an unread count, on the taskbar button, dock
+  // icon or launcher entry, or hides it if the badge is empty. Launcher
+  // entries only show numbers.
+  WEBVIEW_API void webview_set_badge(struct webview *w, const char *badge);
+  // Fills the window's background with a material of the platform, shown
+  // where the page leaves its background transparent, or removes it if the
+  // material is "none". Materials are named as NSVisualEffectMaterial on
+  // MacOS, EG: "sidebar" or "hudWindow", and "mica", "acrylic" or
+  // "tabbed" on Windows. Returns 0 if the material isn't supported.
+  WEBVIEW_API int webview_set_material(struct webview *w,
+                                       const char *material);
+  // Returns non-zero if webview_set_material supports the material
+  WEBVIEW_API int webview_material_supported(struct webview *w,
+                                             const char *material);
+  // The tray icon is added by webview_tray_set_icon and belongs to the
+  // window. Its menu has an item per line, either "-" for a separator or
+  // whether the item is enabled then whether it's checked, each "0" or "1",
+  // followed by its label. Items are numbered from 0, counting separators.
+  WEBVIEW_API int webview_tray_set_icon(struct webview *w, const uint8_t *data,
+                                        int size);
+  WEBVIEW_API void webview_tray_set_tooltip(struct webview *w,
+                                            const char *tooltip);
+  WEBVIEW_API void webview_tray_set_menu(struct webview *w, const char *menu);
+  WEBVIEW_API void webview_tray_remove(struct webview *w);
+  // Shows a notification from the app, in the desktop's notification area
+  // on Linux and MacOS and as a balloon from the tray icon on Windows. The
+  // window's icon is put in the tray while the balloon is shown if it has no
+  // tray icon. Silent notifications don't play a sound. Returns 0 if the
+  // notification couldn't be shown, EG: on MacOS if the app isn't bundled.
+  WEBVIEW_API int webview_notify(struct webview *w, const char *id,
+                                 const char *title, const char *body,
+                                 int silent);
+  // The menu bar has an item per line, encoded as for the tray icon's menu
+  // but indented with a tab per level. Unindented items are the menus in the
+  // bar, and the items after an item that are indented one more level are
+  // its submenu. Items are numbered from 0, counting menus and separators.
+  // Items may also be radio items and have an accelerator and a role, see
+  // webview_menu_next_item. Items with a role aren't reported to menu_cb.
+  // On MacOS, the menus are added to the app's menu bar after the Edit menu.
+  WEBVIEW_API void webview_set_menu(struct webview *w, const char *menu);
+  // Shows a menu, encoded as for the menu bar, at the given position in the
+  // page, in CSS pixels. Items are numbered as in the menu bar.
+  WEBVIEW_API void webview_show_context_menu(struct webview *w,
+                                             const char *menu, int x, int y);
+  // Gives the keys pressed in the window to key_cb before the page, EG: for
+  // shortcuts that work wherever the focus is. Keys are named as they are
+  // typed without Shift, EG: "a" or "5", or by what they do, EG: "Enter",
+  // "Escape", "Tab", "Backspace", "Delete", "Insert", "Home", "End",
+  // "PageUp", "PageDown", "ArrowLeft", "Space" or "F5". Modifier keys alone
+  // aren't given. Embedded windows can't capture keys.
+  WEBVIEW_API void webview_capture_keys(struct webview *w, int capture);
+  // Gives the mouse's side buttons, sideways scrolling and swipes in the
+  // window to gesture_cb before the page, which handles them differently on
+  // each platform, if at all. Embedded windows can't capture gestures.
+  WEBVIEW_API void webview_capture_gestures(struct webview *w, int capture);
+  // Turns the system's pen and touch gestures in the window on or off, EG:
+  // so drawing apps get press-and-hold and flicks as pointer events. Only
+  // Windows has such gestures outside of the page, which resets them when it
+  // loads another page, so they are turned off again after each load.
+  WEBVIEW_API void webview_set_system_gestures(struct webview *w, int enabled);
+  // Starts dragging the files, whose paths are separated by newlines, out of
+  // the window, EG: to the file manager. It must be called while the primary
+  // mouse button is held, and returns non-zero if the drag started.
+  WEBVIEW_API int webview_start_drag(struct webview *w, const char *files);
+  WEBVIEW_API void webview_place_overlay(struct webview *w, void *view, int x,
+                                         int y, int width, int height,
+                                         int visible);
+  WEBVIEW_API void webview_remove_overlay(struct webview *w, void *view);
+  WEBVIEW_API void webview_automation_start(struct webview *w, const char *name);
+  WEBVIEW_API void webview_automation_reply(struct webview *w, void *reply,
+                                            const char *result, const char *error);
+  WEBVIEW_API void webview_geolocation_reply(struct webview *w, void *request,
+                                             int allow);
   WEBVIEW_API void webview_set_color(struct webview *w, uint8_t r, uint8_t g,
                                      uint8_t b, uint8_t a);
   WEBVIEW_API void webview_dialog(struct webview *w,
                                   enum webview_dialog_type dlgtype, int flags,
                                   const char *title, const char *arg,
                                   char *result, size_t resultsz, char *filter);
+  WEBVIEW_API int webview_save_dialog(struct webview *w, const char *title,
+                                      const char *directory,
+                                      const char *filename,
+                                      const char *formats, int flags,
+                                      char *result, size_t resultsz);
+  WEBVIEW_API void webview_open_dialog(struct webview *w, const char *title,
+                                       const char *directory, const char *formats,
+                                       int flags, char *result, size_t resultsz);
+  WEBVIEW_API int webview_message_dialog(struct webview *w, int type,
+                                         const char *title, const char *message,
+                                         const char *buttons);
+  WEBVIEW_API void webview_select_directories(struct webview *w, const char *title,
+                                              char *result, size_t resultsz);
+  WEBVIEW_API int webview_color_picker(struct webview *w, const char *title,
+                                       uint8_t *r, uint8_t *g, uint8_t *b,
+                                       uint8_t *a);
+  // The font picker starts with the given family, if not empty, size, weight
+  // (100 to 900, as in CSS) and style. The chosen font is returned in them,
+  // with the name of its face, EG: "Semibold Italic", if the platform has
+  // one.
+  WEBVIEW_API int webview_font_picker(struct webview *w, const char *title,
+                                      char *family, size_t familysz,
+                                      char *face, size_t facesz, int *size,
+                                      int *weight, int *italic);
   WEBVIEW_API void webview_dispatch(struct webview *w, webview_dispatch_fn fn,
                                     void *arg);
   WEBVIEW_API void webview_terminate(struct webview *w);
   WEBVIEW_API void webview_exit(struct webview *w);
+  WEBVIEW_API void webview_close(struct webview *w);
   WEBVIEW_API void webview_debug(const char *format, ...);
   WEBVIEW_API void webview_print_log(const char *s);
 
@@ -277,6 +741,272 @@ struct webview_priv
     return r;
   }
 
+  // Set with webview_set_about. Unset fields are NULL.
+  static struct
+  {
+    char *name;
+    char *version;
+    char *copyright;
+    uint8_t *icon;
+    int iconsz;
+  } webview_about;
+
+  static char *webview_about_field(char *previous, const char *value)
+  {
+    free(previous);
+    return value != NULL && *value != '\0' ? strdup(value) : NULL;
+  }
+
+  WEBVIEW_API void webview_set_about(const char *name, const char *version,
+                                     const char *copyright,
+                                     const uint8_t *icon, int iconsz)
+  {
+    webview_about.name = webview_about_field(webview_about.name, name);
+    webview_about.version = webview_about_field(webview_about.version, version);
+    webview_about.copyright =
+        webview_about_field(webview_about.copyright, copyright);
+    free(webview_about.icon);
+    webview_about.icon = NULL;
+    webview_about.iconsz = 0;
+    if (icon != NULL && iconsz > 0)
+    {
+      webview_about.icon = (uint8_t *)malloc(iconsz);
+      memcpy(webview_about.icon, icon, iconsz);
+      webview_about.iconsz = iconsz;
+    }
+  }
+
+  static void webview_window_event(struct webview *w, int event)
+  {
+    if (w->window_event_cb != NULL)
+    {
+      w->window_event_cb(w, event);
+    }
+  }
+
+  static void webview_tray_event(struct webview *w, int item)
+  {
+    if (w->tray_cb != NULL)
+    {
+      w->tray_cb(w, item);
+    }
+  }
+
+  static void webview_notification_event(struct webview *w, const char *id)
+  {
+    if (w->notification_cb != NULL)
+    {
+      w->notification_cb(w, id);
+    }
+  }
+
+  // Reads the next item of a tray menu, copying its label into label, which
+  // holds size bytes. Returns the rest of the menu, or NULL at its end.
+  static const char *webview_tray_next_item(const char *menu, char *label,
+                                            size_t size, int *separator,
+                                            int *enabled, int *checked)
+  {
+    if (menu == NULL || *menu == '\0')
+    {
+      return NULL;
+    }
+    const char *end = strchr(menu, '\n');
+    size_t length = end != NULL ? (size_t)(end - menu) : strlen(menu);
+    *separator = length == 1 && menu[0] == '-';
+    *enabled = length > 0 && menu[0] == '1';
+    *checked = length > 1 && menu[1] == '1';
+    label[0] = '\0';
+    if (!*separator && length > 2)
+    {
+      size_t n = length - 2 < size - 1 ? length - 2 : size - 1;
+      memcpy(label, menu + 2, n);
+      label[n] = '\0';
+    }
+    return end != NULL ? end + 1 : menu + length;
+  }
+
+  static void webview_menu_event(struct webview *w, int item)
+  {
+    if (w->menu_cb != NULL)
+    {
+      w->menu_cb(w, item);
+    }
+  }
+
+  static void webview_context_menu_event(struct webview *w, int item)
+  {
+    if (w->context_menu_cb != NULL)
+    {
+      w->context_menu_cb(w, item);
+    }
+  }
+
+  WEBVIEW_API void webview_capture_keys(struct webview *w, int capture)
+  {
+    w->capture_keys = capture;
+  }
+
+  // Returns non-zero if the key should be kept from the page
+  static int webview_key_event(struct webview *w, const char *key,
+                               int modifiers, int down)
+  {
+    if (!w->capture_keys || w->key_cb == NULL || key[0] == '\0')
+    {
+      return 0;
+    }
+    return w->key_cb(w, key, modifiers, down);
+  }
+
+  WEBVIEW_API void webview_capture_gestures(struct webview *w, int capture)
+  {
+    w->capture_gestures = capture;
+  }
+
+  // Returns non-zero if the gesture should be kept from the page
+  static int webview_gesture_event(struct webview *w, int gesture, double dx,
+                                   double dy)
+  {
+    if (!w->capture_gestures || w->gesture_cb == NULL)
+    {
+      return 0;
+    }
+    return w->gesture_cb(w, gesture, dx, dy);
+  }
+
+  // The names of the keys that don't type a character
+  struct webview_key_name
+  {
+    unsigned int code;
+    const char *name;
+  };
+
+  // Returns how deep the next item of the menu bar is, from the tabs it
+  // starts with, or -1 at the end of the menu
+  static int webview_menu_depth(const char *menu)
+  {
+    int depth = 0;
+    if (menu == NULL || *menu == '\0')
+    {
+      return -1;
+    }
+    while (menu[depth] == '\t')
+    {
+      depth++;
+    }
+    return depth;
+  }
+
+  // Items with a role do what the platform's standard item does rather
+  // than being reported, EG: copying the page's selection
+  enum webview_menu_role
+  {
+    WEBVIEW_MENU_ROLE_NONE = 0,
+    WEBVIEW_MENU_ROLE_CUT,
+    WEBVIEW_MENU_ROLE_COPY,
+    WEBVIEW_MENU_ROLE_PASTE,
+    WEBVIEW_MENU_ROLE_SELECT_ALL,
+    WEBVIEW_MENU_ROLE_QUIT,
+    WEBVIEW_MENU_ROLE_MINIMIZE,
+    WEBVIEW_MENU_ROLE_ABOUT
+  };
+
+  // The names of the roles, in the order of enum webview_menu_role
+  static const char *webview_menu_roles[] = {
+      "", "cut", "copy", "paste", "selectall", "quit", "minimize", "about"};
+
+  // Reads the next item of the menu bar as webview_tray_next_item does. A
+  // radio item is checked with '2' or '3' rather than '0' or '1', and an
+  // item's accelerator follows its label after a tab: a hex digit of its
+  // webview_key_modifier flags then the name of its key, which is copied
+  // into key. The name of the item's role may follow after another tab.
+  // Returns the rest of the menu, or NULL at its end.
+  static const char *webview_menu_next_item(const char *menu, char *label,
+                                            size_t size, char *key,
+                                            size_t key_size, int *modifiers,
+                                            int *separator, int *enabled,
+                                            int *checked, int *radio,
+                                            int *role)
+  {
+    int depth = webview_menu_depth(menu);
+    if (depth < 0)
+    {
+      return NULL;
+    }
+    const char *line = menu + depth;
+    menu = webview_tray_next_item(line, label, size, separator, enabled,
+                                  checked);
+    *radio = !*separator && (line[1] == '2' || line[1] == '3');
+    if (*radio)
+    {
+      *checked = line[1] == '3';
+    }
+    key[0] = '\0';
+    *modifiers = 0;
+    *role = WEBVIEW_MENU_ROLE_NONE;
+    char *tab = strchr(label, '\t');
+    if (tab != NULL)
+    {
+      *tab = '\0';
+      char *accelerator = tab + 1;
+      tab = strchr(accelerator, '\t');
+      if (tab != NULL)
+      {
+        *tab = '\0';
+        for (int i = 1; i < (int)(sizeof(webview_menu_roles) /
+                                  sizeof(webview_menu_roles[0]));
+             i++)
+        {
+          if (strcmp(tab + 1, webview_menu_roles[i]) == 0)
+          {
+            *role = i;
+          }
+        }
+      }
+      char flags = accelerator[0];
+      if (flags >= '0' && flags <= '9')
+      {
+        *modifiers = flags - '0';
+      }
+      else if (flags >= 'a' && flags <= 'f')
+      {
+        *modifiers = flags - 'a' + 10;
+      }
+      if (flags != '\0')
+      {
+        snprintf(key, key_size, "%s", accelerator + 1);
+      }
+    }
+    return menu;
+  }
+
+  // Adds the pane to the end of its host's list of panes
+  static void webview_link_pane(struct webview *w)
+  {
+    struct webview **next = &w->host->next_pane;
+    while (*next != NULL)
+    {
+      next = &(*next)->next_pane;
+    }
+    *next = w;
+  }
+
+  // Removes the pane from its host's list. Returns 0 if it wasn't in it.
+  static int webview_unlink_pane(struct webview *w)
+  {
+    struct webview **next = &w->host->next_pane;
+    while (*next != NULL)
+    {
+      if (*next == w)
+      {
+        *next = w->next_pane;
+        w->next_pane = NULL;
+        return 1;
+      }
+      next = &(*next)->next_pane;
+    }
+    return 0;
+  }
+
 #if defined(WEBVIEW_GTK)
   static void external_message_received_cb(WebKitUserContentManager *m,
                                            WebKitJavascriptResult *r,
@@ -310,2053 +1040,8757 @@ struct webview_priv
     }
   }
 
-  static void webview_destroy_cb(GtkWidget *widget, gpointer arg)
+  // The webview makes the window fullscreen for the page's fullscreen
+  // element itself, so only the menu bar has to be hidden
+  static gboolean webview_enter_fullscreen_cb(WebKitWebView *webview,
+                                              gpointer arg)
   {
-    (void)widget;
+    (void)webview;
     struct webview *w = (struct webview *)arg;
-    webview_terminate(w);
+    if (w->priv.menubar != NULL)
+    {
+      gtk_widget_hide(w->priv.menubar);
+    }
+    return FALSE;
   }
 
-  static gboolean webview_context_menu_cb(WebKitWebView *webview,
-                                          GtkWidget *default_menu,
-                                          WebKitHitTestResult *hit_test_result,
-                                          gboolean triggered_with_keyboard,
-                                          gpointer userdata)
+  static gboolean webview_leave_fullscreen_cb(WebKitWebView *webview,
+                                              gpointer arg)
   {
     (void)webview;
-    (void)default_menu;
-    (void)hit_test_result;
-    (void)triggered_with_keyboard;
-    (void)userdata;
-    return TRUE;
-  }
-
-  WEBVIEW_API int webview_init(struct webview *w)
-  {
-    if (gtk_init_check(0, NULL) == FALSE)
+    struct webview *w = (struct webview *)arg;
+    if (w->priv.menubar != NULL)
     {
-      return -1;
+      gtk_widget_show(w->priv.menubar);
     }
+    return FALSE;
+  }
 
+  // The web process renders the page separately from the app, so the app
+  // can recover when it crashes or is killed for using too much memory
+#if WEBKIT_CHECK_VERSION(2, 20, 0)
+  static void webview_process_terminated_cb(WebKitWebView *webview,
+                                            WebKitWebProcessTerminationReason reason,
+                                            gpointer arg)
+  {
+    (void)webview;
+    struct webview *w = (struct webview *)arg;
     w->priv.ready = 0;
-    w->priv.should_exit = 0;
-    w->priv.queue = g_async_queue_new();
-    w->priv.window = gtk_window_new(GTK_WINDOW_TOPLEVEL);
-    
-    w->priv.min_width = -1;
-    w->priv.min_height = -1;
-    w->priv.max_width = -1;
-    w->priv.max_height = -1;
-    
-    gtk_window_set_title(GTK_WINDOW(w->priv.window), w->title);
-
-    if (w->resizable)
+    if (w->process_terminated_cb != NULL)
     {
-      gtk_window_set_default_size(GTK_WINDOW(w->priv.window), w->width,
-                                  w->height);
+      w->process_terminated_cb(w, reason == WEBKIT_WEB_PROCESS_EXCEEDED_MEMORY_LIMIT
+                                      ? "exceeded-memory-limit"
+                                      : "crashed");
     }
-    else
+  }
+#else
+  static void webview_process_terminated_cb(WebKitWebView *webview, gpointer arg)
+  {
+    (void)webview;
+    struct webview *w = (struct webview *)arg;
+    w->priv.ready = 0;
+    if (w->process_terminated_cb != NULL)
     {
-      gtk_widget_set_size_request(w->priv.window, w->width, w->height);
+      w->process_terminated_cb(w, "crashed");
     }
-    gtk_window_set_resizable(GTK_WINDOW(w->priv.window), !!w->resizable);
-    gtk_window_set_position(GTK_WINDOW(w->priv.window), GTK_WIN_POS_CENTER);
-
-    w->priv.scroller = gtk_scrolled_window_new(NULL, NULL);
-    gtk_container_add(GTK_CONTAINER(w->priv.window), w->priv.scroller);
-
-    WebKitUserContentManager *m = webkit_user_content_manager_new();
-    webkit_user_content_manager_register_script_message_handler(m, "external");
-    g_signal_connect(m, "script-message-received::external",
-                     G_CALLBACK(external_message_received_cb), w);
-
-    w->priv.webview = webkit_web_view_new_with_user_content_manager(m);
-    webkit_web_view_load_uri(WEBKIT_WEB_VIEW(w->priv.webview),
-                             webview_check_url(w->url));
-    g_signal_connect(G_OBJECT(w->priv.webview), "load-changed",
-                     G_CALLBACK(webview_load_changed_cb), w);
-    gtk_container_add(GTK_CONTAINER(w->priv.scroller), w->priv.webview);
+  }
+#endif
 
-    if (w->debug)
+  // Asks geolocation_cb whether the page may have the user's location.
+  // Pages are denied it without one. Other permissions are left to WebKit.
+  static gboolean webview_permission_request_cb(WebKitWebView *webview,
+                                                WebKitPermissionRequest *request,
+                                                gpointer arg)
+  {
+    struct webview *w = (struct webview *)arg;
+    if (!WEBKIT_IS_GEOLOCATION_PERMISSION_REQUEST(request))
     {
-      WebKitSettings *settings =
-          webkit_web_view_get_settings(WEBKIT_WEB_VIEW(w->priv.webview));
-      webkit_settings_set_enable_write_console_messages_to_stdout(settings, true);
-      webkit_settings_set_enable_developer_extras(settings, true);
-      webkit_settings_set_hardware_acceleration_policy(settings, WEBKIT_HARDWARE_ACCELERATION_POLICY_ALWAYS);
+      return FALSE;
     }
-    else
+    if (w->geolocation_cb == NULL)
     {
-      g_signal_connect(G_OBJECT(w->priv.webview), "context-menu",
-                       G_CALLBACK(webview_context_menu_cb), w);
+      webkit_permission_request_deny(request);
+      return TRUE;
     }
-
-    gtk_widget_show_all(w->priv.window);
-
-    webkit_web_view_run_javascript(
-        WEBKIT_WEB_VIEW(w->priv.webview),
-        "window.external={invoke:function(x){"
-        "window.webkit.messageHandlers.external.postMessage(x);}}",
-        NULL, NULL, NULL);
-
-    g_signal_connect(G_OBJECT(w->priv.window), "destroy",
-                     G_CALLBACK(webview_destroy_cb), w);
-    return 0;
+#if WEBKIT_CHECK_VERSION(2, 16, 0)
+    WebKitSecurityOrigin *security_origin =
+        webkit_security_origin_new_for_uri(webkit_web_view_get_uri(webview));
+    gchar *origin = webkit_security_origin_to_string(security_origin);
+    webkit_security_origin_unref(security_origin);
+#else
+    gchar *origin = g_strdup(webkit_web_view_get_uri(webview));
+#endif
+    g_object_ref(request);
+    w->geolocation_cb(w, origin != NULL ? origin : "", request);
+    g_free(origin);
+    return TRUE;
   }
 
-  WEBVIEW_API int webview_loop(struct webview *w, int blocking)
+  static gboolean webview_delete_cb(GtkWidget *widget, GdkEvent *event,
+                                    gpointer arg)
   {
-    gtk_main_iteration_do(blocking);
-    return w->priv.should_exit;
+    (void)widget;
+    (void)event;
+    struct webview *w = (struct webview *)arg;
+    if (w->kiosk)
+    {
+      return TRUE;
+    }
+    return w->closing_cb != NULL && w->closing_cb(w);
   }
 
-  WEBVIEW_API void webview_set_title(struct webview *w, const char *title)
+  // Notifications that are still shown can't be clicked once the window
+  // is closed
+  static void webview_notifications_free(struct webview *w)
   {
-    gtk_window_set_title(GTK_WINDOW(w->priv.window), title);
+    if (w->priv.notifications == NULL)
+    {
+      return;
+    }
+    g_dbus_connection_signal_unsubscribe(w->priv.notifications,
+                                         w->priv.notification_signals);
+    g_hash_table_destroy(w->priv.notification_ids);
+    g_object_unref(w->priv.notifications);
+    w->priv.notifications = NULL;
+    w->priv.notification_ids = NULL;
   }
 
-  WEBVIEW_API void webview_focus(struct webview *w)
+  static void webview_destroy_cb(GtkWidget *widget, gpointer arg)
   {
-    gtk_window_present(GTK_WINDOW(w->priv.window));
+    (void)widget;
+    struct webview *w = (struct webview *)arg;
+    g_signal_handlers_disconnect_by_data(gdk_display_get_default(), w);
+    webview_tray_remove(w);
+    webview_notifications_free(w);
+    webview_terminate(w);
+    if (w->closed_cb != NULL)
+    {
+      w->closed_cb(w);
+    }
   }
 
-  WEBVIEW_API void webview_minsize(struct webview *w, int width, int height) {
-  
-    w->priv.min_width = width;
-    w->priv.min_height = height;
-
-    GdkGeometry hints;
-    GdkWindowHints usedHints = (GdkWindowHints) GDK_HINT_MIN_SIZE;
-    
-    hints.min_width = w->priv.min_width;
-    hints.min_height = w->priv.min_height;
-    if (w->priv.max_width != -1) {
-      hints.max_width = w->priv.max_width;
-      hints.max_height = w->priv.max_height;
-      usedHints = (GdkWindowHints)(GDK_HINT_MIN_SIZE | GDK_HINT_MAX_SIZE);
+  static gboolean webview_configure_cb(GtkWidget *widget,
+                                       GdkEventConfigure *event, gpointer arg)
+  {
+    (void)widget;
+    struct webview *w = (struct webview *)arg;
+    GdkRectangle *last = &w->priv.geometry;
+    if (event->width != last->width || event->height != last->height)
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_RESIZE);
     }
-    
-    gtk_window_set_geometry_hints(GTK_WINDOW(w->priv.window), w->priv.window, &hints, usedHints);
-  }
-
-  WEBVIEW_API void webview_maxsize(struct webview *w, int width, int height) {
-    
-    w->priv.max_width = width;
-    w->priv.max_height = height;
-  
-    GdkGeometry hints;
-    GdkWindowHints usedHints = (GdkWindowHints) GDK_HINT_MAX_SIZE;
-    
-    if (w->priv.min_width != -1) {
-      hints.min_width = w->priv.min_width;
-      hints.min_height = w->priv.min_height;
-      usedHints = (GdkWindowHints)(GDK_HINT_MIN_SIZE | GDK_HINT_MAX_SIZE);
+    if (event->x != last->x || event->y != last->y)
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_MOVE);
     }
-    hints.max_width = w->priv.max_width;
-    hints.max_height = w->priv.max_height;
-    
-    gtk_window_set_geometry_hints(GTK_WINDOW(w->priv.window), w->priv.window, &hints, usedHints);
+    last->x = event->x;
+    last->y = event->y;
+    last->width = event->width;
+    last->height = event->height;
+    return FALSE;
   }
 
-  WEBVIEW_API void webview_set_fullscreen(struct webview *w, int fullscreen)
+  static gboolean webview_window_state_cb(GtkWidget *widget,
+                                          GdkEventWindowState *event,
+                                          gpointer arg)
   {
-    if (fullscreen)
+    (void)widget;
+    struct webview *w = (struct webview *)arg;
+    if (event->changed_mask & GDK_WINDOW_STATE_ICONIFIED)
     {
-      gtk_window_fullscreen(GTK_WINDOW(w->priv.window));
+      webview_window_event(w, (event->new_window_state & GDK_WINDOW_STATE_ICONIFIED)
+                                  ? WEBVIEW_WINDOW_EVENT_MINIMISE
+                                  : WEBVIEW_WINDOW_EVENT_RESTORE);
     }
-    else
+    else if (event->changed_mask & GDK_WINDOW_STATE_MAXIMIZED)
     {
-      gtk_window_unfullscreen(GTK_WINDOW(w->priv.window));
+      webview_window_event(w, (event->new_window_state & GDK_WINDOW_STATE_MAXIMIZED)
+                                  ? WEBVIEW_WINDOW_EVENT_MAXIMISE
+                                  : WEBVIEW_WINDOW_EVENT_RESTORE);
     }
+    return FALSE;
   }
 
-  WEBVIEW_API void webview_set_color(struct webview *w, uint8_t r, uint8_t g,
-                                     uint8_t b, uint8_t a)
-  {
-    GdkRGBA color = {r / 255.0, g / 255.0, b / 255.0, a / 255.0};
-    webkit_web_view_set_background_color(WEBKIT_WEB_VIEW(w->priv.webview),
-                                         &color);
-  }
-
-  WEBVIEW_API void webview_dialog(struct webview *w,
-                                  enum webview_dialog_type dlgtype, int flags,
-                                  const char *title, const char *arg,
-                                  char *result, size_t resultsz, char *filter)
+  static const struct webview_key_name webview_key_names[] = {
+      {GDK_KEY_Return, "Enter"},       {GDK_KEY_KP_Enter, "Enter"},
+      {GDK_KEY_Escape, "Escape"},      {GDK_KEY_Tab, "Tab"},
+      {GDK_KEY_ISO_Left_Tab, "Tab"},   {GDK_KEY_BackSpace, "Backspace"},
+      {GDK_KEY_Delete, "Delete"},      {GDK_KEY_Insert, "Insert"},
+      {GDK_KEY_Home, "Home"},          {GDK_KEY_End, "End"},
+      {GDK_KEY_Page_Up, "PageUp"},     {GDK_KEY_Page_Down, "PageDown"},
+      {GDK_KEY_Left, "ArrowLeft"},     {GDK_KEY_Right, "ArrowRight"},
+      {GDK_KEY_Up, "ArrowUp"},         {GDK_KEY_Down, "ArrowDown"},
+      {GDK_KEY_space, "Space"}};
+
+  // The window sees keys before the focused widget does
+  static gboolean webview_key_cb(GtkWidget *widget, GdkEventKey *event,
+                                 gpointer arg)
   {
-    GtkWidget *dlg;
-    if (result != NULL)
+    struct webview *w = (struct webview *)arg;
+    char key[16] = "";
+    guint keyval = event->keyval;
+    if (!w->capture_keys || event->is_modifier)
     {
-      result[0] = '\0';
+      return FALSE;
     }
-    if (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN ||
-        dlgtype == WEBVIEW_DIALOG_TYPE_SAVE)
+    gdk_keymap_translate_keyboard_state(
+        gdk_keymap_get_for_display(gtk_widget_get_display(widget)),
+        event->hardware_keycode, 0, event->group, &keyval, NULL, NULL, NULL);
+    for (size_t i = 0; i < sizeof(webview_key_names) / sizeof(webview_key_names[0]); i++)
     {
-      dlg = gtk_file_chooser_dialog_new(
-          title, GTK_WINDOW(w->priv.window),
-          (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN
-               ? (flags & WEBVIEW_DIALOG_FLAG_DIRECTORY
-                      ? GTK_FILE_CHOOSER_ACTION_SELECT_FOLDER
-                      : GTK_FILE_CHOOSER_ACTION_OPEN)
-               : GTK_FILE_CHOOSER_ACTION_SAVE),
-          "_Cancel", GTK_RESPONSE_CANCEL,
-          (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN ? "_Open" : "_Save"),
-          GTK_RESPONSE_ACCEPT, NULL);
-      if (filter[0] != '\0') {
-          GtkFileFilter *file_filter = gtk_file_filter_new();
-          gchar **filters  = g_strsplit(filter, ",", -1);
-          gint i;
-          for(i = 0; filters && filters[i]; i++) {
-              gtk_file_filter_add_pattern(file_filter, filters[i]);
-          }
-          gtk_file_filter_set_name(file_filter, filter);
-          gtk_file_chooser_add_filter(GTK_FILE_CHOOSER(dlg), file_filter);
-          g_strfreev(filters);
-      }
-      gtk_file_chooser_set_local_only(GTK_FILE_CHOOSER(dlg), FALSE);
-      gtk_file_chooser_set_select_multiple(GTK_FILE_CHOOSER(dlg), FALSE);
-      gtk_file_chooser_set_show_hidden(GTK_FILE_CHOOSER(dlg), TRUE);
-      gtk_file_chooser_set_do_overwrite_confirmation(GTK_FILE_CHOOSER(dlg), TRUE);
-      gtk_file_chooser_set_create_folders(GTK_FILE_CHOOSER(dlg), TRUE);
-      gint response = gtk_dialog_run(GTK_DIALOG(dlg));
-      if (response == GTK_RESPONSE_ACCEPT)
+      if (webview_key_names[i].code == keyval)
       {
-        gchar *filename = gtk_file_chooser_get_filename(GTK_FILE_CHOOSER(dlg));
-        g_strlcpy(result, filename, resultsz);
-        g_free(filename);
+        g_strlcpy(key, webview_key_names[i].name, sizeof(key));
       }
-      gtk_widget_destroy(dlg);
     }
-    else if (dlgtype == WEBVIEW_DIALOG_TYPE_ALERT)
+    if (key[0] == '\0' && keyval >= GDK_KEY_F1 && keyval <= GDK_KEY_F35)
     {
-      GtkMessageType type = GTK_MESSAGE_OTHER;
-      switch (flags & WEBVIEW_DIALOG_FLAG_ALERT_MASK)
-      {
-      case WEBVIEW_DIALOG_FLAG_INFO:
-        type = GTK_MESSAGE_INFO;
-        break;
-      case WEBVIEW_DIALOG_FLAG_WARNING:
-        type = GTK_MESSAGE_WARNING;
-        break;
-      case WEBVIEW_DIALOG_FLAG_ERROR:
-        type = GTK_MESSAGE_ERROR;
-        break;
-      }
-      dlg = gtk_message_dialog_new(GTK_WINDOW(w->priv.window), GTK_DIALOG_MODAL,
-                                   type, GTK_BUTTONS_OK, "%s", title);
-      gtk_message_dialog_format_secondary_text(GTK_MESSAGE_DIALOG(dlg), "%s",
-                                               arg);
-      gtk_dialog_run(GTK_DIALOG(dlg));
-      gtk_widget_destroy(dlg);
+      g_snprintf(key, sizeof(key), "F%u", keyval - GDK_KEY_F1 + 1);
+    }
+    gunichar c = gdk_keyval_to_unicode(keyval);
+    if (key[0] == '\0' && c != 0 && g_unichar_isprint(c))
+    {
+      key[g_unichar_to_utf8(g_unichar_tolower(c), key)] = '\0';
+    }
+    if (key[0] == '\0' && gdk_keyval_name(keyval) != NULL)
+    {
+      g_strlcpy(key, gdk_keyval_name(keyval), sizeof(key));
     }
+
+    int modifiers = 0;
+    modifiers |= (event->state & GDK_SHIFT_MASK) ? WEBVIEW_KEY_SHIFT : 0;
+    modifiers |= (event->state & GDK_CONTROL_MASK) ? WEBVIEW_KEY_CONTROL : 0;
+    modifiers |= (event->state & GDK_MOD1_MASK) ? WEBVIEW_KEY_ALT : 0;
+    modifiers |= (event->state & GDK_SUPER_MASK) ? WEBVIEW_KEY_SUPER : 0;
+    return webview_key_event(w, key, modifiers,
+                             event->type == GDK_KEY_PRESS);
   }
 
-  static void webview_eval_finished(GObject *object, GAsyncResult *result,
-                                    gpointer userdata)
+  // Buttons 8 and 9 are the mouse's back and forward buttons
+  static gboolean webview_button_cb(GtkWidget *widget, GdkEventButton *event,
+                                    gpointer arg)
   {
-    (void)object;
-    (void)result;
-    struct webview *w = (struct webview *)userdata;
-    w->priv.js_busy = 0;
+    (void)widget;
+    struct webview *w = (struct webview *)arg;
+    if (event->button == 8)
+    {
+      return webview_gesture_event(w, WEBVIEW_GESTURE_BACK, 0, 0);
+    }
+    if (event->button == 9)
+    {
+      return webview_gesture_event(w, WEBVIEW_GESTURE_FORWARD, 0, 0);
+    }
+    return FALSE;
   }
 
-  WEBVIEW_API int webview_eval(struct webview *w, const char *js)
+  static gboolean webview_scroll_cb(GtkWidget *widget, GdkEventScroll *event,
+                                    gpointer arg)
   {
-    while (w->priv.ready == 0)
+    (void)widget;
+    struct webview *w = (struct webview *)arg;
+    double dx = 0;
+    double dy = 0;
+    switch (event->direction)
     {
-      g_main_context_iteration(NULL, TRUE);
+    case GDK_SCROLL_LEFT:
+      dx = -1;
+      break;
+    case GDK_SCROLL_RIGHT:
+      dx = 1;
+      break;
+    case GDK_SCROLL_SMOOTH:
+      gdk_event_get_scroll_deltas((GdkEvent *)event, &dx, &dy);
+      break;
+    default:
+      break;
     }
-    w->priv.js_busy = 1;
-    webkit_web_view_run_javascript(WEBKIT_WEB_VIEW(w->priv.webview), js, NULL,
-                                   webview_eval_finished, w);
-    while (w->priv.js_busy)
+    if (dx == 0)
     {
-      g_main_context_iteration(NULL, TRUE);
+      return FALSE;
     }
-    return 0;
+    return webview_gesture_event(w, WEBVIEW_GESTURE_SCROLL, dx, 0);
   }
 
-  static gboolean webview_dispatch_wrapper(gpointer userdata)
+#if GTK_CHECK_VERSION(3, 18, 0)
+  // Swipes with three or more fingers are given as they move, so the
+  // distance is added up until they are lifted
+  static gboolean webview_swipe_cb(GtkWidget *widget, GdkEvent *event,
+                                   gpointer arg)
   {
-    struct webview *w = (struct webview *)userdata;
-    for (;;)
+    (void)widget;
+    struct webview *w = (struct webview *)arg;
+    if (event->type != GDK_TOUCHPAD_SWIPE)
     {
-      struct webview_dispatch_arg *arg =
-          (struct webview_dispatch_arg *)g_async_queue_try_pop(w->priv.queue);
-      if (arg == NULL)
+      return FALSE;
+    }
+    GdkEventTouchpadSwipe *swipe = &event->touchpad_swipe;
+    switch (swipe->phase)
+    {
+    case GDK_TOUCHPAD_GESTURE_PHASE_BEGIN:
+      w->priv.swipe_dx = 0;
+      w->priv.swipe_dy = 0;
+      break;
+    case GDK_TOUCHPAD_GESTURE_PHASE_UPDATE:
+      w->priv.swipe_dx += swipe->dx;
+      w->priv.swipe_dy += swipe->dy;
+      break;
+    case GDK_TOUCHPAD_GESTURE_PHASE_END:
+    {
+      double dx = w->priv.swipe_dx;
+      double dy = w->priv.swipe_dy;
+      if (dx == 0 && dy == 0)
       {
-        break;
+        return FALSE;
       }
-      (arg->fn)(w, arg->arg);
-      g_free(arg);
+      // Only the main direction is given
+      if ((dx < 0 ? -dx : dx) >= (dy < 0 ? -dy : dy))
+      {
+        return webview_gesture_event(w, WEBVIEW_GESTURE_SWIPE, dx > 0 ? 1 : -1,
+                                     0);
+      }
+      return webview_gesture_event(w, WEBVIEW_GESTURE_SWIPE, 0,
+                                   dy > 0 ? 1 : -1);
+    }
+    default:
+      break;
     }
     return FALSE;
   }
+#endif
 
-  WEBVIEW_API void webview_dispatch(struct webview *w, webview_dispatch_fn fn,
-                                    void *arg)
+  static gboolean webview_focus_cb(GtkWidget *widget, GdkEventFocus *event,
+                                   gpointer arg)
   {
-    struct webview_dispatch_arg *context =
-        (struct webview_dispatch_arg *)g_new(struct webview_dispatch_arg *, 1);
-    context->w = w;
-    context->arg = arg;
-    context->fn = fn;
-    g_async_queue_lock(w->priv.queue);
-    g_async_queue_push_unlocked(w->priv.queue, context);
-    if (g_async_queue_length_unlocked(w->priv.queue) == 1)
+    (void)widget;
+    struct webview *w = (struct webview *)arg;
+    // The urgency hint set by webview_request_attention is cleared once
+    // the user has switched to the window
+    if (event->in)
     {
-      gdk_threads_add_idle(webview_dispatch_wrapper, w);
+      gtk_window_set_urgency_hint(GTK_WINDOW(w->priv.window), FALSE);
     }
-    g_async_queue_unlock(w->priv.queue);
+    webview_window_event(w, event->in ? WEBVIEW_WINDOW_EVENT_FOCUS
+                                      : WEBVIEW_WINDOW_EVENT_BLUR);
+    return FALSE;
   }
 
-  WEBVIEW_API void webview_terminate(struct webview *w)
+  static void webview_monitors_cb(GdkDisplay *display, GdkMonitor *monitor,
+                                  gpointer arg)
   {
-    w->priv.should_exit = 1;
+    (void)display;
+    (void)monitor;
+    webview_window_event((struct webview *)arg, WEBVIEW_WINDOW_EVENT_DISPLAYS);
   }
 
-  WEBVIEW_API void webview_exit(struct webview *w) { (void)w; }
-  WEBVIEW_API void webview_print_log(const char *s)
+  static void webview_scale_cb(GObject *object, GParamSpec *pspec, gpointer arg)
   {
-    fprintf(stderr, "%s\n", s);
+    (void)object;
+    (void)pspec;
+    webview_window_event((struct webview *)arg, WEBVIEW_WINDOW_EVENT_SCALE);
   }
 
-#endif /* WEBVIEW_GTK */
-
-#if defined(WEBVIEW_WINAPI)
-
-#pragma comment(lib, "user32.lib")
-#pragma comment(lib, "ole32.lib")
-#pragma comment(lib, "oleaut32.lib")
-
-#define WM_WEBVIEW_DISPATCH (WM_APP + 1)
-
-  typedef struct
-  {
-    IOleInPlaceFrame frame;
-    HWND window;
-  } _IOleInPlaceFrameEx;
-
-  typedef struct
-  {
-    IOleInPlaceSite inplace;
-    _IOleInPlaceFrameEx frame;
-  } _IOleInPlaceSiteEx;
-
-  typedef struct
+  static gboolean webview_context_menu_cb(WebKitWebView *webview,
+                                          GtkWidget *default_menu,
+                                          WebKitHitTestResult *hit_test_result,
+                                          gboolean triggered_with_keyboard,
+                                          gpointer userdata)
   {
-    IDocHostUIHandler ui;
-  } _IDocHostUIHandlerEx;
+    (void)webview;
+    (void)default_menu;
+    (void)hit_test_result;
+    (void)triggered_with_keyboard;
+    (void)userdata;
+    return TRUE;
+  }
 
-  typedef struct
+  // Reports the files dropped on the webview and forgets them
+  static void webview_report_drop(struct webview *w)
   {
-    IOleClientSite client;
-    _IOleInPlaceSiteEx inplace;
-    _IDocHostUIHandlerEx ui;
-    IDispatch external;
-  } _IOleClientSiteEx;
-
-#ifdef __cplusplus
-#define iid_ref(x) &(x)
-#define iid_unref(x) *(x)
-#else
-#define iid_ref(x) (x)
-#define iid_unref(x) (x)
-#endif
+    w->priv.drop_pending = 0;
+    if (w->file_drop_cb != NULL)
+    {
+      w->file_drop_cb(w, w->priv.drop_files, w->priv.drop_x, w->priv.drop_y);
+    }
+    g_free(w->priv.drop_files);
+    w->priv.drop_files = NULL;
+  }
 
-  static inline WCHAR *webview_to_utf16(const char *s)
+  // Keeps the paths of the files in a drag. WebKit asks for the drag's data
+  // as it enters the webview, or when it's dropped in older versions.
+  static void webview_drag_data_received_cb(GtkWidget *widget,
+                                            GdkDragContext *context, gint x,
+                                            gint y, GtkSelectionData *data,
+                                            guint info, guint time,
+                                            gpointer arg)
   {
-    DWORD size = MultiByteToWideChar(CP_UTF8, 0, s, -1, 0, 0);
-    WCHAR *ws = (WCHAR *)GlobalAlloc(GMEM_FIXED, sizeof(WCHAR) * size);
-    if (ws == NULL)
+    struct webview *w = (struct webview *)arg;
+    g_free(w->priv.drop_files);
+    w->priv.drop_files = NULL;
+    gchar **uris = gtk_selection_data_get_uris(data);
+    if (uris != NULL)
     {
-      return NULL;
+      GString *files = g_string_new(NULL);
+      for (int i = 0; uris[i] != NULL; i++)
+      {
+        gchar *path = g_filename_from_uri(uris[i], NULL, NULL);
+        if (path == NULL)
+        {
+          continue;
+        }
+        if (files->len > 0)
+        {
+          g_string_append_c(files, '\n');
+        }
+        g_string_append(files, path);
+        g_free(path);
+      }
+      g_strfreev(uris);
+      w->priv.drop_files = g_string_free(files, files->len == 0);
+    }
+    if (w->priv.drop_pending)
+    {
+      w->priv.drop_pending = 0;
+      if (w->priv.drop_files != NULL)
+      {
+        webview_report_drop(w);
+      }
     }
-    MultiByteToWideChar(CP_UTF8, 0, s, -1, ws, size);
-    return ws;
   }
 
-  static inline char *webview_from_utf16(WCHAR *ws)
+  // Reports the files dropped on the webview, once their paths are known.
+  // Returns FALSE so that WebKit gives the drop to the page.
+  static gboolean webview_drag_drop_cb(GtkWidget *widget,
+                                       GdkDragContext *context, gint x, gint y,
+                                       guint time, gpointer arg)
   {
-    int n = WideCharToMultiByte(CP_UTF8, 0, ws, -1, NULL, 0, NULL, NULL);
-    char *s = (char *)GlobalAlloc(GMEM_FIXED, n);
-    if (s == NULL)
+    struct webview *w = (struct webview *)arg;
+    w->priv.drop_x = x;
+    w->priv.drop_y = y;
+    if (w->priv.drop_files != NULL)
     {
-      return NULL;
+      webview_report_drop(w);
     }
-    WideCharToMultiByte(CP_UTF8, 0, ws, -1, s, n, NULL, NULL);
-    return s;
+    else
+    {
+      w->priv.drop_pending = 1;
+    }
+    return FALSE;
   }
 
-  static int iid_eq(REFIID a, const IID *b)
+  // Creates the webview of the window, in a scroller under an overlay that
+  // holds the native views placed over it
+  static void webview_create_webview(struct webview *w)
   {
-    return memcmp((const void *)iid_ref(a), (const void *)b, sizeof(GUID)) == 0;
-  }
+    w->priv.overlay = gtk_overlay_new();
+    w->priv.scroller = gtk_scrolled_window_new(NULL, NULL);
+    gtk_container_add(GTK_CONTAINER(w->priv.overlay), w->priv.scroller);
 
-  static HRESULT STDMETHODCALLTYPE JS_QueryInterface(IDispatch FAR *This,
-                                                     REFIID riid,
-                                                     LPVOID FAR *ppvObj)
-  {
-    if (iid_eq(riid, &IID_IDispatch))
+    WebKitUserContentManager *m = webkit_user_content_manager_new();
+    webkit_user_content_manager_register_script_message_handler(m, "external");
+    g_signal_connect(m, "script-message-received::external",
+                     G_CALLBACK(external_message_received_cb), w);
+
+    w->priv.webview = webkit_web_view_new_with_user_content_manager(m);
+    webkit_web_view_load_uri(WEBKIT_WEB_VIEW(w->priv.webview),
+                             webview_check_url(w->url));
+    g_signal_connect(G_OBJECT(w->priv.webview), "load-changed",
+                     G_CALLBACK(webview_load_changed_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.webview), "enter-fullscreen",
+                     G_CALLBACK(webview_enter_fullscreen_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.webview), "leave-fullscreen",
+                     G_CALLBACK(webview_leave_fullscreen_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.webview), "button-press-event",
+                     G_CALLBACK(webview_button_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.webview), "scroll-event",
+                     G_CALLBACK(webview_scroll_cb), w);
+#if GTK_CHECK_VERSION(3, 18, 0)
+    gtk_widget_add_events(w->priv.webview, GDK_TOUCHPAD_GESTURE_MASK);
+    g_signal_connect(G_OBJECT(w->priv.webview), "event",
+                     G_CALLBACK(webview_swipe_cb), w);
+#endif
+#if WEBKIT_CHECK_VERSION(2, 20, 0)
+    g_signal_connect(G_OBJECT(w->priv.webview), "web-process-terminated",
+                     G_CALLBACK(webview_process_terminated_cb), w);
+#else
+    g_signal_connect(G_OBJECT(w->priv.webview), "web-process-crashed",
+                     G_CALLBACK(webview_process_terminated_cb), w);
+#endif
+    g_signal_connect(G_OBJECT(w->priv.webview), "permission-request",
+                     G_CALLBACK(webview_permission_request_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.webview), "drag-data-received",
+                     G_CALLBACK(webview_drag_data_received_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.webview), "drag-drop",
+                     G_CALLBACK(webview_drag_drop_cb), w);
+    gtk_container_add(GTK_CONTAINER(w->priv.scroller), w->priv.webview);
+
+    if (w->debug)
     {
-      *ppvObj = This;
-      return S_OK;
+      WebKitSettings *settings =
+          webkit_web_view_get_settings(WEBKIT_WEB_VIEW(w->priv.webview));
+      webkit_settings_set_enable_write_console_messages_to_stdout(settings, true);
+      webkit_settings_set_enable_developer_extras(settings, true);
+      webkit_settings_set_hardware_acceleration_policy(settings, WEBKIT_HARDWARE_ACCELERATION_POLICY_ALWAYS);
+    }
+    else
+    {
+      g_signal_connect(G_OBJECT(w->priv.webview), "context-menu",
+                       G_CALLBACK(webview_context_menu_cb), w);
     }
-    *ppvObj = 0;
-    return E_NOINTERFACE;
-  }
-  static ULONG STDMETHODCALLTYPE JS_AddRef(IDispatch FAR *This) { return 1; }
-  static ULONG STDMETHODCALLTYPE JS_Release(IDispatch FAR *This) { return 1; }
-  static HRESULT STDMETHODCALLTYPE JS_GetTypeInfoCount(IDispatch FAR *This,
-                                                       UINT *pctinfo)
-  {
-    return S_OK;
   }
-  static HRESULT STDMETHODCALLTYPE JS_GetTypeInfo(IDispatch FAR *This,
-                                                  UINT iTInfo, LCID lcid,
-                                                  ITypeInfo **ppTInfo)
+
+  static void webview_inject_external(struct webview *w)
   {
-    return S_OK;
+    webkit_web_view_run_javascript(
+        WEBKIT_WEB_VIEW(w->priv.webview),
+        "window.external={invoke:function(x){"
+        "window.webkit.messageHandlers.external.postMessage(x);}}",
+        NULL, NULL, NULL);
   }
-#define WEBVIEW_JS_INVOKE_ID 0x1000
-  static HRESULT STDMETHODCALLTYPE JS_GetIDsOfNames(IDispatch FAR *This,
-                                                    REFIID riid,
-                                                    LPOLESTR *rgszNames,
-                                                    UINT cNames, LCID lcid,
-                                                    DISPID *rgDispId)
+
+  WEBVIEW_API int webview_init(struct webview *w)
   {
-    if (cNames != 1)
+    if (gtk_init_check(0, NULL) == FALSE)
     {
-      return S_FALSE;
+      return -1;
     }
-    if (wcscmp(rgszNames[0], L"invoke") == 0)
+
+    w->priv.ready = 0;
+    w->priv.should_exit = 0;
+    w->priv.queue = g_async_queue_new();
+    w->priv.window = gtk_window_new(GTK_WINDOW_TOPLEVEL);
+    
+    w->priv.min_width = -1;
+    w->priv.min_height = -1;
+    w->priv.max_width = -1;
+    w->priv.max_height = -1;
+    
+    gtk_window_set_title(GTK_WINDOW(w->priv.window), w->title);
+
+    if (w->resizable)
     {
-      rgDispId[0] = WEBVIEW_JS_INVOKE_ID;
-      return S_OK;
+      gtk_window_set_default_size(GTK_WINDOW(w->priv.window), w->width,
+                                  w->height);
     }
-    return S_FALSE;
+    else
+    {
+      gtk_widget_set_size_request(w->priv.window, w->width, w->height);
+    }
+    gtk_window_set_resizable(GTK_WINDOW(w->priv.window), !!w->resizable);
+    gtk_window_set_position(GTK_WINDOW(w->priv.window), GTK_WIN_POS_CENTER);
+
+    // Transparency needs a compositing window manager
+    if (w->transparent)
+    {
+      GdkScreen *screen = gtk_widget_get_screen(w->priv.window);
+      GdkVisual *visual = gdk_screen_get_rgba_visual(screen);
+      if (visual != NULL && gdk_screen_is_composited(screen))
+      {
+        gtk_widget_set_visual(w->priv.window, visual);
+        gtk_widget_set_app_paintable(w->priv.window, TRUE);
+      }
+    }
+
+    webview_create_webview(w);
+    w->priv.box = gtk_box_new(GTK_ORIENTATION_VERTICAL, 0);
+    gtk_box_pack_end(GTK_BOX(w->priv.box), w->priv.overlay, TRUE, TRUE, 0);
+    gtk_container_add(GTK_CONTAINER(w->priv.window), w->priv.box);
+
+    if (w->hidden)
+    {
+      gtk_widget_show_all(w->priv.box);
+    }
+    else
+    {
+      gtk_widget_show_all(w->priv.window);
+    }
+    webview_inject_external(w);
+
+    g_signal_connect(G_OBJECT(w->priv.window), "delete-event",
+                     G_CALLBACK(webview_delete_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.window), "destroy",
+                     G_CALLBACK(webview_destroy_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.window), "configure-event",
+                     G_CALLBACK(webview_configure_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.window), "window-state-event",
+                     G_CALLBACK(webview_window_state_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.window), "focus-in-event",
+                     G_CALLBACK(webview_focus_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.window), "focus-out-event",
+                     G_CALLBACK(webview_focus_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.window), "key-press-event",
+                     G_CALLBACK(webview_key_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.window), "key-release-event",
+                     G_CALLBACK(webview_key_cb), w);
+    g_signal_connect(G_OBJECT(w->priv.window), "notify::scale-factor",
+                     G_CALLBACK(webview_scale_cb), w);
+    g_signal_connect(G_OBJECT(gdk_display_get_default()), "monitor-added",
+                     G_CALLBACK(webview_monitors_cb), w);
+    g_signal_connect(G_OBJECT(gdk_display_get_default()), "monitor-removed",
+                     G_CALLBACK(webview_monitors_cb), w);
+
+    if (w->kiosk)
+    {
+      gtk_window_set_keep_above(GTK_WINDOW(w->priv.window), TRUE);
+      gtk_window_fullscreen(GTK_WINDOW(w->priv.window));
+    }
+    return 0;
   }
 
-  static HRESULT STDMETHODCALLTYPE
-  JS_Invoke(IDispatch FAR *This, DISPID dispIdMember, REFIID riid, LCID lcid,
-            WORD wFlags, DISPPARAMS *pDispParams, VARIANT *pVarResult,
-            EXCEPINFO *pExcepInfo, UINT *puArgErr)
+  // Returns the content of the window, below its menu bar
+  static GtkWidget *webview_get_content(struct webview *w)
   {
-    size_t offset = (size_t) & ((_IOleClientSiteEx *)NULL)->external;
-    _IOleClientSiteEx *ex = (_IOleClientSiteEx *)((char *)(This)-offset);
-    struct webview *w = (struct webview *)GetWindowLongPtr(
-        ex->inplace.frame.window, GWLP_USERDATA);
-    if (pDispParams->cArgs == 1 && pDispParams->rgvarg[0].vt == VT_BSTR)
+    GtkWidget *content = NULL;
+    GList *children = gtk_container_get_children(GTK_CONTAINER(w->priv.box));
+    for (GList *child = children; child != NULL; child = child->next)
     {
-      BSTR bstr = pDispParams->rgvarg[0].bstrVal;
-      char *s = webview_from_utf16(bstr);
-      if (s != NULL)
+      if (child->data != w->priv.menubar)
       {
-        if (dispIdMember == WEBVIEW_JS_INVOKE_ID)
-        {
-          if (w->external_invoke_cb != NULL)
-          {
-            w->external_invoke_cb(w, s);
-          }
-        }
-        else
-        {
-          return S_FALSE;
-        }
-        GlobalFree(s);
+        content = GTK_WIDGET(child->data);
       }
     }
-    return S_OK;
+    g_list_free(children);
+    return content;
   }
 
-  static IDispatchVtbl ExternalDispatchTable = {
-      JS_QueryInterface, JS_AddRef, JS_Release, JS_GetTypeInfoCount,
-      JS_GetTypeInfo, JS_GetIDsOfNames, JS_Invoke};
-
-  static ULONG STDMETHODCALLTYPE Site_AddRef(IOleClientSite FAR *This)
+  static void webview_pane_destroy_cb(GtkWidget *widget, gpointer arg)
   {
-    return 1;
+    (void)widget;
+    struct webview *w = (struct webview *)arg;
+    webview_unlink_pane(w);
+    if (w->closed_cb != NULL)
+    {
+      w->closed_cb(w);
+    }
   }
-  static ULONG STDMETHODCALLTYPE Site_Release(IOleClientSite FAR *This)
+
+  // Docks the pane beside everything already in the host's window, so panes
+  // are added from left to right, or top to bottom when vertical. The pane
+  // is given its width, or its height when vertical.
+  WEBVIEW_API int webview_init_pane(struct webview *w)
   {
-    return 1;
+    struct webview *host = w->host;
+    w->priv.ready = 0;
+    w->priv.should_exit = 0;
+    w->priv.queue = g_async_queue_new();
+    w->priv.window = host->priv.window;
+    w->priv.box = host->priv.box;
+    w->priv.min_width = -1;
+    w->priv.min_height = -1;
+    w->priv.max_width = -1;
+    w->priv.max_height = -1;
+
+    webview_create_webview(w);
+
+    GtkWidget *child = webview_get_content(host);
+    int size = w->vertical ? gtk_widget_get_allocated_height(child)
+                           : gtk_widget_get_allocated_width(child);
+    int pane_size = w->vertical ? w->height : w->width;
+    GtkWidget *paned = gtk_paned_new(w->vertical ? GTK_ORIENTATION_VERTICAL
+                                                 : GTK_ORIENTATION_HORIZONTAL);
+    g_object_ref(child);
+    gtk_container_remove(GTK_CONTAINER(w->priv.box), child);
+    gtk_paned_pack1(GTK_PANED(paned), child, TRUE, FALSE);
+    gtk_paned_pack2(GTK_PANED(paned), w->priv.overlay, TRUE, FALSE);
+    g_object_unref(child);
+    gtk_box_pack_end(GTK_BOX(w->priv.box), paned, TRUE, TRUE, 0);
+    if (size > pane_size)
+    {
+      gtk_paned_set_position(GTK_PANED(paned), size - pane_size);
+    }
+
+    gtk_widget_show_all(paned);
+    webview_inject_external(w);
+
+    g_signal_connect(G_OBJECT(w->priv.overlay), "destroy",
+                     G_CALLBACK(webview_pane_destroy_cb), w);
+    webview_link_pane(w);
+    return 0;
   }
-  static HRESULT STDMETHODCALLTYPE Site_SaveObject(IOleClientSite FAR *This)
+
+  // Removes the pane from the window. The other side of its splitter takes
+  // the splitter's place.
+  static void webview_close_pane(struct webview *w)
   {
-    return E_NOTIMPL;
+    GtkWidget *paned = gtk_widget_get_parent(w->priv.overlay);
+    GtkWidget *sibling = gtk_paned_get_child1(GTK_PANED(paned));
+    if (sibling == w->priv.overlay)
+    {
+      sibling = gtk_paned_get_child2(GTK_PANED(paned));
+    }
+    GtkWidget *container = gtk_widget_get_parent(paned);
+    int first = GTK_IS_PANED(container) &&
+                gtk_paned_get_child1(GTK_PANED(container)) == paned;
+
+    g_object_ref(sibling);
+    gtk_container_remove(GTK_CONTAINER(paned), sibling);
+    gtk_widget_destroy(paned);
+    if (!GTK_IS_PANED(container))
+    {
+      gtk_box_pack_end(GTK_BOX(container), sibling, TRUE, TRUE, 0);
+    }
+    else if (first)
+    {
+      gtk_paned_pack1(GTK_PANED(container), sibling, TRUE, FALSE);
+    }
+    else
+    {
+      gtk_paned_pack2(GTK_PANED(container), sibling, TRUE, FALSE);
+    }
+    g_object_unref(sibling);
   }
-  static HRESULT STDMETHODCALLTYPE Site_GetMoniker(IOleClientSite FAR *This,
-                                                   DWORD dwAssign,
-                                                   DWORD dwWhichMoniker,
-                                                   IMoniker **ppmk)
+
+  WEBVIEW_API int webview_loop(struct webview *w, int blocking)
   {
-    return E_NOTIMPL;
+    gtk_main_iteration_do(blocking);
+    return w->priv.should_exit;
   }
-  static HRESULT STDMETHODCALLTYPE
-  Site_GetContainer(IOleClientSite FAR *This, LPOLECONTAINER FAR *ppContainer)
+
+  WEBVIEW_API void webview_set_title(struct webview *w, const char *title)
   {
-    *ppContainer = 0;
-    return E_NOINTERFACE;
+    gtk_window_set_title(GTK_WINDOW(w->priv.window), title);
   }
-  static HRESULT STDMETHODCALLTYPE Site_ShowObject(IOleClientSite FAR *This)
+
+  WEBVIEW_API int webview_set_icon(struct webview *w, const uint8_t *data,
+                                   int size)
   {
-    return NOERROR;
+    if (size == 0)
+    {
+      gtk_window_set_icon(GTK_WINDOW(w->priv.window), NULL);
+      return 0;
+    }
+    GdkPixbufLoader *loader = gdk_pixbuf_loader_new();
+    if (!gdk_pixbuf_loader_write(loader, data, size, NULL) ||
+        !gdk_pixbuf_loader_close(loader, NULL))
+    {
+      g_object_unref(loader);
+      return -1;
+    }
+    GdkPixbuf *icon = gdk_pixbuf_loader_get_pixbuf(loader);
+    gtk_window_set_icon(GTK_WINDOW(w->priv.window), icon);
+    g_object_unref(loader);
+    return icon != NULL ? 0 : -1;
   }
-  static HRESULT STDMETHODCALLTYPE Site_OnShowWindow(IOleClientSite FAR *This,
-                                                     BOOL fShow)
+
+  // Without an icon, the window's is shown
+  WEBVIEW_API void webview_show_about(struct webview *w)
   {
-    return E_NOTIMPL;
+    GtkWindow *window = GTK_WINDOW(w->priv.window);
+    GtkWidget *dlg = gtk_about_dialog_new();
+    GdkPixbufLoader *loader = NULL;
+    GdkPixbuf *logo = gtk_window_get_icon(window);
+    if (webview_about.iconsz > 0)
+    {
+      loader = gdk_pixbuf_loader_new();
+      if (gdk_pixbuf_loader_write(loader, webview_about.icon,
+                                  webview_about.iconsz, NULL) &&
+          gdk_pixbuf_loader_close(loader, NULL) &&
+          gdk_pixbuf_loader_get_pixbuf(loader) != NULL)
+      {
+        logo = gdk_pixbuf_loader_get_pixbuf(loader);
+      }
+    }
+    gtk_window_set_transient_for(GTK_WINDOW(dlg), window);
+    gtk_window_set_modal(GTK_WINDOW(dlg), TRUE);
+    gtk_about_dialog_set_program_name(
+        GTK_ABOUT_DIALOG(dlg), webview_about.name != NULL
+                                   ? webview_about.name
+                                   : gtk_window_get_title(window));
+    gtk_about_dialog_set_version(GTK_ABOUT_DIALOG(dlg), webview_about.version);
+    gtk_about_dialog_set_copyright(GTK_ABOUT_DIALOG(dlg),
+                                   webview_about.copyright);
+    if (logo != NULL)
+    {
+      gtk_about_dialog_set_logo(GTK_ABOUT_DIALOG(dlg), logo);
+    }
+    gtk_dialog_run(GTK_DIALOG(dlg));
+    gtk_widget_destroy(dlg);
+    if (loader != NULL)
+    {
+      g_object_unref(loader);
+    }
   }
-  static HRESULT STDMETHODCALLTYPE
-  Site_RequestNewObjectLayout(IOleClientSite FAR *This)
+
+  // WebKitGTK leaves touch and pen gestures to the page
+  WEBVIEW_API void webview_set_system_gestures(struct webview *w, int enabled)
   {
-    return E_NOTIMPL;
+    (void)w;
+    (void)enabled;
   }
-  static HRESULT STDMETHODCALLTYPE Site_QueryInterface(IOleClientSite FAR *This,
-                                                       REFIID riid,
-                                                       void **ppvObject)
+
+  // Gives the files being dragged out of the webview to where they're dropped
+  static void webview_drag_data_get_cb(GtkWidget *widget,
+                                       GdkDragContext *context,
+                                       GtkSelectionData *data, guint info,
+                                       guint time, gpointer arg)
   {
-    if (iid_eq(riid, &IID_IUnknown) || iid_eq(riid, &IID_IOleClientSite))
+    struct webview *w = (struct webview *)arg;
+    if (w->priv.drag_uris != NULL)
     {
-      *ppvObject = &((_IOleClientSiteEx *)This)->client;
+      gtk_selection_data_set_uris(data, w->priv.drag_uris);
     }
-    else if (iid_eq(riid, &IID_IOleInPlaceSite))
+  }
+
+  // The drag starts from the scroller, as WebKit handles the webview's own
+  WEBVIEW_API int webview_start_drag(struct webview *w, const char *files)
+  {
+    gchar **paths = g_strsplit(files, "\n", -1);
+    guint count = g_strv_length(paths);
+    g_strfreev(w->priv.drag_uris);
+    w->priv.drag_uris = g_new0(gchar *, count + 1);
+    guint n = 0;
+    for (guint i = 0; i < count; i++)
     {
-      *ppvObject = &((_IOleClientSiteEx *)This)->inplace;
+      gchar *uri = g_filename_to_uri(paths[i], NULL, NULL);
+      if (uri != NULL)
+      {
+        w->priv.drag_uris[n++] = uri;
+      }
     }
-    else if (iid_eq(riid, &IID_IDocHostUIHandler))
+    g_strfreev(paths);
+    if (n == 0)
     {
-      *ppvObject = &((_IOleClientSiteEx *)This)->ui;
+      return 0;
     }
-    else
+
+    if (!w->priv.drag_source)
     {
-      *ppvObject = 0;
-      return (E_NOINTERFACE);
+      g_signal_connect(G_OBJECT(w->priv.scroller), "drag-data-get",
+                       G_CALLBACK(webview_drag_data_get_cb), w);
+      w->priv.drag_source = 1;
     }
-    return S_OK;
+    GtkTargetList *targets = gtk_target_list_new(NULL, 0);
+    gtk_target_list_add_uri_targets(targets, 0);
+    GdkDragContext *context = gtk_drag_begin_with_coordinates(
+        w->priv.scroller, targets, GDK_ACTION_COPY, 1, NULL, -1, -1);
+    gtk_target_list_unref(targets);
+    return context != NULL;
   }
-  static HRESULT STDMETHODCALLTYPE InPlace_QueryInterface(
-      IOleInPlaceSite FAR *This, REFIID riid, LPVOID FAR *ppvObj)
+
+  WEBVIEW_API void webview_focus(struct webview *w)
   {
-    return (Site_QueryInterface(
-        (IOleClientSite *)((char *)This - sizeof(IOleClientSite)), riid, ppvObj));
+    gtk_window_present(GTK_WINDOW(w->priv.window));
   }
-  static ULONG STDMETHODCALLTYPE InPlace_AddRef(IOleInPlaceSite FAR *This)
+
+  WEBVIEW_API void webview_reload(struct webview *w)
   {
-    return 1;
+    w->priv.ready = 0;
+    webkit_web_view_reload(WEBKIT_WEB_VIEW(w->priv.webview));
   }
-  static ULONG STDMETHODCALLTYPE InPlace_Release(IOleInPlaceSite FAR *This)
+
+  WEBVIEW_API void webview_navigate(struct webview *w, const char *url)
   {
-    return 1;
+    w->url = url;
+    w->priv.ready = 0;
+    webkit_web_view_load_uri(WEBKIT_WEB_VIEW(w->priv.webview),
+                             webview_check_url(w->url));
   }
-  static HRESULT STDMETHODCALLTYPE InPlace_GetWindow(IOleInPlaceSite FAR *This,
-                                                     HWND FAR *lphwnd)
+
+  // Sets the minimum and maximum sizes and the aspect ratio that have been
+  // given, as each call replaces all of the hints
+  static void webview_set_geometry_hints(struct webview *w)
   {
-    *lphwnd = ((_IOleInPlaceSiteEx FAR *)This)->frame.window;
-    return S_OK;
+    GdkGeometry hints;
+    GdkWindowHints usedHints = (GdkWindowHints)0;
+
+    if (w->priv.min_width != -1) {
+      hints.min_width = w->priv.min_width;
+      hints.min_height = w->priv.min_height;
+      usedHints = (GdkWindowHints)(usedHints | GDK_HINT_MIN_SIZE);
+    }
+    if (w->priv.max_width != -1) {
+      hints.max_width = w->priv.max_width;
+      hints.max_height = w->priv.max_height;
+      usedHints = (GdkWindowHints)(usedHints | GDK_HINT_MAX_SIZE);
+    }
+    if (w->priv.aspect > 0) {
+      hints.min_aspect = w->priv.aspect;
+      hints.max_aspect = w->priv.aspect;
+      usedHints = (GdkWindowHints)(usedHints | GDK_HINT_ASPECT);
+    }
+
+    gtk_window_set_geometry_hints(GTK_WINDOW(w->priv.window), w->priv.window, &hints, usedHints);
   }
-  static HRESULT STDMETHODCALLTYPE
-  InPlace_ContextSensitiveHelp(IOleInPlaceSite FAR *This, BOOL fEnterMode)
-  {
-    return E_NOTIMPL;
+
+  WEBVIEW_API void webview_minsize(struct webview *w, int width, int height) {
+    w->priv.min_width = width;
+    w->priv.min_height = height;
+    webview_set_geometry_hints(w);
   }
-  static HRESULT STDMETHODCALLTYPE
-  InPlace_CanInPlaceActivate(IOleInPlaceSite FAR *This)
-  {
-    return S_OK;
+
+  WEBVIEW_API void webview_maxsize(struct webview *w, int width, int height) {
+    w->priv.max_width = width;
+    w->priv.max_height = height;
+    webview_set_geometry_hints(w);
   }
-  static HRESULT STDMETHODCALLTYPE
-  InPlace_OnInPlaceActivate(IOleInPlaceSite FAR *This)
+
+  WEBVIEW_API void webview_set_aspect_ratio(struct webview *w, int width,
+                                            int height)
   {
-    return S_OK;
+    w->priv.aspect = width > 0 && height > 0 ? (double)width / height : 0;
+    webview_set_geometry_hints(w);
   }
-  static HRESULT STDMETHODCALLTYPE
-  InPlace_OnUIActivate(IOleInPlaceSite FAR *This)
+
+  WEBVIEW_API void webview_set_fullscreen(struct webview *w, int fullscreen)
   {
-    return S_OK;
+    if (fullscreen)
+    {
+      gtk_window_fullscreen(GTK_WINDOW(w->priv.window));
+    }
+    else
+    {
+      gtk_window_unfullscreen(GTK_WINDOW(w->priv.window));
+    }
   }
-  static HRESULT STDMETHODCALLTYPE InPlace_GetWindowContext(
-      IOleInPlaceSite FAR *This, LPOLEINPLACEFRAME FAR *lplpFrame,
-      LPOLEINPLACEUIWINDOW FAR *lplpDoc, LPRECT lprcPosRect, LPRECT lprcClipRect,
-      LPOLEINPLACEFRAMEINFO lpFrameInfo)
+
+  WEBVIEW_API void webview_set_always_on_top(struct webview *w, int onTop)
   {
-    *lplpFrame = (LPOLEINPLACEFRAME) & ((_IOleInPlaceSiteEx *)This)->frame;
-    *lplpDoc = 0;
-    lpFrameInfo->fMDIApp = FALSE;
-    lpFrameInfo->hwndFrame = ((_IOleInPlaceFrameEx *)*lplpFrame)->window;
-    lpFrameInfo->haccel = 0;
-    lpFrameInfo->cAccelEntries = 0;
-    return S_OK;
+    gtk_window_set_keep_above(GTK_WINDOW(w->priv.window), onTop);
   }
-  static HRESULT STDMETHODCALLTYPE InPlace_Scroll(IOleInPlaceSite FAR *This,
-                                                  SIZE scrollExtent)
+
+  WEBVIEW_API void webview_set_borderless(struct webview *w, int borderless)
   {
-    return E_NOTIMPL;
+    gtk_window_set_decorated(GTK_WINDOW(w->priv.window), !borderless);
   }
-  static HRESULT STDMETHODCALLTYPE
-  InPlace_OnUIDeactivate(IOleInPlaceSite FAR *This, BOOL fUndoable)
+
+  WEBVIEW_API void webview_set_size(struct webview *w, int width, int height)
   {
-    return S_OK;
+    gtk_window_resize(GTK_WINDOW(w->priv.window), width, height);
   }
-  static HRESULT STDMETHODCALLTYPE
-  InPlace_OnInPlaceDeactivate(IOleInPlaceSite FAR *This)
-  {
-    return S_OK;
+
+  WEBVIEW_API void webview_snap_to_corner(struct webview *w, int corner, int margin)
+  {
+    GdkRectangle area;
+    int width, height, x, y;
+    GdkDisplay *display = gdk_display_get_default();
+    GdkMonitor *monitor = gdk_display_get_monitor_at_window(
+        display, gtk_widget_get_window(w->priv.window));
+    gdk_monitor_get_workarea(monitor, &area);
+    gtk_window_get_size(GTK_WINDOW(w->priv.window), &width, &height);
+    webview_corner_position(corner, margin, area.x, area.y, area.width,
+                            area.height, width, height, &x, &y);
+    gtk_window_move(GTK_WINDOW(w->priv.window), x, y);
   }
-  static HRESULT STDMETHODCALLTYPE
-  InPlace_DiscardUndoState(IOleInPlaceSite FAR *This)
+
+  WEBVIEW_API void webview_set_ignore_mouse_events(struct webview *w, int ignore)
   {
-    return E_NOTIMPL;
+    // An empty input shape passes all input to the windows below
+    if (ignore)
+    {
+      cairo_region_t *region = cairo_region_create();
+      gtk_widget_input_shape_combine_region(w->priv.window, region);
+      cairo_region_destroy(region);
+    }
+    else
+    {
+      gtk_widget_input_shape_combine_region(w->priv.window, NULL);
+    }
   }
-  static HRESULT STDMETHODCALLTYPE
-  InPlace_DeactivateAndUndo(IOleInPlaceSite FAR *This)
+
+  WEBVIEW_API void webview_cursor_position(struct webview *w, int *x, int *y)
   {
-    return E_NOTIMPL;
+    GdkSeat *seat = gdk_display_get_default_seat(gdk_display_get_default());
+    gdk_window_get_device_position(gtk_widget_get_window(w->priv.window),
+                                   gdk_seat_get_pointer(seat), x, y, NULL);
   }
-  static HRESULT STDMETHODCALLTYPE
-  InPlace_OnPosRectChange(IOleInPlaceSite FAR *This, LPCRECT lprcPosRect)
+
+  // Returns the given monitor or, if it doesn't exist, the one the window is on
+  static GdkMonitor *webview_monitor(struct webview *w, int display)
   {
-    IOleObject *browserObject;
-    IOleInPlaceObject *inplace;
-    browserObject = *((IOleObject **)((char *)This - sizeof(IOleObject *) -
-                                      sizeof(IOleClientSite)));
-    if (!browserObject->lpVtbl->QueryInterface(browserObject,
-                                               iid_unref(&IID_IOleInPlaceObject),
-                                               (void **)&inplace))
+    GdkDisplay *gdkDisplay = gdk_display_get_default();
+    if (display >= 0 && display < gdk_display_get_n_monitors(gdkDisplay))
     {
-      inplace->lpVtbl->SetObjectRects(inplace, lprcPosRect, lprcPosRect);
-      inplace->lpVtbl->Release(inplace);
+      return gdk_display_get_monitor(gdkDisplay, display);
     }
-    return S_OK;
+    return gdk_display_get_monitor_at_window(
+        gdkDisplay, gtk_widget_get_window(w->priv.window));
   }
-  static HRESULT STDMETHODCALLTYPE Frame_QueryInterface(
-      IOleInPlaceFrame FAR *This, REFIID riid, LPVOID FAR *ppvObj)
+
+  WEBVIEW_API int webview_display_count(struct webview *w)
   {
-    return E_NOTIMPL;
+    return gdk_display_get_n_monitors(gdk_display_get_default());
   }
-  static ULONG STDMETHODCALLTYPE Frame_AddRef(IOleInPlaceFrame FAR *This)
+
+  WEBVIEW_API void webview_display_workarea(struct webview *w, int display, int *x,
+                                            int *y, int *width, int *height)
   {
-    return 1;
+    GdkRectangle area;
+    gdk_monitor_get_workarea(webview_monitor(w, display), &area);
+    *x = area.x;
+    *y = area.y;
+    *width = area.width;
+    *height = area.height;
   }
-  static ULONG STDMETHODCALLTYPE Frame_Release(IOleInPlaceFrame FAR *This)
-  {
-    return 1;
+
+  WEBVIEW_API void webview_display_bounds(struct webview *w, int display, int *x,
+                                         int *y, int *width, int *height,
+                                         double *scale, int *primary)
+  {
+    GdkRectangle area;
+    GdkMonitor *monitor = webview_monitor(w, display);
+    gdk_monitor_get_geometry(monitor, &area);
+    *x = area.x;
+    *y = area.y;
+    *width = area.width;
+    *height = area.height;
+    *scale = gdk_monitor_get_scale_factor(monitor);
+    *primary = gdk_monitor_is_primary(monitor);
   }
-  static HRESULT STDMETHODCALLTYPE Frame_GetWindow(IOleInPlaceFrame FAR *This,
-                                                   HWND FAR *lphwnd)
+
+  WEBVIEW_API int webview_cursor_display(struct webview *w)
   {
-    *lphwnd = ((_IOleInPlaceFrameEx *)This)->window;
-    return S_OK;
+    int x, y, i;
+    GdkDisplay *gdkDisplay = gdk_display_get_default();
+    GdkSeat *seat = gdk_display_get_default_seat(gdkDisplay);
+    gdk_device_get_position(gdk_seat_get_pointer(seat), NULL, &x, &y);
+    GdkMonitor *monitor = gdk_display_get_monitor_at_point(gdkDisplay, x, y);
+    for (i = 0; i < gdk_display_get_n_monitors(gdkDisplay); i++)
+    {
+      if (gdk_display_get_monitor(gdkDisplay, i) == monitor)
+      {
+        return i;
+      }
+    }
+    return 0;
   }
-  static HRESULT STDMETHODCALLTYPE
-  Frame_ContextSensitiveHelp(IOleInPlaceFrame FAR *This, BOOL fEnterMode)
+
+  WEBVIEW_API double webview_get_scale(struct webview *w)
   {
-    return E_NOTIMPL;
+    return gtk_widget_get_scale_factor(w->priv.window);
   }
-  static HRESULT STDMETHODCALLTYPE Frame_GetBorder(IOleInPlaceFrame FAR *This,
-                                                   LPRECT lprectBorder)
+
+  WEBVIEW_API void webview_place(struct webview *w, int display, int edge, int margin)
   {
-    return E_NOTIMPL;
+    GdkRectangle area;
+    int width, height, x, y;
+    gdk_monitor_get_workarea(webview_monitor(w, display), &area);
+    gtk_window_get_size(GTK_WINDOW(w->priv.window), &width, &height);
+    webview_edge_position(edge, margin, area.x, area.y, area.width, area.height,
+                          width, height, &x, &y);
+    gtk_window_move(GTK_WINDOW(w->priv.window), x, y);
   }
-  static HRESULT STDMETHODCALLTYPE Frame_RequestBorderSpace(
-      IOleInPlaceFrame FAR *This, LPCBORDERWIDTHS pborderwidths)
+
+  WEBVIEW_API void webview_set_position(struct webview *w, int x, int y)
   {
-    return E_NOTIMPL;
+    gtk_window_move(GTK_WINDOW(w->priv.window), x, y);
   }
-  static HRESULT STDMETHODCALLTYPE Frame_SetBorderSpace(
-      IOleInPlaceFrame FAR *This, LPCBORDERWIDTHS pborderwidths)
+
+  WEBVIEW_API void webview_get_position(struct webview *w, int *x, int *y)
   {
-    return E_NOTIMPL;
+    gtk_window_get_position(GTK_WINDOW(w->priv.window), x, y);
   }
-  static HRESULT STDMETHODCALLTYPE Frame_SetActiveObject(
-      IOleInPlaceFrame FAR *This, IOleInPlaceActiveObject *pActiveObject,
-      LPCOLESTR pszObjName)
+
+  WEBVIEW_API void webview_get_size(struct webview *w, int *width, int *height)
   {
-    return S_OK;
+    gtk_window_get_size(GTK_WINDOW(w->priv.window), width, height);
   }
-  static HRESULT STDMETHODCALLTYPE
-  Frame_InsertMenus(IOleInPlaceFrame FAR *This, HMENU hmenuShared,
-                    LPOLEMENUGROUPWIDTHS lpMenuWidths)
+
+  WEBVIEW_API void webview_set_maximised(struct webview *w, int maximised)
   {
-    return E_NOTIMPL;
+    if (maximised)
+    {
+      gtk_window_maximize(GTK_WINDOW(w->priv.window));
+    }
+    else
+    {
+      gtk_window_unmaximize(GTK_WINDOW(w->priv.window));
+    }
   }
-  static HRESULT STDMETHODCALLTYPE Frame_SetMenu(IOleInPlaceFrame FAR *This,
-                                                 HMENU hmenuShared,
-                                                 HOLEMENU holemenu,
-                                                 HWND hwndActiveObject)
+
+  WEBVIEW_API int webview_is_maximised(struct webview *w)
   {
-    return S_OK;
+    return gtk_window_is_maximized(GTK_WINDOW(w->priv.window));
   }
-  static HRESULT STDMETHODCALLTYPE Frame_RemoveMenus(IOleInPlaceFrame FAR *This,
-                                                     HMENU hmenuShared)
+
+  WEBVIEW_API void webview_set_minimised(struct webview *w, int minimised)
   {
-    return E_NOTIMPL;
+    if (minimised)
+    {
+      gtk_window_iconify(GTK_WINDOW(w->priv.window));
+    }
+    else
+    {
+      gtk_window_deiconify(GTK_WINDOW(w->priv.window));
+    }
   }
-  static HRESULT STDMETHODCALLTYPE Frame_SetStatusText(IOleInPlaceFrame FAR *This,
-                                                       LPCOLESTR pszStatusText)
+
+  WEBVIEW_API void webview_set_visible(struct webview *w, int visible)
   {
-    return S_OK;
+    if (visible)
+    {
+      gtk_window_present(GTK_WINDOW(w->priv.window));
+    }
+    else
+    {
+      gtk_widget_hide(w->priv.window);
+    }
   }
-  static HRESULT STDMETHODCALLTYPE
-  Frame_EnableModeless(IOleInPlaceFrame FAR *This, BOOL fEnable)
+
+  // GTK draws its own decorations so there is no overlay to configure
+  WEBVIEW_API void webview_set_traffic_light_position(struct webview *w, int x, int y) {}
+
+  WEBVIEW_API void webview_titlebar_button_area(struct webview *w, int *x, int *y,
+                                                int *width, int *height)
   {
-    return S_OK;
+    *x = *y = *width = *height = 0;
   }
-  static HRESULT STDMETHODCALLTYPE
-  Frame_TranslateAccelerator(IOleInPlaceFrame FAR *This, LPMSG lpmsg, WORD wID)
+
+  WEBVIEW_API void webview_set_titlebar_color(struct webview *w, uint8_t r,
+                                              uint8_t g, uint8_t b, uint8_t sr,
+                                              uint8_t sg, uint8_t sb) {}
+
+  WEBVIEW_API int webview_save_dialog(struct webview *w, const char *title,
+                                      const char *directory,
+                                      const char *filename,
+                                      const char *formats, int flags,
+                                      char *result, size_t resultsz)
   {
-    return E_NOTIMPL;
+    GtkWidget *dlg;
+    GSList *filters = NULL;
+    int format = -1;
+    gint i, j;
+    result[0] = '\0';
+    dlg = gtk_file_chooser_dialog_new(
+        title, GTK_WINDOW(w->priv.window), GTK_FILE_CHOOSER_ACTION_SAVE,
+        "_Cancel", GTK_RESPONSE_CANCEL, "_Save", GTK_RESPONSE_ACCEPT, NULL);
+    gchar **lines = g_strsplit(formats, "\n", -1);
+    for (i = 0; lines && lines[i]; i++)
+    {
+      gchar **parts = g_strsplit(lines[i], "|", 2);
+      if (parts[0] != NULL && parts[1] != NULL)
+      {
+        GtkFileFilter *file_filter = gtk_file_filter_new();
+        gchar **patterns = g_strsplit(parts[1], ";", -1);
+        for (j = 0; patterns && patterns[j]; j++)
+        {
+          gtk_file_filter_add_pattern(file_filter, patterns[j]);
+        }
+        g_strfreev(patterns);
+        gtk_file_filter_set_name(file_filter, parts[0]);
+        gtk_file_chooser_add_filter(GTK_FILE_CHOOSER(dlg), file_filter);
+        filters = g_slist_append(filters, file_filter);
+      }
+      g_strfreev(parts);
+    }
+    g_strfreev(lines);
+    gtk_file_chooser_set_local_only(GTK_FILE_CHOOSER(dlg), FALSE);
+    gtk_file_chooser_set_show_hidden(GTK_FILE_CHOOSER(dlg), TRUE);
+    gtk_file_chooser_set_do_overwrite_confirmation(
+        GTK_FILE_CHOOSER(dlg), !!(flags & WEBVIEW_SAVE_FLAG_CONFIRM_OVERWRITE));
+    gtk_file_chooser_set_create_folders(
+        GTK_FILE_CHOOSER(dlg), !!(flags & WEBVIEW_SAVE_FLAG_CREATE_DIRECTORIES));
+    if (directory[0] != '\0')
+    {
+      gtk_file_chooser_set_current_folder(GTK_FILE_CHOOSER(dlg), directory);
+    }
+    if (filename[0] != '\0')
+    {
+      gtk_file_chooser_set_current_name(GTK_FILE_CHOOSER(dlg), filename);
+    }
+    if (gtk_dialog_run(GTK_DIALOG(dlg)) == GTK_RESPONSE_ACCEPT)
+    {
+      gchar *chosen = gtk_file_chooser_get_filename(GTK_FILE_CHOOSER(dlg));
+      g_strlcpy(result, chosen, resultsz);
+      g_free(chosen);
+      format = g_slist_index(filters,
+                             gtk_file_chooser_get_filter(GTK_FILE_CHOOSER(dlg)));
+    }
+    g_slist_free(filters);
+    gtk_widget_destroy(dlg);
+    return format;
   }
-  static HRESULT STDMETHODCALLTYPE UI_QueryInterface(IDocHostUIHandler FAR *This,
-                                                     REFIID riid,
-                                                     LPVOID FAR *ppvObj)
+
+  // Returns the selected files or directories separated by newlines
+  WEBVIEW_API void webview_open_dialog(struct webview *w, const char *title,
+                                       const char *directory, const char *formats,
+                                       int flags, char *result, size_t resultsz)
   {
-    return (Site_QueryInterface((IOleClientSite *)((char *)This -
-                                                   sizeof(IOleClientSite) -
-                                                   sizeof(_IOleInPlaceSiteEx)),
-                                riid, ppvObj));
+    GtkWidget *dlg;
+    gint i, j;
+    result[0] = '\0';
+    dlg = gtk_file_chooser_dialog_new(
+        title, GTK_WINDOW(w->priv.window),
+        flags & WEBVIEW_OPEN_FLAG_DIRECTORY ? GTK_FILE_CHOOSER_ACTION_SELECT_FOLDER
+                                            : GTK_FILE_CHOOSER_ACTION_OPEN,
+        "_Cancel", GTK_RESPONSE_CANCEL, "_Open", GTK_RESPONSE_ACCEPT, NULL);
+    gchar **lines = g_strsplit(formats, "\n", -1);
+    for (i = 0; lines && lines[i]; i++)
+    {
+      gchar **parts = g_strsplit(lines[i], "|", 2);
+      if (parts[0] != NULL && parts[1] != NULL)
+      {
+        GtkFileFilter *file_filter = gtk_file_filter_new();
+        gchar **patterns = g_strsplit(parts[1], ";", -1);
+        for (j = 0; patterns && patterns[j]; j++)
+        {
+          gtk_file_filter_add_pattern(file_filter, patterns[j]);
+        }
+        g_strfreev(patterns);
+        gtk_file_filter_set_name(file_filter, parts[0]);
+        gtk_file_chooser_add_filter(GTK_FILE_CHOOSER(dlg), file_filter);
+      }
+      g_strfreev(parts);
+    }
+    g_strfreev(lines);
+    if (directory[0] != '\0')
+    {
+      gtk_file_chooser_set_current_folder(GTK_FILE_CHOOSER(dlg), directory);
+    }
+    gtk_file_chooser_set_local_only(GTK_FILE_CHOOSER(dlg), TRUE);
+    gtk_file_chooser_set_show_hidden(GTK_FILE_CHOOSER(dlg), TRUE);
+    gtk_file_chooser_set_select_multiple(
+        GTK_FILE_CHOOSER(dlg), !!(flags & WEBVIEW_OPEN_FLAG_MULTIPLE));
+    gtk_file_chooser_set_create_folders(
+        GTK_FILE_CHOOSER(dlg), !!(flags & WEBVIEW_OPEN_FLAG_DIRECTORY));
+    if (gtk_dialog_run(GTK_DIALOG(dlg)) == GTK_RESPONSE_ACCEPT)
+    {
+      GSList *filenames = gtk_file_chooser_get_filenames(GTK_FILE_CHOOSER(dlg));
+      GSList *item;
+      for (item = filenames; item != NULL; item = item->next)
+      {
+        if (result[0] != '\0')
+        {
+          g_strlcat(result, "\n", resultsz);
+        }
+        g_strlcat(result, (gchar *)item->data, resultsz);
+      }
+      g_slist_free_full(filenames, g_free);
+    }
+    gtk_widget_destroy(dlg);
   }
-  static ULONG STDMETHODCALLTYPE UI_AddRef(IDocHostUIHandler FAR *This)
+
+  // Returns the selected directories separated by newlines
+  WEBVIEW_API void webview_select_directories(struct webview *w, const char *title,
+                                              char *result, size_t resultsz)
   {
-    return 1;
+    GtkWidget *dlg;
+    result[0] = '\0';
+    dlg = gtk_file_chooser_dialog_new(
+        title, GTK_WINDOW(w->priv.window), GTK_FILE_CHOOSER_ACTION_SELECT_FOLDER,
+        "_Cancel", GTK_RESPONSE_CANCEL, "_Open", GTK_RESPONSE_ACCEPT, NULL);
+    gtk_file_chooser_set_local_only(GTK_FILE_CHOOSER(dlg), TRUE);
+    gtk_file_chooser_set_select_multiple(GTK_FILE_CHOOSER(dlg), TRUE);
+    gtk_file_chooser_set_create_folders(GTK_FILE_CHOOSER(dlg), TRUE);
+    if (gtk_dialog_run(GTK_DIALOG(dlg)) == GTK_RESPONSE_ACCEPT)
+    {
+      GSList *filenames = gtk_file_chooser_get_filenames(GTK_FILE_CHOOSER(dlg));
+      GSList *item;
+      for (item = filenames; item != NULL; item = item->next)
+      {
+        if (result[0] != '\0')
+        {
+          g_strlcat(result, "\n", resultsz);
+        }
+        g_strlcat(result, (gchar *)item->data, resultsz);
+      }
+      g_slist_free_full(filenames, g_free);
+    }
+    gtk_widget_destroy(dlg);
   }
-  static ULONG STDMETHODCALLTYPE UI_Release(IDocHostUIHandler FAR *This)
+
+  WEBVIEW_API int webview_message_dialog(struct webview *w, int type,
+                                         const char *title, const char *message,
+                                         const char *buttons)
   {
-    return 1;
+    GtkMessageType gtype = GTK_MESSAGE_INFO;
+    gchar **labels = g_strsplit(buttons, "\n", -1);
+    gint count = 0;
+    gint response;
+    GtkWidget *dlg;
+    switch (type)
+    {
+    case WEBVIEW_MESSAGE_WARNING:
+      gtype = GTK_MESSAGE_WARNING;
+      break;
+    case WEBVIEW_MESSAGE_ERROR:
+      gtype = GTK_MESSAGE_ERROR;
+      break;
+    case WEBVIEW_MESSAGE_QUESTION:
+      gtype = GTK_MESSAGE_QUESTION;
+      break;
+    }
+    dlg = gtk_message_dialog_new(GTK_WINDOW(w->priv.window), GTK_DIALOG_MODAL,
+                                 gtype, GTK_BUTTONS_NONE, "%s", title);
+    gtk_message_dialog_format_secondary_text(GTK_MESSAGE_DIALOG(dlg), "%s",
+                                             message);
+    for (; labels[count] != NULL; count++)
+    {
+      if (labels[count][0] != '\0')
+      {
+        gtk_dialog_add_button(GTK_DIALOG(dlg), labels[count], count);
+      }
+    }
+    if (buttons[0] == '\0')
+    {
+      gtk_dialog_add_button(GTK_DIALOG(dlg), "_OK", 0);
+    }
+    g_strfreev(labels);
+    response = gtk_dialog_run(GTK_DIALOG(dlg));
+    gtk_widget_destroy(dlg);
+    return response >= 0 ? response : -1;
   }
-  static HRESULT STDMETHODCALLTYPE UI_ShowContextMenu(
-      IDocHostUIHandler FAR *This, DWORD dwID, POINT __RPC_FAR *ppt,
-      IUnknown __RPC_FAR *pcmdtReserved, IDispatch __RPC_FAR *pdispReserved)
-  {
-    return S_OK;
-  }
-  static HRESULT STDMETHODCALLTYPE
-  UI_GetHostInfo(IDocHostUIHandler FAR *This, DOCHOSTUIINFO __RPC_FAR *pInfo)
-  {
-    pInfo->cbSize = sizeof(DOCHOSTUIINFO);
-    pInfo->dwFlags = DOCHOSTUIFLAG_NO3DBORDER;
-    pInfo->dwDoubleClick = DOCHOSTUIDBLCLK_DEFAULT;
-    return S_OK;
+
+  // The pickers return 1 if the user made a choice and 0 if they cancelled
+  WEBVIEW_API int webview_color_picker(struct webview *w, const char *title,
+                                       uint8_t *r, uint8_t *g, uint8_t *b,
+                                       uint8_t *a)
+  {
+    int chosen = 0;
+    GdkRGBA color = {*r / 255.0, *g / 255.0, *b / 255.0, *a / 255.0};
+    GtkWidget *dlg = gtk_color_chooser_dialog_new(title, GTK_WINDOW(w->priv.window));
+    gtk_color_chooser_set_use_alpha(GTK_COLOR_CHOOSER(dlg), TRUE);
+    gtk_color_chooser_set_rgba(GTK_COLOR_CHOOSER(dlg), &color);
+    if (gtk_dialog_run(GTK_DIALOG(dlg)) == GTK_RESPONSE_OK)
+    {
+      gtk_color_chooser_get_rgba(GTK_COLOR_CHOOSER(dlg), &color);
+      *r = color.red * 255;
+      *g = color.green * 255;
+      *b = color.blue * 255;
+      *a = color.alpha * 255;
+      chosen = 1;
+    }
+    gtk_widget_destroy(dlg);
+    return chosen;
   }
-  static HRESULT STDMETHODCALLTYPE UI_ShowUI(
-      IDocHostUIHandler FAR *This, DWORD dwID,
-      IOleInPlaceActiveObject __RPC_FAR *pActiveObject,
-      IOleCommandTarget __RPC_FAR *pCommandTarget,
-      IOleInPlaceFrame __RPC_FAR *pFrame, IOleInPlaceUIWindow __RPC_FAR *pDoc)
+
+  WEBVIEW_API int webview_font_picker(struct webview *w, const char *title,
+                                      char *family, size_t familysz,
+                                      char *face, size_t facesz, int *size,
+                                      int *weight, int *italic)
   {
-    return S_OK;
+    int chosen = 0;
+    GtkWidget *dlg = gtk_font_chooser_dialog_new(title, GTK_WINDOW(w->priv.window));
+    face[0] = '\0';
+    if (family[0] != '\0')
+    {
+      PangoFontDescription *initial = pango_font_description_new();
+      pango_font_description_set_family(initial, family);
+      if (*size > 0)
+      {
+        pango_font_description_set_size(initial, *size * PANGO_SCALE);
+      }
+      pango_font_description_set_weight(initial, (PangoWeight)*weight);
+      pango_font_description_set_style(
+          initial, *italic ? PANGO_STYLE_ITALIC : PANGO_STYLE_NORMAL);
+      gtk_font_chooser_set_font_desc(GTK_FONT_CHOOSER(dlg), initial);
+      pango_font_description_free(initial);
+    }
+    if (gtk_dialog_run(GTK_DIALOG(dlg)) == GTK_RESPONSE_OK)
+    {
+      PangoFontDescription *font =
+          gtk_font_chooser_get_font_desc(GTK_FONT_CHOOSER(dlg));
+      PangoFontFace *fontface =
+          gtk_font_chooser_get_font_face(GTK_FONT_CHOOSER(dlg));
+      g_strlcpy(family, pango_font_description_get_family(font), familysz);
+      if (fontface != NULL)
+      {
+        g_strlcpy(face, pango_font_face_get_face_name(fontface), facesz);
+      }
+      *size = pango_font_description_get_size(font) / PANGO_SCALE;
+      *weight = pango_font_description_get_weight(font);
+      *italic = pango_font_description_get_style(font) != PANGO_STYLE_NORMAL;
+      pango_font_description_free(font);
+      chosen = 1;
+    }
+    gtk_widget_destroy(dlg);
+    return chosen;
   }
-  static HRESULT STDMETHODCALLTYPE UI_HideUI(IDocHostUIHandler FAR *This)
-  {
-    return S_OK;
+
+  // WebKitGTK opens its emoji chooser for the focused input on Ctrl+.
+  // so a key press is synthesised as the shortcut may be grabbed by the
+  // input method before it reaches the webview
+  WEBVIEW_API void webview_show_emoji_picker(struct webview *w)
+  {
+    GdkKeymapKey *keys;
+    gint n_keys;
+    GdkDisplay *display = gdk_display_get_default();
+    GdkEvent *event = gdk_event_new(GDK_KEY_PRESS);
+    event->key.window = g_object_ref(gtk_widget_get_window(w->priv.webview));
+    event->key.send_event = TRUE;
+    event->key.time = GDK_CURRENT_TIME;
+    event->key.state = GDK_CONTROL_MASK;
+    event->key.keyval = GDK_KEY_period;
+    if (gdk_keymap_get_entries_for_keyval(gdk_keymap_get_for_display(display),
+                                          GDK_KEY_period, &keys, &n_keys))
+    {
+      event->key.hardware_keycode = keys[0].keycode;
+      g_free(keys);
+    }
+    gdk_event_set_device(event, gdk_seat_get_keyboard(gdk_display_get_default_seat(display)));
+    gtk_main_do_event(event);
+    gdk_event_free(event);
   }
-  static HRESULT STDMETHODCALLTYPE UI_UpdateUI(IDocHostUIHandler FAR *This)
+
+  // Window managers mark urgent windows in the taskbar or dock. GTK has no
+  // levels of urgency, so critical requests are the same.
+  WEBVIEW_API void webview_request_attention(struct webview *w, int critical)
   {
-    return S_OK;
+    (void)critical;
+    if (!gtk_window_is_active(GTK_WINDOW(w->priv.window)))
+    {
+      gtk_window_set_urgency_hint(GTK_WINDOW(w->priv.window), TRUE);
+    }
   }
-  static HRESULT STDMETHODCALLTYPE UI_EnableModeless(IDocHostUIHandler FAR *This,
-                                                     BOOL fEnable)
+
+  // Fading the window needs a compositing window manager
+  WEBVIEW_API void webview_set_opacity(struct webview *w, double opacity)
   {
-    return S_OK;
+    gtk_widget_set_opacity(w->priv.window, opacity);
   }
-  static HRESULT STDMETHODCALLTYPE
-  UI_OnDocWindowActivate(IDocHostUIHandler FAR *This, BOOL fActivate)
+
+  // The Unity launcher API is a D-Bus signal naming the app's desktop file,
+  // which is also read by docks such as Plank, Dash to Dock and KDE's task
+  // manager
+  static void webview_launcher_update(GVariantBuilder *properties)
   {
-    return S_OK;
+    GDBusConnection *bus = g_bus_get_sync(G_BUS_TYPE_SESSION, NULL, NULL);
+    if (bus == NULL)
+    {
+      g_variant_builder_clear(properties);
+      return;
+    }
+    gchar *uri = g_strdup_printf("application://%s.desktop", g_get_prgname());
+    gchar *path = g_strdup_printf("/com/canonical/unity/launcherentry/%u",
+                                  g_str_hash(uri));
+    g_dbus_connection_emit_signal(bus, NULL, path,
+                                  "com.canonical.Unity.LauncherEntry",
+                                  "Update",
+                                  g_variant_new("(sa{sv})", uri, properties),
+                                  NULL);
+    g_free(path);
+    g_free(uri);
+    g_object_unref(bus);
   }
-  static HRESULT STDMETHODCALLTYPE
-  UI_OnFrameWindowActivate(IDocHostUIHandler FAR *This, BOOL fActivate)
+
+  WEBVIEW_API void webview_set_progress(struct webview *w, double progress)
   {
-    return S_OK;
+    (void)w;
+    GVariantBuilder properties;
+    g_variant_builder_init(&properties, G_VARIANT_TYPE("a{sv}"));
+    g_variant_builder_add(&properties, "{sv}", "progress",
+                          g_variant_new_double(progress < 0 ? 0 : progress));
+    g_variant_builder_add(&properties, "{sv}", "progress-visible",
+                          g_variant_new_boolean(progress >= 0));
+    webview_launcher_update(&properties);
   }
-  static HRESULT STDMETHODCALLTYPE
-  UI_ResizeBorder(IDocHostUIHandler FAR *This, LPCRECT prcBorder,
-                  IOleInPlaceUIWindow __RPC_FAR *pUIWindow, BOOL fRameWindow)
-  {
-    return S_OK;
+
+  WEBVIEW_API void webview_set_badge(struct webview *w, const char *badge)
+  {
+    (void)w;
+    char *end;
+    gint64 count = g_ascii_strtoll(badge, &end, 10);
+    GVariantBuilder properties;
+    g_variant_builder_init(&properties, G_VARIANT_TYPE("a{sv}"));
+    g_variant_builder_add(&properties, "{sv}", "count",
+                          g_variant_new_int64(count));
+    g_variant_builder_add(&properties, "{sv}", "count-visible",
+                          g_variant_new_boolean(*badge != '\0' && *end == '\0'));
+    webview_launcher_update(&properties);
   }
-  static HRESULT STDMETHODCALLTYPE
-  UI_TranslateAccelerator(IDocHostUIHandler FAR *This, LPMSG lpMsg,
-                          const GUID __RPC_FAR *pguidCmdGroup, DWORD nCmdID)
+
+  // Window materials are left to the desktop's theme
+  WEBVIEW_API int webview_set_material(struct webview *w, const char *material)
   {
-    return S_FALSE;
+    return webview_material_supported(w, material);
   }
-  static HRESULT STDMETHODCALLTYPE UI_GetOptionKeyPath(
-      IDocHostUIHandler FAR *This, LPOLESTR __RPC_FAR *pchKey, DWORD dw)
+
+  WEBVIEW_API int webview_material_supported(struct webview *w,
+                                             const char *material)
   {
-    return S_FALSE;
+    (void)w;
+    return strcmp(material, "none") == 0;
   }
-  static HRESULT STDMETHODCALLTYPE UI_GetDropTarget(
-      IDocHostUIHandler FAR *This, IDropTarget __RPC_FAR *pDropTarget,
-      IDropTarget __RPC_FAR *__RPC_FAR *ppDropTarget)
+
+  static void webview_tray_activate_cb(GtkStatusIcon *icon, gpointer arg)
   {
-    return S_FALSE;
+    (void)icon;
+    webview_tray_event((struct webview *)arg, -1);
   }
-  static HRESULT STDMETHODCALLTYPE UI_GetExternal(
-      IDocHostUIHandler FAR *This, IDispatch __RPC_FAR *__RPC_FAR *ppDispatch)
+
+  static void webview_tray_popup_cb(GtkStatusIcon *icon, guint button,
+                                    guint time, gpointer arg)
   {
-    *ppDispatch = (IDispatch *)(This + 1);
-    return S_OK;
+    struct webview *w = (struct webview *)arg;
+    if (w->priv.tray_menu != NULL)
+    {
+      gtk_menu_popup(GTK_MENU(w->priv.tray_menu), NULL, NULL,
+                     gtk_status_icon_position_menu, icon, button, time);
+    }
   }
-  static HRESULT STDMETHODCALLTYPE UI_TranslateUrl(
-      IDocHostUIHandler FAR *This, DWORD dwTranslate, OLECHAR __RPC_FAR *pchURLIn,
-      OLECHAR __RPC_FAR *__RPC_FAR *ppchURLOut)
+
+  static void webview_tray_item_cb(GtkMenuItem *item, gpointer arg)
   {
-    *ppchURLOut = 0;
-    return S_FALSE;
+    webview_tray_event((struct webview *)arg,
+                       GPOINTER_TO_INT(g_object_get_data(G_OBJECT(item),
+                                                         "webview-tray-item")));
   }
-  static HRESULT STDMETHODCALLTYPE
-  UI_FilterDataObject(IDocHostUIHandler FAR *This, IDataObject __RPC_FAR *pDO,
-                      IDataObject __RPC_FAR *__RPC_FAR *ppDORet)
+
+  // GtkStatusIcon is deprecated, but most desktops still show it
+  WEBVIEW_API int webview_tray_set_icon(struct webview *w, const uint8_t *data,
+                                        int size)
   {
-    *ppDORet = 0;
-    return S_FALSE;
+    GdkPixbufLoader *loader = gdk_pixbuf_loader_new();
+    if (!gdk_pixbuf_loader_write(loader, data, size, NULL) ||
+        !gdk_pixbuf_loader_close(loader, NULL) ||
+        gdk_pixbuf_loader_get_pixbuf(loader) == NULL)
+    {
+      g_object_unref(loader);
+      return -1;
+    }
+    if (w->priv.tray == NULL)
+    {
+      w->priv.tray = gtk_status_icon_new();
+      g_signal_connect(G_OBJECT(w->priv.tray), "activate",
+                       G_CALLBACK(webview_tray_activate_cb), w);
+      g_signal_connect(G_OBJECT(w->priv.tray), "popup-menu",
+                       G_CALLBACK(webview_tray_popup_cb), w);
+    }
+    gtk_status_icon_set_from_pixbuf(w->priv.tray,
+                                    gdk_pixbuf_loader_get_pixbuf(loader));
+    gtk_status_icon_set_visible(w->priv.tray, TRUE);
+    g_object_unref(loader);
+    return 0;
   }
 
-  static const TCHAR *classname = TEXT("WebView");
-  static const SAFEARRAYBOUND ArrayBound = {1, 0};
-
-  static IOleClientSiteVtbl MyIOleClientSiteTable = {
-      Site_QueryInterface, Site_AddRef, Site_Release,
-      Site_SaveObject, Site_GetMoniker, Site_GetContainer,
-      Site_ShowObject, Site_OnShowWindow, Site_RequestNewObjectLayout};
-  static IOleInPlaceSiteVtbl MyIOleInPlaceSiteTable = {
-      InPlace_QueryInterface,
-      InPlace_AddRef,
-      InPlace_Release,
-      InPlace_GetWindow,
-      InPlace_ContextSensitiveHelp,
-      InPlace_CanInPlaceActivate,
-      InPlace_OnInPlaceActivate,
-      InPlace_OnUIActivate,
-      InPlace_GetWindowContext,
-      InPlace_Scroll,
-      InPlace_OnUIDeactivate,
-      InPlace_OnInPlaceDeactivate,
-      InPlace_DiscardUndoState,
-      InPlace_DeactivateAndUndo,
-      InPlace_OnPosRectChange};
-
-  static IOleInPlaceFrameVtbl MyIOleInPlaceFrameTable = {
-      Frame_QueryInterface,
-      Frame_AddRef,
-      Frame_Release,
-      Frame_GetWindow,
-      Frame_ContextSensitiveHelp,
-      Frame_GetBorder,
-      Frame_RequestBorderSpace,
-      Frame_SetBorderSpace,
-      Frame_SetActiveObject,
-      Frame_InsertMenus,
-      Frame_SetMenu,
-      Frame_RemoveMenus,
-      Frame_SetStatusText,
-      Frame_EnableModeless,
-      Frame_TranslateAccelerator};
-
-  static IDocHostUIHandlerVtbl MyIDocHostUIHandlerTable = {
-      UI_QueryInterface,
-      UI_AddRef,
-      UI_Release,
-      UI_ShowContextMenu,
-      UI_GetHostInfo,
-      UI_ShowUI,
-      UI_HideUI,
-      UI_UpdateUI,
-      UI_EnableModeless,
-      UI_OnDocWindowActivate,
-      UI_OnFrameWindowActivate,
-      UI_ResizeBorder,
-      UI_TranslateAccelerator,
-      UI_GetOptionKeyPath,
-      UI_GetDropTarget,
-      UI_GetExternal,
-      UI_TranslateUrl,
-      UI_FilterDataObject};
-
-  static void UnEmbedBrowserObject(struct webview *w)
+  WEBVIEW_API void webview_tray_set_tooltip(struct webview *w,
+                                            const char *tooltip)
   {
-    if (w->priv.browser != NULL)
+    if (w->priv.tray != NULL)
     {
-      (*w->priv.browser)->lpVtbl->Close(*w->priv.browser, OLECLOSE_NOSAVE);
-      (*w->priv.browser)->lpVtbl->Release(*w->priv.browser);
-      GlobalFree(w->priv.browser);
-      w->priv.browser = NULL;
+      gtk_status_icon_set_tooltip_text(w->priv.tray, tooltip);
     }
   }
 
-  static int EmbedBrowserObject(struct webview *w)
+  // The menu is shown by right clicking the icon
+  WEBVIEW_API void webview_tray_set_menu(struct webview *w, const char *menu)
   {
-    RECT rect;
-    IWebBrowser2 *webBrowser2 = NULL;
-    LPCLASSFACTORY pClassFactory = NULL;
-    _IOleClientSiteEx *_iOleClientSiteEx = NULL;
-    IOleObject **browser = (IOleObject **)GlobalAlloc(
-        GMEM_FIXED, sizeof(IOleObject *) + sizeof(_IOleClientSiteEx));
-    if (browser == NULL)
+    char label[256];
+    int separator, enabled, checked;
+    int index = 0;
+    if (w->priv.tray_menu != NULL)
     {
-      goto error;
+      gtk_widget_destroy(w->priv.tray_menu);
+      w->priv.tray_menu = NULL;
     }
-    w->priv.browser = browser;
-
-    _iOleClientSiteEx = (_IOleClientSiteEx *)(browser + 1);
-    _iOleClientSiteEx->client.lpVtbl = &MyIOleClientSiteTable;
-    _iOleClientSiteEx->inplace.inplace.lpVtbl = &MyIOleInPlaceSiteTable;
-    _iOleClientSiteEx->inplace.frame.frame.lpVtbl = &MyIOleInPlaceFrameTable;
-    _iOleClientSiteEx->inplace.frame.window = w->priv.hwnd;
-    _iOleClientSiteEx->ui.ui.lpVtbl = &MyIDocHostUIHandlerTable;
-    _iOleClientSiteEx->external.lpVtbl = &ExternalDispatchTable;
-
-    if (CoGetClassObject(iid_unref(&CLSID_WebBrowser),
-                         CLSCTX_INPROC_SERVER | CLSCTX_INPROC_HANDLER, NULL,
-                         iid_unref(&IID_IClassFactory),
-                         (void **)&pClassFactory) != S_OK)
+    if (menu == NULL || *menu == '\0')
     {
-      goto error;
+      return;
     }
-
-    if (pClassFactory == NULL)
+    w->priv.tray_menu = gtk_menu_new();
+    while ((menu = webview_tray_next_item(menu, label, sizeof(label),
+                                          &separator, &enabled, &checked)) != NULL)
     {
-      goto error;
+      GtkWidget *item;
+      if (separator)
+      {
+        item = gtk_separator_menu_item_new();
+      }
+      else if (checked)
+      {
+        item = gtk_check_menu_item_new_with_label(label);
+        gtk_check_menu_item_set_active(GTK_CHECK_MENU_ITEM(item), TRUE);
+      }
+      else
+      {
+        item = gtk_menu_item_new_with_label(label);
+      }
+      gtk_widget_set_sensitive(item, separator || enabled);
+      g_object_set_data(G_OBJECT(item), "webview-tray-item",
+                        GINT_TO_POINTER(index++));
+      g_signal_connect(G_OBJECT(item), "activate",
+                       G_CALLBACK(webview_tray_item_cb), w);
+      gtk_menu_shell_append(GTK_MENU_SHELL(w->priv.tray_menu), item);
     }
+    gtk_widget_show_all(w->priv.tray_menu);
+  }
 
-    if (pClassFactory->lpVtbl->CreateInstance(pClassFactory, 0,
-                                              iid_unref(&IID_IOleObject),
-                                              (void **)browser) != S_OK)
+  WEBVIEW_API void webview_tray_remove(struct webview *w)
+  {
+    if (w->priv.tray_menu != NULL)
     {
-      goto error;
+      gtk_widget_destroy(w->priv.tray_menu);
+      w->priv.tray_menu = NULL;
     }
-    pClassFactory->lpVtbl->Release(pClassFactory);
-    if ((*browser)->lpVtbl->SetClientSite(
-            *browser, (IOleClientSite *)_iOleClientSiteEx) != S_OK)
+    if (w->priv.tray != NULL)
     {
-      goto error;
+      gtk_status_icon_set_visible(w->priv.tray, FALSE);
+      g_object_unref(w->priv.tray);
+      w->priv.tray = NULL;
     }
-    (*browser)->lpVtbl->SetHostNames(*browser, L"My Host Name", 0);
+  }
 
-    if (OleSetContainedObject((struct IUnknown *)(*browser), TRUE) != S_OK)
+  // The server sends ActionInvoked with the "default" action when a
+  // notification is clicked, then NotificationClosed once it's gone
+  static void webview_notification_signal_cb(
+      GDBusConnection *connection, const gchar *sender, const gchar *path,
+      const gchar *interface_name, const gchar *signal_name,
+      GVariant *parameters, gpointer arg)
+  {
+    struct webview *w = (struct webview *)arg;
+    guint32 number, reason;
+    const gchar *action;
+    if (g_strcmp0(signal_name, "ActionInvoked") == 0)
     {
-      goto error;
+      g_variant_get(parameters, "(u&s)", &number, &action);
+      const gchar *id = (const gchar *)g_hash_table_lookup(
+          w->priv.notification_ids, GUINT_TO_POINTER(number));
+      if (id != NULL && g_strcmp0(action, "default") == 0)
+      {
+        webview_notification_event(w, id);
+      }
     }
-    GetClientRect(w->priv.hwnd, &rect);
-    if ((*browser)->lpVtbl->DoVerb((*browser), OLEIVERB_SHOW, NULL,
-                                   (IOleClientSite *)_iOleClientSiteEx, -1,
-                                   w->priv.hwnd, &rect) != S_OK)
+    else if (g_strcmp0(signal_name, "NotificationClosed") == 0)
     {
-      goto error;
+      g_variant_get(parameters, "(uu)", &number, &reason);
+      g_hash_table_remove(w->priv.notification_ids, GUINT_TO_POINTER(number));
     }
-    if ((*browser)->lpVtbl->QueryInterface((*browser),
-                                           iid_unref(&IID_IWebBrowser2),
-                                           (void **)&webBrowser2) != S_OK)
+  }
+
+  // Notifications are sent to the freedesktop.org notification server over
+  // D-Bus, as libnotify does
+  WEBVIEW_API int webview_notify(struct webview *w, const char *id,
+                                 const char *title, const char *body,
+                                 int silent)
+  {
+    GError *error = NULL;
+    if (w->priv.notifications == NULL)
     {
-      goto error;
+      w->priv.notifications = g_bus_get_sync(G_BUS_TYPE_SESSION, NULL, &error);
+      if (w->priv.notifications == NULL)
+      {
+        g_warning("Unable to connect to the session bus: %s", error->message);
+        g_error_free(error);
+        return 0;
+      }
+      w->priv.notification_ids =
+          g_hash_table_new_full(g_direct_hash, g_direct_equal, NULL, g_free);
+      w->priv.notification_signals = g_dbus_connection_signal_subscribe(
+          w->priv.notifications, "org.freedesktop.Notifications",
+          "org.freedesktop.Notifications", NULL,
+          "/org/freedesktop/Notifications", NULL, G_DBUS_SIGNAL_FLAGS_NONE,
+          webview_notification_signal_cb, w, NULL);
     }
 
-    webBrowser2->lpVtbl->put_Left(webBrowser2, 0);
-    webBrowser2->lpVtbl->put_Top(webBrowser2, 0);
-    webBrowser2->lpVtbl->put_Width(webBrowser2, rect.right);
-    webBrowser2->lpVtbl->put_Height(webBrowser2, rect.bottom);
-    webBrowser2->lpVtbl->Release(webBrowser2);
-
-    return 0;
-  error:
-    UnEmbedBrowserObject(w);
-    if (pClassFactory != NULL)
+    GVariantBuilder actions, hints;
+    g_variant_builder_init(&actions, G_VARIANT_TYPE("as"));
+    g_variant_builder_add(&actions, "s", "default");
+    g_variant_builder_add(&actions, "s", "Open");
+    g_variant_builder_init(&hints, G_VARIANT_TYPE("a{sv}"));
+    if (silent)
     {
-      pClassFactory->lpVtbl->Release(pClassFactory);
+      g_variant_builder_add(&hints, "{sv}", "suppress-sound",
+                            g_variant_new_boolean(TRUE));
     }
-    if (browser != NULL)
+    const gchar *name = g_get_application_name();
+    GVariant *result = g_dbus_connection_call_sync(
+        w->priv.notifications, "org.freedesktop.Notifications",
+        "/org/freedesktop/Notifications", "org.freedesktop.Notifications",
+        "Notify",
+        g_variant_new("(susssasa{sv}i)", name != NULL ? name : "", 0, "",
+                      title, body, &actions, &hints, -1),
+        G_VARIANT_TYPE("(u)"), G_DBUS_CALL_FLAGS_NONE, -1, NULL, &error);
+    if (result == NULL)
     {
-      GlobalFree(browser);
+      g_warning("Unable to show the notification: %s", error->message);
+      g_error_free(error);
+      return 0;
     }
-    return -1;
+    guint32 number;
+    g_variant_get(result, "(u)", &number);
+    g_variant_unref(result);
+    g_hash_table_replace(w->priv.notification_ids, GUINT_TO_POINTER(number),
+                         g_strdup(id));
+    return 1;
   }
 
-#define WEBVIEW_DATA_URL_PREFIX "data:text/html,"
-  static int DisplayHTMLPage(struct webview *w)
+  static void webview_menu_item_cb(GtkMenuItem *item, gpointer arg)
   {
-    IWebBrowser2 *webBrowser2;
-    VARIANT myURL;
-    LPDISPATCH lpDispatch;
-    IHTMLDocument2 *htmlDoc2;
-    BSTR bstr;
-    IOleObject *browserObject;
-    SAFEARRAY *sfArray;
-    VARIANT *pVar;
-    browserObject = *w->priv.browser;
-    int isDataURL = 0;
-    const char *webview_url = webview_check_url(w->url);
-    if (!browserObject->lpVtbl->QueryInterface(
-            browserObject, iid_unref(&IID_IWebBrowser2), (void **)&webBrowser2))
+    // Items with a submenu are activated when it opens
+    if (gtk_menu_item_get_submenu(item) != NULL)
     {
-      LPCSTR webPageName;
-      isDataURL = (strncmp(webview_url, WEBVIEW_DATA_URL_PREFIX,
-                           strlen(WEBVIEW_DATA_URL_PREFIX)) == 0);
-      if (isDataURL)
+      return;
+    }
+    webview_menu_event((struct webview *)arg,
+                       GPOINTER_TO_INT(g_object_get_data(G_OBJECT(item),
+                                                         "webview-menu-item")));
+  }
+
+  static void webview_context_menu_item_cb(GtkMenuItem *item, gpointer arg)
+  {
+    if (gtk_menu_item_get_submenu(item) != NULL)
+    {
+      return;
+    }
+    webview_context_menu_event(
+        (struct webview *)arg,
+        GPOINTER_TO_INT(g_object_get_data(G_OBJECT(item), "webview-menu-item")));
+  }
+
+  // Items with a role do what they do here rather than being reported.
+  // Quitting closes the window as if the user had.
+  static void webview_menu_role_cb(GtkMenuItem *item, gpointer arg)
+  {
+    struct webview *w = (struct webview *)arg;
+    WebKitWebView *webview = WEBKIT_WEB_VIEW(w->priv.webview);
+    GtkWindow *window = GTK_WINDOW(w->priv.window);
+    switch (GPOINTER_TO_INT(g_object_get_data(G_OBJECT(item),
+                                              "webview-menu-role")))
+    {
+    case WEBVIEW_MENU_ROLE_CUT:
+      webkit_web_view_execute_editing_command(webview,
+                                              WEBKIT_EDITING_COMMAND_CUT);
+      break;
+    case WEBVIEW_MENU_ROLE_COPY:
+      webkit_web_view_execute_editing_command(webview,
+                                              WEBKIT_EDITING_COMMAND_COPY);
+      break;
+    case WEBVIEW_MENU_ROLE_PASTE:
+      webkit_web_view_execute_editing_command(webview,
+                                              WEBKIT_EDITING_COMMAND_PASTE);
+      break;
+    case WEBVIEW_MENU_ROLE_SELECT_ALL:
+      webkit_web_view_execute_editing_command(
+          webview, WEBKIT_EDITING_COMMAND_SELECT_ALL);
+      break;
+    case WEBVIEW_MENU_ROLE_QUIT:
+      gtk_window_close(window);
+      break;
+    case WEBVIEW_MENU_ROLE_MINIMIZE:
+      gtk_window_iconify(window);
+      break;
+    case WEBVIEW_MENU_ROLE_ABOUT:
+      webview_show_about(w);
+      break;
+    }
+  }
+
+  // Returns the keyval of a key named by webview_menu_next_item, or 0 if
+  // it's unknown
+  static guint webview_keyval(const char *key)
+  {
+    int number;
+    for (size_t i = 0;
+         i < sizeof(webview_key_names) / sizeof(webview_key_names[0]); i++)
+    {
+      if (strcmp(key, webview_key_names[i].name) == 0)
       {
-        webPageName = "about:blank";
+        return webview_key_names[i].code;
+      }
+    }
+    if (key[0] == 'F' && sscanf(key + 1, "%d", &number) == 1 && number >= 1 &&
+        number <= 24)
+    {
+      return GDK_KEY_F1 + number - 1;
+    }
+    if (key[0] != '\0' && key[1] == '\0')
+    {
+      return gdk_unicode_to_keyval((guint32)(unsigned char)key[0]);
+    }
+    return 0;
+  }
+
+  // Appends the items at the given depth to the menu shell, with their
+  // submenus, returning the rest of the menu. Items indented too far are
+  // appended as if they weren't. Choosing an item calls activate. The items'
+  // accelerators are added to accel_group, unless it's NULL.
+  static const char *webview_menu_append(struct webview *w, GtkWidget *shell,
+                                         const char *menu, int depth,
+                                         int *index, GCallback activate,
+                                         GtkAccelGroup *accel_group)
+  {
+    char label[256];
+    char key[16];
+    int modifiers, separator, enabled, checked, radio, role;
+    while (webview_menu_depth(menu) >= depth)
+    {
+      menu = webview_menu_next_item(menu, label, sizeof(label), key,
+                                    sizeof(key), &modifiers, &separator,
+                                    &enabled, &checked, &radio, &role);
+      GtkWidget *item;
+      if (separator)
+      {
+        item = gtk_separator_menu_item_new();
+      }
+      else if (checked || radio)
+      {
+        item = gtk_check_menu_item_new_with_label(label);
+        gtk_check_menu_item_set_draw_as_radio(GTK_CHECK_MENU_ITEM(item),
+                                              radio);
+        gtk_check_menu_item_set_active(GTK_CHECK_MENU_ITEM(item), checked);
       }
       else
       {
-        webPageName = (LPCSTR)webview_url;
+        item = gtk_menu_item_new_with_label(label);
       }
-      VariantInit(&myURL);
-      myURL.vt = VT_BSTR;
-      // #ifndef UNICODE
+      gtk_widget_set_sensitive(item, separator || enabled);
+      guint keyval = webview_keyval(key);
+      if (accel_group != NULL && keyval != 0)
       {
-        wchar_t *buffer = webview_to_utf16(webPageName);
-        if (buffer == NULL)
+        GdkModifierType mask = (GdkModifierType)0;
+        if (modifiers & WEBVIEW_KEY_SHIFT)
         {
-          goto badalloc;
+          mask = (GdkModifierType)(mask | GDK_SHIFT_MASK);
         }
-        myURL.bstrVal = SysAllocString(buffer);
-        GlobalFree(buffer);
-      }
-      // #else
-      //       myURL.bstrVal = SysAllocString(webPageName);
-      // #endif
-      if (!myURL.bstrVal)
-      {
-      badalloc:
-        webBrowser2->lpVtbl->Release(webBrowser2);
-        return (-6);
+        if (modifiers & WEBVIEW_KEY_CONTROL)
+        {
+          mask = (GdkModifierType)(mask | GDK_CONTROL_MASK);
+        }
+        if (modifiers & WEBVIEW_KEY_ALT)
+        {
+          mask = (GdkModifierType)(mask | GDK_MOD1_MASK);
+        }
+        if (modifiers & WEBVIEW_KEY_SUPER)
+        {
+          mask = (GdkModifierType)(mask | GDK_SUPER_MASK);
+        }
+        gtk_widget_add_accelerator(item, "activate", accel_group, keyval, mask,
+                                   GTK_ACCEL_VISIBLE);
       }
-      webBrowser2->lpVtbl->Navigate2(webBrowser2, &myURL, 0, 0, 0, 0);
-      VariantClear(&myURL);
-      if (!isDataURL)
+      g_object_set_data(G_OBJECT(item), "webview-menu-item",
+                        GINT_TO_POINTER((*index)++));
+      if (!separator && webview_menu_depth(menu) > depth)
       {
-        return 0;
+        GtkWidget *submenu = gtk_menu_new();
+        menu = webview_menu_append(w, submenu, menu, depth + 1, index,
+                                   activate, accel_group);
+        gtk_menu_item_set_submenu(GTK_MENU_ITEM(item), submenu);
       }
-
-      char *url = (char *)calloc(1, strlen(webview_url) + 1);
-      char *q = url;
-      for (const char *p = webview_url + strlen(WEBVIEW_DATA_URL_PREFIX); *q = *p;
-           p++, q++)
+      if (role != WEBVIEW_MENU_ROLE_NONE)
       {
-        if (*q == '%' && *(p + 1) && *(p + 2))
-        {
-          sscanf(p + 1, "%02x", q);
-          p = p + 2;
-        }
+        g_object_set_data(G_OBJECT(item), "webview-menu-role",
+                          GINT_TO_POINTER(role));
+        g_signal_connect(G_OBJECT(item), "activate",
+                         G_CALLBACK(webview_menu_role_cb), w);
       }
-
-      if (webBrowser2->lpVtbl->get_Document(webBrowser2, &lpDispatch) == S_OK)
+      else
       {
-        if (lpDispatch->lpVtbl->QueryInterface(lpDispatch,
-                                               iid_unref(&IID_IHTMLDocument2),
-                                               (void **)&htmlDoc2) == S_OK)
-        {
-          if ((sfArray = SafeArrayCreate(VT_VARIANT, 1,
-                                         (SAFEARRAYBOUND *)&ArrayBound)))
-          {
-            if (!SafeArrayAccessData(sfArray, (void **)&pVar))
-            {
-              pVar->vt = VT_BSTR;
-              // #ifndef UNICODE
-              {
-                wchar_t *buffer = webview_to_utf16(url);
-                if (buffer == NULL)
-                {
-                  goto release;
-                }
-                bstr = SysAllocString(buffer);
-                GlobalFree(buffer);
-              }
-              // #else
-              //               bstr = SysAllocString(url);
-              // #endif
-              if ((pVar->bstrVal = bstr))
-              {
-                htmlDoc2->lpVtbl->write(htmlDoc2, sfArray);
-                htmlDoc2->lpVtbl->close(htmlDoc2);
-              }
-            }
-            SafeArrayDestroy(sfArray);
-          }
-        release:
-          free(url);
-          htmlDoc2->lpVtbl->Release(htmlDoc2);
-        }
-        lpDispatch->lpVtbl->Release(lpDispatch);
+        g_signal_connect(G_OBJECT(item), "activate", activate, w);
       }
-      webBrowser2->lpVtbl->Release(webBrowser2);
-      return (0);
+      gtk_menu_shell_append(GTK_MENU_SHELL(shell), item);
     }
-    return (-5);
+    return menu;
   }
 
-  static LRESULT CALLBACK wndproc(HWND hwnd, UINT uMsg, WPARAM wParam,
-                                  LPARAM lParam)
+  WEBVIEW_API void webview_set_menu(struct webview *w, const char *menu)
   {
-    struct webview *w = (struct webview *)GetWindowLongPtr(hwnd, GWLP_USERDATA);
-    switch (uMsg)
+    int index = 0;
+    if (w->priv.menubar != NULL)
     {
-    case WM_CREATE:
-      w = (struct webview *)((CREATESTRUCT *)lParam)->lpCreateParams;
-      w->priv.hwnd = hwnd;
-
-      return EmbedBrowserObject(w);
-    case WM_GETMINMAXINFO:
+      gtk_widget_destroy(w->priv.menubar);
+      w->priv.menubar = NULL;
+    }
+    if (menu == NULL || *menu == '\0')
     {
-      if (w != NULL) {
-        // get pixel density 
-        HDC hDC = GetDC(NULL);
-        double DPIScaleX = GetDeviceCaps(hDC, 88)/96.0;
-        double DPIScaleY = GetDeviceCaps(hDC, 90)/96.0;
-        ReleaseDC(NULL, hDC);
-      	
-        RECT rcClient, rcWind;
-        POINT ptDiff;
-        GetClientRect(hwnd, &rcClient);
-        GetWindowRect(hwnd, &rcWind);
-
-        int widthExtra = (rcWind.right - rcWind.left) - rcClient.right;
-        int heightExtra = (rcWind.bottom - rcWind.top) - rcClient.bottom;
-
-        LPMINMAXINFO lpMMI = (LPMINMAXINFO)lParam;
-      
-        if (w->priv.min_width != -1) {
-          lpMMI->ptMinTrackSize.x = w->priv.min_width * DPIScaleX + widthExtra;
-          lpMMI->ptMinTrackSize.y = w->priv.min_height * DPIScaleY + heightExtra;
-        }
-        if (w->priv.max_width != -1) {
-          lpMMI->ptMaxTrackSize.x = w->priv.max_width * DPIScaleX + widthExtra;
-          lpMMI->ptMaxTrackSize.y = w->priv.max_height * DPIScaleY + heightExtra;
-        }
-      }
-      
-      return 0;
+      return;
     }
-    case WM_DESTROY:
-      UnEmbedBrowserObject(w);
-      PostQuitMessage(0);
-      return TRUE;
-    case WM_SIZE:
+    if (w->priv.accel_group == NULL)
     {
-      IWebBrowser2 *webBrowser2;
-      IOleObject *browser = *w->priv.browser;
-      if (browser->lpVtbl->QueryInterface(browser, iid_unref(&IID_IWebBrowser2),
-                                          (void **)&webBrowser2) == S_OK)
-      {
-        RECT rect;
-        GetClientRect(hwnd, &rect);
-        webBrowser2->lpVtbl->put_Width(webBrowser2, rect.right);
-        webBrowser2->lpVtbl->put_Height(webBrowser2, rect.bottom);
-      }
-      return TRUE;
+      w->priv.accel_group = gtk_accel_group_new();
+      gtk_window_add_accel_group(GTK_WINDOW(w->priv.window),
+                                 w->priv.accel_group);
     }
-    case WM_WEBVIEW_DISPATCH:
+    w->priv.menubar = gtk_menu_bar_new();
+    webview_menu_append(w, w->priv.menubar, menu, 0, &index,
+                        G_CALLBACK(webview_menu_item_cb), w->priv.accel_group);
+    gtk_box_pack_start(GTK_BOX(w->priv.box), w->priv.menubar, FALSE, FALSE, 0);
+    gtk_widget_show_all(w->priv.menubar);
+  }
+
+  // The menu is kept until the next one is shown, as its items are
+  // activated after it closes
+  WEBVIEW_API void webview_show_context_menu(struct webview *w,
+                                             const char *menu, int x, int y)
+  {
+    int index = 0;
+    if (w->priv.context_menu != NULL)
     {
-      webview_dispatch_fn f = (webview_dispatch_fn)wParam;
-      void *arg = (void *)lParam;
-      (*f)(w, arg);
-      return TRUE;
+      gtk_widget_destroy(w->priv.context_menu);
+      w->priv.context_menu = NULL;
     }
+    if (menu == NULL || *menu == '\0')
+    {
+      return;
     }
-    return DefWindowProc(hwnd, uMsg, wParam, lParam);
+    w->priv.context_menu = gtk_menu_new();
+    webview_menu_append(w, w->priv.context_menu, menu, 0, &index,
+                        G_CALLBACK(webview_context_menu_item_cb), NULL);
+    gtk_menu_attach_to_widget(GTK_MENU(w->priv.context_menu), w->priv.webview,
+                              NULL);
+    gtk_widget_show_all(w->priv.context_menu);
+#if GTK_CHECK_VERSION(3, 22, 0)
+    GdkRectangle rect = {x, y, 1, 1};
+    gtk_widget_translate_coordinates(w->priv.webview, w->priv.window, x, y,
+                                     &rect.x, &rect.y);
+    gtk_menu_popup_at_rect(GTK_MENU(w->priv.context_menu),
+                           gtk_widget_get_window(w->priv.window), &rect,
+                           GDK_GRAVITY_NORTH_WEST, GDK_GRAVITY_NORTH_WEST,
+                           NULL);
+#else
+    // Older versions show it at the pointer
+    (void)x;
+    (void)y;
+    gtk_menu_popup(GTK_MENU(w->priv.context_menu), NULL, NULL, NULL, NULL, 0,
+                   gtk_get_current_event_time());
+#endif
   }
 
-#define WEBVIEW_KEY_FEATURE_BROWSER_EMULATION \
-  "Software\\Microsoft\\Internet "            \
-  "Explorer\\Main\\FeatureControl\\FEATURE_BROWSER_EMULATION"
+  // The view is added to the overlay the first time it is placed
+  WEBVIEW_API void webview_place_overlay(struct webview *w, void *view, int x,
+                                         int y, int width, int height,
+                                         int visible)
+  {
+    GtkWidget *widget = GTK_WIDGET(view);
+    if (gtk_widget_get_parent(widget) != w->priv.overlay)
+    {
+      gtk_widget_set_halign(widget, GTK_ALIGN_START);
+      gtk_widget_set_valign(widget, GTK_ALIGN_START);
+      gtk_overlay_add_overlay(GTK_OVERLAY(w->priv.overlay), widget);
+    }
+    gtk_widget_set_margin_start(widget, x);
+    gtk_widget_set_margin_top(widget, y);
+    gtk_widget_set_size_request(widget, width, height);
+    gtk_widget_set_visible(widget, visible);
+  }
 
-  static int webview_fix_ie_compat_mode()
+  // Removing the view drops the reference the overlay took, which destroys
+  // it unless the caller holds another
+  WEBVIEW_API void webview_remove_overlay(struct webview *w, void *view)
   {
-    HKEY hKey;
-    DWORD ie_version = 11000;
-    TCHAR appname[MAX_PATH + 1];
-    TCHAR *p;
-    if (GetModuleFileName(NULL, appname, MAX_PATH + 1) == 0)
+    GtkWidget *widget = GTK_WIDGET(view);
+    if (gtk_widget_get_parent(widget) == w->priv.overlay)
     {
-      return -1;
+      gtk_container_remove(GTK_CONTAINER(w->priv.overlay), widget);
     }
-    for (p = &appname[strlen(appname) - 1]; p != appname && *p != '\\'; p--)
+  }
+
+  static void webview_automation_method_call(
+      GDBusConnection *connection, const gchar *sender, const gchar *path,
+      const gchar *interface_name, const gchar *method_name,
+      GVariant *parameters, GDBusMethodInvocation *invocation, gpointer arg)
+  {
+    struct webview *w = (struct webview *)arg;
+    const gchar *method = "";
+    const gchar *args = "";
+    if (g_strcmp0(method_name, "Call") == 0)
     {
+      g_variant_get(parameters, "(&s&s)", &method, &args);
     }
-    p++;
-    if (RegCreateKey(HKEY_CURRENT_USER, WEBVIEW_KEY_FEATURE_BROWSER_EMULATION,
-                     &hKey) != ERROR_SUCCESS)
+    // An empty method asks for the list of methods
+    w->automation_cb(w, method, args, invocation);
+  }
+
+  static const GDBusInterfaceVTable webview_automation_vtable = {
+      webview_automation_method_call, NULL, NULL};
+
+  static void webview_automation_bus_acquired(GDBusConnection *connection,
+                                              const gchar *name, gpointer arg)
+  {
+    GError *error = NULL;
+    gchar *xml = g_strdup_printf(
+        "<node><interface name='%s'>"
+        "<method name='Call'>"
+        "<arg type='s' name='method' direction='in'/>"
+        "<arg type='s' name='args' direction='in'/>"
+        "<arg type='s' name='result' direction='out'/>"
+        "</method>"
+        "<method name='List'>"
+        "<arg type='s' name='methods' direction='out'/>"
+        "</method>"
+        "</interface></node>",
+        name);
+    GDBusNodeInfo *info = g_dbus_node_info_new_for_xml(xml, &error);
+    g_free(xml);
+    if (info == NULL)
     {
-      return -1;
+      g_warning("Unable to create automation interface: %s", error->message);
+      g_error_free(error);
+      return;
     }
-    if (RegSetValueEx(hKey, p, 0, REG_DWORD, (BYTE *)&ie_version,
-                      sizeof(ie_version)) != ERROR_SUCCESS)
+
+    // com.example.App is served at /com/example/App
+    gchar *path = g_strconcat("/", name, NULL);
+    g_strdelimit(path, ".", '/');
+    g_strdelimit(path, "-", '_');
+    if (g_dbus_connection_register_object(connection, path, info->interfaces[0],
+                                          &webview_automation_vtable, arg,
+                                          NULL, &error) == 0)
     {
-      RegCloseKey(hKey);
-      return -1;
+      g_warning("Unable to register automation object: %s", error->message);
+      g_error_free(error);
     }
-    RegCloseKey(hKey);
-    return 0;
+    g_free(path);
+    g_dbus_node_info_unref(info);
   }
 
-  WEBVIEW_API int webview_init(struct webview *w)
+  WEBVIEW_API void webview_automation_start(struct webview *w, const char *name)
   {
-    w->priv.min_width = -1;
-    w->priv.max_width = -1;
-
-    WNDCLASSEX wc;
-    HINSTANCE hInstance;
-    DWORD style;
-    RECT clientRect;
-    RECT rect;
+    g_bus_own_name(G_BUS_TYPE_SESSION, name, G_BUS_NAME_OWNER_FLAGS_NONE,
+                   webview_automation_bus_acquired, NULL, NULL, w, NULL);
+  }
 
-    if (webview_fix_ie_compat_mode() < 0)
+  WEBVIEW_API void webview_automation_reply(struct webview *w, void *reply,
+                                            const char *result, const char *error)
+  {
+    GDBusMethodInvocation *invocation = (GDBusMethodInvocation *)reply;
+    if (error != NULL)
     {
-      return -1;
+      g_dbus_method_invocation_return_dbus_error(
+          invocation, "org.wails.Automation.Error", error);
+      return;
     }
+    g_dbus_method_invocation_return_value(invocation,
+                                          g_variant_new("(s)", result));
+  }
 
-    hInstance = GetModuleHandle(NULL);
-    if (hInstance == NULL)
+  WEBVIEW_API void webview_geolocation_reply(struct webview *w, void *request,
+                                             int allow)
+  {
+    WebKitPermissionRequest *permission = WEBKIT_PERMISSION_REQUEST(request);
+    if (allow)
     {
-      return -1;
+      webkit_permission_request_allow(permission);
     }
-    if (OleInitialize(NULL) != S_OK)
+    else
     {
-      return -1;
+      webkit_permission_request_deny(permission);
     }
+    g_object_unref(permission);
+  }
 
-    ZeroMemory(&wc, sizeof(WNDCLASSEX));
-    wc.cbSize = sizeof(WNDCLASSEX);
-    wc.hInstance = hInstance;
-    wc.lpfnWndProc = wndproc;
-    wc.lpszClassName = classname;
-    wc.hIcon = LoadIcon(hInstance, MAKEINTRESOURCE(100));
-    wc.hIconSm = LoadIcon(hInstance, MAKEINTRESOURCE(100));
-    RegisterClassEx(&wc);
+  WEBVIEW_API void webview_set_color(struct webview *w, uint8_t r, uint8_t g,
+                                     uint8_t b, uint8_t a)
+  {
+    GdkRGBA color = {r / 255.0, g / 255.0, b / 255.0, a / 255.0};
+    webkit_web_view_set_background_color(WEBKIT_WEB_VIEW(w->priv.webview),
+                                         &color);
+  }
 
-    style = WS_OVERLAPPEDWINDOW;
+  WEBVIEW_API void webview_dialog(struct webview *w,
+                                  enum webview_dialog_type dlgtype, int flags,
+                                  const char *title, const char *arg,
+                                  char *result, size_t resultsz, char *filter)
+  {
+    GtkWidget *dlg;
+    if (result != NULL)
+    {
+      result[0] = '\0';
+    }
+    if (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN ||
+        dlgtype == WEBVIEW_DIALOG_TYPE_SAVE)
+    {
+      dlg = gtk_file_chooser_dialog_new(
+          title, GTK_WINDOW(w->priv.window),
+          (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN
+               ? (flags & WEBVIEW_DIALOG_FLAG_DIRECTORY
+                      ? GTK_FILE_CHOOSER_ACTION_SELECT_FOLDER
+                      : GTK_FILE_CHOOSER_ACTION_OPEN)
+               : GTK_FILE_CHOOSER_ACTION_SAVE),
+          "_Cancel", GTK_RESPONSE_CANCEL,
+          (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN ? "_Open" : "_Save"),
+          GTK_RESPONSE_ACCEPT, NULL);
+      if (filter[0] != '\0') {
+          GtkFileFilter *file_filter = gtk_file_filter_new();
+          gchar **filters  = g_strsplit(filter, ",", -1);
+          gint i;
+          for(i = 0; filters && filters[i]; i++) {
+              gtk_file_filter_add_pattern(file_filter, filters[i]);
+          }
+          gtk_file_filter_set_name(file_filter, filter);
+          gtk_file_chooser_add_filter(GTK_FILE_CHOOSER(dlg), file_filter);
+          g_strfreev(filters);
+      }
+      gtk_file_chooser_set_local_only(GTK_FILE_CHOOSER(dlg), FALSE);
+      gtk_file_chooser_set_select_multiple(GTK_FILE_CHOOSER(dlg), FALSE);
+      gtk_file_chooser_set_show_hidden(GTK_FILE_CHOOSER(dlg), TRUE);
+      gtk_file_chooser_set_do_overwrite_confirmation(GTK_FILE_CHOOSER(dlg), TRUE);
+      gtk_file_chooser_set_create_folders(GTK_FILE_CHOOSER(dlg), TRUE);
+      gint response = gtk_dialog_run(GTK_DIALOG(dlg));
+      if (response == GTK_RESPONSE_ACCEPT)
+      {
+        gchar *filename = gtk_file_chooser_get_filename(GTK_FILE_CHOOSER(dlg));
+        g_strlcpy(result, filename, resultsz);
+        g_free(filename);
+      }
+      gtk_widget_destroy(dlg);
+    }
+    else if (dlgtype == WEBVIEW_DIALOG_TYPE_ALERT)
+    {
+      GtkMessageType type = GTK_MESSAGE_OTHER;
+      switch (flags & WEBVIEW_DIALOG_FLAG_ALERT_MASK)
+      {
+      case WEBVIEW_DIALOG_FLAG_INFO:
+        type = GTK_MESSAGE_INFO;
+        break;
+      case WEBVIEW_DIALOG_FLAG_WARNING:
+        type = GTK_MESSAGE_WARNING;
+        break;
+      case WEBVIEW_DIALOG_FLAG_ERROR:
+        type = GTK_MESSAGE_ERROR;
+        break;
+      }
+      dlg = gtk_message_dialog_new(GTK_WINDOW(w->priv.window), GTK_DIALOG_MODAL,
+                                   type, GTK_BUTTONS_OK, "%s", title);
+      gtk_message_dialog_format_secondary_text(GTK_MESSAGE_DIALOG(dlg), "%s",
+                                               arg);
+      gtk_dialog_run(GTK_DIALOG(dlg));
+      gtk_widget_destroy(dlg);
+    }
+  }
+
+  static void webview_eval_finished(GObject *object, GAsyncResult *result,
+                                    gpointer userdata)
+  {
+    (void)object;
+    (void)result;
+    struct webview *w = (struct webview *)userdata;
+    w->priv.js_busy = 0;
+  }
+
+  WEBVIEW_API int webview_eval(struct webview *w, const char *js)
+  {
+    while (w->priv.ready == 0)
+    {
+      g_main_context_iteration(NULL, TRUE);
+    }
+    w->priv.js_busy = 1;
+    webkit_web_view_run_javascript(WEBKIT_WEB_VIEW(w->priv.webview), js, NULL,
+                                   webview_eval_finished, w);
+    while (w->priv.js_busy)
+    {
+      g_main_context_iteration(NULL, TRUE);
+    }
+    return 0;
+  }
+
+  static gboolean webview_dispatch_wrapper(gpointer userdata)
+  {
+    struct webview *w = (struct webview *)userdata;
+    for (;;)
+    {
+      struct webview_dispatch_arg *arg =
+          (struct webview_dispatch_arg *)g_async_queue_try_pop(w->priv.queue);
+      if (arg == NULL)
+      {
+        break;
+      }
+      (arg->fn)(w, arg->arg);
+      g_free(arg);
+    }
+    return FALSE;
+  }
+
+  WEBVIEW_API void webview_dispatch(struct webview *w, webview_dispatch_fn fn,
+                                    void *arg)
+  {
+    struct webview_dispatch_arg *context =
+        (struct webview_dispatch_arg *)g_new(struct webview_dispatch_arg *, 1);
+    context->w = w;
+    context->arg = arg;
+    context->fn = fn;
+    g_async_queue_lock(w->priv.queue);
+    g_async_queue_push_unlocked(w->priv.queue, context);
+    if (g_async_queue_length_unlocked(w->priv.queue) == 1)
+    {
+      gdk_threads_add_idle(webview_dispatch_wrapper, w);
+    }
+    g_async_queue_unlock(w->priv.queue);
+  }
+
+  WEBVIEW_API void webview_terminate(struct webview *w)
+  {
+    w->priv.should_exit = 1;
+  }
+
+  WEBVIEW_API void webview_exit(struct webview *w) { (void)w; }
+
+  WEBVIEW_API void webview_close(struct webview *w)
+  {
+    if (w->host != NULL)
+    {
+      webview_close_pane(w);
+      return;
+    }
+    gtk_widget_destroy(w->priv.window);
+  }
+  WEBVIEW_API void webview_print_log(const char *s)
+  {
+    fprintf(stderr, "%s\n", s);
+  }
+
+#endif /* WEBVIEW_GTK */
+
+#if defined(WEBVIEW_WINAPI)
+
+#pragma comment(lib, "user32.lib")
+#pragma comment(lib, "ole32.lib")
+#pragma comment(lib, "oleaut32.lib")
+#pragma comment(lib, "shell32.lib")
+
+#define WM_WEBVIEW_DISPATCH (WM_APP + 1)
+#define WM_WEBVIEW_TRAY (WM_APP + 2)
+#ifndef NIN_BALLOONHIDE
+#define NIN_BALLOONHIDE (WM_USER + 3)
+#define NIN_BALLOONTIMEOUT (WM_USER + 4)
+#define NIN_BALLOONUSERCLICK (WM_USER + 5)
+#endif
+#ifndef NIIF_NOSOUND
+#define NIIF_NOSOUND 0x00000010
+#endif
+// The command id of the first item of the menu bar
+#define WEBVIEW_MENU_ID 0x1000
+
+  typedef struct
+  {
+    IOleInPlaceFrame frame;
+    HWND window;
+  } _IOleInPlaceFrameEx;
+
+  typedef struct
+  {
+    IOleInPlaceSite inplace;
+    _IOleInPlaceFrameEx frame;
+  } _IOleInPlaceSiteEx;
+
+  typedef struct
+  {
+    IDocHostUIHandler ui;
+  } _IDocHostUIHandlerEx;
+
+  typedef struct
+  {
+    IOleClientSite client;
+    _IOleInPlaceSiteEx inplace;
+    _IDocHostUIHandlerEx ui;
+    IDispatch external;
+  } _IOleClientSiteEx;
+
+#ifdef __cplusplus
+#define iid_ref(x) &(x)
+#define iid_unref(x) *(x)
+#else
+#define iid_ref(x) (x)
+#define iid_unref(x) (x)
+#endif
+
+  static inline WCHAR *webview_to_utf16(const char *s)
+  {
+    DWORD size = MultiByteToWideChar(CP_UTF8, 0, s, -1, 0, 0);
+    WCHAR *ws = (WCHAR *)GlobalAlloc(GMEM_FIXED, sizeof(WCHAR) * size);
+    if (ws == NULL)
+    {
+      return NULL;
+    }
+    MultiByteToWideChar(CP_UTF8, 0, s, -1, ws, size);
+    return ws;
+  }
+
+  static inline char *webview_from_utf16(WCHAR *ws)
+  {
+    int n = WideCharToMultiByte(CP_UTF8, 0, ws, -1, NULL, 0, NULL, NULL);
+    char *s = (char *)GlobalAlloc(GMEM_FIXED, n);
+    if (s == NULL)
+    {
+      return NULL;
+    }
+    WideCharToMultiByte(CP_UTF8, 0, ws, -1, s, n, NULL, NULL);
+    return s;
+  }
+
+  static int iid_eq(REFIID a, const IID *b)
+  {
+    return memcmp((const void *)iid_ref(a), (const void *)b, sizeof(GUID)) == 0;
+  }
+
+  static HRESULT STDMETHODCALLTYPE JS_QueryInterface(IDispatch FAR *This,
+                                                     REFIID riid,
+                                                     LPVOID FAR *ppvObj)
+  {
+    if (iid_eq(riid, &IID_IDispatch))
+    {
+      *ppvObj = This;
+      return S_OK;
+    }
+    *ppvObj = 0;
+    return E_NOINTERFACE;
+  }
+  static ULONG STDMETHODCALLTYPE JS_AddRef(IDispatch FAR *This) { return 1; }
+  static ULONG STDMETHODCALLTYPE JS_Release(IDispatch FAR *This) { return 1; }
+  static HRESULT STDMETHODCALLTYPE JS_GetTypeInfoCount(IDispatch FAR *This,
+                                                       UINT *pctinfo)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE JS_GetTypeInfo(IDispatch FAR *This,
+                                                  UINT iTInfo, LCID lcid,
+                                                  ITypeInfo **ppTInfo)
+  {
+    return S_OK;
+  }
+#define WEBVIEW_JS_INVOKE_ID 0x1000
+  static HRESULT STDMETHODCALLTYPE JS_GetIDsOfNames(IDispatch FAR *This,
+                                                    REFIID riid,
+                                                    LPOLESTR *rgszNames,
+                                                    UINT cNames, LCID lcid,
+                                                    DISPID *rgDispId)
+  {
+    if (cNames != 1)
+    {
+      return S_FALSE;
+    }
+    if (wcscmp(rgszNames[0], L"invoke") == 0)
+    {
+      rgDispId[0] = WEBVIEW_JS_INVOKE_ID;
+      return S_OK;
+    }
+    return S_FALSE;
+  }
+
+  static HRESULT STDMETHODCALLTYPE
+  JS_Invoke(IDispatch FAR *This, DISPID dispIdMember, REFIID riid, LCID lcid,
+            WORD wFlags, DISPPARAMS *pDispParams, VARIANT *pVarResult,
+            EXCEPINFO *pExcepInfo, UINT *puArgErr)
+  {
+    size_t offset = (size_t) & ((_IOleClientSiteEx *)NULL)->external;
+    _IOleClientSiteEx *ex = (_IOleClientSiteEx *)((char *)(This)-offset);
+    struct webview *w = (struct webview *)GetWindowLongPtr(
+        ex->inplace.frame.window, GWLP_USERDATA);
+    if (pDispParams->cArgs == 1 && pDispParams->rgvarg[0].vt == VT_BSTR)
+    {
+      BSTR bstr = pDispParams->rgvarg[0].bstrVal;
+      char *s = webview_from_utf16(bstr);
+      if (s != NULL)
+      {
+        if (dispIdMember == WEBVIEW_JS_INVOKE_ID)
+        {
+          if (w->external_invoke_cb != NULL)
+          {
+            w->external_invoke_cb(w, s);
+          }
+        }
+        else
+        {
+          return S_FALSE;
+        }
+        GlobalFree(s);
+      }
+    }
+    return S_OK;
+  }
+
+  static IDispatchVtbl ExternalDispatchTable = {
+      JS_QueryInterface, JS_AddRef, JS_Release, JS_GetTypeInfoCount,
+      JS_GetTypeInfo, JS_GetIDsOfNames, JS_Invoke};
+
+  static ULONG STDMETHODCALLTYPE Site_AddRef(IOleClientSite FAR *This)
+  {
+    return 1;
+  }
+  static ULONG STDMETHODCALLTYPE Site_Release(IOleClientSite FAR *This)
+  {
+    return 1;
+  }
+  static HRESULT STDMETHODCALLTYPE Site_SaveObject(IOleClientSite FAR *This)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE Site_GetMoniker(IOleClientSite FAR *This,
+                                                   DWORD dwAssign,
+                                                   DWORD dwWhichMoniker,
+                                                   IMoniker **ppmk)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  Site_GetContainer(IOleClientSite FAR *This, LPOLECONTAINER FAR *ppContainer)
+  {
+    *ppContainer = 0;
+    return E_NOINTERFACE;
+  }
+  static HRESULT STDMETHODCALLTYPE Site_ShowObject(IOleClientSite FAR *This)
+  {
+    return NOERROR;
+  }
+  static HRESULT STDMETHODCALLTYPE Site_OnShowWindow(IOleClientSite FAR *This,
+                                                     BOOL fShow)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  Site_RequestNewObjectLayout(IOleClientSite FAR *This)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE Site_QueryInterface(IOleClientSite FAR *This,
+                                                       REFIID riid,
+                                                       void **ppvObject)
+  {
+    if (iid_eq(riid, &IID_IUnknown) || iid_eq(riid, &IID_IOleClientSite))
+    {
+      *ppvObject = &((_IOleClientSiteEx *)This)->client;
+    }
+    else if (iid_eq(riid, &IID_IOleInPlaceSite))
+    {
+      *ppvObject = &((_IOleClientSiteEx *)This)->inplace;
+    }
+    else if (iid_eq(riid, &IID_IDocHostUIHandler))
+    {
+      *ppvObject = &((_IOleClientSiteEx *)This)->ui;
+    }
+    else
+    {
+      *ppvObject = 0;
+      return (E_NOINTERFACE);
+    }
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE InPlace_QueryInterface(
+      IOleInPlaceSite FAR *This, REFIID riid, LPVOID FAR *ppvObj)
+  {
+    return (Site_QueryInterface(
+        (IOleClientSite *)((char *)This - sizeof(IOleClientSite)), riid, ppvObj));
+  }
+  static ULONG STDMETHODCALLTYPE InPlace_AddRef(IOleInPlaceSite FAR *This)
+  {
+    return 1;
+  }
+  static ULONG STDMETHODCALLTYPE InPlace_Release(IOleInPlaceSite FAR *This)
+  {
+    return 1;
+  }
+  static HRESULT STDMETHODCALLTYPE InPlace_GetWindow(IOleInPlaceSite FAR *This,
+                                                     HWND FAR *lphwnd)
+  {
+    *lphwnd = ((_IOleInPlaceSiteEx FAR *)This)->frame.window;
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  InPlace_ContextSensitiveHelp(IOleInPlaceSite FAR *This, BOOL fEnterMode)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  InPlace_CanInPlaceActivate(IOleInPlaceSite FAR *This)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  InPlace_OnInPlaceActivate(IOleInPlaceSite FAR *This)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  InPlace_OnUIActivate(IOleInPlaceSite FAR *This)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE InPlace_GetWindowContext(
+      IOleInPlaceSite FAR *This, LPOLEINPLACEFRAME FAR *lplpFrame,
+      LPOLEINPLACEUIWINDOW FAR *lplpDoc, LPRECT lprcPosRect, LPRECT lprcClipRect,
+      LPOLEINPLACEFRAMEINFO lpFrameInfo)
+  {
+    *lplpFrame = (LPOLEINPLACEFRAME) & ((_IOleInPlaceSiteEx *)This)->frame;
+    *lplpDoc = 0;
+    lpFrameInfo->fMDIApp = FALSE;
+    lpFrameInfo->hwndFrame = ((_IOleInPlaceFrameEx *)*lplpFrame)->window;
+    lpFrameInfo->haccel = 0;
+    lpFrameInfo->cAccelEntries = 0;
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE InPlace_Scroll(IOleInPlaceSite FAR *This,
+                                                  SIZE scrollExtent)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  InPlace_OnUIDeactivate(IOleInPlaceSite FAR *This, BOOL fUndoable)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  InPlace_OnInPlaceDeactivate(IOleInPlaceSite FAR *This)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  InPlace_DiscardUndoState(IOleInPlaceSite FAR *This)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  InPlace_DeactivateAndUndo(IOleInPlaceSite FAR *This)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  InPlace_OnPosRectChange(IOleInPlaceSite FAR *This, LPCRECT lprcPosRect)
+  {
+    IOleObject *browserObject;
+    IOleInPlaceObject *inplace;
+    browserObject = *((IOleObject **)((char *)This - sizeof(IOleObject *) -
+                                      sizeof(IOleClientSite)));
+    if (!browserObject->lpVtbl->QueryInterface(browserObject,
+                                               iid_unref(&IID_IOleInPlaceObject),
+                                               (void **)&inplace))
+    {
+      inplace->lpVtbl->SetObjectRects(inplace, lprcPosRect, lprcPosRect);
+      inplace->lpVtbl->Release(inplace);
+    }
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE Frame_QueryInterface(
+      IOleInPlaceFrame FAR *This, REFIID riid, LPVOID FAR *ppvObj)
+  {
+    return E_NOTIMPL;
+  }
+  static ULONG STDMETHODCALLTYPE Frame_AddRef(IOleInPlaceFrame FAR *This)
+  {
+    return 1;
+  }
+  static ULONG STDMETHODCALLTYPE Frame_Release(IOleInPlaceFrame FAR *This)
+  {
+    return 1;
+  }
+  static HRESULT STDMETHODCALLTYPE Frame_GetWindow(IOleInPlaceFrame FAR *This,
+                                                   HWND FAR *lphwnd)
+  {
+    *lphwnd = ((_IOleInPlaceFrameEx *)This)->window;
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  Frame_ContextSensitiveHelp(IOleInPlaceFrame FAR *This, BOOL fEnterMode)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE Frame_GetBorder(IOleInPlaceFrame FAR *This,
+                                                   LPRECT lprectBorder)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE Frame_RequestBorderSpace(
+      IOleInPlaceFrame FAR *This, LPCBORDERWIDTHS pborderwidths)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE Frame_SetBorderSpace(
+      IOleInPlaceFrame FAR *This, LPCBORDERWIDTHS pborderwidths)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE Frame_SetActiveObject(
+      IOleInPlaceFrame FAR *This, IOleInPlaceActiveObject *pActiveObject,
+      LPCOLESTR pszObjName)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  Frame_InsertMenus(IOleInPlaceFrame FAR *This, HMENU hmenuShared,
+                    LPOLEMENUGROUPWIDTHS lpMenuWidths)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE Frame_SetMenu(IOleInPlaceFrame FAR *This,
+                                                 HMENU hmenuShared,
+                                                 HOLEMENU holemenu,
+                                                 HWND hwndActiveObject)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE Frame_RemoveMenus(IOleInPlaceFrame FAR *This,
+                                                     HMENU hmenuShared)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE Frame_SetStatusText(IOleInPlaceFrame FAR *This,
+                                                       LPCOLESTR pszStatusText)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  Frame_EnableModeless(IOleInPlaceFrame FAR *This, BOOL fEnable)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  Frame_TranslateAccelerator(IOleInPlaceFrame FAR *This, LPMSG lpmsg, WORD wID)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE UI_QueryInterface(IDocHostUIHandler FAR *This,
+                                                     REFIID riid,
+                                                     LPVOID FAR *ppvObj)
+  {
+    return (Site_QueryInterface((IOleClientSite *)((char *)This -
+                                                   sizeof(IOleClientSite) -
+                                                   sizeof(_IOleInPlaceSiteEx)),
+                                riid, ppvObj));
+  }
+  static ULONG STDMETHODCALLTYPE UI_AddRef(IDocHostUIHandler FAR *This)
+  {
+    return 1;
+  }
+  static ULONG STDMETHODCALLTYPE UI_Release(IDocHostUIHandler FAR *This)
+  {
+    return 1;
+  }
+  static HRESULT STDMETHODCALLTYPE UI_ShowContextMenu(
+      IDocHostUIHandler FAR *This, DWORD dwID, POINT __RPC_FAR *ppt,
+      IUnknown __RPC_FAR *pcmdtReserved, IDispatch __RPC_FAR *pdispReserved)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  UI_GetHostInfo(IDocHostUIHandler FAR *This, DOCHOSTUIINFO __RPC_FAR *pInfo)
+  {
+    pInfo->cbSize = sizeof(DOCHOSTUIINFO);
+    pInfo->dwFlags = DOCHOSTUIFLAG_NO3DBORDER;
+    pInfo->dwDoubleClick = DOCHOSTUIDBLCLK_DEFAULT;
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE UI_ShowUI(
+      IDocHostUIHandler FAR *This, DWORD dwID,
+      IOleInPlaceActiveObject __RPC_FAR *pActiveObject,
+      IOleCommandTarget __RPC_FAR *pCommandTarget,
+      IOleInPlaceFrame __RPC_FAR *pFrame, IOleInPlaceUIWindow __RPC_FAR *pDoc)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE UI_HideUI(IDocHostUIHandler FAR *This)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE UI_UpdateUI(IDocHostUIHandler FAR *This)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE UI_EnableModeless(IDocHostUIHandler FAR *This,
+                                                     BOOL fEnable)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  UI_OnDocWindowActivate(IDocHostUIHandler FAR *This, BOOL fActivate)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  UI_OnFrameWindowActivate(IDocHostUIHandler FAR *This, BOOL fActivate)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  UI_ResizeBorder(IDocHostUIHandler FAR *This, LPCRECT prcBorder,
+                  IOleInPlaceUIWindow __RPC_FAR *pUIWindow, BOOL fRameWindow)
+  {
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  UI_TranslateAccelerator(IDocHostUIHandler FAR *This, LPMSG lpMsg,
+                          const GUID __RPC_FAR *pguidCmdGroup, DWORD nCmdID)
+  {
+    return S_FALSE;
+  }
+  static HRESULT STDMETHODCALLTYPE UI_GetOptionKeyPath(
+      IDocHostUIHandler FAR *This, LPOLESTR __RPC_FAR *pchKey, DWORD dw)
+  {
+    return S_FALSE;
+  }
+
+  static double webview_scale(struct webview *w);
+
+  // Wraps the browser's drop target to report the files dropped on the
+  // webview. The page still gets the drag and the drop.
+  typedef struct
+  {
+    IDropTarget target;
+    LONG refs;
+    IDropTarget *inner;
+    struct webview *w;
+    // Set while the drag holds files
+    BOOL files;
+  } _IDropTargetEx;
+
+  static HRESULT STDMETHODCALLTYPE DT_QueryInterface(IDropTarget *This,
+                                                     REFIID riid,
+                                                     void **ppvObject)
+  {
+    if (iid_eq(riid, &IID_IUnknown) || iid_eq(riid, &IID_IDropTarget))
+    {
+      *ppvObject = This;
+      This->lpVtbl->AddRef(This);
+      return S_OK;
+    }
+    *ppvObject = NULL;
+    return E_NOINTERFACE;
+  }
+  static ULONG STDMETHODCALLTYPE DT_AddRef(IDropTarget *This)
+  {
+    return InterlockedIncrement(&((_IDropTargetEx *)This)->refs);
+  }
+  static ULONG STDMETHODCALLTYPE DT_Release(IDropTarget *This)
+  {
+    _IDropTargetEx *ex = (_IDropTargetEx *)This;
+    LONG refs = InterlockedDecrement(&ex->refs);
+    if (refs == 0)
+    {
+      ex->inner->lpVtbl->Release(ex->inner);
+      GlobalFree(ex);
+    }
+    return refs;
+  }
+  static HRESULT STDMETHODCALLTYPE DT_DragEnter(IDropTarget *This,
+                                                IDataObject *pDataObj,
+                                                DWORD grfKeyState, POINTL pt,
+                                                DWORD *pdwEffect)
+  {
+    _IDropTargetEx *ex = (_IDropTargetEx *)This;
+    FORMATETC format = {CF_HDROP, NULL, DVASPECT_CONTENT, -1, TYMED_HGLOBAL};
+    ex->files = pDataObj->lpVtbl->QueryGetData(pDataObj, &format) == S_OK;
+    return ex->inner->lpVtbl->DragEnter(ex->inner, pDataObj, grfKeyState, pt,
+                                        pdwEffect);
+  }
+  static HRESULT STDMETHODCALLTYPE DT_DragOver(IDropTarget *This,
+                                               DWORD grfKeyState, POINTL pt,
+                                               DWORD *pdwEffect)
+  {
+    _IDropTargetEx *ex = (_IDropTargetEx *)This;
+    return ex->inner->lpVtbl->DragOver(ex->inner, grfKeyState, pt, pdwEffect);
+  }
+  static HRESULT STDMETHODCALLTYPE DT_DragLeave(IDropTarget *This)
+  {
+    _IDropTargetEx *ex = (_IDropTargetEx *)This;
+    ex->files = FALSE;
+    return ex->inner->lpVtbl->DragLeave(ex->inner);
+  }
+  static HRESULT STDMETHODCALLTYPE DT_Drop(IDropTarget *This,
+                                           IDataObject *pDataObj,
+                                           DWORD grfKeyState, POINTL pt,
+                                           DWORD *pdwEffect)
+  {
+    _IDropTargetEx *ex = (_IDropTargetEx *)This;
+    struct webview *w = ex->w;
+    FORMATETC format = {CF_HDROP, NULL, DVASPECT_CONTENT, -1, TYMED_HGLOBAL};
+    STGMEDIUM medium;
+    if (ex->files && w->file_drop_cb != NULL &&
+        pDataObj->lpVtbl->GetData(pDataObj, &format, &medium) == S_OK)
+    {
+      HDROP drop = (HDROP)GlobalLock(medium.hGlobal);
+      if (drop != NULL)
+      {
+        // The paths are joined in UTF-16 and converted together
+        UINT count = DragQueryFileW(drop, 0xFFFFFFFF, NULL, 0);
+        size_t length = 1;
+        for (UINT i = 0; i < count; i++)
+        {
+          length += DragQueryFileW(drop, i, NULL, 0) + 1;
+        }
+        WCHAR *paths = (WCHAR *)GlobalAlloc(GMEM_FIXED, length * sizeof(WCHAR));
+        if (paths != NULL)
+        {
+          WCHAR *end = paths;
+          for (UINT i = 0; i < count; i++)
+          {
+            if (i > 0)
+            {
+              *end++ = L'\n';
+            }
+            end += DragQueryFileW(drop, i, end, length - (end - paths));
+          }
+          *end = 0;
+          char *files = webview_from_utf16(paths);
+          if (files != NULL)
+          {
+            POINT point = {pt.x, pt.y};
+            ScreenToClient(w->priv.hwnd, &point);
+            double scale = webview_scale(w);
+            w->file_drop_cb(w, files, (int)(point.x / scale),
+                            (int)(point.y / scale));
+            GlobalFree(files);
+          }
+          GlobalFree(paths);
+        }
+        GlobalUnlock(medium.hGlobal);
+      }
+      ReleaseStgMedium(&medium);
+    }
+    ex->files = FALSE;
+    return ex->inner->lpVtbl->Drop(ex->inner, pDataObj, grfKeyState, pt,
+                                   pdwEffect);
+  }
+  static IDropTargetVtbl MyIDropTargetTable = {
+      DT_QueryInterface, DT_AddRef, DT_Release, DT_DragEnter,
+      DT_DragOver, DT_DragLeave, DT_Drop};
+
+  static HRESULT STDMETHODCALLTYPE UI_GetDropTarget(
+      IDocHostUIHandler FAR *This, IDropTarget __RPC_FAR *pDropTarget,
+      IDropTarget __RPC_FAR *__RPC_FAR *ppDropTarget)
+  {
+    size_t offset = (size_t) & ((_IOleClientSiteEx *)NULL)->ui;
+    _IOleClientSiteEx *site = (_IOleClientSiteEx *)((char *)(This)-offset);
+    struct webview *w = (struct webview *)GetWindowLongPtr(
+        site->inplace.frame.window, GWLP_USERDATA);
+    _IDropTargetEx *ex =
+        (_IDropTargetEx *)GlobalAlloc(GMEM_FIXED, sizeof(_IDropTargetEx));
+    if (w == NULL || pDropTarget == NULL || ex == NULL)
+    {
+      if (ex != NULL)
+      {
+        GlobalFree(ex);
+      }
+      return S_FALSE;
+    }
+    ex->target.lpVtbl = &MyIDropTargetTable;
+    ex->refs = 1;
+    ex->inner = pDropTarget;
+    ex->inner->lpVtbl->AddRef(ex->inner);
+    ex->w = w;
+    ex->files = FALSE;
+    *ppDropTarget = &ex->target;
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE UI_GetExternal(
+      IDocHostUIHandler FAR *This, IDispatch __RPC_FAR *__RPC_FAR *ppDispatch)
+  {
+    *ppDispatch = (IDispatch *)(This + 1);
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE UI_TranslateUrl(
+      IDocHostUIHandler FAR *This, DWORD dwTranslate, OLECHAR __RPC_FAR *pchURLIn,
+      OLECHAR __RPC_FAR *__RPC_FAR *ppchURLOut)
+  {
+    *ppchURLOut = 0;
+    return S_FALSE;
+  }
+  static HRESULT STDMETHODCALLTYPE
+  UI_FilterDataObject(IDocHostUIHandler FAR *This, IDataObject __RPC_FAR *pDO,
+                      IDataObject __RPC_FAR *__RPC_FAR *ppDORet)
+  {
+    *ppDORet = 0;
+    return S_FALSE;
+  }
+
+  static const TCHAR *classname = TEXT("WebView");
+  static const SAFEARRAYBOUND ArrayBound = {1, 0};
+
+  static IOleClientSiteVtbl MyIOleClientSiteTable = {
+      Site_QueryInterface, Site_AddRef, Site_Release,
+      Site_SaveObject, Site_GetMoniker, Site_GetContainer,
+      Site_ShowObject, Site_OnShowWindow, Site_RequestNewObjectLayout};
+  static IOleInPlaceSiteVtbl MyIOleInPlaceSiteTable = {
+      InPlace_QueryInterface,
+      InPlace_AddRef,
+      InPlace_Release,
+      InPlace_GetWindow,
+      InPlace_ContextSensitiveHelp,
+      InPlace_CanInPlaceActivate,
+      InPlace_OnInPlaceActivate,
+      InPlace_OnUIActivate,
+      InPlace_GetWindowContext,
+      InPlace_Scroll,
+      InPlace_OnUIDeactivate,
+      InPlace_OnInPlaceDeactivate,
+      InPlace_DiscardUndoState,
+      InPlace_DeactivateAndUndo,
+      InPlace_OnPosRectChange};
+
+  static IOleInPlaceFrameVtbl MyIOleInPlaceFrameTable = {
+      Frame_QueryInterface,
+      Frame_AddRef,
+      Frame_Release,
+      Frame_GetWindow,
+      Frame_ContextSensitiveHelp,
+      Frame_GetBorder,
+      Frame_RequestBorderSpace,
+      Frame_SetBorderSpace,
+      Frame_SetActiveObject,
+      Frame_InsertMenus,
+      Frame_SetMenu,
+      Frame_RemoveMenus,
+      Frame_SetStatusText,
+      Frame_EnableModeless,
+      Frame_TranslateAccelerator};
+
+  static IDocHostUIHandlerVtbl MyIDocHostUIHandlerTable = {
+      UI_QueryInterface,
+      UI_AddRef,
+      UI_Release,
+      UI_ShowContextMenu,
+      UI_GetHostInfo,
+      UI_ShowUI,
+      UI_HideUI,
+      UI_UpdateUI,
+      UI_EnableModeless,
+      UI_OnDocWindowActivate,
+      UI_OnFrameWindowActivate,
+      UI_ResizeBorder,
+      UI_TranslateAccelerator,
+      UI_GetOptionKeyPath,
+      UI_GetDropTarget,
+      UI_GetExternal,
+      UI_TranslateUrl,
+      UI_FilterDataObject};
+
+  static void UnEmbedBrowserObject(struct webview *w)
+  {
+    if (w->priv.browser != NULL)
+    {
+      (*w->priv.browser)->lpVtbl->Close(*w->priv.browser, OLECLOSE_NOSAVE);
+      (*w->priv.browser)->lpVtbl->Release(*w->priv.browser);
+      GlobalFree(w->priv.browser);
+      w->priv.browser = NULL;
+    }
+  }
+
+  static int EmbedBrowserObject(struct webview *w)
+  {
+    RECT rect;
+    IWebBrowser2 *webBrowser2 = NULL;
+    LPCLASSFACTORY pClassFactory = NULL;
+    _IOleClientSiteEx *_iOleClientSiteEx = NULL;
+    IOleObject **browser = (IOleObject **)GlobalAlloc(
+        GMEM_FIXED, sizeof(IOleObject *) + sizeof(_IOleClientSiteEx));
+    if (browser == NULL)
+    {
+      goto error;
+    }
+    w->priv.browser = browser;
+
+    _iOleClientSiteEx = (_IOleClientSiteEx *)(browser + 1);
+    _iOleClientSiteEx->client.lpVtbl = &MyIOleClientSiteTable;
+    _iOleClientSiteEx->inplace.inplace.lpVtbl = &MyIOleInPlaceSiteTable;
+    _iOleClientSiteEx->inplace.frame.frame.lpVtbl = &MyIOleInPlaceFrameTable;
+    _iOleClientSiteEx->inplace.frame.window = w->priv.hwnd;
+    _iOleClientSiteEx->ui.ui.lpVtbl = &MyIDocHostUIHandlerTable;
+    _iOleClientSiteEx->external.lpVtbl = &ExternalDispatchTable;
+
+    if (CoGetClassObject(iid_unref(&CLSID_WebBrowser),
+                         CLSCTX_INPROC_SERVER | CLSCTX_INPROC_HANDLER, NULL,
+                         iid_unref(&IID_IClassFactory),
+                         (void **)&pClassFactory) != S_OK)
+    {
+      goto error;
+    }
+
+    if (pClassFactory == NULL)
+    {
+      goto error;
+    }
+
+    if (pClassFactory->lpVtbl->CreateInstance(pClassFactory, 0,
+                                              iid_unref(&IID_IOleObject),
+                                              (void **)browser) != S_OK)
+    {
+      goto error;
+    }
+    pClassFactory->lpVtbl->Release(pClassFactory);
+    if ((*browser)->lpVtbl->SetClientSite(
+            *browser, (IOleClientSite *)_iOleClientSiteEx) != S_OK)
+    {
+      goto error;
+    }
+    (*browser)->lpVtbl->SetHostNames(*browser, L"My Host Name", 0);
+
+    if (OleSetContainedObject((struct IUnknown *)(*browser), TRUE) != S_OK)
+    {
+      goto error;
+    }
+    GetClientRect(w->priv.hwnd, &rect);
+    if ((*browser)->lpVtbl->DoVerb((*browser), OLEIVERB_SHOW, NULL,
+                                   (IOleClientSite *)_iOleClientSiteEx, -1,
+                                   w->priv.hwnd, &rect) != S_OK)
+    {
+      goto error;
+    }
+    if ((*browser)->lpVtbl->QueryInterface((*browser),
+                                           iid_unref(&IID_IWebBrowser2),
+                                           (void **)&webBrowser2) != S_OK)
+    {
+      goto error;
+    }
+
+    webBrowser2->lpVtbl->put_Left(webBrowser2, 0);
+    webBrowser2->lpVtbl->put_Top(webBrowser2, 0);
+    webBrowser2->lpVtbl->put_Width(webBrowser2, rect.right);
+    webBrowser2->lpVtbl->put_Height(webBrowser2, rect.bottom);
+    webBrowser2->lpVtbl->Release(webBrowser2);
+
+    return 0;
+  error:
+    UnEmbedBrowserObject(w);
+    if (pClassFactory != NULL)
+    {
+      pClassFactory->lpVtbl->Release(pClassFactory);
+    }
+    if (browser != NULL)
+    {
+      GlobalFree(browser);
+    }
+    return -1;
+  }
+
+#define WEBVIEW_DATA_URL_PREFIX "data:text/html,"
+  static int DisplayHTMLPage(struct webview *w)
+  {
+    IWebBrowser2 *webBrowser2;
+    VARIANT myURL;
+    LPDISPATCH lpDispatch;
+    IHTMLDocument2 *htmlDoc2;
+    BSTR bstr;
+    IOleObject *browserObject;
+    SAFEARRAY *sfArray;
+    VARIANT *pVar;
+    browserObject = *w->priv.browser;
+    int isDataURL = 0;
+    const char *webview_url = webview_check_url(w->url);
+    if (!browserObject->lpVtbl->QueryInterface(
+            browserObject, iid_unref(&IID_IWebBrowser2), (void **)&webBrowser2))
+    {
+      LPCSTR webPageName;
+      isDataURL = (strncmp(webview_url, WEBVIEW_DATA_URL_PREFIX,
+                           strlen(WEBVIEW_DATA_URL_PREFIX)) == 0);
+      if (isDataURL)
+      {
+        webPageName = "about:blank";
+      }
+      else
+      {
+        webPageName = (LPCSTR)webview_url;
+      }
+      VariantInit(&myURL);
+      myURL.vt = VT_BSTR;
+      // #ifndef UNICODE
+      {
+        wchar_t *buffer = webview_to_utf16(webPageName);
+        if (buffer == NULL)
+        {
+          goto badalloc;
+        }
+        myURL.bstrVal = SysAllocString(buffer);
+        GlobalFree(buffer);
+      }
+      // #else
+      //       myURL.bstrVal = SysAllocString(webPageName);
+      // #endif
+      if (!myURL.bstrVal)
+      {
+      badalloc:
+        webBrowser2->lpVtbl->Release(webBrowser2);
+        return (-6);
+      }
+      webBrowser2->lpVtbl->Navigate2(webBrowser2, &myURL, 0, 0, 0, 0);
+      VariantClear(&myURL);
+      if (!isDataURL)
+      {
+        return 0;
+      }
+
+      char *url = (char *)calloc(1, strlen(webview_url) + 1);
+      char *q = url;
+      for (const char *p = webview_url + strlen(WEBVIEW_DATA_URL_PREFIX); *q = *p;
+           p++, q++)
+      {
+        if (*q == '%' && *(p + 1) && *(p + 2))
+        {
+          sscanf(p + 1, "%02x", q);
+          p = p + 2;
+        }
+      }
+
+      if (webBrowser2->lpVtbl->get_Document(webBrowser2, &lpDispatch) == S_OK)
+      {
+        if (lpDispatch->lpVtbl->QueryInterface(lpDispatch,
+                                               iid_unref(&IID_IHTMLDocument2),
+                                               (void **)&htmlDoc2) == S_OK)
+        {
+          if ((sfArray = SafeArrayCreate(VT_VARIANT, 1,
+                                         (SAFEARRAYBOUND *)&ArrayBound)))
+          {
+            if (!SafeArrayAccessData(sfArray, (void **)&pVar))
+            {
+              pVar->vt = VT_BSTR;
+              // #ifndef UNICODE
+              {
+                wchar_t *buffer = webview_to_utf16(url);
+                if (buffer == NULL)
+                {
+                  goto release;
+                }
+                bstr = SysAllocString(buffer);
+                GlobalFree(buffer);
+              }
+              // #else
+              //               bstr = SysAllocString(url);
+              // #endif
+              if ((pVar->bstrVal = bstr))
+              {
+                htmlDoc2->lpVtbl->write(htmlDoc2, sfArray);
+                htmlDoc2->lpVtbl->close(htmlDoc2);
+              }
+            }
+            SafeArrayDestroy(sfArray);
+          }
+        release:
+          free(url);
+          htmlDoc2->lpVtbl->Release(htmlDoc2);
+        }
+        lpDispatch->lpVtbl->Release(lpDispatch);
+      }
+      webBrowser2->lpVtbl->Release(webBrowser2);
+      return (0);
+    }
+    return (-5);
+  }
+
+#define WEBVIEW_SPLITTER_SIZE 4
+
+#ifndef WM_DPICHANGED
+#define WM_DPICHANGED 0x02E0
+#endif
+#define WEBVIEW_OLECMDID_OPTICAL_ZOOM 63
+
+  // Returns the scale of the display the window is on. Before Windows 10
+  // every display has the system's scale.
+  static double webview_scale(struct webview *w)
+  {
+    typedef UINT(WINAPI * GetDpiForWindowFunc)(HWND);
+    GetDpiForWindowFunc getDpiForWindow = (GetDpiForWindowFunc)GetProcAddress(
+        GetModuleHandle(TEXT("user32.dll")), "GetDpiForWindow");
+    if (getDpiForWindow != NULL && w->priv.hwnd != NULL)
+    {
+      return getDpiForWindow(w->priv.hwnd) / 96.0;
+    }
+    HDC hDC = GetDC(NULL);
+    double scale = GetDeviceCaps(hDC, LOGPIXELSX) / 96.0;
+    ReleaseDC(NULL, hDC);
+    return scale;
+  }
+
+  // Zooms the page to the scale of the display the window is on
+  static void webview_zoom_browser(struct webview *w)
+  {
+    IWebBrowser2 *webBrowser2;
+    IOleObject *browser = *w->priv.browser;
+    if (browser->lpVtbl->QueryInterface(browser, iid_unref(&IID_IWebBrowser2),
+                                        (void **)&webBrowser2) == S_OK)
+    {
+      VARIANT zoom;
+      VariantInit(&zoom);
+      zoom.vt = VT_I4;
+      zoom.lVal = (LONG)(webview_scale(w) * 100);
+      webBrowser2->lpVtbl->ExecWB(webBrowser2,
+                                  (OLECMDID)WEBVIEW_OLECMDID_OPTICAL_ZOOM,
+                                  OLECMDEXECOPT_DONTPROMPTUSER, &zoom, NULL);
+      webBrowser2->lpVtbl->Release(webBrowser2);
+    }
+  }
+
+  // Returns the role of the item of the menu with the given command id,
+  // which is kept in the item's data
+  static int webview_menu_item_role(HMENU menu, UINT id)
+  {
+    MENUITEMINFOW info = {sizeof(MENUITEMINFOW)};
+    info.fMask = MIIM_DATA;
+    if (menu == NULL || !GetMenuItemInfoW(menu, id, FALSE, &info))
+    {
+      return WEBVIEW_MENU_ROLE_NONE;
+    }
+    return (int)info.dwItemData;
+  }
+
+  // Does what the standard item with the role does. Quitting closes the
+  // window as if the user had.
+  static void webview_menu_role(struct webview *w, int role)
+  {
+    OLECMDID command;
+    switch (role)
+    {
+    case WEBVIEW_MENU_ROLE_CUT:
+      command = OLECMDID_CUT;
+      break;
+    case WEBVIEW_MENU_ROLE_COPY:
+      command = OLECMDID_COPY;
+      break;
+    case WEBVIEW_MENU_ROLE_PASTE:
+      command = OLECMDID_PASTE;
+      break;
+    case WEBVIEW_MENU_ROLE_SELECT_ALL:
+      command = OLECMDID_SELECTALL;
+      break;
+    case WEBVIEW_MENU_ROLE_QUIT:
+      PostMessage(w->priv.hwnd, WM_CLOSE, 0, 0);
+      return;
+    case WEBVIEW_MENU_ROLE_MINIMIZE:
+      ShowWindow(w->priv.hwnd, SW_MINIMIZE);
+      return;
+    case WEBVIEW_MENU_ROLE_ABOUT:
+      webview_show_about(w);
+      return;
+    default:
+      return;
+    }
+    IWebBrowser2 *webBrowser2;
+    IOleObject *browser = *w->priv.browser;
+    if (browser->lpVtbl->QueryInterface(browser, iid_unref(&IID_IWebBrowser2),
+                                        (void **)&webBrowser2) == S_OK)
+    {
+      webBrowser2->lpVtbl->ExecWB(webBrowser2, command,
+                                  OLECMDEXECOPT_DODEFAULT, NULL, NULL);
+      webBrowser2->lpVtbl->Release(webBrowser2);
+    }
+  }
+
+  // Makes an icon of the given size from PNG data, or from the closest image
+  // in an ICO file
+  static HICON webview_create_icon(const uint8_t *data, int size, int icon_size)
+  {
+    PBYTE bits = (PBYTE)data;
+    DWORD length = size;
+    if (size > 6 && data[0] == 0 && data[1] == 0 && data[2] == 1 && data[3] == 0)
+    {
+      // Pick the entry in the directory with the closest width, where 0 is
+      // 256 pixels
+      int count = data[4] | (data[5] << 8);
+      int best = -1;
+      int best_distance = 0;
+      for (int i = 0; i < count && 6 + (i + 1) * 16 <= size; i++)
+      {
+        int width = data[6 + i * 16] == 0 ? 256 : data[6 + i * 16];
+        int distance = abs(width - icon_size);
+        if (best < 0 || distance < best_distance)
+        {
+          best = i;
+          best_distance = distance;
+        }
+      }
+      if (best < 0)
+      {
+        return NULL;
+      }
+      const uint8_t *entry = data + 6 + best * 16;
+      DWORD bytes = entry[8] | (entry[9] << 8) | (entry[10] << 16) | ((DWORD)entry[11] << 24);
+      DWORD offset = entry[12] | (entry[13] << 8) | (entry[14] << 16) | ((DWORD)entry[15] << 24);
+      if (offset >= (DWORD)size || bytes > (DWORD)size - offset)
+      {
+        return NULL;
+      }
+      bits += offset;
+      length = bytes;
+    }
+    return CreateIconFromResourceEx(bits, length, TRUE, 0x00030000, icon_size,
+                                    icon_size, LR_DEFAULTCOLOR);
+  }
+
+  static void webview_destroy_icons(struct webview *w)
+  {
+    if (w->priv.icon != NULL)
+    {
+      DestroyIcon(w->priv.icon);
+      w->priv.icon = NULL;
+    }
+    if (w->priv.small_icon != NULL)
+    {
+      DestroyIcon(w->priv.small_icon);
+      w->priv.small_icon = NULL;
+    }
+  }
+
+  // The menu is only dismissed by clicking elsewhere while the window is in
+  // the foreground
+  static void webview_tray_show_menu(struct webview *w)
+  {
+    POINT p;
+    if (w->priv.tray_menu == NULL)
+    {
+      return;
+    }
+    GetCursorPos(&p);
+    SetForegroundWindow(w->priv.hwnd);
+    int item = TrackPopupMenu(w->priv.tray_menu,
+                              TPM_RETURNCMD | TPM_RIGHTBUTTON | TPM_NONOTIFY,
+                              p.x, p.y, 0, w->priv.hwnd, NULL);
+    PostMessage(w->priv.hwnd, WM_NULL, 0, 0);
+    if (item > 0)
+    {
+      webview_tray_event(w, item - 1);
+    }
+  }
+
+  // Called once the balloon is gone, removing the icon if it was only added
+  // to show it
+  static void webview_forget_notification(struct webview *w)
+  {
+    NOTIFYICONDATAW *tray = &w->priv.tray;
+    free(w->priv.notification);
+    w->priv.notification = NULL;
+    if (w->priv.notification_icon)
+    {
+      Shell_NotifyIconW(NIM_DELETE, tray);
+      DestroyIcon(tray->hIcon);
+      ZeroMemory(tray, sizeof(*tray));
+      w->priv.notification_icon = FALSE;
+    }
+  }
+
+  // Transparent windows key out their background colour, unless they have a
+  // translucent backdrop, where the background's alpha fades the window. The
+  // window's opacity applies on top of either.
+  static void webview_update_layered(struct webview *w)
+  {
+    BYTE alpha = 255 - w->priv.transparency;
+    if (w->transparent && w->backdrop != WEBVIEW_BACKDROP_TRANSLUCENT)
+    {
+      SetLayeredWindowAttributes(w->priv.hwnd, w->priv.color, alpha,
+                                 LWA_COLORKEY | LWA_ALPHA);
+      return;
+    }
+    if (w->transparent)
+    {
+      alpha = (BYTE)(alpha * w->priv.color_alpha / 255);
+    }
+    SetLayeredWindowAttributes(w->priv.hwnd, 0, alpha, LWA_ALPHA);
+  }
+
+  static void webview_resize_browser(struct webview *w, int width, int height)
+  {
+    IWebBrowser2 *webBrowser2;
+    IOleObject *browser = *w->priv.browser;
+    if (browser->lpVtbl->QueryInterface(browser, iid_unref(&IID_IWebBrowser2),
+                                        (void **)&webBrowser2) == S_OK)
+    {
+      webBrowser2->lpVtbl->put_Width(webBrowser2, width);
+      webBrowser2->lpVtbl->put_Height(webBrowser2, height);
+    }
+  }
+
+  // Reports the window's change of size, or of state, from WM_SIZE
+  static void webview_size_event(struct webview *w, WPARAM type)
+  {
+    WPARAM last = w->priv.size_type;
+    w->priv.size_type = type;
+    if (type == SIZE_MINIMIZED)
+    {
+      if (last != SIZE_MINIMIZED)
+      {
+        webview_window_event(w, WEBVIEW_WINDOW_EVENT_MINIMISE);
+      }
+      return;
+    }
+    if (type == SIZE_MAXIMIZED && last != SIZE_MAXIMIZED)
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_MAXIMISE);
+    }
+    else if (type == SIZE_RESTORED && last != SIZE_RESTORED)
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_RESTORE);
+    }
+    webview_window_event(w, WEBVIEW_WINDOW_EVENT_RESIZE);
+  }
+
+  // Lays out the host's webview and its panes. The panes keep their sizes
+  // and the host's webview takes the rest of the window. All the panes take
+  // the orientation of the first.
+  static void webview_layout_panes(struct webview *host)
+  {
+    RECT rect;
+    GetClientRect(host->priv.hwnd, &rect);
+    int vertical = host->next_pane != NULL && host->next_pane->vertical;
+    int offset = vertical ? rect.bottom : rect.right;
+    struct webview *pane;
+    for (pane = host->next_pane; pane != NULL; pane = pane->next_pane)
+    {
+      offset -= pane->priv.pane_size + WEBVIEW_SPLITTER_SIZE;
+    }
+    if (offset < 0)
+    {
+      offset = 0;
+    }
+
+    if (vertical)
+    {
+      webview_resize_browser(host, rect.right, offset);
+    }
+    else
+    {
+      webview_resize_browser(host, offset, rect.bottom);
+    }
+    for (pane = host->next_pane; pane != NULL; pane = pane->next_pane)
+    {
+      pane->priv.splitter = offset;
+      offset += WEBVIEW_SPLITTER_SIZE;
+      if (vertical)
+      {
+        MoveWindow(pane->priv.hwnd, 0, offset, rect.right, pane->priv.pane_size,
+                   TRUE);
+      }
+      else
+      {
+        MoveWindow(pane->priv.hwnd, offset, 0, pane->priv.pane_size, rect.bottom,
+                   TRUE);
+      }
+      offset += pane->priv.pane_size;
+    }
+  }
+
+  // Returns the pane after the splitter at the given point in the host's
+  // window, or NULL if there is no splitter there
+  static struct webview *webview_splitter_at(struct webview *host, POINT pt)
+  {
+    int vertical = host->next_pane != NULL && host->next_pane->vertical;
+    int position = vertical ? pt.y : pt.x;
+    struct webview *pane;
+    for (pane = host->next_pane; pane != NULL; pane = pane->next_pane)
+    {
+      if (position >= pane->priv.splitter &&
+          position < pane->priv.splitter + WEBVIEW_SPLITTER_SIZE)
+      {
+        return pane;
+      }
+    }
+    return NULL;
+  }
+
+  // Moves the splitter being dragged to the given point. The webview before
+  // it grows as the pane after it shrinks.
+  static void webview_drag_splitter(struct webview *host, POINT pt)
+  {
+    struct webview *pane = host->priv.dragging;
+    struct webview *before = NULL;
+    struct webview *p;
+    for (p = host->next_pane; p != NULL && p != pane; p = p->next_pane)
+    {
+      before = p;
+    }
+
+    int position = pane->vertical ? pt.y : pt.x;
+    int delta = position - (pane->priv.splitter + WEBVIEW_SPLITTER_SIZE / 2);
+    int before_size = before != NULL ? before->priv.pane_size : pane->priv.splitter;
+    if (delta > pane->priv.pane_size)
+    {
+      delta = pane->priv.pane_size;
+    }
+    if (delta < -before_size)
+    {
+      delta = -before_size;
+    }
+    pane->priv.pane_size -= delta;
+    if (before != NULL)
+    {
+      before->priv.pane_size += delta;
+    }
+    webview_layout_panes(host);
+  }
+
+  static LRESULT CALLBACK wndproc(HWND hwnd, UINT uMsg, WPARAM wParam,
+                                  LPARAM lParam)
+  {
+    struct webview *w = (struct webview *)GetWindowLongPtr(hwnd, GWLP_USERDATA);
+    switch (uMsg)
+    {
+    case WM_CREATE:
+      w = (struct webview *)((CREATESTRUCT *)lParam)->lpCreateParams;
+      w->priv.hwnd = hwnd;
+
+      return EmbedBrowserObject(w);
+    case WM_GETMINMAXINFO:
+    {
+      if (w != NULL) {
+        // get pixel density of the display the window is on
+        double DPIScaleX = webview_scale(w);
+        double DPIScaleY = DPIScaleX;
+      	
+        RECT rcClient, rcWind;
+        POINT ptDiff;
+        GetClientRect(hwnd, &rcClient);
+        GetWindowRect(hwnd, &rcWind);
+
+        int widthExtra = (rcWind.right - rcWind.left) - rcClient.right;
+        int heightExtra = (rcWind.bottom - rcWind.top) - rcClient.bottom;
+
+        LPMINMAXINFO lpMMI = (LPMINMAXINFO)lParam;
+      
+        if (w->priv.min_width != -1) {
+          lpMMI->ptMinTrackSize.x = w->priv.min_width * DPIScaleX + widthExtra;
+          lpMMI->ptMinTrackSize.y = w->priv.min_height * DPIScaleY + heightExtra;
+        }
+        if (w->priv.max_width != -1) {
+          lpMMI->ptMaxTrackSize.x = w->priv.max_width * DPIScaleX + widthExtra;
+          lpMMI->ptMaxTrackSize.y = w->priv.max_height * DPIScaleY + heightExtra;
+        }
+      }
+      
+      return 0;
+    }
+    case WM_SIZING:
+      // Keep the content at the aspect ratio, resizing it along the edge
+      // being dragged
+      if (w != NULL && w->priv.aspect > 0)
+      {
+        RECT *rect = (RECT *)lParam;
+        RECT rcClient, rcWind;
+        GetClientRect(hwnd, &rcClient);
+        GetWindowRect(hwnd, &rcWind);
+        int widthExtra = (rcWind.right - rcWind.left) - rcClient.right;
+        int heightExtra = (rcWind.bottom - rcWind.top) - rcClient.bottom;
+
+        if (wParam == WMSZ_TOP || wParam == WMSZ_BOTTOM)
+        {
+          int height = rect->bottom - rect->top - heightExtra;
+          rect->right = rect->left + (int)(height * w->priv.aspect) + widthExtra;
+        }
+        else
+        {
+          int width = rect->right - rect->left - widthExtra;
+          int height = (int)(width / w->priv.aspect);
+          if (wParam == WMSZ_TOPLEFT || wParam == WMSZ_TOPRIGHT)
+          {
+            rect->top = rect->bottom - height - heightExtra;
+          }
+          else
+          {
+            rect->bottom = rect->top + height + heightExtra;
+          }
+        }
+        return TRUE;
+      }
+      break;
+    case WM_CLOSE:
+      // Kiosk windows ignore Alt+F4
+      if (w->kiosk)
+      {
+        return 0;
+      }
+      if (w->closing_cb != NULL && w->closing_cb(w))
+      {
+        return 0;
+      }
+      break;
+    case WM_DESTROY:
+      if (w->host == NULL)
+      {
+        // Panes are destroyed after their host, which must not be laid out
+        // again
+        w->next_pane = NULL;
+      }
+      else if (webview_unlink_pane(w))
+      {
+        webview_layout_panes(w->host);
+      }
+      UnEmbedBrowserObject(w);
+      webview_destroy_icons(w);
+      webview_forget_notification(w);
+      webview_tray_remove(w);
+      if (w->priv.taskbar != NULL)
+      {
+        w->priv.taskbar->lpVtbl->Release(w->priv.taskbar);
+        w->priv.taskbar = NULL;
+      }
+      if (w->closed_cb != NULL)
+      {
+        w->closed_cb(w);
+      }
+      if (w->host == NULL && !w->secondary && !w->embedded)
+      {
+        PostQuitMessage(0);
+      }
+      return TRUE;
+    case WM_SIZE:
+    {
+      // Panes are sized as they are created, before they are set up
+      if (w == NULL)
+      {
+        break;
+      }
+      if (w->host == NULL)
+      {
+        webview_size_event(w, wParam);
+      }
+      if (w->next_pane != NULL)
+      {
+        webview_layout_panes(w);
+        return TRUE;
+      }
+      RECT rect;
+      GetClientRect(hwnd, &rect);
+      webview_resize_browser(w, rect.right, rect.bottom);
+      return TRUE;
+    }
+    case WM_MOVE:
+      if (w != NULL && w->host == NULL)
+      {
+        webview_window_event(w, WEBVIEW_WINDOW_EVENT_MOVE);
+      }
+      break;
+    case WM_ACTIVATE:
+      if (w != NULL && w->host == NULL)
+      {
+        webview_window_event(w, LOWORD(wParam) == WA_INACTIVE
+                                    ? WEBVIEW_WINDOW_EVENT_BLUR
+                                    : WEBVIEW_WINDOW_EVENT_FOCUS);
+      }
+      break;
+    case WM_DISPLAYCHANGE:
+      if (w != NULL && w->host == NULL)
+      {
+        webview_window_event(w, WEBVIEW_WINDOW_EVENT_DISPLAYS);
+      }
+      break;
+    case WM_DPICHANGED:
+      if (w != NULL)
+      {
+        // Take the size Windows suggests for the new scale, and zoom the
+        // page and any panes to match
+        RECT *suggested = (RECT *)lParam;
+        SetWindowPos(hwnd, NULL, suggested->left, suggested->top,
+                     suggested->right - suggested->left,
+                     suggested->bottom - suggested->top,
+                     SWP_NOZORDER | SWP_NOACTIVATE);
+        struct webview *pane;
+        for (pane = w; pane != NULL; pane = pane->next_pane)
+        {
+          webview_zoom_browser(pane);
+        }
+        webview_window_event(w, WEBVIEW_WINDOW_EVENT_SCALE);
+        return 0;
+      }
+      break;
+    case WM_SETCURSOR:
+      if (w != NULL && w->next_pane != NULL && LOWORD(lParam) == HTCLIENT)
+      {
+        POINT pt;
+        GetCursorPos(&pt);
+        ScreenToClient(hwnd, &pt);
+        if (w->priv.dragging != NULL || webview_splitter_at(w, pt) != NULL)
+        {
+          SetCursor(LoadCursor(NULL, w->next_pane->vertical ? IDC_SIZENS
+                                                            : IDC_SIZEWE));
+          return TRUE;
+        }
+      }
+      break;
+    case WM_LBUTTONDOWN:
+      if (w->next_pane != NULL)
+      {
+        POINT pt = {(short)LOWORD(lParam), (short)HIWORD(lParam)};
+        w->priv.dragging = webview_splitter_at(w, pt);
+        if (w->priv.dragging != NULL)
+        {
+          SetCapture(hwnd);
+          return 0;
+        }
+      }
+      break;
+    case WM_MOUSEMOVE:
+      if (w->priv.dragging != NULL)
+      {
+        POINT pt = {(short)LOWORD(lParam), (short)HIWORD(lParam)};
+        webview_drag_splitter(w, pt);
+        return 0;
+      }
+      break;
+    case WM_LBUTTONUP:
+      if (w->priv.dragging != NULL)
+      {
+        w->priv.dragging = NULL;
+        ReleaseCapture();
+        return 0;
+      }
+      break;
+    case WM_WEBVIEW_DISPATCH:
+    {
+      webview_dispatch_fn f = (webview_dispatch_fn)wParam;
+      void *arg = (void *)lParam;
+      (*f)(w, arg);
+      return TRUE;
+    }
+    case WM_WEBVIEW_TRAY:
+      // The tray icon sends the mouse message it received
+      if (lParam == WM_LBUTTONUP)
+      {
+        webview_tray_event(w, -1);
+      }
+      else if (lParam == WM_RBUTTONUP)
+      {
+        webview_tray_show_menu(w);
+      }
+      else if (lParam == NIN_BALLOONUSERCLICK && w->priv.notification != NULL)
+      {
+        char *id = w->priv.notification;
+        w->priv.notification = NULL;
+        webview_forget_notification(w);
+        webview_notification_event(w, id);
+        free(id);
+      }
+      else if (lParam == NIN_BALLOONHIDE || lParam == NIN_BALLOONTIMEOUT)
+      {
+        webview_forget_notification(w);
+      }
+      return 0;
+    case WM_COMMAND:
+      // Items of the menu bar send their command id, with 1 in the high
+      // word if chosen with their accelerator
+      if (HIWORD(wParam) <= 1 && lParam == 0 &&
+          LOWORD(wParam) >= WEBVIEW_MENU_ID)
+      {
+        int role = webview_menu_item_role(w->priv.menu, LOWORD(wParam));
+        if (role != WEBVIEW_MENU_ROLE_NONE)
+        {
+          webview_menu_role(w, role);
+        }
+        else
+        {
+          webview_menu_event(w, LOWORD(wParam) - WEBVIEW_MENU_ID);
+        }
+        return 0;
+      }
+      break;
+    }
+    return DefWindowProc(hwnd, uMsg, wParam, lParam);
+  }
+
+#define WEBVIEW_KEY_FEATURE_BROWSER_EMULATION \
+  "Software\\Microsoft\\Internet "            \
+  "Explorer\\Main\\FeatureControl\\FEATURE_BROWSER_EMULATION"
+
+  static int webview_fix_ie_compat_mode()
+  {
+    HKEY hKey;
+    DWORD ie_version = 11000;
+    TCHAR appname[MAX_PATH + 1];
+    TCHAR *p;
+    if (GetModuleFileName(NULL, appname, MAX_PATH + 1) == 0)
+    {
+      return -1;
+    }
+    for (p = &appname[strlen(appname) - 1]; p != appname && *p != '\\'; p--)
+    {
+    }
+    p++;
+    if (RegCreateKey(HKEY_CURRENT_USER, WEBVIEW_KEY_FEATURE_BROWSER_EMULATION,
+                     &hKey) != ERROR_SUCCESS)
+    {
+      return -1;
+    }
+    if (RegSetValueEx(hKey, p, 0, REG_DWORD, (BYTE *)&ie_version,
+                      sizeof(ie_version)) != ERROR_SUCCESS)
+    {
+      RegCloseKey(hKey);
+      return -1;
+    }
+    RegCloseKey(hKey);
+    return 0;
+  }
+
+  WEBVIEW_API int webview_init(struct webview *w)
+  {
+    w->priv.min_width = -1;
+    w->priv.max_width = -1;
+
+    WNDCLASSEX wc;
+    HINSTANCE hInstance;
+    DWORD style;
+    RECT clientRect;
+    RECT rect;
+
+    if (webview_fix_ie_compat_mode() < 0)
+    {
+      return -1;
+    }
+
+    hInstance = GetModuleHandle(NULL);
+    if (hInstance == NULL)
+    {
+      return -1;
+    }
+    // S_FALSE means OLE was initialised by an earlier window
+    HRESULT oleResult = OleInitialize(NULL);
+    if (oleResult != S_OK && oleResult != S_FALSE)
+    {
+      return -1;
+    }
+
+    ZeroMemory(&wc, sizeof(WNDCLASSEX));
+    wc.cbSize = sizeof(WNDCLASSEX);
+    wc.hInstance = hInstance;
+    wc.lpfnWndProc = wndproc;
+    wc.lpszClassName = classname;
+    wc.hIcon = LoadIcon(hInstance, MAKEINTRESOURCE(100));
+    wc.hIconSm = LoadIcon(hInstance, MAKEINTRESOURCE(100));
+    RegisterClassEx(&wc);
+
+    style = WS_OVERLAPPEDWINDOW;
     if (!w->resizable)
     {
-      style = WS_OVERLAPPED | WS_CAPTION | WS_MINIMIZEBOX | WS_SYSMENU;
+      style = WS_OVERLAPPED | WS_CAPTION | WS_MINIMIZEBOX | WS_SYSMENU;
+    }
+
+    // Scale
+    // Credit: https://github.com/webview/webview/issues/54#issuecomment-379528243
+    HDC hDC = GetDC(NULL);
+    w->width = GetDeviceCaps(hDC, 88)*w->width/96.0;
+    w->height = GetDeviceCaps(hDC, 90)*w->height/96.0;
+    ReleaseDC(NULL, hDC);
+
+    rect.left = 0;
+    rect.top = 0;
+    rect.right = w->width;
+    rect.bottom = w->height;
+    AdjustWindowRect(&rect, WS_OVERLAPPEDWINDOW, 0);
+
+    GetClientRect(GetDesktopWindow(), &clientRect);
+    int left = (clientRect.right / 2) - ((rect.right - rect.left) / 2);
+    int top = (clientRect.bottom / 2) - ((rect.bottom - rect.top) / 2);
+    rect.right = rect.right - rect.left + left;
+    rect.left = left;
+    rect.bottom = rect.bottom - rect.top + top;
+    rect.top = top;
+
+#ifdef UNICODE
+    wchar_t *u16title = webview_to_utf16(w->title);
+    if (u16title == NULL)
+    {
+      return -1;
+    }
+
+    w->priv.hwnd =
+        CreateWindowEx(0, classname, u16title, style, rect.left, rect.top,
+                       rect.right - rect.left, rect.bottom - rect.top,
+                       HWND_DESKTOP, NULL, hInstance, (void *)w);
+#else
+    w->priv.hwnd =
+        CreateWindowEx(0, classname, w->title, style, rect.left, rect.top,
+                       rect.right - rect.left, rect.bottom - rect.top,
+                       HWND_DESKTOP, NULL, hInstance, (void *)w);
+#endif
+
+    if (w->priv.hwnd == 0)
+    {
+      OleUninitialize();
+      return -1;
+    }
+
+    SetWindowLongPtr(w->priv.hwnd, GWLP_USERDATA, (LONG_PTR)w);
+
+    // Layered windows are made see-through by webview_set_color
+    w->priv.color_alpha = 255;
+    if (w->transparent)
+    {
+      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE,
+                    GetWindowLong(w->priv.hwnd, GWL_EXSTYLE) | WS_EX_LAYERED);
+      SetLayeredWindowAttributes(w->priv.hwnd, 0, 255, LWA_ALPHA);
+    }
+
+    DisplayHTMLPage(w);
+
+#ifdef UNICODE
+    SetWindowText(w->priv.hwnd, u16title);
+    GlobalFree(u16title);
+#else
+    SetWindowText(w->priv.hwnd, w->title);
+#endif
+
+    if (!w->hidden)
+    {
+      ShowWindow(w->priv.hwnd, SW_SHOWDEFAULT);
+      UpdateWindow(w->priv.hwnd);
+      SetFocus(w->priv.hwnd);
+    }
+
+    if (w->kiosk)
+    {
+      webview_set_fullscreen(w, 1);
+      webview_set_always_on_top(w, 1);
+    }
+
+    return 0;
+  }
+
+  // Panes are child windows of the host's window, of the same class, which
+  // the host lays out with its webview
+  WEBVIEW_API int webview_init_pane(struct webview *w)
+  {
+    struct webview *host = w->host;
+    w->priv.min_width = -1;
+    w->priv.max_width = -1;
+
+    double scale = webview_scale(host);
+    w->width = scale * w->width;
+    w->height = scale * w->height;
+    w->priv.pane_size = w->vertical ? w->height : w->width;
+
+    w->priv.hwnd =
+        CreateWindowEx(0, classname, NULL, WS_CHILD | WS_CLIPSIBLINGS, 0, 0,
+                       w->width, w->height, host->priv.hwnd, NULL,
+                       GetModuleHandle(NULL), (void *)w);
+    if (w->priv.hwnd == 0)
+    {
+      return -1;
+    }
+    SetWindowLongPtr(w->priv.hwnd, GWLP_USERDATA, (LONG_PTR)w);
+    DisplayHTMLPage(w);
+
+    webview_link_pane(w);
+    webview_layout_panes(host);
+    ShowWindow(w->priv.hwnd, SW_SHOW);
+    return 0;
+  }
+
+  static const struct webview_key_name webview_key_names[] = {
+      {VK_RETURN, "Enter"},    {VK_ESCAPE, "Escape"},   {VK_TAB, "Tab"},
+      {VK_BACK, "Backspace"},  {VK_DELETE, "Delete"},   {VK_INSERT, "Insert"},
+      {VK_HOME, "Home"},       {VK_END, "End"},         {VK_PRIOR, "PageUp"},
+      {VK_NEXT, "PageDown"},   {VK_LEFT, "ArrowLeft"},  {VK_RIGHT, "ArrowRight"},
+      {VK_UP, "ArrowUp"},      {VK_DOWN, "ArrowDown"},  {VK_SPACE, "Space"}};
+
+  // Returns the webview whose window holds the given window, or NULL if
+  // it's not one of ours
+  static struct webview *webview_for_window(HWND hwnd)
+  {
+    TCHAR name[16];
+    HWND root = GetAncestor(hwnd, GA_ROOT);
+    if (root == NULL || !GetClassName(root, name, 16) ||
+        lstrcmp(name, classname) != 0)
+    {
+      return NULL;
+    }
+    return (struct webview *)GetWindowLongPtr(root, GWLP_USERDATA);
+  }
+
+  // Gives a key pressed in one of the webview's windows to the webview
+  // before the browser translates it. Returns non-zero if it was consumed.
+  static int webview_key_message(MSG *msg)
+  {
+    char key[16] = "";
+    struct webview *w = webview_for_window(msg->hwnd);
+    WPARAM vk = msg->wParam;
+    if (w == NULL || !w->capture_keys || vk == VK_SHIFT || vk == VK_CONTROL ||
+        vk == VK_MENU || vk == VK_LWIN || vk == VK_RWIN)
+    {
+      return 0;
+    }
+    for (size_t i = 0; i < sizeof(webview_key_names) / sizeof(webview_key_names[0]); i++)
+    {
+      if (webview_key_names[i].code == vk)
+      {
+        strcpy(key, webview_key_names[i].name);
+      }
+    }
+    if (key[0] == '\0' && vk >= VK_F1 && vk <= VK_F24)
+    {
+      snprintf(key, sizeof(key), "F%d", (int)(vk - VK_F1 + 1));
+    }
+    // The high bit is set for dead keys
+    WCHAR c = (WCHAR)(MapVirtualKeyW((UINT)vk, MAPVK_VK_TO_CHAR) & 0x7FFF);
+    if (key[0] == '\0' && c != 0)
+    {
+      WCHAR lower[2] = {(WCHAR)(UINT_PTR)CharLowerW((LPWSTR)(UINT_PTR)c), 0};
+      WideCharToMultiByte(CP_UTF8, 0, lower, -1, key, sizeof(key), NULL, NULL);
+    }
+
+    int modifiers = 0;
+    modifiers |= GetKeyState(VK_SHIFT) < 0 ? WEBVIEW_KEY_SHIFT : 0;
+    modifiers |= GetKeyState(VK_CONTROL) < 0 ? WEBVIEW_KEY_CONTROL : 0;
+    modifiers |= GetKeyState(VK_MENU) < 0 ? WEBVIEW_KEY_ALT : 0;
+    modifiers |= (GetKeyState(VK_LWIN) < 0 || GetKeyState(VK_RWIN) < 0)
+                     ? WEBVIEW_KEY_SUPER
+                     : 0;
+    return webview_key_event(w, key, modifiers,
+                             msg->message == WM_KEYDOWN ||
+                                 msg->message == WM_SYSKEYDOWN);
+  }
+
+#ifndef WM_MOUSEHWHEEL
+#define WM_MOUSEHWHEEL 0x020E
+#endif
+
+  // Gives a side button click or sideways scroll in one of the webview's
+  // windows to the webview before the browser sees it. The browser goes
+  // back or forward when a side button is released. Returns non-zero if it
+  // was consumed.
+  static int webview_gesture_message(MSG *msg)
+  {
+    struct webview *w = webview_for_window(msg->hwnd);
+    if (w == NULL || !w->capture_gestures)
+    {
+      return 0;
+    }
+    switch (msg->message)
+    {
+    case WM_XBUTTONUP:
+      return webview_gesture_event(w,
+                                   GET_XBUTTON_WPARAM(msg->wParam) == XBUTTON1
+                                       ? WEBVIEW_GESTURE_BACK
+                                       : WEBVIEW_GESTURE_FORWARD,
+                                   0, 0);
+    case WM_MOUSEHWHEEL:
+      return webview_gesture_event(
+          w, WEBVIEW_GESTURE_SCROLL,
+          (double)GET_WHEEL_DELTA_WPARAM(msg->wParam) / WHEEL_DELTA, 0);
+    }
+    return 0;
+  }
+
+  WEBVIEW_API int webview_loop(struct webview *w, int blocking)
+  {
+    MSG msg;
+    if (blocking)
+    {
+      GetMessage(&msg, 0, 0, 0);
+    }
+    else if (!PeekMessage(&msg, 0, 0, 0, PM_REMOVE))
+    {
+      return 0;
+    }
+    if ((msg.message == WM_KEYDOWN || msg.message == WM_KEYUP ||
+         msg.message == WM_SYSKEYDOWN || msg.message == WM_SYSKEYUP) &&
+        webview_key_message(&msg))
+    {
+      return 0;
+    }
+    if ((msg.message == WM_XBUTTONUP || msg.message == WM_MOUSEHWHEEL) &&
+        webview_gesture_message(&msg))
+    {
+      return 0;
+    }
+    struct webview *root = webview_for_window(msg.hwnd);
+    if (root != NULL && root->priv.accelerators != NULL &&
+        TranslateAccelerator(root->priv.hwnd, root->priv.accelerators, &msg))
+    {
+      return 0;
+    }
+    switch (msg.message)
+    {
+    case WM_QUIT:
+      return -1;
+    case WM_COMMAND:
+    case WM_KEYDOWN:
+    case WM_KEYUP:
+    {
+      // Disable refresh when pressing F5 on windows
+      if (msg.wParam == VK_F5)
+      {
+        break;
+      }
+      HRESULT r = S_OK;
+      IWebBrowser2 *webBrowser2;
+      IOleObject *browser = *w->priv.browser;
+      if (browser->lpVtbl->QueryInterface(browser, iid_unref(&IID_IWebBrowser2),
+                                          (void **)&webBrowser2) == S_OK)
+      {
+        IOleInPlaceActiveObject *pIOIPAO;
+        if (browser->lpVtbl->QueryInterface(
+                browser, iid_unref(&IID_IOleInPlaceActiveObject),
+                (void **)&pIOIPAO) == S_OK)
+        {
+          r = pIOIPAO->lpVtbl->TranslateAccelerator(pIOIPAO, &msg);
+          pIOIPAO->lpVtbl->Release(pIOIPAO);
+        }
+        webBrowser2->lpVtbl->Release(webBrowser2);
+      }
+      if (r != S_FALSE)
+      {
+        break;
+      }
+    }
+    default:
+      TranslateMessage(&msg);
+      DispatchMessage(&msg);
+    }
+    return 0;
+  }
+
+  WEBVIEW_API int webview_eval(struct webview *w, const char *js)
+  {
+    IWebBrowser2 *webBrowser2;
+    IHTMLDocument2 *htmlDoc2;
+    IDispatch *docDispatch;
+    IDispatch *scriptDispatch;
+    if ((*w->priv.browser)
+            ->lpVtbl->QueryInterface((*w->priv.browser),
+                                     iid_unref(&IID_IWebBrowser2),
+                                     (void **)&webBrowser2) != S_OK)
+    {
+      return -1;
+    }
+
+    if (webBrowser2->lpVtbl->get_Document(webBrowser2, &docDispatch) != S_OK)
+    {
+      return -1;
+    }
+    if (docDispatch->lpVtbl->QueryInterface(docDispatch,
+                                            iid_unref(&IID_IHTMLDocument2),
+                                            (void **)&htmlDoc2) != S_OK)
+    {
+      return -1;
+    }
+    if (htmlDoc2->lpVtbl->get_Script(htmlDoc2, &scriptDispatch) != S_OK)
+    {
+      return -1;
+    }
+    DISPID dispid;
+    BSTR evalStr = SysAllocString(L"eval");
+    if (scriptDispatch->lpVtbl->GetIDsOfNames(
+            scriptDispatch, iid_unref(&IID_NULL), &evalStr, 1,
+            LOCALE_SYSTEM_DEFAULT, &dispid) != S_OK)
+    {
+      SysFreeString(evalStr);
+      return -1;
+    }
+    SysFreeString(evalStr);
+
+    DISPPARAMS params;
+    VARIANT arg;
+    VARIANT result;
+    EXCEPINFO excepInfo;
+    UINT nArgErr = (UINT)-1;
+    params.cArgs = 1;
+    params.cNamedArgs = 0;
+    params.rgvarg = &arg;
+    arg.vt = VT_BSTR;
+    static const char *prologue = "(function(){";
+    static const char *epilogue = ";})();";
+    int n = strlen(prologue) + strlen(epilogue) + strlen(js) + 1;
+    char *eval = (char *)malloc(n);
+    snprintf(eval, n, "%s%s%s", prologue, js, epilogue);
+    wchar_t *buf = webview_to_utf16(eval);
+    if (buf == NULL)
+    {
+      return -1;
+    }
+    arg.bstrVal = SysAllocString(buf);
+    if (scriptDispatch->lpVtbl->Invoke(
+            scriptDispatch, dispid, iid_unref(&IID_NULL), 0, DISPATCH_METHOD,
+            &params, &result, &excepInfo, &nArgErr) != S_OK)
+    {
+      return -1;
+    }
+    SysFreeString(arg.bstrVal);
+    free(eval);
+    scriptDispatch->lpVtbl->Release(scriptDispatch);
+    htmlDoc2->lpVtbl->Release(htmlDoc2);
+    docDispatch->lpVtbl->Release(docDispatch);
+    return 0;
+  }
+
+  WEBVIEW_API void webview_dispatch(struct webview *w, webview_dispatch_fn fn,
+                                    void *arg)
+  {
+    PostMessageW(w->priv.hwnd, WM_WEBVIEW_DISPATCH, (WPARAM)fn, (LPARAM)arg);
+  }
+
+  WEBVIEW_API void webview_set_title(struct webview *w, const char *title)
+  {
+#ifdef UNICODE
+    wchar_t *u16title = webview_to_utf16(title);
+    if (u16title == NULL)
+    {
+      return;
+    }
+    SetWindowText(w->priv.hwnd, u16title);
+    GlobalFree(u16title);
+#else
+    SetWindowText(w->priv.hwnd, title);
+#endif
+  }
+
+  WEBVIEW_API int webview_set_icon(struct webview *w, const uint8_t *data,
+                                   int size)
+  {
+    HICON icon = NULL;
+    HICON small_icon = NULL;
+    if (size > 0)
+    {
+      icon = webview_create_icon(data, size, GetSystemMetrics(SM_CXICON));
+      small_icon = webview_create_icon(data, size, GetSystemMetrics(SM_CXSMICON));
+      if (icon == NULL || small_icon == NULL)
+      {
+        if (icon != NULL)
+        {
+          DestroyIcon(icon);
+        }
+        if (small_icon != NULL)
+        {
+          DestroyIcon(small_icon);
+        }
+        return -1;
+      }
+    }
+
+    // Without an icon, the window goes back to the app's icon
+    SendMessage(w->priv.hwnd, WM_SETICON, ICON_BIG,
+                (LPARAM)(icon != NULL ? icon : (HICON)GetClassLongPtr(w->priv.hwnd, GCLP_HICON)));
+    SendMessage(w->priv.hwnd, WM_SETICON, ICON_SMALL,
+                (LPARAM)(small_icon != NULL ? small_icon : (HICON)GetClassLongPtr(w->priv.hwnd, GCLP_HICONSM)));
+    webview_destroy_icons(w);
+    w->priv.icon = icon;
+    w->priv.small_icon = small_icon;
+    return 0;
+  }
+
+  // Windows has no standard about dialog, so a message box is shown. Its
+  // icon is replaced when it's activated, as message boxes only take icons
+  // from resources.
+  static HHOOK webview_about_hook = NULL;
+  static HICON webview_about_icon = NULL;
+
+  static LRESULT CALLBACK webview_about_hook_proc(int code, WPARAM wparam,
+                                                  LPARAM lparam)
+  {
+    if (code != HCBT_ACTIVATE)
+    {
+      return CallNextHookEx(webview_about_hook, code, wparam, lparam);
+    }
+    // 20 is the ID of the message box's icon
+    SendDlgItemMessageW((HWND)wparam, 20, STM_SETICON,
+                        (WPARAM)webview_about_icon, 0);
+    UnhookWindowsHookEx(webview_about_hook);
+    webview_about_hook = NULL;
+    return 0;
+  }
+
+  WEBVIEW_API void webview_show_about(struct webview *w)
+  {
+    MSGBOXPARAMSW params;
+    WCHAR title[256];
+    WCHAR *name = NULL;
+    WCHAR *version = NULL;
+    WCHAR *copyright = NULL;
+    WCHAR caption[320];
+    WCHAR text[1024];
+
+    GetWindowTextW(w->priv.hwnd, title, 256);
+    if (webview_about.name != NULL)
+    {
+      name = webview_to_utf16(webview_about.name);
+    }
+    _snwprintf(caption, 320, L"About %ls", name != NULL ? name : title);
+    caption[319] = L'\0';
+    _snwprintf(text, 1024, L"%ls", name != NULL ? name : title);
+    if (webview_about.version != NULL)
+    {
+      version = webview_to_utf16(webview_about.version);
+      _snwprintf(text + wcslen(text), 1024 - wcslen(text), L"\nVersion %ls",
+                 version);
+    }
+    if (webview_about.copyright != NULL)
+    {
+      copyright = webview_to_utf16(webview_about.copyright);
+      _snwprintf(text + wcslen(text), 1024 - wcslen(text), L"\n\n%ls",
+                 copyright);
+    }
+    text[1023] = L'\0';
+
+    // The app's icon is resource 100, as set on the window class
+    ZeroMemory(&params, sizeof(params));
+    params.cbSize = sizeof(params);
+    params.hwndOwner = w->priv.hwnd;
+    params.hInstance = GetModuleHandle(NULL);
+    params.lpszText = text;
+    params.lpszCaption = caption;
+    params.dwStyle = MB_OK | MB_USERICON;
+    params.lpszIcon = MAKEINTRESOURCEW(100);
+    if (webview_about.iconsz > 0)
+    {
+      webview_about_icon = webview_create_icon(
+          webview_about.icon, webview_about.iconsz, GetSystemMetrics(SM_CXICON));
+    }
+    if (webview_about_icon != NULL)
+    {
+      webview_about_hook = SetWindowsHookExW(WH_CBT, webview_about_hook_proc,
+                                             NULL, GetCurrentThreadId());
+    }
+    MessageBoxIndirectW(&params);
+    if (webview_about_hook != NULL)
+    {
+      UnhookWindowsHookEx(webview_about_hook);
+      webview_about_hook = NULL;
+    }
+    if (webview_about_icon != NULL)
+    {
+      DestroyIcon(webview_about_icon);
+      webview_about_icon = NULL;
+    }
+    GlobalFree(name);
+    GlobalFree(version);
+    GlobalFree(copyright);
+  }
+
+#define WEBVIEW_TABLET_DISABLE_PRESSANDHOLD 0x00000001
+#define WEBVIEW_TABLET_DISABLE_PENTAPFEEDBACK 0x00000008
+#define WEBVIEW_TABLET_DISABLE_PENBARRELFEEDBACK 0x00000010
+#define WEBVIEW_TABLET_DISABLE_FLICKS 0x00010000
+
+  // The pen service reads the gestures to turn off from a property of the
+  // window under the pen
+  static BOOL CALLBACK webview_set_pen_property(HWND hwnd, LPARAM flags)
+  {
+    if (flags != 0)
+    {
+      SetPropW(hwnd, L"MicrosoftTabletPenServiceProperty", (HANDLE)flags);
+    }
+    else
+    {
+      RemovePropW(hwnd, L"MicrosoftTabletPenServiceProperty");
+    }
+    return TRUE;
+  }
+
+  // The browser's windows are inside the window, so they're all given the
+  // property
+  WEBVIEW_API void webview_set_system_gestures(struct webview *w, int enabled)
+  {
+    LPARAM flags = 0;
+    if (!enabled)
+    {
+      flags = WEBVIEW_TABLET_DISABLE_PRESSANDHOLD |
+              WEBVIEW_TABLET_DISABLE_PENTAPFEEDBACK |
+              WEBVIEW_TABLET_DISABLE_PENBARRELFEEDBACK |
+              WEBVIEW_TABLET_DISABLE_FLICKS;
+    }
+    webview_set_pen_property(w->priv.hwnd, flags);
+    EnumChildWindows(w->priv.hwnd, webview_set_pen_property, flags);
+  }
+
+  typedef HRESULT(WINAPI *webview_sh_create_data_object_t)(
+      const void *, UINT, const void **, IDataObject *, REFIID, void **);
+  typedef HRESULT(WINAPI *webview_sh_do_drag_drop_t)(HWND, IDataObject *,
+                                                      IDropSource *, DWORD,
+                                                      DWORD *);
+
+  // The shell's data object and drop source show the files' icons while
+  // they're dragged. SHDoDragDrop returns once they're dropped, and as it
+  // drops straight away if no button is held, that is checked first.
+  WEBVIEW_API int webview_start_drag(struct webview *w, const char *files)
+  {
+    int button = GetSystemMetrics(SM_SWAPBUTTON) ? VK_RBUTTON : VK_LBUTTON;
+    if (!(GetAsyncKeyState(button) & 0x8000))
+    {
+      return 0;
+    }
+    HMODULE shell32 = GetModuleHandleW(L"shell32.dll");
+    webview_sh_create_data_object_t createDataObject =
+        (webview_sh_create_data_object_t)GetProcAddress(shell32,
+                                                        "SHCreateDataObject");
+    webview_sh_do_drag_drop_t doDragDrop =
+        (webview_sh_do_drag_drop_t)GetProcAddress(shell32, "SHDoDragDrop");
+    if (createDataObject == NULL || doDragDrop == NULL)
+    {
+      return 0;
+    }
+
+    // CF_HDROP is a DROPFILES followed by the paths, each ending with a null,
+    // and another null after the last one
+    WCHAR *paths = webview_to_utf16(files);
+    if (paths == NULL)
+    {
+      return 0;
+    }
+    size_t length = wcslen(paths);
+    for (size_t i = 0; i < length; i++)
+    {
+      if (paths[i] == L'\n')
+      {
+        paths[i] = L'\0';
+      }
+    }
+    HGLOBAL global = GlobalAlloc(GMEM_MOVEABLE | GMEM_ZEROINIT,
+                                 sizeof(DROPFILES) + (length + 2) * sizeof(WCHAR));
+    DROPFILES *drop = (DROPFILES *)GlobalLock(global);
+    if (drop == NULL)
+    {
+      GlobalFree(global);
+      GlobalFree(paths);
+      return 0;
+    }
+    drop->pFiles = sizeof(DROPFILES);
+    drop->fWide = TRUE;
+    memcpy((char *)drop + sizeof(DROPFILES), paths, length * sizeof(WCHAR));
+    GlobalUnlock(global);
+    GlobalFree(paths);
+
+    IDataObject *data = NULL;
+    if (createDataObject(NULL, 0, NULL, NULL, iid_unref(&IID_IDataObject),
+                         (void **)&data) != S_OK)
+    {
+      GlobalFree(global);
+      return 0;
+    }
+    FORMATETC format = {CF_HDROP, NULL, DVASPECT_CONTENT, -1, TYMED_HGLOBAL};
+    STGMEDIUM medium = {0};
+    medium.tymed = TYMED_HGLOBAL;
+    medium.hGlobal = global;
+    if (data->lpVtbl->SetData(data, &format, &medium, TRUE) != S_OK)
+    {
+      GlobalFree(global);
+      data->lpVtbl->Release(data);
+      return 0;
+    }
+    DWORD effect = DROPEFFECT_NONE;
+    HRESULT result =
+        doDragDrop(w->priv.hwnd, data, NULL, DROPEFFECT_COPY, &effect);
+    data->lpVtbl->Release(data);
+    return SUCCEEDED(result);
+  }
+
+  WEBVIEW_API void webview_focus(struct webview *w)
+  {
+    SetFocus(w->priv.hwnd);
+  }
+
+  WEBVIEW_API void webview_reload(struct webview *w)
+  {
+    IWebBrowser2 *webBrowser2;
+    IOleObject *browser = *w->priv.browser;
+    if (browser->lpVtbl->QueryInterface(browser, iid_unref(&IID_IWebBrowser2),
+                                        (void **)&webBrowser2) == S_OK)
+    {
+      webBrowser2->lpVtbl->Refresh(webBrowser2);
+      webBrowser2->lpVtbl->Release(webBrowser2);
+    }
+  }
+
+  WEBVIEW_API void webview_navigate(struct webview *w, const char *url)
+  {
+    w->url = url;
+    DisplayHTMLPage(w);
+  }
+
+  WEBVIEW_API void webview_minsize(struct webview *w, int width, int height) {
+    w->priv.min_width = width;
+    w->priv.min_height = height;
+  }
+
+  WEBVIEW_API void webview_maxsize(struct webview *w, int width, int height) {
+    w->priv.max_width = width;
+    w->priv.max_height = height;
+  }
+
+  // The aspect ratio is kept by WM_SIZING
+  WEBVIEW_API void webview_set_aspect_ratio(struct webview *w, int width,
+                                            int height)
+  {
+    w->priv.aspect = width > 0 && height > 0 ? (double)width / height : 0;
+  }
+
+  WEBVIEW_API void webview_set_fullscreen(struct webview *w, int fullscreen)
+  {
+    if (w->priv.is_fullscreen == !!fullscreen)
+    {
+      return;
+    }
+    if (w->priv.is_fullscreen == 0)
+    {
+      w->priv.saved_style = GetWindowLong(w->priv.hwnd, GWL_STYLE);
+      w->priv.saved_ex_style = GetWindowLong(w->priv.hwnd, GWL_EXSTYLE);
+      GetWindowRect(w->priv.hwnd, &w->priv.saved_rect);
+    }
+    w->priv.is_fullscreen = !!fullscreen;
+    if (fullscreen)
+    {
+      MONITORINFO monitor_info;
+      SetWindowLong(w->priv.hwnd, GWL_STYLE,
+                    w->priv.saved_style & ~(WS_CAPTION | WS_THICKFRAME));
+      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE,
+                    w->priv.saved_ex_style &
+                        ~(WS_EX_DLGMODALFRAME | WS_EX_WINDOWEDGE |
+                          WS_EX_CLIENTEDGE | WS_EX_STATICEDGE));
+      monitor_info.cbSize = sizeof(monitor_info);
+      GetMonitorInfo(MonitorFromWindow(w->priv.hwnd, MONITOR_DEFAULTTONEAREST),
+                     &monitor_info);
+      RECT r;
+      r.left = monitor_info.rcMonitor.left;
+      r.top = monitor_info.rcMonitor.top;
+      r.right = monitor_info.rcMonitor.right;
+      r.bottom = monitor_info.rcMonitor.bottom;
+      SetWindowPos(w->priv.hwnd, NULL, r.left, r.top, r.right - r.left,
+                   r.bottom - r.top,
+                   SWP_NOZORDER | SWP_NOACTIVATE | SWP_FRAMECHANGED);
+    }
+    else
+    {
+      SetWindowLong(w->priv.hwnd, GWL_STYLE, w->priv.saved_style);
+      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE, w->priv.saved_ex_style);
+      SetWindowPos(w->priv.hwnd, NULL, w->priv.saved_rect.left,
+                   w->priv.saved_rect.top,
+                   w->priv.saved_rect.right - w->priv.saved_rect.left,
+                   w->priv.saved_rect.bottom - w->priv.saved_rect.top,
+                   SWP_NOZORDER | SWP_NOACTIVATE | SWP_FRAMECHANGED);
+    }
+  }
+
+  WEBVIEW_API void webview_set_always_on_top(struct webview *w, int onTop)
+  {
+    SetWindowPos(w->priv.hwnd, onTop ? HWND_TOPMOST : HWND_NOTOPMOST, 0, 0, 0, 0,
+                 SWP_NOMOVE | SWP_NOSIZE | SWP_NOACTIVATE);
+  }
+
+  WEBVIEW_API void webview_set_borderless(struct webview *w, int borderless)
+  {
+    DWORD style = GetWindowLong(w->priv.hwnd, GWL_STYLE);
+    if (borderless)
+    {
+      style = style & ~(WS_CAPTION | WS_THICKFRAME);
+    }
+    else
+    {
+      style = style | WS_CAPTION;
+      if (w->resizable)
+      {
+        style = style | WS_THICKFRAME;
+      }
+    }
+    SetWindowLong(w->priv.hwnd, GWL_STYLE, style);
+    SetWindowPos(w->priv.hwnd, NULL, 0, 0, 0, 0,
+                 SWP_NOMOVE | SWP_NOSIZE | SWP_NOZORDER | SWP_NOACTIVATE |
+                     SWP_FRAMECHANGED);
+  }
+
+  WEBVIEW_API void webview_set_size(struct webview *w, int width, int height)
+  {
+    RECT rect;
+    double scale = webview_scale(w);
+    rect.left = 0;
+    rect.top = 0;
+    rect.right = scale * width;
+    rect.bottom = scale * height;
+    AdjustWindowRect(&rect, GetWindowLong(w->priv.hwnd, GWL_STYLE), 0);
+    SetWindowPos(w->priv.hwnd, NULL, 0, 0, rect.right - rect.left,
+                 rect.bottom - rect.top,
+                 SWP_NOMOVE | SWP_NOZORDER | SWP_NOACTIVATE);
+  }
+
+  WEBVIEW_API void webview_snap_to_corner(struct webview *w, int corner, int margin)
+  {
+    MONITORINFO monitor_info;
+    RECT r;
+    int x, y;
+    monitor_info.cbSize = sizeof(monitor_info);
+    GetMonitorInfo(MonitorFromWindow(w->priv.hwnd, MONITOR_DEFAULTTONEAREST),
+                   &monitor_info);
+    GetWindowRect(w->priv.hwnd, &r);
+    webview_corner_position(corner, margin, monitor_info.rcWork.left,
+                            monitor_info.rcWork.top,
+                            monitor_info.rcWork.right - monitor_info.rcWork.left,
+                            monitor_info.rcWork.bottom - monitor_info.rcWork.top,
+                            r.right - r.left, r.bottom - r.top, &x, &y);
+    SetWindowPos(w->priv.hwnd, NULL, x, y, 0, 0,
+                 SWP_NOSIZE | SWP_NOZORDER | SWP_NOACTIVATE);
+  }
+
+  WEBVIEW_API void webview_set_ignore_mouse_events(struct webview *w, int ignore)
+  {
+    // Transparent layered windows pass mouse input to the windows below
+    DWORD exStyle = GetWindowLong(w->priv.hwnd, GWL_EXSTYLE);
+    if (ignore)
+    {
+      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE,
+                    exStyle | WS_EX_LAYERED | WS_EX_TRANSPARENT);
+      if (!w->transparent)
+      {
+        webview_update_layered(w);
+      }
+    }
+    else if (w->transparent || w->priv.transparency > 0)
+    {
+      // Transparent and faded windows stay layered
+      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE, exStyle & ~WS_EX_TRANSPARENT);
+    }
+    else
+    {
+      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE,
+                    exStyle & ~(WS_EX_LAYERED | WS_EX_TRANSPARENT));
+    }
+  }
+
+  WEBVIEW_API void webview_cursor_position(struct webview *w, int *x, int *y)
+  {
+    POINT p;
+    double scale = webview_scale(w);
+    GetCursorPos(&p);
+    ScreenToClient(w->priv.hwnd, &p);
+    *x = p.x / scale;
+    *y = p.y / scale;
+  }
+
+#define WEBVIEW_MAX_MONITORS 16
+
+  struct webview_monitors
+  {
+    HMONITOR monitors[WEBVIEW_MAX_MONITORS];
+    int count;
+  };
+
+  static BOOL CALLBACK webview_enum_monitor(HMONITOR monitor, HDC hdc,
+                                            LPRECT rect, LPARAM data)
+  {
+    struct webview_monitors *m = (struct webview_monitors *)data;
+    if (m->count < WEBVIEW_MAX_MONITORS)
+    {
+      m->monitors[m->count++] = monitor;
+    }
+    return TRUE;
+  }
+
+  static void webview_get_monitors(struct webview_monitors *m)
+  {
+    m->count = 0;
+    EnumDisplayMonitors(NULL, NULL, webview_enum_monitor, (LPARAM)m);
+  }
+
+  // Returns the given monitor or, if it doesn't exist, the one the window is on
+  static HMONITOR webview_monitor(struct webview *w, int display)
+  {
+    struct webview_monitors m;
+    webview_get_monitors(&m);
+    if (display >= 0 && display < m.count)
+    {
+      return m.monitors[display];
+    }
+    return MonitorFromWindow(w->priv.hwnd, MONITOR_DEFAULTTONEAREST);
+  }
+
+  WEBVIEW_API int webview_display_count(struct webview *w)
+  {
+    struct webview_monitors m;
+    webview_get_monitors(&m);
+    return m.count;
+  }
+
+  WEBVIEW_API void webview_display_workarea(struct webview *w, int display, int *x,
+                                            int *y, int *width, int *height)
+  {
+    MONITORINFO monitor_info;
+    monitor_info.cbSize = sizeof(monitor_info);
+    GetMonitorInfo(webview_monitor(w, display), &monitor_info);
+    *x = monitor_info.rcWork.left;
+    *y = monitor_info.rcWork.top;
+    *width = monitor_info.rcWork.right - monitor_info.rcWork.left;
+    *height = monitor_info.rcWork.bottom - monitor_info.rcWork.top;
+  }
+
+  WEBVIEW_API void webview_display_bounds(struct webview *w, int display, int *x,
+                                         int *y, int *width, int *height,
+                                         double *scale, int *primary)
+  {
+    MONITORINFOEX monitor_info;
+    monitor_info.cbSize = sizeof(monitor_info);
+    GetMonitorInfo(webview_monitor(w, display), (LPMONITORINFO)&monitor_info);
+    *x = monitor_info.rcMonitor.left;
+    *y = monitor_info.rcMonitor.top;
+    *width = monitor_info.rcMonitor.right - monitor_info.rcMonitor.left;
+    *height = monitor_info.rcMonitor.bottom - monitor_info.rcMonitor.top;
+    *primary = (monitor_info.dwFlags & MONITORINFOF_PRIMARY) != 0;
+
+    // GetDpiForMonitor needs Windows 8.1
+    typedef HRESULT(WINAPI * GetDpiForMonitorFunc)(HMONITOR, int, UINT *, UINT *);
+    HMODULE shcore = LoadLibrary(TEXT("shcore.dll"));
+    GetDpiForMonitorFunc getDpiForMonitor =
+        shcore != NULL
+            ? (GetDpiForMonitorFunc)GetProcAddress(shcore, "GetDpiForMonitor")
+            : NULL;
+    UINT dpiX, dpiY;
+    if (getDpiForMonitor != NULL &&
+        getDpiForMonitor(webview_monitor(w, display), 0, &dpiX, &dpiY) == S_OK)
+    {
+      *scale = dpiX / 96.0;
+    }
+    else
+    {
+      HDC hDC = CreateDC(TEXT("DISPLAY"), monitor_info.szDevice, NULL, NULL);
+      *scale = GetDeviceCaps(hDC, LOGPIXELSX) / 96.0;
+      DeleteDC(hDC);
+    }
+    if (shcore != NULL)
+    {
+      FreeLibrary(shcore);
+    }
+  }
+
+  WEBVIEW_API int webview_cursor_display(struct webview *w)
+  {
+    struct webview_monitors m;
+    POINT p;
+    int i;
+    GetCursorPos(&p);
+    HMONITOR monitor = MonitorFromPoint(p, MONITOR_DEFAULTTONEAREST);
+    webview_get_monitors(&m);
+    for (i = 0; i < m.count; i++)
+    {
+      if (m.monitors[i] == monitor)
+      {
+        return i;
+      }
+    }
+    return 0;
+  }
+
+  WEBVIEW_API double webview_get_scale(struct webview *w)
+  {
+    return webview_scale(w);
+  }
+
+  WEBVIEW_API void webview_place(struct webview *w, int display, int edge, int margin)
+  {
+    int x, y, width, height;
+    RECT r;
+    webview_display_workarea(w, display, &x, &y, &width, &height);
+    GetWindowRect(w->priv.hwnd, &r);
+    webview_edge_position(edge, margin, x, y, width, height, r.right - r.left,
+                          r.bottom - r.top, &x, &y);
+    SetWindowPos(w->priv.hwnd, NULL, x, y, 0, 0,
+                 SWP_NOSIZE | SWP_NOZORDER | SWP_NOACTIVATE);
+  }
+
+  WEBVIEW_API void webview_set_position(struct webview *w, int x, int y)
+  {
+    SetWindowPos(w->priv.hwnd, NULL, x, y, 0, 0,
+                 SWP_NOSIZE | SWP_NOZORDER | SWP_NOACTIVATE);
+  }
+
+  WEBVIEW_API void webview_get_position(struct webview *w, int *x, int *y)
+  {
+    RECT r;
+    GetWindowRect(w->priv.hwnd, &r);
+    *x = r.left;
+    *y = r.top;
+  }
+
+  // The size is of the client area, in the same units as webview_set_size
+  WEBVIEW_API void webview_get_size(struct webview *w, int *width, int *height)
+  {
+    RECT rect;
+    double scale = webview_scale(w);
+    GetClientRect(w->priv.hwnd, &rect);
+    *width = rect.right / scale;
+    *height = rect.bottom / scale;
+  }
+
+  WEBVIEW_API void webview_set_maximised(struct webview *w, int maximised)
+  {
+    ShowWindow(w->priv.hwnd, maximised ? SW_MAXIMIZE : SW_RESTORE);
+  }
+
+  WEBVIEW_API int webview_is_maximised(struct webview *w)
+  {
+    return IsZoomed(w->priv.hwnd);
+  }
+
+  WEBVIEW_API void webview_set_minimised(struct webview *w, int minimised)
+  {
+    if (minimised)
+    {
+      ShowWindow(w->priv.hwnd, SW_MINIMIZE);
+    }
+    else if (IsIconic(w->priv.hwnd))
+    {
+      ShowWindow(w->priv.hwnd, SW_RESTORE);
+    }
+  }
+
+  WEBVIEW_API void webview_set_visible(struct webview *w, int visible)
+  {
+    if (visible)
+    {
+      ShowWindow(w->priv.hwnd, IsIconic(w->priv.hwnd) ? SW_RESTORE : SW_SHOW);
+      SetForegroundWindow(w->priv.hwnd);
+      SetFocus(w->priv.hwnd);
+    }
+    else
+    {
+      ShowWindow(w->priv.hwnd, SW_HIDE);
+    }
+  }
+
+  WEBVIEW_API void webview_set_traffic_light_position(struct webview *w, int x, int y) {}
+
+  // The caption buttons are drawn outside of the client area
+  WEBVIEW_API void webview_titlebar_button_area(struct webview *w, int *x, int *y,
+                                                int *width, int *height)
+  {
+    *x = *y = *width = *height = 0;
+  }
+
+  // Caption colours are supported from Windows 11
+  #define WEBVIEW_DWMWA_CAPTION_COLOR 35
+  #define WEBVIEW_DWMWA_TEXT_COLOR 36
+  typedef HRESULT(WINAPI *DwmSetWindowAttributeFunc)(HWND, DWORD, LPCVOID, DWORD);
+
+  WEBVIEW_API void webview_set_titlebar_color(struct webview *w, uint8_t r,
+                                              uint8_t g, uint8_t b, uint8_t sr,
+                                              uint8_t sg, uint8_t sb)
+  {
+    HMODULE dwmapi = LoadLibraryA("dwmapi.dll");
+    if (dwmapi == NULL)
+    {
+      return;
+    }
+    DwmSetWindowAttributeFunc setAttribute =
+        (DwmSetWindowAttributeFunc)GetProcAddress(dwmapi, "DwmSetWindowAttribute");
+    if (setAttribute != NULL)
+    {
+      COLORREF caption = RGB(r, g, b);
+      COLORREF text = RGB(sr, sg, sb);
+      setAttribute(w->priv.hwnd, WEBVIEW_DWMWA_CAPTION_COLOR, &caption,
+                   sizeof(caption));
+      setAttribute(w->priv.hwnd, WEBVIEW_DWMWA_TEXT_COLOR, &text, sizeof(text));
+    }
+    FreeLibrary(dwmapi);
+  }
+
+  // System backdrops are supported from Windows 11 22H2
+  #define WEBVIEW_DWMWA_SYSTEMBACKDROP_TYPE 38
+  typedef HRESULT(WINAPI *DwmExtendFrameIntoClientAreaFunc)(HWND,
+                                                            const MARGINS *);
+  typedef LONG(WINAPI *RtlGetVersionFunc)(OSVERSIONINFOW *);
+
+  // The names of the system backdrops, in the order of their
+  // DWM_SYSTEMBACKDROP_TYPE values from 1
+  static const char *webview_materials[] = {"none", "mica", "acrylic",
+                                            "tabbed"};
+
+  // Returns the build number of Windows, which GetVersionEx hides
+  static DWORD webview_windows_build()
+  {
+    OSVERSIONINFOW version = {sizeof(OSVERSIONINFOW)};
+    RtlGetVersionFunc getVersion = (RtlGetVersionFunc)GetProcAddress(
+        GetModuleHandle(TEXT("ntdll.dll")), "RtlGetVersion");
+    if (getVersion == NULL || getVersion(&version) != 0)
+    {
+      return 0;
+    }
+    return version.dwBuildNumber;
+  }
+
+  WEBVIEW_API int webview_material_supported(struct webview *w,
+                                             const char *material)
+  {
+    (void)w;
+    for (int i = 0; i < 4; i++)
+    {
+      if (strcmp(material, webview_materials[i]) == 0)
+      {
+        return i == 0 || webview_windows_build() >= 22621;
+      }
+    }
+    return 0;
+  }
+
+  // The frame is extended into the whole window so the material shows
+  // wherever the page doesn't paint, as well as in the title bar
+  WEBVIEW_API int webview_set_material(struct webview *w, const char *material)
+  {
+    int type = 0;
+    for (int i = 0; i < 4; i++)
+    {
+      if (strcmp(material, webview_materials[i]) == 0)
+      {
+        type = i + 1;
+      }
+    }
+    if (type == 0 || !webview_material_supported(w, material))
+    {
+      return 0;
+    }
+    HMODULE dwmapi = LoadLibraryA("dwmapi.dll");
+    if (dwmapi == NULL)
+    {
+      return 0;
+    }
+    DwmSetWindowAttributeFunc setAttribute =
+        (DwmSetWindowAttributeFunc)GetProcAddress(dwmapi, "DwmSetWindowAttribute");
+    DwmExtendFrameIntoClientAreaFunc extendFrame =
+        (DwmExtendFrameIntoClientAreaFunc)GetProcAddress(
+            dwmapi, "DwmExtendFrameIntoClientArea");
+    HRESULT result = E_FAIL;
+    if (setAttribute != NULL && extendFrame != NULL)
+    {
+      MARGINS margins = {0, 0, 0, 0};
+      if (type != 1)
+      {
+        margins.cxLeftWidth = -1;
+      }
+      extendFrame(w->priv.hwnd, &margins);
+      result = setAttribute(w->priv.hwnd, WEBVIEW_DWMWA_SYSTEMBACKDROP_TYPE,
+                            &type, sizeof(type));
+    }
+    FreeLibrary(dwmapi);
+    return SUCCEEDED(result);
+  }
+
+  WEBVIEW_API int webview_save_dialog(struct webview *w, const char *title,
+                                      const char *directory,
+                                      const char *filename,
+                                      const char *formats, int flags,
+                                      char *result, size_t resultsz)
+  {
+    IFileSaveDialog *dlg = NULL;
+    IShellItem *res = NULL;
+    WCHAR *ws = NULL;
+    WCHAR *wtitle = NULL;
+    COMDLG_FILTERSPEC *specs = NULL;
+    FILEOPENDIALOGOPTIONS opts;
+    UINT type = 0;
+    int count = 0;
+    int format = -1;
+    int i;
+    char *formats_dup = strdup(formats);
+    char *line;
+    result[0] = '\0';
+
+    // The Windows dialog always allows creating directories
+    for (i = 0; formats[i]; i++)
+    {
+      if (formats[i] == '\n')
+      {
+        count++;
+      }
+    }
+    specs = (COMDLG_FILTERSPEC *)calloc(count + 1, sizeof(COMDLG_FILTERSPEC));
+    count = 0;
+    for (line = strtok(formats_dup, "\n"); line != NULL; line = strtok(NULL, "\n"))
+    {
+      char *separator = strchr(line, '|');
+      if (separator == NULL)
+      {
+        continue;
+      }
+      *separator = '\0';
+      specs[count].pszName = webview_to_utf16(line);
+      specs[count].pszSpec = webview_to_utf16(separator + 1);
+      count++;
+    }
+
+    if (CoCreateInstance(iid_unref(&CLSID_FileSaveDialog), NULL,
+                         CLSCTX_INPROC_SERVER, iid_unref(&IID_IFileSaveDialog),
+                         (void **)&dlg) != S_OK)
+    {
+      goto error_specs;
+    }
+    if (count > 0)
+    {
+      dlg->lpVtbl->SetFileTypes(dlg, count, specs);
+      // Make the dialog append the extension of the chosen type
+      const WCHAR *extension = wcsrchr(specs[0].pszSpec, L'.');
+      if (extension != NULL)
+      {
+        dlg->lpVtbl->SetDefaultExtension(dlg, extension + 1);
+      }
+    }
+    wtitle = webview_to_utf16(title);
+    if (wtitle != NULL)
+    {
+      dlg->lpVtbl->SetTitle(dlg, wtitle);
+    }
+    if (directory[0] != '\0')
+    {
+      WCHAR *wdirectory = webview_to_utf16(directory);
+      IShellItem *folder = NULL;
+      if (wdirectory != NULL)
+      {
+        if (SHCreateItemFromParsingName(wdirectory, NULL,
+                                        iid_unref(&IID_IShellItem),
+                                        (void **)&folder) == S_OK)
+        {
+          dlg->lpVtbl->SetFolder(dlg, folder);
+          folder->lpVtbl->Release(folder);
+        }
+        GlobalFree(wdirectory);
+      }
+    }
+    if (filename[0] != '\0')
+    {
+      WCHAR *wfilename = webview_to_utf16(filename);
+      if (wfilename != NULL)
+      {
+        dlg->lpVtbl->SetFileName(dlg, wfilename);
+        GlobalFree(wfilename);
+      }
+    }
+    if (dlg->lpVtbl->GetOptions(dlg, &opts) != S_OK)
+    {
+      goto error_dlg;
+    }
+    opts |= FOS_NOCHANGEDIR | FOS_FORCESHOWHIDDEN | FOS_DEFAULTNOMINIMODE;
+    opts &= ~FOS_OVERWRITEPROMPT;
+    if (flags & WEBVIEW_SAVE_FLAG_CONFIRM_OVERWRITE)
+    {
+      opts |= FOS_OVERWRITEPROMPT;
+    }
+    if (dlg->lpVtbl->SetOptions(dlg, opts) != S_OK)
+    {
+      goto error_dlg;
+    }
+    if (dlg->lpVtbl->Show(dlg, w->priv.hwnd) != S_OK)
+    {
+      goto error_dlg;
+    }
+    if (dlg->lpVtbl->GetResult(dlg, &res) != S_OK)
+    {
+      goto error_dlg;
+    }
+    if (res->lpVtbl->GetDisplayName(res, SIGDN_FILESYSPATH, &ws) == S_OK)
+    {
+      char *s = webview_from_utf16(ws);
+      strncpy(result, s, resultsz);
+      result[resultsz - 1] = '\0';
+      GlobalFree(s);
+      CoTaskMemFree(ws);
+      // File type indexes are 1 based
+      if (dlg->lpVtbl->GetFileTypeIndex(dlg, &type) == S_OK && type > 0)
+      {
+        format = type - 1;
+      }
+    }
+    res->lpVtbl->Release(res);
+  error_dlg:
+    dlg->lpVtbl->Release(dlg);
+  error_specs:
+    for (i = 0; i < count; i++)
+    {
+      GlobalFree((HGLOBAL)specs[i].pszName);
+      GlobalFree((HGLOBAL)specs[i].pszSpec);
+    }
+    free(specs);
+    free(formats_dup);
+    if (wtitle != NULL)
+    {
+      GlobalFree(wtitle);
+    }
+    return format;
+  }
+
+  // Returns the selected files or directories separated by newlines
+  WEBVIEW_API void webview_open_dialog(struct webview *w, const char *title,
+                                       const char *directory, const char *formats,
+                                       int flags, char *result, size_t resultsz)
+  {
+    IFileOpenDialog *dlg = NULL;
+    IShellItemArray *items = NULL;
+    COMDLG_FILTERSPEC *specs = NULL;
+    FILEOPENDIALOGOPTIONS opts;
+    DWORD count = 0;
+    DWORD i;
+    int nspecs = 0;
+    int j;
+    WCHAR *wtitle = NULL;
+    char *formats_dup = strdup(formats);
+    char *line;
+    result[0] = '\0';
+
+    for (j = 0; formats[j]; j++)
+    {
+      if (formats[j] == '\n')
+      {
+        nspecs++;
+      }
+    }
+    specs = (COMDLG_FILTERSPEC *)calloc(nspecs + 1, sizeof(COMDLG_FILTERSPEC));
+    nspecs = 0;
+    for (line = strtok(formats_dup, "\n"); line != NULL; line = strtok(NULL, "\n"))
+    {
+      char *separator = strchr(line, '|');
+      if (separator == NULL)
+      {
+        continue;
+      }
+      *separator = '\0';
+      specs[nspecs].pszName = webview_to_utf16(line);
+      specs[nspecs].pszSpec = webview_to_utf16(separator + 1);
+      nspecs++;
+    }
+
+    if (CoCreateInstance(iid_unref(&CLSID_FileOpenDialog), NULL,
+                         CLSCTX_INPROC_SERVER, iid_unref(&IID_IFileOpenDialog),
+                         (void **)&dlg) != S_OK)
+    {
+      goto error_specs;
+    }
+    if (nspecs > 0)
+    {
+      dlg->lpVtbl->SetFileTypes(dlg, nspecs, specs);
+    }
+    wtitle = webview_to_utf16(title);
+    if (wtitle != NULL)
+    {
+      dlg->lpVtbl->SetTitle(dlg, wtitle);
+      GlobalFree(wtitle);
+    }
+    if (directory[0] != '\0')
+    {
+      WCHAR *wdirectory = webview_to_utf16(directory);
+      IShellItem *folder = NULL;
+      if (wdirectory != NULL)
+      {
+        if (SHCreateItemFromParsingName(wdirectory, NULL,
+                                        iid_unref(&IID_IShellItem),
+                                        (void **)&folder) == S_OK)
+        {
+          dlg->lpVtbl->SetFolder(dlg, folder);
+          folder->lpVtbl->Release(folder);
+        }
+        GlobalFree(wdirectory);
+      }
+    }
+    if (dlg->lpVtbl->GetOptions(dlg, &opts) != S_OK)
+    {
+      goto error_dlg;
+    }
+    opts |= FOS_NOCHANGEDIR | FOS_FILEMUSTEXIST | FOS_FORCESHOWHIDDEN;
+    if (flags & WEBVIEW_OPEN_FLAG_MULTIPLE)
+    {
+      opts |= FOS_ALLOWMULTISELECT;
+    }
+    if (flags & WEBVIEW_OPEN_FLAG_DIRECTORY)
+    {
+      opts |= FOS_PICKFOLDERS;
+    }
+    if (dlg->lpVtbl->SetOptions(dlg, opts) != S_OK)
+    {
+      goto error_dlg;
+    }
+    if (dlg->lpVtbl->Show(dlg, w->priv.hwnd) != S_OK)
+    {
+      goto error_dlg;
+    }
+    if (dlg->lpVtbl->GetResults(dlg, &items) != S_OK)
+    {
+      goto error_dlg;
+    }
+    items->lpVtbl->GetCount(items, &count);
+    for (i = 0; i < count; i++)
+    {
+      IShellItem *item = NULL;
+      WCHAR *ws = NULL;
+      if (items->lpVtbl->GetItemAt(items, i, &item) != S_OK)
+      {
+        continue;
+      }
+      if (item->lpVtbl->GetDisplayName(item, SIGDN_FILESYSPATH, &ws) == S_OK)
+      {
+        char *s = webview_from_utf16(ws);
+        if (strlen(result) + strlen(s) + 2 < resultsz)
+        {
+          if (result[0] != '\0')
+          {
+            strcat(result, "\n");
+          }
+          strcat(result, s);
+        }
+        GlobalFree(s);
+        CoTaskMemFree(ws);
+      }
+      item->lpVtbl->Release(item);
+    }
+    items->lpVtbl->Release(items);
+  error_dlg:
+    dlg->lpVtbl->Release(dlg);
+  error_specs:
+    for (j = 0; j < nspecs; j++)
+    {
+      GlobalFree((HGLOBAL)specs[j].pszName);
+      GlobalFree((HGLOBAL)specs[j].pszSpec);
+    }
+    free(specs);
+    free(formats_dup);
+  }
+
+  // MessageBox only has stock buttons, so their labels are replaced when
+  // the box is activated. Dialogs are only run on the main thread, so the
+  // hook's state is kept in statics.
+  static HHOOK webview_message_hook = NULL;
+  static WCHAR *webview_message_labels[3];
+  static const int *webview_message_ids = NULL;
+  static int webview_message_count = 0;
+
+  static LRESULT CALLBACK webview_message_hook_proc(int code, WPARAM wparam,
+                                                    LPARAM lparam)
+  {
+    int i;
+    if (code != HCBT_ACTIVATE)
+    {
+      return CallNextHookEx(webview_message_hook, code, wparam, lparam);
+    }
+    for (i = 0; i < webview_message_count; i++)
+    {
+      SetDlgItemTextW((HWND)wparam, webview_message_ids[i],
+                      webview_message_labels[i]);
+    }
+    UnhookWindowsHookEx(webview_message_hook);
+    webview_message_hook = NULL;
+    return 0;
+  }
+
+  // At most three buttons are shown. As Escape cancels the box, it presses
+  // the last button when there are two or three.
+  WEBVIEW_API int webview_message_dialog(struct webview *w, int type,
+                                         const char *title, const char *message,
+                                         const char *buttons)
+  {
+    static const int ids[3][3] = {
+        {IDOK}, {IDOK, IDCANCEL}, {IDYES, IDNO, IDCANCEL}};
+    static const UINT sets[3] = {MB_OK, MB_OKCANCEL, MB_YESNOCANCEL};
+    WCHAR *wtitle = webview_to_utf16(title);
+    WCHAR *wmessage = webview_to_utf16(message);
+    char *labels = strdup(buttons);
+    char *line;
+    UINT mbtype;
+    int count = 0;
+    int shown;
+    int pressed;
+    int result = -1;
+    int i;
+
+    for (line = strtok(labels, "\n"); line != NULL && count < 3;
+         line = strtok(NULL, "\n"))
+    {
+      webview_message_labels[count++] = webview_to_utf16(line);
+    }
+    shown = count > 0 ? count : 1;
+    mbtype = sets[shown - 1];
+    switch (type)
+    {
+    case WEBVIEW_MESSAGE_INFO:
+      mbtype |= MB_ICONINFORMATION;
+      break;
+    case WEBVIEW_MESSAGE_WARNING:
+      mbtype |= MB_ICONWARNING;
+      break;
+    case WEBVIEW_MESSAGE_ERROR:
+      mbtype |= MB_ICONERROR;
+      break;
+    case WEBVIEW_MESSAGE_QUESTION:
+      mbtype |= MB_ICONQUESTION;
+      break;
+    }
+
+    webview_message_ids = ids[shown - 1];
+    webview_message_count = count;
+    if (count > 0)
+    {
+      webview_message_hook = SetWindowsHookExW(
+          WH_CBT, webview_message_hook_proc, NULL, GetCurrentThreadId());
+    }
+    pressed = MessageBoxW(w->priv.hwnd, wmessage, wtitle, mbtype);
+    if (webview_message_hook != NULL)
+    {
+      UnhookWindowsHookEx(webview_message_hook);
+      webview_message_hook = NULL;
+    }
+    for (i = 0; i < shown; i++)
+    {
+      if (ids[shown - 1][i] == pressed)
+      {
+        result = i;
+      }
+    }
+
+    for (i = 0; i < count; i++)
+    {
+      GlobalFree(webview_message_labels[i]);
+    }
+    webview_message_count = 0;
+    free(labels);
+    GlobalFree(wmessage);
+    GlobalFree(wtitle);
+    return result;
+  }
+
+  // Returns the selected directories separated by newlines
+  WEBVIEW_API void webview_select_directories(struct webview *w, const char *title,
+                                              char *result, size_t resultsz)
+  {
+    IFileOpenDialog *dlg = NULL;
+    IShellItemArray *items = NULL;
+    FILEOPENDIALOGOPTIONS opts;
+    DWORD count = 0;
+    DWORD i;
+    WCHAR *wtitle = NULL;
+    result[0] = '\0';
+    if (CoCreateInstance(iid_unref(&CLSID_FileOpenDialog), NULL,
+                         CLSCTX_INPROC_SERVER, iid_unref(&IID_IFileOpenDialog),
+                         (void **)&dlg) != S_OK)
+    {
+      return;
+    }
+    wtitle = webview_to_utf16(title);
+    if (wtitle != NULL)
+    {
+      dlg->lpVtbl->SetTitle(dlg, wtitle);
+      GlobalFree(wtitle);
+    }
+    if (dlg->lpVtbl->GetOptions(dlg, &opts) != S_OK)
+    {
+      goto error_dlg;
+    }
+    opts |= FOS_PICKFOLDERS | FOS_ALLOWMULTISELECT | FOS_NOCHANGEDIR |
+            FOS_PATHMUSTEXIST | FOS_FORCESHOWHIDDEN;
+    if (dlg->lpVtbl->SetOptions(dlg, opts) != S_OK)
+    {
+      goto error_dlg;
+    }
+    if (dlg->lpVtbl->Show(dlg, w->priv.hwnd) != S_OK)
+    {
+      goto error_dlg;
+    }
+    if (dlg->lpVtbl->GetResults(dlg, &items) != S_OK)
+    {
+      goto error_dlg;
+    }
+    items->lpVtbl->GetCount(items, &count);
+    for (i = 0; i < count; i++)
+    {
+      IShellItem *item = NULL;
+      WCHAR *ws = NULL;
+      if (items->lpVtbl->GetItemAt(items, i, &item) != S_OK)
+      {
+        continue;
+      }
+      if (item->lpVtbl->GetDisplayName(item, SIGDN_FILESYSPATH, &ws) == S_OK)
+      {
+        char *s = webview_from_utf16(ws);
+        if (strlen(result) + strlen(s) + 2 < resultsz)
+        {
+          if (result[0] != '\0')
+          {
+            strcat(result, "\n");
+          }
+          strcat(result, s);
+        }
+        GlobalFree(s);
+        CoTaskMemFree(ws);
+      }
+      item->lpVtbl->Release(item);
+    }
+    items->lpVtbl->Release(items);
+  error_dlg:
+    dlg->lpVtbl->Release(dlg);
+  }
+
+  // The pickers return 1 if the user made a choice and 0 if they cancelled
+  WEBVIEW_API int webview_color_picker(struct webview *w, const char *title,
+                                       uint8_t *r, uint8_t *g, uint8_t *b,
+                                       uint8_t *a)
+  {
+    // The Windows picker has no title or alpha channel
+    static COLORREF custom[16];
+    CHOOSECOLORA cc;
+    ZeroMemory(&cc, sizeof(cc));
+    cc.lStructSize = sizeof(cc);
+    cc.hwndOwner = w->priv.hwnd;
+    cc.lpCustColors = custom;
+    cc.rgbResult = RGB(*r, *g, *b);
+    cc.Flags = CC_FULLOPEN | CC_RGBINIT;
+    if (!ChooseColorA(&cc))
+    {
+      return 0;
+    }
+    *r = GetRValue(cc.rgbResult);
+    *g = GetGValue(cc.rgbResult);
+    *b = GetBValue(cc.rgbResult);
+    return 1;
+  }
+
+  WEBVIEW_API int webview_font_picker(struct webview *w, const char *title,
+                                      char *family, size_t familysz,
+                                      char *face, size_t facesz, int *size,
+                                      int *weight, int *italic)
+  {
+    // The Windows picker doesn't name the face, so it's left empty
+    LOGFONTA lf;
+    CHOOSEFONTA cf;
+    ZeroMemory(&lf, sizeof(lf));
+    ZeroMemory(&cf, sizeof(cf));
+    cf.lStructSize = sizeof(cf);
+    cf.hwndOwner = w->priv.hwnd;
+    cf.lpLogFont = &lf;
+    cf.Flags = CF_SCREENFONTS;
+    face[0] = '\0';
+    if (family[0] != '\0')
+    {
+      strncpy(lf.lfFaceName, family, LF_FACESIZE - 1);
+      if (*size > 0)
+      {
+        HDC hdc = GetDC(w->priv.hwnd);
+        lf.lfHeight = -MulDiv(*size, GetDeviceCaps(hdc, LOGPIXELSY), 72);
+        ReleaseDC(w->priv.hwnd, hdc);
+      }
+      lf.lfWeight = *weight;
+      lf.lfItalic = *italic != 0;
+      cf.Flags |= CF_INITTOLOGFONTSTRUCT;
+    }
+    if (!ChooseFontA(&cf))
+    {
+      return 0;
+    }
+    strncpy(family, lf.lfFaceName, familysz);
+    family[familysz - 1] = '\0';
+    *size = cf.iPointSize / 10;
+    *weight = lf.lfWeight;
+    *italic = lf.lfItalic != 0;
+    return 1;
+  }
+
+  // The emoji panel is opened with Win+. for the focused input (Windows 10+)
+  WEBVIEW_API void webview_show_emoji_picker(struct webview *w)
+  {
+    INPUT inputs[4];
+    ZeroMemory(inputs, sizeof(inputs));
+    inputs[0].type = inputs[1].type = inputs[2].type = inputs[3].type = INPUT_KEYBOARD;
+    inputs[0].ki.wVk = inputs[3].ki.wVk = VK_LWIN;
+    inputs[1].ki.wVk = inputs[2].ki.wVk = VK_OEM_PERIOD;
+    inputs[2].ki.dwFlags = inputs[3].ki.dwFlags = KEYEVENTF_KEYUP;
+    SetForegroundWindow(w->priv.hwnd);
+    SendInput(4, inputs, sizeof(INPUT));
+  }
+
+  // Critical requests flash the taskbar button until the window is brought
+  // to the front, others flash it a few times
+  WEBVIEW_API void webview_request_attention(struct webview *w, int critical)
+  {
+    FLASHWINFO info;
+    ZeroMemory(&info, sizeof(info));
+    info.cbSize = sizeof(info);
+    info.hwnd = w->priv.hwnd;
+    if (critical)
+    {
+      info.dwFlags = FLASHW_ALL | FLASHW_TIMERNOFG;
+    }
+    else
+    {
+      info.dwFlags = FLASHW_TRAY;
+      info.uCount = 3;
+    }
+    FlashWindowEx(&info);
+  }
+
+  // Returns the taskbar, or NULL before Windows 7
+  static ITaskbarList3 *webview_taskbar(struct webview *w)
+  {
+    if (w->priv.taskbar == NULL &&
+        CoCreateInstance(iid_unref(&CLSID_TaskbarList), NULL,
+                         CLSCTX_INPROC_SERVER, iid_unref(&IID_ITaskbarList3),
+                         (void **)&w->priv.taskbar) == S_OK &&
+        w->priv.taskbar->lpVtbl->HrInit(w->priv.taskbar) != S_OK)
+    {
+      w->priv.taskbar->lpVtbl->Release(w->priv.taskbar);
+      w->priv.taskbar = NULL;
+    }
+    return w->priv.taskbar;
+  }
+
+  WEBVIEW_API void webview_set_progress(struct webview *w, double progress)
+  {
+    ITaskbarList3 *taskbar = webview_taskbar(w);
+    if (taskbar == NULL)
+    {
+      return;
+    }
+    if (progress < 0)
+    {
+      taskbar->lpVtbl->SetProgressState(taskbar, w->priv.hwnd, TBPF_NOPROGRESS);
+      return;
+    }
+    taskbar->lpVtbl->SetProgressState(taskbar, w->priv.hwnd, TBPF_NORMAL);
+    taskbar->lpVtbl->SetProgressValue(
+        taskbar, w->priv.hwnd, (ULONGLONG)(progress * 1000 + 0.5), 1000);
+  }
+
+  // Draws the badge in white on a red circle, the size of a small icon
+  static HICON webview_badge_icon(const WCHAR *badge)
+  {
+    int size = GetSystemMetrics(SM_CXSMICON);
+    BITMAPINFO info;
+    ZeroMemory(&info, sizeof(info));
+    info.bmiHeader.biSize = sizeof(BITMAPINFOHEADER);
+    info.bmiHeader.biWidth = size;
+    info.bmiHeader.biHeight = -size;
+    info.bmiHeader.biPlanes = 1;
+    info.bmiHeader.biBitCount = 32;
+    info.bmiHeader.biCompression = BI_RGB;
+    DWORD *pixels = NULL;
+    HDC dc = CreateCompatibleDC(NULL);
+    HBITMAP color = CreateDIBSection(dc, &info, DIB_RGB_COLORS,
+                                     (void **)&pixels, NULL, 0);
+    HBITMAP mask = CreateBitmap(size, size, 1, 1, NULL);
+    if (color == NULL || mask == NULL)
+    {
+      DeleteObject(color);
+      DeleteObject(mask);
+      DeleteDC(dc);
+      return NULL;
+    }
+    HGDIOBJ previous = SelectObject(dc, color);
+    ZeroMemory(pixels, size * size * sizeof(DWORD));
+    HBRUSH brush = CreateSolidBrush(RGB(0xd1, 0x34, 0x38));
+    HFONT font = CreateFontW(-size * 3 / 4, 0, 0, 0, FW_BOLD, FALSE, FALSE,
+                             FALSE, DEFAULT_CHARSET, OUT_DEFAULT_PRECIS,
+                             CLIP_DEFAULT_PRECIS, ANTIALIASED_QUALITY,
+                             DEFAULT_PITCH, L"Segoe UI");
+    SelectObject(dc, brush);
+    SelectObject(dc, GetStockObject(NULL_PEN));
+    Ellipse(dc, 0, 0, size + 1, size + 1);
+    SelectObject(dc, font);
+    SetBkMode(dc, TRANSPARENT);
+    SetTextColor(dc, RGB(0xff, 0xff, 0xff));
+    RECT rect = {0, 0, size, size};
+    DrawTextW(dc, badge, -1, &rect, DT_CENTER | DT_VCENTER | DT_SINGLELINE);
+    GdiFlush();
+    // GDI leaves the alpha channel clear, so what was drawn is made opaque
+    for (int i = 0; i < size * size; i++)
+    {
+      if (pixels[i] != 0)
+      {
+        pixels[i] |= 0xff000000;
+      }
+    }
+    SelectObject(dc, previous);
+    DeleteObject(font);
+    DeleteObject(brush);
+    DeleteDC(dc);
+
+    ICONINFO icon = {TRUE, 0, 0, mask, color};
+    HICON result = CreateIconIndirect(&icon);
+    DeleteObject(color);
+    DeleteObject(mask);
+    return result;
+  }
+
+  // The badge is the taskbar button's overlay icon
+  WEBVIEW_API void webview_set_badge(struct webview *w, const char *badge)
+  {
+    ITaskbarList3 *taskbar = webview_taskbar(w);
+    if (taskbar == NULL)
+    {
+      return;
+    }
+    if (*badge == '\0')
+    {
+      taskbar->lpVtbl->SetOverlayIcon(taskbar, w->priv.hwnd, NULL, NULL);
+      return;
+    }
+    WCHAR *text = webview_to_utf16(badge);
+    if (text == NULL)
+    {
+      return;
+    }
+    HICON icon = webview_badge_icon(text);
+    if (icon != NULL)
+    {
+      taskbar->lpVtbl->SetOverlayIcon(taskbar, w->priv.hwnd, icon, text);
+      DestroyIcon(icon);
+    }
+    GlobalFree(text);
+  }
+
+  // The window is layered while it's faded
+  WEBVIEW_API void webview_set_opacity(struct webview *w, double opacity)
+  {
+    DWORD exStyle = GetWindowLong(w->priv.hwnd, GWL_EXSTYLE);
+    w->priv.transparency = (BYTE)(255 - (int)(opacity * 255.0 + 0.5));
+    if (w->priv.transparency > 0 || w->transparent)
+    {
+      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE, exStyle | WS_EX_LAYERED);
+      webview_update_layered(w);
+    }
+    else if (!(exStyle & WS_EX_TRANSPARENT))
+    {
+      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE, exStyle & ~WS_EX_LAYERED);
+    }
+    else
+    {
+      webview_update_layered(w);
+    }
+  }
+
+  // The tray icon is sent WM_WEBVIEW_TRAY messages for the window
+  WEBVIEW_API int webview_tray_set_icon(struct webview *w, const uint8_t *data,
+                                        int size)
+  {
+    NOTIFYICONDATAW *tray = &w->priv.tray;
+    HICON icon = webview_create_icon(data, size, GetSystemMetrics(SM_CXSMICON));
+    if (icon == NULL)
+    {
+      return -1;
+    }
+    DWORD message = NIM_MODIFY;
+    HICON previous = tray->hIcon;
+    if (tray->cbSize == 0)
+    {
+      tray->cbSize = sizeof(*tray);
+      tray->hWnd = w->priv.hwnd;
+      tray->uID = 1;
+      tray->uCallbackMessage = WM_WEBVIEW_TRAY;
+      message = NIM_ADD;
+    }
+    tray->uFlags = NIF_ICON | NIF_MESSAGE | NIF_TIP;
+    tray->hIcon = icon;
+    if (!Shell_NotifyIconW(message, tray))
+    {
+      DestroyIcon(icon);
+      tray->hIcon = previous;
+      if (message == NIM_ADD)
+      {
+        ZeroMemory(tray, sizeof(*tray));
+      }
+      return -1;
+    }
+    if (previous != NULL)
+    {
+      DestroyIcon(previous);
+    }
+    // The icon is kept once the balloon is gone
+    w->priv.notification_icon = FALSE;
+    return 0;
+  }
+
+  WEBVIEW_API void webview_tray_set_tooltip(struct webview *w,
+                                            const char *tooltip)
+  {
+    NOTIFYICONDATAW *tray = &w->priv.tray;
+    if (tray->cbSize == 0)
+    {
+      return;
+    }
+    WCHAR *tip = webview_to_utf16(tooltip);
+    if (tip == NULL)
+    {
+      return;
+    }
+    wcsncpy(tray->szTip, tip, ARRAYSIZE(tray->szTip) - 1);
+    tray->szTip[ARRAYSIZE(tray->szTip) - 1] = 0;
+    GlobalFree(tip);
+    Shell_NotifyIconW(NIM_MODIFY, tray);
+  }
+
+  // The menu is shown by right clicking the icon. Its items are numbered
+  // from 1, as TrackPopupMenu returns 0 when nothing is chosen.
+  WEBVIEW_API void webview_tray_set_menu(struct webview *w, const char *menu)
+  {
+    char label[256];
+    int separator, enabled, checked;
+    int index = 0;
+    if (w->priv.tray_menu != NULL)
+    {
+      DestroyMenu(w->priv.tray_menu);
+      w->priv.tray_menu = NULL;
+    }
+    if (menu == NULL || *menu == '\0')
+    {
+      return;
+    }
+    w->priv.tray_menu = CreatePopupMenu();
+    while ((menu = webview_tray_next_item(menu, label, sizeof(label),
+                                          &separator, &enabled, &checked)) != NULL)
+    {
+      index++;
+      if (separator)
+      {
+        AppendMenuW(w->priv.tray_menu, MF_SEPARATOR, 0, NULL);
+        continue;
+      }
+      WCHAR *text = webview_to_utf16(label);
+      AppendMenuW(w->priv.tray_menu,
+                  MF_STRING | (enabled ? 0 : MF_GRAYED) |
+                      (checked ? MF_CHECKED : 0),
+                  index, text);
+      GlobalFree(text);
+    }
+  }
+
+  WEBVIEW_API void webview_tray_remove(struct webview *w)
+  {
+    NOTIFYICONDATAW *tray = &w->priv.tray;
+    if (tray->cbSize != 0)
+    {
+      Shell_NotifyIconW(NIM_DELETE, tray);
+      DestroyIcon(tray->hIcon);
+      ZeroMemory(tray, sizeof(*tray));
+    }
+    w->priv.notification_icon = FALSE;
+    if (w->priv.tray_menu != NULL)
+    {
+      DestroyMenu(w->priv.tray_menu);
+      w->priv.tray_menu = NULL;
+    }
+  }
+
+  // Windows 10 and later show balloons as toasts. Only one is shown at a
+  // time, so a new notification replaces the last one.
+  WEBVIEW_API int webview_notify(struct webview *w, const char *id,
+                                 const char *title, const char *body,
+                                 int silent)
+  {
+    NOTIFYICONDATAW *tray = &w->priv.tray;
+    WCHAR *wtitle = webview_to_utf16(title);
+    WCHAR *wbody = webview_to_utf16(body);
+    if (wtitle == NULL || wbody == NULL)
+    {
+      GlobalFree(wtitle);
+      GlobalFree(wbody);
+      return 0;
+    }
+    if (tray->cbSize == 0)
+    {
+      HICON icon = w->priv.small_icon;
+      if (icon == NULL)
+      {
+        icon = (HICON)GetClassLongPtr(w->priv.hwnd, GCLP_HICONSM);
+      }
+      tray->cbSize = sizeof(*tray);
+      tray->hWnd = w->priv.hwnd;
+      tray->uID = 1;
+      tray->uCallbackMessage = WM_WEBVIEW_TRAY;
+      tray->uFlags = NIF_ICON | NIF_MESSAGE | NIF_TIP;
+      tray->hIcon = CopyIcon(icon != NULL ? icon : LoadIcon(NULL, IDI_APPLICATION));
+      if (!Shell_NotifyIconW(NIM_ADD, tray))
+      {
+        DestroyIcon(tray->hIcon);
+        ZeroMemory(tray, sizeof(*tray));
+        GlobalFree(wtitle);
+        GlobalFree(wbody);
+        return 0;
+      }
+      w->priv.notification_icon = TRUE;
+    }
+
+    // The balloon is shown by modifying a copy, so that the icon's flags are
+    // kept for the tooltip
+    NOTIFYICONDATAW balloon = *tray;
+    balloon.uFlags = NIF_INFO;
+    balloon.dwInfoFlags = NIIF_INFO | (silent ? NIIF_NOSOUND : 0);
+    wcsncpy(balloon.szInfoTitle, wtitle, ARRAYSIZE(balloon.szInfoTitle) - 1);
+    balloon.szInfoTitle[ARRAYSIZE(balloon.szInfoTitle) - 1] = 0;
+    wcsncpy(balloon.szInfo, wbody, ARRAYSIZE(balloon.szInfo) - 1);
+    balloon.szInfo[ARRAYSIZE(balloon.szInfo) - 1] = 0;
+    GlobalFree(wtitle);
+    GlobalFree(wbody);
+    free(w->priv.notification);
+    w->priv.notification = NULL;
+    if (!Shell_NotifyIconW(NIM_MODIFY, &balloon))
+    {
+      webview_forget_notification(w);
+      return 0;
+    }
+    w->priv.notification = strdup(id);
+    return 1;
+  }
+
+  // Appends the items at the given depth to the menu, with their submenus,
+  // returning the rest of the menu. Items indented too far are appended as
+  // if they weren't.
+  // Sets the virtual key and the FSHIFT flag it needs for a key named by
+  // webview_menu_next_item. Returns 0 if the key is unknown.
+  static int webview_virtual_key(const char *key, WORD *vk, BYTE *flags)
+  {
+    int number;
+    *flags = 0;
+    for (size_t i = 0;
+         i < sizeof(webview_key_names) / sizeof(webview_key_names[0]); i++)
+    {
+      if (strcmp(key, webview_key_names[i].name) == 0)
+      {
+        *vk = (WORD)webview_key_names[i].code;
+        return 1;
+      }
+    }
+    if (key[0] == 'F' && sscanf(key + 1, "%d", &number) == 1 && number >= 1 &&
+        number <= 24)
+    {
+      *vk = (WORD)(VK_F1 + number - 1);
+      return 1;
+    }
+    SHORT scan = key[0] != '\0' && key[1] == '\0' ? VkKeyScanA(key[0]) : -1;
+    if (scan == -1)
+    {
+      return 0;
+    }
+    *vk = LOBYTE(scan);
+    *flags = (HIBYTE(scan) & 1) ? FSHIFT : 0;
+    return 1;
+  }
+
+  // Appends the items at the given depth to the menu, with their submenus,
+  // returning the rest of the menu. Items indented too far are appended as
+  // if they weren't. If accelerators isn't NULL, the items' accelerators
+  // are added to it and counted, and shown after their labels.
+  static const char *webview_menu_append(HMENU parent, const char *menu,
+                                         int depth, int *index,
+                                         ACCEL *accelerators, int *count)
+  {
+    char label[256];
+    char key[16];
+    char text[320];
+    int modifiers, separator, enabled, checked, radio, role;
+    while (webview_menu_depth(menu) >= depth)
+    {
+      menu = webview_menu_next_item(menu, label, sizeof(label), key,
+                                    sizeof(key), &modifiers, &separator,
+                                    &enabled, &checked, &radio, &role);
+      UINT_PTR id = WEBVIEW_MENU_ID + (*index)++;
+      if (separator)
+      {
+        AppendMenuW(parent, MF_SEPARATOR, 0, NULL);
+        continue;
+      }
+      int popup = webview_menu_depth(menu) > depth;
+      snprintf(text, sizeof(text), "%s", label);
+      WORD vk;
+      BYTE flags;
+      // Windows has no accelerators with the Windows key
+      if (accelerators != NULL && !popup && !(modifiers & WEBVIEW_KEY_SUPER) &&
+          webview_virtual_key(key, &vk, &flags))
+      {
+        flags |= FVIRTKEY;
+        flags |= (modifiers & WEBVIEW_KEY_SHIFT) ? FSHIFT : 0;
+        flags |= (modifiers & WEBVIEW_KEY_CONTROL) ? FCONTROL : 0;
+        flags |= (modifiers & WEBVIEW_KEY_ALT) ? FALT : 0;
+        ACCEL accelerator = {flags, vk, (WORD)id};
+        accelerators[(*count)++] = accelerator;
+        if (key[0] >= 'a' && key[0] <= 'z' && key[1] == '\0')
+        {
+          key[0] = key[0] - 'a' + 'A';
+        }
+        snprintf(text, sizeof(text), "%s\t%s%s%s%s", label,
+                 (flags & FCONTROL) ? "Ctrl+" : "",
+                 (modifiers & WEBVIEW_KEY_SHIFT) ? "Shift+" : "",
+                 (flags & FALT) ? "Alt+" : "", key);
+      }
+      UINT menu_flags = MF_STRING | (enabled ? 0 : MF_GRAYED) |
+                        (checked ? MF_CHECKED : 0);
+      if (popup)
+      {
+        HMENU submenu = CreatePopupMenu();
+        menu = webview_menu_append(submenu, menu, depth + 1, index,
+                                   accelerators, count);
+        menu_flags |= MF_POPUP;
+        id = (UINT_PTR)submenu;
+      }
+      WCHAR *wide = webview_to_utf16(text);
+      AppendMenuW(parent, menu_flags, id, wide);
+      GlobalFree(wide);
+      if (popup)
+      {
+        continue;
+      }
+      // Checked radio items show a bullet rather than a check mark
+      MENUITEMINFOW info = {sizeof(MENUITEMINFOW)};
+      info.fMask = MIIM_FTYPE | MIIM_DATA;
+      info.fType = MFT_STRING | (radio ? MFT_RADIOCHECK : 0);
+      info.dwItemData = (ULONG_PTR)role;
+      SetMenuItemInfoW(parent, (UINT)id, FALSE, &info);
+    }
+    return menu;
+  }
+
+  // The items send WM_COMMAND with their number from WEBVIEW_MENU_ID. The
+  // menu bar is destroyed with the window.
+  WEBVIEW_API void webview_set_menu(struct webview *w, const char *menu)
+  {
+    int index = 0;
+    int count = 0;
+    HMENU previous = w->priv.menu;
+    w->priv.menu = NULL;
+    if (w->priv.accelerators != NULL)
+    {
+      DestroyAcceleratorTable(w->priv.accelerators);
+      w->priv.accelerators = NULL;
+    }
+    if (menu != NULL && *menu != '\0')
+    {
+      // Each line may have an accelerator
+      int lines = 1;
+      for (const char *c = menu; *c != '\0'; c++)
+      {
+        lines += *c == '\n';
+      }
+      ACCEL *accelerators = (ACCEL *)malloc(lines * sizeof(ACCEL));
+      w->priv.menu = CreateMenu();
+      webview_menu_append(w->priv.menu, menu, 0, &index, accelerators, &count);
+      if (count > 0)
+      {
+        w->priv.accelerators = CreateAcceleratorTableW(accelerators, count);
+      }
+      free(accelerators);
+    }
+    SetMenu(w->priv.hwnd, w->priv.menu);
+    if (previous != NULL)
+    {
+      DestroyMenu(previous);
+    }
+    DrawMenuBar(w->priv.hwnd);
+  }
+
+  // TrackPopupMenu returns the chosen item's command id, or 0 if the menu
+  // was dismissed
+  WEBVIEW_API void webview_show_context_menu(struct webview *w,
+                                             const char *menu, int x, int y)
+  {
+    int index = 0;
+    if (menu == NULL || *menu == '\0')
+    {
+      return;
+    }
+    HMENU popup = CreatePopupMenu();
+    webview_menu_append(popup, menu, 0, &index, NULL, NULL);
+    double scale = webview_scale(w);
+    POINT p = {(LONG)(x * scale), (LONG)(y * scale)};
+    ClientToScreen(w->priv.hwnd, &p);
+    int item = TrackPopupMenu(popup,
+                              TPM_RETURNCMD | TPM_RIGHTBUTTON | TPM_NONOTIFY,
+                              p.x, p.y, 0, w->priv.hwnd, NULL);
+    int role = webview_menu_item_role(popup, item);
+    DestroyMenu(popup);
+    if (role != WEBVIEW_MENU_ROLE_NONE)
+    {
+      webview_menu_role(w, role);
+    }
+    else if (item >= WEBVIEW_MENU_ID)
+    {
+      webview_context_menu_event(w, item - WEBVIEW_MENU_ID);
+    }
+  }
+
+  // The view becomes a child of the window, above the browser. The position
+  // is scaled from CSS pixels.
+  WEBVIEW_API void webview_place_overlay(struct webview *w, void *view, int x,
+                                         int y, int width, int height,
+                                         int visible)
+  {
+    HWND hwnd = (HWND)view;
+    if (GetParent(hwnd) != w->priv.hwnd)
+    {
+      SetWindowLong(hwnd, GWL_STYLE,
+                    (GetWindowLong(hwnd, GWL_STYLE) & ~WS_POPUP) | WS_CHILD);
+      SetParent(hwnd, w->priv.hwnd);
+    }
+    double scaleX = webview_scale(w);
+    double scaleY = scaleX;
+    SetWindowPos(hwnd, HWND_TOP, x * scaleX, y * scaleY, width * scaleX,
+                 height * scaleY,
+                 SWP_NOACTIVATE | (visible ? SWP_SHOWWINDOW : SWP_HIDEWINDOW));
+  }
+
+  WEBVIEW_API void webview_remove_overlay(struct webview *w, void *view)
+  {
+    HWND hwnd = (HWND)view;
+    if (GetParent(hwnd) == w->priv.hwnd)
+    {
+      ShowWindow(hwnd, SW_HIDE);
+      SetParent(hwnd, NULL);
+    }
+  }
+
+  // The state of a call made through the automation object, which waits
+  // for webview_automation_reply before returning
+  struct webview_automation_call
+  {
+    int done;
+    char *result;
+    char *error;
+  };
+
+  typedef struct
+  {
+    IDispatch dispatch;
+    struct webview *w;
+  } webview_automation_object;
+
+#define WEBVIEW_AUTOMATION_CALL_ID 1
+#define WEBVIEW_AUTOMATION_LIST_ID 2
+
+  static HRESULT STDMETHODCALLTYPE Automation_QueryInterface(IDispatch FAR *This,
+                                                             REFIID riid,
+                                                             LPVOID FAR *ppvObj)
+  {
+    if (iid_eq(riid, &IID_IUnknown) || iid_eq(riid, &IID_IDispatch))
+    {
+      *ppvObj = This;
+      return S_OK;
+    }
+    *ppvObj = 0;
+    return E_NOINTERFACE;
+  }
+  static ULONG STDMETHODCALLTYPE Automation_AddRef(IDispatch FAR *This) { return 1; }
+  static ULONG STDMETHODCALLTYPE Automation_Release(IDispatch FAR *This) { return 1; }
+  static HRESULT STDMETHODCALLTYPE Automation_GetTypeInfoCount(IDispatch FAR *This,
+                                                               UINT *pctinfo)
+  {
+    *pctinfo = 0;
+    return S_OK;
+  }
+  static HRESULT STDMETHODCALLTYPE Automation_GetTypeInfo(IDispatch FAR *This,
+                                                          UINT iTInfo, LCID lcid,
+                                                          ITypeInfo **ppTInfo)
+  {
+    return E_NOTIMPL;
+  }
+  static HRESULT STDMETHODCALLTYPE Automation_GetIDsOfNames(IDispatch FAR *This,
+                                                            REFIID riid,
+                                                            LPOLESTR *rgszNames,
+                                                            UINT cNames, LCID lcid,
+                                                            DISPID *rgDispId)
+  {
+    if (cNames != 1)
+    {
+      return DISP_E_UNKNOWNNAME;
+    }
+    if (_wcsicmp(rgszNames[0], L"Call") == 0)
+    {
+      rgDispId[0] = WEBVIEW_AUTOMATION_CALL_ID;
+      return S_OK;
+    }
+    if (_wcsicmp(rgszNames[0], L"List") == 0)
+    {
+      rgDispId[0] = WEBVIEW_AUTOMATION_LIST_ID;
+      return S_OK;
+    }
+    rgDispId[0] = DISPID_UNKNOWN;
+    return DISP_E_UNKNOWNNAME;
+  }
+
+  static BSTR webview_automation_bstr(const char *s)
+  {
+    int n = MultiByteToWideChar(CP_UTF8, 0, s, -1, NULL, 0);
+    BSTR bstr = SysAllocStringLen(NULL, n - 1);
+    if (bstr != NULL)
+    {
+      MultiByteToWideChar(CP_UTF8, 0, s, -1, bstr, n);
+    }
+    return bstr;
+  }
+
+  static HRESULT STDMETHODCALLTYPE
+  Automation_Invoke(IDispatch FAR *This, DISPID dispIdMember, REFIID riid,
+                    LCID lcid, WORD wFlags, DISPPARAMS *pDispParams,
+                    VARIANT *pVarResult, EXCEPINFO *pExcepInfo, UINT *puArgErr)
+  {
+    struct webview *w = ((webview_automation_object *)This)->w;
+    char *method = NULL;
+    char *args = NULL;
+    struct webview_automation_call call = {0, NULL, NULL};
+    MSG msg;
+    UINT i;
+
+    if (dispIdMember == WEBVIEW_AUTOMATION_CALL_ID)
+    {
+      // Arguments are passed in reverse order: method, then args
+      if (pDispParams->cArgs != 2)
+      {
+        return DISP_E_BADPARAMCOUNT;
+      }
+      for (i = 0; i < 2; i++)
+      {
+        if (pDispParams->rgvarg[i].vt != VT_BSTR)
+        {
+          if (puArgErr != NULL)
+          {
+            *puArgErr = i;
+          }
+          return DISP_E_TYPEMISMATCH;
+        }
+      }
+      method = webview_from_utf16(pDispParams->rgvarg[1].bstrVal);
+      args = webview_from_utf16(pDispParams->rgvarg[0].bstrVal);
+    }
+    else if (dispIdMember != WEBVIEW_AUTOMATION_LIST_ID)
+    {
+      return DISP_E_MEMBERNOTFOUND;
+    }
+
+    w->automation_cb(w, method != NULL ? method : "", args != NULL ? args : "",
+                     &call);
+    if (method != NULL)
+    {
+      GlobalFree(method);
+    }
+    if (args != NULL)
+    {
+      GlobalFree(args);
+    }
+
+    // Keep the window responsive until the result is ready. The reply is
+    // dispatched to this thread, so it is handled by this loop.
+    while (!call.done)
+    {
+      if (GetMessage(&msg, 0, 0, 0) <= 0)
+      {
+        PostQuitMessage(0);
+        return E_ABORT;
+      }
+      TranslateMessage(&msg);
+      DispatchMessage(&msg);
+    }
+
+    if (call.error != NULL)
+    {
+      if (pExcepInfo != NULL)
+      {
+        memset(pExcepInfo, 0, sizeof(EXCEPINFO));
+        pExcepInfo->bstrSource = SysAllocString(L"Wails");
+        pExcepInfo->bstrDescription = webview_automation_bstr(call.error);
+        pExcepInfo->scode = E_FAIL;
+      }
+      free(call.error);
+      return DISP_E_EXCEPTION;
+    }
+    if (pVarResult != NULL)
+    {
+      VariantInit(pVarResult);
+      pVarResult->vt = VT_BSTR;
+      pVarResult->bstrVal = webview_automation_bstr(call.result);
+    }
+    free(call.result);
+    return S_OK;
+  }
+
+  static IDispatchVtbl AutomationDispatchTable = {
+      Automation_QueryInterface, Automation_AddRef, Automation_Release,
+      Automation_GetTypeInfoCount, Automation_GetTypeInfo,
+      Automation_GetIDsOfNames, Automation_Invoke};
+
+  static webview_automation_object webview_automation = {
+      {&AutomationDispatchTable}, NULL};
+
+  // Derives a stable CLSID from the ProgID, so clients can find the running
+  // object without a type library or installer
+  static void webview_automation_clsid(const char *name, CLSID *clsid)
+  {
+    uint64_t hi = 14695981039346656037ULL;
+    uint64_t lo = 1099511628211ULL;
+    const char *c;
+    for (c = name; *c; c++)
+    {
+      hi = (hi ^ (uint8_t)*c) * 1099511628211ULL;
+      lo = (lo ^ (uint8_t)*c) * 14695981039346656037ULL;
+    }
+    clsid->Data1 = (unsigned long)(hi >> 32);
+    clsid->Data2 = (unsigned short)(hi >> 16);
+    clsid->Data3 = (unsigned short)((hi & 0x0fff) | 0x4000);
+    memcpy(clsid->Data4, &lo, sizeof(clsid->Data4));
+    clsid->Data4[0] = (clsid->Data4[0] & 0x3f) | 0x80;
+  }
+
+  WEBVIEW_API void webview_automation_start(struct webview *w, const char *name)
+  {
+    CLSID clsid;
+    WCHAR clsidString[40];
+    char key[MAX_PATH];
+    HKEY hKey;
+
+    webview_automation_clsid(name, &clsid);
+    StringFromGUID2(iid_unref(&clsid), clsidString, 40);
+
+    // Register the ProgID for the current user, so GetObject(, "name") works
+    snprintf(key, sizeof(key), "Software\\Classes\\%s\\CLSID", name);
+    if (RegCreateKeyExA(HKEY_CURRENT_USER, key, 0, NULL, 0, KEY_WRITE, NULL,
+                        &hKey, NULL) == ERROR_SUCCESS)
+    {
+      RegSetValueExW(hKey, NULL, 0, REG_SZ, (const BYTE *)clsidString,
+                     (DWORD)((wcslen(clsidString) + 1) * sizeof(WCHAR)));
+      RegCloseKey(hKey);
+    }
+
+    webview_automation.w = w;
+    RegisterActiveObject((IUnknown *)&webview_automation.dispatch, iid_unref(&clsid),
+                         ACTIVEOBJECT_STRONG, &w->priv.automation_cookie);
+  }
+
+  WEBVIEW_API void webview_automation_reply(struct webview *w, void *reply,
+                                            const char *result, const char *error)
+  {
+    struct webview_automation_call *call =
+        (struct webview_automation_call *)reply;
+    if (error != NULL)
+    {
+      call->error = strdup(error);
+    }
+    else
+    {
+      call->result = strdup(result);
+    }
+    call->done = 1;
+  }
+
+  // MSHTML never asks for geolocation, see webview_geolocation_cb_t
+  WEBVIEW_API void webview_geolocation_reply(struct webview *w, void *request,
+                                             int allow) {}
+
+  WEBVIEW_API void webview_set_color(struct webview *w, uint8_t r, uint8_t g,
+                                     uint8_t b, uint8_t a)
+  {
+    HBRUSH brush = CreateSolidBrush(RGB(r, g, b));
+    SetClassLongPtr(w->priv.hwnd, GCLP_HBRBACKGROUND, (LONG_PTR)brush);
+    w->priv.color = RGB(r, g, b);
+    w->priv.color_alpha = a;
+    if (w->transparent)
+    {
+      webview_update_layered(w);
+    }
+  }
+
+/* These are missing parts from MinGW */
+#ifndef __IFileDialog_INTERFACE_DEFINED__
+#define __IFileDialog_INTERFACE_DEFINED__
+  enum _FILEOPENDIALOGOPTIONS
+  {
+    FOS_OVERWRITEPROMPT = 0x2,
+    FOS_STRICTFILETYPES = 0x4,
+    FOS_NOCHANGEDIR = 0x8,
+    FOS_PICKFOLDERS = 0x20,
+    FOS_FORCEFILESYSTEM = 0x40,
+    FOS_ALLNONSTORAGEITEMS = 0x80,
+    FOS_NOVALIDATE = 0x100,
+    FOS_ALLOWMULTISELECT = 0x200,
+    FOS_PATHMUSTEXIST = 0x800,
+    FOS_FILEMUSTEXIST = 0x1000,
+    FOS_CREATEPROMPT = 0x2000,
+    FOS_SHAREAWARE = 0x4000,
+    FOS_NOREADONLYRETURN = 0x8000,
+    FOS_NOTESTFILECREATE = 0x10000,
+    FOS_HIDEMRUPLACES = 0x20000,
+    FOS_HIDEPINNEDPLACES = 0x40000,
+    FOS_NODEREFERENCELINKS = 0x100000,
+    FOS_DONTADDTORECENT = 0x2000000,
+    FOS_FORCESHOWHIDDEN = 0x10000000,
+    FOS_DEFAULTNOMINIMODE = 0x20000000,
+    FOS_FORCEPREVIEWPANEON = 0x40000000
+  };
+  typedef DWORD FILEOPENDIALOGOPTIONS;
+  typedef enum FDAP
+  {
+    FDAP_BOTTOM = 0,
+    FDAP_TOP = 1
+  } FDAP;
+  DEFINE_GUID(IID_IFileDialog, 0x42f85136, 0xdb7e, 0x439c, 0x85, 0xf1, 0xe4, 0x07,
+              0x5d, 0x13, 0x5f, 0xc8);
+  typedef struct IFileDialogVtbl
+  {
+    BEGIN_INTERFACE
+    HRESULT(STDMETHODCALLTYPE *QueryInterface)
+    (IFileDialog *This, REFIID riid, void **ppvObject);
+    ULONG(STDMETHODCALLTYPE *AddRef)
+    (IFileDialog *This);
+    ULONG(STDMETHODCALLTYPE *Release)
+    (IFileDialog *This);
+    HRESULT(STDMETHODCALLTYPE *Show)
+    (IFileDialog *This, HWND hwndOwner);
+    HRESULT(STDMETHODCALLTYPE *SetFileTypes)
+    (IFileDialog *This, UINT cFileTypes, const COMDLG_FILTERSPEC *rgFilterSpec);
+    HRESULT(STDMETHODCALLTYPE *SetFileTypeIndex)
+    (IFileDialog *This, UINT iFileType);
+    HRESULT(STDMETHODCALLTYPE *GetFileTypeIndex)
+    (IFileDialog *This, UINT *piFileType);
+    HRESULT(STDMETHODCALLTYPE *Advise)
+    (IFileDialog *This, IFileDialogEvents *pfde, DWORD *pdwCookie);
+    HRESULT(STDMETHODCALLTYPE *Unadvise)
+    (IFileDialog *This, DWORD dwCookie);
+    HRESULT(STDMETHODCALLTYPE *SetOptions)
+    (IFileDialog *This, FILEOPENDIALOGOPTIONS fos);
+    HRESULT(STDMETHODCALLTYPE *GetOptions)
+    (IFileDialog *This, FILEOPENDIALOGOPTIONS *pfos);
+    HRESULT(STDMETHODCALLTYPE *SetDefaultFolder)
+    (IFileDialog *This, IShellItem *psi);
+    HRESULT(STDMETHODCALLTYPE *SetFolder)
+    (IFileDialog *This, IShellItem *psi);
+    HRESULT(STDMETHODCALLTYPE *GetFolder)
+    (IFileDialog *This, IShellItem **ppsi);
+    HRESULT(STDMETHODCALLTYPE *GetCurrentSelection)
+    (IFileDialog *This, IShellItem **ppsi);
+    HRESULT(STDMETHODCALLTYPE *SetFileName)
+    (IFileDialog *This, LPCWSTR pszName);
+    HRESULT(STDMETHODCALLTYPE *GetFileName)
+    (IFileDialog *This, LPWSTR *pszName);
+    HRESULT(STDMETHODCALLTYPE *SetTitle)
+    (IFileDialog *This, LPCWSTR pszTitle);
+    HRESULT(STDMETHODCALLTYPE *SetOkButtonLabel)
+    (IFileDialog *This, LPCWSTR pszText);
+    HRESULT(STDMETHODCALLTYPE *SetFileNameLabel)
+    (IFileDialog *This, LPCWSTR pszLabel);
+    HRESULT(STDMETHODCALLTYPE *GetResult)
+    (IFileDialog *This, IShellItem **ppsi);
+    HRESULT(STDMETHODCALLTYPE *AddPlace)
+    (IFileDialog *This, IShellItem *psi, FDAP fdap);
+    HRESULT(STDMETHODCALLTYPE *SetDefaultExtension)
+    (IFileDialog *This, LPCWSTR pszDefaultExtension);
+    HRESULT(STDMETHODCALLTYPE *Close)
+    (IFileDialog *This, HRESULT hr);
+    HRESULT(STDMETHODCALLTYPE *SetClientGuid)
+    (IFileDialog *This, REFGUID guid);
+    HRESULT(STDMETHODCALLTYPE *ClearClientData)
+    (IFileDialog *This);
+    HRESULT(STDMETHODCALLTYPE *SetFilter)
+    (IFileDialog *This, IShellItemFilter *pFilter);
+    END_INTERFACE
+  } IFileDialogVtbl;
+  interface IFileDialog
+  {
+    CONST_VTBL IFileDialogVtbl *lpVtbl;
+  };
+  DEFINE_GUID(IID_IFileOpenDialog, 0xd57c7288, 0xd4ad, 0x4768, 0xbe, 0x02, 0x9d,
+              0x96, 0x95, 0x32, 0xd9, 0x60);
+  DEFINE_GUID(IID_IFileSaveDialog, 0x84bccd23, 0x5fde, 0x4cdb, 0xae, 0xa4, 0xaf,
+              0x64, 0xb8, 0x3d, 0x78, 0xab);
+#endif
+
+  WEBVIEW_API void webview_dialog(struct webview *w,
+                                  enum webview_dialog_type dlgtype, int flags,
+                                  const char *title, const char *arg,
+                                  char *result, size_t resultsz, char *filter)
+  {
+    if (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN ||
+        dlgtype == WEBVIEW_DIALOG_TYPE_SAVE)
+    {
+      IFileDialog *dlg = NULL;
+      IShellItem *res = NULL;
+      WCHAR *ws = NULL;
+      char *s = NULL;
+      FILEOPENDIALOGOPTIONS opts, add_opts;
+      if (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN)
+      {
+        if (CoCreateInstance(
+                iid_unref(&CLSID_FileOpenDialog), NULL, CLSCTX_INPROC_SERVER,
+                iid_unref(&IID_IFileOpenDialog), (void **)&dlg) != S_OK)
+        {
+          goto error_dlg;
+        }
+        if (flags & WEBVIEW_DIALOG_FLAG_DIRECTORY)
+        {
+          add_opts |= FOS_PICKFOLDERS;
+        }
+        add_opts |= FOS_NOCHANGEDIR | FOS_ALLNONSTORAGEITEMS | FOS_NOVALIDATE |
+                    FOS_PATHMUSTEXIST | FOS_FILEMUSTEXIST | FOS_SHAREAWARE |
+                    FOS_NOTESTFILECREATE | FOS_NODEREFERENCELINKS |
+                    FOS_FORCESHOWHIDDEN | FOS_DEFAULTNOMINIMODE;
+      }
+      else
+      {
+        if (CoCreateInstance(
+                iid_unref(&CLSID_FileSaveDialog), NULL, CLSCTX_INPROC_SERVER,
+                iid_unref(&IID_IFileSaveDialog), (void **)&dlg) != S_OK)
+        {
+          goto error_dlg;
+        }
+        add_opts |= FOS_OVERWRITEPROMPT | FOS_NOCHANGEDIR |
+                    FOS_ALLNONSTORAGEITEMS | FOS_NOVALIDATE | FOS_SHAREAWARE |
+                    FOS_NOTESTFILECREATE | FOS_NODEREFERENCELINKS |
+                    FOS_FORCESHOWHIDDEN | FOS_DEFAULTNOMINIMODE;
+      }
+      if (filter[0] != '\0')
+      {
+        int count;
+        int i=0;
+        char* token;
+        char* filter_dup = strdup(filter);
+        for (count=1; filter[count]; filter[count]==',' ? count++ : *filter++);
+        COMDLG_FILTERSPEC rgSpec[count];
+        char* filters[count];
+        token = strtok(filter_dup, ",");
+        while(token != NULL)
+        {
+          filters[i] = token;
+          token = strtok(NULL, ",");
+          i++;
+        }
+        for (int i=0; i < count; i++) {
+          wchar_t *wFilter = (wchar_t *)malloc(4096);
+          MultiByteToWideChar(CP_ACP, 0, filters[i], -1, wFilter, 4096);
+          rgSpec[i].pszName = wFilter;
+          rgSpec[i].pszSpec = wFilter;
+        }
+        if (dlg->lpVtbl->SetFileTypes(dlg, count, rgSpec) != S_OK) {
+          goto error_dlg;
+        }
+      }
+      if (dlg->lpVtbl->GetOptions(dlg, &opts) != S_OK)
+      {
+        goto error_dlg;
+      }
+      opts &= ~FOS_NOREADONLYRETURN;
+      opts |= add_opts;
+      if (dlg->lpVtbl->SetOptions(dlg, opts) != S_OK)
+      {
+        goto error_dlg;
+      }
+      if (dlg->lpVtbl->Show(dlg, w->priv.hwnd) != S_OK)
+      {
+        goto error_dlg;
+      }
+      if (dlg->lpVtbl->GetResult(dlg, &res) != S_OK)
+      {
+        goto error_dlg;
+      }
+      if (res->lpVtbl->GetDisplayName(res, SIGDN_FILESYSPATH, &ws) != S_OK)
+      {
+        goto error_result;
+      }
+      s = webview_from_utf16(ws);
+      strncpy(result, s, resultsz);
+      result[resultsz - 1] = '\0';
+      CoTaskMemFree(ws);
+    error_result:
+      res->lpVtbl->Release(res);
+    error_dlg:
+      dlg->lpVtbl->Release(dlg);
+      return;
+    }
+    else if (dlgtype == WEBVIEW_DIALOG_TYPE_ALERT)
+    {
+#if 0
+    /* MinGW often doesn't contain TaskDialog, we'll use MessageBox for now */
+    WCHAR *wtitle = webview_to_utf16(title);
+    WCHAR *warg = webview_to_utf16(arg);
+    TaskDialog(w->priv.hwnd, NULL, NULL, wtitle, warg, 0, NULL, NULL);
+    GlobalFree(warg);
+    GlobalFree(wtitle);
+#else
+      UINT type = MB_OK;
+      switch (flags & WEBVIEW_DIALOG_FLAG_ALERT_MASK)
+      {
+      case WEBVIEW_DIALOG_FLAG_INFO:
+        type |= MB_ICONINFORMATION;
+        break;
+      case WEBVIEW_DIALOG_FLAG_WARNING:
+        type |= MB_ICONWARNING;
+        break;
+      case WEBVIEW_DIALOG_FLAG_ERROR:
+        type |= MB_ICONERROR;
+        break;
+      }
+      MessageBox(w->priv.hwnd, arg, title, type);
+#endif
+    }
+  }
+
+  WEBVIEW_API void webview_terminate(struct webview *w) { PostQuitMessage(0); }
+  WEBVIEW_API void webview_exit(struct webview *w) { OleUninitialize(); }
+
+  WEBVIEW_API void webview_close(struct webview *w)
+  {
+    DestroyWindow(w->priv.hwnd);
+  }
+  WEBVIEW_API void webview_print_log(const char *s) { OutputDebugString(s); }
+
+#endif /* WEBVIEW_WINAPI */
+
+#if defined(WEBVIEW_COCOA)
+#if (!defined MAC_OS_X_VERSION_10_12) || \
+    MAC_OS_X_VERSION_MAX_ALLOWED < MAC_OS_X_VERSION_10_12
+#define NSAlertStyleWarning NSWarningAlertStyle
+#define NSAlertStyleCritical NSCriticalAlertStyle
+#define NSWindowStyleMaskResizable NSResizableWindowMask
+#define NSWindowStyleMaskMiniaturizable NSMiniaturizableWindowMask
+#define NSWindowStyleMaskTitled NSTitledWindowMask
+#define NSWindowStyleMaskClosable NSClosableWindowMask
+#define NSWindowStyleMaskFullScreen NSFullScreenWindowMask
+#define NSEventMaskAny NSAnyEventMask
+#define NSEventModifierFlagCommand NSCommandKeyMask
+#define NSEventModifierFlagOption NSAlternateKeyMask
+#define NSEventModifierFlagShift NSShiftKeyMask
+#define NSEventModifierFlagControl NSControlKeyMask
+#define NSEventTypeKeyDown NSKeyDown
+#define NSEventTypeKeyUp NSKeyUp
+#define NSEventTypeOtherMouseUp NSOtherMouseUp
+#define NSEventTypeScrollWheel NSScrollWheel
+#define NSAlertStyleInformational NSInformationalAlertStyle
+#endif /* MAC_OS_X_VERSION_10_12 */
+#if (!defined MAC_OS_X_VERSION_10_13) || \
+    MAC_OS_X_VERSION_MAX_ALLOWED < MAC_OS_X_VERSION_10_13
+#define NSModalResponseOK NSFileHandlingPanelOKButton
+#endif /* MAC_OS_X_VERSION_10_12, MAC_OS_X_VERSION_10_13 */
+  static void webview_window_will_close(id self, SEL cmd, id notification)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    [[NSNotificationCenter defaultCenter] removeObserver:self];
+    NSUserNotificationCenter *notifications =
+        [NSUserNotificationCenter defaultUserNotificationCenter];
+    if ([notifications delegate] == self)
+    {
+      [notifications setDelegate:nil];
+    }
+    if ([NSApp servicesProvider] == self)
+    {
+      [NSApp setServicesProvider:nil];
+    }
+    webview_tray_remove(w);
+    webview_set_menu(w, NULL);
+    webview_terminate(w);
+    // The panes close with the window
+    while (w->next_pane != NULL)
+    {
+      struct webview *pane = w->next_pane;
+      webview_unlink_pane(pane);
+      if (pane->closed_cb != NULL)
+      {
+        pane->closed_cb(pane);
+      }
+    }
+    if (w->closed_cb != NULL)
+    {
+      w->closed_cb(w);
+    }
+  }
+
+  // Only called when the user closes the window
+  static BOOL webview_window_should_close(id self, SEL cmd, id sender)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    return w->closing_cb == NULL || !w->closing_cb(w);
+  }
+
+  // Quitting from the menu closes the main window as its close button does,
+  // so it can be cancelled
+  static void webview_quit(id self, SEL cmd, id sender)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (webview_window_should_close(self, cmd, sender))
+    {
+      [w->priv.window close];
+    }
+  }
+
+  static void webview_about_clicked(id self, SEL cmd, id sender)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w != NULL)
+    {
+      webview_show_about(w);
+    }
+  }
+
+  static BOOL webview_is_selector_excluded_from_web_script(id self, SEL cmd,
+                                                           SEL selector)
+  {
+    return selector != @selector(invoke:);
+  }
+
+  static NSString *webview_webscript_name_for_selector(id self, SEL cmd,
+                                                       SEL selector)
+  {
+    return selector == @selector(invoke:) ? @"invoke" : nil;
+  }
+
+  static void webview_did_clear_window_object(id self, SEL cmd, id webview,
+                                              id script, id frame)
+  {
+    [script setValue:self forKey:@"external"];
+  }
+
+  // Asks geolocation_cb whether the page may have the user's location.
+  // Pages are denied it without one.
+  static void webview_decide_geolocation(id self, SEL cmd, id webview,
+                                         id origin, id frame, id listener)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w == NULL || w->geolocation_cb == NULL)
+    {
+      [listener performSelector:sel_registerName("deny")];
+      return;
+    }
+    NSString *url = [NSString stringWithFormat:@"%@://%@", [origin protocol],
+                                               [origin host]];
+    if ([origin port] != 0)
+    {
+      url = [url stringByAppendingFormat:@":%d", (int)[origin port]];
+    }
+    [listener retain];
+    w->geolocation_cb(w, [url UTF8String], listener);
+  }
+
+  // Legacy WebViews get the location from a provider, which is shared by the
+  // windows and follows CoreLocation while a page watches the location
+  static void webview_geolocation_register(id self, SEL cmd, id webview)
+  {
+    NSMutableSet *webviews = objc_getAssociatedObject(self, "webviews");
+    CLLocationManager *manager = objc_getAssociatedObject(self, "manager");
+    if (webviews == nil)
+    {
+      webviews = [NSMutableSet set];
+      objc_setAssociatedObject(self, "webviews", webviews,
+                               OBJC_ASSOCIATION_RETAIN);
+    }
+    if (manager == nil)
+    {
+      manager = [[[CLLocationManager alloc] init] autorelease];
+      [manager setDelegate:self];
+      objc_setAssociatedObject(self, "manager", manager,
+                               OBJC_ASSOCIATION_RETAIN);
+    }
+    [webviews addObject:webview];
+    [manager startUpdatingLocation];
+  }
+
+  static void webview_geolocation_unregister(id self, SEL cmd, id webview)
+  {
+    NSMutableSet *webviews = objc_getAssociatedObject(self, "webviews");
+    [webviews removeObject:webview];
+    if ([webviews count] == 0)
+    {
+      [objc_getAssociatedObject(self, "manager") stopUpdatingLocation];
+    }
+  }
+
+  static id webview_geolocation_last_position(id self, SEL cmd)
+  {
+    return objc_getAssociatedObject(self, "position");
+  }
+
+  static void webview_location_updated(id self, SEL cmd, id manager,
+                                       NSArray *locations)
+  {
+    CLLocation *location = [locations lastObject];
+    if (location == nil)
+    {
+      return;
+    }
+    id position =
+        ((id(*)(id, SEL, double, double, double, double))objc_msgSend)(
+            [objc_getClass("WebGeolocationPosition") alloc],
+            sel_registerName("initWithTimestamp:latitude:longitude:accuracy:"),
+            [[location timestamp] timeIntervalSince1970],
+            [location coordinate].latitude, [location coordinate].longitude,
+            [location horizontalAccuracy]);
+    objc_setAssociatedObject(self, "position", position, OBJC_ASSOCIATION_RETAIN);
+    [position release];
+    for (id webview in [objc_getAssociatedObject(self, "webviews") allObjects])
+    {
+      [webview performSelector:sel_registerName("_geolocationDidChangePosition:")
+                    withObject:position];
+    }
+  }
+
+  static void webview_location_failed(id self, SEL cmd, id manager,
+                                      NSError *error)
+  {
+    for (id webview in [objc_getAssociatedObject(self, "webviews") allObjects])
+    {
+      [webview performSelector:sel_registerName("_geolocationDidFailWithMessage:")
+                    withObject:[error localizedDescription]];
+    }
+  }
+
+  static void webview_set_geolocation_provider(WebView *webview)
+  {
+    static id provider = nil;
+    SEL setProvider = sel_registerName("_setGeolocationProvider:");
+    if (![webview respondsToSelector:setProvider])
+    {
+      return;
+    }
+    if (provider == nil)
+    {
+      Class providerClass = objc_allocateClassPair(
+          [NSObject class], "WebViewGeolocationProvider", 0);
+      class_addMethod(providerClass, sel_registerName("registerWebView:"),
+                      (IMP)webview_geolocation_register, "v@:@");
+      class_addMethod(providerClass, sel_registerName("unregisterWebView:"),
+                      (IMP)webview_geolocation_unregister, "v@:@");
+      class_addMethod(providerClass, sel_registerName("lastPosition"),
+                      (IMP)webview_geolocation_last_position, "@@:");
+      class_addMethod(providerClass,
+                      sel_registerName("locationManager:didUpdateLocations:"),
+                      (IMP)webview_location_updated, "v@:@@");
+      class_addMethod(providerClass,
+                      sel_registerName("locationManager:didFailWithError:"),
+                      (IMP)webview_location_failed, "v@:@@");
+      objc_registerClassPair(providerClass);
+      provider = [[providerClass alloc] init];
+    }
+    [webview performSelector:setProvider withObject:provider];
+  }
+
+  static void webview_run_input_open_panel(id self, SEL cmd, id webview,
+                                           id listener, BOOL allowMultiple)
+  {
+    char filename[256] = "";
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+
+    webview_dialog(w, WEBVIEW_DIALOG_TYPE_OPEN, WEBVIEW_DIALOG_FLAG_FILE, "", "",
+                   filename, 255, "");
+    filename[255] = '\0';
+    if (strlen(filename) > 0)
+    {
+      [listener chooseFilename:[NSString stringWithUTF8String:filename]];
+    }
+    else
+    {
+      [listener cancel];
+    }
+  }
+
+  static void webview_external_invoke(id self, SEL cmd, id arg)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w == NULL || w->external_invoke_cb == NULL)
+    {
+      return;
+    }
+    if ([arg isKindOfClass:[NSString class]] == NO)
+    {
+      return;
+    }
+    w->external_invoke_cb(w, [(NSString *)(arg) UTF8String]);
+  }
+
+  // Moves the traffic lights to the position set with
+  // webview_set_traffic_light_position. AppKit resets them on resize.
+  static void webview_position_traffic_lights(struct webview *w)
+  {
+    if (!w->priv.traffic_light_set)
+    {
+      return;
+    }
+    NSButton *close = [w->priv.window standardWindowButton:NSWindowCloseButton];
+    NSButton *minimise =
+        [w->priv.window standardWindowButton:NSWindowMiniaturizeButton];
+    NSButton *zoom = [w->priv.window standardWindowButton:NSWindowZoomButton];
+    if (close == nil || minimise == nil || zoom == nil)
+    {
+      return;
+    }
+
+    // Grow the title bar so the buttons aren't clipped
+    NSView *container = [[close superview] superview];
+    NSRect containerFrame = [container frame];
+    CGFloat height = [close frame].size.height + 2 * w->priv.traffic_light_y;
+    containerFrame.size.height = height;
+    containerFrame.origin.y = [w->priv.window frame].size.height - height;
+    [container setFrame:containerFrame];
+
+    CGFloat spacing = [minimise frame].origin.x - [close frame].origin.x;
+    NSArray *buttons = [NSArray arrayWithObjects:close, minimise, zoom, nil];
+    int i;
+    for (i = 0; i < 3; i++)
+    {
+      NSButton *button = [buttons objectAtIndex:i];
+      NSPoint origin = NSMakePoint(w->priv.traffic_light_x + i * spacing,
+                                   w->priv.traffic_light_y);
+      [button setFrameOrigin:origin];
+    }
+  }
+
+  static void webview_window_did_resize(id self, SEL cmd, id notification)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w != NULL)
+    {
+      webview_position_traffic_lights(w);
+      // Zooming is reported as maximising
+      int zoomed = [w->priv.window isZoomed];
+      if (zoomed != w->priv.zoomed)
+      {
+        w->priv.zoomed = zoomed;
+        webview_window_event(w, zoomed ? WEBVIEW_WINDOW_EVENT_MAXIMISE
+                                       : WEBVIEW_WINDOW_EVENT_RESTORE);
+      }
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_RESIZE);
+    }
+  }
+
+  // Reports the window's moves, changes of focus and minimising
+  static void webview_window_did_change(id self, SEL cmd, id notification)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w == NULL)
+    {
+      return;
+    }
+    if (cmd == @selector(windowDidMove:))
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_MOVE);
+    }
+    else if (cmd == @selector(windowDidBecomeKey:))
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_FOCUS);
+    }
+    else if (cmd == @selector(windowDidResignKey:))
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_BLUR);
+    }
+    else if (cmd == @selector(windowDidMiniaturize:))
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_MINIMISE);
+    }
+    else if (cmd == @selector(windowDidDeminiaturize:))
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_RESTORE);
+    }
+    else if (cmd == @selector(applicationDidChangeScreenParameters:))
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_DISPLAYS);
+    }
+    else if (cmd == @selector(windowDidChangeBackingProperties:))
+    {
+      webview_window_event(w, WEBVIEW_WINDOW_EVENT_SCALE);
     }
+  }
 
-    // Scale
-    // Credit: https://github.com/webview/webview/issues/54#issuecomment-379528243
-    HDC hDC = GetDC(NULL);
-    w->width = GetDeviceCaps(hDC, 88)*w->width/96.0;
-    w->height = GetDeviceCaps(hDC, 90)*w->height/96.0;
-    ReleaseDC(NULL, hDC);
+  // The sender's tag is the number of the menu item, or -1 for the icon
+  static void webview_tray_clicked(id self, SEL cmd, id sender)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w != NULL)
+    {
+      webview_tray_event(w, (int)[sender tag]);
+    }
+  }
 
-    rect.left = 0;
-    rect.top = 0;
-    rect.right = w->width;
-    rect.bottom = w->height;
-    AdjustWindowRect(&rect, WS_OVERLAPPEDWINDOW, 0);
+  // The notification's id is kept in its user info
+  static void webview_notification_activated(id self, SEL cmd, id center,
+                                             id notification)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    NSString *identifier =
+        [[notification userInfo] objectForKey:@"webview-notification"];
+    [center removeDeliveredNotification:notification];
+    if (w != NULL && identifier != nil)
+    {
+      webview_notification_event(w, [identifier UTF8String]);
+    }
+  }
 
-    GetClientRect(GetDesktopWindow(), &clientRect);
-    int left = (clientRect.right / 2) - ((rect.right - rect.left) / 2);
-    int top = (clientRect.bottom / 2) - ((rect.bottom - rect.top) / 2);
-    rect.right = rect.right - rect.left + left;
-    rect.left = left;
-    rect.bottom = rect.bottom - rect.top + top;
-    rect.top = top;
+  // Notifications are shown even when the app is active
+  static BOOL webview_notification_should_present(id self, SEL cmd, id center,
+                                                  id notification)
+  {
+    return YES;
+  }
+
+  // The files dragged out of a webview, see webview_start_drag, can only be
+  // copied
+  static NSDragOperation webview_dragging_source_operation(id self, SEL cmd,
+                                                           id session,
+                                                           NSInteger context)
+  {
+    return NSDragOperationCopy;
+  }
+
+  // WebView's own performDragOperation:, which gives the drop to the page
+  static IMP webview_perform_drag_operation_super = NULL;
+
+  // Replaces WebView's performDragOperation: to report the files dropped on
+  // the webview, before the page gets the drop as usual
+  static BOOL webview_perform_drag_operation(id self, SEL cmd,
+                                             id<NSDraggingInfo> info)
+  {
+    id delegate = [(WebView *)self UIDelegate];
+    struct webview *w =
+        delegate != nil
+            ? (struct webview *)objc_getAssociatedObject(delegate, "webview")
+            : NULL;
+    if (w != NULL && w->file_drop_cb != NULL)
+    {
+      NSArray *urls = [[info draggingPasteboard]
+          readObjectsForClasses:@[ [NSURL class] ]
+                        options:@{NSPasteboardURLReadingFileURLsOnlyKey : @YES}];
+      if ([urls count] > 0)
+      {
+        NSMutableArray *paths = [NSMutableArray array];
+        for (NSURL *url in urls)
+        {
+          [paths addObject:[url path]];
+        }
+        NSPoint point = [self convertPoint:[info draggingLocation] fromView:nil];
+        if (![self isFlipped])
+        {
+          point.y = [self bounds].size.height - point.y;
+        }
+        w->file_drop_cb(w, [[paths componentsJoinedByString:@"\n"] UTF8String],
+                        (int)point.x, (int)point.y);
+      }
+    }
+    return ((BOOL(*)(id, SEL, id<NSDraggingInfo>))
+                webview_perform_drag_operation_super)(self, cmd, info);
+  }
+
+  // Called for the services whose NSMessage is "share", with the files or
+  // text the user chose in the other app
+  static void webview_share_service(id self, SEL cmd, NSPasteboard *pboard,
+                                    NSString *userData, NSString **error)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w == NULL || w->share_cb == NULL)
+    {
+      return;
+    }
+    NSString *files = @"";
+    NSString *text = nil;
+    id paths = [pboard propertyListForType:NSFilenamesPboardType];
+    if ([paths isKindOfClass:[NSArray class]])
+    {
+      files = [paths componentsJoinedByString:@"\n"];
+    }
+    else
+    {
+      text = [pboard stringForType:NSPasteboardTypeString];
+    }
+    w->share_cb(w, text != nil ? [text UTF8String] : "", [files UTF8String]);
+  }
+
+  // The sender's tag is the number of the menu bar item
+  static void webview_menu_clicked(id self, SEL cmd, id sender)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w != NULL)
+    {
+      webview_menu_event(w, (int)[sender tag]);
+    }
+  }
+
+  // The sender's tag is the number of the context menu item
+  static void webview_context_menu_clicked(id self, SEL cmd, id sender)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w != NULL)
+    {
+      webview_context_menu_event(w, (int)[sender tag]);
+    }
+  }
+
+  // Called when the "+" button in the tab bar is clicked
+  static void webview_new_window_for_tab(id self, SEL cmd, id sender)
+  {
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w == NULL || w->new_tab_cb == NULL)
+    {
+      return;
+    }
+    w->new_tab_cb(w);
+  }
+
+  WEBVIEW_API int webview_init(struct webview *w)
+  {
+    w->priv.pool = [[NSAutoreleasePool alloc] init];
+    [NSApplication sharedApplication];
+
+    // The delegate class is registered by the first window
+    Class webViewDelegateClass = objc_getClass("WebViewDelegate");
+    if (webViewDelegateClass == nil)
+    {
+      webViewDelegateClass =
+          objc_allocateClassPair([NSObject class], "WebViewDelegate", 0);
+      class_addMethod(webViewDelegateClass, sel_registerName("windowWillClose:"),
+                      (IMP)webview_window_will_close, "v@:@");
+      class_addMethod(webViewDelegateClass, sel_registerName("windowShouldClose:"),
+                      (IMP)webview_window_should_close, "c@:@");
+      class_addMethod(webViewDelegateClass, sel_registerName("quit:"),
+                      (IMP)webview_quit, "v@:@");
+      class_addMethod(webViewDelegateClass, sel_registerName("about:"),
+                      (IMP)webview_about_clicked, "v@:@");
+      class_addMethod(object_getClass(webViewDelegateClass),
+                      sel_registerName("isSelectorExcludedFromWebScript:"),
+                      (IMP)webview_is_selector_excluded_from_web_script, "c@::");
+      class_addMethod(object_getClass(webViewDelegateClass),
+                      sel_registerName("webScriptNameForSelector:"),
+                      (IMP)webview_webscript_name_for_selector, "c@::");
+      class_addMethod(webViewDelegateClass,
+                      sel_registerName("webView:didClearWindowObject:forFrame:"),
+                      (IMP)webview_did_clear_window_object, "v@:@@@");
+      class_addMethod(
+          webViewDelegateClass,
+          sel_registerName("webView:runOpenPanelForFileButtonWithResultListener:"
+                           "allowMultipleFiles:"),
+          (IMP)webview_run_input_open_panel, "v@:@@c");
+      class_addMethod(webViewDelegateClass, sel_registerName("invoke:"),
+                      (IMP)webview_external_invoke, "v@:@");
+      class_addMethod(
+          webViewDelegateClass,
+          sel_registerName("webView:decidePolicyForGeolocationRequestFromOrigin:"
+                           "frame:listener:"),
+          (IMP)webview_decide_geolocation, "v@:@@@@");
+      class_addMethod(webViewDelegateClass, sel_registerName("windowDidResize:"),
+                      (IMP)webview_window_did_resize, "v@:@");
+      const char *changes[] = {"windowDidMove:", "windowDidBecomeKey:",
+                               "windowDidResignKey:", "windowDidMiniaturize:",
+                               "windowDidDeminiaturize:",
+                               "applicationDidChangeScreenParameters:",
+                               "windowDidChangeBackingProperties:"};
+      for (int i = 0; i < 7; i++)
+      {
+        class_addMethod(webViewDelegateClass, sel_registerName(changes[i]),
+                        (IMP)webview_window_did_change, "v@:@");
+      }
+      class_addMethod(webViewDelegateClass,
+                      sel_registerName("handleAutomationEvent:withReplyEvent:"),
+                      (IMP)webview_handle_automation_event, "v@:@@");
+      class_addMethod(webViewDelegateClass, sel_registerName("trayClicked:"),
+                      (IMP)webview_tray_clicked, "v@:@");
+      class_addMethod(webViewDelegateClass, sel_registerName("menuClicked:"),
+                      (IMP)webview_menu_clicked, "v@:@");
+      class_addMethod(
+          webViewDelegateClass,
+          sel_registerName("userNotificationCenter:didActivateNotification:"),
+          (IMP)webview_notification_activated, "v@:@@");
+      class_addMethod(
+          webViewDelegateClass,
+          sel_registerName("userNotificationCenter:shouldPresentNotification:"),
+          (IMP)webview_notification_should_present, "c@:@@");
+      class_addMethod(webViewDelegateClass,
+                      sel_registerName("share:userData:error:"),
+                      (IMP)webview_share_service, "v@:@@^@");
+      class_addMethod(webViewDelegateClass,
+                      sel_registerName("contextMenuClicked:"),
+                      (IMP)webview_context_menu_clicked, "v@:@");
+      class_addMethod(webViewDelegateClass,
+                      sel_registerName("draggingSession:"
+                                       "sourceOperationMaskForDraggingContext:"),
+                      (IMP)webview_dragging_source_operation, "Q@:@q");
+      // The "+" button is only shown if something responds to newWindowForTab:
+      if (w->tabbing)
+      {
+        class_addMethod(webViewDelegateClass, sel_registerName("newWindowForTab:"),
+                        (IMP)webview_new_window_for_tab, "v@:@");
+      }
+      objc_registerClassPair(webViewDelegateClass);
+
+      // Every webview reports the files dropped on it
+      Method drop = class_getInstanceMethod([WebView class],
+                                            @selector(performDragOperation:));
+      if (drop != NULL)
+      {
+        webview_perform_drag_operation_super = method_setImplementation(
+            drop, (IMP)webview_perform_drag_operation);
+      }
+    }
+
+    w->priv.delegate = [[webViewDelegateClass alloc] init];
+    objc_setAssociatedObject(w->priv.delegate, "webview", (id)(w),
+                             OBJC_ASSOCIATION_ASSIGN);
+
+    // Disable damn smart quotes
+    // Credit: https://stackoverflow.com/a/31640511
+    [[NSUserDefaults standardUserDefaults] setBool:NO forKey:@"NSAutomaticQuoteSubstitutionEnabled"];
+
+    NSRect r = NSMakeRect(0, 0, w->width, w->height);
+    NSUInteger style = NSWindowStyleMaskTitled | NSWindowStyleMaskClosable |
+                       NSWindowStyleMaskMiniaturizable;
+    if (w->resizable)
+    {
+      style = style | NSWindowStyleMaskResizable;
+      // style = style | NSTexturedBackgroundWindowMask;
+      // style = style | NSUnifiedTitleAndToolbarWindowMask;
+    }
+
+    // Transparent title bar
+    if (w->transparentTitlebar)
+    {
+      style = style | NSWindowStyleMaskFullSizeContentView;
+    }
+
+    w->priv.window = [[NSWindow alloc] initWithContentRect:r
+                                                 styleMask:style
+                                                   backing:NSBackingStoreBuffered
+                                                     defer:NO];
+    [w->priv.window autorelease];
+    if (w->secondary || w->embedded)
+    {
+      // Secondary windows are closed with webview_close, which must not
+      // release the window as well
+      [w->priv.window setReleasedWhenClosed:NO];
+    }
+
+    // Title
+    NSString *nsTitle = [NSString stringWithUTF8String:w->title];
+    [w->priv.window setTitle:nsTitle];
+
+    [w->priv.window setDelegate:w->priv.delegate];
+    [w->priv.window center];
+    [[NSNotificationCenter defaultCenter]
+        addObserver:w->priv.delegate
+           selector:@selector(applicationDidChangeScreenParameters:)
+               name:NSApplicationDidChangeScreenParametersNotification
+             object:nil];
+
+    // The main window provides the app's services, see webview_share_cb_t
+    if (!w->secondary && !w->embedded)
+    {
+      [NSApp setServicesProvider:w->priv.delegate];
+    }
+
+    // Window tabbing (macOS 10.12+)
+    if ([w->priv.window respondsToSelector:@selector(setTabbingMode:)])
+    {
+      if (w->tabbing)
+      {
+        [w->priv.window setTabbingMode:NSWindowTabbingModePreferred];
+        [w->priv.window setTabbingIdentifier:nsTitle];
+      }
+      else
+      {
+        [NSWindow setAllowsAutomaticWindowTabbing:NO];
+      }
+    }
+
+    //  NSToolbar *toolbar = [[NSToolbar alloc] initWithIdentifier:@"wat"];
+    //   toolbar.showsBaselineSeparator = NO;
+    //   [w->priv.window setToolbar:toolbar];
+
+    if (w->transparentTitlebar)
+    {
+      // Configure window look with hidden toolbar
+      [w->priv.window setTitlebarAppearsTransparent:YES];
+      [w->priv.window setTitleVisibility:NSWindowTitleHidden];
+      // w->priv.window.isMovableByWindowBackground = true;
+    }
+
+    [[NSUserDefaults standardUserDefaults] setBool:!!w->debug
+                                            forKey:@"WebKitDeveloperExtras"];
+    [[NSUserDefaults standardUserDefaults] synchronize];
+    w->priv.webview =
+        [[WebView alloc] initWithFrame:r
+                             frameName:@"WebView"
+                             groupName:nil];
+    NSURL *nsURL = [NSURL
+        URLWithString:[NSString stringWithUTF8String:webview_check_url(w->url)]];
+    [[w->priv.webview mainFrame] loadRequest:[NSURLRequest requestWithURL:nsURL]];
+
+    [w->priv.webview setAutoresizesSubviews:YES];
+    [w->priv.webview
+        setAutoresizingMask:NSViewWidthSizable | NSViewHeightSizable];
+    w->priv.webview.frameLoadDelegate = w->priv.delegate;
+    w->priv.webview.UIDelegate = w->priv.delegate;
+    webview_set_geolocation_provider(w->priv.webview);
+    if (w->transparent)
+    {
+      [w->priv.window setOpaque:NO];
+      [w->priv.window setBackgroundColor:[NSColor clearColor]];
+      [w->priv.webview setDrawsBackground:NO];
+      if (w->backdrop == WEBVIEW_BACKDROP_TRANSLUCENT)
+      {
+        // Blur the desktop behind the page. The view is kept so that
+        // webview_set_material can change it.
+        NSVisualEffectView *effect =
+            [[[NSVisualEffectView alloc] initWithFrame:r] autorelease];
+        [effect setBlendingMode:NSVisualEffectBlendingModeBehindWindow];
+        [effect setState:NSVisualEffectStateActive];
+        [effect setAutoresizingMask:NSViewWidthSizable | NSViewHeightSizable];
+        [[w->priv.window contentView] addSubview:effect];
+        w->priv.effect = effect;
+      }
+    }
+    [[w->priv.window contentView] addSubview:w->priv.webview];
+    if (!w->hidden)
+    {
+      [w->priv.window orderFrontRegardless];
+    }
+
+    // Disable scrolling - make this configurable
+    // [[[w->priv.webview mainFrame] frameView] setAllowsScrolling:NO];
+
+    // ----> Enables WebGL but won't pass the app store guidelines
+    //
+    // WebPreferences *p = [w->priv.webview preferences];
+    // if ([p respondsToSelector:@selector(setWebGLEnabled:)]) {
+    //     [p setWebGLEnabled:YES];
+    // }
+
+    // The host app of an embedded window has set up the application
+    if (w->embedded)
+    {
+      w->priv.should_exit = 0;
+      return 0;
+    }
+
+    [NSApp setActivationPolicy:NSApplicationActivationPolicyRegular];
+    [NSApp finishLaunching];
+    if (!w->hidden)
+    {
+      [NSApp activateIgnoringOtherApps:YES];
+    }
+
+    NSMenu *menubar = [[[NSMenu alloc] initWithTitle:@""] autorelease];
+
+    NSMenuItem *appMenuItem =
+        [[[NSMenuItem alloc] initWithTitle:@"wails app" action:NULL keyEquivalent:@""]
+            autorelease];
+    NSMenu *appMenu = [[[NSMenu alloc] initWithTitle:@"wails app"] autorelease];
+    [appMenuItem setSubmenu:appMenu];
+    [menubar addItem:appMenuItem];
+
+    NSMenuItem *item = [[[NSMenuItem alloc]
+        initWithTitle:[NSString stringWithFormat:@"About %s",
+                                                 webview_about.name != NULL
+                                                     ? webview_about.name
+                                                     : w->title]
+               action:@selector(about:)
+        keyEquivalent:@""] autorelease];
+    [item setTarget:w->priv.delegate];
+    [appMenu addItem:item];
+    [appMenu addItem:[NSMenuItem separatorItem]];
+
+    item = [[[NSMenuItem alloc] initWithTitle:@"Hide"
+                                                   action:@selector(hide:)
+                                            keyEquivalent:@"h"] autorelease];
+    [appMenu addItem:item];
+    item = [[[NSMenuItem alloc] initWithTitle:@"Hide Others"
+                                       action:@selector(hideOtherApplications:)
+                                keyEquivalent:@"h"] autorelease];
+    [item setKeyEquivalentModifierMask:(NSEventModifierFlagOption |
+                                        NSEventModifierFlagCommand)];
+    [appMenu addItem:item];
+    item = [[[NSMenuItem alloc] initWithTitle:@"Show All"
+                                       action:@selector(unhideAllApplications:)
+                                keyEquivalent:@""] autorelease];
+    [appMenu addItem:item];
+    [appMenu addItem:[NSMenuItem separatorItem]];
+
+    NSMenuItem *editMenuItem =
+        [[[NSMenuItem alloc] initWithTitle:@"Edit" action:NULL keyEquivalent:@""]
+            autorelease];
+    NSMenu *editMenu = [[[NSMenu alloc] initWithTitle:@"Edit"] autorelease];
+    [editMenuItem setSubmenu:editMenu];
+    [menubar addItem:editMenuItem];
+
+    item = [[[NSMenuItem alloc] initWithTitle:@"Cut"
+                                       action:@selector(cut:)
+                                keyEquivalent:@"x"] autorelease];
+    [editMenu addItem:item];
+
+    item = [[[NSMenuItem alloc] initWithTitle:@"Copy"
+                                       action:@selector(copy:)
+                                keyEquivalent:@"c"] autorelease];
+    [editMenu addItem:item];
+
+    item = [[[NSMenuItem alloc] initWithTitle:@"Paste"
+                                       action:@selector(paste:)
+                                keyEquivalent:@"v"] autorelease];
+    [editMenu addItem:item];
+
+    item = [[[NSMenuItem alloc] initWithTitle:@"Select All"
+                                       action:@selector(selectAll:)
+                                keyEquivalent:@"a"] autorelease];
+    [editMenu addItem:item];
 
-#ifdef UNICODE
-    wchar_t *u16title = webview_to_utf16(w->title);
-    if (u16title == NULL)
+    if (w->tabbing)
     {
-      return -1;
+      NSMenuItem *windowMenuItem =
+          [[[NSMenuItem alloc] initWithTitle:@"Window" action:NULL keyEquivalent:@""]
+              autorelease];
+      NSMenu *windowMenu = [[[NSMenu alloc] initWithTitle:@"Window"] autorelease];
+      [windowMenuItem setSubmenu:windowMenu];
+      [menubar addItem:windowMenuItem];
+
+      item = [[[NSMenuItem alloc] initWithTitle:@"Show Tab Bar"
+                                         action:@selector(toggleTabBar:)
+                                  keyEquivalent:@""] autorelease];
+      [windowMenu addItem:item];
+
+      item = [[[NSMenuItem alloc] initWithTitle:@"Merge All Windows"
+                                         action:@selector(mergeAllWindows:)
+                                  keyEquivalent:@""] autorelease];
+      [windowMenu addItem:item];
+
+      [NSApp setWindowsMenu:windowMenu];
     }
 
-    w->priv.hwnd =
-        CreateWindowEx(0, classname, u16title, style, rect.left, rect.top,
-                       rect.right - rect.left, rect.bottom - rect.top,
-                       HWND_DESKTOP, NULL, hInstance, (void *)w);
-#else
-    w->priv.hwnd =
-        CreateWindowEx(0, classname, w->title, style, rect.left, rect.top,
-                       rect.right - rect.left, rect.bottom - rect.top,
-                       HWND_DESKTOP, NULL, hInstance, (void *)w);
-#endif
+    // Kiosk apps can't be quit with the keyboard
+    if (!w->kiosk)
+    {
+      [appMenu addItem:[NSMenuItem separatorItem]];
 
-    if (w->priv.hwnd == 0)
+      item = [[[NSMenuItem alloc] initWithTitle:@"Quit"
+                                         action:@selector(quit:)
+                                  keyEquivalent:@"q"] autorelease];
+      [item setTarget:w->priv.delegate];
+      [appMenu addItem:item];
+    }
+
+    [NSApp setMainMenu:menubar];
+
+    if (w->kiosk)
     {
-      OleUninitialize();
-      return -1;
+      // Hide the dock and menu bar and stop the user switching apps
+      [NSApp setPresentationOptions:
+                 NSApplicationPresentationHideDock |
+                 NSApplicationPresentationHideMenuBar |
+                 NSApplicationPresentationDisableProcessSwitching |
+                 NSApplicationPresentationDisableForceQuit |
+                 NSApplicationPresentationDisableSessionTermination |
+                 NSApplicationPresentationDisableHideApplication];
+      webview_set_borderless(w, 1);
+      [w->priv.window setFrame:[[w->priv.window screen] frame] display:YES];
+      webview_set_always_on_top(w, 1);
     }
 
-    SetWindowLongPtr(w->priv.hwnd, GWLP_USERDATA, (LONG_PTR)w);
+    w->priv.should_exit = 0;
+    return 0;
+  }
 
-    DisplayHTMLPage(w);
+  // Panes share a split view with the host's webview, which replaces it in
+  // the window when the first pane is docked. All the panes take the
+  // orientation of the first.
+  WEBVIEW_API int webview_init_pane(struct webview *w)
+  {
+    struct webview *host = w->host;
+    w->priv.window = host->priv.window;
+    w->priv.delegate = [[objc_getClass("WebViewDelegate") alloc] init];
+    objc_setAssociatedObject(w->priv.delegate, "webview", (id)(w),
+                             OBJC_ASSOCIATION_ASSIGN);
 
-#ifdef UNICODE
-    SetWindowText(w->priv.hwnd, u16title);
-    GlobalFree(u16title);
-#else
-    SetWindowText(w->priv.hwnd, w->title);
-#endif
+    NSRect r = NSMakeRect(0, 0, w->width, w->height);
+    w->priv.webview =
+        [[WebView alloc] initWithFrame:r
+                             frameName:@"WebView"
+                             groupName:nil];
+    NSURL *nsURL = [NSURL
+        URLWithString:[NSString stringWithUTF8String:webview_check_url(w->url)]];
+    [[w->priv.webview mainFrame] loadRequest:[NSURLRequest requestWithURL:nsURL]];
+    [w->priv.webview setAutoresizesSubviews:YES];
+    w->priv.webview.frameLoadDelegate = w->priv.delegate;
+    w->priv.webview.UIDelegate = w->priv.delegate;
+    webview_set_geolocation_provider(w->priv.webview);
+    if (host->transparent)
+    {
+      [w->priv.webview setDrawsBackground:NO];
+    }
 
-    ShowWindow(w->priv.hwnd, SW_SHOWDEFAULT);
-    UpdateWindow(w->priv.hwnd);
-    SetFocus(w->priv.hwnd);
+    NSView *parent = [host->priv.webview superview];
+    NSSplitView *split;
+    if ([parent isKindOfClass:[NSSplitView class]])
+    {
+      split = (NSSplitView *)parent;
+    }
+    else
+    {
+      split = [[[NSSplitView alloc] initWithFrame:[host->priv.webview frame]]
+          autorelease];
+      [split setVertical:!w->vertical];
+      [split setDividerStyle:NSSplitViewDividerStyleThin];
+      [split setAutoresizingMask:NSViewWidthSizable | NSViewHeightSizable];
+      [parent addSubview:split];
+      [split addSubview:host->priv.webview];
+    }
+    [split addSubview:w->priv.webview];
+    [split adjustSubviews];
+
+    // The pane is given its width, or its height when vertical
+    NSInteger divider = [[split subviews] count] - 2;
+    CGFloat total = [split isVertical] ? [split frame].size.width
+                                       : [split frame].size.height;
+    CGFloat size = [split isVertical] ? w->width : w->height;
+    if (total > size)
+    {
+      [split setPosition:total - size ofDividerAtIndex:divider];
+    }
 
+    webview_link_pane(w);
+    w->priv.should_exit = 0;
     return 0;
   }
 
-  WEBVIEW_API int webview_loop(struct webview *w, int blocking)
-  {
-    MSG msg;
-    if (blocking)
+  static const struct webview_key_name webview_key_names[] = {
+      {36, "Enter"},      {76, "Enter"},       {53, "Escape"},
+      {48, "Tab"},        {51, "Backspace"},   {117, "Delete"},
+      {114, "Insert"},    {115, "Home"},       {119, "End"},
+      {116, "PageUp"},    {121, "PageDown"},   {123, "ArrowLeft"},
+      {124, "ArrowRight"}, {126, "ArrowUp"},   {125, "ArrowDown"},
+      {49, "Space"}};
+
+  // Gives a key pressed in one of the webview's windows to the webview
+  // before the window sends it on. Returns non-zero if it was consumed.
+  static int webview_key_ns_event(NSEvent *event)
+  {
+    char key[16] = "";
+    struct webview *w = (struct webview *)objc_getAssociatedObject(
+        [[event window] delegate], "webview");
+    if (w == NULL || !w->capture_keys)
     {
-      GetMessage(&msg, 0, 0, 0);
+      return 0;
     }
-    else
+    for (size_t i = 0; i < sizeof(webview_key_names) / sizeof(webview_key_names[0]); i++)
     {
-      PeekMessage(&msg, 0, 0, 0, PM_REMOVE);
+      if (webview_key_names[i].code == [event keyCode])
+      {
+        strcpy(key, webview_key_names[i].name);
+      }
     }
-    switch (msg.message)
+    NSString *characters = [event charactersIgnoringModifiers];
+    unichar c = [characters length] > 0 ? [characters characterAtIndex:0] : 0;
+    if (key[0] == '\0' && c >= NSF1FunctionKey && c <= NSF35FunctionKey)
     {
-    case WM_QUIT:
-      return -1;
-    case WM_COMMAND:
-    case WM_KEYDOWN:
-    case WM_KEYUP:
+      snprintf(key, sizeof(key), "F%d", (int)(c - NSF1FunctionKey + 1));
+    }
+    // Other function keys are in a private use area
+    if (key[0] == '\0' && c != 0 && (c < 0xF700 || c > 0xF8FF))
     {
-      // Disable refresh when pressing F5 on windows
-      if (msg.wParam == VK_F5)
+      strncpy(key, [[characters lowercaseString] UTF8String], sizeof(key) - 1);
+    }
+
+    NSUInteger flags = [event modifierFlags];
+    int modifiers = 0;
+    modifiers |= (flags & NSEventModifierFlagShift) ? WEBVIEW_KEY_SHIFT : 0;
+    modifiers |= (flags & NSEventModifierFlagControl) ? WEBVIEW_KEY_CONTROL : 0;
+    modifiers |= (flags & NSEventModifierFlagOption) ? WEBVIEW_KEY_ALT : 0;
+    modifiers |= (flags & NSEventModifierFlagCommand) ? WEBVIEW_KEY_SUPER : 0;
+    return webview_key_event(w, key, modifiers,
+                             [event type] == NSEventTypeKeyDown);
+  }
+
+  // Gives a side button click, sideways scroll or swipe in one of the
+  // webview's windows to the webview before the window sends it on.
+  // Returns non-zero if it was consumed.
+  static int webview_gesture_ns_event(NSEvent *event)
+  {
+    struct webview *w = (struct webview *)objc_getAssociatedObject(
+        [[event window] delegate], "webview");
+    if (w == NULL || !w->capture_gestures)
+    {
+      return 0;
+    }
+    switch ([event type])
+    {
+    case NSEventTypeOtherMouseUp:
+      // Buttons 3 and 4 are the mouse's back and forward buttons
+      if ([event buttonNumber] == 3)
       {
-        break;
+        return webview_gesture_event(w, WEBVIEW_GESTURE_BACK, 0, 0);
       }
-      HRESULT r = S_OK;
-      IWebBrowser2 *webBrowser2;
-      IOleObject *browser = *w->priv.browser;
-      if (browser->lpVtbl->QueryInterface(browser, iid_unref(&IID_IWebBrowser2),
-                                          (void **)&webBrowser2) == S_OK)
+      if ([event buttonNumber] == 4)
       {
-        IOleInPlaceActiveObject *pIOIPAO;
-        if (browser->lpVtbl->QueryInterface(
-                browser, iid_unref(&IID_IOleInPlaceActiveObject),
-                (void **)&pIOIPAO) == S_OK)
-        {
-          r = pIOIPAO->lpVtbl->TranslateAccelerator(pIOIPAO, &msg);
-          pIOIPAO->lpVtbl->Release(pIOIPAO);
-        }
-        webBrowser2->lpVtbl->Release(webBrowser2);
+        return webview_gesture_event(w, WEBVIEW_GESTURE_FORWARD, 0, 0);
       }
-      if (r != S_FALSE)
+      return 0;
+    case NSEventTypeScrollWheel:
+      // The deltas are positive to the left and up
+      if ([event deltaX] == 0)
       {
-        break;
+        return 0;
       }
-    }
+      return webview_gesture_event(w, WEBVIEW_GESTURE_SCROLL, -[event deltaX],
+                                   0);
+    case NSEventTypeSwipe:
+      return webview_gesture_event(w, WEBVIEW_GESTURE_SWIPE, -[event deltaX],
+                                   -[event deltaY]);
     default:
-      TranslateMessage(&msg);
-      DispatchMessage(&msg);
+      return 0;
     }
-    return 0;
+  }
+
+  WEBVIEW_API int webview_loop(struct webview *w, int blocking)
+  {
+    NSDate *until = (blocking ? [NSDate distantFuture] : [NSDate distantPast]);
+    NSEvent *event = [NSApp nextEventMatchingMask:NSEventMaskAny
+                                        untilDate:until
+                                           inMode:NSDefaultRunLoopMode
+                                          dequeue:YES];
+    if (event)
+    {
+      NSEventType type = [event type];
+      int consumed = (type == NSEventTypeKeyDown || type == NSEventTypeKeyUp)
+                         ? webview_key_ns_event(event)
+                         : webview_gesture_ns_event(event);
+      if (!consumed)
+      {
+        [NSApp sendEvent:event];
+      }
+    }
+    return w->priv.should_exit;
   }
 
   WEBVIEW_API int webview_eval(struct webview *w, const char *js)
   {
-    IWebBrowser2 *webBrowser2;
-    IHTMLDocument2 *htmlDoc2;
-    IDispatch *docDispatch;
-    IDispatch *scriptDispatch;
-    if ((*w->priv.browser)
-            ->lpVtbl->QueryInterface((*w->priv.browser),
-                                     iid_unref(&IID_IWebBrowser2),
-                                     (void **)&webBrowser2) != S_OK)
+    NSString *nsJS = [NSString stringWithUTF8String:js];
+    [[w->priv.webview windowScriptObject] evaluateWebScript:nsJS];
+    return 0;
+  }
+
+  WEBVIEW_API void webview_set_title(struct webview *w, const char *title)
+  {
+    NSString *nsTitle = [NSString stringWithUTF8String:title];
+    [w->priv.window setTitle:nsTitle];
+  }
+
+  // Windows don't have icons on MacOS, so the dock icon is changed instead
+  WEBVIEW_API int webview_set_icon(struct webview *w, const uint8_t *data,
+                                   int size)
+  {
+    if (size == 0)
+    {
+      [NSApp setApplicationIconImage:nil];
+      return 0;
+    }
+    NSData *iconData = [NSData dataWithBytes:data length:size];
+    NSImage *icon = [[[NSImage alloc] initWithData:iconData] autorelease];
+    if (icon == nil)
     {
       return -1;
     }
+    [NSApp setApplicationIconImage:icon];
+    return 0;
+  }
 
-    if (webBrowser2->lpVtbl->get_Document(webBrowser2, &docDispatch) != S_OK)
+  // The standard about panel falls back to the app's bundle for anything
+  // that isn't set
+  WEBVIEW_API void webview_show_about(struct webview *w)
+  {
+    NSMutableDictionary *options = [NSMutableDictionary dictionary];
+    [options setObject:[NSString stringWithUTF8String:webview_about.name != NULL
+                                                          ? webview_about.name
+                                                          : w->title]
+                forKey:@"ApplicationName"];
+    if (webview_about.version != NULL)
     {
-      return -1;
+      [options setObject:[NSString stringWithUTF8String:webview_about.version]
+                  forKey:@"ApplicationVersion"];
     }
-    if (docDispatch->lpVtbl->QueryInterface(docDispatch,
-                                            iid_unref(&IID_IHTMLDocument2),
-                                            (void **)&htmlDoc2) != S_OK)
+    if (webview_about.copyright != NULL)
     {
-      return -1;
+      [options setObject:[NSString stringWithUTF8String:webview_about.copyright]
+                  forKey:@"Copyright"];
     }
-    if (htmlDoc2->lpVtbl->get_Script(htmlDoc2, &scriptDispatch) != S_OK)
+    if (webview_about.iconsz > 0)
+    {
+      NSImage *icon = [[[NSImage alloc]
+          initWithData:[NSData dataWithBytes:webview_about.icon
+                                      length:webview_about.iconsz]] autorelease];
+      if (icon != nil)
+      {
+        [options setObject:icon forKey:@"ApplicationIcon"];
+      }
+    }
+    [NSApp orderFrontStandardAboutPanelWithOptions:options];
+    [NSApp activateIgnoringOtherApps:YES];
+  }
+
+  // The Force Touch lookup is the only system gesture over a WebView, and
+  // the page cancels it
+  WEBVIEW_API void webview_set_system_gestures(struct webview *w, int enabled)
+  {
+    (void)w;
+    (void)enabled;
+  }
+
+  // The drag is started with a mouse event of its own, as this is called
+  // from a dispatched block rather than the page's mouse event. Each file is
+  // shown by its icon under the mouse.
+  WEBVIEW_API int webview_start_drag(struct webview *w, const char *files)
+  {
+    if (([NSEvent pressedMouseButtons] & 1) == 0)
+    {
+      return 0;
+    }
+    NSPoint location = [w->priv.window mouseLocationOutsideOfEventStream];
+    NSEvent *event =
+        [NSEvent mouseEventWithType:NSEventTypeLeftMouseDragged
+                           location:location
+                      modifierFlags:0
+                          timestamp:[[NSProcessInfo processInfo] systemUptime]
+                       windowNumber:[w->priv.window windowNumber]
+                            context:nil
+                        eventNumber:0
+                         clickCount:1
+                           pressure:1];
+    NSPoint point = [w->priv.webview convertPoint:location fromView:nil];
+    NSMutableArray *items = [NSMutableArray array];
+    NSArray *paths =
+        [[NSString stringWithUTF8String:files] componentsSeparatedByString:@"\n"];
+    for (NSString *path in paths)
+    {
+      if ([path length] == 0)
+      {
+        continue;
+      }
+      NSDraggingItem *item = [[[NSDraggingItem alloc]
+          initWithPasteboardWriter:[NSURL fileURLWithPath:path]] autorelease];
+      CGFloat offset = 8 * [items count];
+      [item setDraggingFrame:NSMakeRect(point.x - 16 + offset,
+                                        point.y - 16 - offset, 32, 32)
+                    contents:[[NSWorkspace sharedWorkspace] iconForFile:path]];
+      [items addObject:item];
+    }
+    if ([items count] == 0)
+    {
+      return 0;
+    }
+    [w->priv.webview beginDraggingSessionWithItems:items
+                                             event:event
+                                            source:w->priv.delegate];
+    return 1;
+  }
+
+  WEBVIEW_API void webview_focus(struct webview *w)
+  {
+    [w->priv.window makeKeyWindow];
+  }
+
+  WEBVIEW_API void webview_reload(struct webview *w)
+  {
+    [w->priv.webview reload:nil];
+  }
+
+  WEBVIEW_API void webview_navigate(struct webview *w, const char *url)
+  {
+    w->url = url;
+    NSURL *nsURL = [NSURL
+        URLWithString:[NSString stringWithUTF8String:webview_check_url(url)]];
+    [[w->priv.webview mainFrame] loadRequest:[NSURLRequest requestWithURL:nsURL]];
+  }
+  
+  WEBVIEW_API void webview_minsize(struct webview *w, int width, int height) {
+    NSSize size;
+    size.width = width;
+    size.height = height;
+    [w->priv.window setMinSize:size];
+  }
+  
+  WEBVIEW_API void webview_maxsize(struct webview *w, int width, int height) {
+    NSSize size;
+    size.width = width;
+    size.height = height;
+    [w->priv.window setMaxSize:size];
+
+    NSButton *button = [w->priv.window standardWindowButton:NSWindowZoomButton];
+    [button performSelectorOnMainThread:@selector(setEnabled:) withObject:NO
+    waitUntilDone:NO];
+  }
+
+  WEBVIEW_API void webview_set_aspect_ratio(struct webview *w, int width,
+                                            int height)
+  {
+    if (width > 0 && height > 0)
+    {
+      [w->priv.window setContentAspectRatio:NSMakeSize(width, height)];
+    }
+    else
+    {
+      // Resize increments replace the aspect ratio
+      [w->priv.window setContentResizeIncrements:NSMakeSize(1, 1)];
+    }
+  }
+  
+  WEBVIEW_API void webview_set_fullscreen(struct webview *w, int fullscreen)
+  {
+    int b = ((([w->priv.window styleMask] & NSWindowStyleMaskFullScreen) ==
+              NSWindowStyleMaskFullScreen)
+                 ? 1
+                 : 0);
+    if (b != fullscreen)
+    {
+      [w->priv.window toggleFullScreen:nil];
+    }
+  }
+
+  WEBVIEW_API void webview_set_always_on_top(struct webview *w, int onTop)
+  {
+    [w->priv.window setLevel:(onTop ? NSFloatingWindowLevel : NSNormalWindowLevel)];
+  }
+
+  WEBVIEW_API void webview_set_borderless(struct webview *w, int borderless)
+  {
+    NSUInteger style = NSWindowStyleMaskBorderless;
+    if (!borderless)
+    {
+      style = NSWindowStyleMaskTitled | NSWindowStyleMaskClosable |
+              NSWindowStyleMaskMiniaturizable;
+      if (w->resizable)
+      {
+        style = style | NSWindowStyleMaskResizable;
+      }
+      if (w->transparentTitlebar)
+      {
+        style = style | NSWindowStyleMaskFullSizeContentView;
+      }
+    }
+    [w->priv.window setStyleMask:style];
+  }
+
+  WEBVIEW_API void webview_set_size(struct webview *w, int width, int height)
+  {
+    [w->priv.window setContentSize:NSMakeSize(width, height)];
+  }
+
+  WEBVIEW_API void webview_snap_to_corner(struct webview *w, int corner, int margin)
+  {
+    // Cocoa's origin is the bottom left of the screen
+    static const int flipped[] = {
+        WEBVIEW_CORNER_BOTTOM_LEFT, WEBVIEW_CORNER_BOTTOM_RIGHT,
+        WEBVIEW_CORNER_TOP_LEFT, WEBVIEW_CORNER_TOP_RIGHT};
+    NSRect area = [[w->priv.window screen] visibleFrame];
+    NSRect frame = [w->priv.window frame];
+    int x, y;
+    webview_corner_position(flipped[corner & 3], margin, area.origin.x,
+                            area.origin.y, area.size.width, area.size.height,
+                            frame.size.width, frame.size.height, &x, &y);
+    [w->priv.window setFrameOrigin:NSMakePoint(x, y)];
+  }
+
+  WEBVIEW_API void webview_set_ignore_mouse_events(struct webview *w, int ignore)
+  {
+    [w->priv.window setIgnoresMouseEvents:(ignore ? YES : NO)];
+  }
+
+  WEBVIEW_API void webview_cursor_position(struct webview *w, int *x, int *y)
+  {
+    // Convert from screen coordinates to top left based content coordinates
+    NSPoint p = [NSEvent mouseLocation];
+    NSRect r = [w->priv.window convertRectFromScreen:NSMakeRect(p.x, p.y, 0, 0)];
+    *x = r.origin.x;
+    *y = [[w->priv.window contentView] frame].size.height - r.origin.y;
+  }
+
+  // Returns the given screen or, if it doesn't exist, the one the window is on
+  static NSScreen *webview_screen(struct webview *w, int display)
+  {
+    NSArray *screens = [NSScreen screens];
+    if (display >= 0 && display < (int)[screens count])
     {
-      return -1;
+      return [screens objectAtIndex:display];
     }
-    DISPID dispid;
-    BSTR evalStr = SysAllocString(L"eval");
-    if (scriptDispatch->lpVtbl->GetIDsOfNames(
-            scriptDispatch, iid_unref(&IID_NULL), &evalStr, 1,
-            LOCALE_SYSTEM_DEFAULT, &dispid) != S_OK)
+    return [w->priv.window screen];
+  }
+
+  WEBVIEW_API int webview_display_count(struct webview *w)
+  {
+    return (int)[[NSScreen screens] count];
+  }
+
+  WEBVIEW_API void webview_display_workarea(struct webview *w, int display, int *x,
+                                            int *y, int *width, int *height)
+  {
+    // Flip to top left based coordinates relative to the primary screen
+    NSRect primary = [[[NSScreen screens] objectAtIndex:0] frame];
+    NSRect area = [webview_screen(w, display) visibleFrame];
+    *x = area.origin.x;
+    *y = primary.size.height - (area.origin.y + area.size.height);
+    *width = area.size.width;
+    *height = area.size.height;
+  }
+
+  WEBVIEW_API void webview_display_bounds(struct webview *w, int display, int *x,
+                                         int *y, int *width, int *height,
+                                         double *scale, int *primary)
+  {
+    // Flip to top left based coordinates relative to the primary screen
+    NSScreen *primaryScreen = [[NSScreen screens] objectAtIndex:0];
+    NSScreen *screen = webview_screen(w, display);
+    NSRect area = [screen frame];
+    *x = area.origin.x;
+    *y = [primaryScreen frame].size.height - (area.origin.y + area.size.height);
+    *width = area.size.width;
+    *height = area.size.height;
+    *scale = [screen backingScaleFactor];
+    *primary = screen == primaryScreen;
+  }
+
+  WEBVIEW_API int webview_cursor_display(struct webview *w)
+  {
+    NSArray *screens = [NSScreen screens];
+    NSPoint p = [NSEvent mouseLocation];
+    int i;
+    for (i = 0; i < (int)[screens count]; i++)
     {
-      SysFreeString(evalStr);
-      return -1;
+      if (NSMouseInRect(p, [[screens objectAtIndex:i] frame], NO))
+      {
+        return i;
+      }
     }
-    SysFreeString(evalStr);
+    return 0;
+  }
 
-    DISPPARAMS params;
-    VARIANT arg;
-    VARIANT result;
-    EXCEPINFO excepInfo;
-    UINT nArgErr = (UINT)-1;
-    params.cArgs = 1;
-    params.cNamedArgs = 0;
-    params.rgvarg = &arg;
-    arg.vt = VT_BSTR;
-    static const char *prologue = "(function(){";
-    static const char *epilogue = ";})();";
-    int n = strlen(prologue) + strlen(epilogue) + strlen(js) + 1;
-    char *eval = (char *)malloc(n);
-    snprintf(eval, n, "%s%s%s", prologue, js, epilogue);
-    wchar_t *buf = webview_to_utf16(eval);
-    if (buf == NULL)
+  WEBVIEW_API double webview_get_scale(struct webview *w)
+  {
+    return [w->priv.window backingScaleFactor];
+  }
+
+  WEBVIEW_API void webview_place(struct webview *w, int display, int edge, int margin)
+  {
+    // Cocoa's origin is the bottom left of the screen
+    if (edge == WEBVIEW_EDGE_TOP)
     {
-      return -1;
+      edge = WEBVIEW_EDGE_BOTTOM;
     }
-    arg.bstrVal = SysAllocString(buf);
-    if (scriptDispatch->lpVtbl->Invoke(
-            scriptDispatch, dispid, iid_unref(&IID_NULL), 0, DISPATCH_METHOD,
-            &params, &result, &excepInfo, &nArgErr) != S_OK)
+    else if (edge == WEBVIEW_EDGE_BOTTOM)
     {
-      return -1;
+      edge = WEBVIEW_EDGE_TOP;
     }
-    SysFreeString(arg.bstrVal);
-    free(eval);
-    scriptDispatch->lpVtbl->Release(scriptDispatch);
-    htmlDoc2->lpVtbl->Release(htmlDoc2);
-    docDispatch->lpVtbl->Release(docDispatch);
-    return 0;
+    NSRect area = [webview_screen(w, display) visibleFrame];
+    NSRect frame = [w->priv.window frame];
+    int x, y;
+    webview_edge_position(edge, margin, area.origin.x, area.origin.y,
+                          area.size.width, area.size.height, frame.size.width,
+                          frame.size.height, &x, &y);
+    [w->priv.window setFrameOrigin:NSMakePoint(x, y)];
   }
 
-  WEBVIEW_API void webview_dispatch(struct webview *w, webview_dispatch_fn fn,
-                                    void *arg)
+  WEBVIEW_API void webview_set_position(struct webview *w, int x, int y)
   {
-    PostMessageW(w->priv.hwnd, WM_WEBVIEW_DISPATCH, (WPARAM)fn, (LPARAM)arg);
+    // Flip from top left based coordinates relative to the primary screen
+    NSRect primary = [[[NSScreen screens] objectAtIndex:0] frame];
+    [w->priv.window
+        setFrameTopLeftPoint:NSMakePoint(x, primary.size.height - y)];
   }
 
-  WEBVIEW_API void webview_set_title(struct webview *w, const char *title)
+  WEBVIEW_API void webview_get_position(struct webview *w, int *x, int *y)
   {
-#ifdef UNICODE
-    wchar_t *u16title = webview_to_utf16(title);
-    if (u16title == NULL)
-    {
-      return;
-    }
-    SetWindowText(w->priv.hwnd, u16title);
-    GlobalFree(u16title);
-#else
-    SetWindowText(w->priv.hwnd, title);
-#endif
+    NSRect primary = [[[NSScreen screens] objectAtIndex:0] frame];
+    NSRect frame = [w->priv.window frame];
+    *x = frame.origin.x;
+    *y = primary.size.height - (frame.origin.y + frame.size.height);
   }
 
-  WEBVIEW_API void webview_focus(struct webview *w)
+  WEBVIEW_API void webview_get_size(struct webview *w, int *width, int *height)
   {
-    SetFocus(w->priv.hwnd);
+    NSRect content =
+        [w->priv.window contentRectForFrameRect:[w->priv.window frame]];
+    *width = content.size.width;
+    *height = content.size.height;
   }
 
-  WEBVIEW_API void webview_minsize(struct webview *w, int width, int height) {
-    w->priv.min_width = width;
-    w->priv.min_height = height;
+  WEBVIEW_API void webview_set_maximised(struct webview *w, int maximised)
+  {
+    if ([w->priv.window isZoomed] != (maximised != 0))
+    {
+      [w->priv.window zoom:nil];
+    }
   }
 
-  WEBVIEW_API void webview_maxsize(struct webview *w, int width, int height) {
-    w->priv.max_width = width;
-    w->priv.max_height = height;
+  WEBVIEW_API int webview_is_maximised(struct webview *w)
+  {
+    return [w->priv.window isZoomed];
   }
 
-  WEBVIEW_API void webview_set_fullscreen(struct webview *w, int fullscreen)
+  WEBVIEW_API void webview_set_minimised(struct webview *w, int minimised)
   {
-    if (w->priv.is_fullscreen == !!fullscreen)
+    if (minimised)
     {
-      return;
+      [w->priv.window miniaturize:nil];
     }
-    if (w->priv.is_fullscreen == 0)
+    else
     {
-      w->priv.saved_style = GetWindowLong(w->priv.hwnd, GWL_STYLE);
-      w->priv.saved_ex_style = GetWindowLong(w->priv.hwnd, GWL_EXSTYLE);
-      GetWindowRect(w->priv.hwnd, &w->priv.saved_rect);
+      [w->priv.window deminiaturize:nil];
     }
-    w->priv.is_fullscreen = !!fullscreen;
-    if (fullscreen)
+  }
+
+  WEBVIEW_API void webview_set_visible(struct webview *w, int visible)
+  {
+    if (visible)
     {
-      MONITORINFO monitor_info;
-      SetWindowLong(w->priv.hwnd, GWL_STYLE,
-                    w->priv.saved_style & ~(WS_CAPTION | WS_THICKFRAME));
-      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE,
-                    w->priv.saved_ex_style &
-                        ~(WS_EX_DLGMODALFRAME | WS_EX_WINDOWEDGE |
-                          WS_EX_CLIENTEDGE | WS_EX_STATICEDGE));
-      monitor_info.cbSize = sizeof(monitor_info);
-      GetMonitorInfo(MonitorFromWindow(w->priv.hwnd, MONITOR_DEFAULTTONEAREST),
-                     &monitor_info);
-      RECT r;
-      r.left = monitor_info.rcMonitor.left;
-      r.top = monitor_info.rcMonitor.top;
-      r.right = monitor_info.rcMonitor.right;
-      r.bottom = monitor_info.rcMonitor.bottom;
-      SetWindowPos(w->priv.hwnd, NULL, r.left, r.top, r.right - r.left,
-                   r.bottom - r.top,
-                   SWP_NOZORDER | SWP_NOACTIVATE | SWP_FRAMECHANGED);
+      [NSApp activateIgnoringOtherApps:YES];
+      [w->priv.window makeKeyAndOrderFront:nil];
     }
     else
     {
-      SetWindowLong(w->priv.hwnd, GWL_STYLE, w->priv.saved_style);
-      SetWindowLong(w->priv.hwnd, GWL_EXSTYLE, w->priv.saved_ex_style);
-      SetWindowPos(w->priv.hwnd, NULL, w->priv.saved_rect.left,
-                   w->priv.saved_rect.top,
-                   w->priv.saved_rect.right - w->priv.saved_rect.left,
-                   w->priv.saved_rect.bottom - w->priv.saved_rect.top,
-                   SWP_NOZORDER | SWP_NOACTIVATE | SWP_FRAMECHANGED);
+      [w->priv.window orderOut:nil];
     }
   }
 
-  WEBVIEW_API void webview_set_color(struct webview *w, uint8_t r, uint8_t g,
-                                     uint8_t b, uint8_t a)
+  WEBVIEW_API void webview_set_traffic_light_position(struct webview *w, int x, int y)
   {
-    HBRUSH brush = CreateSolidBrush(RGB(r, g, b));
-    SetClassLongPtr(w->priv.hwnd, GCLP_HBRBACKGROUND, (LONG_PTR)brush);
+    w->priv.traffic_light_set = 1;
+    w->priv.traffic_light_x = x;
+    w->priv.traffic_light_y = y;
+    webview_position_traffic_lights(w);
   }
 
-/* These are missing parts from MinGW */
-#ifndef __IFileDialog_INTERFACE_DEFINED__
-#define __IFileDialog_INTERFACE_DEFINED__
-  enum _FILEOPENDIALOGOPTIONS
-  {
-    FOS_OVERWRITEPROMPT = 0x2,
-    FOS_STRICTFILETYPES = 0x4,
-    FOS_NOCHANGEDIR = 0x8,
-    FOS_PICKFOLDERS = 0x20,
-    FOS_FORCEFILESYSTEM = 0x40,
-    FOS_ALLNONSTORAGEITEMS = 0x80,
-    FOS_NOVALIDATE = 0x100,
-    FOS_ALLOWMULTISELECT = 0x200,
-    FOS_PATHMUSTEXIST = 0x800,
-    FOS_FILEMUSTEXIST = 0x1000,
-    FOS_CREATEPROMPT = 0x2000,
-    FOS_SHAREAWARE = 0x4000,
-    FOS_NOREADONLYRETURN = 0x8000,
-    FOS_NOTESTFILECREATE = 0x10000,
-    FOS_HIDEMRUPLACES = 0x20000,
-    FOS_HIDEPINNEDPLACES = 0x40000,
-    FOS_NODEREFERENCELINKS = 0x100000,
-    FOS_DONTADDTORECENT = 0x2000000,
-    FOS_FORCESHOWHIDDEN = 0x10000000,
-    FOS_DEFAULTNOMINIMODE = 0x20000000,
-    FOS_FORCEPREVIEWPANEON = 0x40000000
-  };
-  typedef DWORD FILEOPENDIALOGOPTIONS;
-  typedef enum FDAP
-  {
-    FDAP_BOTTOM = 0,
-    FDAP_TOP = 1
-  } FDAP;
-  DEFINE_GUID(IID_IFileDialog, 0x42f85136, 0xdb7e, 0x439c, 0x85, 0xf1, 0xe4, 0x07,
-              0x5d, 0x13, 0x5f, 0xc8);
-  typedef struct IFileDialogVtbl
-  {
-    BEGIN_INTERFACE
-    HRESULT(STDMETHODCALLTYPE *QueryInterface)
-    (IFileDialog *This, REFIID riid, void **ppvObject);
-    ULONG(STDMETHODCALLTYPE *AddRef)
-    (IFileDialog *This);
-    ULONG(STDMETHODCALLTYPE *Release)
-    (IFileDialog *This);
-    HRESULT(STDMETHODCALLTYPE *Show)
-    (IFileDialog *This, HWND hwndOwner);
-    HRESULT(STDMETHODCALLTYPE *SetFileTypes)
-    (IFileDialog *This, UINT cFileTypes, const COMDLG_FILTERSPEC *rgFilterSpec);
-    HRESULT(STDMETHODCALLTYPE *SetFileTypeIndex)
-    (IFileDialog *This, UINT iFileType);
-    HRESULT(STDMETHODCALLTYPE *GetFileTypeIndex)
-    (IFileDialog *This, UINT *piFileType);
-    HRESULT(STDMETHODCALLTYPE *Advise)
-    (IFileDialog *This, IFileDialogEvents *pfde, DWORD *pdwCookie);
-    HRESULT(STDMETHODCALLTYPE *Unadvise)
-    (IFileDialog *This, DWORD dwCookie);
-    HRESULT(STDMETHODCALLTYPE *SetOptions)
-    (IFileDialog *This, FILEOPENDIALOGOPTIONS fos);
-    HRESULT(STDMETHODCALLTYPE *GetOptions)
-    (IFileDialog *This, FILEOPENDIALOGOPTIONS *pfos);
-    HRESULT(STDMETHODCALLTYPE *SetDefaultFolder)
-    (IFileDialog *This, IShellItem *psi);
-    HRESULT(STDMETHODCALLTYPE *SetFolder)
-    (IFileDialog *This, IShellItem *psi);
-    HRESULT(STDMETHODCALLTYPE *GetFolder)
-    (IFileDialog *This, IShellItem **ppsi);
-    HRESULT(STDMETHODCALLTYPE *GetCurrentSelection)
-    (IFileDialog *This, IShellItem **ppsi);
-    HRESULT(STDMETHODCALLTYPE *SetFileName)
-    (IFileDialog *This, LPCWSTR pszName);
-    HRESULT(STDMETHODCALLTYPE *GetFileName)
-    (IFileDialog *This, LPWSTR *pszName);
-    HRESULT(STDMETHODCALLTYPE *SetTitle)
-    (IFileDialog *This, LPCWSTR pszTitle);
-    HRESULT(STDMETHODCALLTYPE *SetOkButtonLabel)
-    (IFileDialog *This, LPCWSTR pszText);
-    HRESULT(STDMETHODCALLTYPE *SetFileNameLabel)
-    (IFileDialog *This, LPCWSTR pszLabel);
-    HRESULT(STDMETHODCALLTYPE *GetResult)
-    (IFileDialog *This, IShellItem **ppsi);
-    HRESULT(STDMETHODCALLTYPE *AddPlace)
-    (IFileDialog *This, IShellItem *psi, FDAP fdap);
-    HRESULT(STDMETHODCALLTYPE *SetDefaultExtension)
-    (IFileDialog *This, LPCWSTR pszDefaultExtension);
-    HRESULT(STDMETHODCALLTYPE *Close)
-    (IFileDialog *This, HRESULT hr);
-    HRESULT(STDMETHODCALLTYPE *SetClientGuid)
-    (IFileDialog *This, REFGUID guid);
-    HRESULT(STDMETHODCALLTYPE *ClearClientData)
-    (IFileDialog *This);
-    HRESULT(STDMETHODCALLTYPE *SetFilter)
-    (IFileDialog *This, IShellItemFilter *pFilter);
-    END_INTERFACE
-  } IFileDialogVtbl;
-  interface IFileDialog
+  WEBVIEW_API void webview_titlebar_button_area(struct webview *w, int *x, int *y,
+                                                int *width, int *height)
   {
-    CONST_VTBL IFileDialogVtbl *lpVtbl;
-  };
-  DEFINE_GUID(IID_IFileOpenDialog, 0xd57c7288, 0xd4ad, 0x4768, 0xbe, 0x02, 0x9d,
-              0x96, 0x95, 0x32, 0xd9, 0x60);
-  DEFINE_GUID(IID_IFileSaveDialog, 0x84bccd23, 0x5fde, 0x4cdb, 0xae, 0xa4, 0xaf,
-              0x64, 0xb8, 0x3d, 0x78, 0xab);
-#endif
+    *x = *y = *width = *height = 0;
+    if (!w->transparentTitlebar)
+    {
+      return;
+    }
+    NSButton *close = [w->priv.window standardWindowButton:NSWindowCloseButton];
+    NSButton *zoom = [w->priv.window standardWindowButton:NSWindowZoomButton];
+    if (close == nil || zoom == nil)
+    {
+      return;
+    }
+    // Convert to top left based content coordinates
+    NSRect first = [close convertRect:[close bounds] toView:nil];
+    NSRect last = [zoom convertRect:[zoom bounds] toView:nil];
+    NSRect area = NSUnionRect(first, last);
+    *x = area.origin.x;
+    *y = [[w->priv.window contentView] frame].size.height -
+         (area.origin.y + area.size.height);
+    *width = area.size.width;
+    *height = area.size.height;
+  }
 
-  WEBVIEW_API void webview_dialog(struct webview *w,
-                                  enum webview_dialog_type dlgtype, int flags,
-                                  const char *title, const char *arg,
-                                  char *result, size_t resultsz, char *filter)
+  // The traffic lights always use the system colours
+  WEBVIEW_API void webview_set_titlebar_color(struct webview *w, uint8_t r,
+                                              uint8_t g, uint8_t b, uint8_t sr,
+                                              uint8_t sg, uint8_t sb) {}
+
+  // Updates the allowed file types of the save panel
+  // when a format is chosen from the format picker
+  static void webview_format_changed(id self, SEL cmd, id sender)
   {
-    if (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN ||
-        dlgtype == WEBVIEW_DIALOG_TYPE_SAVE)
+    NSSavePanel *panel = objc_getAssociatedObject(self, "panel");
+    NSArray *types = objc_getAssociatedObject(self, "types");
+    NSInteger index = [sender indexOfSelectedItem];
+    if (index >= 0 && index < (NSInteger)[types count])
     {
-      IFileDialog *dlg = NULL;
-      IShellItem *res = NULL;
-      WCHAR *ws = NULL;
-      char *s = NULL;
-      FILEOPENDIALOGOPTIONS opts, add_opts;
-      if (dlgtype == WEBVIEW_DIALOG_TYPE_OPEN)
+      [panel setAllowedFileTypes:[types objectAtIndex:index]];
+    }
+  }
+
+  WEBVIEW_API int webview_save_dialog(struct webview *w, const char *title,
+                                      const char *directory,
+                                      const char *filename,
+                                      const char *formats, int flags,
+                                      char *result, size_t resultsz)
+  {
+    int format = -1;
+    NSSavePanel *panel = [NSSavePanel savePanel];
+    NSMutableArray *types = [NSMutableArray array];
+    NSPopUpButton *picker = nil;
+    id target = nil;
+    result[0] = '\0';
+
+    for (NSString *line in [[NSString stringWithUTF8String:formats]
+             componentsSeparatedByString:@"\n"])
+    {
+      NSArray *parts = [line componentsSeparatedByString:@"|"];
+      if ([parts count] != 2)
+      {
+        continue;
+      }
+      NSString *patterns = [[parts objectAtIndex:1]
+          stringByReplacingOccurrencesOfString:@"*."
+                                    withString:@""];
+      [types addObject:[patterns componentsSeparatedByString:@";"]];
+      if (picker == nil)
+      {
+        picker = [[[NSPopUpButton alloc] initWithFrame:NSMakeRect(0, 0, 240, 26)
+                                             pullsDown:NO] autorelease];
+      }
+      [picker addItemWithTitle:[parts objectAtIndex:0]];
+    }
+
+    if (picker != nil)
+    {
+      Class targetClass = objc_getClass("WebViewFormatPicker");
+      if (targetClass == nil)
       {
-        if (CoCreateInstance(
-                iid_unref(&CLSID_FileOpenDialog), NULL, CLSCTX_INPROC_SERVER,
-                iid_unref(&IID_IFileOpenDialog), (void **)&dlg) != S_OK)
-        {
-          goto error_dlg;
-        }
-        if (flags & WEBVIEW_DIALOG_FLAG_DIRECTORY)
-        {
-          add_opts |= FOS_PICKFOLDERS;
-        }
-        add_opts |= FOS_NOCHANGEDIR | FOS_ALLNONSTORAGEITEMS | FOS_NOVALIDATE |
-                    FOS_PATHMUSTEXIST | FOS_FILEMUSTEXIST | FOS_SHAREAWARE |
-                    FOS_NOTESTFILECREATE | FOS_NODEREFERENCELINKS |
-                    FOS_FORCESHOWHIDDEN | FOS_DEFAULTNOMINIMODE;
+        targetClass = objc_allocateClassPair([NSObject class], "WebViewFormatPicker", 0);
+        class_addMethod(targetClass, sel_registerName("formatChanged:"),
+                        (IMP)webview_format_changed, "v@:@");
+        objc_registerClassPair(targetClass);
       }
-      else
+      target = [[targetClass alloc] init];
+      objc_setAssociatedObject(target, "panel", panel, OBJC_ASSOCIATION_ASSIGN);
+      objc_setAssociatedObject(target, "types", types, OBJC_ASSOCIATION_RETAIN);
+      [picker setTarget:target];
+      [picker setAction:sel_registerName("formatChanged:")];
+      [panel setAccessoryView:picker];
+      [panel setAllowedFileTypes:[types objectAtIndex:0]];
+    }
+
+    // NSSavePanel always confirms overwriting existing files
+    [panel setTitle:[NSString stringWithUTF8String:title]];
+    [panel setCanCreateDirectories:!!(flags & WEBVIEW_SAVE_FLAG_CREATE_DIRECTORIES)];
+    [panel setShowsHiddenFiles:YES];
+    [panel setExtensionHidden:NO];
+    [panel setCanSelectHiddenExtension:NO];
+    [panel setTreatsFilePackagesAsDirectories:YES];
+    [panel setNameFieldStringValue:@"Temp"]; // Necessary to prevent crash when replacing files
+    [panel setNameFieldStringValue:filename[0] != '\0'
+                                       ? [NSString stringWithUTF8String:filename]
+                                       : @"Untitled"];
+    if (directory[0] != '\0')
+    {
+      [panel setDirectoryURL:[NSURL fileURLWithPath:[NSString stringWithUTF8String:directory]
+                                         isDirectory:YES]];
+    }
+    [panel beginSheetModalForWindow:w->priv.window
+                  completionHandler:^(NSInteger response) {
+                    [NSApp stopModalWithCode:response];
+                  }];
+    if ([NSApp runModalForWindow:panel] == NSModalResponseOK)
+    {
+      strlcpy(result, [[[panel URL] path] UTF8String], resultsz);
+      format = picker != nil ? (int)[picker indexOfSelectedItem] : -1;
+    }
+    [target release];
+    return format;
+  }
+
+  // Returns the selected files or directories separated by newlines.
+  // NSOpenPanel has no format picker, so the files of every format may be
+  // chosen.
+  WEBVIEW_API void webview_open_dialog(struct webview *w, const char *title,
+                                       const char *directory, const char *formats,
+                                       int flags, char *result, size_t resultsz)
+  {
+    NSOpenPanel *panel = [NSOpenPanel openPanel];
+    NSMutableArray *types = [NSMutableArray array];
+    BOOL any = NO;
+    result[0] = '\0';
+
+    for (NSString *line in [[NSString stringWithUTF8String:formats]
+             componentsSeparatedByString:@"\n"])
+    {
+      NSArray *parts = [line componentsSeparatedByString:@"|"];
+      if ([parts count] != 2)
       {
-        if (CoCreateInstance(
-                iid_unref(&CLSID_FileSaveDialog), NULL, CLSCTX_INPROC_SERVER,
-                iid_unref(&IID_IFileSaveDialog), (void **)&dlg) != S_OK)
-        {
-          goto error_dlg;
-        }
-        add_opts |= FOS_OVERWRITEPROMPT | FOS_NOCHANGEDIR |
-                    FOS_ALLNONSTORAGEITEMS | FOS_NOVALIDATE | FOS_SHAREAWARE |
-                    FOS_NOTESTFILECREATE | FOS_NODEREFERENCELINKS |
-                    FOS_FORCESHOWHIDDEN | FOS_DEFAULTNOMINIMODE;
+        continue;
       }
-      if (filter[0] != '\0')
+      for (NSString *pattern in [[parts objectAtIndex:1]
+               componentsSeparatedByString:@";"])
       {
-        int count;
-        int i=0;
-        char* token;
-        char* filter_dup = strdup(filter);
-        for (count=1; filter[count]; filter[count]==',' ? count++ : *filter++);
-        COMDLG_FILTERSPEC rgSpec[count];
-        char* filters[count];
-        token = strtok(filter_dup, ",");
-        while(token != NULL)
+        NSString *type = [pattern stringByReplacingOccurrencesOfString:@"*."
+                                                            withString:@""];
+        if ([type isEqualToString:@"*"])
         {
-          filters[i] = token;
-          token = strtok(NULL, ",");
-          i++;
-        }
-        for (int i=0; i < count; i++) {
-          wchar_t *wFilter = (wchar_t *)malloc(4096);
-          MultiByteToWideChar(CP_ACP, 0, filters[i], -1, wFilter, 4096);
-          rgSpec[i].pszName = wFilter;
-          rgSpec[i].pszSpec = wFilter;
-        }
-        if (dlg->lpVtbl->SetFileTypes(dlg, count, rgSpec) != S_OK) {
-          goto error_dlg;
+          any = YES;
         }
+        [types addObject:type];
       }
-      if (dlg->lpVtbl->GetOptions(dlg, &opts) != S_OK)
-      {
-        goto error_dlg;
-      }
-      opts &= ~FOS_NOREADONLYRETURN;
-      opts |= add_opts;
-      if (dlg->lpVtbl->SetOptions(dlg, opts) != S_OK)
+    }
+    if ([types count] > 0 && !any)
+    {
+      [panel setAllowedFileTypes:types];
+    }
+    if (directory[0] != '\0')
+    {
+      [panel setDirectoryURL:[NSURL fileURLWithPath:[NSString stringWithUTF8String:directory]
+                                         isDirectory:YES]];
+    }
+    [panel setTitle:[NSString stringWithUTF8String:title]];
+    [panel setCanChooseFiles:!(flags & WEBVIEW_OPEN_FLAG_DIRECTORY)];
+    [panel setCanChooseDirectories:!!(flags & WEBVIEW_OPEN_FLAG_DIRECTORY)];
+    [panel setCanCreateDirectories:!!(flags & WEBVIEW_OPEN_FLAG_DIRECTORY)];
+    [panel setAllowsMultipleSelection:!!(flags & WEBVIEW_OPEN_FLAG_MULTIPLE)];
+    [panel setResolvesAliases:YES];
+    [panel setShowsHiddenFiles:YES];
+    [panel beginSheetModalForWindow:w->priv.window
+                  completionHandler:^(NSInteger response) {
+                    [NSApp stopModalWithCode:response];
+                  }];
+    if ([NSApp runModalForWindow:panel] == NSModalResponseOK)
+    {
+      NSMutableArray *paths = [NSMutableArray array];
+      for (NSURL *url in [panel URLs])
       {
-        goto error_dlg;
+        [paths addObject:[url path]];
       }
-      if (dlg->lpVtbl->Show(dlg, w->priv.hwnd) != S_OK)
+      strlcpy(result, [[paths componentsJoinedByString:@"\n"] UTF8String], resultsz);
+    }
+  }
+
+  WEBVIEW_API int webview_message_dialog(struct webview *w, int type,
+                                         const char *title, const char *message,
+                                         const char *buttons)
+  {
+    NSAlert *a = [NSAlert new];
+    NSInteger response;
+    int count = 0;
+    switch (type)
+    {
+    case WEBVIEW_MESSAGE_WARNING:
+      [a setAlertStyle:NSAlertStyleWarning];
+      break;
+    case WEBVIEW_MESSAGE_ERROR:
+      [a setAlertStyle:NSAlertStyleCritical];
+      break;
+    default:
+      [a setAlertStyle:NSAlertStyleInformational];
+      break;
+    }
+    [a setMessageText:[NSString stringWithUTF8String:title]];
+    [a setInformativeText:[NSString stringWithUTF8String:message]];
+    for (NSString *label in [[NSString stringWithUTF8String:buttons]
+             componentsSeparatedByString:@"\n"])
+    {
+      if ([label length] > 0)
       {
-        goto error_dlg;
+        [a addButtonWithTitle:label];
+        count++;
       }
-      if (dlg->lpVtbl->GetResult(dlg, &res) != S_OK)
+    }
+    if (count == 0)
+    {
+      [a addButtonWithTitle:@"OK"];
+    }
+    [a beginSheetModalForWindow:w->priv.window
+              completionHandler:^(NSModalResponse code) {
+                [NSApp stopModalWithCode:code];
+              }];
+    response = [NSApp runModalForWindow:[a window]];
+    [a release];
+    return response >= NSAlertFirstButtonReturn
+               ? (int)(response - NSAlertFirstButtonReturn)
+               : -1;
+  }
+
+  // Returns the selected directories separated by newlines
+  WEBVIEW_API void webview_select_directories(struct webview *w, const char *title,
+                                              char *result, size_t resultsz)
+  {
+    NSOpenPanel *panel = [NSOpenPanel openPanel];
+    result[0] = '\0';
+    [panel setTitle:[NSString stringWithUTF8String:title]];
+    [panel setCanChooseFiles:NO];
+    [panel setCanChooseDirectories:YES];
+    [panel setAllowsMultipleSelection:YES];
+    [panel setCanCreateDirectories:YES];
+    [panel setResolvesAliases:YES];
+    [panel beginSheetModalForWindow:w->priv.window
+                  completionHandler:^(NSInteger response) {
+                    [NSApp stopModalWithCode:response];
+                  }];
+    if ([NSApp runModalForWindow:panel] == NSModalResponseOK)
+    {
+      NSMutableArray *paths = [NSMutableArray array];
+      for (NSURL *url in [panel URLs])
       {
-        goto error_dlg;
+        [paths addObject:[url path]];
       }
-      if (res->lpVtbl->GetDisplayName(res, SIGDN_FILESYSPATH, &ws) != S_OK)
+      strlcpy(result, [[paths componentsJoinedByString:@"\n"] UTF8String], resultsz);
+    }
+  }
+
+  // Stops the modal loop of a colour or font panel when it is closed
+  static void webview_picker_will_close(id self, SEL cmd, id notification)
+  {
+    [NSApp stopModal];
+  }
+
+  static id webview_picker_delegate()
+  {
+    Class delegateClass = objc_getClass("WebViewPickerDelegate");
+    if (delegateClass == nil)
+    {
+      delegateClass =
+          objc_allocateClassPair([NSObject class], "WebViewPickerDelegate", 0);
+      class_addMethod(delegateClass, sel_registerName("windowWillClose:"),
+                      (IMP)webview_picker_will_close, "v@:@");
+      objc_registerClassPair(delegateClass);
+    }
+    return [[delegateClass alloc] init];
+  }
+
+  // The pickers are run until they are closed. As the panels have no cancel
+  // button, they always return 1 to indicate the user made a choice.
+  WEBVIEW_API int webview_color_picker(struct webview *w, const char *title,
+                                       uint8_t *r, uint8_t *g, uint8_t *b,
+                                       uint8_t *a)
+  {
+    NSColorPanel *panel = [NSColorPanel sharedColorPanel];
+    id delegate = webview_picker_delegate();
+    [panel setTitle:[NSString stringWithUTF8String:title]];
+    [panel setShowsAlpha:YES];
+    [panel setColor:[NSColor colorWithSRGBRed:*r / 255.0
+                                        green:*g / 255.0
+                                         blue:*b / 255.0
+                                        alpha:*a / 255.0]];
+    [panel setDelegate:delegate];
+    [NSApp runModalForWindow:panel];
+    [panel setDelegate:nil];
+    [delegate release];
+    NSColor *color = [[panel color] colorUsingColorSpace:[NSColorSpace sRGBColorSpace]];
+    if (color == nil)
+    {
+      return 0;
+    }
+    *r = [color redComponent] * 255;
+    *g = [color greenComponent] * 255;
+    *b = [color blueComponent] * 255;
+    *a = [color alphaComponent] * 255;
+    return 1;
+  }
+
+  WEBVIEW_API int webview_font_picker(struct webview *w, const char *title,
+                                      char *family, size_t familysz,
+                                      char *face, size_t facesz, int *size,
+                                      int *weight, int *italic)
+  {
+    // The AppKit weights, from 0 to 15, of the CSS weights 100 to 900
+    static const int weights[] = {2, 3, 4, 5, 6, 8, 9, 10, 12};
+    NSFontManager *manager = [NSFontManager sharedFontManager];
+    NSFontPanel *panel = [manager fontPanel:YES];
+    NSFont *initial = nil;
+    NSInteger appkitWeight;
+    NSString *faceName;
+    int i;
+    if (family[0] != '\0')
+    {
+      i = *weight / 100 - 1;
+      initial = [manager fontWithFamily:[NSString stringWithUTF8String:family]
+                                 traits:(*italic ? NSItalicFontMask : 0)
+                                 weight:weights[i < 0 ? 0 : i > 8 ? 8 : i]
+                                   size:(*size > 0 ? *size : [NSFont systemFontSize])];
+    }
+    if (initial == nil)
+    {
+      initial = [NSFont systemFontOfSize:[NSFont systemFontSize]];
+    }
+    id delegate = webview_picker_delegate();
+    [manager setSelectedFont:initial isMultiple:NO];
+    [panel setTitle:[NSString stringWithUTF8String:title]];
+    [panel setDelegate:delegate];
+    [NSApp runModalForWindow:panel];
+    [panel setDelegate:nil];
+    [delegate release];
+    NSFont *font = [panel panelConvertFont:initial];
+    NSFontTraitMask traits = [manager traitsOfFont:font];
+    strlcpy(family, [[font familyName] UTF8String], familysz);
+    faceName = [[font fontDescriptor] objectForKey:NSFontFaceAttribute];
+    strlcpy(face, faceName != nil ? [faceName UTF8String] : "", facesz);
+    *size = [font pointSize];
+    appkitWeight = [manager weightOfFont:font];
+    *weight = 900;
+    for (i = 0; i < 9; i++)
+    {
+      if (appkitWeight <= weights[i])
       {
-        goto error_result;
+        *weight = (i + 1) * 100;
+        break;
       }
-      s = webview_from_utf16(ws);
-      strncpy(result, s, resultsz);
-      result[resultsz - 1] = '\0';
-      CoTaskMemFree(ws);
-    error_result:
-      res->lpVtbl->Release(res);
-    error_dlg:
-      dlg->lpVtbl->Release(dlg);
+    }
+    *italic = (traits & NSItalicFontMask) != 0;
+    return 1;
+  }
+
+  WEBVIEW_API void webview_show_emoji_picker(struct webview *w)
+  {
+    [NSApp orderFrontCharacterPalette:nil];
+  }
+
+  // Critical requests bounce the dock icon until the app is activated,
+  // others bounce it once
+  WEBVIEW_API void webview_request_attention(struct webview *w, int critical)
+  {
+    (void)w;
+    [NSApp requestUserAttention:critical ? NSCriticalRequest
+                                         : NSInformationalRequest];
+  }
+
+  WEBVIEW_API void webview_set_opacity(struct webview *w, double opacity)
+  {
+    [w->priv.window setAlphaValue:(CGFloat)opacity];
+  }
+
+  // The dock tile belongs to the app. While there's progress, it shows the
+  // app's icon with a progress bar along its bottom.
+  WEBVIEW_API void webview_set_progress(struct webview *w, double progress)
+  {
+    (void)w;
+    NSDockTile *tile = [NSApp dockTile];
+    if (progress < 0)
+    {
+      [tile setContentView:nil];
+      [tile display];
       return;
     }
-    else if (dlgtype == WEBVIEW_DIALOG_TYPE_ALERT)
+    NSView *view = [tile contentView];
+    if (view == nil)
     {
-#if 0
-    /* MinGW often doesn't contain TaskDialog, we'll use MessageBox for now */
-    WCHAR *wtitle = webview_to_utf16(title);
-    WCHAR *warg = webview_to_utf16(arg);
-    TaskDialog(w->priv.hwnd, NULL, NULL, wtitle, warg, 0, NULL, NULL);
-    GlobalFree(warg);
-    GlobalFree(wtitle);
-#else
-      UINT type = MB_OK;
-      switch (flags & WEBVIEW_DIALOG_FLAG_ALERT_MASK)
+      NSSize size = [tile size];
+      NSImageView *icon = [[[NSImageView alloc]
+          initWithFrame:NSMakeRect(0, 0, size.width, size.height)] autorelease];
+      [icon setImage:[NSApp applicationIconImage]];
+      NSProgressIndicator *bar = [[[NSProgressIndicator alloc]
+          initWithFrame:NSMakeRect(size.width * 0.1, size.height * 0.05,
+                                   size.width * 0.8, size.height * 0.15)]
+          autorelease];
+      [bar setStyle:NSProgressIndicatorBarStyle];
+      [bar setIndeterminate:NO];
+      [bar setMinValue:0];
+      [bar setMaxValue:1];
+      [icon addSubview:bar];
+      [tile setContentView:icon];
+      view = icon;
+    }
+    NSProgressIndicator *bar = [[view subviews] objectAtIndex:0];
+    [bar setDoubleValue:progress > 1 ? 1 : progress];
+    [tile display];
+  }
+
+  WEBVIEW_API void webview_set_badge(struct webview *w, const char *badge)
+  {
+    (void)w;
+    [[NSApp dockTile]
+        setBadgeLabel:*badge != '\0' ? [NSString stringWithUTF8String:badge]
+                                      : nil];
+  }
+
+  // The values of the NSVisualEffectMaterial constants and the versions of
+  // MacOS they were added in, as the SDK may be older
+  static const struct
+  {
+    const char *name;
+    NSInteger value;
+    NSInteger minor;
+  } webview_materials[] = {
+      {"titlebar", 3, 10},          {"selection", 4, 10},
+      {"menu", 5, 11},              {"popover", 6, 11},
+      {"sidebar", 7, 11},           {"headerView", 10, 14},
+      {"sheet", 11, 14},            {"windowBackground", 12, 14},
+      {"hudWindow", 13, 14},        {"fullScreenUI", 15, 14},
+      {"toolTip", 17, 14},          {"contentBackground", 18, 14},
+      {"underWindowBackground", 21, 14}, {"underPageBackground", 22, 14}};
+
+  // Returns the index of the material in webview_materials, or -1 if it
+  // isn't supported by this version of MacOS
+  static int webview_material_index(const char *material)
+  {
+    for (size_t i = 0; i < sizeof(webview_materials) / sizeof(webview_materials[0]); i++)
+    {
+      NSOperatingSystemVersion version = {10, webview_materials[i].minor, 0};
+      if (strcmp(material, webview_materials[i].name) == 0 &&
+          [[NSProcessInfo processInfo] isOperatingSystemAtLeastVersion:version])
       {
-      case WEBVIEW_DIALOG_FLAG_INFO:
-        type |= MB_ICONINFORMATION;
-        break;
-      case WEBVIEW_DIALOG_FLAG_WARNING:
-        type |= MB_ICONWARNING;
-        break;
-      case WEBVIEW_DIALOG_FLAG_ERROR:
-        type |= MB_ICONERROR;
-        break;
+        return (int)i;
       }
-      MessageBox(w->priv.hwnd, arg, title, type);
-#endif
     }
+    return -1;
   }
 
-  WEBVIEW_API void webview_terminate(struct webview *w) { PostQuitMessage(0); }
-  WEBVIEW_API void webview_exit(struct webview *w) { OleUninitialize(); }
-  WEBVIEW_API void webview_print_log(const char *s) { OutputDebugString(s); }
+  WEBVIEW_API int webview_material_supported(struct webview *w,
+                                             const char *material)
+  {
+    (void)w;
+    return strcmp(material, "none") == 0 || webview_material_index(material) >= 0;
+  }
 
-#endif /* WEBVIEW_WINAPI */
+  // The material is shown by a visual effect view behind the webview, which
+  // stops drawing its background
+  WEBVIEW_API int webview_set_material(struct webview *w, const char *material)
+  {
+    if (strcmp(material, "none") == 0)
+    {
+      [w->priv.effect removeFromSuperview];
+      w->priv.effect = nil;
+      [w->priv.webview setDrawsBackground:!w->transparent];
+      return 1;
+    }
+    int index = webview_material_index(material);
+    if (index < 0)
+    {
+      return 0;
+    }
+    if (w->priv.effect == nil)
+    {
+      NSView *content = [w->priv.window contentView];
+      w->priv.effect = [[[NSVisualEffectView alloc]
+          initWithFrame:[content bounds]] autorelease];
+      [w->priv.effect setBlendingMode:NSVisualEffectBlendingModeBehindWindow];
+      [w->priv.effect setState:NSVisualEffectStateFollowsWindowActiveState];
+      [w->priv.effect
+          setAutoresizingMask:NSViewWidthSizable | NSViewHeightSizable];
+      [content addSubview:w->priv.effect positioned:NSWindowBelow relativeTo:nil];
+    }
+    [w->priv.effect
+        setMaterial:(NSVisualEffectMaterial)webview_materials[index].value];
+    [w->priv.webview setDrawsBackground:NO];
+    return 1;
+  }
 
-#if defined(WEBVIEW_COCOA)
-#if (!defined MAC_OS_X_VERSION_10_12) || \
-    MAC_OS_X_VERSION_MAX_ALLOWED < MAC_OS_X_VERSION_10_12
-#define NSAlertStyleWarning NSWarningAlertStyle
-#define NSAlertStyleCritical NSCriticalAlertStyle
-#define NSWindowStyleMaskResizable NSResizableWindowMask
-#define NSWindowStyleMaskMiniaturizable NSMiniaturizableWindowMask
-#define NSWindowStyleMaskTitled NSTitledWindowMask
-#define NSWindowStyleMaskClosable NSClosableWindowMask
-#define NSWindowStyleMaskFullScreen NSFullScreenWindowMask
-#define NSEventMaskAny NSAnyEventMask
-#define NSEventModifierFlagCommand NSCommandKeyMask
-#define NSEventModifierFlagOption NSAlternateKeyMask
-#define NSAlertStyleInformational NSInformationalAlertStyle
-#endif /* MAC_OS_X_VERSION_10_12 */
-#if (!defined MAC_OS_X_VERSION_10_13) || \
-    MAC_OS_X_VERSION_MAX_ALLOWED < MAC_OS_X_VERSION_10_13
-#define NSModalResponseOK NSFileHandlingPanelOKButton
-#endif /* MAC_OS_X_VERSION_10_12, MAC_OS_X_VERSION_10_13 */
-  static void webview_window_will_close(id self, SEL cmd, id notification)
+  // The icon is shown in the menu bar and scaled to fit it
+  WEBVIEW_API int webview_tray_set_icon(struct webview *w, const uint8_t *data,
+                                        int size)
   {
-    struct webview *w =
-        (struct webview *)objc_getAssociatedObject(self, "webview");
-    webview_terminate(w);
+    NSData *iconData = [NSData dataWithBytes:data length:size];
+    NSImage *icon = [[[NSImage alloc] initWithData:iconData] autorelease];
+    if (icon == nil || [icon size].height == 0)
+    {
+      return -1;
+    }
+    CGFloat height = [[NSStatusBar systemStatusBar] thickness] - 4;
+    [icon setSize:NSMakeSize(height * [icon size].width / [icon size].height,
+                             height)];
+    if (w->priv.tray == nil)
+    {
+      w->priv.tray = [[[NSStatusBar systemStatusBar]
+          statusItemWithLength:NSVariableStatusItemLength] retain];
+      NSStatusBarButton *button = [w->priv.tray button];
+      [button setTarget:w->priv.delegate];
+      [button setAction:@selector(trayClicked:)];
+      [button setTag:-1];
+    }
+    [[w->priv.tray button] setImage:icon];
+    return 0;
   }
 
-  static BOOL webview_is_selector_excluded_from_web_script(id self, SEL cmd,
-                                                           SEL selector)
+  WEBVIEW_API void webview_tray_set_tooltip(struct webview *w,
+                                            const char *tooltip)
   {
-    return selector != @selector(invoke:);
+    [[w->priv.tray button]
+        setToolTip:[NSString stringWithUTF8String:tooltip]];
   }
 
-  static NSString *webview_webscript_name_for_selector(id self, SEL cmd,
-                                                       SEL selector)
+  // Clicking the icon shows its menu, if it has one, instead of being
+  // reported to the tray callback
+  WEBVIEW_API void webview_tray_set_menu(struct webview *w, const char *menu)
   {
-    return selector == @selector(invoke:) ? @"invoke" : nil;
+    char label[256];
+    int separator, enabled, checked;
+    int index = 0;
+    if (w->priv.tray == nil)
+    {
+      return;
+    }
+    if (menu == NULL || *menu == '\0')
+    {
+      [w->priv.tray setMenu:nil];
+      return;
+    }
+    NSMenu *trayMenu = [[[NSMenu alloc] initWithTitle:@""] autorelease];
+    [trayMenu setAutoenablesItems:NO];
+    while ((menu = webview_tray_next_item(menu, label, sizeof(label),
+                                          &separator, &enabled, &checked)) != NULL)
+    {
+      if (separator)
+      {
+        [trayMenu addItem:[NSMenuItem separatorItem]];
+        index++;
+        continue;
+      }
+      NSMenuItem *item = [[[NSMenuItem alloc]
+          initWithTitle:[NSString stringWithUTF8String:label]
+                 action:@selector(trayClicked:)
+          keyEquivalent:@""] autorelease];
+      [item setTarget:w->priv.delegate];
+      [item setTag:index++];
+      [item setEnabled:enabled];
+      [item setState:checked ? NSOnState : NSOffState];
+      [trayMenu addItem:item];
+    }
+    [w->priv.tray setMenu:trayMenu];
+  }
+
+  WEBVIEW_API void webview_tray_remove(struct webview *w)
+  {
+    if (w->priv.tray != nil)
+    {
+      [[NSStatusBar systemStatusBar] removeStatusItem:w->priv.tray];
+      [w->priv.tray release];
+      w->priv.tray = nil;
+    }
+  }
+
+  // The notification center has one delegate, so clicks are reported to the
+  // window that showed the last notification
+  WEBVIEW_API int webview_notify(struct webview *w, const char *id,
+                                 const char *title, const char *body,
+                                 int silent)
+  {
+    // There is no notification center for apps that aren't bundled
+    NSUserNotificationCenter *center =
+        [NSUserNotificationCenter defaultUserNotificationCenter];
+    if (center == nil)
+    {
+      return 0;
+    }
+    NSUserNotification *notification =
+        [[[NSUserNotification alloc] init] autorelease];
+    [notification setTitle:[NSString stringWithUTF8String:title]];
+    [notification setInformativeText:[NSString stringWithUTF8String:body]];
+    [notification
+        setUserInfo:[NSDictionary
+                        dictionaryWithObject:[NSString stringWithUTF8String:id]
+                                      forKey:@"webview-notification"]];
+    if (!silent)
+    {
+      [notification setSoundName:NSUserNotificationDefaultSoundName];
+    }
+    [center setDelegate:w->priv.delegate];
+    [center deliverNotification:notification];
+    return 1;
+  }
+
+  // Appends the items at the given depth to the menu, with their submenus,
+  // returning the rest of the menu. Items indented too far are appended as
+  // if they weren't. Choosing an item sends the action to the delegate.
+  // The characters of the keys that don't type one, for key equivalents
+  static const struct webview_key_name webview_key_equivalents[] = {
+      {'\r', "Enter"},
+      {0x1b, "Escape"},
+      {'\t', "Tab"},
+      {NSBackspaceCharacter, "Backspace"},
+      {NSDeleteFunctionKey, "Delete"},
+      {NSInsertFunctionKey, "Insert"},
+      {NSHomeFunctionKey, "Home"},
+      {NSEndFunctionKey, "End"},
+      {NSPageUpFunctionKey, "PageUp"},
+      {NSPageDownFunctionKey, "PageDown"},
+      {NSLeftArrowFunctionKey, "ArrowLeft"},
+      {NSRightArrowFunctionKey, "ArrowRight"},
+      {NSUpArrowFunctionKey, "ArrowUp"},
+      {NSDownArrowFunctionKey, "ArrowDown"},
+      {' ', "Space"}};
+
+  // Returns the key equivalent of a key named by webview_menu_next_item, or
+  // an empty string if it's unknown
+  static NSString *webview_key_equivalent(const char *key)
+  {
+    int number;
+    unichar c = 0;
+    for (size_t i = 0; i < sizeof(webview_key_equivalents) /
+                               sizeof(webview_key_equivalents[0]);
+         i++)
+    {
+      if (strcmp(key, webview_key_equivalents[i].name) == 0)
+      {
+        c = (unichar)webview_key_equivalents[i].code;
+      }
+    }
+    if (c == 0 && key[0] == 'F' && sscanf(key + 1, "%d", &number) == 1 &&
+        number >= 1 && number <= 24)
+    {
+      c = (unichar)(NSF1FunctionKey + number - 1);
+    }
+    if (c == 0 && key[0] != '\0')
+    {
+      return [NSString stringWithUTF8String:key];
+    }
+    return c == 0 ? @"" : [NSString stringWithCharacters:&c length:1];
   }
 
-  static void webview_did_clear_window_object(id self, SEL cmd, id webview,
-                                              id script, id frame)
+  static const char *webview_menu_append(struct webview *w, NSMenu *parent,
+                                         const char *menu, int depth,
+                                         int *index, SEL action)
   {
-    [script setValue:self forKey:@"external"];
+    char label[256];
+    char key[16];
+    int modifiers, separator, enabled, checked, radio, role;
+    while (webview_menu_depth(menu) >= depth)
+    {
+      menu = webview_menu_next_item(menu, label, sizeof(label), key,
+                                    sizeof(key), &modifiers, &separator,
+                                    &enabled, &checked, &radio, &role);
+      int tag = (*index)++;
+      if (separator)
+      {
+        [parent addItem:[NSMenuItem separatorItem]];
+        continue;
+      }
+      NSString *title = [NSString stringWithUTF8String:label];
+      NSMenuItem *item = [[[NSMenuItem alloc] initWithTitle:title
+                                                     action:action
+                                              keyEquivalent:@""] autorelease];
+      [item setTarget:w->priv.delegate];
+      [item setTag:tag];
+      [item setEnabled:enabled];
+      [item setState:checked ? NSOnState : NSOffState];
+      // Items with a role send the standard item's action instead, editing
+      // actions going to the focused view
+      switch (role)
+      {
+      case WEBVIEW_MENU_ROLE_CUT:
+        [item setAction:@selector(cut:)];
+        [item setTarget:nil];
+        break;
+      case WEBVIEW_MENU_ROLE_COPY:
+        [item setAction:@selector(copy:)];
+        [item setTarget:nil];
+        break;
+      case WEBVIEW_MENU_ROLE_PASTE:
+        [item setAction:@selector(paste:)];
+        [item setTarget:nil];
+        break;
+      case WEBVIEW_MENU_ROLE_SELECT_ALL:
+        [item setAction:@selector(selectAll:)];
+        [item setTarget:nil];
+        break;
+      case WEBVIEW_MENU_ROLE_QUIT:
+        [item setAction:@selector(quit:)];
+        break;
+      case WEBVIEW_MENU_ROLE_MINIMIZE:
+        [item setAction:@selector(performMiniaturize:)];
+        [item setTarget:nil];
+        break;
+      case WEBVIEW_MENU_ROLE_ABOUT:
+        [item setAction:@selector(about:)];
+        [item setTarget:w->priv.delegate];
+        break;
+      }
+      if (key[0] != '\0')
+      {
+        NSUInteger mask = 0;
+        mask |= (modifiers & WEBVIEW_KEY_SHIFT) ? NSEventModifierFlagShift : 0;
+        mask |=
+            (modifiers & WEBVIEW_KEY_CONTROL) ? NSEventModifierFlagControl : 0;
+        mask |= (modifiers & WEBVIEW_KEY_ALT) ? NSEventModifierFlagOption : 0;
+        mask |= (modifiers & WEBVIEW_KEY_SUPER) ? NSEventModifierFlagCommand : 0;
+        [item setKeyEquivalent:webview_key_equivalent(key)];
+        [item setKeyEquivalentModifierMask:mask];
+      }
+      if (webview_menu_depth(menu) > depth)
+      {
+        NSMenu *submenu = [[[NSMenu alloc] initWithTitle:title] autorelease];
+        [submenu setAutoenablesItems:NO];
+        menu = webview_menu_append(w, submenu, menu, depth + 1, index, action);
+        [item setAction:NULL];
+        [item setSubmenu:submenu];
+      }
+      [parent addItem:item];
+    }
+    return menu;
   }
 
-  static void webview_run_input_open_panel(id self, SEL cmd, id webview,
-                                           id listener, BOOL allowMultiple)
+  // The app menu and the Edit menu stay first, keeping the standard
+  // shortcuts. The host app of an embedded window owns the menu bar.
+  WEBVIEW_API void webview_set_menu(struct webview *w, const char *menu)
   {
-    char filename[256] = "";
-    struct webview *w =
-        (struct webview *)objc_getAssociatedObject(self, "webview");
-
-    webview_dialog(w, WEBVIEW_DIALOG_TYPE_OPEN, WEBVIEW_DIALOG_FLAG_FILE, "", "",
-                   filename, 255, "");
-    filename[255] = '\0';
-    if (strlen(filename) > 0)
+    int index = 0;
+    NSMenu *menubar = [NSApp mainMenu];
+    if (w->embedded || menubar == nil)
     {
-      [listener chooseFilename:[NSString stringWithUTF8String:filename]];
+      return;
     }
-    else
+    for (NSMenuItem *item in w->priv.menus)
     {
-      [listener cancel];
+      [menubar removeItem:item];
+    }
+    [w->priv.menus release];
+    w->priv.menus = nil;
+    if (menu == NULL || *menu == '\0')
+    {
+      return;
+    }
+    NSMenu *menus = [[[NSMenu alloc] initWithTitle:@""] autorelease];
+    webview_menu_append(w, menus, menu, 0, &index, @selector(menuClicked:));
+    w->priv.menus = [[menus itemArray] copy];
+    [menus removeAllItems];
+    NSInteger at = MIN(2, [menubar numberOfItems]);
+    for (NSMenuItem *item in w->priv.menus)
+    {
+      [menubar insertItem:item atIndex:at++];
     }
   }
 
-  static void webview_external_invoke(id self, SEL cmd, id arg)
+  // The menu is shown until an item is chosen or it's dismissed
+  WEBVIEW_API void webview_show_context_menu(struct webview *w,
+                                             const char *menu, int x, int y)
   {
-    struct webview *w =
-        (struct webview *)objc_getAssociatedObject(self, "webview");
-    if (w == NULL || w->external_invoke_cb == NULL)
+    int index = 0;
+    if (menu == NULL || *menu == '\0')
     {
       return;
     }
-    if ([arg isKindOfClass:[NSString class]] == NO)
+    NSMenu *popup = [[[NSMenu alloc] initWithTitle:@""] autorelease];
+    [popup setAutoenablesItems:NO];
+    webview_menu_append(w, popup, menu, 0, &index,
+                        @selector(contextMenuClicked:));
+    // The page's origin is at the top left
+    CGFloat top = y;
+    if (![w->priv.webview isFlipped])
     {
-      return;
+      top = [w->priv.webview bounds].size.height - y;
     }
-    w->external_invoke_cb(w, [(NSString *)(arg) UTF8String]);
+    [popup popUpMenuPositioningItem:nil
+                         atLocation:NSMakePoint(x, top)
+                             inView:w->priv.webview];
   }
 
-  WEBVIEW_API int webview_init(struct webview *w)
+  // The view is added to the webview, so it moves with it
+  WEBVIEW_API void webview_place_overlay(struct webview *w, void *view, int x,
+                                         int y, int width, int height,
+                                         int visible)
   {
-    w->priv.pool = [[NSAutoreleasePool alloc] init];
-    [NSApplication sharedApplication];
-
-    Class webViewDelegateClass =
-        objc_allocateClassPair([NSObject class], "WebViewDelegate", 0);
-    class_addMethod(webViewDelegateClass, sel_registerName("windowWillClose:"),
-                    (IMP)webview_window_will_close, "v@:@");
-    class_addMethod(object_getClass(webViewDelegateClass),
-                    sel_registerName("isSelectorExcludedFromWebScript:"),
-                    (IMP)webview_is_selector_excluded_from_web_script, "c@::");
-    class_addMethod(object_getClass(webViewDelegateClass),
-                    sel_registerName("webScriptNameForSelector:"),
-                    (IMP)webview_webscript_name_for_selector, "c@::");
-    class_addMethod(webViewDelegateClass,
-                    sel_registerName("webView:didClearWindowObject:forFrame:"),
-                    (IMP)webview_did_clear_window_object, "v@:@@@");
-    class_addMethod(
-        webViewDelegateClass,
-        sel_registerName("webView:runOpenPanelForFileButtonWithResultListener:"
-                         "allowMultipleFiles:"),
-        (IMP)webview_run_input_open_panel, "v@:@@c");
-    class_addMethod(webViewDelegateClass, sel_registerName("invoke:"),
-                    (IMP)webview_external_invoke, "v@:@");
-    objc_registerClassPair(webViewDelegateClass);
-
-    w->priv.delegate = [[webViewDelegateClass alloc] init];
-    objc_setAssociatedObject(w->priv.delegate, "webview", (id)(w),
-                             OBJC_ASSOCIATION_ASSIGN);
-
-    // Disable damn smart quotes
-    // Credit: https://stackoverflow.com/a/31640511
-    [[NSUserDefaults standardUserDefaults] setBool:NO forKey:@"NSAutomaticQuoteSubstitutionEnabled"];
-
-    NSRect r = NSMakeRect(0, 0, w->width, w->height);
-    NSUInteger style = NSWindowStyleMaskTitled | NSWindowStyleMaskClosable |
-                       NSWindowStyleMaskMiniaturizable;
-    if (w->resizable)
+    NSView *nsview = (NSView *)view;
+    if ([nsview superview] != w->priv.webview)
     {
-      style = style | NSWindowStyleMaskResizable;
-      // style = style | NSTexturedBackgroundWindowMask;
-      // style = style | NSUnifiedTitleAndToolbarWindowMask;
+      [w->priv.webview addSubview:nsview];
     }
-
-    // Transparent title bar
-    // if (w->transparentTitlebar) {
-    //   style = style | NSFullSizeContentViewWindowMask | NSUnifiedTitleAndToolbarWindowMask | NSTexturedBackgroundWindowMask;
-    // }
-
-    w->priv.window = [[NSWindow alloc] initWithContentRect:r
-                                                 styleMask:style
-                                                   backing:NSBackingStoreBuffered
-                                                     defer:NO];
-    [w->priv.window autorelease];
-
-    // Title
-    NSString *nsTitle = [NSString stringWithUTF8String:w->title];
-    [w->priv.window setTitle:nsTitle];
-
-    [w->priv.window setDelegate:w->priv.delegate];
-    [w->priv.window center];
-
-    //  NSToolbar *toolbar = [[NSToolbar alloc] initWithIdentifier:@"wat"];
-    //   toolbar.showsBaselineSeparator = NO;
-    //   [w->priv.window setToolbar:toolbar];
-
-    // if (w->transparentTitlebar) {
-
-    //   // Configure window look with hidden toolbar
-    //   [w->priv.window setTitlebarAppearsTransparent:YES];
-    //   [w->priv.window setTitleVisibility:NSWindowTitleHidden];
-    //   // w->priv.window.isMovableByWindowBackground = true;
-    // }
-
-    [[NSUserDefaults standardUserDefaults] setBool:!!w->debug
-                                            forKey:@"WebKitDeveloperExtras"];
-    [[NSUserDefaults standardUserDefaults] synchronize];
-    w->priv.webview =
-        [[WebView alloc] initWithFrame:r
-                             frameName:@"WebView"
-                             groupName:nil];
-    NSURL *nsURL = [NSURL
-        URLWithString:[NSString stringWithUTF8String:webview_check_url(w->url)]];
-    [[w->priv.webview mainFrame] loadRequest:[NSURLRequest requestWithURL:nsURL]];
-
-    [w->priv.webview setAutoresizesSubviews:YES];
-    [w->priv.webview
-        setAutoresizingMask:NSViewWidthSizable | NSViewHeightSizable];
-    w->priv.webview.frameLoadDelegate = w->priv.delegate;
-    w->priv.webview.UIDelegate = w->priv.delegate;
-    [[w->priv.window contentView] addSubview:w->priv.webview];
-    [w->priv.window orderFrontRegardless];
-
-    // Disable scrolling - make this configurable
-    // [[[w->priv.webview mainFrame] frameView] setAllowsScrolling:NO];
-
-    // ----> Enables WebGL but won't pass the app store guidelines
-    //
-    // WebPreferences *p = [w->priv.webview preferences];
-    // if ([p respondsToSelector:@selector(setWebGLEnabled:)]) {
-    //     [p setWebGLEnabled:YES];
-    // }
-
-    [NSApp setActivationPolicy:NSApplicationActivationPolicyRegular];
-    [NSApp finishLaunching];
-    [NSApp activateIgnoringOtherApps:YES];
-
-    NSMenu *menubar = [[[NSMenu alloc] initWithTitle:@""] autorelease];
-
-    NSMenuItem *appMenuItem =
-        [[[NSMenuItem alloc] initWithTitle:@"wails app" action:NULL keyEquivalent:@""]
-            autorelease];
-    NSMenu *appMenu = [[[NSMenu alloc] initWithTitle:@"wails app"] autorelease];
-    [appMenuItem setSubmenu:appMenu];
-    [menubar addItem:appMenuItem];
-
-    NSMenuItem *item = [[[NSMenuItem alloc] initWithTitle:@"Hide"
-                                                   action:@selector(hide:)
-                                            keyEquivalent:@"h"] autorelease];
-    [appMenu addItem:item];
-    item = [[[NSMenuItem alloc] initWithTitle:@"Hide Others"
-                                       action:@selector(hideOtherApplications:)
-                                keyEquivalent:@"h"] autorelease];
-    [item setKeyEquivalentModifierMask:(NSEventModifierFlagOption |
-                                        NSEventModifierFlagCommand)];
-    [appMenu addItem:item];
-    item = [[[NSMenuItem alloc] initWithTitle:@"Show All"
-                                       action:@selector(unhideAllApplications:)
-                                keyEquivalent:@""] autorelease];
-    [appMenu addItem:item];
-    [appMenu addItem:[NSMenuItem separatorItem]];
-
-    NSMenuItem *editMenuItem =
-        [[[NSMenuItem alloc] initWithTitle:@"Edit" action:NULL keyEquivalent:@""]
-            autorelease];
-    NSMenu *editMenu = [[[NSMenu alloc] initWithTitle:@"Edit"] autorelease];
-    [editMenuItem setSubmenu:editMenu];
-    [menubar addItem:editMenuItem];
-
-    item = [[[NSMenuItem alloc] initWithTitle:@"Cut"
-                                       action:@selector(cut:)
-                                keyEquivalent:@"x"] autorelease];
-    [editMenu addItem:item];
-
-    item = [[[NSMenuItem alloc] initWithTitle:@"Copy"
-                                       action:@selector(copy:)
-                                keyEquivalent:@"c"] autorelease];
-    [editMenu addItem:item];
-
-    item = [[[NSMenuItem alloc] initWithTitle:@"Paste"
-                                       action:@selector(paste:)
-                                keyEquivalent:@"v"] autorelease];
-    [editMenu addItem:item];
-
-    item = [[[NSMenuItem alloc] initWithTitle:@"Select All"
-                                       action:@selector(selectAll:)
-                                keyEquivalent:@"a"] autorelease];
-    [editMenu addItem:item];
-
-    [appMenu addItem:[NSMenuItem separatorItem]];
-
-    item = [[[NSMenuItem alloc] initWithTitle:@"Quit"
-                                       action:@selector(terminate:)
-                                keyEquivalent:@"q"] autorelease];
-    [appMenu addItem:item];
-
-    [NSApp setMainMenu:menubar];
-
-    w->priv.should_exit = 0;
-    return 0;
+    // The page's origin is at the top left
+    CGFloat top = y;
+    if (![w->priv.webview isFlipped])
+    {
+      top = [w->priv.webview bounds].size.height - y - height;
+    }
+    [nsview setFrame:NSMakeRect(x, top, width, height)];
+    [nsview setHidden:!visible];
   }
 
-  WEBVIEW_API int webview_loop(struct webview *w, int blocking)
+  WEBVIEW_API void webview_remove_overlay(struct webview *w, void *view)
   {
-    NSDate *until = (blocking ? [NSDate distantFuture] : [NSDate distantPast]);
-    NSEvent *event = [NSApp nextEventMatchingMask:NSEventMaskAny
-                                        untilDate:until
-                                           inMode:NSDefaultRunLoopMode
-                                          dequeue:YES];
-    if (event)
+    NSView *nsview = (NSView *)view;
+    if ([nsview superview] == w->priv.webview)
     {
-      [NSApp sendEvent:event];
+      [nsview removeFromSuperview];
     }
-    return w->priv.should_exit;
   }
 
-  WEBVIEW_API int webview_eval(struct webview *w, const char *js)
+  // Handles the 'Wail'/'Call' and 'Wail'/'List' Apple Events. The method is
+  // the direct parameter and the JSON arguments are the 'Args' parameter.
+  static void webview_handle_automation_event(id self, SEL cmd,
+                                              NSAppleEventDescriptor *event,
+                                              NSAppleEventDescriptor *replyEvent)
   {
-    NSString *nsJS = [NSString stringWithUTF8String:js];
-    [[w->priv.webview windowScriptObject] evaluateWebScript:nsJS];
-    return 0;
+    struct webview *w =
+        (struct webview *)objc_getAssociatedObject(self, "webview");
+    if (w == NULL || w->automation_cb == NULL)
+    {
+      return;
+    }
+    const char *method = "";
+    const char *args = "";
+    if ([event eventID] == 'Call')
+    {
+      NSString *value = [[event paramDescriptorForKeyword:keyDirectObject] stringValue];
+      if (value != nil)
+      {
+        method = [value UTF8String];
+      }
+      value = [[event paramDescriptorForKeyword:'Args'] stringValue];
+      if (value != nil)
+      {
+        args = [value UTF8String];
+      }
+    }
+    // The reply is sent once the result is ready
+    NSAppleEventManagerSuspensionID suspension =
+        [[NSAppleEventManager sharedAppleEventManager] suspendCurrentAppleEvent];
+    w->automation_cb(w, method, args, (void *)suspension);
   }
 
-  WEBVIEW_API void webview_set_title(struct webview *w, const char *title)
-  {
-    NSString *nsTitle = [NSString stringWithUTF8String:title];
-    [w->priv.window setTitle:nsTitle];
+  WEBVIEW_API void webview_automation_start(struct webview *w, const char *name)
+  {
+    NSAppleEventManager *manager = [NSAppleEventManager sharedAppleEventManager];
+    [manager setEventHandler:w->priv.delegate
+                 andSelector:sel_registerName("handleAutomationEvent:withReplyEvent:")
+               forEventClass:'Wail'
+                  andEventID:'Call'];
+    [manager setEventHandler:w->priv.delegate
+                 andSelector:sel_registerName("handleAutomationEvent:withReplyEvent:")
+               forEventClass:'Wail'
+                  andEventID:'List'];
   }
 
-  WEBVIEW_API void webview_focus(struct webview *w)
+  WEBVIEW_API void webview_automation_reply(struct webview *w, void *reply,
+                                            const char *result, const char *error)
   {
-    [w->priv.window makeKeyWindow];
-  }
-  
-  WEBVIEW_API void webview_minsize(struct webview *w, int width, int height) {
-    NSSize size;
-    size.width = width;
-    size.height = height;
-    [w->priv.window setMinSize:size];
+    NSAppleEventManager *manager = [NSAppleEventManager sharedAppleEventManager];
+    NSAppleEventManagerSuspensionID suspension =
+        (NSAppleEventManagerSuspensionID)reply;
+    NSAppleEventDescriptor *replyEvent =
+        [manager replyAppleEventForSuspensionID:suspension];
+    if (error != NULL)
+    {
+      [replyEvent setParamDescriptor:[NSAppleEventDescriptor descriptorWithInt32:errAEEventFailed]
+                          forKeyword:keyErrorNumber];
+      [replyEvent setParamDescriptor:[NSAppleEventDescriptor descriptorWithString:[NSString stringWithUTF8String:error]]
+                          forKeyword:keyErrorString];
+    }
+    else
+    {
+      [replyEvent setParamDescriptor:[NSAppleEventDescriptor descriptorWithString:[NSString stringWithUTF8String:result]]
+                          forKeyword:keyDirectObject];
+    }
+    [manager resumeWithSuspensionID:suspension];
   }
-  
-  WEBVIEW_API void webview_maxsize(struct webview *w, int width, int height) {
-    NSSize size;
-    size.width = width;
-    size.height = height;
-    [w->priv.window setMaxSize:size];
 
-    NSButton *button = [w->priv.window standardWindowButton:NSWindowZoomButton];
-    [button performSelectorOnMainThread:@selector(setEnabled:) withObject:NO
-    waitUntilDone:NO];
-  }
-  
-  WEBVIEW_API void webview_set_fullscreen(struct webview *w, int fullscreen)
+  WEBVIEW_API void webview_geolocation_reply(struct webview *w, void *request,
+                                             int allow)
   {
-    int b = ((([w->priv.window styleMask] & NSWindowStyleMaskFullScreen) ==
-              NSWindowStyleMaskFullScreen)
-                 ? 1
-                 : 0);
-    if (b != fullscreen)
-    {
-      [w->priv.window toggleFullScreen:nil];
-    }
+    id listener = (id)request;
+    [listener performSelector:sel_registerName(allow ? "allow" : "deny")];
+    [listener release];
   }
 
   WEBVIEW_API void webview_set_color(struct webview *w, uint8_t r, uint8_t g,
@@ -2493,6 +9927,31 @@ struct webview_priv
     w->priv.should_exit = 1;
   }
   WEBVIEW_API void webview_exit(struct webview *w) { [NSApp terminate:NSApp]; }
+
+  WEBVIEW_API void webview_close(struct webview *w)
+  {
+    if (w->host != NULL)
+    {
+      // The split view is left to the host's webview when its last pane closes
+      NSSplitView *split = (NSSplitView *)[w->priv.webview superview];
+      [w->priv.webview removeFromSuperview];
+      [w->priv.webview release];
+      w->priv.webview = nil;
+      if ([[split subviews] count] == 1)
+      {
+        WebView *webview = w->host->priv.webview;
+        [webview setFrame:[split frame]];
+        [[split superview] addSubview:webview];
+        [split removeFromSuperview];
+      }
+      if (webview_unlink_pane(w) && w->closed_cb != NULL)
+      {
+        w->closed_cb(w);
+      }
+      return;
+    }
+    [w->priv.window close];
+  }
   WEBVIEW_API void webview_print_log(const char *s) { NSLog(@"%s", s); }
 
 #endif /* WEBVIEW_COCOA */
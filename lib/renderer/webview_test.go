@@ -0,0 +1,129 @@
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+	wv "github.com/wailsapp/wails/lib/renderer/webview"
+)
+
+// fakeWindow runs dispatched functions straight away and records the pages
+// loaded
+type fakeWindow struct {
+	wv.WebView
+	loaded []string
+}
+
+func (f *fakeWindow) Dispatch(fn func()) {
+	fn()
+}
+
+func (f *fakeWindow) Navigate(url string) {
+	f.loaded = append(f.loaded, url)
+}
+
+func (f *fakeWindow) Reload() {
+	f.loaded = append(f.loaded, "reload")
+}
+
+func (f *fakeWindow) Eval(js string) error {
+	return nil
+}
+
+// fakeHTMLConfig renders a new page each time the HTML is loaded
+type fakeHTMLConfig struct {
+	interfaces.AppConfig
+	renders int
+	err     error
+}
+
+func (f *fakeHTMLConfig) GetHTML() (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.renders++
+	return fmt.Sprintf("data:text/html,<body data-nonce=%d>", f.renders), nil
+}
+
+func TestReloadRendersHTML(t *testing.T) {
+	window := &fakeWindow{}
+	config := &fakeHTMLConfig{}
+	w := &WebView{window: window, config: config, log: logger.NewCustomLogger("WebView")}
+
+	w.Reload()
+	w.Reload()
+	want := []string{"data:text/html,<body data-nonce=1>", "data:text/html,<body data-nonce=2>"}
+	if !reflect.DeepEqual(window.loaded, want) {
+		t.Errorf("Reload() loaded %q, want %q", window.loaded, want)
+	}
+
+	// The page is reloaded as it was if the HTML can't be rendered
+	window.loaded = nil
+	config.err = errors.New("bad template")
+	w.Reload()
+	if !reflect.DeepEqual(window.loaded, []string{"reload"}) {
+		t.Errorf("Reload() with a bad template loaded %q, want a reload", window.loaded)
+	}
+}
+
+// fakePassthroughWindow records whether the window ignores mouse input, with
+// the cursor outside of the window
+type fakePassthroughWindow struct {
+	wv.WebView
+	lock     sync.Mutex
+	ignoring []bool
+}
+
+func (f *fakePassthroughWindow) Dispatch(fn func()) {
+	fn()
+}
+
+func (f *fakePassthroughWindow) SetIgnoreMouseEvents(ignore bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.ignoring = append(f.ignoring, ignore)
+}
+
+func (f *fakePassthroughWindow) CursorPosition() (x, y int) {
+	return -1, -1
+}
+
+func (f *fakePassthroughWindow) changes() []bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]bool(nil), f.ignoring...)
+}
+
+func TestInputRegions(t *testing.T) {
+	window := &fakePassthroughWindow{}
+	w := &WebView{window: window, log: logger.NewCustomLogger("WebView")}
+
+	// Input outside of the regions passes through
+	w.SetInputRegions([]image.Rectangle{image.Rect(0, 0, 100, 20)})
+	deadline := time.Now().Add(time.Second)
+	for len(window.changes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(passthroughInterval)
+	}
+	if got := window.changes(); !reflect.DeepEqual(got, []bool{true}) {
+		t.Fatalf("the window was set to ignore input %v with the cursor outside of the regions", got)
+	}
+
+	// Removing the regions stops the tracking and restores the window
+	w.SetInputRegions(nil)
+	time.Sleep(3 * passthroughInterval)
+	if got := window.changes(); !reflect.DeepEqual(got, []bool{true, false}) {
+		t.Errorf("the window was set to ignore input %v after the regions were removed", got)
+	}
+	w.passthrough.lock.Lock()
+	defer w.passthrough.lock.Unlock()
+	if w.passthrough.stop != nil {
+		t.Errorf("the cursor was still tracked after the regions were removed")
+	}
+}
@@ -0,0 +1,136 @@
+package renderer
+
+import (
+	"encoding/json"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	wv "github.com/wailsapp/wails/lib/renderer/webview"
+)
+
+// windowState is how the main window was when it last closed. It is saved
+// when AppConfig.PersistWindowState is set.
+type windowState struct {
+	X         int  `json:"x"`
+	Y         int  `json:"y"`
+	Width     int  `json:"width"`
+	Height    int  `json:"height"`
+	Maximised bool `json:"maximised"`
+
+	// The display the window was on. The window is centred on it if the
+	// saved position is no longer on any display.
+	Display int `json:"display"`
+}
+
+// The part of the window that must be on a display for its saved position
+// to be used, so it can be dragged
+var windowStateGrabArea = image.Rect(0, 0, 100, 30)
+
+// windowStateFilename returns the file the window state is saved in, under
+// the app's title in the user's config directory
+func windowStateFilename(title string) string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	name := strings.ToLower(strings.Replace(title, " ", "-", -1))
+	return filepath.Join(configDir, name, "window.json")
+}
+
+// loadWindowState reads the saved window state. It returns nil
+// if there is none.
+func loadWindowState(filename string) *windowState {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil
+	}
+	state := &windowState{}
+	if json.Unmarshal(data, state) != nil || state.Width <= 0 || state.Height <= 0 {
+		return nil
+	}
+	return state
+}
+
+func (s *windowState) save(filename string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// visible returns true if the top left of the window is on one of the
+// given displays at the saved position
+func (s *windowState) visible(displays []image.Rectangle) bool {
+	grabArea := windowStateGrabArea.Add(image.Pt(s.X, s.Y))
+	for _, display := range displays {
+		if grabArea.In(display) {
+			return true
+		}
+	}
+	return false
+}
+
+// displayOf returns the display holding the centre of the given window
+// bounds, or the first display it overlaps. It returns -1 if there is none.
+func displayOf(bounds image.Rectangle, displays []image.Rectangle) int {
+	centre := image.Pt((bounds.Min.X+bounds.Max.X)/2, (bounds.Min.Y+bounds.Max.Y)/2)
+	for index, display := range displays {
+		if centre.In(display) {
+			return index
+		}
+	}
+	for index, display := range displays {
+		if bounds.Overlaps(display) {
+			return index
+		}
+	}
+	return -1
+}
+
+// restoreWindowState moves the window to its saved position, or centres it
+// on its saved display if it would be off screen. It must be called on the
+// main thread.
+func (w *WebView) restoreWindowState(state *windowState) {
+	displays := w.displays()
+	switch {
+	case state.visible(displays):
+		w.window.SetPosition(state.X, state.Y)
+	case state.Display >= 0 && state.Display < len(displays):
+		w.window.Place(state.Display, wv.EdgeNone, 0)
+	default:
+		w.window.Place(-1, wv.EdgeNone, 0)
+	}
+	if state.Maximised {
+		w.window.SetMaximised(true)
+	}
+}
+
+// saveWindowState saves the state of the window if it is persisted. The size
+// and position of a maximised window are those from before it was maximised.
+// It must be called on the main thread.
+func (w *WebView) saveWindowState() {
+	if w.windowStateFile == "" {
+		return
+	}
+	state := w.windowState
+	state.Maximised = w.window.Maximised()
+	x, y := w.window.Position()
+	width, height := w.window.Size()
+	if !state.Maximised {
+		state.X, state.Y = x, y
+		state.Width, state.Height = width, height
+	}
+	state.Display = displayOf(image.Rect(x, y, x+width, y+height), w.displays())
+	err := state.save(w.windowStateFile)
+	if err != nil {
+		w.log.Errorf("Unable to save the window state: %s", err.Error())
+	}
+}
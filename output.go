@@ -0,0 +1,41 @@
+package wails
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// captureOutput replaces os.Stdout and os.Stderr with pipes that write
+// each line to the log
+func captureOutput() error {
+	log := logger.NewCustomLogger("Output")
+	stdout, err := logLines(log.Info)
+	if err != nil {
+		return err
+	}
+	stderr, err := logLines(log.Error)
+	if err != nil {
+		return err
+	}
+	os.Stdout = stdout
+	os.Stderr = stderr
+	return nil
+}
+
+// logLines returns a file that passes each line written to it to the given
+// log function
+func logLines(logLine func(string)) (*os.File, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			logLine(scanner.Text())
+		}
+	}()
+	return writer, nil
+}
@@ -0,0 +1,60 @@
+package wails
+
+import (
+	"strconv"
+)
+
+// PaneOptions is the configuration of a pane created with App.NewPane or
+// Window.NewPane
+type PaneOptions struct {
+	// The width of the pane in pixels, or its height when Vertical. The pane
+	// takes half of the window if it isn't set.
+	Size int
+
+	// Docks the pane below the webviews of the window instead of beside them.
+	// On Windows and MacOS, the panes of a window all take the orientation
+	// of the first.
+	Vertical bool
+
+	// The HTML, Javascript and CSS of the pane, as in AppConfig
+	HTML string
+	JS   string
+	CSS  string
+
+	// The route the pane opens at, set as the location hash before its
+	// Javascript runs, EG: "/editor" for a hash based router
+	Route string
+}
+
+// NewPane creates a pane docked in the main window, after its webview and
+// earlier panes, with a splitter between them. A pane is a Window with its
+// own bound structs, events and runtime, so it can be reloaded or closed
+// without affecting the rest of the window. Bind the pane's structs then
+// call Open to show it.
+func (a *App) NewPane(options *PaneOptions) *Window {
+	return a.newPane(nil, options)
+}
+
+// NewPane creates a pane docked in the window, as with App.NewPane. The
+// window must be opened before its panes.
+func (w *Window) NewPane(options *PaneOptions) *Window {
+	return w.app.newPane(w, options)
+}
+
+func (a *App) newPane(host *Window, options *PaneOptions) *Window {
+	if options == nil {
+		options = &PaneOptions{}
+	}
+	js := options.JS
+	if options.Route != "" {
+		js = "window.location.hash=" + strconv.Quote(options.Route) + ";" + js
+	}
+	pane := a.NewWindow(&WindowOptions{
+		HTML: options.HTML,
+		JS:   js,
+		CSS:  options.CSS,
+	})
+	pane.host = host
+	pane.pane = options
+	return pane
+}
@@ -0,0 +1,48 @@
+package wails
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/wailsapp/wails/lib/perf"
+)
+
+// recordPerformance records how long calls take and asks the frontend for
+// its performance entries each time it's ready, for reportPerformance
+func (a *App) recordPerformance() {
+	a.recorder = perf.NewRecorder()
+	a.ipc.RecordPerformance(a.recorder)
+
+	a.eventManager.On("wails:ready", func(...interface{}) {
+		a.eventManager.Emit(perf.ObserveEvent)
+	})
+	a.eventManager.On(perf.EntriesEvent, func(data ...interface{}) {
+		if len(data) == 0 {
+			return
+		}
+		encoded, err := json.Marshal(data[0])
+		if err != nil {
+			return
+		}
+		var entries []perf.Entry
+		err = json.Unmarshal(encoded, &entries)
+		if err != nil {
+			a.log.Errorf("Invalid performance entries: %s", err.Error())
+			return
+		}
+		a.recorder.RecordEntries(entries)
+	})
+}
+
+// reportPerformance prints the report started by recordPerformance
+func (a *App) reportPerformance() {
+	if !a.perfReport || a.recorder == nil {
+		return
+	}
+	fmt.Println()
+	err := a.recorder.Report(os.Stdout)
+	if err != nil {
+		a.log.Errorf("Unable to write the performance report: %s", err.Error())
+	}
+}
@@ -0,0 +1,112 @@
+// Package cli parses the flags an app is launched with, before the GUI starts.
+// Arguments it doesn't recognise are kept, in order, so apps can still parse
+// their own.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Flags are the flags the app was launched with
+type Flags struct {
+	// Start the app minimised (--minimized)
+	Minimized bool `json:"minimized"`
+
+	// The arguments are files another app shared with this one (--share).
+	// See runtime.Share.
+	Share bool `json:"share"`
+
+	// The name of the profile to use (--profile <name>)
+	Profile string `json:"profile"`
+
+	// The bound method to call instead of starting the GUI, EG: "Counter.Add"
+	// (--headless-command <method>). See HeadlessArgs.
+	HeadlessCommand string `json:"headlessCommand"`
+
+	// The arguments that weren't recognised
+	Args []string `json:"args"`
+}
+
+// Parse parses the given arguments, which are normally os.Args[1:]. Flags
+// may start with one or two dashes and take their value from the next
+// argument or after an "=". Everything after "--" is left unparsed.
+func Parse(args []string) (*Flags, error) {
+	result := &Flags{
+		Args: []string{},
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			result.Args = append(result.Args, args[i+1:]...)
+			break
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if name == arg || name == "" {
+			result.Args = append(result.Args, arg)
+			continue
+		}
+		value, hasValue := "", false
+		if index := strings.Index(name, "="); index >= 0 {
+			name, value, hasValue = name[:index], name[index+1:], true
+		}
+
+		// Takes the flag's value from the next argument if not given after "="
+		stringValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("flag needs an argument: %s", arg)
+			}
+			i++
+			return args[i], nil
+		}
+
+		var err error
+		switch name {
+		case "minimized":
+			result.Minimized = true
+			if hasValue {
+				result.Minimized, err = strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value %q for flag %s", value, arg)
+				}
+			}
+		case "share":
+			result.Share = true
+		case "profile":
+			result.Profile, err = stringValue()
+		case "headless-command":
+			result.HeadlessCommand, err = stringValue()
+		default:
+			result.Args = append(result.Args, arg)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// HeadlessArgs returns the arguments for the headless command as a JSON array.
+// Each unrecognised argument is passed as JSON if it is valid JSON, EG: 1 or
+// true, or as a string otherwise.
+func (f *Flags) HeadlessArgs() string {
+	args := make([]json.RawMessage, 0, len(f.Args))
+	for _, arg := range f.Args {
+		if json.Valid([]byte(arg)) {
+			args = append(args, json.RawMessage(arg))
+			continue
+		}
+		value, _ := json.Marshal(arg)
+		args = append(args, value)
+	}
+	result, _ := json.Marshal(args)
+	return string(result)
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want *Flags
+	}{
+		{"none", []string{}, &Flags{Args: []string{}}},
+		{"minimized", []string{"--minimized"}, &Flags{Minimized: true, Args: []string{}}},
+		{"minimized=false", []string{"-minimized=false"}, &Flags{Args: []string{}}},
+		{"share", []string{"--share", "a.txt", "b.txt"}, &Flags{Share: true, Args: []string{"a.txt", "b.txt"}}},
+		{"profile", []string{"--profile", "work"}, &Flags{Profile: "work", Args: []string{}}},
+		{"unknown", []string{"-loglevel", "info", "file.txt"}, &Flags{Args: []string{"-loglevel", "info", "file.txt"}}},
+		{"terminator", []string{"--", "--profile", "work"}, &Flags{Args: []string{"--profile", "work"}}},
+		{"headless", []string{"--headless-command", "Counter.Add", "1", "2"}, &Flags{HeadlessCommand: "Counter.Add", Args: []string{"1", "2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args)
+			if err != nil {
+				t.Errorf("Parse() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMissingValue(t *testing.T) {
+	_, err := Parse([]string{"--profile"})
+	if err == nil {
+		t.Error("Parse() expected an error")
+	}
+}
+
+func TestHeadlessArgs(t *testing.T) {
+	flags := &Flags{Args: []string{"1", "true", "hello", `{"a":1}`}}
+	want := `[1,true,"hello",{"a":1}]`
+	if got := flags.HeadlessArgs(); got != want {
+		t.Errorf("HeadlessArgs() = %s, want %s", got, want)
+	}
+}
@@ -115,6 +115,7 @@ function startBridge() {
 		hideReconnectOverlay();
 		clearInterval(window.wailsbridge.connectTimer);
 		window.wailsbridge.websocket.onclose = handleDisconnect;
+		window.wailsbridge.websocket.binaryType = 'arraybuffer';
 		window.wailsbridge.websocket.onmessage = handleMessage;
 		window.wailsbridge.connectionState = 'connected';
 	}
@@ -147,6 +148,12 @@ function startBridge() {
 	}
 
 	function handleMessage(message) {
+		// Results that are binary come in binary frames
+		if (message.data instanceof ArrayBuffer) {
+			window.wails._.CallbackFrame(message.data);
+			return;
+		}
+
 		// As a bridge we ignore js and css injections
 		switch (message.data[0]) {
 		// Wails library - inject!
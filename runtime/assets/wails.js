@@ -1 +1,1816 @@
-!function(n){var e={};function t(r){if(e[r])return e[r].exports;var o=e[r]={i:r,l:!1,exports:{}};return n[r].call(o.exports,o,o.exports,t),o.l=!0,o.exports}t.m=n,t.c=e,t.d=function(n,e,r){t.o(n,e)||Object.defineProperty(n,e,{enumerable:!0,get:r})},t.r=function(n){"undefined"!=typeof Symbol&&Symbol.toStringTag&&Object.defineProperty(n,Symbol.toStringTag,{value:"Module"}),Object.defineProperty(n,"__esModule",{value:!0})},t.t=function(n,e){if(1&e&&(n=t(n)),8&e)return n;if(4&e&&"object"==typeof n&&n&&n.__esModule)return n;var r=Object.create(null);if(t.r(r),Object.defineProperty(r,"default",{enumerable:!0,value:n}),2&e&&"string"!=typeof n)for(var o in n)t.d(r,o,function(e){return n[e]}.bind(null,o));return r},t.n=function(n){var e=n&&n.__esModule?function(){return n.default}:function(){return n};return t.d(e,"a",e),e},t.o=function(n,e){return Object.prototype.hasOwnProperty.call(n,e)},t.p="",t(t.s=0)}([function(n,e,t){"use strict";t.r(e),t.d(e,"Init",(function(){return L}));var r={};t.r(r),t.d(r,"Debug",(function(){return l})),t.d(r,"Info",(function(){return s})),t.d(r,"Warning",(function(){return d})),t.d(r,"Error",(function(){return f})),t.d(r,"Fatal",(function(){return w}));var o={};t.r(o),t.d(o,"OpenURL",(function(){return h})),t.d(o,"OpenFile",(function(){return y}));var i={};t.r(i),t.d(i,"New",(function(){return N}));var a=[];function c(n,e,t){var r={type:n,callbackID:t,payload:e};!function(n){if(window.wailsbridge?window.wailsbridge.websocket.send(n):window.external.invoke(n),a.length>0)for(var e=0;e<a.length;e++)a[e](n)}(JSON.stringify(r))}function u(n,e){c("log",{level:n,message:e})}function l(n){u("debug",n)}function s(n){u("info",n)}function d(n){u("warning",n)}function f(n){u("error",n)}function w(n){u("fatal",n)}var p,v={};function g(n,e,t){return null!=t&&null!=t||(t=0),new Promise((function(r,o){var i;do{i=n+"-"+p()}while(v[i]);if(t>0)var a=setTimeout((function(){o(Error("Call to "+n+" timed out. Request ID: "+i))}),t);v[i]={timeoutHandle:a,reject:o,resolve:r};try{c("call",{bindingName:n,data:JSON.stringify(e)},i)}catch(n){console.error(n)}}))}function b(n,e){return g(".wails."+n,e)}function h(n){return b("Browser.OpenURL",n)}function y(n){return b("Browser.OpenFile",n)}p=window.crypto?function(){var n=new Uint32Array(1);return window.crypto.getRandomValues(n)[0]}:function(){return 9007199254740991*Math.random()};var m=function n(e,t){!function(n,e){if(!(n instanceof e))throw new TypeError("Cannot call a class as a function")}(this,n),t=t||-1,this.Callback=function(n){return e.apply(null,n),-1!==t&&0===(t-=1)}},E={};function O(n,e,t){E[n]=E[n]||[];var r=new m(e,t);E[n].push(r)}function S(n){var e=JSON.stringify([].slice.apply(arguments).slice(1)),t={name:n,data:e};c("event",t)}var j={};function k(n){try{return new Function("var "+n),!0}catch(n){return!1}}function N(n,e){var t,r=this;if(!window.wails)throw Error("Wails is not initialised");var o=[];return this.subscribe=function(n){o.push(n)},this.set=function(e){t=e,window.wails.Events.Emit("wails:sync:store:updatedbyfrontend:"+n,JSON.stringify(t)),o.forEach((function(n){n(t)}))},this.update=function(n){var e=n(t);r.set(e)},window.wails.Events.On("wails:sync:store:updatedbybackend:"+n,(function(n){n=JSON.parse(n),t=n,o.forEach((function(n){n(t)}))})),e&&this.set(e),this}function C(){return(C=Object.assign||function(n){for(var e=1;e<arguments.length;e++){var t=arguments[e];for(var r in t)Object.prototype.hasOwnProperty.call(t,r)&&(n[r]=t[r])}return n}).apply(this,arguments)}window.backend={},window.wails=window.wails||{},window.backend={},null==window.external&&(window.external={invoke:function(n){window.webkit.messageHandlers.external.postMessage(n)}});var I={NewBinding:function(n){var e=[].concat(n.split(".").splice(1)),t=window.backend;if(e.length>1)for(var r=0;r<e.length-1;r+=1){var o=e[r];if(!k(o))return new Error("".concat(o," is not a valid javascript identifier."));t[o]||(t[o]={}),t=t[o]}var i=e.pop();if(!k(i))return new Error("".concat(i," is not a valid javascript identifier."));t[i]=function(){var e=0;function t(){var t=[].slice.call(arguments);return g(n,t,e)}return t.setTimeout=function(n){e=n},t.getTimeout=function(){return e},t}()},Callback:function(n){var e;n=decodeURIComponent(n.replace(/\s+/g,"").replace(/[0-9a-f]{2}/g,"%$&"));try{e=JSON.parse(n)}catch(e){var t="Invalid JSON passed to callback: ".concat(e.message,". Message: ").concat(n);throw l(t),new Error(t)}var r=e.callbackid,o=v[r];if(!o){var i="Callback '".concat(r,"' not registed!!!");throw console.error(i),new Error(i)}clearTimeout(o.timeoutHandle),delete v[r],e.error?o.reject(e.error):o.resolve(e.data)},Notify:function(n,e){if(E[n]){for(var t=E[n].slice(),r=0;r<E[n].length;r+=1){var o=E[n][r],i=[];if(e)try{i=JSON.parse(e)}catch(e){f("Invalid JSON data sent to notify. Event name = "+n)}o.Callback(i)&&t.splice(r,1)}E[n]=t}},AddScript:function(n,e){var t=document.createElement("script");t.text=n,document.body.appendChild(t),e&&S(e)},InjectCSS:function(n){var e=document.createElement("style");e.setAttribute("type","text/css"),e.styleSheet?e.styleSheet.cssText=n:e.appendChild(document.createTextNode(n)),(document.head||document.getElementsByTagName("head")[0]).appendChild(e)},Init:L,AddIPCListener:function(n){a.push(n)}},x={Log:r,Browser:o,Events:{On:function(n,e){O(n,e)},OnMultiple:O,Emit:S,Heartbeat:function(n,e,t){var r=null;j[n]=function(){clearInterval(r),t()},r=setInterval((function(){S(n)}),e)},Acknowledge:function(n){if(!j[n])throw new f("Cannot acknowledge unknown heartbeat '".concat(n,"'"));j[n]()}},Store:i,_:I};function L(n){n()}C(window.wails,x),window.onerror=function(n,e,t,r,o){window.wails.Log.Error("**** Caught Unhandled Error ****"),window.wails.Log.Error("Message: "+n),window.wails.Log.Error("URL: "+e),window.wails.Log.Error("Line No: "+t),window.wails.Log.Error("Column No: "+r),window.wails.Log.Error("error: "+o)},window.usefirebug&&function(){document.getElementsByTagName("html")[0].setAttribute("debug","true");var n=document.createElement("script");n.src="https://wails.app/assets/js/firebug-lite.js#startOpened=true,disableWhenFirebugActive=false",n.type="application/javascript",document.head.appendChild(n),window.wails.Log.Info("Injected firebug")}(),S("wails:loaded")}]);
\ No newline at end of file
+(function (modules) {
+var installedModules = {};
+function __webpack_require__(moduleId) {
+if (installedModules[moduleId]) {
+return installedModules[moduleId].exports;
+}
+var module = installedModules[moduleId] = { i: moduleId, l: false, exports: {} };
+modules[moduleId].call(module.exports, module, module.exports, __webpack_require__);
+module.l = true;
+return module.exports;
+}
+__webpack_require__.d = function (exports, name, getter) {
+if (!Object.prototype.hasOwnProperty.call(exports, name)) {
+Object.defineProperty(exports, name, { enumerable: true, get: getter });
+}
+};
+__webpack_require__.r = function (exports) {
+if (typeof Symbol !== 'undefined' && Symbol.toStringTag) {
+Object.defineProperty(exports, Symbol.toStringTag, { value: 'Module' });
+}
+Object.defineProperty(exports, '__esModule', { value: true });
+};
+return __webpack_require__(0);
+})([
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "Init", function() { return Init; });
+function _extends() { _extends = Object.assign || function (target) { for (var i = 1; i < arguments.length; i++) { var source = arguments[i]; for (var key in source) { if (Object.prototype.hasOwnProperty.call(source, key)) { target[key] = source[key]; } } } return target; }; return _extends.apply(this, arguments); }
+var _log__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(1);
+var _browser__WEBPACK_IMPORTED_MODULE_1__ = __webpack_require__(3);
+var _support__WEBPACK_IMPORTED_MODULE_2__ = __webpack_require__(5);
+var _permissions__WEBPACK_IMPORTED_MODULE_3__ = __webpack_require__(6);
+var _dialog__WEBPACK_IMPORTED_MODULE_4__ = __webpack_require__(7);
+var _window__WEBPACK_IMPORTED_MODULE_5__ = __webpack_require__(8);
+var _fonts__WEBPACK_IMPORTED_MODULE_6__ = __webpack_require__(10);
+var _system__WEBPACK_IMPORTED_MODULE_7__ = __webpack_require__(11);
+var _purchases__WEBPACK_IMPORTED_MODULE_8__ = __webpack_require__(12);
+var _menu__WEBPACK_IMPORTED_MODULE_9__ = __webpack_require__(13);
+var _share__WEBPACK_IMPORTED_MODULE_10__ = __webpack_require__(14);
+var _calendar__WEBPACK_IMPORTED_MODULE_11__ = __webpack_require__(15);
+var _reminders__WEBPACK_IMPORTED_MODULE_12__ = __webpack_require__(16);
+var _power__WEBPACK_IMPORTED_MODULE_13__ = __webpack_require__(17);
+var _feedback__WEBPACK_IMPORTED_MODULE_14__ = __webpack_require__(18);
+var _touch__WEBPACK_IMPORTED_MODULE_15__ = __webpack_require__(19);
+var _clipboard__WEBPACK_IMPORTED_MODULE_16__ = __webpack_require__(20);
+var _gamepads__WEBPACK_IMPORTED_MODULE_17__ = __webpack_require__(21);
+var _events__WEBPACK_IMPORTED_MODULE_18__ = __webpack_require__(9);
+var _bindings__WEBPACK_IMPORTED_MODULE_19__ = __webpack_require__(22);
+var _calls__WEBPACK_IMPORTED_MODULE_20__ = __webpack_require__(4);
+var _utils__WEBPACK_IMPORTED_MODULE_21__ = __webpack_require__(23);
+var _ipc__WEBPACK_IMPORTED_MODULE_22__ = __webpack_require__(2);
+var _overlays__WEBPACK_IMPORTED_MODULE_23__ = __webpack_require__(24);
+var _perf__WEBPACK_IMPORTED_MODULE_24__ = __webpack_require__(25);
+var _devoverlay__WEBPACK_IMPORTED_MODULE_25__ = __webpack_require__(26);
+var _fullscreen__WEBPACK_IMPORTED_MODULE_26__ = __webpack_require__(27);
+var _touch__WEBPACK_IMPORTED_MODULE_27__ = __webpack_require__(19);
+var _pointer__WEBPACK_IMPORTED_MODULE_28__ = __webpack_require__(28);
+var _gamepads__WEBPACK_IMPORTED_MODULE_29__ = __webpack_require__(21);
+var _filedrop__WEBPACK_IMPORTED_MODULE_30__ = __webpack_require__(29);
+var _store__WEBPACK_IMPORTED_MODULE_31__ = __webpack_require__(30);
+window.wails = window.wails || {};
+window.backend = {};
+if (window.external == undefined) {
+window.external = {
+invoke: function(x) {
+window.webkit.messageHandlers.external.postMessage(x);
+}
+};
+}
+var internal = {
+NewBinding: _bindings__WEBPACK_IMPORTED_MODULE_19__["NewBinding"],
+Callback: _calls__WEBPACK_IMPORTED_MODULE_20__["Callback"],
+CallbackBinary: _calls__WEBPACK_IMPORTED_MODULE_20__["CallbackBinary"],
+CallbackFrame: _calls__WEBPACK_IMPORTED_MODULE_20__["CallbackFrame"],
+SetMaxPayloadSize: _calls__WEBPACK_IMPORTED_MODULE_20__["SetMaxPayloadSize"],
+Notify: _events__WEBPACK_IMPORTED_MODULE_18__["Notify"],
+AddScript: _utils__WEBPACK_IMPORTED_MODULE_21__["AddScript"],
+InjectCSS: _utils__WEBPACK_IMPORTED_MODULE_21__["InjectCSS"],
+Init: Init,
+AddIPCListener: _ipc__WEBPACK_IMPORTED_MODULE_22__["AddIPCListener"],
+TrackOverlay: _overlays__WEBPACK_IMPORTED_MODULE_23__["TrackOverlay"],
+UntrackOverlay: _overlays__WEBPACK_IMPORTED_MODULE_23__["UntrackOverlay"],
+SetSystemGestures: _pointer__WEBPACK_IMPORTED_MODULE_28__["SetSystemGestures"],
+};
+var runtime = {
+Log: _log__WEBPACK_IMPORTED_MODULE_0__,
+Browser: _browser__WEBPACK_IMPORTED_MODULE_1__,
+Support: _support__WEBPACK_IMPORTED_MODULE_2__,
+Permissions: _permissions__WEBPACK_IMPORTED_MODULE_3__,
+Dialog: _dialog__WEBPACK_IMPORTED_MODULE_4__,
+Window: _window__WEBPACK_IMPORTED_MODULE_5__,
+Fonts: _fonts__WEBPACK_IMPORTED_MODULE_6__,
+System: _system__WEBPACK_IMPORTED_MODULE_7__,
+Purchases: _purchases__WEBPACK_IMPORTED_MODULE_8__,
+Menu: _menu__WEBPACK_IMPORTED_MODULE_9__,
+Share: _share__WEBPACK_IMPORTED_MODULE_10__,
+Calendar: _calendar__WEBPACK_IMPORTED_MODULE_11__,
+Reminders: _reminders__WEBPACK_IMPORTED_MODULE_12__,
+Power: _power__WEBPACK_IMPORTED_MODULE_13__,
+Feedback: _feedback__WEBPACK_IMPORTED_MODULE_14__,
+Touch: _touch__WEBPACK_IMPORTED_MODULE_15__,
+Clipboard: _clipboard__WEBPACK_IMPORTED_MODULE_16__,
+Gamepads: _gamepads__WEBPACK_IMPORTED_MODULE_17__,
+Events: {
+On: _events__WEBPACK_IMPORTED_MODULE_18__["On"],
+OnMultiple: _events__WEBPACK_IMPORTED_MODULE_18__["OnMultiple"],
+Emit: _events__WEBPACK_IMPORTED_MODULE_18__["Emit"],
+Heartbeat: _events__WEBPACK_IMPORTED_MODULE_18__["Heartbeat"],
+Acknowledge: _events__WEBPACK_IMPORTED_MODULE_18__["Acknowledge"],
+},
+Store: _store__WEBPACK_IMPORTED_MODULE_31__,
+Calls: {
+SetRetry: _calls__WEBPACK_IMPORTED_MODULE_20__["SetRetry"],
+},
+_: internal,
+};
+_extends(window.wails, runtime);
+window.onerror = function (msg, url, lineNo, columnNo, error) {
+window.wails.Log.Error('**** Caught Unhandled Error ****');
+window.wails.Log.Error('Message: ' + msg);
+window.wails.Log.Error('URL: ' + url);
+window.wails.Log.Error('Line No: ' + lineNo);
+window.wails.Log.Error('Column No: ' + columnNo);
+window.wails.Log.Error('error: ' + error);
+};
+if( window.usefirebug ) {
+_utils__WEBPACK_IMPORTED_MODULE_21__["InjectFirebug"]();
+}
+window.addEventListener('beforeunload', function () {
+_events__WEBPACK_IMPORTED_MODULE_18__["Emit"]('wails:unloading');
+});
+_events__WEBPACK_IMPORTED_MODULE_18__["On"]('wails:perf:observe', _perf__WEBPACK_IMPORTED_MODULE_24__["ObservePerformance"]);
+_events__WEBPACK_IMPORTED_MODULE_18__["On"]('wails:devoverlay:enable', _devoverlay__WEBPACK_IMPORTED_MODULE_25__["EnableDevOverlay"]);
+_fullscreen__WEBPACK_IMPORTED_MODULE_26__["SetupFullscreen"]();
+_touch__WEBPACK_IMPORTED_MODULE_27__["SetupTouchKeyboard"]();
+_pointer__WEBPACK_IMPORTED_MODULE_28__["SetupPointerEvents"]();
+_gamepads__WEBPACK_IMPORTED_MODULE_29__["SetupGamepads"]();
+_filedrop__WEBPACK_IMPORTED_MODULE_30__["SetupFileDrop"]();
+_events__WEBPACK_IMPORTED_MODULE_18__["Emit"]('wails:loaded');
+function Init(callback) {
+callback();
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "Debug", function() { return Debug; });
+__webpack_require__.d(__webpack_exports__, "Info", function() { return Info; });
+__webpack_require__.d(__webpack_exports__, "Warning", function() { return Warning; });
+__webpack_require__.d(__webpack_exports__, "Error", function() { return Error; });
+__webpack_require__.d(__webpack_exports__, "Fatal", function() { return Fatal; });
+var _ipc__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(2);
+function sendLogMessage(level, message) {
+var payload = {
+level: level,
+message: message,
+};
+_ipc__WEBPACK_IMPORTED_MODULE_0__["SendMessage"]('log', payload);
+}
+function Debug(message) {
+sendLogMessage('debug', message);
+}
+function Info(message) {
+sendLogMessage('info', message);
+}
+function Warning(message) {
+sendLogMessage('warning', message);
+}
+function Error(message) {
+sendLogMessage('error', message);
+}
+function Fatal(message) {
+sendLogMessage('fatal', message);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "AddIPCListener", function() { return AddIPCListener; });
+__webpack_require__.d(__webpack_exports__, "EncodeBinary", function() { return EncodeBinary; });
+__webpack_require__.d(__webpack_exports__, "SendMessage", function() { return SendMessage; });
+function _extends() { _extends = Object.assign || function (target) { for (var i = 1; i < arguments.length; i++) { var source = arguments[i]; for (var key in source) { if (Object.prototype.hasOwnProperty.call(source, key)) { target[key] = source[key]; } } } return target; }; return _extends.apply(this, arguments); }
+var listeners = [];
+function AddIPCListener(callback) {
+listeners.push(callback);
+}
+function Invoke(message, frame) {
+if (window.wailsbridge) {
+window.wailsbridge.websocket.send(frame || message);
+} else {
+window.external.invoke(message);
+}
+if (listeners.length > 0) {
+for (var i = 0; i < listeners.length; i++) {
+listeners[i](message);
+}
+}
+}
+function encodeFrame(message, binary) {
+var header = new TextEncoder().encode(message);
+var size = 4 + header.length;
+for (var i = 0; i < binary.length; i++) {
+size += binary[i].byteLength;
+}
+var frame = new Uint8Array(size);
+new DataView(frame.buffer).setUint32(0, header.length);
+frame.set(header, 4);
+var offset = 4 + header.length;
+for (var j = 0; j < binary.length; j++) {
+frame.set(binary[j], offset);
+offset += binary[j].byteLength;
+}
+return frame.buffer;
+}
+function base64(data) {
+var result = '';
+for (var i = 0; i < data.length; i += 0x8000) {
+result += String.fromCharCode.apply(null, data.subarray(i, i + 0x8000));
+}
+return btoa(result);
+}
+function EncodeBinary(binary) {
+if (window.wailsbridge) {
+return {
+payload: binary.map(function (data) {
+return data.byteLength;
+}),
+frame: binary,
+};
+}
+return {
+payload: binary.map(base64),
+};
+}
+function SendMessage(type, payload, callbackID, binary) {
+if (binary && binary.payload.length > 0) {
+payload = _extends({}, payload, { binary: binary.payload });
+}
+var message = {
+type: type,
+callbackID: callbackID,
+payload: payload
+};
+var json = JSON.stringify(message);
+if (binary && binary.frame && binary.frame.length > 0) {
+Invoke(json, encodeFrame(json, binary.frame));
+return;
+}
+Invoke(json);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "OpenURL", function() { return OpenURL; });
+__webpack_require__.d(__webpack_exports__, "OpenFile", function() { return OpenFile; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function OpenURL(url) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Browser.OpenURL', url);
+}
+function OpenFile(filename) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Browser.OpenFile', filename);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "SetMaxPayloadSize", function() { return SetMaxPayloadSize; });
+__webpack_require__.d(__webpack_exports__, "SetRetry", function() { return SetRetry; });
+__webpack_require__.d(__webpack_exports__, "Call", function() { return Call; });
+__webpack_require__.d(__webpack_exports__, "Callback", function() { return Callback; });
+__webpack_require__.d(__webpack_exports__, "CallbackBinary", function() { return CallbackBinary; });
+__webpack_require__.d(__webpack_exports__, "CallbackFrame", function() { return CallbackFrame; });
+__webpack_require__.d(__webpack_exports__, "SystemCall", function() { return SystemCall; });
+var _log__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(1);
+var _ipc__WEBPACK_IMPORTED_MODULE_1__ = __webpack_require__(2);
+var callbacks = {};
+var maxPayloadSize = 0;
+var chunks = {};
+var retryOptions = {
+retries: 0,
+delay: 250,
+maxDelay: 5000,
+};
+function cryptoRandom() {
+var array = new Uint32Array(1);
+return window.crypto.getRandomValues(array)[0];
+}
+function basicRandom() {
+return Math.random() * 9007199254740991;
+}
+var randomFunc;
+if (window.crypto) {
+randomFunc = cryptoRandom;
+} else {
+randomFunc = basicRandom;
+}
+function SetMaxPayloadSize(size) {
+maxPayloadSize = size;
+}
+function SetRetry(options) {
+retryOptions = retryDefaults(options);
+}
+function retryDefaults(options) {
+options = options || {};
+return {
+retries: options.retries > 0 ? options.retries : 0,
+delay: options.delay >= 0 ? options.delay : 250,
+maxDelay: options.maxDelay >= 0 ? options.maxDelay : 5000,
+};
+}
+function sendChunks(call) {
+while (call.data && maxPayloadSize > 0 && call.data.length > maxPayloadSize) {
+var size = maxPayloadSize;
+var code = call.data.charCodeAt(size - 1);
+if (size > 1 && code >= 0xD800 && code <= 0xDBFF) {
+size--;
+}
+_ipc__WEBPACK_IMPORTED_MODULE_1__["SendMessage"]('chunk', { data: call.data.slice(0, size) }, call.callbackID);
+call.data = call.data.slice(size);
+}
+}
+function binaryArgs(args, binary) {
+if (!Array.isArray(args)) {
+return args;
+}
+return args.map(function (arg) {
+if (arg instanceof ArrayBuffer) {
+arg = new Uint8Array(arg);
+} else if (ArrayBuffer.isView(arg)) {
+arg = new Uint8Array(arg.buffer, arg.byteOffset, arg.byteLength);
+} else {
+return arg;
+}
+binary.push(arg);
+return { $binary: binary.length - 1 };
+});
+}
+function Call(bindingName, data, timeout, retry, signal, onData) {
+if (timeout == null || timeout == undefined) {
+timeout = 0;
+}
+retry = retry ? retryDefaults(retry) : retryOptions;
+return new Promise(function (resolve, reject) {
+var callbackID;
+do {
+callbackID = bindingName + '-' + randomFunc();
+} while (callbacks[callbackID]);
+if (timeout > 0) {
+var timeoutHandle = setTimeout(function () {
+cancelCall(callbackID, Error('Call to ' + bindingName + ' timed out. Request ID: ' + callbackID));
+}, timeout);
+}
+callbacks[callbackID] = {
+timeoutHandle: timeoutHandle,
+reject: reject,
+resolve: resolve,
+onData: onData
+};
+if (signal) {
+var cancelled = function () {
+return signal.reason || Error('Call to ' + bindingName + ' was cancelled. Request ID: ' + callbackID);
+};
+if (signal.aborted) {
+clearTimeout(timeoutHandle);
+delete callbacks[callbackID];
+reject(cancelled());
+return;
+}
+var abort = function () {
+cancelCall(callbackID, cancelled());
+};
+signal.addEventListener('abort', abort);
+callbacks[callbackID].removeAbort = function () {
+signal.removeEventListener('abort', abort);
+};
+}
+var bytes = [];
+var json = JSON.stringify(binaryArgs(data, bytes));
+var binary = bytes.length > 0 ? _ipc__WEBPACK_IMPORTED_MODULE_1__["EncodeBinary"](bytes) : null;
+var unsent = { data: json, callbackID: callbackID };
+var attempt = 0;
+function send() {
+try {
+sendChunks(unsent);
+var payload = {
+bindingName: bindingName,
+data: unsent.data,
+};
+if (onData) {
+payload.stream = true;
+}
+_ipc__WEBPACK_IMPORTED_MODULE_1__["SendMessage"]('call', payload, callbackID, binary);
+} catch (e) {
+if (attempt >= retry.retries) {
+console.error(e);
+if (retry.retries > 0) {
+clearTimeout(timeoutHandle);
+removeAbort(callbacks[callbackID]);
+delete callbacks[callbackID];
+reject(Error('Call to ' + bindingName + ' failed after ' + attempt + ' retries: ' + e.message));
+}
+return;
+}
+var delay = Math.min(retry.delay * Math.pow(2, attempt), retry.maxDelay);
+attempt++;
+callbacks[callbackID].retryHandle = setTimeout(send, delay);
+}
+}
+send();
+});
+}
+function removeAbort(callbackData) {
+if (callbackData && callbackData.removeAbort) {
+callbackData.removeAbort();
+}
+}
+function cancelCall(callbackID, error) {
+var callbackData = callbacks[callbackID];
+if (!callbackData || callbackData.cancelled) {
+return;
+}
+clearTimeout(callbackData.timeoutHandle);
+clearTimeout(callbackData.retryHandle);
+removeAbort(callbackData);
+callbacks[callbackID] = { cancelled: true };
+callbackData.reject(error);
+try {
+_ipc__WEBPACK_IMPORTED_MODULE_1__["SendMessage"]('cancel', {}, callbackID);
+} catch (e) {
+delete callbacks[callbackID];
+}
+}
+function parseMessage(incomingMessage) {
+try {
+return JSON.parse(incomingMessage);
+} catch (e) {
+var error = "Invalid JSON passed to callback: " + (e.message) + ". Message: " + (incomingMessage);
+_log__WEBPACK_IMPORTED_MODULE_0__["Debug"](error);
+throw new Error(error);
+}
+}
+function Callback(incomingMessage) {
+incomingMessage = decodeURIComponent(incomingMessage.replace(/\s+/g, '').replace(/[0-9a-f]{2}/g, '%$&'));
+var message = parseMessage(incomingMessage);
+var callbackID = message.callbackid;
+if (message.chunk !== undefined) {
+chunks[callbackID] = (chunks[callbackID] || '') + message.chunk;
+if (message.more) {
+return;
+}
+message = parseMessage(chunks[callbackID]);
+delete chunks[callbackID];
+}
+resolveCallback(message);
+}
+function CallbackBinary(header, data) {
+var message = parseMessage(decodeURIComponent(header.replace(/[0-9a-f]{2}/g, '%$&')));
+var bytes = atob(data);
+message.data = new Uint8Array(bytes.length);
+for (var i = 0; i < bytes.length; i++) {
+message.data[i] = bytes.charCodeAt(i);
+}
+resolveCallback(message);
+}
+function CallbackFrame(frame) {
+var size = new DataView(frame).getUint32(0);
+var message = parseMessage(new TextDecoder().decode(new Uint8Array(frame, 4, size)));
+message.data = new Uint8Array(frame, 4 + size);
+resolveCallback(message);
+}
+function resolveCallback(message) {
+var callbackID = message.callbackid;
+var callbackData = callbacks[callbackID];
+if (message.stream) {
+if (callbackData && callbackData.onData) {
+callbackData.onData(message.data);
+}
+return;
+}
+if (!callbackData) {
+var error = "Callback '" + (callbackID) + "' not registed!!!";
+console.error(error);
+throw new Error(error);
+}
+clearTimeout(callbackData.timeoutHandle);
+removeAbort(callbackData);
+delete callbacks[callbackID];
+if (callbackData.cancelled) {
+return;
+}
+if (message.error) {
+callbackData.reject(message.error);
+} else {
+callbackData.resolve(message.data);
+}
+}
+function SystemCall(method, data) {
+return Call('.wails.' + method, data);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "SystemReport", function() { return SystemReport; });
+__webpack_require__.d(__webpack_exports__, "ReportBug", function() { return ReportBug; });
+__webpack_require__.d(__webpack_exports__, "EmailSupport", function() { return EmailSupport; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function SystemReport() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Support.SystemReport');
+}
+function ReportBug(title, description) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Support.ReportBug', { title: title, description: description });
+}
+function EmailSupport(subject, description) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Support.EmailSupport', { title: subject, description: description });
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "Status", function() { return Status; });
+__webpack_require__.d(__webpack_exports__, "Request", function() { return Request; });
+__webpack_require__.d(__webpack_exports__, "OpenSettings", function() { return OpenSettings; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function Status(permission) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Permissions.Status', permission);
+}
+function Request(permission) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Permissions.Request', permission);
+}
+function OpenSettings(permission) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Permissions.OpenSettings', permission);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "ColorPicker", function() { return ColorPicker; });
+__webpack_require__.d(__webpack_exports__, "FontPicker", function() { return FontPicker; });
+__webpack_require__.d(__webpack_exports__, "OpenFile", function() { return OpenFile; });
+__webpack_require__.d(__webpack_exports__, "OpenDirectory", function() { return OpenDirectory; });
+__webpack_require__.d(__webpack_exports__, "About", function() { return About; });
+__webpack_require__.d(__webpack_exports__, "Message", function() { return Message; });
+__webpack_require__.d(__webpack_exports__, "SaveFile", function() { return SaveFile; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function ColorPicker(initial) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Dialog.ColorPicker', initial || '');
+}
+function FontPicker(initial) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Dialog.FontPicker', initial || null);
+}
+function OpenFile(options) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Dialog.OpenFile', options || {});
+}
+function OpenDirectory(options) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Dialog.OpenDirectory', options || {});
+}
+function About() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Dialog.About');
+}
+function Message(type, title, message, buttons) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Dialog.Message', { type: type, title: title, message: message, buttons: buttons || [] });
+}
+function SaveFile(options) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Dialog.SaveFile', options || {});
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "OnFileDrop", function() { return OnFileDrop; });
+__webpack_require__.d(__webpack_exports__, "ShowEmojiPicker", function() { return ShowEmojiPicker; });
+__webpack_require__.d(__webpack_exports__, "Fullscreen", function() { return Fullscreen; });
+__webpack_require__.d(__webpack_exports__, "UnFullscreen", function() { return UnFullscreen; });
+__webpack_require__.d(__webpack_exports__, "Minimise", function() { return Minimise; });
+__webpack_require__.d(__webpack_exports__, "Maximise", function() { return Maximise; });
+__webpack_require__.d(__webpack_exports__, "Restore", function() { return Restore; });
+__webpack_require__.d(__webpack_exports__, "Show", function() { return Show; });
+__webpack_require__.d(__webpack_exports__, "Hide", function() { return Hide; });
+__webpack_require__.d(__webpack_exports__, "SetMinSize", function() { return SetMinSize; });
+__webpack_require__.d(__webpack_exports__, "SetMaxSize", function() { return SetMaxSize; });
+__webpack_require__.d(__webpack_exports__, "SetAspectRatio", function() { return SetAspectRatio; });
+__webpack_require__.d(__webpack_exports__, "SetSystemGestures", function() { return SetSystemGestures; });
+__webpack_require__.d(__webpack_exports__, "StartDrag", function() { return StartDrag; });
+__webpack_require__.d(__webpack_exports__, "RequestUserAttention", function() { return RequestUserAttention; });
+__webpack_require__.d(__webpack_exports__, "SetOpacity", function() { return SetOpacity; });
+__webpack_require__.d(__webpack_exports__, "SetProgress", function() { return SetProgress; });
+__webpack_require__.d(__webpack_exports__, "SetBadge", function() { return SetBadge; });
+__webpack_require__.d(__webpack_exports__, "SetMaterial", function() { return SetMaterial; });
+__webpack_require__.d(__webpack_exports__, "Materials", function() { return Materials; });
+__webpack_require__.d(__webpack_exports__, "Displays", function() { return Displays; });
+__webpack_require__.d(__webpack_exports__, "SetTitle", function() { return SetTitle; });
+__webpack_require__.d(__webpack_exports__, "SetIcon", function() { return SetIcon; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+var _events__WEBPACK_IMPORTED_MODULE_1__ = __webpack_require__(9);
+function OnFileDrop(callback) {
+_events__WEBPACK_IMPORTED_MODULE_1__["On"]('wails:file-drop', callback);
+}
+function ShowEmojiPicker() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.ShowEmojiPicker');
+}
+function Fullscreen() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.Fullscreen');
+}
+function UnFullscreen() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.UnFullscreen');
+}
+function Minimise() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.Minimise');
+}
+function Maximise() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.Maximise');
+}
+function Restore() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.Restore');
+}
+function Show() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.Show');
+}
+function Hide() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.Hide');
+}
+function SetMinSize(width, height) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetMinSize', { width: width, height: height });
+}
+function SetMaxSize(width, height) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetMaxSize', { width: width, height: height });
+}
+function SetAspectRatio(width, height) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetAspectRatio', { width: width, height: height });
+}
+function SetSystemGestures(enabled) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetSystemGestures', !!enabled);
+}
+function StartDrag(files) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.StartDrag', [].concat(files));
+}
+function RequestUserAttention(critical) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.RequestUserAttention', !!critical);
+}
+function SetOpacity(opacity) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetOpacity', opacity);
+}
+function SetProgress(progress) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetProgress', progress);
+}
+function SetBadge(badge) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetBadge', String(badge));
+}
+function SetMaterial(material) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetMaterial', material);
+}
+function Materials() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.Materials');
+}
+function Displays() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.Displays');
+}
+function SetTitle(title) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetTitle', title);
+}
+function SetIcon(icon) {
+if (icon instanceof ArrayBuffer) {
+icon = new Uint8Array(icon);
+}
+if (icon instanceof Uint8Array) {
+var binary = '';
+for (var i = 0; i < icon.length; i++) {
+binary += String.fromCharCode(icon[i]);
+}
+icon = window.btoa(binary);
+}
+icon = (icon || '').replace(/^data:[^,]*;base64,/, '');
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.SetIcon', icon);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "OnMultiple", function() { return OnMultiple; });
+__webpack_require__.d(__webpack_exports__, "On", function() { return On; });
+__webpack_require__.d(__webpack_exports__, "Once", function() { return Once; });
+__webpack_require__.d(__webpack_exports__, "Notify", function() { return Notify; });
+__webpack_require__.d(__webpack_exports__, "Emit", function() { return Emit; });
+__webpack_require__.d(__webpack_exports__, "Heartbeat", function() { return Heartbeat; });
+__webpack_require__.d(__webpack_exports__, "Acknowledge", function() { return Acknowledge; });
+var _log__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(1);
+var _ipc__WEBPACK_IMPORTED_MODULE_1__ = __webpack_require__(2);
+function Listener(callback, maxCallbacks) {
+maxCallbacks = maxCallbacks || -1;
+this.Callback = function (data) {
+callback.apply(null, data);
+if (maxCallbacks === -1) {
+return false;
+}
+maxCallbacks -= 1;
+return maxCallbacks === 0;
+};
+}
+var eventListeners = {};
+function OnMultiple(eventName, callback, maxCallbacks) {
+eventListeners[eventName] = eventListeners[eventName] || [];
+var thisListener = new Listener(callback, maxCallbacks);
+eventListeners[eventName].push(thisListener);
+}
+function On(eventName, callback) {
+OnMultiple(eventName, callback);
+}
+function Once(eventName, callback) {
+OnMultiple(eventName, callback, 1);
+}
+function Notify(eventName, data) {
+if (eventListeners[eventName]) {
+var newEventListenerList = eventListeners[eventName].slice();
+for (var count = 0; count < eventListeners[eventName].length; count += 1) {
+var listener = eventListeners[eventName][count];
+var parsedData = [];
+if (data) {
+try {
+parsedData = JSON.parse(data);
+} catch (e) {
+_log__WEBPACK_IMPORTED_MODULE_0__["Error"]('Invalid JSON data sent to notify. Event name = ' + eventName);
+}
+}
+var destroy = listener.Callback(parsedData);
+if (destroy) {
+newEventListenerList.splice(count, 1);
+}
+}
+eventListeners[eventName] = newEventListenerList;
+}
+}
+function Emit(eventName) {
+var data = JSON.stringify([].slice.apply(arguments).slice(1));
+var payload = {
+name: eventName,
+data: data,
+};
+_ipc__WEBPACK_IMPORTED_MODULE_1__["SendMessage"]('event', payload);
+}
+var heartbeatCallbacks = {};
+function Heartbeat(eventName, timeInMilliseconds, callback) {
+var interval = null;
+function dynamicCallback() {
+clearInterval(interval);
+callback();
+}
+heartbeatCallbacks[eventName] = dynamicCallback;
+interval = setInterval(function () {
+Emit(eventName);
+}, timeInMilliseconds);
+}
+function Acknowledge(eventName) {
+if (heartbeatCallbacks[eventName]) {
+heartbeatCallbacks[eventName]();
+} else {
+throw new _log__WEBPACK_IMPORTED_MODULE_0__["Error"]("Cannot acknowledge unknown heartbeat '" + (eventName) + "'");
+}
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "List", function() { return List; });
+__webpack_require__.d(__webpack_exports__, "Families", function() { return Families; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function List() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Fonts.List');
+}
+function Families() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Fonts.Families');
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "Locale", function() { return Locale; });
+__webpack_require__.d(__webpack_exports__, "MachineID", function() { return MachineID; });
+__webpack_require__.d(__webpack_exports__, "NewID", function() { return NewID; });
+__webpack_require__.d(__webpack_exports__, "Stats", function() { return Stats; });
+__webpack_require__.d(__webpack_exports__, "WatchStats", function() { return WatchStats; });
+__webpack_require__.d(__webpack_exports__, "StopWatchingStats", function() { return StopWatchingStats; });
+__webpack_require__.d(__webpack_exports__, "Flags", function() { return Flags; });
+__webpack_require__.d(__webpack_exports__, "Installation", function() { return Installation; });
+__webpack_require__.d(__webpack_exports__, "MoveToApplications", function() { return MoveToApplications; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function Locale() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('System.Locale');
+}
+function MachineID() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('System.MachineID');
+}
+function NewID() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('System.NewID');
+}
+function Stats() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('System.Stats');
+}
+function WatchStats(interval) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('System.WatchStats', interval || 1000);
+}
+function StopWatchingStats() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('System.StopWatchingStats');
+}
+function Flags() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('System.Flags');
+}
+function Installation() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('System.Installation');
+}
+function MoveToApplications() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('System.MoveToApplications');
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "CanMakePayments", function() { return CanMakePayments; });
+__webpack_require__.d(__webpack_exports__, "Products", function() { return Products; });
+__webpack_require__.d(__webpack_exports__, "Purchase", function() { return Purchase; });
+__webpack_require__.d(__webpack_exports__, "Restore", function() { return Restore; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function CanMakePayments() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Purchases.CanMakePayments');
+}
+function Products(ids) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Purchases.Products', ids);
+}
+function Purchase(productId) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Purchases.Purchase', productId);
+}
+function Restore() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Purchases.Restore');
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "ShowContextMenu", function() { return ShowContextMenu; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function ShowContextMenu(menu, x, y) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Menu.ShowContextMenu', { menu: menu, x: Math.round(x), y: Math.round(y) });
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "OnShare", function() { return OnShare; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+var _events__WEBPACK_IMPORTED_MODULE_1__ = __webpack_require__(9);
+function OnShare(callback) {
+_events__WEBPACK_IMPORTED_MODULE_1__["On"]('wails:share', callback);
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Share.Pending').then(function (pending) {
+for (var i = 0; i < pending.length; i++) {
+callback(pending[i]);
+}
+});
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "Status", function() { return Status; });
+__webpack_require__.d(__webpack_exports__, "Request", function() { return Request; });
+__webpack_require__.d(__webpack_exports__, "Events", function() { return Events; });
+__webpack_require__.d(__webpack_exports__, "SaveEvent", function() { return SaveEvent; });
+__webpack_require__.d(__webpack_exports__, "DeleteEvent", function() { return DeleteEvent; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function Status() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Calendar.Status');
+}
+function Request() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Calendar.Request');
+}
+function Events(start, end) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Calendar.Events', { start: start, end: end });
+}
+function SaveEvent(event) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Calendar.SaveEvent', event);
+}
+function DeleteEvent(id) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Calendar.DeleteEvent', id);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "Status", function() { return Status; });
+__webpack_require__.d(__webpack_exports__, "Request", function() { return Request; });
+__webpack_require__.d(__webpack_exports__, "List", function() { return List; });
+__webpack_require__.d(__webpack_exports__, "Save", function() { return Save; });
+__webpack_require__.d(__webpack_exports__, "Delete", function() { return Delete; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function Status() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Reminders.Status');
+}
+function Request() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Reminders.Request');
+}
+function List(includeCompleted) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Reminders.List', !!includeCompleted);
+}
+function Save(reminder) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Reminders.Save', reminder);
+}
+function Delete(id) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Reminders.Delete', id);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "Status", function() { return Status; });
+__webpack_require__.d(__webpack_exports__, "Watch", function() { return Watch; });
+__webpack_require__.d(__webpack_exports__, "StopWatching", function() { return StopWatching; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function Status() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Power.Status');
+}
+function Watch() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Power.Watch');
+}
+function StopWatching() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Power.StopWatching');
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "Beep", function() { return Beep; });
+__webpack_require__.d(__webpack_exports__, "Haptic", function() { return Haptic; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function Beep(kind) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Feedback.Beep', kind || 'default');
+}
+function Haptic(pattern) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Feedback.Haptic', pattern || 'generic');
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "Status", function() { return Status; });
+__webpack_require__.d(__webpack_exports__, "Watch", function() { return Watch; });
+__webpack_require__.d(__webpack_exports__, "StopWatching", function() { return StopWatching; });
+__webpack_require__.d(__webpack_exports__, "ShowKeyboard", function() { return ShowKeyboard; });
+__webpack_require__.d(__webpack_exports__, "SetupTouchKeyboard", function() { return SetupTouchKeyboard; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+var textTypes = ['', 'text', 'search', 'email', 'url', 'tel', 'password', 'number', 'date', 'time', 'datetime-local', 'month', 'week'];
+var lastTouch = 0;
+function Status() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Touch.Status');
+}
+function Watch() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Touch.Watch');
+}
+function StopWatching() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Touch.StopWatching');
+}
+function ShowKeyboard() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Touch.ShowKeyboard');
+}
+function SetupTouchKeyboard() {
+var touched = function () {
+lastTouch = Date.now();
+};
+document.addEventListener('touchstart', touched, true);
+document.addEventListener('pointerdown', function (event) {
+if (event.pointerType === 'touch') {
+touched();
+}
+}, true);
+document.addEventListener('focusin', function (event) {
+if (Date.now() - lastTouch < 1000 && isTextInput(event.target)) {
+ShowKeyboard().catch(function () {});
+}
+});
+}
+function isTextInput(element) {
+if (!element || element.disabled || element.readOnly) {
+return false;
+}
+if (element.isContentEditable || element.tagName === 'TEXTAREA') {
+return true;
+}
+return element.tagName === 'INPUT' && textTypes.indexOf((element.getAttribute('type') || '').toLowerCase()) !== -1;
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "GetText", function() { return GetText; });
+__webpack_require__.d(__webpack_exports__, "SetText", function() { return SetText; });
+__webpack_require__.d(__webpack_exports__, "GetImage", function() { return GetImage; });
+__webpack_require__.d(__webpack_exports__, "SetImage", function() { return SetImage; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+function GetText() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Clipboard.GetText');
+}
+function SetText(text) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Clipboard.SetText', String(text));
+}
+function GetImage() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Clipboard.GetImage').then(function (image) {
+return image ? 'data:image/png;base64,' + image : null;
+});
+}
+function SetImage(image) {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Clipboard.SetImage', String(image).replace(/^data:image\/png;base64,/, ''));
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "List", function() { return List; });
+__webpack_require__.d(__webpack_exports__, "Watch", function() { return Watch; });
+__webpack_require__.d(__webpack_exports__, "StopWatching", function() { return StopWatching; });
+__webpack_require__.d(__webpack_exports__, "SetupGamepads", function() { return SetupGamepads; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+var _events__WEBPACK_IMPORTED_MODULE_1__ = __webpack_require__(9);
+var gamepads = [];
+var watching = false;
+function List() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Gamepads.List');
+}
+function Watch() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Gamepads.Watch');
+}
+function StopWatching() {
+return _calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Gamepads.StopWatching');
+}
+function SetupGamepads() {
+if (typeof navigator.getGamepads === 'function') {
+return;
+}
+navigator.getGamepads = function () {
+watch();
+return gamepads.slice();
+};
+var addEventListener = window.addEventListener;
+window.addEventListener = function (type) {
+if (type === 'gamepadconnected') {
+watch();
+}
+return addEventListener.apply(this, arguments);
+};
+_events__WEBPACK_IMPORTED_MODULE_1__["On"]('wails:gamepad:connected', function (gamepad) {
+gamepads[gamepad.index] = toGamepad(gamepad, true);
+dispatch('gamepadconnected', gamepads[gamepad.index]);
+});
+_events__WEBPACK_IMPORTED_MODULE_1__["On"]('wails:gamepad:changed', function (gamepad) {
+gamepads[gamepad.index] = toGamepad(gamepad, true);
+});
+_events__WEBPACK_IMPORTED_MODULE_1__["On"]('wails:gamepad:disconnected', function (gamepad) {
+gamepads[gamepad.index] = null;
+dispatch('gamepaddisconnected', toGamepad(gamepad, false));
+});
+}
+function watch() {
+if (!watching) {
+watching = true;
+Watch().catch(function () {
+watching = false;
+});
+}
+}
+function toGamepad(gamepad, connected) {
+return {
+id: gamepad.id,
+index: gamepad.index,
+connected: connected,
+mapping: gamepad.mapping,
+timestamp: gamepad.timestamp,
+buttons: gamepad.buttons.map(function (button) {
+return { pressed: button.pressed, touched: button.pressed || button.value > 0, value: button.value };
+}),
+axes: gamepad.axes,
+};
+}
+function dispatch(type, gamepad) {
+var event = document.createEvent('Event');
+event.initEvent(type, false, false);
+event.gamepad = gamepad;
+window.dispatchEvent(event);
+var handler = window['on' + type];
+if (typeof handler === 'function') {
+handler.call(window, event);
+}
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "NewBinding", function() { return NewBinding; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+window.backend = {};
+function isAbortSignal(value) {
+return value != null && typeof value === 'object' &&
+typeof value.aborted === 'boolean' && typeof value.addEventListener === 'function';
+}
+function isValidIdentifier(name) {
+try {
+new Function('var ' + name);
+return true;
+} catch (e) {
+return false;
+}
+}
+function NewBinding(bindingName) {
+var bindingSections = [].concat(bindingName.split('.').splice(1));
+var pathToBinding = window.backend;
+if (bindingSections.length > 1) {
+for (var index = 0; index < bindingSections.length-1; index += 1) {
+var name = bindingSections[index];
+if (!isValidIdentifier(name)) {
+return new Error("" + (name) + " is not a valid javascript identifier.");
+}
+if (!pathToBinding[name]) {
+pathToBinding[name] = {};
+}
+pathToBinding = pathToBinding[name];
+}
+}
+var name = bindingSections.pop();
+if (!isValidIdentifier(name)) {
+return new Error("" + (name) + " is not a valid javascript identifier.");
+}
+pathToBinding[name] = function () {
+var timeout = 0;
+var retry = null;
+function dynamic() {
+var args = [].slice.call(arguments);
+var signal = null;
+var onData = null;
+if (args.length > 0 && isAbortSignal(args[args.length - 1])) {
+signal = args.pop();
+}
+if (args.length > 0 && typeof args[args.length - 1] === 'function') {
+onData = args.pop();
+}
+return _calls__WEBPACK_IMPORTED_MODULE_0__["Call"](bindingName, args, timeout, retry, signal, onData);
+}
+dynamic.setTimeout = function (newTimeout) {
+timeout = newTimeout;
+};
+dynamic.getTimeout = function () {
+return timeout;
+};
+dynamic.setRetry = function (newRetry) {
+retry = newRetry;
+};
+dynamic.getRetry = function () {
+return retry;
+};
+return dynamic;
+}();
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "AddScript", function() { return AddScript; });
+__webpack_require__.d(__webpack_exports__, "InjectFirebug", function() { return InjectFirebug; });
+__webpack_require__.d(__webpack_exports__, "InjectCSS", function() { return InjectCSS; });
+var _events__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(9);
+function AddScript(js, callbackID) {
+var script = document.createElement('script');
+script.text = js;
+document.body.appendChild(script);
+if (callbackID) {
+_events__WEBPACK_IMPORTED_MODULE_0__["Emit"](callbackID);
+}
+}
+function InjectFirebug() {
+var html = document.getElementsByTagName('html')[0];
+html.setAttribute('debug', 'true');
+var firebugURL = 'https://wails.app/assets/js/firebug-lite.js#startOpened=true,disableWhenFirebugActive=false';
+var script = document.createElement('script');
+script.src = firebugURL;
+script.type = 'application/javascript';
+document.head.appendChild(script);
+window.wails.Log.Info('Injected firebug');
+}
+function InjectCSS(css) {
+var elem = document.createElement('style');
+elem.setAttribute('type', 'text/css');
+if (elem.styleSheet) {
+elem.styleSheet.cssText = css;
+} else {
+elem.appendChild(document.createTextNode(css));
+}
+var head = document.head || document.getElementsByTagName('head')[0];
+head.appendChild(elem);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "TrackOverlay", function() { return TrackOverlay; });
+__webpack_require__.d(__webpack_exports__, "UntrackOverlay", function() { return UntrackOverlay; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+var overlays = {};
+var tracking = false;
+function TrackOverlay(id, selector) {
+overlays[id] = { selector: selector, placement: null };
+if (!tracking) {
+tracking = true;
+window.requestAnimationFrame(update);
+}
+}
+function UntrackOverlay(id) {
+delete overlays[id];
+}
+function placement(selector) {
+var element = document.querySelector(selector);
+if (!element) {
+return { x: 0, y: 0, width: 0, height: 0, visible: false };
+}
+var rect = element.getBoundingClientRect();
+return {
+x: Math.round(rect.left),
+y: Math.round(rect.top),
+width: Math.round(rect.width),
+height: Math.round(rect.height),
+visible: rect.width > 0 && rect.height > 0 && window.getComputedStyle(element).visibility !== 'hidden',
+};
+}
+function update() {
+var ids = Object.keys(overlays);
+if (ids.length === 0) {
+tracking = false;
+return;
+}
+ids.forEach(function (id) {
+var overlay = overlays[id];
+var current = placement(overlay.selector);
+var key = JSON.stringify(current);
+if (key !== overlay.placement) {
+overlay.placement = key;
+current.id = id;
+_calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.PlaceOverlay', current);
+}
+});
+window.requestAnimationFrame(update);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "ObservePerformance", function() { return ObservePerformance; });
+var _events__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(9);
+var pending = [];
+var observing = false;
+function queueEntries(entries) {
+for (var i = 0; i < entries.length; i++) {
+var entry = entries[i];
+pending.push({
+name: entry.name,
+entryType: entry.entryType,
+startTime: entry.startTime,
+duration: entry.duration,
+});
+}
+}
+function sendEntries() {
+if (pending.length === 0) {
+return;
+}
+_events__WEBPACK_IMPORTED_MODULE_0__["Emit"]('wails:perf:entries', pending);
+pending = [];
+}
+function ObservePerformance() {
+var performance = window.performance;
+if (observing || !performance) {
+return;
+}
+observing = true;
+if (performance.getEntriesByType) {
+queueEntries(performance.getEntriesByType('mark'));
+queueEntries(performance.getEntriesByType('measure'));
+var navigation = performance.getEntriesByType('navigation');
+if (navigation.length > 0) {
+queueEntries(navigation);
+} else if (performance.timing) {
+var timing = performance.timing;
+queueEntries([{
+name: 'document',
+entryType: 'navigation',
+startTime: 0,
+duration: Math.max(0, timing.loadEventEnd - timing.navigationStart),
+}]);
+}
+}
+if (window.PerformanceObserver) {
+['mark', 'measure', 'longtask'].forEach(function (entryType) {
+try {
+new PerformanceObserver(function (list) {
+queueEntries(list.getEntries());
+}).observe({ entryTypes: [entryType] });
+} catch (e) {
+}
+});
+}
+sendEntries();
+setInterval(sendEntries, 1000);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "ToggleDevOverlay", function() { return ToggleDevOverlay; });
+__webpack_require__.d(__webpack_exports__, "EnableDevOverlay", function() { return EnableDevOverlay; });
+var _events__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(9);
+var _utils__WEBPACK_IMPORTED_MODULE_1__ = __webpack_require__(23);
+var maxTraces = 1000;
+var traces = [];
+var lastSeq = 0;
+var enabled = false;
+var panel = null;
+var list = null;
+var filter = null;
+var kind = null;
+var errorsOnly = null;
+var pauseButton = null;
+var paused = false;
+var poller = null;
+var css = "\n#wails-devoverlay { position: fixed; left: 0; right: 0; bottom: 0; height: 40%; z-index: 2147483647; display: flex; flex-direction: column; background: rgba(24, 24, 24, 0.96); color: #ddd; font: 12px/1.5 Menlo, Consolas, monospace; border-top: 1px solid #555; }\n#wails-devoverlay .toolbar { display: flex; align-items: center; padding: 4px 8px; border-bottom: 1px solid #444; }\n#wails-devoverlay .toolbar > * { margin-right: 8px; font: inherit; }\n#wails-devoverlay .toolbar input[type=text] { flex: 1; background: #111; color: #ddd; border: 1px solid #555; padding: 1px 4px; }\n#wails-devoverlay .list { flex: 1; overflow: auto; }\n#wails-devoverlay table { width: 100%; border-collapse: collapse; }\n#wails-devoverlay td { padding: 0 8px; white-space: nowrap; }\n#wails-devoverlay td.name { width: 100%; white-space: normal; word-break: break-all; }\n#wails-devoverlay td.number { text-align: right; }\n#wails-devoverlay tr.call { color: #9cdcfe; }\n#wails-devoverlay tr.event { color: #c5e1a5; }\n#wails-devoverlay tr.error { color: #f48771; }\n";
+function pad(value, length) {
+value = String(value);
+while (value.length < length) {
+value = '0' + value;
+}
+return value;
+}
+function formatTime(time) {
+var date = new Date(time);
+return pad(date.getHours(), 2) + ':' + pad(date.getMinutes(), 2) + ':' +
+pad(date.getSeconds(), 2) + '.' + pad(date.getMilliseconds(), 3);
+}
+function formatSize(size) {
+if (size >= 1024 * 1024) {
+return (size / 1024 / 1024).toFixed(1) + ' MB';
+}
+if (size >= 1024) {
+return (size / 1024).toFixed(1) + ' KB';
+}
+return size + ' B';
+}
+function cell(row, text, className) {
+var td = document.createElement('td');
+td.textContent = text;
+if (className) {
+td.className = className;
+}
+row.appendChild(td);
+}
+function matches(trace) {
+if (kind.value && trace.kind !== kind.value) {
+return false;
+}
+if (errorsOnly.checked && !trace.error) {
+return false;
+}
+var text = filter.value.toLowerCase();
+return !text || trace.name.toLowerCase().indexOf(text) !== -1;
+}
+function render() {
+var atBottom = list.scrollTop + list.clientHeight >= list.scrollHeight - 4;
+var table = document.createElement('table');
+var body = document.createElement('tbody');
+for (var i = 0; i < traces.length; i++) {
+var trace = traces[i];
+if (!matches(trace)) {
+continue;
+}
+var row = document.createElement('tr');
+row.className = trace.error ? 'error' : trace.kind;
+row.title = trace.error || '';
+cell(row, formatTime(trace.time));
+if (trace.kind === 'call') {
+cell(row, 'call');
+cell(row, trace.name, 'name');
+cell(row, trace.duration.toFixed(1) + ' ms', 'number');
+cell(row, formatSize(trace.size) + ' → ' + formatSize(trace.resultSize), 'number');
+} else {
+cell(row, trace.source === 'frontend' ? 'event →' : 'event ←');
+cell(row, trace.name, 'name');
+cell(row, '', 'number');
+cell(row, formatSize(trace.size), 'number');
+}
+body.appendChild(row);
+}
+table.appendChild(body);
+list.innerHTML = '';
+list.appendChild(table);
+if (atBottom) {
+list.scrollTop = list.scrollHeight;
+}
+}
+function addTraces(newTraces) {
+if (!newTraces || newTraces.length === 0) {
+return;
+}
+traces = traces.concat(newTraces);
+if (traces.length > maxTraces) {
+traces = traces.slice(traces.length - maxTraces);
+}
+lastSeq = newTraces[newTraces.length - 1].seq;
+if (panel && !paused) {
+render();
+}
+}
+function fetchTraces() {
+_events__WEBPACK_IMPORTED_MODULE_0__["Emit"]('wails:devoverlay:fetch', lastSeq);
+}
+function control(tag, properties) {
+var element = document.createElement(tag);
+for (var name in properties) {
+element[name] = properties[name];
+}
+return element;
+}
+function createPanel() {
+_utils__WEBPACK_IMPORTED_MODULE_1__["InjectCSS"](css);
+panel = control('div', { id: 'wails-devoverlay' });
+var toolbar = control('div', { className: 'toolbar' });
+toolbar.appendChild(control('strong', { textContent: 'Calls & Events' }));
+filter = control('input', { type: 'text', placeholder: 'Filter by name' });
+filter.addEventListener('input', render);
+toolbar.appendChild(filter);
+kind = control('select', {});
+[['', 'All'], ['call', 'Calls'], ['event', 'Events']].forEach(function (option) {
+kind.appendChild(control('option', { value: option[0], textContent: option[1] }));
+});
+kind.addEventListener('change', render);
+toolbar.appendChild(kind);
+var errorsLabel = control('label', {});
+errorsOnly = control('input', { type: 'checkbox' });
+errorsOnly.addEventListener('change', render);
+errorsLabel.appendChild(errorsOnly);
+errorsLabel.appendChild(document.createTextNode(' Errors'));
+toolbar.appendChild(errorsLabel);
+pauseButton = control('button', { textContent: 'Pause' });
+pauseButton.addEventListener('click', function () {
+paused = !paused;
+pauseButton.textContent = paused ? 'Resume' : 'Pause';
+if (!paused) {
+render();
+}
+});
+toolbar.appendChild(pauseButton);
+var clearButton = control('button', { textContent: 'Clear' });
+clearButton.addEventListener('click', function () {
+traces = [];
+render();
+});
+toolbar.appendChild(clearButton);
+var closeButton = control('button', { textContent: '×', title: 'Close (Ctrl+Shift+D)' });
+closeButton.addEventListener('click', ToggleDevOverlay);
+toolbar.appendChild(closeButton);
+panel.appendChild(toolbar);
+list = control('div', { className: 'list' });
+panel.appendChild(list);
+}
+function ToggleDevOverlay() {
+if (!enabled) {
+return;
+}
+if (!panel) {
+createPanel();
+}
+if (panel.parentNode) {
+document.body.removeChild(panel);
+clearInterval(poller);
+poller = null;
+return;
+}
+document.body.appendChild(panel);
+render();
+list.scrollTop = list.scrollHeight;
+fetchTraces();
+poller = setInterval(fetchTraces, 500);
+}
+function EnableDevOverlay() {
+if (enabled) {
+return;
+}
+enabled = true;
+_events__WEBPACK_IMPORTED_MODULE_0__["On"]('wails:devoverlay:traces', addTraces);
+document.addEventListener('keydown', function (event) {
+if ((event.ctrlKey || event.metaKey) && event.shiftKey && (event.key === 'D' || event.key === 'd' || event.keyCode === 68)) {
+event.preventDefault();
+ToggleDevOverlay();
+}
+}, true);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "SetupFullscreen", function() { return SetupFullscreen; });
+var _calls__WEBPACK_IMPORTED_MODULE_0__ = __webpack_require__(4);
+var _events__WEBPACK_IMPORTED_MODULE_1__ = __webpack_require__(9);
+var _utils__WEBPACK_IMPORTED_MODULE_2__ = __webpack_require__(23);
+var fullscreenElement = null;
+function SetupFullscreen() {
+if (document.fullscreenEnabled) {
+document.addEventListener('fullscreenchange', nativeChanged);
+return;
+}
+if (document.webkitFullscreenEnabled) {
+unprefix();
+return;
+}
+emulate();
+}
+function nativeChanged() {
+_events__WEBPACK_IMPORTED_MODULE_1__["Emit"]('wails:fullscreen', !!(document.fullscreenElement || document.webkitFullscreenElement));
+}
+function unprefix() {
+var prototype = Element.prototype;
+if (!prototype.requestFullscreen) {
+prototype.requestFullscreen = function () {
+this.webkitRequestFullscreen();
+return Promise.resolve();
+};
+}
+if (!document.exitFullscreen) {
+document.exitFullscreen = function () {
+document.webkitExitFullscreen();
+return Promise.resolve();
+};
+}
+define('fullscreenEnabled', function () {
+return true;
+});
+define('fullscreenElement', function () {
+return document.webkitFullscreenElement || null;
+});
+document.addEventListener('webkitfullscreenchange', function (event) {
+dispatch(event.target, 'fullscreenchange');
+nativeChanged();
+});
+}
+function emulate() {
+_utils__WEBPACK_IMPORTED_MODULE_2__["InjectCSS"]('.wails-fullscreen { position: fixed !important; top: 0 !important; left: 0 !important; ' +
+'width: 100% !important; height: 100% !important; max-width: none !important; ' +
+'max-height: none !important; margin: 0 !important; box-sizing: border-box !important; ' +
+'z-index: 2147483647 !important; background: #000; }');
+var prototype = Element.prototype;
+prototype.requestFullscreen = function () {
+return enter(this);
+};
+prototype.webkitRequestFullscreen = prototype.requestFullscreen;
+prototype.msRequestFullscreen = prototype.requestFullscreen;
+document.exitFullscreen = exit;
+document.webkitExitFullscreen = exit;
+document.msExitFullscreen = exit;
+define('fullscreenEnabled', function () {
+return true;
+});
+define('fullscreenElement', function () {
+return fullscreenElement;
+});
+document.addEventListener('keydown', function (event) {
+if (fullscreenElement && (event.key === 'Escape' || event.key === 'Esc' || event.keyCode === 27)) {
+event.preventDefault();
+exit();
+}
+}, true);
+}
+function enter(element) {
+if (fullscreenElement === element) {
+return Promise.resolve();
+}
+if (fullscreenElement) {
+fullscreenElement.classList.remove('wails-fullscreen');
+} else {
+_calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.Fullscreen');
+}
+fullscreenElement = element;
+element.classList.add('wails-fullscreen');
+dispatch(element, 'fullscreenchange');
+_events__WEBPACK_IMPORTED_MODULE_1__["Emit"]('wails:fullscreen', true);
+return Promise.resolve();
+}
+function exit() {
+var element = fullscreenElement;
+if (!element) {
+return Promise.resolve();
+}
+fullscreenElement = null;
+element.classList.remove('wails-fullscreen');
+_calls__WEBPACK_IMPORTED_MODULE_0__["SystemCall"]('Window.UnFullscreen');
+dispatch(element, 'fullscreenchange');
+_events__WEBPACK_IMPORTED_MODULE_1__["Emit"]('wails:fullscreen', false);
+return Promise.resolve();
+}
+function define(name, getter) {
+try {
+Object.defineProperty(document, name, { get: getter, configurable: true });
+} catch (e) {
+}
+}
+function dispatch(element, name) {
+var event = document.createEvent('Event');
+event.initEvent(name, true, false);
+(document.documentElement.contains(element) ? element : document).dispatchEvent(event);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "SetupPointerEvents", function() { return SetupPointerEvents; });
+__webpack_require__.d(__webpack_exports__, "SetSystemGestures", function() { return SetSystemGestures; });
+var gestureStyle = null;
+function SetupPointerEvents() {
+if (window.PointerEvent) {
+return;
+}
+if (window.MSPointerEvent) {
+forwardMSPointerEvents();
+return;
+}
+emulatePointerEvents();
+}
+function SetSystemGestures(enabled) {
+if (enabled) {
+if (gestureStyle) {
+gestureStyle.parentNode.removeChild(gestureStyle);
+gestureStyle = null;
+document.removeEventListener('webkitmouseforcewillbegin', preventDefault, true);
+}
+return;
+}
+if (!gestureStyle) {
+gestureStyle = document.createElement('style');
+gestureStyle.setAttribute('type', 'text/css');
+gestureStyle.appendChild(document.createTextNode('html, body, * { touch-action: none; -ms-touch-action: none; -webkit-touch-callout: none; }'));
+document.head.appendChild(gestureStyle);
+document.addEventListener('webkitmouseforcewillbegin', preventDefault, true);
+}
+}
+function preventDefault(event) {
+event.preventDefault();
+}
+function forwardMSPointerEvents() {
+var types = {
+MSPointerDown: 'pointerdown',
+MSPointerMove: 'pointermove',
+MSPointerUp: 'pointerup',
+MSPointerCancel: 'pointercancel',
+MSPointerOver: 'pointerover',
+MSPointerOut: 'pointerout',
+};
+var pointerTypes = { 2: 'touch', 3: 'pen', 4: 'mouse' };
+Object.keys(types).forEach(function (type) {
+document.addEventListener(type, function (event) {
+dispatch(types[type], event.target, event, {
+pointerId: event.pointerId,
+pointerType: pointerTypes[event.pointerType] || event.pointerType,
+pressure: event.pressure,
+tiltX: event.tiltX,
+tiltY: event.tiltY,
+width: event.width,
+height: event.height,
+isPrimary: event.isPrimary,
+});
+}, true);
+});
+}
+function emulatePointerEvents() {
+var mouse = function (type) {
+return function (event) {
+var pressure = event.buttons || type === 'pointerdown' ? 0.5 : 0;
+if (pressure && event.webkitForce) {
+pressure = Math.max(0, Math.min(1, (event.webkitForce - 1) / 2));
+}
+if (type === 'pointerup') {
+pressure = 0;
+}
+dispatch(type, event.target, event, {
+pointerId: 1,
+pointerType: 'mouse',
+pressure: pressure,
+tiltX: 0,
+tiltY: 0,
+width: 1,
+height: 1,
+isPrimary: true,
+});
+};
+};
+document.addEventListener('mousedown', mouse('pointerdown'), true);
+document.addEventListener('mousemove', mouse('pointermove'), true);
+document.addEventListener('mouseup', mouse('pointerup'), true);
+var touch = function (type) {
+return function (event) {
+for (var i = 0; i < event.changedTouches.length; i++) {
+var point = event.changedTouches[i];
+var pen = point.touchType === 'stylus';
+var tilt = { x: 0, y: 0 };
+if (pen && point.altitudeAngle !== undefined) {
+tilt = penTilt(point.altitudeAngle, point.azimuthAngle);
+}
+dispatch(type, point.target, point, {
+pointerId: point.identifier + 2,
+pointerType: pen ? 'pen' : 'touch',
+pressure: type === 'pointerup' || type === 'pointercancel' ? 0 : (point.force || 0.5),
+tiltX: tilt.x,
+tiltY: tilt.y,
+width: (point.radiusX || 0.5) * 2,
+height: (point.radiusY || 0.5) * 2,
+isPrimary: point === event.touches[0] || event.touches.length === 0,
+});
+}
+};
+};
+document.addEventListener('touchstart', touch('pointerdown'), true);
+document.addEventListener('touchmove', touch('pointermove'), true);
+document.addEventListener('touchend', touch('pointerup'), true);
+document.addEventListener('touchcancel', touch('pointercancel'), true);
+}
+function penTilt(altitude, azimuth) {
+var degrees = 180 / Math.PI;
+if (altitude >= Math.PI / 2) {
+return { x: 0, y: 0 };
+}
+var tan = Math.tan(altitude);
+return {
+x: Math.round(Math.atan(Math.cos(azimuth) / tan) * degrees),
+y: Math.round(Math.atan(Math.sin(azimuth) / tan) * degrees),
+};
+}
+function dispatch(type, target, source, pointer) {
+var event = document.createEvent('MouseEvents');
+var bubbles = type !== 'pointerover' && type !== 'pointerout';
+event.initMouseEvent(type, bubbles, true, window, 0,
+source.screenX, source.screenY, source.clientX, source.clientY,
+!!source.ctrlKey, !!source.altKey, !!source.shiftKey, !!source.metaKey,
+source.button || 0, null);
+Object.keys(pointer).forEach(function (name) {
+event[name] = pointer[name];
+});
+target.dispatchEvent(event);
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "SetupFileDrop", function() { return SetupFileDrop; });
+function SetupFileDrop() {
+var accept = function (event) {
+if (!hasFiles(event) || event.defaultPrevented) {
+return;
+}
+event.preventDefault();
+if (event.type !== 'drop') {
+event.dataTransfer.dropEffect = 'copy';
+}
+};
+window.addEventListener('dragenter', accept);
+window.addEventListener('dragover', accept);
+window.addEventListener('drop', accept);
+}
+function hasFiles(event) {
+var types = event.dataTransfer && event.dataTransfer.types;
+if (!types) {
+return false;
+}
+if (types.indexOf) {
+return types.indexOf('Files') !== -1;
+}
+return types.contains('Files');
+}
+},
+function (module, __webpack_exports__, __webpack_require__) {
+"use strict";
+__webpack_require__.r(__webpack_exports__);
+__webpack_require__.d(__webpack_exports__, "New", function() { return New; });
+function New(name, optionalDefault) {
+var data;
+if( !window.wails) {
+throw Error('Wails is not initialised');
+}
+var callbacks = [];
+this.subscribe = function (callback) {
+callbacks.push(callback);
+};
+this.set = function (newdata) {
+data = newdata;
+window.wails.Events.Emit('wails:sync:store:updatedbyfrontend:'+name, JSON.stringify(data));
+callbacks.forEach( function(callback) {
+callback(data);
+});
+};
+this.update = (function (updater) {
+var newValue = updater(data);
+this.set(newValue);
+}).bind(this);
+window.wails.Events.On('wails:sync:store:updatedbybackend:'+name, function(result) {
+result = JSON.parse(result);
+data = result;
+callbacks.forEach( function(callback) {
+callback(data);
+});
+});
+if( optionalDefault ) {
+this.set(optionalDefault);
+}
+return this;
+}
+}
+]);
\ No newline at end of file
@@ -0,0 +1,149 @@
+package runtime
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// Bookmarks keeps access to the folders the user has chosen across launches
+// of sandboxed macOS apps. Sandboxed apps lose access to user chosen folders
+// when they quit, unless they keep a security-scoped bookmark to them.
+// On other platforms, and for apps that aren't sandboxed, access is never
+// lost and Bookmarks does nothing.
+type Bookmarks struct {
+	filename string
+	log      *logger.CustomLogger
+	lock     sync.Mutex
+	disabled error
+
+	// Bookmarks keyed by path
+	entries map[string]string
+}
+
+// NewBookmarks creates a new Bookmarks struct. Bookmarks are saved
+// in the user's config directory, under the app's title.
+func NewBookmarks(config interfaces.AppConfig) *Bookmarks {
+	result := &Bookmarks{
+		log:     logger.NewCustomLogger("Bookmarks"),
+		entries: make(map[string]string),
+	}
+	if configDir, err := os.UserConfigDir(); err == nil && config != nil {
+		name := strings.ToLower(strings.Replace(config.GetTitle(), " ", "-", -1))
+		result.filename = filepath.Join(configDir, name, "bookmarks.json")
+	}
+	return result
+}
+
+// Add creates bookmarks for the given paths and saves them
+func (b *Bookmarks) Add(paths ...string) error {
+	if b.disabled != nil {
+		return b.disabled
+	}
+	if !bookmarksSupported() {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for _, path := range paths {
+		bookmark, err := createBookmark(path)
+		if err != nil {
+			return err
+		}
+		b.entries[path] = bookmark
+	}
+	return b.save()
+}
+
+// Remove deletes the bookmark for the given path. The app
+// keeps access to the path until it quits.
+func (b *Bookmarks) Remove(path string) error {
+	if b.disabled != nil {
+		return b.disabled
+	}
+	if !bookmarksSupported() {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.entries, path)
+	return b.save()
+}
+
+// Paths returns the paths that are bookmarked
+func (b *Bookmarks) Paths() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	var result []string
+	for path := range b.entries {
+		result = append(result, path)
+	}
+	return result
+}
+
+// Restore resolves the saved bookmarks, regaining access to their paths.
+// Bookmarks that can't be resolved are dropped and stale bookmarks, EG: for
+// folders that have moved, are recreated. The accessible paths are returned.
+func (b *Bookmarks) Restore() []string {
+	if !bookmarksSupported() || b.filename == "" || b.disabled != nil {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	data, err := ioutil.ReadFile(b.filename)
+	if err != nil {
+		return nil
+	}
+	var saved map[string]string
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		b.log.Errorf("Unable to read bookmarks: %s", err.Error())
+		return nil
+	}
+
+	var result []string
+	b.entries = make(map[string]string)
+	for oldPath, bookmark := range saved {
+		path, stale, err := resolveBookmark(bookmark)
+		if err != nil {
+			b.log.Errorf("Unable to resolve bookmark for '%s': %s", oldPath, err.Error())
+			continue
+		}
+		if stale {
+			if bookmark, err = createBookmark(path); err != nil {
+				continue
+			}
+		}
+		b.entries[path] = bookmark
+		result = append(result, path)
+	}
+
+	err = b.save()
+	if err != nil {
+		b.log.Errorf("Unable to save bookmarks: %s", err.Error())
+	}
+	return result
+}
+
+// save writes the bookmarks to disk. The lock must be held.
+func (b *Bookmarks) save() error {
+	if b.filename == "" {
+		return nil
+	}
+	data, err := json.Marshal(b.entries)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(b.filename), 0700)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(b.filename, data, 0600)
+}
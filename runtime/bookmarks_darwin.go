@@ -0,0 +1,87 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation
+
+#import <Foundation/Foundation.h>
+#include <stdlib.h>
+#include <string.h>
+
+// Returns a base64 encoded security-scoped bookmark for the given path, or NULL
+static char *createBookmark(const char *path) {
+	@autoreleasepool {
+		NSURL *url = [NSURL fileURLWithPath:[NSString stringWithUTF8String:path]];
+		NSData *data = [url bookmarkDataWithOptions:NSURLBookmarkCreationWithSecurityScope
+		             includingResourceValuesForKeys:nil
+		                              relativeToURL:nil
+		                                      error:nil];
+		if (data == nil) {
+			return NULL;
+		}
+		return strdup([[data base64EncodedStringWithOptions:0] UTF8String]);
+	}
+}
+
+// Resolves the given bookmark and starts accessing its path, which is returned.
+// Returns NULL if the bookmark can't be resolved.
+static char *resolveBookmark(const char *bookmark, int *stale) {
+	@autoreleasepool {
+		NSData *data = [[[NSData alloc]
+			initWithBase64EncodedString:[NSString stringWithUTF8String:bookmark]
+			                    options:0] autorelease];
+		if (data == nil) {
+			return NULL;
+		}
+		BOOL isStale = NO;
+		NSURL *url = [NSURL URLByResolvingBookmarkData:data
+		                                       options:NSURLBookmarkResolutionWithSecurityScope
+		                                 relativeToURL:nil
+		                           bookmarkDataIsStale:&isStale
+		                                         error:nil];
+		if (url == nil || ![url startAccessingSecurityScopedResource]) {
+			return NULL;
+		}
+		*stale = isStale ? 1 : 0;
+		return strdup([[url path] UTF8String]);
+	}
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+)
+
+// Security-scoped bookmarks are only needed by sandboxed apps
+func bookmarksSupported() bool {
+	return os.Getenv("APP_SANDBOX_CONTAINER_ID") != ""
+}
+
+func createBookmark(path string) (string, error) {
+	pathPtr := C.CString(path)
+	defer C.free(unsafe.Pointer(pathPtr))
+	bookmark := C.createBookmark(pathPtr)
+	if bookmark == nil {
+		return "", fmt.Errorf("unable to create bookmark for '%s'", path)
+	}
+	defer C.free(unsafe.Pointer(bookmark))
+	return C.GoString(bookmark), nil
+}
+
+func resolveBookmark(bookmark string) (string, bool, error) {
+	bookmarkPtr := C.CString(bookmark)
+	defer C.free(unsafe.Pointer(bookmarkPtr))
+	var stale C.int
+	path := C.resolveBookmark(bookmarkPtr, &stale)
+	if path == nil {
+		return "", false, fmt.Errorf("bookmark can't be resolved")
+	}
+	defer C.free(unsafe.Pointer(path))
+	return C.GoString(path), stale == 1, nil
+}
@@ -0,0 +1,19 @@
+//go:build !darwin
+// +build !darwin
+
+package runtime
+
+import "errors"
+
+// Access to user chosen folders is only lost by sandboxed macOS apps
+func bookmarksSupported() bool {
+	return false
+}
+
+func createBookmark(path string) (string, error) {
+	return "", errors.New("bookmarks are not supported on this platform")
+}
+
+func resolveBookmark(bookmark string) (string, bool, error) {
+	return "", false, errors.New("bookmarks are not supported on this platform")
+}
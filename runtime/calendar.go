@@ -0,0 +1,144 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// CalendarEvent is an event in one of the user's calendars
+type CalendarEvent struct {
+	// Set by the provider. Events without one are added by SaveEvent.
+	ID string `json:"id"`
+
+	// The name of the calendar. Events are added to the user's default
+	// calendar if it's empty.
+	Calendar string `json:"calendar"`
+
+	Title    string    `json:"title"`
+	Notes    string    `json:"notes,omitempty"`
+	Location string    `json:"location,omitempty"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	AllDay   bool      `json:"allDay"`
+}
+
+// CalendarProvider connects the runtime to the user's calendars, EG:
+// EventKit on MacOS or Windows.ApplicationModel.Appointments
+type CalendarProvider interface {
+	DataAccess
+
+	// Events returns the events that overlap the given period
+	Events(start, end time.Time) ([]*CalendarEvent, error)
+
+	// SaveEvent adds the event, or updates it if it has an ID, returning
+	// its ID
+	SaveEvent(event *CalendarEvent) (string, error)
+
+	// DeleteEvent removes the event with the given ID
+	DeleteEvent(id string) error
+}
+
+// noCalendar is used when no CalendarProvider is set
+type noCalendar struct{}
+
+func (noCalendar) AccessStatus() PermissionStatus { return PermissionUnknown }
+func (noCalendar) RequestAccess() (PermissionStatus, error) {
+	return PermissionUnknown, ErrDataUnavailable
+}
+func (noCalendar) Events(time.Time, time.Time) ([]*CalendarEvent, error) {
+	return nil, ErrDataUnavailable
+}
+func (noCalendar) SaveEvent(*CalendarEvent) (string, error) { return "", ErrDataUnavailable }
+func (noCalendar) DeleteEvent(string) error                 { return ErrDataUnavailable }
+
+// Calendar exposes the user's calendars through the CalendarProvider set
+// for the app. The user must allow access with Request first.
+type Calendar struct {
+	access   dataAccess
+	lock     sync.Mutex
+	provider CalendarProvider
+}
+
+// NewCalendar creates a new Calendar struct
+func NewCalendar(eventManager interfaces.EventManager) *Calendar {
+	return &Calendar{
+		access: dataAccess{
+			eventManager: eventManager,
+			log:          logger.NewCustomLogger("Calendar"),
+			permission:   PermissionCalendar,
+		},
+		provider: noCalendar{},
+	}
+}
+
+// SetProvider sets the CalendarProvider
+func (r *Calendar) SetProvider(provider CalendarProvider) {
+	if provider == nil {
+		provider = noCalendar{}
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.provider = provider
+}
+
+func (r *Calendar) getProvider() CalendarProvider {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.provider
+}
+
+// Status returns whether the user has allowed the app to access their
+// calendars
+func (r *Calendar) Status() PermissionStatus {
+	return r.getProvider().AccessStatus()
+}
+
+// Request asks the user to allow the app to access their calendars,
+// returning the status once they have answered. PermissionChangedEvent is
+// emitted with PermissionCalendar if the status changes.
+func (r *Calendar) Request() (PermissionStatus, error) {
+	return r.access.request(r.getProvider())
+}
+
+// Events returns the events that overlap the given period
+func (r *Calendar) Events(start, end time.Time) ([]*CalendarEvent, error) {
+	provider := r.getProvider()
+	err := r.access.check(provider)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("the period ends before it starts")
+	}
+	return provider.Events(start, end)
+}
+
+// SaveEvent adds the event, or updates it if it has an ID, returning its ID
+func (r *Calendar) SaveEvent(event *CalendarEvent) (string, error) {
+	provider := r.getProvider()
+	err := r.access.check(provider)
+	if err != nil {
+		return "", err
+	}
+	if event == nil {
+		return "", fmt.Errorf("no event given")
+	}
+	if event.End.Before(event.Start) {
+		return "", fmt.Errorf("event '%s' ends before it starts", event.Title)
+	}
+	return provider.SaveEvent(event)
+}
+
+// DeleteEvent removes the event with the given ID
+func (r *Calendar) DeleteEvent(id string) error {
+	provider := r.getProvider()
+	err := r.access.check(provider)
+	if err != nil {
+		return err
+	}
+	return provider.DeleteEvent(id)
+}
@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+)
+
+// ErrClipboardUnavailable is returned on Linux when none of the clipboard
+// tools are installed
+var ErrClipboardUnavailable = errors.New("no clipboard tool found, install wl-clipboard, xclip or xsel")
+
+// Clipboard reads and writes the system clipboard from Go, so apps don't
+// depend on the webview's clipboard permissions
+type Clipboard struct{}
+
+// NewClipboard creates a new Clipboard struct
+func NewClipboard() *Clipboard {
+	return &Clipboard{}
+}
+
+// GetText returns the text on the clipboard, or "" if it holds no text
+func (r *Clipboard) GetText() (string, error) {
+	return clipboardText()
+}
+
+// SetText replaces the contents of the clipboard with the given text
+func (r *Clipboard) SetText(text string) error {
+	return setClipboardText(text)
+}
+
+// GetImage returns the image on the clipboard as a PNG, or nil if it holds
+// no image
+func (r *Clipboard) GetImage() ([]byte, error) {
+	return clipboardImage()
+}
+
+// SetImage replaces the contents of the clipboard with the given PNG image
+func (r *Clipboard) SetImage(image []byte) error {
+	_, err := png.DecodeConfig(bytes.NewReader(image))
+	if err != nil {
+		return errors.New("the image is not a PNG")
+	}
+	return setClipboardImage(image)
+}
@@ -0,0 +1,103 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+
+#import <AppKit/AppKit.h>
+#include <stdlib.h>
+
+// Returns NULL if the pasteboard holds no text
+static char *clipboardText() {
+	@autoreleasepool {
+		NSString *text = [[NSPasteboard generalPasteboard] stringForType:NSPasteboardTypeString];
+		if (text == nil) {
+			return NULL;
+		}
+		return strdup([text UTF8String]);
+	}
+}
+
+static void setClipboardText(const char *text) {
+	@autoreleasepool {
+		NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+		[pasteboard clearContents];
+		[pasteboard setString:[NSString stringWithUTF8String:text] forType:NSPasteboardTypeString];
+	}
+}
+
+// Returns the image as a PNG, converted from TIFF where the app that copied
+// it only offers that, or NULL if the pasteboard holds no image
+static void *clipboardImage(int *length) {
+	@autoreleasepool {
+		NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+		NSData *data = [pasteboard dataForType:NSPasteboardTypePNG];
+		if (data == nil) {
+			NSData *tiff = [pasteboard dataForType:NSPasteboardTypeTIFF];
+			if (tiff == nil) {
+				return NULL;
+			}
+			NSBitmapImageRep *bitmap = [NSBitmapImageRep imageRepWithData:tiff];
+			data = [bitmap representationUsingType:NSBitmapImageFileTypePNG properties:@{}];
+			if (data == nil) {
+				return NULL;
+			}
+		}
+		*length = (int)[data length];
+		void *result = malloc([data length]);
+		memcpy(result, [data bytes], [data length]);
+		return result;
+	}
+}
+
+// The image is set as TIFF too, for apps that don't read PNG
+static void setClipboardImage(const void *png, int length) {
+	@autoreleasepool {
+		NSData *data = [NSData dataWithBytes:png length:length];
+		NSPasteboard *pasteboard = [NSPasteboard generalPasteboard];
+		[pasteboard clearContents];
+		[pasteboard setData:data forType:NSPasteboardTypePNG];
+		NSBitmapImageRep *bitmap = [NSBitmapImageRep imageRepWithData:data];
+		if (bitmap != nil) {
+			[pasteboard setData:[bitmap TIFFRepresentation] forType:NSPasteboardTypeTIFF];
+		}
+	}
+}
+*/
+import "C"
+
+import "unsafe"
+
+func clipboardText() (string, error) {
+	text := C.clipboardText()
+	if text == nil {
+		return "", nil
+	}
+	defer C.free(unsafe.Pointer(text))
+	return C.GoString(text), nil
+}
+
+func setClipboardText(text string) error {
+	ctext := C.CString(text)
+	defer C.free(unsafe.Pointer(ctext))
+	C.setClipboardText(ctext)
+	return nil
+}
+
+func clipboardImage() ([]byte, error) {
+	var length C.int
+	image := C.clipboardImage(&length)
+	if image == nil {
+		return nil, nil
+	}
+	defer C.free(image)
+	return C.GoBytes(image, length), nil
+}
+
+func setClipboardImage(image []byte) error {
+	C.setClipboardImage(unsafe.Pointer(&image[0]), C.int(len(image)))
+	return nil
+}
@@ -0,0 +1,94 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// clipboardCommand returns the command that reads the clipboard, or writes
+// it when copy is true, as the given MIME type. Wayland sessions use
+// wl-clipboard, and X11 sessions use xclip or, for text only, xsel.
+func clipboardCommand(copy bool, mimeType string) (*exec.Cmd, error) {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if copy {
+			if _, err := exec.LookPath("wl-copy"); err == nil {
+				return exec.Command("wl-copy", "--type", mimeType), nil
+			}
+		} else if _, err := exec.LookPath("wl-paste"); err == nil {
+			return exec.Command("wl-paste", "--no-newline", "--type", mimeType), nil
+		}
+	}
+	if _, err := exec.LookPath("xclip"); err == nil {
+		if copy {
+			return exec.Command("xclip", "-selection", "clipboard", "-in", "-target", mimeType), nil
+		}
+		return exec.Command("xclip", "-selection", "clipboard", "-out", "-target", mimeType), nil
+	}
+	if _, err := exec.LookPath("xsel"); err == nil && strings.HasPrefix(mimeType, "text/") {
+		if copy {
+			return exec.Command("xsel", "--clipboard", "--input"), nil
+		}
+		return exec.Command("xsel", "--clipboard", "--output"), nil
+	}
+	return nil, ErrClipboardUnavailable
+}
+
+// readClipboard returns the clipboard's contents as the given MIME type, or
+// nil if it doesn't hold that type. The tools fail when it doesn't.
+func readClipboard(mimeType string) ([]byte, error) {
+	cmd, err := clipboardCommand(false, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	data, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func writeClipboard(mimeType string, data []byte) error {
+	cmd, err := clipboardCommand(true, mimeType)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = bytes.NewReader(data)
+	return cmd.Run()
+}
+
+func clipboardText() (string, error) {
+	text, err := readClipboard("text/plain;charset=utf-8")
+	if err != nil || text != nil {
+		return string(text), err
+	}
+	// Some X11 apps only offer text as UTF8_STRING
+	if _, err := exec.LookPath("xclip"); err == nil && os.Getenv("WAYLAND_DISPLAY") == "" {
+		text, err = readClipboard("UTF8_STRING")
+		return string(text), err
+	}
+	return "", nil
+}
+
+func setClipboardText(text string) error {
+	return writeClipboard("text/plain;charset=utf-8", []byte(text))
+}
+
+func clipboardImage() ([]byte, error) {
+	image, err := readClipboard("image/png")
+	if err != nil || len(image) == 0 {
+		return nil, err
+	}
+	return image, nil
+}
+
+func setClipboardImage(image []byte) error {
+	return writeClipboard("image/png", image)
+}
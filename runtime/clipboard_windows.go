@@ -0,0 +1,290 @@
+//go:build windows
+// +build windows
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	cfDIB         = 8
+	cfUnicodeText = 13
+	gmemMoveable  = 0x0002
+
+	// BITMAPINFOHEADER compressions
+	biRGB       = 0
+	biBitfields = 3
+)
+
+var (
+	procOpenClipboard              = user32.NewProc("OpenClipboard")
+	procCloseClipboard             = user32.NewProc("CloseClipboard")
+	procEmptyClipboard             = user32.NewProc("EmptyClipboard")
+	procGetClipboardData           = user32.NewProc("GetClipboardData")
+	procSetClipboardData           = user32.NewProc("SetClipboardData")
+	procIsClipboardFormatAvailable = user32.NewProc("IsClipboardFormatAvailable")
+	procRegisterClipboardFormatW   = user32.NewProc("RegisterClipboardFormatW")
+	procGlobalAlloc                = kernel32.NewProc("GlobalAlloc")
+	procGlobalFree                 = kernel32.NewProc("GlobalFree")
+	procGlobalLock                 = kernel32.NewProc("GlobalLock")
+	procGlobalUnlock               = kernel32.NewProc("GlobalUnlock")
+	procGlobalSize                 = kernel32.NewProc("GlobalSize")
+	procRtlMoveMemory              = kernel32.NewProc("RtlMoveMemory")
+)
+
+// bitmapInfoHeader is BITMAPINFOHEADER
+type bitmapInfoHeader struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   uint32
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+// pngFormat returns the "PNG" clipboard format, which browsers and Office
+// use for images with transparency
+func pngFormat() uintptr {
+	name, _ := syscall.UTF16PtrFromString("PNG")
+	format, _, _ := procRegisterClipboardFormatW.Call(uintptr(unsafe.Pointer(name)))
+	return format
+}
+
+// openClipboard opens the clipboard on the calling thread, which is locked
+// until the returned function closes it. Other apps hold the clipboard
+// briefly while they use it, so opening it is retried.
+func openClipboard() (func(), error) {
+	runtime.LockOSThread()
+	var err error
+	for attempt := 0; attempt < 10; attempt++ {
+		var result uintptr
+		result, _, err = procOpenClipboard.Call(0)
+		if result != 0 {
+			return func() {
+				procCloseClipboard.Call()
+				runtime.UnlockOSThread()
+			}, nil
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	runtime.UnlockOSThread()
+	return nil, fmt.Errorf("unable to open the clipboard: %s", err)
+}
+
+// readClipboard returns the clipboard's contents in the given format, or nil
+// if it doesn't hold that format. The clipboard must be open.
+func readClipboard(format uintptr) []byte {
+	if available, _, _ := procIsClipboardFormatAvailable.Call(format); available == 0 {
+		return nil
+	}
+	handle, _, _ := procGetClipboardData.Call(format)
+	if handle == 0 {
+		return nil
+	}
+	size, _, _ := procGlobalSize.Call(handle)
+	pointer, _, _ := procGlobalLock.Call(handle)
+	if pointer == 0 || size == 0 {
+		return nil
+	}
+	defer procGlobalUnlock.Call(handle)
+	data := make([]byte, size)
+	procRtlMoveMemory.Call(uintptr(unsafe.Pointer(&data[0])), pointer, size)
+	return data
+}
+
+// writeClipboard adds the data to the clipboard in the given format. The
+// clipboard must be open and emptied.
+func writeClipboard(format uintptr, data []byte) error {
+	handle, _, err := procGlobalAlloc.Call(gmemMoveable, uintptr(len(data)))
+	if handle == 0 {
+		return err
+	}
+	pointer, _, err := procGlobalLock.Call(handle)
+	if pointer == 0 {
+		procGlobalFree.Call(handle)
+		return err
+	}
+	procRtlMoveMemory.Call(pointer, uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)))
+	procGlobalUnlock.Call(handle)
+
+	// The clipboard owns the memory once it's set
+	result, _, err := procSetClipboardData.Call(format, handle)
+	if result == 0 {
+		procGlobalFree.Call(handle)
+		return err
+	}
+	return nil
+}
+
+func clipboardText() (string, error) {
+	closeClipboard, err := openClipboard()
+	if err != nil {
+		return "", err
+	}
+	defer closeClipboard()
+	data := readClipboard(cfUnicodeText)
+	if len(data) < 2 {
+		return "", nil
+	}
+	text := make([]uint16, len(data)/2)
+	for i := range text {
+		text[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+	return syscall.UTF16ToString(text), nil
+}
+
+func setClipboardText(text string) error {
+	utf16, err := syscall.UTF16FromString(text)
+	if err != nil {
+		return err
+	}
+	data := make([]byte, len(utf16)*2)
+	for i, char := range utf16 {
+		binary.LittleEndian.PutUint16(data[i*2:], char)
+	}
+	closeClipboard, err := openClipboard()
+	if err != nil {
+		return err
+	}
+	defer closeClipboard()
+	procEmptyClipboard.Call()
+	return writeClipboard(cfUnicodeText, data)
+}
+
+// Images are read from the PNG format where an app offers it, and from the
+// device independent bitmap that every app offers otherwise
+func clipboardImage() ([]byte, error) {
+	closeClipboard, err := openClipboard()
+	if err != nil {
+		return nil, err
+	}
+	defer closeClipboard()
+	if data := readClipboard(pngFormat()); data != nil {
+		return data, nil
+	}
+	dib := readClipboard(cfDIB)
+	if dib == nil {
+		return nil, nil
+	}
+	bitmap, err := decodeDIB(dib)
+	if err != nil {
+		return nil, err
+	}
+	var result bytes.Buffer
+	err = png.Encode(&result, bitmap)
+	if err != nil {
+		return nil, err
+	}
+	return result.Bytes(), nil
+}
+
+// The image is set as a PNG, for apps that keep its transparency, and as a
+// device independent bitmap for the others
+func setClipboardImage(data []byte) error {
+	decoded, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	closeClipboard, err := openClipboard()
+	if err != nil {
+		return err
+	}
+	defer closeClipboard()
+	procEmptyClipboard.Call()
+	err = writeClipboard(pngFormat(), data)
+	if err != nil {
+		return err
+	}
+	return writeClipboard(cfDIB, encodeDIB(decoded))
+}
+
+// decodeDIB decodes a 24 or 32 bit device independent bitmap, as set by
+// screenshots and most apps
+func decodeDIB(dib []byte) (image.Image, error) {
+	var header bitmapInfoHeader
+	err := binary.Read(bytes.NewReader(dib), binary.LittleEndian, &header)
+	if err != nil {
+		return nil, err
+	}
+	if header.Compression != biRGB && header.Compression != biBitfields {
+		return nil, errors.New("the clipboard's bitmap is compressed")
+	}
+	if header.BitCount != 24 && header.BitCount != 32 {
+		return nil, fmt.Errorf("the clipboard's bitmap has %d bits per pixel", header.BitCount)
+	}
+	offset := int(header.Size) + int(header.ClrUsed)*4
+	// The colour masks follow the original header
+	if header.Compression == biBitfields && header.Size == 40 {
+		offset += 12
+	}
+	width, height := int(header.Width), int(header.Height)
+	bottomUp := height > 0
+	if !bottomUp {
+		height = -height
+	}
+	pixelSize := int(header.BitCount) / 8
+	// Rows are padded to 4 bytes
+	stride := (width*pixelSize + 3) &^ 3
+	if width <= 0 || offset+stride*height > len(dib) {
+		return nil, errors.New("the clipboard's bitmap is truncated")
+	}
+
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		row := y
+		if bottomUp {
+			row = height - 1 - y
+		}
+		pixels := dib[offset+row*stride:]
+		for x := 0; x < width; x++ {
+			pixel := pixels[x*pixelSize:]
+			// The alpha of 32 bit bitmaps is usually unused
+			result.SetNRGBA(x, y, color.NRGBA{R: pixel[2], G: pixel[1], B: pixel[0], A: 255})
+		}
+	}
+	return result, nil
+}
+
+// encodeDIB encodes an image as a 32 bit, bottom-up device independent
+// bitmap
+func encodeDIB(source image.Image) []byte {
+	bounds := source.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), source, bounds.Min, draw.Src)
+
+	width, height := bounds.Dx(), bounds.Dy()
+	header := bitmapInfoHeader{
+		Size:      40,
+		Width:     int32(width),
+		Height:    int32(height),
+		Planes:    1,
+		BitCount:  32,
+		SizeImage: uint32(width * height * 4),
+	}
+	var result bytes.Buffer
+	binary.Write(&result, binary.LittleEndian, header)
+	for y := height - 1; y >= 0; y-- {
+		for x := 0; x < width; x++ {
+			pixel := nrgba.NRGBAAt(x, y)
+			result.Write([]byte{pixel.B, pixel.G, pixel.R, pixel.A})
+		}
+	}
+	return result.Bytes()
+}
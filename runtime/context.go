@@ -0,0 +1,18 @@
+package runtime
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the runtime
+func NewContext(ctx context.Context, r *Runtime) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext returns the runtime carried by ctx, EG: the context given to
+// the OnStartup, OnDomReady and OnShutdown hooks of bound structs. It returns
+// nil if there isn't one.
+func FromContext(ctx context.Context) *Runtime {
+	r, _ := ctx.Value(contextKey{}).(*Runtime)
+	return r
+}
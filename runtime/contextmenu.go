@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"sync"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+)
+
+// ContextMenuClickEvent is emitted with the ID of the context menu item that
+// was chosen
+const ContextMenuClickEvent = "wails:contextmenu:click"
+
+// ContextMenuChosenEvent is emitted by the renderer with the number of the
+// context menu item that was chosen
+const ContextMenuChosenEvent = "wails:contextmenu:chosen"
+
+// ContextMenuItem is an item in a context menu. An item with a SubMenu opens
+// it rather than being chosen.
+type ContextMenuItem struct {
+	ID        string             `json:"id"`
+	Label     string             `json:"label"`
+	Disabled  bool               `json:"disabled"`
+	Checked   bool               `json:"checked"`
+	Separator bool               `json:"separator"`
+	SubMenu   []*ContextMenuItem `json:"submenu"`
+}
+
+// ContextMenu shows native context menus, EG: when the frontend is right
+// clicked. Unlike menus drawn in HTML, they can extend past the window.
+type ContextMenu struct {
+	eventManager interfaces.EventManager
+	renderer     interfaces.Renderer
+	lock         sync.Mutex
+	items        []*MenuItem
+}
+
+// NewContextMenu creates a new ContextMenu struct
+func NewContextMenu(eventManager interfaces.EventManager, renderer interfaces.Renderer) *ContextMenu {
+	result := &ContextMenu{
+		eventManager: eventManager,
+		renderer:     renderer,
+	}
+	eventManager.On(ContextMenuChosenEvent, result.chosen)
+	return result
+}
+
+// Show shows the items at the given position in the page, in CSS pixels.
+// Choosing an item emits ContextMenuClickEvent with its ID, to both the
+// backend and the frontend. Nothing is emitted if the menu is dismissed.
+func (r *ContextMenu) Show(items []*ContextMenuItem, x, y int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.items = r.menuItems(items)
+	r.renderer.ShowContextMenu(encodeMenu(r.items), x, y)
+}
+
+// menuItems converts the items to menu items that emit their ID
+func (r *ContextMenu) menuItems(items []*ContextMenuItem) []*MenuItem {
+	result := make([]*MenuItem, 0, len(items))
+	for _, item := range items {
+		id := item.ID
+		result = append(result, &MenuItem{
+			Label:     item.Label,
+			Disabled:  item.Disabled,
+			Checked:   item.Checked,
+			Separator: item.Separator,
+			SubMenu:   r.menuItems(item.SubMenu),
+			OnClick: func() {
+				r.eventManager.Emit(ContextMenuClickEvent, id)
+			},
+		})
+	}
+	return result
+}
+
+// chosen emits the ID of the chosen item
+func (r *ContextMenu) chosen(data ...interface{}) {
+	if len(data) == 0 {
+		return
+	}
+	var number int
+	switch value := data[0].(type) {
+	case int:
+		number = value
+	case float64:
+		number = int(value)
+	default:
+		return
+	}
+
+	r.lock.Lock()
+	item := findMenuItem(r.items, number)
+	r.lock.Unlock()
+	if item != nil && !item.Separator {
+		item.OnClick()
+	}
+}
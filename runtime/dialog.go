@@ -1,17 +1,168 @@
 package runtime
 
-import "github.com/wailsapp/wails/lib/interfaces"
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-playground/colors"
+	"github.com/wailsapp/wails/lib/interfaces"
+)
+
+// ErrFileExists is returned by SaveFile when the chosen file
+// exists and the overwrite policy is OverwriteRefuse
+var ErrFileExists = errors.New("file already exists")
+
+// SaveFormat is a file format the user may choose to save in
+type SaveFormat struct {
+	// The name shown in the format picker, EG: "PNG Image"
+	Name string `json:"name"`
+
+	// The extensions of the format without the dot, EG: "png".
+	// The first is appended to filenames without an extension.
+	Extensions []string `json:"extensions"`
+}
+
+// OverwritePolicy decides what happens when the user chooses an existing file
+type OverwritePolicy int
+
+const (
+	// OverwritePrompt asks the user to confirm overwriting the file
+	OverwritePrompt OverwritePolicy = iota
+
+	// OverwriteAllow overwrites the file without asking. MacOS always asks.
+	OverwriteAllow
+
+	// OverwriteRefuse returns ErrFileExists
+	OverwriteRefuse
+)
+
+// SaveDialogOptions configures the dialog opened by SaveFile
+type SaveDialogOptions struct {
+	Title string `json:"title"`
+
+	// The directory the dialog starts in. If empty, the platform decides,
+	// usually picking the last directory used.
+	DefaultDirectory string `json:"defaultDirectory"`
+
+	// The filename the dialog suggests, EG: "export.csv"
+	DefaultFilename string `json:"defaultFilename"`
+
+	// The formats the user may choose between
+	Formats []SaveFormat `json:"formats"`
+
+	// Prevents the user creating directories. Not supported on Windows.
+	DisableCreateDirectories bool `json:"disableCreateDirectories"`
+
+	Overwrite OverwritePolicy `json:"overwrite"`
+}
+
+// SaveDialogResult is the file and format chosen in a save dialog
+type SaveDialogResult struct {
+	Path string `json:"path"`
+
+	// The chosen format or nil if no formats were given
+	Format *SaveFormat `json:"format"`
+}
+
+// FileFilter is a kind of file the user may choose in an open dialog
+type FileFilter struct {
+	// The name shown in the filter picker, EG: "Images"
+	Name string `json:"name"`
+
+	// The extensions of the files without the dot, EG: "png", or "*"
+	// for any file
+	Extensions []string `json:"extensions"`
+}
+
+// OpenDialogOptions configures the dialog opened by OpenFile
+type OpenDialogOptions struct {
+	Title string `json:"title"`
+
+	// The directory the dialog starts in. If empty, the platform decides,
+	// usually picking the last directory used.
+	DefaultDirectory string `json:"defaultDirectory"`
+
+	// Allows the user to choose more than one file
+	Multiple bool `json:"multiple"`
+
+	// The filters the user may choose between. MacOS has no filter picker,
+	// so the files matching any of them may be chosen.
+	Filters []FileFilter `json:"filters"`
+}
+
+// DirectoryDialogOptions configures the dialog opened by OpenDirectory
+type DirectoryDialogOptions struct {
+	Title string `json:"title"`
+
+	// The directory the dialog starts in. If empty, the platform decides,
+	// usually picking the last directory used.
+	DefaultDirectory string `json:"defaultDirectory"`
+
+	// Allows the user to choose more than one directory
+	Multiple bool `json:"multiple"`
+}
+
+// MessageType is the kind of message shown by Message, which picks its icon
+type MessageType string
+
+const (
+	// MessageInfo informs the user, EG: that an export finished
+	MessageInfo MessageType = "info"
+	// MessageWarning warns the user before something they should check
+	MessageWarning MessageType = "warning"
+	// MessageError reports that something failed
+	MessageError MessageType = "error"
+	// MessageQuestion asks the user to choose, EG: whether to save changes
+	MessageQuestion MessageType = "question"
+)
+
+// Colour is a colour chosen with ColorPicker
+type Colour struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+	A uint8 `json:"a"`
+
+	// The colour in "#rrggbbaa" format
+	Hex string `json:"hex"`
+}
+
+// Font is a font chosen with, or shown first by, FontPicker
+type Font struct {
+	Family string `json:"family"`
+
+	// The name of the face within the family, EG: "Semibold Italic", as
+	// the system names it. On Windows, it's made up from the weight.
+	Style string `json:"style"`
+
+	// The size in points
+	Size int `json:"size"`
+
+	// The weight from 100 to 900, as in CSS, EG: 400 for regular or 700
+	// for bold
+	Weight int `json:"weight"`
+
+	// Bold is set for weights of 700 and above. It picks the weight of
+	// the initial font when its Weight isn't set.
+	Bold   bool `json:"bold"`
+	Italic bool `json:"italic"`
+}
 
 // Dialog exposes an interface to native dialogs
 type Dialog struct {
-	renderer interfaces.Renderer
+	renderer  interfaces.Renderer
+	bookmarks *Bookmarks
 }
 
 // NewDialog creates a new Dialog struct
-func NewDialog(renderer interfaces.Renderer) *Dialog {
+func NewDialog(renderer interfaces.Renderer, bookmarks *Bookmarks) *Dialog {
 	return &Dialog{
-		renderer: renderer,
+		renderer:  renderer,
+		bookmarks: bookmarks,
 	}
 }
 
@@ -28,11 +179,123 @@ func (r *Dialog) SelectFile(params ...string) string {
 	return r.renderer.SelectFile(title, filter)
 }
 
+// OpenFile prompts the user to select one or more files to open, returning
+// their paths. A nil result is returned if the user cancels the dialog.
+func (r *Dialog) OpenFile(options *OpenDialogOptions) ([]string, error) {
+	if options == nil {
+		options = &OpenDialogOptions{}
+	}
+	title := options.Title
+	if title == "" {
+		title = "Select File"
+		if options.Multiple {
+			title = "Select Files"
+		}
+	}
+
+	directory, err := dialogDirectory(options.DefaultDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	var filters []string
+	for _, filter := range options.Filters {
+		filters = append(filters, dialogFormat(filter.Name, filter.Extensions))
+	}
+	return r.renderer.SelectFiles(title, directory, strings.Join(filters, "\n"), options.Multiple), nil
+}
+
+// Message shows a native message dialog with the given buttons, or OK if
+// there are none, returning the label of the pressed button. An empty label
+// is returned if the user dismisses the dialog. Windows shows at most three
+// buttons, and Escape presses the last of them.
+func (r *Dialog) Message(messageType MessageType, title string, message string, buttons []string) (string, error) {
+	switch messageType {
+	case "":
+		messageType = MessageInfo
+	case MessageInfo, MessageWarning, MessageError, MessageQuestion:
+	default:
+		return "", fmt.Errorf("unknown message type '%s'", messageType)
+	}
+
+	// Labels are sent as lines, so they can't be empty or span lines
+	var labels []string
+	for _, button := range buttons {
+		label := strings.Join(strings.Fields(button), " ")
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) == 0 {
+		labels = []string{"OK"}
+	}
+
+	pressed := r.renderer.ShowMessage(string(messageType), title, message, labels)
+	if pressed < 0 || pressed >= len(labels) {
+		return "", nil
+	}
+	return labels[pressed], nil
+}
+
+// About shows the platform's standard about dialog with the app's Title,
+// Version, Copyright and AboutIcon from its AppConfig. On MacOS, it's also
+// shown by the About item of the app menu.
+func (r *Dialog) About() {
+	r.renderer.ShowAbout()
+}
+
 // SelectDirectory prompts the user to select a directory
 func (r *Dialog) SelectDirectory() string {
 	return r.renderer.SelectDirectory()
 }
 
+// SelectDirectories prompts the user to select one or more directories.
+// Sandboxed macOS apps keep access to the directories across launches.
+func (r *Dialog) SelectDirectories(params ...string) []string {
+	title := "Select Directories"
+	if len(params) > 0 {
+		title = params[0]
+	}
+	result := r.renderer.SelectDirectories(title)
+	r.bookmark(result)
+	return result
+}
+
+// OpenDirectory prompts the user to select one or more directories, EG: a
+// workspace or a folder to export to, returning their paths. A nil result is
+// returned if the user cancels the dialog. Sandboxed macOS apps keep access
+// to the directories across launches.
+func (r *Dialog) OpenDirectory(options *DirectoryDialogOptions) ([]string, error) {
+	if options == nil {
+		options = &DirectoryDialogOptions{}
+	}
+	title := options.Title
+	if title == "" {
+		title = "Select Directory"
+		if options.Multiple {
+			title = "Select Directories"
+		}
+	}
+	directory, err := dialogDirectory(options.DefaultDirectory)
+	if err != nil {
+		return nil, err
+	}
+	result := r.renderer.SelectDirectoriesWithOptions(title, directory, options.Multiple)
+	r.bookmark(result)
+	return result, nil
+}
+
+// bookmark keeps access to the chosen directories across launches
+func (r *Dialog) bookmark(directories []string) {
+	if r.bookmarks == nil || len(directories) == 0 {
+		return
+	}
+	err := r.bookmarks.Add(directories...)
+	if err != nil && !errors.Is(err, ErrSubsystemDisabled) {
+		r.bookmarks.log.Errorf("Unable to bookmark directories: %s", err.Error())
+	}
+}
+
 // SelectSaveFile prompts the user to select a file for saving
 func (r *Dialog) SelectSaveFile(params ...string) string {
 	title := "Select Save"
@@ -45,3 +308,178 @@ func (r *Dialog) SelectSaveFile(params ...string) string {
 	}
 	return r.renderer.SelectSaveFile(title, filter)
 }
+
+// SaveFile prompts the user to select a file for saving and the format to save
+// it in. A nil result is returned if the user cancels the dialog.
+func (r *Dialog) SaveFile(options *SaveDialogOptions) (*SaveDialogResult, error) {
+	if options == nil {
+		options = &SaveDialogOptions{}
+	}
+	title := options.Title
+	if title == "" {
+		title = "Select Save"
+	}
+
+	directory, err := dialogDirectory(options.DefaultDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	// The directory is given separately
+	filename := options.DefaultFilename
+	if filename != "" {
+		filename = filepath.Base(filename)
+	}
+
+	var formats []string
+	for _, format := range options.Formats {
+		formats = append(formats, dialogFormat(format.Name, format.Extensions))
+	}
+
+	path, index := r.renderer.SelectSaveFileWithFormats(title, directory,
+		filename, strings.Join(formats, "\n"),
+		!options.DisableCreateDirectories, options.Overwrite == OverwritePrompt)
+	if path == "" {
+		return nil, nil
+	}
+
+	result := &SaveDialogResult{Path: path}
+	if index >= 0 && index < len(options.Formats) {
+		result.Format = &options.Formats[index]
+		if filepath.Ext(path) == "" && len(result.Format.Extensions) > 0 {
+			result.Path += "." + strings.TrimPrefix(result.Format.Extensions[0], ".")
+		}
+	}
+
+	if options.Overwrite == OverwriteRefuse {
+		if _, err := os.Stat(result.Path); err == nil {
+			return result, ErrFileExists
+		}
+	}
+	return result, nil
+}
+
+// ColorPicker prompts the user to choose a colour. The picker starts with the
+// given colour, which can take "#fff", "rgb(255,255,255)" or "rgba(255,255,255,1)"
+// formats and defaults to white. A nil result is returned if the user cancels.
+// The Windows picker does not support transparency.
+func (r *Dialog) ColorPicker(initial string) (*Colour, error) {
+	start := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	if initial != "" {
+		parsed, err := colors.Parse(initial)
+		if err != nil {
+			return nil, err
+		}
+		rgba := parsed.ToRGBA()
+		start = color.RGBA{R: rgba.R, G: rgba.G, B: rgba.B, A: uint8(255 * rgba.A)}
+	}
+
+	chosen, ok := r.renderer.ColorPicker("Select Colour", start)
+	if !ok {
+		return nil, nil
+	}
+	return &Colour{
+		R:   chosen.R,
+		G:   chosen.G,
+		B:   chosen.B,
+		A:   chosen.A,
+		Hex: fmt.Sprintf("#%02x%02x%02x%02x", chosen.R, chosen.G, chosen.B, chosen.A),
+	}, nil
+}
+
+// FontPicker prompts the user to choose a font, starting with the given
+// font if it has a family. A nil result is returned if the user cancels.
+func (r *Dialog) FontPicker(initial ...*Font) *Font {
+	var start interfaces.FontDescription
+	if len(initial) > 0 && initial[0] != nil && initial[0].Family != "" {
+		weight := initial[0].Weight
+		if weight == 0 && initial[0].Bold {
+			weight = 700
+		}
+		start = interfaces.FontDescription{
+			Family: initial[0].Family,
+			Size:   initial[0].Size,
+			Weight: fontWeight(weight),
+			Italic: initial[0].Italic,
+		}
+	}
+
+	chosen, ok := r.renderer.FontPicker("Select Font", start)
+	if !ok {
+		return nil
+	}
+	weight := fontWeight(chosen.Weight)
+	style := chosen.Face
+	if style == "" {
+		style = fontStyleName(weight, chosen.Italic)
+	}
+	return &Font{
+		Family: chosen.Family,
+		Style:  style,
+		Size:   chosen.Size,
+		Weight: weight,
+		Bold:   weight >= 700,
+		Italic: chosen.Italic,
+	}
+}
+
+// fontWeight keeps a weight within the CSS range, taking an unset weight
+// to be regular
+func fontWeight(weight int) int {
+	switch {
+	case weight <= 0:
+		return 400
+	case weight < 100:
+		return 100
+	case weight > 900:
+		return 900
+	}
+	return weight
+}
+
+// fontStyleName names a face by its weight and style, as most fonts do
+func fontStyleName(weight int, italic bool) string {
+	names := []string{"Thin", "ExtraLight", "Light", "Regular", "Medium", "SemiBold", "Bold", "ExtraBold", "Black"}
+	name := names[(weight+50)/100-1]
+	if !italic {
+		return name
+	}
+	if name == "Regular" {
+		return "Italic"
+	}
+	return name + " Italic"
+}
+
+// dialogFormat returns the "name|*.ext;*.ext" line the renderer takes for
+// a format or filter
+func dialogFormat(name string, extensions []string) string {
+	patterns := make([]string, len(extensions))
+	for index, extension := range extensions {
+		if extension == "*" {
+			patterns[index] = "*"
+			continue
+		}
+		patterns[index] = "*." + strings.TrimPrefix(extension, ".")
+	}
+	return name + "|" + strings.Join(patterns, ";")
+}
+
+// dialogDirectory returns the absolute path of the directory a dialog starts
+// in, or an error if it isn't a directory
+func dialogDirectory(directory string) (string, error) {
+	if directory == "" {
+		return "", nil
+	}
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(directory)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("'%s' is not a directory", directory)
+	}
+	return directory, nil
+}
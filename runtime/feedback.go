@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrHapticsUnsupported is returned by Haptic on platforms without haptic
+// feedback
+var ErrHapticsUnsupported = errors.New("haptic feedback is not supported on this platform")
+
+// BeepKind is the kind of event a beep signals, which picks the system sound
+type BeepKind string
+
+const (
+	// BeepDefault is the user's alert sound
+	BeepDefault BeepKind = "default"
+	// BeepInfo signals information, EG: a task finished
+	BeepInfo BeepKind = "info"
+	// BeepWarning signals something the user should check
+	BeepWarning BeepKind = "warning"
+	// BeepError signals that something failed
+	BeepError BeepKind = "error"
+	// BeepQuestion signals that the user needs to answer
+	BeepQuestion BeepKind = "question"
+)
+
+// HapticPattern is the feel of haptic feedback
+type HapticPattern string
+
+const (
+	// HapticGeneric is for feedback that doesn't fit the other patterns,
+	// EG: a confirmation
+	HapticGeneric HapticPattern = "generic"
+	// HapticAlignment is for something snapping into place while dragging
+	HapticAlignment HapticPattern = "alignment"
+	// HapticLevelChange is for a value passing a threshold, EG: a zoom level
+	HapticLevelChange HapticPattern = "level-change"
+)
+
+// Feedback plays the system's sounds and haptics, so apps can signal
+// confirmations and errors without bundling audio files
+type Feedback struct{}
+
+// NewFeedback creates a new Feedback struct
+func NewFeedback() *Feedback {
+	return &Feedback{}
+}
+
+// Beep plays the system sound for the given kind of event. On Linux, the
+// sound theme is played with canberra-gtk-play.
+func (r *Feedback) Beep(kind BeepKind) error {
+	if kind == "" {
+		kind = BeepDefault
+	}
+	if !isValidBeepKind(kind) {
+		return fmt.Errorf("unknown beep kind '%s'", kind)
+	}
+	return beep(kind)
+}
+
+// Haptic performs the given feedback on the trackpad. It is only supported
+// on MacOS, with a Force Touch trackpad, and returns ErrHapticsUnsupported
+// elsewhere.
+func (r *Feedback) Haptic(pattern HapticPattern) error {
+	if pattern == "" {
+		pattern = HapticGeneric
+	}
+	switch pattern {
+	case HapticGeneric, HapticAlignment, HapticLevelChange:
+		return haptic(pattern)
+	default:
+		return fmt.Errorf("unknown haptic pattern '%s'", pattern)
+	}
+}
+
+func isValidBeepKind(kind BeepKind) bool {
+	switch kind {
+	case BeepDefault, BeepInfo, BeepWarning, BeepError, BeepQuestion:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+
+#include <stdlib.h>
+#import <AppKit/AppKit.h>
+
+// Plays the named system sound, or the user's alert sound without a name
+static void playSound(const char *name) {
+	@autoreleasepool {
+		NSSound *sound = nil;
+		if (name[0] != '\0') {
+			sound = [NSSound soundNamed:[NSString stringWithUTF8String:name]];
+		}
+		if (sound == nil) {
+			NSBeep();
+			return;
+		}
+		[sound play];
+	}
+}
+
+static void performHaptic(int pattern) {
+	@autoreleasepool {
+		[[NSHapticFeedbackManager defaultPerformer]
+			performFeedbackPattern:(NSHapticFeedbackPattern)pattern
+			       performanceTime:NSHapticFeedbackPerformanceTimeNow];
+	}
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// The system sounds for each kind of beep. The default is the user's alert
+// sound.
+var beepSounds = map[BeepKind]string{
+	BeepDefault:  "",
+	BeepInfo:     "Glass",
+	BeepWarning:  "Funk",
+	BeepError:    "Basso",
+	BeepQuestion: "Tink",
+}
+
+// The NSHapticFeedbackPattern of each pattern
+var hapticPatterns = map[HapticPattern]int{
+	HapticGeneric:     0,
+	HapticAlignment:   1,
+	HapticLevelChange: 2,
+}
+
+func beep(kind BeepKind) error {
+	name := C.CString(beepSounds[kind])
+	defer C.free(unsafe.Pointer(name))
+	C.playSound(name)
+	return nil
+}
+
+func haptic(pattern HapticPattern) error {
+	C.performHaptic(C.int(hapticPatterns[pattern]))
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// The sounds of the freedesktop sound theme for each kind of beep
+var beepSounds = map[BeepKind]string{
+	BeepDefault:  "bell",
+	BeepInfo:     "dialog-information",
+	BeepWarning:  "dialog-warning",
+	BeepError:    "dialog-error",
+	BeepQuestion: "dialog-question",
+}
+
+func beep(kind BeepKind) error {
+	player, err := exec.LookPath("canberra-gtk-play")
+	if err != nil {
+		return fmt.Errorf("unable to beep: canberra-gtk-play is not installed")
+	}
+	command := exec.Command(player, "--id", beepSounds[kind])
+	err = command.Start()
+	if err != nil {
+		return err
+	}
+	go command.Wait()
+	return nil
+}
+
+func haptic(pattern HapticPattern) error {
+	return ErrHapticsUnsupported
+}
@@ -0,0 +1,27 @@
+//go:build windows
+// +build windows
+
+package runtime
+
+var procMessageBeep = user32.NewProc("MessageBeep")
+
+// The MessageBeep types for each kind of beep
+var beepTypes = map[BeepKind]uintptr{
+	BeepDefault:  0x00000000, // MB_OK
+	BeepInfo:     0x00000040, // MB_ICONINFORMATION
+	BeepWarning:  0x00000030, // MB_ICONWARNING
+	BeepError:    0x00000010, // MB_ICONERROR
+	BeepQuestion: 0x00000020, // MB_ICONQUESTION
+}
+
+func beep(kind BeepKind) error {
+	result, _, err := procMessageBeep.Call(beepTypes[kind])
+	if result == 0 {
+		return err
+	}
+	return nil
+}
+
+func haptic(pattern HapticPattern) error {
+	return ErrHapticsUnsupported
+}
@@ -0,0 +1,18 @@
+package runtime
+
+// FileDropEvent is emitted with a FileDrop when files are dragged from the
+// desktop and dropped on a window. The page's drop event only has the
+// files' contents, not their paths.
+const FileDropEvent = "wails:file-drop"
+
+// FileDrop is the files dropped on a window
+type FileDrop struct {
+	// The files' absolute paths
+	Files []string `json:"files"`
+
+	// Where the files were dropped, in the page's coordinates, so the
+	// element they were dropped on can be found with
+	// document.elementFromPoint
+	X int `json:"x"`
+	Y int `json:"y"`
+}
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"sort"
+
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// FontFamily is an installed font family and the styles it is available in
+type FontFamily struct {
+	Name   string   `json:"name"`
+	Styles []string `json:"styles"`
+}
+
+// fontEntry is a single installed font as reported by the platform
+type fontEntry struct {
+	family string
+	style  string
+}
+
+// Fonts exposes the fonts installed on the system
+type Fonts struct {
+	log      *logger.CustomLogger
+	disabled error
+}
+
+// NewFonts creates a new Fonts struct
+func NewFonts() *Fonts {
+	return &Fonts{
+		log: logger.NewCustomLogger("Fonts"),
+	}
+}
+
+// List returns the sorted names of the installed font families
+func (f *Fonts) List() ([]string, error) {
+	families, err := f.Families()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, len(families))
+	for index, family := range families {
+		result[index] = family.Name
+	}
+	return result, nil
+}
+
+// Families returns the installed font families, sorted by name, with their styles
+func (f *Fonts) Families() ([]*FontFamily, error) {
+	if f.disabled != nil {
+		return nil, f.disabled
+	}
+	entries, err := installedFonts()
+	if err != nil {
+		f.log.Errorf("Unable to list fonts: %s", err.Error())
+		return nil, err
+	}
+
+	families := make(map[string]*FontFamily)
+	seen := make(map[fontEntry]bool)
+	for _, entry := range entries {
+		if entry.family == "" || seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		family, exists := families[entry.family]
+		if !exists {
+			family = &FontFamily{Name: entry.family, Styles: []string{}}
+			families[entry.family] = family
+		}
+		if entry.style != "" {
+			family.Styles = append(family.Styles, entry.style)
+		}
+	}
+
+	result := make([]*FontFamily, 0, len(families))
+	for _, family := range families {
+		sort.Strings(family.Styles)
+		result = append(result, family)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
@@ -0,0 +1,46 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+
+#import <AppKit/AppKit.h>
+#include <stdlib.h>
+
+// Returns the installed fonts as "family\tstyle" lines
+static char *installedFonts() {
+	@autoreleasepool {
+		NSFontManager *manager = [NSFontManager sharedFontManager];
+		NSMutableString *result = [NSMutableString string];
+		for (NSString *family in [manager availableFontFamilies]) {
+			for (NSArray *member in [manager availableMembersOfFontFamily:family]) {
+				[result appendFormat:@"%@\t%@\n", family, member[1]];
+			}
+		}
+		return strdup([result UTF8String]);
+	}
+}
+*/
+import "C"
+
+import (
+	"strings"
+	"unsafe"
+)
+
+func installedFonts() ([]fontEntry, error) {
+	list := C.installedFonts()
+	defer C.free(unsafe.Pointer(list))
+
+	var result []fontEntry
+	for _, line := range strings.Split(C.GoString(list), "\n") {
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) == 2 {
+			result = append(result, fontEntry{family: fields[0], style: fields[1]})
+		}
+	}
+	return result, nil
+}
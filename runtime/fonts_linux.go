@@ -0,0 +1,26 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Fonts are listed with fontconfig, which is installed alongside GTK
+func installedFonts() ([]fontEntry, error) {
+	output, err := exec.Command("fc-list", "--format", "%{family[0]}\t%{style[0]}\n").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []fontEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) == 2 {
+			result = append(result, fontEntry{family: fields[0], style: fields[1]})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+//go:build windows
+// +build windows
+
+package runtime
+
+import (
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+const fontsKey = `Software\Microsoft\Windows NT\CurrentVersion\Fonts`
+
+// fontStyleWords are the words that end registry font names to give the
+// style, EG: "Segoe UI Semibold Italic (TrueType)"
+var fontStyleWords = map[string]bool{
+	"Thin": true, "ExtraLight": true, "Light": true, "Semilight": true,
+	"SemiLight": true, "Regular": true, "Medium": true, "Semibold": true,
+	"SemiBold": true, "Bold": true, "ExtraBold": true, "Black": true,
+	"Heavy": true, "Italic": true, "Oblique": true, "Condensed": true,
+}
+
+// Fonts are listed from the registry, which names each font file installed
+// for all users or the current user, EG: "Arial Bold Italic (TrueType)"
+func installedFonts() ([]fontEntry, error) {
+	var result []fontEntry
+	for _, root := range []registry.Key{registry.LOCAL_MACHINE, registry.CURRENT_USER} {
+		key, err := registry.OpenKey(root, fontsKey, registry.QUERY_VALUE)
+		if err != nil {
+			if root == registry.LOCAL_MACHINE {
+				return nil, err
+			}
+			continue
+		}
+		names, err := key.ReadValueNames(-1)
+		key.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			result = append(result, parseRegistryFontName(name)...)
+		}
+	}
+	return result, nil
+}
+
+// parseRegistryFontName returns the fonts in a registry font name.
+// Font collections list each font separated by " & ".
+func parseRegistryFontName(name string) []fontEntry {
+	if index := strings.LastIndex(name, " ("); index > 0 {
+		name = name[:index]
+	}
+
+	var result []fontEntry
+	for _, font := range strings.Split(name, " & ") {
+		words := strings.Fields(font)
+		split := len(words)
+		for split > 1 && fontStyleWords[words[split-1]] {
+			split--
+		}
+		style := strings.Join(words[split:], " ")
+		if style == "" {
+			style = "Regular"
+		}
+		result = append(result, fontEntry{family: strings.Join(words[:split], " "), style: style})
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package runtime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+const (
+	// GamepadConnectedEvent is emitted with the Gamepad when one is
+	// connected, while they're being watched
+	GamepadConnectedEvent = "wails:gamepad:connected"
+
+	// GamepadDisconnectedEvent is emitted with the last state of the Gamepad
+	// when one is disconnected, while they're being watched
+	GamepadDisconnectedEvent = "wails:gamepad:disconnected"
+
+	// GamepadChangedEvent is emitted with the Gamepad when its buttons or
+	// axes change, while they're being watched
+	GamepadChangedEvent = "wails:gamepad:changed"
+)
+
+// How often the gamepads are read while they're being watched, which is
+// once a frame at 60fps
+var gamepadPollInterval = 16 * time.Millisecond
+
+// GamepadButton is the state of a button or trigger
+type GamepadButton struct {
+	Pressed bool `json:"pressed"`
+
+	// From 0 to 1, for the analog triggers
+	Value float64 `json:"value"`
+}
+
+// Gamepad is the state of a game controller, in the layout of the web's
+// Gamepad API
+type Gamepad struct {
+	// The controller's slot, which is kept while it's connected
+	Index int `json:"index"`
+
+	// The controller's name, EG: "Xbox Wireless Controller"
+	ID string `json:"id"`
+
+	// "standard" when the buttons and axes are in the standard layout, or ""
+	// when they're in the controller's own order, as on Linux
+	Mapping string `json:"mapping"`
+
+	Buttons []GamepadButton `json:"buttons"`
+
+	// From -1 to 1, with left and up being negative
+	Axes []float64 `json:"axes"`
+
+	// When the gamepad was read, in milliseconds since the Unix epoch
+	Timestamp int64 `json:"timestamp"`
+}
+
+// sameInputs returns true if the gamepads' buttons and axes are the same
+func (g *Gamepad) sameInputs(other *Gamepad) bool {
+	if len(g.Buttons) != len(other.Buttons) || len(g.Axes) != len(other.Axes) {
+		return false
+	}
+	for index, button := range g.Buttons {
+		if button != other.Buttons[index] {
+			return false
+		}
+	}
+	for index, axis := range g.Axes {
+		if axis != other.Axes[index] {
+			return false
+		}
+	}
+	return true
+}
+
+// standardGamepadButtons is the number of buttons in the standard layout:
+// A, B, X, Y, the bumpers, the triggers, back, start, the sticks, the
+// D-pad and the guide button
+const standardGamepadButtons = 17
+
+// Gamepads reads the connected game controllers from Go, for webviews
+// without the Gamepad API, EG: some WebKitGTK builds. Windows reads XInput
+// controllers, Linux reads the joystick devices and MacOS reads the
+// controllers supported by the GameController framework.
+type Gamepads struct {
+	eventManager interfaces.EventManager
+	log          *logger.CustomLogger
+	lock         sync.Mutex
+	stop         chan struct{}
+}
+
+// NewGamepads creates a new Gamepads struct
+func NewGamepads(eventManager interfaces.EventManager) *Gamepads {
+	return &Gamepads{
+		eventManager: eventManager,
+		log:          logger.NewCustomLogger("Gamepads"),
+	}
+}
+
+// List returns the state of the connected gamepads
+func (r *Gamepads) List() ([]*Gamepad, error) {
+	gamepads, err := readGamepads()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	for _, gamepad := range gamepads {
+		gamepad.Timestamp = now
+	}
+	return gamepads, nil
+}
+
+// Watch emits GamepadConnectedEvent and GamepadDisconnectedEvent when
+// gamepads are plugged in and out, and GamepadChangedEvent when they're
+// used, until StopWatching is called
+func (r *Gamepads) Watch() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	r.stop = stop
+	go func() {
+		connected := map[int]*Gamepad{}
+		ticker := time.NewTicker(gamepadPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				gamepads, err := r.List()
+				if err != nil {
+					r.log.Errorf("Unable to read gamepads: %s", err.Error())
+					continue
+				}
+				current := map[int]*Gamepad{}
+				for _, gamepad := range gamepads {
+					current[gamepad.Index] = gamepad
+					last, ok := connected[gamepad.Index]
+					switch {
+					case !ok:
+						r.log.Debugf("Gamepad connected: %s", gamepad.ID)
+						r.eventManager.Emit(GamepadConnectedEvent, gamepad)
+					case !gamepad.sameInputs(last):
+						r.eventManager.Emit(GamepadChangedEvent, gamepad)
+					}
+				}
+				for index, gamepad := range connected {
+					if current[index] == nil {
+						r.log.Debugf("Gamepad disconnected: %s", gamepad.ID)
+						r.eventManager.Emit(GamepadDisconnectedEvent, gamepad)
+					}
+				}
+				connected = current
+			}
+		}
+	}()
+}
+
+// StopWatching stops the events started with Watch
+func (r *Gamepads) StopWatching() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
@@ -0,0 +1,100 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation -framework GameController
+
+#import <Foundation/Foundation.h>
+#import <GameController/GameController.h>
+
+#define MAX_GAMEPADS 4
+#define GAMEPAD_BUTTONS 17
+
+typedef struct {
+	char name[128];
+	int index;
+	float buttons[GAMEPAD_BUTTONS];
+	float axes[4];
+} gamepadState;
+
+// Returns the number of extended gamepads read into the states, with their
+// buttons in the standard layout
+static int readGamepads(gamepadState *states) {
+	@autoreleasepool {
+		int count = 0;
+		NSArray *controllers = [GCController controllers];
+		for (NSUInteger i = 0; i < [controllers count] && count < MAX_GAMEPADS; i++) {
+			GCController *controller = controllers[i];
+			GCExtendedGamepad *pad = [controller extendedGamepad];
+			if (pad == nil) {
+				continue;
+			}
+			gamepadState *state = &states[count++];
+			memset(state, 0, sizeof(gamepadState));
+			NSString *vendor = [controller vendorName] ?: @"Game Controller";
+			strlcpy(state->name, [vendor UTF8String], sizeof(state->name));
+			state->index = (int)i;
+
+			GCControllerButtonInput *buttons[GAMEPAD_BUTTONS] = {
+				pad.buttonA, pad.buttonB, pad.buttonX, pad.buttonY,
+				pad.leftShoulder, pad.rightShoulder, pad.leftTrigger, pad.rightTrigger,
+				nil, nil, nil, nil,
+				pad.dpad.up, pad.dpad.down, pad.dpad.left, pad.dpad.right,
+				nil,
+			};
+			if (@available(macOS 10.14.1, *)) {
+				buttons[10] = pad.leftThumbstickButton;
+				buttons[11] = pad.rightThumbstickButton;
+			}
+			if (@available(macOS 10.15, *)) {
+				buttons[8] = pad.buttonOptions;
+				buttons[9] = pad.buttonMenu;
+			}
+			if (@available(macOS 11.0, *)) {
+				buttons[16] = pad.buttonHome;
+			}
+			for (int b = 0; b < GAMEPAD_BUTTONS; b++) {
+				state->buttons[b] = buttons[b] != nil ? buttons[b].value : 0;
+			}
+
+			// The Y axes are up, so they're flipped
+			state->axes[0] = pad.leftThumbstick.xAxis.value;
+			state->axes[1] = -pad.leftThumbstick.yAxis.value;
+			state->axes[2] = pad.rightThumbstick.xAxis.value;
+			state->axes[3] = -pad.rightThumbstick.yAxis.value;
+		}
+		return count;
+	}
+}
+*/
+import "C"
+
+// The GameController framework supports Xbox, PlayStation and MFi
+// controllers, which are in the standard layout
+func readGamepads() ([]*Gamepad, error) {
+	var states [C.MAX_GAMEPADS]C.gamepadState
+	count := int(C.readGamepads(&states[0]))
+	result := make([]*Gamepad, count)
+	for i := 0; i < count; i++ {
+		state := states[i]
+		gamepad := &Gamepad{
+			Index:   int(state.index),
+			ID:      C.GoString(&state.name[0]),
+			Mapping: "standard",
+			Buttons: make([]GamepadButton, standardGamepadButtons),
+			Axes:    make([]float64, len(state.axes)),
+		}
+		for index := range gamepad.Buttons {
+			value := float64(state.buttons[index])
+			gamepad.Buttons[index] = GamepadButton{Pressed: value > 0.1, Value: value}
+		}
+		for index := range gamepad.Axes {
+			gamepad.Axes[index] = float64(state.axes[index])
+		}
+		result[i] = gamepad
+	}
+	return result, nil
+}
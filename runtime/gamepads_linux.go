@@ -0,0 +1,154 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// The types of a js_event
+const (
+	jsEventButton = 0x01
+	jsEventAxis   = 0x02
+	// Set on the events sent for the current state when a device is opened
+	jsEventInit = 0x80
+)
+
+// The joystick ioctls reading a device's name, JSIOCGNAME(128), and its
+// number of axes and buttons
+const (
+	jsNameLength  = 128
+	jsIOCGAXES    = 2<<30 | 1<<16 | 'j'<<8 | 0x11
+	jsIOCGBUTTONS = 2<<30 | 1<<16 | 'j'<<8 | 0x12
+	jsIOCGNAME    = 2<<30 | jsNameLength<<16 | 'j'<<8 | 0x13
+)
+
+// joystick is an open joystick device and its state, which is updated from
+// the device's events
+type joystick struct {
+	fd      int
+	gamepad Gamepad
+}
+
+// The joystick devices stay open while they're connected, so only their
+// events need reading
+var (
+	joysticksLock sync.Mutex
+	joysticks     = map[string]*joystick{}
+)
+
+// openJoystick opens a joystick device, without blocking on its reads
+func openJoystick(path string) (*joystick, error) {
+	fd, err := syscall.Open(path, syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	var axes, buttons uint8
+	syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), jsIOCGAXES, uintptr(unsafe.Pointer(&axes)))
+	syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), jsIOCGBUTTONS, uintptr(unsafe.Pointer(&buttons)))
+	name := make([]byte, jsNameLength)
+	syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), jsIOCGNAME, uintptr(unsafe.Pointer(&name[0])))
+
+	index, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(path), "js"))
+	return &joystick{
+		fd: fd,
+		gamepad: Gamepad{
+			Index:   index,
+			ID:      strings.TrimRight(string(name), "\x00"),
+			Buttons: make([]GamepadButton, buttons),
+			Axes:    make([]float64, axes),
+		},
+	}, nil
+}
+
+// read applies the device's pending events to its state. Returns false if
+// the device has been unplugged.
+func (j *joystick) read() bool {
+	// Each js_event is 8 bytes: the time, the value, the type and the number
+	buffer := make([]byte, 8*64)
+	for {
+		length, err := syscall.Read(j.fd, buffer)
+		if err == syscall.EAGAIN || err == syscall.EINTR {
+			return true
+		}
+		if err != nil || length <= 0 {
+			return false
+		}
+		for offset := 0; offset+8 <= length; offset += 8 {
+			value := int16(binary.LittleEndian.Uint16(buffer[offset+4:]))
+			number := int(buffer[offset+7])
+			switch buffer[offset+6] &^ jsEventInit {
+			case jsEventButton:
+				if number < len(j.gamepad.Buttons) {
+					j.gamepad.Buttons[number] = GamepadButton{Pressed: value != 0}
+					if value != 0 {
+						j.gamepad.Buttons[number].Value = 1
+					}
+				}
+			case jsEventAxis:
+				if number < len(j.gamepad.Axes) {
+					j.gamepad.Axes[number] = float64(value) / 32767
+					if value == -32768 {
+						j.gamepad.Axes[number] = -1
+					}
+				}
+			}
+		}
+	}
+}
+
+// state returns a copy of the joystick's state
+func (j *joystick) state() *Gamepad {
+	result := j.gamepad
+	result.Buttons = append([]GamepadButton{}, j.gamepad.Buttons...)
+	result.Axes = append([]float64{}, j.gamepad.Axes...)
+	return &result
+}
+
+// The joystick devices report buttons and axes in the controller's own
+// order, so they aren't in the standard layout. Reading them may need the
+// user to be in the input group.
+func readGamepads() ([]*Gamepad, error) {
+	joysticksLock.Lock()
+	defer joysticksLock.Unlock()
+
+	paths, err := filepath.Glob("/dev/input/js*")
+	if err != nil {
+		return nil, err
+	}
+	connected := map[string]bool{}
+	var result []*Gamepad
+	for _, path := range paths {
+		device := joysticks[path]
+		if device == nil {
+			device, err = openJoystick(path)
+			if err != nil {
+				continue
+			}
+			joysticks[path] = device
+		}
+		if !device.read() {
+			continue
+		}
+		connected[path] = true
+		result = append(result, device.state())
+	}
+	for path, device := range joysticks {
+		if !connected[path] {
+			syscall.Close(device.fd)
+			delete(joysticks, path)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Index < result[j].Index
+	})
+	return result, nil
+}
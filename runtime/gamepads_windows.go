@@ -0,0 +1,131 @@
+//go:build windows
+// +build windows
+
+package runtime
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// XInput supports four controllers
+const xinputUserCount = 4
+
+// errorDeviceNotConnected is returned by XInputGetState for empty slots
+const errorDeviceNotConnected = 1167
+
+// xinput1_4 ships with Windows 8 and later, and xinput9_1_0 with Windows 7
+var (
+	xinput                = windows.NewLazySystemDLL("xinput1_4.dll")
+	xinputLegacy          = windows.NewLazySystemDLL("xinput9_1_0.dll")
+	xinputOnce            sync.Once
+	xinputErr             error
+	procXInputGetState    *windows.LazyProc
+	xinputButtonsStandard = []uint16{
+		0x1000, // A
+		0x2000, // B
+		0x4000, // X
+		0x8000, // Y
+		0x0100, // Left shoulder
+		0x0200, // Right shoulder
+		0,      // Left trigger
+		0,      // Right trigger
+		0x0020, // Back
+		0x0010, // Start
+		0x0040, // Left thumb
+		0x0080, // Right thumb
+		0x0001, // D-pad up
+		0x0002, // D-pad down
+		0x0004, // D-pad left
+		0x0008, // D-pad right
+		0,      // Guide, which XInput doesn't report
+	}
+)
+
+// xinputState is XINPUT_STATE
+type xinputState struct {
+	PacketNumber uint32
+	Buttons      uint16
+	LeftTrigger  uint8
+	RightTrigger uint8
+	ThumbLX      int16
+	ThumbLY      int16
+	ThumbRX      int16
+	ThumbRY      int16
+}
+
+// xinputGetState returns XInputGetState from the newest XInput available
+func xinputGetState() (*windows.LazyProc, error) {
+	xinputOnce.Do(func() {
+		if xinputErr = xinput.Load(); xinputErr == nil {
+			procXInputGetState = xinput.NewProc("XInputGetState")
+		} else if xinputErr = xinputLegacy.Load(); xinputErr == nil {
+			procXInputGetState = xinputLegacy.NewProc("XInputGetState")
+		}
+	})
+	if xinputErr != nil {
+		return nil, fmt.Errorf("XInput is not available: %s", xinputErr)
+	}
+	return procXInputGetState, nil
+}
+
+// XInput controllers are in the standard layout. Their Y axes are up, so
+// they're flipped.
+func readGamepads() ([]*Gamepad, error) {
+	getState, err := xinputGetState()
+	if err != nil {
+		return nil, err
+	}
+	var result []*Gamepad
+	for user := 0; user < xinputUserCount; user++ {
+		var state xinputState
+		code, _, _ := getState.Call(uintptr(user), uintptr(unsafe.Pointer(&state)))
+		if code == errorDeviceNotConnected {
+			continue
+		}
+		if code != 0 {
+			return nil, windows.Errno(code)
+		}
+		gamepad := &Gamepad{
+			Index:   user,
+			ID:      "Xbox 360 Controller (XInput STANDARD GAMEPAD)",
+			Mapping: "standard",
+			Buttons: make([]GamepadButton, standardGamepadButtons),
+			Axes: []float64{
+				thumbAxis(state.ThumbLX),
+				-thumbAxis(state.ThumbLY),
+				thumbAxis(state.ThumbRX),
+				-thumbAxis(state.ThumbRY),
+			},
+		}
+		for index, mask := range xinputButtonsStandard {
+			if mask != 0 && state.Buttons&mask != 0 {
+				gamepad.Buttons[index] = GamepadButton{Pressed: true, Value: 1}
+			}
+		}
+		gamepad.Buttons[6] = triggerButton(state.LeftTrigger)
+		gamepad.Buttons[7] = triggerButton(state.RightTrigger)
+		result = append(result, gamepad)
+	}
+	return result, nil
+}
+
+// thumbAxis scales a thumbstick's position to -1 to 1
+func thumbAxis(value int16) float64 {
+	if value < 0 {
+		return float64(value) / 32768
+	}
+	return float64(value) / 32767
+}
+
+// triggerButton returns a trigger's state. It's pressed past XInput's
+// threshold.
+func triggerButton(value uint8) GamepadButton {
+	return GamepadButton{
+		Pressed: value > 30,
+		Value:   float64(value) / 255,
+	}
+}
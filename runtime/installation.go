@@ -0,0 +1,76 @@
+package runtime
+
+import "errors"
+
+// ErrMoveUnsupported is returned by MoveToApplications on platforms other
+// than MacOS
+var ErrMoveUnsupported = errors.New("moving the app to the Applications folder is only supported on MacOS")
+
+// Installation is where the app is running from, and whether MacOS is
+// restricting it because it was downloaded
+type Installation struct {
+	// The app bundle or, outside a bundle, the executable
+	Path string `json:"path"`
+
+	// Where the app was opened from, EG: the Downloads folder. It differs
+	// from Path when the app is translocated.
+	OriginalPath string `json:"originalPath"`
+
+	// True when MacOS runs the app from a random, read-only copy, because
+	// it was opened from where it was downloaded. Files next to the app
+	// aren't found and updates can't be installed.
+	Translocated bool `json:"translocated"`
+
+	// True when the app is marked as downloaded from the internet
+	Quarantined bool `json:"quarantined"`
+
+	// True when the app is in the Applications folder, or the user's
+	InApplications bool `json:"inApplications"`
+}
+
+// Installation returns where the app is running from, and whether MacOS
+// has translocated or quarantined it
+func (r *System) Installation() (*Installation, error) {
+	return readInstallation()
+}
+
+// MoveToApplications moves the app to the Applications folder and launches
+// it from there once this instance quits, which it should do straight after.
+// Returns the app's new path. An older copy in the Applications folder is
+// moved to the trash.
+func (r *System) MoveToApplications() (string, error) {
+	installation, err := readInstallation()
+	if err != nil {
+		return "", err
+	}
+	if installation.InApplications {
+		return installation.OriginalPath, nil
+	}
+	r.log.Infof("Moving %s to the Applications folder", installation.OriginalPath)
+	return moveToApplications(installation.OriginalPath)
+}
+
+// OfferMoveToApplications asks the user to move the app to the Applications
+// folder with a native dialog, when MacOS has translocated it. Returns true
+// if it was moved and relaunched, so this instance should quit.
+func (r *System) OfferMoveToApplications() (bool, error) {
+	installation, err := readInstallation()
+	if err != nil {
+		return false, err
+	}
+	if !installation.Translocated || installation.InApplications {
+		return false, nil
+	}
+	name := "The app"
+	if r.config != nil && r.config.GetTitle() != "" {
+		name = r.config.GetTitle()
+	}
+	if !askToMoveToApplications(name) {
+		return false, nil
+	}
+	_, err = r.MoveToApplications()
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,185 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+
+#import <AppKit/AppKit.h>
+#include <dlfcn.h>
+#include <stdlib.h>
+#include <sys/xattr.h>
+
+// Returns the app bundle's path, or the executable's outside a bundle
+static char *bundlePath() {
+	@autoreleasepool {
+		NSBundle *bundle = [NSBundle mainBundle];
+		NSString *path = [bundle bundlePath];
+		if (![[path pathExtension] isEqualToString:@"app"]) {
+			path = [bundle executablePath];
+		}
+		return strdup([path fileSystemRepresentation]);
+	}
+}
+
+// Returns where the translocated path was opened from, or NULL if it isn't
+// translocated. SecTranslocate isn't in the public headers, so it's loaded
+// at runtime.
+static char *translocatedFrom(const char *path) {
+	void *security = dlopen("/System/Library/Frameworks/Security.framework/Security", RTLD_LAZY);
+	if (security == NULL) {
+		return NULL;
+	}
+	Boolean (*isTranslocated)(CFURLRef, bool *, CFErrorRef *) = dlsym(security, "SecTranslocateIsTranslocatedURL");
+	CFURLRef (*originalPath)(CFURLRef, CFErrorRef *) = dlsym(security, "SecTranslocateCreateOriginalPathForURL");
+	if (isTranslocated == NULL || originalPath == NULL) {
+		return NULL;
+	}
+	char *result = NULL;
+	@autoreleasepool {
+		NSURL *url = [NSURL fileURLWithPath:[NSString stringWithUTF8String:path]];
+		bool translocated = false;
+		if (isTranslocated((CFURLRef)url, &translocated, NULL) && translocated) {
+			CFURLRef original = originalPath((CFURLRef)url, NULL);
+			if (original != NULL) {
+				result = strdup([[(NSURL *)original path] fileSystemRepresentation]);
+				CFRelease(original);
+			}
+		}
+	}
+	return result;
+}
+
+static int isQuarantined(const char *path) {
+	return getxattr(path, "com.apple.quarantine", NULL, 0, 0, 0) >= 0;
+}
+
+// Returns the Applications folder the user can write to
+static char *applicationsFolder() {
+	@autoreleasepool {
+		NSFileManager *manager = [NSFileManager defaultManager];
+		NSString *folder = @"/Applications";
+		if (![manager isWritableFileAtPath:folder]) {
+			folder = [NSHomeDirectory() stringByAppendingPathComponent:@"Applications"];
+			[manager createDirectoryAtPath:folder withIntermediateDirectories:YES attributes:nil error:nil];
+		}
+		return strdup([folder fileSystemRepresentation]);
+	}
+}
+
+// Copies the bundle to the destination, moving an older copy to the trash,
+// and removes the original where it can, EG: not from a disk image. Returns
+// the error's description, or NULL.
+static char *moveBundle(const char *source, const char *destination) {
+	@autoreleasepool {
+		NSFileManager *manager = [NSFileManager defaultManager];
+		NSString *from = [manager stringWithFileSystemRepresentation:source length:strlen(source)];
+		NSString *to = [manager stringWithFileSystemRepresentation:destination length:strlen(destination)];
+		NSError *error = nil;
+		if ([manager fileExistsAtPath:to]) {
+			if (![manager trashItemAtURL:[NSURL fileURLWithPath:to] resultingItemURL:nil error:&error]) {
+				return strdup([[error localizedDescription] UTF8String]);
+			}
+		}
+		if (![manager copyItemAtPath:from toPath:to error:&error]) {
+			return strdup([[error localizedDescription] UTF8String]);
+		}
+		[manager removeItemAtPath:from error:nil];
+		return NULL;
+	}
+}
+
+// Returns 1 if the user chose to move the app
+static int askToMove(const char *name) {
+	@autoreleasepool {
+		[NSApplication sharedApplication];
+		NSString *app = [NSString stringWithUTF8String:name];
+		NSAlert *alert = [[NSAlert alloc] init];
+		[alert setMessageText:@"Move to Applications folder?"];
+		[alert setInformativeText:[NSString stringWithFormat:@"%@ is running from where it was downloaded, "
+			"which stops it from finding its files and updating itself. "
+			"Move it to the Applications folder to fix this.", app]];
+		[alert addButtonWithTitle:@"Move to Applications Folder"];
+		[alert addButtonWithTitle:@"Do Not Move"];
+		[NSApp activateIgnoringOtherApps:YES];
+		NSModalResponse response = [alert runModal];
+		[alert release];
+		return response == NSAlertFirstButtonReturn;
+	}
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// relaunchScript opens the moved app once this instance has quit
+const relaunchScript = `while kill -0 "$1" 2>/dev/null; do sleep 0.2; done; /usr/bin/open "$2"`
+
+func readInstallation() (*Installation, error) {
+	path := C.bundlePath()
+	defer C.free(unsafe.Pointer(path))
+	result := &Installation{
+		Path: C.GoString(path),
+	}
+	result.OriginalPath = result.Path
+	if original := C.translocatedFrom(path); original != nil {
+		result.OriginalPath = C.GoString(original)
+		C.free(unsafe.Pointer(original))
+		result.Translocated = true
+	}
+
+	original := C.CString(result.OriginalPath)
+	defer C.free(unsafe.Pointer(original))
+	result.Quarantined = C.isQuarantined(original) == 1
+
+	home, _ := os.UserHomeDir()
+	for _, folder := range []string{"/Applications", filepath.Join(home, "Applications")} {
+		if strings.HasPrefix(result.OriginalPath, folder+"/") {
+			result.InApplications = true
+		}
+	}
+	return result, nil
+}
+
+func moveToApplications(path string) (string, error) {
+	if filepath.Ext(path) != ".app" {
+		return "", errors.New("the app isn't running from an app bundle")
+	}
+	folder := C.applicationsFolder()
+	defer C.free(unsafe.Pointer(folder))
+	destination := filepath.Join(C.GoString(folder), filepath.Base(path))
+
+	source := C.CString(path)
+	defer C.free(unsafe.Pointer(source))
+	target := C.CString(destination)
+	defer C.free(unsafe.Pointer(target))
+	if message := C.moveBundle(source, target); message != nil {
+		defer C.free(unsafe.Pointer(message))
+		return "", errors.New(C.GoString(message))
+	}
+
+	// The user chose to move the app, so it no longer needs translocating
+	exec.Command("/usr/bin/xattr", "-d", "-r", "com.apple.quarantine", destination).Run()
+
+	err := exec.Command("/bin/sh", "-c", relaunchScript, "sh", strconv.Itoa(os.Getpid()), destination).Start()
+	if err != nil {
+		return "", err
+	}
+	return destination, nil
+}
+
+func askToMoveToApplications(name string) bool {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return C.askToMove(cname) == 1
+}
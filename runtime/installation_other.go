@@ -0,0 +1,26 @@
+//go:build !darwin
+// +build !darwin
+
+package runtime
+
+import "os"
+
+// Only MacOS translocates and quarantines apps
+func readInstallation() (*Installation, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return &Installation{
+		Path:         path,
+		OriginalPath: path,
+	}, nil
+}
+
+func moveToApplications(path string) (string, error) {
+	return "", ErrMoveUnsupported
+}
+
+func askToMoveToApplications(name string) bool {
+	return false
+}
@@ -13,6 +13,17 @@ import { Call } from './calls';
 
 window.backend = {};
 
+/**
+ * Determines if the given value is an AbortSignal, or behaves like one
+ *
+ * @param {any} value
+ * @returns {boolean}
+ */
+function isAbortSignal(value) {
+	return value != null && typeof value === 'object' &&
+		typeof value.aborted === 'boolean' && typeof value.addEventListener === 'function';
+}
+
 /**
  * Determines if the given identifier is valid Javascript
  *
@@ -73,10 +84,23 @@ export function NewBinding(bindingName) {
 		// No timeout by default
 		var timeout = 0;
 
-		// Actual function
+		// The global retry options by default
+		var retry = null;
+
+		// Actual function. An AbortSignal given after the arguments
+		// cancels the call, and a function given before it is called with
+		// the results the method streams.
 		function dynamic() {
 			var args = [].slice.call(arguments);
-			return Call(bindingName, args, timeout);
+			var signal = null;
+			var onData = null;
+			if (args.length > 0 && isAbortSignal(args[args.length - 1])) {
+				signal = args.pop();
+			}
+			if (args.length > 0 && typeof args[args.length - 1] === 'function') {
+				onData = args.pop();
+			}
+			return Call(bindingName, args, timeout, retry, signal, onData);
 		}
 
 		// Allow setting timeout to function
@@ -89,6 +113,17 @@ export function NewBinding(bindingName) {
 			return timeout;
 		};
 
+		// Allow setting the retry options of the function, or null to
+		// use the global ones
+		dynamic.setRetry = function (newRetry) {
+			retry = newRetry;
+		};
+
+		// Allow getting the retry options of the function
+		dynamic.getRetry = function () {
+			return retry;
+		};
+
 		return dynamic;
 	}();
 }
@@ -0,0 +1,69 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Resolves to whether the user has allowed the app to access their
+ * calendars: 'granted', 'denied', 'not-determined' or 'unknown'
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+export function Status() {
+	return SystemCall('Calendar.Status');
+}
+
+/**
+ * Asks the user to allow the app to access their calendars, resolving to
+ * the status once they have answered
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+export function Request() {
+	return SystemCall('Calendar.Request');
+}
+
+/**
+ * Resolves to the events that overlap the given period, with id, calendar,
+ * title, notes, location, start, end and allDay fields
+ *
+ * @export
+ * @param {Date} start
+ * @param {Date} end
+ * @returns {Promise<Object[]>}
+ */
+export function Events(start, end) {
+	return SystemCall('Calendar.Events', { start: start, end: end });
+}
+
+/**
+ * Adds the event, or updates it if it has an id, resolving to its id
+ *
+ * @export
+ * @param {Object} event
+ * @returns {Promise<string>}
+ */
+export function SaveEvent(event) {
+	return SystemCall('Calendar.SaveEvent', event);
+}
+
+/**
+ * Removes the event with the given id
+ *
+ * @export
+ * @param {string} id
+ * @returns {Promise}
+ */
+export function DeleteEvent(id) {
+	return SystemCall('Calendar.DeleteEvent', id);
+}
@@ -10,10 +10,25 @@ The lightweight framework for web-like apps
 /* jshint esversion: 6 */
 
 import { Debug } from './log';
-import { SendMessage } from './ipc';
+import { SendMessage, EncodeBinary } from './ipc';
 
 var callbacks = {};
 
+// The size above which call data is sent to the backend in chunks.
+// The backend sets it from AppConfig.MaxPayloadSize. Off by default.
+var maxPayloadSize = 0;
+
+// The chunks of results too large to send in one message, by callback ID
+var chunks = {};
+
+// How calls are retried when they can't be sent to the backend, EG: while
+// the bridge reconnects after the backend restarts. Off by default.
+var retryOptions = {
+	retries: 0,
+	delay: 250,
+	maxDelay: 5000,
+};
+
 /**
  * Returns a number from the native browser random function
  *
@@ -45,6 +60,94 @@ if (window.crypto) {
 
 
 
+/**
+ * SetMaxPayloadSize sets the size above which call data is sent to the
+ * backend in chunks. Chunking is turned off if it is not positive.
+ *
+ * @export
+ * @param {number} size
+ */
+export function SetMaxPayloadSize(size) {
+	maxPayloadSize = size;
+}
+
+/**
+ * SetRetry sets how calls are retried when they can't be sent to the
+ * backend. Failed calls are queued and sent again up to `retries` times,
+ * waiting `delay` milliseconds before the first retry and doubling the wait
+ * after each one, up to `maxDelay`. Retries are turned off if `retries` is
+ * not positive. Bindings can override it with their setRetry method.
+ *
+ * @export
+ * @param {{retries: number, delay: number=, maxDelay: number=}} options
+ */
+export function SetRetry(options) {
+	retryOptions = retryDefaults(options);
+}
+
+/**
+ * retryDefaults fills in the options missing from the given retry options
+ *
+ * @param {{retries: number, delay: number=, maxDelay: number=}} options
+ * @returns {{retries: number, delay: number, maxDelay: number}}
+ */
+function retryDefaults(options) {
+	options = options || {};
+	return {
+		retries: options.retries > 0 ? options.retries : 0,
+		delay: options.delay >= 0 ? options.delay : 250,
+		maxDelay: options.maxDelay >= 0 ? options.maxDelay : 5000,
+	};
+}
+
+/**
+ * sendChunks sends the start of the call data ahead in chunks while it is
+ * too large to send in one message. Each chunk is removed from call.data
+ * once it is sent, so a retry carries on from the chunk that failed and
+ * the remainder is sent with the call. The backend joins them.
+ *
+ * @param {{data: string, callbackID: string}} call
+ */
+function sendChunks(call) {
+	while (call.data && maxPayloadSize > 0 && call.data.length > maxPayloadSize) {
+		var size = maxPayloadSize;
+
+		// Don't split a surrogate pair between chunks
+		var code = call.data.charCodeAt(size - 1);
+		if (size > 1 && code >= 0xD800 && code <= 0xDBFF) {
+			size--;
+		}
+		SendMessage('chunk', { data: call.data.slice(0, size) }, call.callbackID);
+		call.data = call.data.slice(size);
+	}
+}
+
+/**
+ * binaryArgs returns the arguments with those that are ArrayBuffers or
+ * typed arrays replaced by references to their bytes, which are added to
+ * binary, so they are sent without being encoded as JSON
+ *
+ * @param {any[]} args
+ * @param {Uint8Array[]} binary
+ * @returns {any[]}
+ */
+function binaryArgs(args, binary) {
+	if (!Array.isArray(args)) {
+		return args;
+	}
+	return args.map(function (arg) {
+		if (arg instanceof ArrayBuffer) {
+			arg = new Uint8Array(arg);
+		} else if (ArrayBuffer.isView(arg)) {
+			arg = new Uint8Array(arg.buffer, arg.byteOffset, arg.byteLength);
+		} else {
+			return arg;
+		}
+		binary.push(arg);
+		return { $binary: binary.length - 1 };
+	});
+}
+
 /**
  * Call sends a message to the backend to call the binding with the
  * given data. A promise is returned and will be completed when the
@@ -52,20 +155,35 @@ if (window.crypto) {
  * or rejected if an error is passed back.
  * There is a timeout mechanism. If the call doesn't respond in the given
  * time (in milliseconds) then the promise is rejected.
+ * If the call can't be sent, it is retried as set by SetRetry or the given
+ * retry options, and rejected once the retries run out.
+ * If the given AbortSignal is aborted, the promise is rejected with its
+ * reason. Calls that time out or are aborted are cancelled in the backend,
+ * which cancels the context given to the bound method, if it takes one.
+ * The results streamed by the bound method are passed to onData as they
+ * arrive, if given, and are otherwise returned as the call's result.
+ * Arguments that are ArrayBuffers or typed arrays are sent as bytes, which
+ * bound methods take as byte slices.
  *
  * @export
  * @param {string} bindingName
  * @param {string} data
  * @param {number=} timeout
+ * @param {{retries: number, delay: number=, maxDelay: number=}=} retry
+ * @param {AbortSignal=} signal
+ * @param {function(any)=} onData
  * @returns
  */
-export function Call(bindingName, data, timeout) {
+export function Call(bindingName, data, timeout, retry, signal, onData) {
 
 	// Timeout infinite by default
 	if (timeout == null || timeout == undefined) {
 		timeout = 0;
 	}
 
+	// Use the global retry options by default
+	retry = retry ? retryDefaults(retry) : retryOptions;
+
 	// Create a promise
 	return new Promise(function (resolve, reject) {
 
@@ -78,7 +196,7 @@ export function Call(bindingName, data, timeout) {
 		// Set timeout
 		if (timeout > 0) {
 			var timeoutHandle = setTimeout(function () {
-				reject(Error('Call to ' + bindingName + ' timed out. Request ID: ' + callbackID));
+				cancelCall(callbackID, Error('Call to ' + bindingName + ' timed out. Request ID: ' + callbackID));
 			}, timeout);
 		}
 
@@ -86,26 +204,128 @@ export function Call(bindingName, data, timeout) {
 		callbacks[callbackID] = {
 			timeoutHandle: timeoutHandle,
 			reject: reject,
-			resolve: resolve
+			resolve: resolve,
+			onData: onData
 		};
 
-		try {
-			const payload = {
-				bindingName: bindingName,
-				data: JSON.stringify(data),
+		// Cancel the call when the signal is aborted
+		if (signal) {
+			const cancelled = function () {
+				return signal.reason || Error('Call to ' + bindingName + ' was cancelled. Request ID: ' + callbackID);
+			};
+			if (signal.aborted) {
+				clearTimeout(timeoutHandle);
+				delete callbacks[callbackID];
+				reject(cancelled());
+				return;
+			}
+			const abort = function () {
+				cancelCall(callbackID, cancelled());
 			};
+			signal.addEventListener('abort', abort);
+			callbacks[callbackID].removeAbort = function () {
+				signal.removeEventListener('abort', abort);
+			};
+		}
+
+		// Encode the arguments once for every attempt
+		const bytes = [];
+		const json = JSON.stringify(binaryArgs(data, bytes));
+		const binary = bytes.length > 0 ? EncodeBinary(bytes) : null;
+		const unsent = { data: json, callbackID: callbackID };
+		var attempt = 0;
 
-			// Make the call
-			SendMessage('call', payload, callbackID);
-		} catch (e) {
-			// eslint-disable-next-line
-			console.error(e);
+		function send() {
+			try {
+				sendChunks(unsent);
+				const payload = {
+					bindingName: bindingName,
+					data: unsent.data,
+				};
+				if (onData) {
+					payload.stream = true;
+				}
+
+				// Make the call
+				SendMessage('call', payload, callbackID, binary);
+			} catch (e) {
+				if (attempt >= retry.retries) {
+					// eslint-disable-next-line
+					console.error(e);
+					if (retry.retries > 0) {
+						clearTimeout(timeoutHandle);
+						removeAbort(callbacks[callbackID]);
+						delete callbacks[callbackID];
+						reject(Error('Call to ' + bindingName + ' failed after ' + attempt + ' retries: ' + e.message));
+					}
+					return;
+				}
+
+				// Queue the call to be sent again after a backoff
+				const delay = Math.min(retry.delay * Math.pow(2, attempt), retry.maxDelay);
+				attempt++;
+				callbacks[callbackID].retryHandle = setTimeout(send, delay);
+			}
 		}
+		send();
 	});
 }
 
+/**
+ * removeAbort stops listening to the AbortSignal of a call, if it has one
+ *
+ * @param {object} callbackData
+ */
+function removeAbort(callbackData) {
+	if (callbackData && callbackData.removeAbort) {
+		callbackData.removeAbort();
+	}
+}
+
+/**
+ * cancelCall rejects the call with the given error and tells the backend
+ * to cancel it. Its result is ignored if it still arrives.
+ *
+ * @param {string} callbackID
+ * @param {Error} error
+ */
+function cancelCall(callbackID, error) {
+	var callbackData = callbacks[callbackID];
+	if (!callbackData || callbackData.cancelled) {
+		return;
+	}
+	clearTimeout(callbackData.timeoutHandle);
+	clearTimeout(callbackData.retryHandle);
+	removeAbort(callbackData);
+	callbacks[callbackID] = { cancelled: true };
+	callbackData.reject(error);
+
+	// The call may not have reached the backend, EG: while it is retried
+	try {
+		SendMessage('cancel', {}, callbackID);
+	} catch (e) {
+		delete callbacks[callbackID];
+	}
+}
+
 
 
+/**
+ * Parses a message from the backend
+ *
+ * @param {string} incomingMessage
+ * @returns {object}
+ */
+function parseMessage(incomingMessage) {
+	try {
+		return JSON.parse(incomingMessage);
+	} catch (e) {
+		const error = `Invalid JSON passed to callback: ${e.message}. Message: ${incomingMessage}`;
+		Debug(error);
+		throw new Error(error);
+	}
+}
+
 /**
  * Called by the backend to return data to a previously called
  * binding invocation
@@ -119,25 +339,89 @@ export function Callback(incomingMessage) {
 	incomingMessage = decodeURIComponent(incomingMessage.replace(/\s+/g, '').replace(/[0-9a-f]{2}/g, '%$&'));
 
 	// Parse the message
-	var message;
-	try {
-		message = JSON.parse(incomingMessage);
-	} catch (e) {
-		const error = `Invalid JSON passed to callback: ${e.message}. Message: ${incomingMessage}`;
-		Debug(error);
-		throw new Error(error);
+	var message = parseMessage(incomingMessage);
+	var callbackID = message.callbackid;
+
+	// Join the chunks of a large result until the last one arrives
+	if (message.chunk !== undefined) {
+		chunks[callbackID] = (chunks[callbackID] || '') + message.chunk;
+		if (message.more) {
+			return;
+		}
+		message = parseMessage(chunks[callbackID]);
+		delete chunks[callbackID];
 	}
+
+	resolveCallback(message);
+}
+
+/**
+ * Called by the backend to return a result that is binary, when it is sent
+ * to the webview. The header is the hex encoded response and the data is
+ * base64 encoded. The call is resolved with a Uint8Array.
+ *
+ * @export
+ * @param {string} header
+ * @param {string} data
+ */
+export function CallbackBinary(header, data) {
+	var message = parseMessage(decodeURIComponent(header.replace(/[0-9a-f]{2}/g, '%$&')));
+	var bytes = atob(data);
+	message.data = new Uint8Array(bytes.length);
+	for (var i = 0; i < bytes.length; i++) {
+		message.data[i] = bytes.charCodeAt(i);
+	}
+	resolveCallback(message);
+}
+
+/**
+ * Called by the bridge with a binary frame from the backend, holding a
+ * response followed by its data. The response is preceded by its length
+ * as a big-endian uint32. The call is resolved with a Uint8Array.
+ *
+ * @export
+ * @param {ArrayBuffer} frame
+ */
+export function CallbackFrame(frame) {
+	var size = new DataView(frame).getUint32(0);
+	var message = parseMessage(new TextDecoder().decode(new Uint8Array(frame, 4, size)));
+	message.data = new Uint8Array(frame, 4 + size);
+	resolveCallback(message);
+}
+
+/**
+ * resolveCallback settles the call the given response is for, or passes
+ * it on to its onData if it was streamed
+ *
+ * @param {object} message
+ */
+function resolveCallback(message) {
 	var callbackID = message.callbackid;
 	var callbackData = callbacks[callbackID];
+
+	// Pass on the results streamed before the call returns
+	if (message.stream) {
+		if (callbackData && callbackData.onData) {
+			callbackData.onData(message.data);
+		}
+		return;
+	}
+
 	if (!callbackData) {
 		const error = `Callback '${callbackID}' not registed!!!`;
 		console.error(error); // eslint-disable-line
 		throw new Error(error);
 	}
 	clearTimeout(callbackData.timeoutHandle);
+	removeAbort(callbackData);
 
 	delete callbacks[callbackID];
 
+	// The call was cancelled and its promise already rejected
+	if (callbackData.cancelled) {
+		return;
+	}
+
 	if (message.error) {
 		callbackData.reject(message.error);
 	} else {
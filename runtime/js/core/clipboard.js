@@ -0,0 +1,59 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+import { SystemCall } from './calls';
+
+/**
+ * Returns the text on the clipboard, or '' if it holds no text
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+export function GetText() {
+	return SystemCall('Clipboard.GetText');
+}
+
+/**
+ * Replaces the contents of the clipboard with the given text
+ *
+ * @export
+ * @param {string} text
+ * @returns {Promise}
+ */
+export function SetText(text) {
+	return SystemCall('Clipboard.SetText', String(text));
+}
+
+/**
+ * Returns the image on the clipboard as a PNG data URL, which can be used
+ * as an image's src, or null if it holds no image
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+export function GetImage() {
+	return SystemCall('Clipboard.GetImage').then(function (image) {
+		return image ? 'data:image/png;base64,' + image : null;
+	});
+}
+
+/**
+ * Replaces the contents of the clipboard with the given PNG image, as a
+ * data URL or base64
+ *
+ * @export
+ * @param {string} image
+ * @returns {Promise}
+ */
+export function SetImage(image) {
+	return SystemCall('Clipboard.SetImage', String(image).replace(/^data:image\/png;base64,/, ''));
+}
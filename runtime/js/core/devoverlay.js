@@ -0,0 +1,246 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+import { On, Emit } from './events';
+import { InjectCSS } from './utils';
+
+// The traces kept by the panel, oldest first
+const maxTraces = 1000;
+let traces = [];
+let lastSeq = 0;
+
+// Set once the shortcut is installed
+let enabled = false;
+
+// The panel, and its controls, once shown
+let panel = null;
+let list = null;
+let filter = null;
+let kind = null;
+let errorsOnly = null;
+let pauseButton = null;
+let paused = false;
+let poller = null;
+
+const css = `
+#wails-devoverlay { position: fixed; left: 0; right: 0; bottom: 0; height: 40%; z-index: 2147483647; display: flex; flex-direction: column; background: rgba(24, 24, 24, 0.96); color: #ddd; font: 12px/1.5 Menlo, Consolas, monospace; border-top: 1px solid #555; }
+#wails-devoverlay .toolbar { display: flex; align-items: center; padding: 4px 8px; border-bottom: 1px solid #444; }
+#wails-devoverlay .toolbar > * { margin-right: 8px; font: inherit; }
+#wails-devoverlay .toolbar input[type=text] { flex: 1; background: #111; color: #ddd; border: 1px solid #555; padding: 1px 4px; }
+#wails-devoverlay .list { flex: 1; overflow: auto; }
+#wails-devoverlay table { width: 100%; border-collapse: collapse; }
+#wails-devoverlay td { padding: 0 8px; white-space: nowrap; }
+#wails-devoverlay td.name { width: 100%; white-space: normal; word-break: break-all; }
+#wails-devoverlay td.number { text-align: right; }
+#wails-devoverlay tr.call { color: #9cdcfe; }
+#wails-devoverlay tr.event { color: #c5e1a5; }
+#wails-devoverlay tr.error { color: #f48771; }
+`;
+
+function pad(value, length) {
+	value = String(value);
+	while (value.length < length) {
+		value = '0' + value;
+	}
+	return value;
+}
+
+function formatTime(time) {
+	const date = new Date(time);
+	return pad(date.getHours(), 2) + ':' + pad(date.getMinutes(), 2) + ':' +
+		pad(date.getSeconds(), 2) + '.' + pad(date.getMilliseconds(), 3);
+}
+
+function formatSize(size) {
+	if (size >= 1024 * 1024) {
+		return (size / 1024 / 1024).toFixed(1) + ' MB';
+	}
+	if (size >= 1024) {
+		return (size / 1024).toFixed(1) + ' KB';
+	}
+	return size + ' B';
+}
+
+function cell(row, text, className) {
+	const td = document.createElement('td');
+	td.textContent = text;
+	if (className) {
+		td.className = className;
+	}
+	row.appendChild(td);
+}
+
+// Returns true if the trace is shown with the current filters
+function matches(trace) {
+	if (kind.value && trace.kind !== kind.value) {
+		return false;
+	}
+	if (errorsOnly.checked && !trace.error) {
+		return false;
+	}
+	const text = filter.value.toLowerCase();
+	return !text || trace.name.toLowerCase().indexOf(text) !== -1;
+}
+
+// Lists the traces that match the filters, keeping the list scrolled to the
+// bottom if it was
+function render() {
+	const atBottom = list.scrollTop + list.clientHeight >= list.scrollHeight - 4;
+	const table = document.createElement('table');
+	const body = document.createElement('tbody');
+	for (let i = 0; i < traces.length; i++) {
+		const trace = traces[i];
+		if (!matches(trace)) {
+			continue;
+		}
+		const row = document.createElement('tr');
+		row.className = trace.error ? 'error' : trace.kind;
+		row.title = trace.error || '';
+		cell(row, formatTime(trace.time));
+		if (trace.kind === 'call') {
+			cell(row, 'call');
+			cell(row, trace.name, 'name');
+			cell(row, trace.duration.toFixed(1) + ' ms', 'number');
+			cell(row, formatSize(trace.size) + ' → ' + formatSize(trace.resultSize), 'number');
+		} else {
+			cell(row, trace.source === 'frontend' ? 'event →' : 'event ←');
+			cell(row, trace.name, 'name');
+			cell(row, '', 'number');
+			cell(row, formatSize(trace.size), 'number');
+		}
+		body.appendChild(row);
+	}
+	table.appendChild(body);
+	list.innerHTML = '';
+	list.appendChild(table);
+	if (atBottom) {
+		list.scrollTop = list.scrollHeight;
+	}
+}
+
+function addTraces(newTraces) {
+	if (!newTraces || newTraces.length === 0) {
+		return;
+	}
+	traces = traces.concat(newTraces);
+	if (traces.length > maxTraces) {
+		traces = traces.slice(traces.length - maxTraces);
+	}
+	lastSeq = newTraces[newTraces.length - 1].seq;
+	if (panel && !paused) {
+		render();
+	}
+}
+
+function fetchTraces() {
+	Emit('wails:devoverlay:fetch', lastSeq);
+}
+
+function control(tag, properties) {
+	const element = document.createElement(tag);
+	for (const name in properties) {
+		element[name] = properties[name];
+	}
+	return element;
+}
+
+function createPanel() {
+	InjectCSS(css);
+	panel = control('div', { id: 'wails-devoverlay' });
+	const toolbar = control('div', { className: 'toolbar' });
+	toolbar.appendChild(control('strong', { textContent: 'Calls & Events' }));
+	filter = control('input', { type: 'text', placeholder: 'Filter by name' });
+	filter.addEventListener('input', render);
+	toolbar.appendChild(filter);
+	kind = control('select', {});
+	[['', 'All'], ['call', 'Calls'], ['event', 'Events']].forEach(function (option) {
+		kind.appendChild(control('option', { value: option[0], textContent: option[1] }));
+	});
+	kind.addEventListener('change', render);
+	toolbar.appendChild(kind);
+	const errorsLabel = control('label', {});
+	errorsOnly = control('input', { type: 'checkbox' });
+	errorsOnly.addEventListener('change', render);
+	errorsLabel.appendChild(errorsOnly);
+	errorsLabel.appendChild(document.createTextNode(' Errors'));
+	toolbar.appendChild(errorsLabel);
+	// Pausing keeps the list as it is to look back through it, while the
+	// traces are still collected
+	pauseButton = control('button', { textContent: 'Pause' });
+	pauseButton.addEventListener('click', function () {
+		paused = !paused;
+		pauseButton.textContent = paused ? 'Resume' : 'Pause';
+		if (!paused) {
+			render();
+		}
+	});
+	toolbar.appendChild(pauseButton);
+	const clearButton = control('button', { textContent: 'Clear' });
+	clearButton.addEventListener('click', function () {
+		traces = [];
+		render();
+	});
+	toolbar.appendChild(clearButton);
+	const closeButton = control('button', { textContent: '×', title: 'Close (Ctrl+Shift+D)' });
+	closeButton.addEventListener('click', ToggleDevOverlay);
+	toolbar.appendChild(closeButton);
+	panel.appendChild(toolbar);
+	list = control('div', { className: 'list' });
+	panel.appendChild(list);
+}
+
+/**
+ * Shows or hides the dev overlay, which lists the recent calls to bound
+ * methods and the events emitted by the frontend and the backend
+ *
+ * @export
+ */
+export function ToggleDevOverlay() {
+	if (!enabled) {
+		return;
+	}
+	if (!panel) {
+		createPanel();
+	}
+	if (panel.parentNode) {
+		document.body.removeChild(panel);
+		clearInterval(poller);
+		poller = null;
+		return;
+	}
+	document.body.appendChild(panel);
+	render();
+	list.scrollTop = list.scrollHeight;
+	fetchTraces();
+	poller = setInterval(fetchTraces, 500);
+}
+
+/**
+ * Lets the dev overlay be toggled with Ctrl+Shift+D, or Cmd+Shift+D on
+ * MacOS. Called by debug builds with the DevOverlay option each time the
+ * page is ready.
+ *
+ * @export
+ */
+export function EnableDevOverlay() {
+	if (enabled) {
+		return;
+	}
+	enabled = true;
+	On('wails:devoverlay:traces', addTraces);
+	document.addEventListener('keydown', function (event) {
+		if ((event.ctrlKey || event.metaKey) && event.shiftKey && (event.key === 'D' || event.key === 'd' || event.keyCode === 68)) {
+			event.preventDefault();
+			ToggleDevOverlay();
+		}
+	}, true);
+}
@@ -0,0 +1,108 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Opens the native colour picker, starting with the given colour.
+ * Resolves to an object with r, g, b, a and hex fields, or null
+ * if the user cancelled.
+ *
+ * @export
+ * @param {string} [initial] - EG: '#fff' or 'rgba(255,255,255,1)'
+ * @returns {Promise<Object>}
+ */
+export function ColorPicker(initial) {
+	return SystemCall('Dialog.ColorPicker', initial || '');
+}
+
+/**
+ * Opens the native font picker, starting with the given font if it has a
+ * family. Resolves to an object with family, style, size, weight, bold
+ * and italic fields, or null if the user cancelled.
+ *
+ * @export
+ * @param {Object} [initial] - EG: { family: 'Helvetica', size: 12, weight: 700 }
+ * @returns {Promise<Object>}
+ */
+export function FontPicker(initial) {
+	return SystemCall('Dialog.FontPicker', initial || null);
+}
+
+/**
+ * Opens the native file dialog. Options may have title, defaultDirectory,
+ * multiple and filters fields, with filters like
+ * [{ name: 'Images', extensions: ['png', 'jpg'] }]. Resolves to the paths
+ * of the chosen files, or null if the user cancelled.
+ *
+ * @export
+ * @param {Object} [options]
+ * @returns {Promise<string[]>}
+ */
+export function OpenFile(options) {
+	return SystemCall('Dialog.OpenFile', options || {});
+}
+
+/**
+ * Opens the native directory dialog. Options may have title,
+ * defaultDirectory and multiple fields. Resolves to the paths of the
+ * chosen directories, or null if the user cancelled.
+ *
+ * @export
+ * @param {Object} [options]
+ * @returns {Promise<string[]>}
+ */
+export function OpenDirectory(options) {
+	return SystemCall('Dialog.OpenDirectory', options || {});
+}
+
+/**
+ * Shows the platform's about dialog with the app's name, version,
+ * copyright and icon
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function About() {
+	return SystemCall('Dialog.About');
+}
+
+/**
+ * Shows a native message dialog of the given type, 'info', 'warning',
+ * 'error' or 'question', with the given button labels, or OK if there are
+ * none. Resolves to the label of the pressed button, or an empty string if
+ * the user dismissed the dialog.
+ *
+ * @export
+ * @param {string} type
+ * @param {string} title
+ * @param {string} message
+ * @param {string[]} [buttons]
+ * @returns {Promise<string>}
+ */
+export function Message(type, title, message, buttons) {
+	return SystemCall('Dialog.Message', { type, title, message, buttons: buttons || [] });
+}
+
+/**
+ * Opens the native save dialog. Options may have title, defaultDirectory,
+ * defaultFilename, formats, disableCreateDirectories and overwrite fields,
+ * with formats like [{ name: 'CSV', extensions: ['csv'] }] and overwrite
+ * 0 to ask, 1 to allow and 2 to refuse existing files. Resolves to an
+ * object with path and format fields, or null if the user cancelled.
+ *
+ * @export
+ * @param {Object} [options]
+ * @returns {Promise<Object>}
+ */
+export function SaveFile(options) {
+	return SystemCall('Dialog.SaveFile', options || {});
+}
@@ -0,0 +1,37 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+import { SystemCall } from './calls';
+
+/**
+ * Plays the system sound for the given kind of event: 'default', 'info',
+ * 'warning', 'error' or 'question'
+ *
+ * @export
+ * @param {string} [kind]
+ * @returns {Promise}
+ */
+export function Beep(kind) {
+	return SystemCall('Feedback.Beep', kind || 'default');
+}
+
+/**
+ * Performs haptic feedback on the trackpad: 'generic', 'alignment' or
+ * 'level-change'. Rejects on platforms other than MacOS.
+ *
+ * @export
+ * @param {string} [pattern]
+ * @returns {Promise}
+ */
+export function Haptic(pattern) {
+	return SystemCall('Feedback.Haptic', pattern || 'generic');
+}
@@ -0,0 +1,52 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Accepts files dragged from the desktop anywhere on the page, so their
+ * paths reach Go as the 'wails:file-drop' event, and stops the webview
+ * opening the files in place of the app. Pages handle drops on their own
+ * elements first.
+ *
+ * @export
+ */
+export function SetupFileDrop() {
+	var accept = function (event) {
+		if (!hasFiles(event) || event.defaultPrevented) {
+			return;
+		}
+		event.preventDefault();
+		if (event.type !== 'drop') {
+			event.dataTransfer.dropEffect = 'copy';
+		}
+	};
+	window.addEventListener('dragenter', accept);
+	window.addEventListener('dragover', accept);
+	window.addEventListener('drop', accept);
+}
+
+/**
+ * Returns true if the drag holds files. Older webviews have a
+ * DOMStringList of types rather than an array.
+ *
+ * @param {DragEvent} event
+ * @returns {boolean}
+ */
+function hasFiles(event) {
+	var types = event.dataTransfer && event.dataTransfer.types;
+	if (!types) {
+		return false;
+	}
+	if (types.indexOf) {
+		return types.indexOf('Files') !== -1;
+	}
+	return types.contains('Files');
+}
@@ -0,0 +1,33 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Resolves to the sorted names of the installed font families
+ *
+ * @export
+ * @returns {Promise<string[]>}
+ */
+export function List() {
+	return SystemCall('Fonts.List');
+}
+
+/**
+ * Resolves to the installed font families as objects
+ * with name and styles fields, sorted by name
+ *
+ * @export
+ * @returns {Promise<Object[]>}
+ */
+export function Families() {
+	return SystemCall('Fonts.Families');
+}
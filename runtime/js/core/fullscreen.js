@@ -0,0 +1,175 @@
+/*
+ _       __      _ __
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  )
+|__/|__/\__,_/_/_/____/
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+import { Emit } from './events';
+import { InjectCSS } from './utils';
+
+// The element shown fullscreen when the webview can't do it itself
+var fullscreenElement = null;
+
+/**
+ * Makes the HTML Fullscreen API work in every webview. Where the webview
+ * supports it, the unprefixed API is added if it's missing. Elsewhere, the
+ * element is made to cover the page and the window is made fullscreen.
+ * Either way, "wails:fullscreen" is emitted with whether an element is
+ * fullscreen whenever it changes.
+ *
+ * @export
+ */
+export function SetupFullscreen() {
+	if (document.fullscreenEnabled) {
+		document.addEventListener('fullscreenchange', nativeChanged);
+		return;
+	}
+	if (document.webkitFullscreenEnabled) {
+		unprefix();
+		return;
+	}
+	emulate();
+}
+
+/**
+ * Emits whether an element is fullscreen when the webview reports a change
+ */
+function nativeChanged() {
+	Emit('wails:fullscreen', !!(document.fullscreenElement || document.webkitFullscreenElement));
+}
+
+/**
+ * Adds the unprefixed API over the webkit prefixed one
+ */
+function unprefix() {
+	var prototype = Element.prototype;
+	if (!prototype.requestFullscreen) {
+		prototype.requestFullscreen = function () {
+			this.webkitRequestFullscreen();
+			return Promise.resolve();
+		};
+	}
+	if (!document.exitFullscreen) {
+		document.exitFullscreen = function () {
+			document.webkitExitFullscreen();
+			return Promise.resolve();
+		};
+	}
+	define('fullscreenEnabled', function () {
+		return true;
+	});
+	define('fullscreenElement', function () {
+		return document.webkitFullscreenElement || null;
+	});
+	document.addEventListener('webkitfullscreenchange', function (event) {
+		dispatch(event.target, 'fullscreenchange');
+		nativeChanged();
+	});
+}
+
+/**
+ * Covers the page with the fullscreen element, in a fullscreen window.
+ * Escape leaves fullscreen, as it does in a browser.
+ */
+function emulate() {
+	InjectCSS('.wails-fullscreen { position: fixed !important; top: 0 !important; left: 0 !important; ' +
+		'width: 100% !important; height: 100% !important; max-width: none !important; ' +
+		'max-height: none !important; margin: 0 !important; box-sizing: border-box !important; ' +
+		'z-index: 2147483647 !important; background: #000; }');
+
+	var prototype = Element.prototype;
+	prototype.requestFullscreen = function () {
+		return enter(this);
+	};
+	prototype.webkitRequestFullscreen = prototype.requestFullscreen;
+	prototype.msRequestFullscreen = prototype.requestFullscreen;
+	document.exitFullscreen = exit;
+	document.webkitExitFullscreen = exit;
+	document.msExitFullscreen = exit;
+	define('fullscreenEnabled', function () {
+		return true;
+	});
+	define('fullscreenElement', function () {
+		return fullscreenElement;
+	});
+
+	document.addEventListener('keydown', function (event) {
+		if (fullscreenElement && (event.key === 'Escape' || event.key === 'Esc' || event.keyCode === 27)) {
+			event.preventDefault();
+			exit();
+		}
+	}, true);
+}
+
+/**
+ * Shows the element fullscreen, leaving the current one first
+ *
+ * @param {Element} element
+ * @returns {Promise}
+ */
+function enter(element) {
+	if (fullscreenElement === element) {
+		return Promise.resolve();
+	}
+	if (fullscreenElement) {
+		fullscreenElement.classList.remove('wails-fullscreen');
+	} else {
+		SystemCall('Window.Fullscreen');
+	}
+	fullscreenElement = element;
+	element.classList.add('wails-fullscreen');
+	dispatch(element, 'fullscreenchange');
+	Emit('wails:fullscreen', true);
+	return Promise.resolve();
+}
+
+/**
+ * Leaves fullscreen, restoring the window
+ *
+ * @returns {Promise}
+ */
+function exit() {
+	var element = fullscreenElement;
+	if (!element) {
+		return Promise.resolve();
+	}
+	fullscreenElement = null;
+	element.classList.remove('wails-fullscreen');
+	SystemCall('Window.UnFullscreen');
+	dispatch(element, 'fullscreenchange');
+	Emit('wails:fullscreen', false);
+	return Promise.resolve();
+}
+
+/**
+ * Defines a read only property of the document
+ *
+ * @param {string} name
+ * @param {function} getter
+ */
+function define(name, getter) {
+	try {
+		Object.defineProperty(document, name, { get: getter, configurable: true });
+	} catch (e) {
+		// The property can't be redefined
+	}
+}
+
+/**
+ * Sends an event that bubbles up from the element to the document, as
+ * fullscreen changes do. Detached elements send it to the document.
+ *
+ * @param {Element} element
+ * @param {string} name
+ */
+function dispatch(element, name) {
+	var event = document.createEvent('Event');
+	event.initEvent(name, true, false);
+	(document.documentElement.contains(element) ? element : document).dispatchEvent(event);
+}
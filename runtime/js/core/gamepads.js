@@ -0,0 +1,136 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+import { SystemCall } from './calls';
+import { On } from './events';
+
+// The connected gamepads by index, when the webview has no Gamepad API
+var gamepads = [];
+
+// True once the gamepads are being watched for the page
+var watching = false;
+
+/**
+ * Resolves to the connected gamepads, read from Go, with index, id, mapping,
+ * buttons, axes and timestamp fields
+ *
+ * @export
+ * @returns {Promise<Object[]>}
+ */
+export function List() {
+	return SystemCall('Gamepads.List');
+}
+
+/**
+ * Emits the 'wails:gamepad:connected', 'wails:gamepad:disconnected' and
+ * 'wails:gamepad:changed' events with the gamepad, until StopWatching is
+ * called
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Watch() {
+	return SystemCall('Gamepads.Watch');
+}
+
+/**
+ * Stops the events started with Watch
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function StopWatching() {
+	return SystemCall('Gamepads.StopWatching');
+}
+
+/**
+ * Provides the Gamepad API from the gamepads read in Go, for webviews built
+ * without it, EG: some WebKitGTK builds. The gamepads are only watched once
+ * the page reads them or listens for them.
+ *
+ * @export
+ */
+export function SetupGamepads() {
+	if (typeof navigator.getGamepads === 'function') {
+		return;
+	}
+	navigator.getGamepads = function () {
+		watch();
+		return gamepads.slice();
+	};
+	var addEventListener = window.addEventListener;
+	window.addEventListener = function (type) {
+		if (type === 'gamepadconnected') {
+			watch();
+		}
+		return addEventListener.apply(this, arguments);
+	};
+
+	On('wails:gamepad:connected', function (gamepad) {
+		gamepads[gamepad.index] = toGamepad(gamepad, true);
+		dispatch('gamepadconnected', gamepads[gamepad.index]);
+	});
+	On('wails:gamepad:changed', function (gamepad) {
+		gamepads[gamepad.index] = toGamepad(gamepad, true);
+	});
+	On('wails:gamepad:disconnected', function (gamepad) {
+		gamepads[gamepad.index] = null;
+		dispatch('gamepaddisconnected', toGamepad(gamepad, false));
+	});
+}
+
+function watch() {
+	if (!watching) {
+		watching = true;
+		Watch().catch(function () {
+			watching = false;
+		});
+	}
+}
+
+/**
+ * Returns the gamepad read in Go in the shape of the Gamepad API's
+ *
+ * @param {Object} gamepad
+ * @param {boolean} connected
+ * @returns {Object}
+ */
+function toGamepad(gamepad, connected) {
+	return {
+		id: gamepad.id,
+		index: gamepad.index,
+		connected: connected,
+		mapping: gamepad.mapping,
+		timestamp: gamepad.timestamp,
+		buttons: gamepad.buttons.map(function (button) {
+			return { pressed: button.pressed, touched: button.pressed || button.value > 0, value: button.value };
+		}),
+		axes: gamepad.axes,
+	};
+}
+
+/**
+ * Dispatches a gamepad event at the window, as the Gamepad API does
+ *
+ * @param {string} type
+ * @param {Object} gamepad
+ */
+function dispatch(type, gamepad) {
+	var event = document.createEvent('Event');
+	event.initEvent(type, false, false);
+	event.gamepad = gamepad;
+	window.dispatchEvent(event);
+	var handler = window['on' + type];
+	if (typeof handler === 'function') {
+		handler.call(window, event);
+	}
+}
@@ -21,13 +21,15 @@ export function AddIPCListener(callback) {
 }
 
 /**
- * Invoke sends the given message to the backend
+ * Invoke sends the given message to the backend, in the given binary frame
+ * if there is one
  *
  * @param {string} message
+ * @param {ArrayBuffer=} frame
  */
-function Invoke(message) {
+function Invoke(message, frame) {
 	if (window.wailsbridge) {
-		window.wailsbridge.websocket.send(message);
+		window.wailsbridge.websocket.send(frame || message);
 	} else {
 		window.external.invoke(message);
 	}
@@ -41,19 +43,94 @@ function Invoke(message) {
 }
 
 /**
- * Sends a message to the backend based on the given type, payload and callbackID
+ * encodeFrame returns a binary frame holding the message followed by the
+ * given bytes. The message is preceded by its length as a big-endian uint32.
+ *
+ * @param {string} message
+ * @param {Uint8Array[]} binary
+ * @returns {ArrayBuffer}
+ */
+function encodeFrame(message, binary) {
+	const header = new TextEncoder().encode(message);
+	var size = 4 + header.length;
+	for (var i = 0; i < binary.length; i++) {
+		size += binary[i].byteLength;
+	}
+	const frame = new Uint8Array(size);
+	new DataView(frame.buffer).setUint32(0, header.length);
+	frame.set(header, 4);
+	var offset = 4 + header.length;
+	for (var j = 0; j < binary.length; j++) {
+		frame.set(binary[j], offset);
+		offset += binary[j].byteLength;
+	}
+	return frame.buffer;
+}
+
+/**
+ * base64 encodes the given bytes, a slice at a time so large arrays don't
+ * overflow the stack
+ *
+ * @param {Uint8Array} data
+ * @returns {string}
+ */
+function base64(data) {
+	var result = '';
+	for (var i = 0; i < data.length; i += 0x8000) {
+		result += String.fromCharCode.apply(null, data.subarray(i, i + 0x8000));
+	}
+	return btoa(result);
+}
+
+/**
+ * EncodeBinary encodes the given byte arrays to send with SendMessage. They
+ * are sent after the message in a binary frame over the bridge. The webview
+ * only takes strings, so they are sent as base64. Messages that are sent
+ * again, EG: when retried, reuse the encoded bytes.
+ *
+ * @export
+ * @param {Uint8Array[]} binary
+ * @returns {{payload: Array<number|string>, frame: Uint8Array[]=}}
+ */
+export function EncodeBinary(binary) {
+	if (window.wailsbridge) {
+		return {
+			payload: binary.map(function (data) {
+				return data.byteLength;
+			}),
+			frame: binary,
+		};
+	}
+	return {
+		payload: binary.map(base64),
+	};
+}
+
+/**
+ * Sends a message to the backend based on the given type, payload and callbackID.
+ * The byte arrays encoded with EncodeBinary are sent with the message,
+ * leaving the given payload unchanged.
  *
  * @export
  * @param {string} type
  * @param {string} payload
  * @param {string=} callbackID
+ * @param {{payload: Array<number|string>, frame: Uint8Array[]=}=} binary
  */
-export function SendMessage(type, payload, callbackID) {
+export function SendMessage(type, payload, callbackID, binary) {
+	if (binary && binary.payload.length > 0) {
+		payload = Object.assign({}, payload, { binary: binary.payload });
+	}
 	const message = {
 		type,
 		callbackID,
 		payload
 	};
+	const json = JSON.stringify(message);
 
-	Invoke(JSON.stringify(message));
+	if (binary && binary.frame && binary.frame.length > 0) {
+		Invoke(json, encodeFrame(json, binary.frame));
+		return;
+	}
+	Invoke(json);
 }
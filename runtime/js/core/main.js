@@ -10,11 +10,35 @@ The lightweight framework for web-like apps
 /* jshint esversion: 6 */
 import * as Log from './log';
 import * as Browser from './browser';
+import * as Support from './support';
+import * as Permissions from './permissions';
+import * as Dialog from './dialog';
+import * as Window from './window';
+import * as Fonts from './fonts';
+import * as System from './system';
+import * as Purchases from './purchases';
+import * as Menu from './menu';
+import * as Share from './share';
+import * as Calendar from './calendar';
+import * as Reminders from './reminders';
+import * as Power from './power';
+import * as Feedback from './feedback';
+import * as Touch from './touch';
+import * as Clipboard from './clipboard';
+import * as Gamepads from './gamepads';
 import { On, OnMultiple, Emit, Notify, Heartbeat, Acknowledge } from './events';
 import { NewBinding } from './bindings';
-import { Callback } from './calls';
+import { Callback, CallbackBinary, CallbackFrame, SetMaxPayloadSize, SetRetry } from './calls';
 import { AddScript, InjectCSS, InjectFirebug } from './utils';
 import { AddIPCListener } from './ipc';
+import { TrackOverlay, UntrackOverlay } from './overlays';
+import { ObservePerformance } from './perf';
+import { EnableDevOverlay } from './devoverlay';
+import { SetupFullscreen } from './fullscreen';
+import { SetupTouchKeyboard } from './touch';
+import { SetupPointerEvents, SetSystemGestures } from './pointer';
+import { SetupGamepads } from './gamepads';
+import { SetupFileDrop } from './filedrop';
 import * as Store from './store';
 
 // Initialise global if not already
@@ -39,17 +63,39 @@ if (window.external == undefined) {
 var internal = {
 	NewBinding,
 	Callback,
+	CallbackBinary,
+	CallbackFrame,
+	SetMaxPayloadSize,
 	Notify,
 	AddScript,
 	InjectCSS,
 	Init,
 	AddIPCListener,
+	TrackOverlay,
+	UntrackOverlay,
+	SetSystemGestures,
 };
 
 // Setup runtime structure
 var runtime = {
 	Log,
 	Browser,
+	Support,
+	Permissions,
+	Dialog,
+	Window,
+	Fonts,
+	System,
+	Purchases,
+	Menu,
+	Share,
+	Calendar,
+	Reminders,
+	Power,
+	Feedback,
+	Touch,
+	Clipboard,
+	Gamepads,
 	Events: {
 		On,
 		OnMultiple,
@@ -58,6 +104,9 @@ var runtime = {
 		Acknowledge,
 	},
 	Store,
+	Calls: {
+		SetRetry,
+	},
 	_: internal,
 };
 
@@ -79,6 +128,32 @@ if( window.usefirebug ) {
 	InjectFirebug();
 }
 
+// Let the backend hold its events while the page reloads
+window.addEventListener('beforeunload', function () {
+	Emit('wails:unloading');
+});
+
+// Send the page's performance entries when asked to by a debug build
+On('wails:perf:observe', ObservePerformance);
+
+// Let the dev overlay be shown in debug builds that enable it
+On('wails:devoverlay:enable', EnableDevOverlay);
+
+// Let video players and the like go fullscreen
+SetupFullscreen();
+
+// Show the on-screen keyboard for inputs focused by touch
+SetupTouchKeyboard();
+
+// Give pens and touches to the page as pointer events in every webview
+SetupPointerEvents();
+
+// Provide the Gamepad API from Go where the webview lacks it
+SetupGamepads();
+
+// Let files from the desktop be dropped anywhere on the window
+SetupFileDrop();
+
 // Emit loaded event
 Emit('wails:loaded');
 
@@ -0,0 +1,28 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Shows a native context menu at the given position in the page, EG: the
+ * clientX and clientY of a contextmenu event. Each item is an object with
+ * id, label, disabled, checked, separator and submenu fields. When an item
+ * is chosen, the 'wails:contextmenu:click' event is emitted with its id.
+ *
+ * @export
+ * @param {Object[]} menu
+ * @param {number} x
+ * @param {number} y
+ * @returns {Promise}
+ */
+export function ShowContextMenu(menu, x, y) {
+	return SystemCall('Menu.ShowContextMenu', { menu: menu, x: Math.round(x), y: Math.round(y) });
+}
@@ -0,0 +1,84 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+// The tracked overlays by ID, with where their elements were last reported
+var overlays = {};
+var tracking = false;
+
+/**
+ * Reports where the element matching the selector is whenever it moves,
+ * so the native overlay with the given ID is kept over it
+ *
+ * @export
+ * @param {string} id
+ * @param {string} selector
+ */
+export function TrackOverlay(id, selector) {
+	overlays[id] = { selector: selector, placement: null };
+	if (!tracking) {
+		tracking = true;
+		window.requestAnimationFrame(update);
+	}
+}
+
+/**
+ * Stops reporting where the overlay's element is
+ *
+ * @export
+ * @param {string} id
+ */
+export function UntrackOverlay(id) {
+	delete overlays[id];
+}
+
+/**
+ * Returns where the element matching the selector is in the page. Missing
+ * or hidden elements aren't visible.
+ *
+ * @param {string} selector
+ * @returns {object}
+ */
+function placement(selector) {
+	var element = document.querySelector(selector);
+	if (!element) {
+		return { x: 0, y: 0, width: 0, height: 0, visible: false };
+	}
+	var rect = element.getBoundingClientRect();
+	return {
+		x: Math.round(rect.left),
+		y: Math.round(rect.top),
+		width: Math.round(rect.width),
+		height: Math.round(rect.height),
+		visible: rect.width > 0 && rect.height > 0 && window.getComputedStyle(element).visibility !== 'hidden',
+	};
+}
+
+// Checks the elements once a frame, reporting those that have moved
+function update() {
+	var ids = Object.keys(overlays);
+	if (ids.length === 0) {
+		tracking = false;
+		return;
+	}
+	ids.forEach(function (id) {
+		var overlay = overlays[id];
+		var current = placement(overlay.selector);
+		var key = JSON.stringify(current);
+		if (key !== overlay.placement) {
+			overlay.placement = key;
+			current.id = id;
+			SystemCall('Window.PlaceOverlay', current);
+		}
+	});
+	window.requestAnimationFrame(update);
+}
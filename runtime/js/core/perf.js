@@ -0,0 +1,87 @@
+/*
+ _       __      _ __
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  )
+|__/|__/\__,_/_/_/____/
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { Emit } from './events';
+
+// The entries waiting to be sent to the backend
+let pending = [];
+
+// Set once the page's entries are being sent
+let observing = false;
+
+function queueEntries(entries) {
+	for (let i = 0; i < entries.length; i++) {
+		const entry = entries[i];
+		pending.push({
+			name: entry.name,
+			entryType: entry.entryType,
+			startTime: entry.startTime,
+			duration: entry.duration,
+		});
+	}
+}
+
+function sendEntries() {
+	if (pending.length === 0) {
+		return;
+	}
+	Emit('wails:perf:entries', pending);
+	pending = [];
+}
+
+/**
+ * Sends the page's navigation timing, marks, measures and long tasks to the
+ * backend, for the report printed by debug builds run with --perf. Entries
+ * are sent in batches as they are recorded.
+ *
+ * @export
+ */
+export function ObservePerformance() {
+	const performance = window.performance;
+	if (observing || !performance) {
+		return;
+	}
+	observing = true;
+
+	if (performance.getEntriesByType) {
+		queueEntries(performance.getEntriesByType('mark'));
+		queueEntries(performance.getEntriesByType('measure'));
+		const navigation = performance.getEntriesByType('navigation');
+		if (navigation.length > 0) {
+			queueEntries(navigation);
+		} else if (performance.timing) {
+			// Navigation Timing Level 1, EG: on IE11
+			const timing = performance.timing;
+			queueEntries([{
+				name: 'document',
+				entryType: 'navigation',
+				startTime: 0,
+				duration: Math.max(0, timing.loadEventEnd - timing.navigationStart),
+			}]);
+		}
+	}
+
+	// Unsupported entry types are skipped
+	if (window.PerformanceObserver) {
+		['mark', 'measure', 'longtask'].forEach(function (entryType) {
+			try {
+				new PerformanceObserver(function (list) {
+					queueEntries(list.getEntries());
+				}).observe({ entryTypes: [entryType] });
+			} catch (e) {
+				// Not supported by this webview
+			}
+		});
+	}
+
+	sendEntries();
+	setInterval(sendEntries, 1000);
+}
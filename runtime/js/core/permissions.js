@@ -0,0 +1,49 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Returns the status of the given permission: one of 'granted',
+ * 'denied', 'not-determined' or 'unknown'
+ *
+ * @export
+ * @param {string} permission - 'screen-recording', 'notifications' or 'camera'
+ * @returns {Promise<string>}
+ */
+export function Status(permission) {
+	return SystemCall('Permissions.Status', permission);
+}
+
+/**
+ * Asks the user for the given permission, either with the native prompt or
+ * by opening the Settings pane. The event 'wails:permission:changed' is
+ * emitted with the permission and its new status when it changes.
+ * Resolves to the status at the time of the request.
+ *
+ * @export
+ * @param {string} permission
+ * @returns {Promise<string>}
+ */
+export function Request(permission) {
+	return SystemCall('Permissions.Request', permission);
+}
+
+/**
+ * Opens the Settings pane for the given permission
+ *
+ * @export
+ * @param {string} permission
+ * @returns {Promise}
+ */
+export function OpenSettings(permission) {
+	return SystemCall('Permissions.OpenSettings', permission);
+}
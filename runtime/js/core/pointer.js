@@ -0,0 +1,194 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+// The style keeping the webview's touch and pen gestures from the page
+var gestureStyle = null;
+
+/**
+ * Makes Pointer Events, with the pressure and tilt of pens and touches,
+ * available in every webview. Old MSHTML document modes only have the
+ * prefixed MSPointer events, which are forwarded. Webviews without either
+ * are given pointer events made from mouse and touch events.
+ *
+ * @export
+ */
+export function SetupPointerEvents() {
+	if (window.PointerEvent) {
+		return;
+	}
+	if (window.MSPointerEvent) {
+		forwardMSPointerEvents();
+		return;
+	}
+	emulatePointerEvents();
+}
+
+/**
+ * Turns the webview's touch and pen gestures over the page, EG: panning,
+ * zooming and the Force Touch lookup, on or off
+ *
+ * @export
+ * @param {boolean} enabled
+ */
+export function SetSystemGestures(enabled) {
+	if (enabled) {
+		if (gestureStyle) {
+			gestureStyle.parentNode.removeChild(gestureStyle);
+			gestureStyle = null;
+			document.removeEventListener('webkitmouseforcewillbegin', preventDefault, true);
+		}
+		return;
+	}
+	if (!gestureStyle) {
+		gestureStyle = document.createElement('style');
+		gestureStyle.setAttribute('type', 'text/css');
+		gestureStyle.appendChild(document.createTextNode('html, body, * { touch-action: none; -ms-touch-action: none; -webkit-touch-callout: none; }'));
+		document.head.appendChild(gestureStyle);
+		document.addEventListener('webkitmouseforcewillbegin', preventDefault, true);
+	}
+}
+
+function preventDefault(event) {
+	event.preventDefault();
+}
+
+/**
+ * Dispatches the MSPointer events of old document modes as pointer events
+ */
+function forwardMSPointerEvents() {
+	var types = {
+		MSPointerDown: 'pointerdown',
+		MSPointerMove: 'pointermove',
+		MSPointerUp: 'pointerup',
+		MSPointerCancel: 'pointercancel',
+		MSPointerOver: 'pointerover',
+		MSPointerOut: 'pointerout',
+	};
+	// MSPointer types are numbers
+	var pointerTypes = { 2: 'touch', 3: 'pen', 4: 'mouse' };
+	Object.keys(types).forEach(function (type) {
+		document.addEventListener(type, function (event) {
+			dispatch(types[type], event.target, event, {
+				pointerId: event.pointerId,
+				pointerType: pointerTypes[event.pointerType] || event.pointerType,
+				pressure: event.pressure,
+				tiltX: event.tiltX,
+				tiltY: event.tiltY,
+				width: event.width,
+				height: event.height,
+				isPrimary: event.isPrimary,
+			});
+		}, true);
+	});
+}
+
+/**
+ * Dispatches pointer events made from mouse and touch events. The pressure
+ * of mouse buttons comes from Force Touch trackpads, where available.
+ */
+function emulatePointerEvents() {
+	var mouse = function (type) {
+		return function (event) {
+			var pressure = event.buttons || type === 'pointerdown' ? 0.5 : 0;
+			if (pressure && event.webkitForce) {
+				// Force Touch reports from 1 for a click up to 3
+				pressure = Math.max(0, Math.min(1, (event.webkitForce - 1) / 2));
+			}
+			if (type === 'pointerup') {
+				pressure = 0;
+			}
+			dispatch(type, event.target, event, {
+				pointerId: 1,
+				pointerType: 'mouse',
+				pressure: pressure,
+				tiltX: 0,
+				tiltY: 0,
+				width: 1,
+				height: 1,
+				isPrimary: true,
+			});
+		};
+	};
+	document.addEventListener('mousedown', mouse('pointerdown'), true);
+	document.addEventListener('mousemove', mouse('pointermove'), true);
+	document.addEventListener('mouseup', mouse('pointerup'), true);
+
+	var touch = function (type) {
+		return function (event) {
+			for (var i = 0; i < event.changedTouches.length; i++) {
+				var point = event.changedTouches[i];
+				var pen = point.touchType === 'stylus';
+				var tilt = { x: 0, y: 0 };
+				if (pen && point.altitudeAngle !== undefined) {
+					tilt = penTilt(point.altitudeAngle, point.azimuthAngle);
+				}
+				dispatch(type, point.target, point, {
+					// Mouse pointers use 1
+					pointerId: point.identifier + 2,
+					pointerType: pen ? 'pen' : 'touch',
+					pressure: type === 'pointerup' || type === 'pointercancel' ? 0 : (point.force || 0.5),
+					tiltX: tilt.x,
+					tiltY: tilt.y,
+					width: (point.radiusX || 0.5) * 2,
+					height: (point.radiusY || 0.5) * 2,
+					isPrimary: point === event.touches[0] || event.touches.length === 0,
+				});
+			}
+		};
+	};
+	document.addEventListener('touchstart', touch('pointerdown'), true);
+	document.addEventListener('touchmove', touch('pointermove'), true);
+	document.addEventListener('touchend', touch('pointerup'), true);
+	document.addEventListener('touchcancel', touch('pointercancel'), true);
+}
+
+/**
+ * Converts the angles of a stylus touch to the tilts of a pointer event,
+ * in degrees
+ *
+ * @param {number} altitude - The pen's angle from the screen, in radians
+ * @param {number} azimuth - The pen's direction on the screen, in radians
+ * @returns {Object}
+ */
+function penTilt(altitude, azimuth) {
+	var degrees = 180 / Math.PI;
+	if (altitude >= Math.PI / 2) {
+		return { x: 0, y: 0 };
+	}
+	var tan = Math.tan(altitude);
+	return {
+		x: Math.round(Math.atan(Math.cos(azimuth) / tan) * degrees),
+		y: Math.round(Math.atan(Math.sin(azimuth) / tan) * degrees),
+	};
+}
+
+/**
+ * Dispatches a pointer event at the target, with the position and keys of
+ * the source event or touch
+ *
+ * @param {string} type
+ * @param {EventTarget} target
+ * @param {Object} source
+ * @param {Object} pointer
+ */
+function dispatch(type, target, source, pointer) {
+	var event = document.createEvent('MouseEvents');
+	var bubbles = type !== 'pointerover' && type !== 'pointerout';
+	event.initMouseEvent(type, bubbles, true, window, 0,
+		source.screenX, source.screenY, source.clientX, source.clientY,
+		!!source.ctrlKey, !!source.altKey, !!source.shiftKey, !!source.metaKey,
+		source.button || 0, null);
+	Object.keys(pointer).forEach(function (name) {
+		event[name] = pointer[name];
+	});
+	target.dispatchEvent(event);
+}
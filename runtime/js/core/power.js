@@ -0,0 +1,46 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Resolves to the machine's battery and power state, with hasBattery,
+ * battery (0 to 100, or -1 without a battery), charging, pluggedIn,
+ * lowPowerMode and thermal ('nominal', 'fair', 'serious', 'critical'
+ * or 'unknown') fields
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+export function Status() {
+	return SystemCall('Power.Status');
+}
+
+/**
+ * Emits the 'wails:power:changed' event with the new status whenever it
+ * changes, until StopWatching is called
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Watch() {
+	return SystemCall('Power.Watch');
+}
+
+/**
+ * Stops the events started with Watch
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function StopWatching() {
+	return SystemCall('Power.StopWatching');
+}
@@ -0,0 +1,58 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Resolves to true if the user may make purchases
+ *
+ * @export
+ * @returns {Promise<boolean>}
+ */
+export function CanMakePayments() {
+	return SystemCall('Purchases.CanMakePayments');
+}
+
+/**
+ * Resolves to the details of the given products, with
+ * id, title, description and price fields
+ *
+ * @export
+ * @param {string[]} ids
+ * @returns {Promise<Object[]>}
+ */
+export function Products(ids) {
+	return SystemCall('Purchases.Products', ids);
+}
+
+/**
+ * Starts a purchase of the given product. The result is emitted as the
+ * 'wails:purchase:updated' event with a transaction with productId,
+ * transactionId, state and optional receipt and error fields.
+ *
+ * @export
+ * @param {string} productId
+ * @returns {Promise}
+ */
+export function Purchase(productId) {
+	return SystemCall('Purchases.Purchase', productId);
+}
+
+/**
+ * Emits the user's previous purchases as 'wails:purchase:updated'
+ * events with the 'restored' state
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Restore() {
+	return SystemCall('Purchases.Restore');
+}
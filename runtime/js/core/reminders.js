@@ -0,0 +1,69 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Resolves to whether the user has allowed the app to access their
+ * reminders: 'granted', 'denied', 'not-determined' or 'unknown'
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+export function Status() {
+	return SystemCall('Reminders.Status');
+}
+
+/**
+ * Asks the user to allow the app to access their reminders, resolving to
+ * the status once they have answered
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+export function Request() {
+	return SystemCall('Reminders.Request');
+}
+
+/**
+ * Resolves to the user's reminders, with id, list, title, notes, due and
+ * completed fields. Completed reminders are included if includeCompleted
+ * is true.
+ *
+ * @export
+ * @param {boolean} includeCompleted
+ * @returns {Promise<Object[]>}
+ */
+export function List(includeCompleted) {
+	return SystemCall('Reminders.List', !!includeCompleted);
+}
+
+/**
+ * Adds the reminder, or updates it if it has an id, resolving to its id
+ *
+ * @export
+ * @param {Object} reminder
+ * @returns {Promise<string>}
+ */
+export function Save(reminder) {
+	return SystemCall('Reminders.Save', reminder);
+}
+
+/**
+ * Removes the reminder with the given id
+ *
+ * @export
+ * @param {string} id
+ * @returns {Promise}
+ */
+export function Delete(id) {
+	return SystemCall('Reminders.Delete', id);
+}
@@ -0,0 +1,31 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+import { On } from './events';
+
+/**
+ * Calls the callback with what other apps share with this one, an object
+ * with text and files fields. The shares that arrived before the frontend
+ * loaded, EG: the share that launched the app, are passed to it first.
+ *
+ * @export
+ * @param {function} callback
+ * @returns {Promise}
+ */
+export function OnShare(callback) {
+	On('wails:share', callback);
+	return SystemCall('Share.Pending').then(function (pending) {
+		for (var i = 0; i < pending.length; i++) {
+			callback(pending[i]);
+		}
+	});
+}
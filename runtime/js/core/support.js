@@ -0,0 +1,49 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Returns a sanitized report of the system the app is running on
+ *
+ * @export
+ * @returns {Promise<object>}
+ */
+export function SystemReport() {
+	return SystemCall('Support.SystemReport');
+}
+
+/**
+ * Opens the app's issue tracker prefilled with the given title,
+ * description and the system report. Falls back to composing a
+ * support email if no issue tracker is configured.
+ *
+ * @export
+ * @param {string} title
+ * @param {string} description
+ * @returns {Promise}
+ */
+export function ReportBug(title, description) {
+	return SystemCall('Support.ReportBug', { title: title, description: description });
+}
+
+/**
+ * Composes a support email with the given subject and description.
+ * Resolves to the path of the diagnostics bundle to attach.
+ *
+ * @export
+ * @param {string} subject
+ * @param {string} description
+ * @returns {Promise<string>}
+ */
+export function EmailSupport(subject, description) {
+	return SystemCall('Support.EmailSupport', { title: subject, description: description });
+}
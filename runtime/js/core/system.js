@@ -0,0 +1,118 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+
+/**
+ * Resolves to the user's locale as set in the OS settings, with tag,
+ * language, region, firstDayOfWeek (0 is Sunday), decimalSeparator
+ * and uses24HourClock fields
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+export function Locale() {
+	return SystemCall('System.Locale');
+}
+
+/**
+ * Resolves to an ID for the machine that is stable across launches.
+ * It is unique to the app so it can't be used to match the machine
+ * across apps.
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+export function MachineID() {
+	return SystemCall('System.MachineID');
+}
+
+/**
+ * Resolves to a new ULID from the app-wide generator. IDs sort by
+ * creation order, including those created in Go.
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+export function NewID() {
+	return SystemCall('System.NewID');
+}
+
+/**
+ * Resolves to the resource usage of the app, with cpu (percentage of one
+ * core since the previous sample), memory, goroutines and webviewMemory
+ * fields. Memory is in bytes.
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+export function Stats() {
+	return SystemCall('System.Stats');
+}
+
+/**
+ * Emits the 'wails:system:stats' event with the app's resource usage
+ * at the given interval until StopWatchingStats is called
+ *
+ * @export
+ * @param {number} [interval] - The interval in milliseconds. Defaults to 1000.
+ * @returns {Promise}
+ */
+export function WatchStats(interval) {
+	return SystemCall('System.WatchStats', interval || 1000);
+}
+
+/**
+ * Stops the events started with WatchStats
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function StopWatchingStats() {
+	return SystemCall('System.StopWatchingStats');
+}
+
+/**
+ * Resolves to the flags the app was launched with, with minimized, share,
+ * profile, headlessCommand and args (the arguments that weren't recognised)
+ * fields
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+export function Flags() {
+	return SystemCall('System.Flags');
+}
+
+/**
+ * Resolves to where the app is running from, with path, originalPath,
+ * translocated, quarantined and inApplications fields. MacOS translocates
+ * apps opened from where they were downloaded, running them from a random,
+ * read-only copy.
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+export function Installation() {
+	return SystemCall('System.Installation');
+}
+
+/**
+ * Moves the app to the Applications folder and launches it from there once
+ * this instance quits, which it should do straight after. Resolves to the
+ * app's new path. MacOS only.
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+export function MoveToApplications() {
+	return SystemCall('System.MoveToApplications');
+}
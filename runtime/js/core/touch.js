@@ -0,0 +1,102 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+import { SystemCall } from './calls';
+
+// The input types typed into with a keyboard
+var textTypes = ['', 'text', 'search', 'email', 'url', 'tel', 'password', 'number', 'date', 'time', 'datetime-local', 'month', 'week'];
+
+// When the page was last touched, so only inputs focused by touch show the
+// keyboard
+var lastTouch = 0;
+
+/**
+ * Resolves to whether the machine can be used by touch, with touchscreen,
+ * maxTouchPoints and tabletMode fields
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+export function Status() {
+	return SystemCall('Touch.Status');
+}
+
+/**
+ * Emits the 'wails:touch:changed' event with the new status whenever it
+ * changes, EG: when the device is folded into a tablet, until StopWatching
+ * is called
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Watch() {
+	return SystemCall('Touch.Watch');
+}
+
+/**
+ * Stops the events started with Watch
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function StopWatching() {
+	return SystemCall('Touch.StopWatching');
+}
+
+/**
+ * Shows the on-screen keyboard
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function ShowKeyboard() {
+	return SystemCall('Touch.ShowKeyboard');
+}
+
+/**
+ * Shows the on-screen keyboard when an input is focused by touch, as the
+ * webviews don't always do it themselves
+ *
+ * @export
+ */
+export function SetupTouchKeyboard() {
+	var touched = function () {
+		lastTouch = Date.now();
+	};
+	document.addEventListener('touchstart', touched, true);
+	document.addEventListener('pointerdown', function (event) {
+		if (event.pointerType === 'touch') {
+			touched();
+		}
+	}, true);
+	document.addEventListener('focusin', function (event) {
+		if (Date.now() - lastTouch < 1000 && isTextInput(event.target)) {
+			ShowKeyboard().catch(function () {});
+		}
+	});
+}
+
+/**
+ * Returns true if the element is typed into
+ *
+ * @param {Element} element
+ * @returns {boolean}
+ */
+function isTextInput(element) {
+	if (!element || element.disabled || element.readOnly) {
+		return false;
+	}
+	if (element.isContentEditable || element.tagName === 'TEXTAREA') {
+		return true;
+	}
+	return element.tagName === 'INPUT' && textTypes.indexOf((element.getAttribute('type') || '').toLowerCase()) !== -1;
+}
@@ -0,0 +1,286 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+import { SystemCall } from './calls';
+import { On } from './events';
+
+/**
+ * Calls the callback when files are dragged from the desktop and dropped on
+ * the window, with an object with files (their absolute paths), x and y
+ * (where they were dropped in the page) fields
+ *
+ * @export
+ * @param {function} callback
+ */
+export function OnFileDrop(callback) {
+	On('wails:file-drop', callback);
+}
+
+/**
+ * Opens the system emoji and character picker. The chosen
+ * character is inserted into the focused input.
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function ShowEmojiPicker() {
+	return SystemCall('Window.ShowEmojiPicker');
+}
+
+/**
+ * Makes the window fullscreen
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Fullscreen() {
+	return SystemCall('Window.Fullscreen');
+}
+
+/**
+ * Restores the window to its size and position before it was made fullscreen
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function UnFullscreen() {
+	return SystemCall('Window.UnFullscreen');
+}
+
+/**
+ * Minimises the window
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Minimise() {
+	return SystemCall('Window.Minimise');
+}
+
+/**
+ * Maximises the window
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Maximise() {
+	return SystemCall('Window.Maximise');
+}
+
+/**
+ * Returns a minimised or maximised window to its previous size and position
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Restore() {
+	return SystemCall('Window.Restore');
+}
+
+/**
+ * Shows the window and brings it to the front
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Show() {
+	return SystemCall('Window.Show');
+}
+
+/**
+ * Hides the window without closing it
+ *
+ * @export
+ * @returns {Promise}
+ */
+export function Hide() {
+	return SystemCall('Window.Hide');
+}
+
+/**
+ * Sets the minimum size of a resizable window
+ *
+ * @export
+ * @param {number} width
+ * @param {number} height
+ * @returns {Promise}
+ */
+export function SetMinSize(width, height) {
+	return SystemCall('Window.SetMinSize', { width, height });
+}
+
+/**
+ * Sets the maximum size of a resizable window
+ *
+ * @export
+ * @param {number} width
+ * @param {number} height
+ * @returns {Promise}
+ */
+export function SetMaxSize(width, height) {
+	return SystemCall('Window.SetMaxSize', { width, height });
+}
+
+/**
+ * Keeps the content of a resizable window at the ratio of the given width
+ * and height as it is resized. Use 0 and 0 to unlock it.
+ *
+ * @export
+ * @param {number} width
+ * @param {number} height
+ * @returns {Promise}
+ */
+export function SetAspectRatio(width, height) {
+	return SystemCall('Window.SetAspectRatio', { width, height });
+}
+
+/**
+ * Turns the system's pen and touch gestures over the page on or off. Drawing
+ * apps turn them off so that panning, press-and-hold and pen flicks reach
+ * the page as pointer events, rather than scrolling it or opening menus.
+ *
+ * @export
+ * @param {boolean} enabled
+ * @returns {Promise}
+ */
+export function SetSystemGestures(enabled) {
+	return SystemCall('Window.SetSystemGestures', !!enabled);
+}
+
+/**
+ * Starts dragging the files at the given paths out of the window, so they
+ * can be dropped in the file manager or another app. It must be called
+ * while the mouse button is held, EG: from a dragstart handler, after
+ * calling preventDefault() on the event.
+ *
+ * @export
+ * @param {string|string[]} files
+ * @returns {Promise}
+ */
+export function StartDrag(files) {
+	return SystemCall('Window.StartDrag', [].concat(files));
+}
+
+/**
+ * Draws the user's attention to the window when it isn't focused, EG: by
+ * flashing its taskbar button or bouncing the dock icon. Critical requests
+ * continue until the user switches to the app.
+ *
+ * @export
+ * @param {boolean=} critical
+ * @returns {Promise}
+ */
+export function RequestUserAttention(critical) {
+	return SystemCall('Window.RequestUserAttention', !!critical);
+}
+
+/**
+ * Fades the whole window, from 0 for invisible to 1 for opaque
+ *
+ * @export
+ * @param {number} opacity
+ * @returns {Promise}
+ */
+export function SetOpacity(opacity) {
+	return SystemCall('Window.SetOpacity', opacity);
+}
+
+/**
+ * Shows the progress of a long task, from 0 to 1, on the taskbar button,
+ * dock icon or launcher entry. Negative progress hides it.
+ *
+ * @export
+ * @param {number} progress
+ * @returns {Promise}
+ */
+export function SetProgress(progress) {
+	return SystemCall('Window.SetProgress', progress);
+}
+
+/**
+ * Shows a short label, EG: an unread count, on the taskbar button, dock
+ * icon or launcher entry. An empty badge hides it.
+ *
+ * @export
+ * @param {string} badge
+ * @returns {Promise}
+ */
+export function SetBadge(badge) {
+	return SystemCall('Window.SetBadge', String(badge));
+}
+
+/**
+ * Fills the window's background with a material of the platform, EG:
+ * 'sidebar' on MacOS or 'mica' on Windows 11, or removes it with 'none'.
+ * It shows where the page leaves its background transparent.
+ *
+ * @export
+ * @param {string} material
+ * @returns {Promise}
+ */
+export function SetMaterial(material) {
+	return SystemCall('Window.SetMaterial', material);
+}
+
+/**
+ * Gets the materials the window supports
+ *
+ * @export
+ * @returns {Promise<string[]>}
+ */
+export function Materials() {
+	return SystemCall('Window.Materials');
+}
+
+/**
+ * Resolves to the displays attached to the system as objects with id,
+ * bounds, workArea, scaleFactor and primary fields
+ *
+ * @export
+ * @returns {Promise<Object[]>}
+ */
+export function Displays() {
+	return SystemCall('Window.Displays');
+}
+
+/**
+ * Sets the window title
+ *
+ * @export
+ * @param {string} title
+ * @returns {Promise}
+ */
+export function SetTitle(title) {
+	return SystemCall('Window.SetTitle', title);
+}
+
+/**
+ * Sets the window icon to a PNG or ICO image, given as a data URL, base64 or
+ * bytes. An empty icon restores the app's icon.
+ *
+ * @export
+ * @param {string|Uint8Array|ArrayBuffer} icon
+ * @returns {Promise}
+ */
+export function SetIcon(icon) {
+	if (icon instanceof ArrayBuffer) {
+		icon = new Uint8Array(icon);
+	}
+	if (icon instanceof Uint8Array) {
+		let binary = '';
+		for (let i = 0; i < icon.length; i++) {
+			binary += String.fromCharCode(icon[i]);
+		}
+		icon = window.btoa(binary);
+	}
+	icon = (icon || '').replace(/^data:[^,]*;base64,/, '');
+	return SystemCall('Window.SetIcon', icon);
+}
@@ -0,0 +1,73 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Returns whether the user has allowed the app to access their calendars
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+function Status() {
+	return window.wails.Calendar.Status();
+}
+
+/**
+ * Asks the user to allow the app to access their calendars
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+function Request() {
+	return window.wails.Calendar.Request();
+}
+
+/**
+ * Returns the events that overlap the given period
+ *
+ * @export
+ * @param {Date} start
+ * @param {Date} end
+ * @returns {Promise<Object[]>}
+ */
+function Events(start, end) {
+	return window.wails.Calendar.Events(start, end);
+}
+
+/**
+ * Adds or updates the event, returning its id
+ *
+ * @export
+ * @param {Object} event
+ * @returns {Promise<string>}
+ */
+function SaveEvent(event) {
+	return window.wails.Calendar.SaveEvent(event);
+}
+
+/**
+ * Removes the event with the given id
+ *
+ * @export
+ * @param {string} id
+ * @returns {Promise}
+ */
+function DeleteEvent(id) {
+	return window.wails.Calendar.DeleteEvent(id);
+}
+
+module.exports = {
+	Status: Status,
+	Request: Request,
+	Events: Events,
+	SaveEvent: SaveEvent,
+	DeleteEvent: DeleteEvent
+};
@@ -0,0 +1,28 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+
+/* jshint esversion: 6 */
+
+
+/**
+ * Sets how calls to the backend are retried when they can't be sent, EG:
+ * while the backend restarts in development. Failed calls are queued and
+ * sent again with a backoff instead of failing straight away.
+ *
+ * @export
+ * @param {{retries: number, delay: number=, maxDelay: number=}} options
+ */
+function SetRetry(options) {
+	window.wails.Calls.SetRetry(options);
+}
+
+module.exports = {
+	SetRetry: SetRetry,
+};
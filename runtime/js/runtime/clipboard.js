@@ -0,0 +1,61 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Returns the text on the clipboard
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+function GetText() {
+	return window.wails.Clipboard.GetText();
+}
+
+/**
+ * Replaces the contents of the clipboard with the given text
+ *
+ * @export
+ * @param {string} text
+ * @returns {Promise}
+ */
+function SetText(text) {
+	return window.wails.Clipboard.SetText(text);
+}
+
+/**
+ * Returns the image on the clipboard as a PNG data URL, or null
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+function GetImage() {
+	return window.wails.Clipboard.GetImage();
+}
+
+/**
+ * Replaces the contents of the clipboard with the given PNG image, as a
+ * data URL or base64
+ *
+ * @export
+ * @param {string} image
+ * @returns {Promise}
+ */
+function SetImage(image) {
+	return window.wails.Clipboard.SetImage(image);
+}
+
+module.exports = {
+	GetText: GetText,
+	SetText: SetText,
+	GetImage: GetImage,
+	SetImage: SetImage
+};
@@ -0,0 +1,100 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Opens the native colour picker, starting with the given colour
+ *
+ * @export
+ * @param {string} [initial]
+ * @returns {Promise<Object>}
+ */
+function ColorPicker(initial) {
+	return window.wails.Dialog.ColorPicker(initial);
+}
+
+/**
+ * Opens the native font picker, starting with the given font
+ *
+ * @export
+ * @param {Object} [initial]
+ * @returns {Promise<Object>}
+ */
+function FontPicker(initial) {
+	return window.wails.Dialog.FontPicker(initial);
+}
+
+/**
+ * Opens the native file dialog, returning the chosen paths
+ *
+ * @export
+ * @param {Object} [options]
+ * @returns {Promise<string[]>}
+ */
+function OpenFile(options) {
+	return window.wails.Dialog.OpenFile(options);
+}
+
+/**
+ * Opens the native directory dialog, returning the chosen paths
+ *
+ * @export
+ * @param {Object} [options]
+ * @returns {Promise<string[]>}
+ */
+function OpenDirectory(options) {
+	return window.wails.Dialog.OpenDirectory(options);
+}
+
+/**
+ * Shows the platform's about dialog
+ *
+ * @export
+ * @returns {Promise}
+ */
+function About() {
+	return window.wails.Dialog.About();
+}
+
+/**
+ * Shows a native message dialog, returning the label of the pressed button
+ *
+ * @export
+ * @param {string} type
+ * @param {string} title
+ * @param {string} message
+ * @param {string[]} [buttons]
+ * @returns {Promise<string>}
+ */
+function Message(type, title, message, buttons) {
+	return window.wails.Dialog.Message(type, title, message, buttons);
+}
+
+/**
+ * Opens the native save dialog, returning the chosen path and format
+ *
+ * @export
+ * @param {Object} [options]
+ * @returns {Promise<Object>}
+ */
+function SaveFile(options) {
+	return window.wails.Dialog.SaveFile(options);
+}
+
+module.exports = {
+	ColorPicker: ColorPicker,
+	FontPicker: FontPicker,
+	OpenFile: OpenFile,
+	OpenDirectory: OpenDirectory,
+	Message: Message,
+	About: About,
+	SaveFile: SaveFile
+};
@@ -0,0 +1,39 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+
+/**
+ * Plays the system sound for the given kind of event
+ *
+ * @export
+ * @param {string} [kind]
+ * @returns {Promise}
+ */
+function Beep(kind) {
+	return window.wails.Feedback.Beep(kind);
+}
+
+/**
+ * Performs haptic feedback on the trackpad, on MacOS only
+ *
+ * @export
+ * @param {string} [pattern]
+ * @returns {Promise}
+ */
+function Haptic(pattern) {
+	return window.wails.Feedback.Haptic(pattern);
+}
+
+module.exports = {
+	Beep: Beep,
+	Haptic: Haptic
+};
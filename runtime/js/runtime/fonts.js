@@ -0,0 +1,36 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Returns the names of the installed font families
+ *
+ * @export
+ * @returns {Promise<string[]>}
+ */
+function List() {
+	return window.wails.Fonts.List();
+}
+
+/**
+ * Returns the installed font families with their styles
+ *
+ * @export
+ * @returns {Promise<Object[]>}
+ */
+function Families() {
+	return window.wails.Fonts.Families();
+}
+
+module.exports = {
+	List: List,
+	Families: Families
+};
@@ -0,0 +1,48 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Resolves to the connected gamepads, read from Go
+ *
+ * @export
+ * @returns {Promise<Object[]>}
+ */
+function List() {
+	return window.wails.Gamepads.List();
+}
+
+/**
+ * Emits the 'wails:gamepad:connected', 'wails:gamepad:disconnected' and
+ * 'wails:gamepad:changed' events, until StopWatching is called
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Watch() {
+	return window.wails.Gamepads.Watch();
+}
+
+/**
+ * Stops the events started with Watch
+ *
+ * @export
+ * @returns {Promise}
+ */
+function StopWatching() {
+	return window.wails.Gamepads.StopWatching();
+}
+
+module.exports = {
+	List: List,
+	Watch: Watch,
+	StopWatching: StopWatching
+};
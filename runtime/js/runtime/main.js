@@ -11,14 +11,48 @@ The lightweight framework for web-like apps
 
 const Log = require('./log');
 const Browser = require('./browser');
+const Calls = require('./calls');
 const Events = require('./events');
 const Init = require('./init');
 const Store = require('./store');
+const Support = require('./support');
+const Permissions = require('./permissions');
+const Dialog = require('./dialog');
+const Window = require('./window');
+const Fonts = require('./fonts');
+const System = require('./system');
+const Purchases = require('./purchases');
+const Menu = require('./menu');
+const Share = require('./share');
+const Calendar = require('./calendar');
+const Reminders = require('./reminders');
+const Power = require('./power');
+const Feedback = require('./feedback');
+const Touch = require('./touch');
+const Clipboard = require('./clipboard');
+const Gamepads = require('./gamepads');
 
 module.exports = {
 	Log: Log,
 	Browser: Browser,
+	Calls: Calls,
 	Events: Events,
 	Init: Init,
 	Store: Store,
+	Support: Support,
+	Permissions: Permissions,
+	Dialog: Dialog,
+	Window: Window,
+	Fonts: Fonts,
+	System: System,
+	Purchases: Purchases,
+	Menu: Menu,
+	Share: Share,
+	Calendar: Calendar,
+	Reminders: Reminders,
+	Power: Power,
+	Feedback: Feedback,
+	Touch: Touch,
+	Clipboard: Clipboard,
+	Gamepads: Gamepads,
 };
\ No newline at end of file
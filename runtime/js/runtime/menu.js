@@ -0,0 +1,29 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Shows a native context menu at the given position in the page. The
+ * 'wails:contextmenu:click' event is emitted with the id of the chosen item.
+ *
+ * @export
+ * @param {Object[]} menu
+ * @param {number} x
+ * @param {number} y
+ * @returns {Promise}
+ */
+function ShowContextMenu(menu, x, y) {
+	return window.wails.Menu.ShowContextMenu(menu, x, y);
+}
+
+module.exports = {
+	ShowContextMenu: ShowContextMenu
+};
@@ -0,0 +1,51 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Returns the status of the given permission
+ *
+ * @export
+ * @param {string} permission - 'screen-recording', 'notifications' or 'camera'
+ * @returns {Promise<string>}
+ */
+function Status(permission) {
+	return window.wails.Permissions.Status(permission);
+}
+
+/**
+ * Asks the user for the given permission. 'wails:permission:changed'
+ * is emitted when its status changes.
+ *
+ * @export
+ * @param {string} permission
+ * @returns {Promise<string>}
+ */
+function Request(permission) {
+	return window.wails.Permissions.Request(permission);
+}
+
+/**
+ * Opens the Settings pane for the given permission
+ *
+ * @export
+ * @param {string} permission
+ * @returns {Promise}
+ */
+function OpenSettings(permission) {
+	return window.wails.Permissions.OpenSettings(permission);
+}
+
+module.exports = {
+	Status: Status,
+	Request: Request,
+	OpenSettings: OpenSettings
+};
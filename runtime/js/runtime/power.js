@@ -0,0 +1,47 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Returns the machine's battery and power state
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+function Status() {
+	return window.wails.Power.Status();
+}
+
+/**
+ * Emits 'wails:power:changed' whenever the status changes
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Watch() {
+	return window.wails.Power.Watch();
+}
+
+/**
+ * Stops the events started with Watch
+ *
+ * @export
+ * @returns {Promise}
+ */
+function StopWatching() {
+	return window.wails.Power.StopWatching();
+}
+
+module.exports = {
+	Status: Status,
+	Watch: Watch,
+	StopWatching: StopWatching
+};
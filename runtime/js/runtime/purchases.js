@@ -0,0 +1,61 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Returns true if the user may make purchases
+ *
+ * @export
+ * @returns {Promise<boolean>}
+ */
+function CanMakePayments() {
+	return window.wails.Purchases.CanMakePayments();
+}
+
+/**
+ * Returns the details of the given products
+ *
+ * @export
+ * @param {string[]} ids
+ * @returns {Promise<Object[]>}
+ */
+function Products(ids) {
+	return window.wails.Purchases.Products(ids);
+}
+
+/**
+ * Starts a purchase of the given product. The result
+ * is emitted as 'wails:purchase:updated'.
+ *
+ * @export
+ * @param {string} productId
+ * @returns {Promise}
+ */
+function Purchase(productId) {
+	return window.wails.Purchases.Purchase(productId);
+}
+
+/**
+ * Emits the user's previous purchases as 'wails:purchase:updated'
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Restore() {
+	return window.wails.Purchases.Restore();
+}
+
+module.exports = {
+	CanMakePayments: CanMakePayments,
+	Products: Products,
+	Purchase: Purchase,
+	Restore: Restore
+};
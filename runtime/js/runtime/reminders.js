@@ -0,0 +1,72 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Returns whether the user has allowed the app to access their reminders
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+function Status() {
+	return window.wails.Reminders.Status();
+}
+
+/**
+ * Asks the user to allow the app to access their reminders
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+function Request() {
+	return window.wails.Reminders.Request();
+}
+
+/**
+ * Returns the user's reminders
+ *
+ * @export
+ * @param {boolean} includeCompleted
+ * @returns {Promise<Object[]>}
+ */
+function List(includeCompleted) {
+	return window.wails.Reminders.List(includeCompleted);
+}
+
+/**
+ * Adds or updates the reminder, returning its id
+ *
+ * @export
+ * @param {Object} reminder
+ * @returns {Promise<string>}
+ */
+function Save(reminder) {
+	return window.wails.Reminders.Save(reminder);
+}
+
+/**
+ * Removes the reminder with the given id
+ *
+ * @export
+ * @param {string} id
+ * @returns {Promise}
+ */
+function Delete(id) {
+	return window.wails.Reminders.Delete(id);
+}
+
+module.exports = {
+	Status: Status,
+	Request: Request,
+	List: List,
+	Save: Save,
+	Delete: Delete
+};
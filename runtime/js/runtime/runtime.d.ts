@@ -1,29 +1,338 @@
-export = wailsapp__runtime;
-
-declare const wailsapp__runtime: {
-    Browser: {
-        OpenFile(filename: string): Promise<any>;
-        OpenURL(url: string): Promise<any>;
-    };
-    Events: {
-        Acknowledge(eventName: string): void;
-        Emit(eventName: string, data?: any): void;
-        Heartbeat(eventName: string, timeInMilliseconds: number, callback: (data?: any) => void): void;
-        On(eventName: string, callback: (data?: any) => void): void;
-        OnMultiple(eventName: string, callback: (data?: any) => void, maxCallbacks: number): void;
-        Once(eventName: string, callback: (data?: any) => void): void;
-    };
-    Init(callback: () => void): void;
-    Log: {
-        Debug(message: string): void;
-        Error(message: string): void;
-        Fatal(message: string): void;
-        Info(message: string): void;
-        Warning(message: string): void;
-    };
-    Store: {
-        New(name: string, optionalDefault?: any): any;
-    };
-};
-
-
+export = wailsapp__runtime;
+
+declare const wailsapp__runtime: {
+    Browser: {
+        OpenFile(filename: string): Promise<any>;
+        OpenURL(url: string): Promise<any>;
+    };
+    Calls: {
+        SetRetry(options: RetryOptions): void;
+    };
+    Events: {
+        Acknowledge(eventName: string): void;
+        Emit(eventName: string, data?: any): void;
+        Heartbeat(eventName: string, timeInMilliseconds: number, callback: (data?: any) => void): void;
+        On(eventName: string, callback: (data?: any) => void): void;
+        OnMultiple(eventName: string, callback: (data?: any) => void, maxCallbacks: number): void;
+        Once(eventName: string, callback: (data?: any) => void): void;
+    };
+    Init(callback: () => void): void;
+    Log: {
+        Debug(message: string): void;
+        Error(message: string): void;
+        Fatal(message: string): void;
+        Info(message: string): void;
+        Warning(message: string): void;
+    };
+    Store: {
+        New(name: string, optionalDefault?: any): any;
+    };
+    Support: {
+        EmailSupport(subject: string, description: string): Promise<string>;
+        ReportBug(title: string, description: string): Promise<any>;
+        SystemReport(): Promise<any>;
+    };
+    Permissions: {
+        OpenSettings(permission: Permission): Promise<any>;
+        Request(permission: Permission): Promise<PermissionStatus>;
+        Status(permission: Permission): Promise<PermissionStatus>;
+    };
+    Dialog: {
+        ColorPicker(initial?: string): Promise<Colour | null>;
+        FontPicker(initial?: Partial<Font>): Promise<Font | null>;
+        OpenFile(options?: OpenDialogOptions): Promise<string[] | null>;
+        OpenDirectory(options?: DirectoryDialogOptions): Promise<string[] | null>;
+        About(): Promise<any>;
+        Message(type: 'info' | 'warning' | 'error' | 'question', title: string, message: string, buttons?: string[]): Promise<string>;
+        SaveFile(options?: SaveDialogOptions): Promise<SaveDialogResult | null>;
+    };
+    Window: {
+        ShowEmojiPicker(): Promise<any>;
+        Fullscreen(): Promise<any>;
+        UnFullscreen(): Promise<any>;
+        Minimise(): Promise<any>;
+        Maximise(): Promise<any>;
+        Restore(): Promise<any>;
+        Show(): Promise<any>;
+        Hide(): Promise<any>;
+        SetMinSize(width: number, height: number): Promise<any>;
+        SetMaxSize(width: number, height: number): Promise<any>;
+        SetAspectRatio(width: number, height: number): Promise<any>;
+        SetTitle(title: string): Promise<any>;
+        SetIcon(icon: string | Uint8Array | ArrayBuffer): Promise<any>;
+        RequestUserAttention(critical?: boolean): Promise<any>;
+        SetSystemGestures(enabled: boolean): Promise<any>;
+        StartDrag(files: string | string[]): Promise<any>;
+        OnFileDrop(callback: (drop: FileDrop) => void): void;
+        SetOpacity(opacity: number): Promise<any>;
+        SetProgress(progress: number): Promise<any>;
+        SetBadge(badge: string | number): Promise<any>;
+        SetMaterial(material: string): Promise<any>;
+        Materials(): Promise<string[]>;
+        Displays(): Promise<Display[]>;
+    };
+    Fonts: {
+        Families(): Promise<FontFamily[]>;
+        List(): Promise<string[]>;
+    };
+    System: {
+        Flags(): Promise<Flags>;
+        Installation(): Promise<Installation>;
+        Locale(): Promise<Locale>;
+        MachineID(): Promise<string>;
+        MoveToApplications(): Promise<string>;
+        NewID(): Promise<string>;
+        Stats(): Promise<Stats>;
+        StopWatchingStats(): Promise<any>;
+        WatchStats(interval?: number): Promise<any>;
+    };
+    Purchases: {
+        CanMakePayments(): Promise<boolean>;
+        Products(ids: string[]): Promise<Product[]>;
+        Purchase(productId: string): Promise<any>;
+        Restore(): Promise<any>;
+    };
+    Menu: {
+        ShowContextMenu(menu: ContextMenuItem[], x: number, y: number): Promise<any>;
+    };
+    Share: {
+        OnShare(callback: (content: SharedContent) => void): Promise<any>;
+    };
+    Calendar: {
+        Status(): Promise<PermissionStatus>;
+        Request(): Promise<PermissionStatus>;
+        Events(start: Date, end: Date): Promise<CalendarEvent[]>;
+        SaveEvent(event: CalendarEvent): Promise<string>;
+        DeleteEvent(id: string): Promise<any>;
+    };
+    Reminders: {
+        Status(): Promise<PermissionStatus>;
+        Request(): Promise<PermissionStatus>;
+        List(includeCompleted?: boolean): Promise<Reminder[]>;
+        Save(reminder: Reminder): Promise<string>;
+        Delete(id: string): Promise<any>;
+    };
+    Power: {
+        Status(): Promise<PowerStatus>;
+        Watch(): Promise<any>;
+        StopWatching(): Promise<any>;
+    };
+    Feedback: {
+        Beep(kind?: 'default' | 'info' | 'warning' | 'error' | 'question'): Promise<any>;
+        Haptic(pattern?: 'generic' | 'alignment' | 'level-change'): Promise<any>;
+    };
+    Touch: {
+        Status(): Promise<TouchStatus>;
+        Watch(): Promise<any>;
+        StopWatching(): Promise<any>;
+        ShowKeyboard(): Promise<any>;
+    };
+    Clipboard: {
+        GetText(): Promise<string>;
+        SetText(text: string): Promise<any>;
+        GetImage(): Promise<string | null>;
+        SetImage(image: string): Promise<any>;
+    };
+    Gamepads: {
+        List(): Promise<GamepadState[]>;
+        Watch(): Promise<any>;
+        StopWatching(): Promise<any>;
+    };
+};
+
+declare interface RetryOptions {
+    retries: number;
+    delay?: number;
+    maxDelay?: number;
+}
+
+declare interface FileFilter {
+    name: string;
+    extensions: string[];
+}
+
+declare interface OpenDialogOptions {
+    title?: string;
+    defaultDirectory?: string;
+    multiple?: boolean;
+    filters?: FileFilter[];
+}
+
+declare interface DirectoryDialogOptions {
+    title?: string;
+    defaultDirectory?: string;
+    multiple?: boolean;
+}
+
+declare interface SaveDialogOptions {
+    title?: string;
+    defaultDirectory?: string;
+    defaultFilename?: string;
+    formats?: FileFilter[];
+    disableCreateDirectories?: boolean;
+    overwrite?: 0 | 1 | 2;
+}
+
+declare interface SaveDialogResult {
+    path: string;
+    format: FileFilter | null;
+}
+
+declare interface ContextMenuItem {
+    id?: string;
+    label?: string;
+    disabled?: boolean;
+    checked?: boolean;
+    separator?: boolean;
+    submenu?: ContextMenuItem[];
+}
+
+declare interface SharedContent {
+    text: string;
+    files: string[];
+}
+
+declare interface FileDrop {
+    files: string[];
+    x: number;
+    y: number;
+}
+
+declare interface StoreRecovery {
+    name: string;
+    filename: string;
+    backup: string;
+    error: string;
+}
+
+declare interface CalendarEvent {
+    id?: string;
+    calendar?: string;
+    title: string;
+    notes?: string;
+    location?: string;
+    start: Date | string;
+    end: Date | string;
+    allDay?: boolean;
+}
+
+declare interface Reminder {
+    id?: string;
+    list?: string;
+    title: string;
+    notes?: string;
+    due?: Date | string;
+    completed?: boolean;
+}
+
+declare interface PowerStatus {
+    hasBattery: boolean;
+    battery: number;
+    charging: boolean;
+    pluggedIn: boolean;
+    lowPowerMode: boolean;
+    thermal: 'nominal' | 'fair' | 'serious' | 'critical' | 'unknown';
+}
+
+declare interface TouchStatus {
+    touchscreen: boolean;
+    maxTouchPoints: number;
+    tabletMode: boolean;
+}
+
+declare interface GamepadState {
+    index: number;
+    id: string;
+    mapping: 'standard' | '';
+    buttons: { pressed: boolean; value: number }[];
+    axes: number[];
+    timestamp: number;
+}
+
+declare type Permission = 'screen-recording' | 'notifications' | 'camera' | 'calendar' | 'reminders';
+
+declare type PermissionStatus = 'granted' | 'denied' | 'not-determined' | 'unknown';
+
+declare interface Colour {
+    r: number;
+    g: number;
+    b: number;
+    a: number;
+    hex: string;
+}
+
+declare interface Font {
+    family: string;
+    style: string;
+    size: number;
+    weight: number;
+    bold: boolean;
+    italic: boolean;
+}
+
+declare interface FontFamily {
+    name: string;
+    styles: string[];
+}
+
+declare interface DisplayArea {
+    x: number;
+    y: number;
+    width: number;
+    height: number;
+}
+
+declare interface Display {
+    id: number;
+    bounds: DisplayArea;
+    workArea: DisplayArea;
+    scaleFactor: number;
+    primary: boolean;
+}
+
+declare interface Locale {
+    tag: string;
+    language: string;
+    region: string;
+    firstDayOfWeek: number;
+    decimalSeparator: string;
+    uses24HourClock: boolean;
+}
+
+declare interface Installation {
+    path: string;
+    originalPath: string;
+    translocated: boolean;
+    quarantined: boolean;
+    inApplications: boolean;
+}
+
+declare interface Stats {
+    cpu: number;
+    memory: number;
+    goroutines: number;
+    webviewMemory: number;
+}
+
+declare interface Product {
+    id: string;
+    title: string;
+    description: string;
+    price: string;
+}
+
+declare interface Transaction {
+    productId: string;
+    transactionId: string;
+    state: 'purchased' | 'pending' | 'restored' | 'cancelled' | 'failed';
+    receipt?: string;
+    error?: string;
+}
+
+declare interface Flags {
+    minimized: boolean;
+    share: boolean;
+    profile: string;
+    headlessCommand: string;
+    args: string[];
+}
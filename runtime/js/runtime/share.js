@@ -0,0 +1,28 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Calls the callback with what other apps share with this one, an object
+ * with text and files fields, starting with the shares that arrived
+ * before the frontend loaded
+ *
+ * @export
+ * @param {function} callback
+ * @returns {Promise}
+ */
+function OnShare(callback) {
+	return window.wails.Share.OnShare(callback);
+}
+
+module.exports = {
+	OnShare: OnShare
+};
@@ -0,0 +1,52 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Returns a sanitized report of the system the app is running on
+ *
+ * @export
+ * @returns {Promise<object>}
+ */
+function SystemReport() {
+	return window.wails.Support.SystemReport();
+}
+
+/**
+ * Opens the app's issue tracker prefilled with the given title,
+ * description and the system report
+ *
+ * @export
+ * @param {string} title
+ * @param {string} description
+ * @returns {Promise}
+ */
+function ReportBug(title, description) {
+	return window.wails.Support.ReportBug(title, description);
+}
+
+/**
+ * Composes a support email with the given subject and description
+ *
+ * @export
+ * @param {string} subject
+ * @param {string} description
+ * @returns {Promise<string>}
+ */
+function EmailSupport(subject, description) {
+	return window.wails.Support.EmailSupport(subject, description);
+}
+
+module.exports = {
+	SystemReport: SystemReport,
+	ReportBug: ReportBug,
+	EmailSupport: EmailSupport
+};
@@ -0,0 +1,116 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Returns the user's locale as set in the OS settings
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+function Locale() {
+	return window.wails.System.Locale();
+}
+
+/**
+ * Returns an ID for the machine that is stable across launches
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+function MachineID() {
+	return window.wails.System.MachineID();
+}
+
+/**
+ * Returns a new ULID from the app-wide generator
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+function NewID() {
+	return window.wails.System.NewID();
+}
+
+/**
+ * Returns the resource usage of the app
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+function Stats() {
+	return window.wails.System.Stats();
+}
+
+/**
+ * Emits 'wails:system:stats' with the app's resource usage at the given interval
+ *
+ * @export
+ * @param {number} [interval] - The interval in milliseconds
+ * @returns {Promise}
+ */
+function WatchStats(interval) {
+	return window.wails.System.WatchStats(interval);
+}
+
+/**
+ * Stops the events started with WatchStats
+ *
+ * @export
+ * @returns {Promise}
+ */
+function StopWatchingStats() {
+	return window.wails.System.StopWatchingStats();
+}
+
+/**
+ * Returns the flags the app was launched with
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+function Flags() {
+	return window.wails.System.Flags();
+}
+
+/**
+ * Returns where the app is running from, and whether MacOS has translocated
+ * or quarantined it
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+function Installation() {
+	return window.wails.System.Installation();
+}
+
+/**
+ * Moves the app to the Applications folder and launches it from there once
+ * this instance quits. MacOS only.
+ *
+ * @export
+ * @returns {Promise<string>}
+ */
+function MoveToApplications() {
+	return window.wails.System.MoveToApplications();
+}
+
+module.exports = {
+	Locale: Locale,
+	MachineID: MachineID,
+	NewID: NewID,
+	Stats: Stats,
+	WatchStats: WatchStats,
+	StopWatchingStats: StopWatchingStats,
+	Flags: Flags,
+	Installation: Installation,
+	MoveToApplications: MoveToApplications
+};
@@ -0,0 +1,59 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+
+/**
+ * Returns whether the machine can be used by touch
+ *
+ * @export
+ * @returns {Promise<Object>}
+ */
+function Status() {
+	return window.wails.Touch.Status();
+}
+
+/**
+ * Emits 'wails:touch:changed' whenever the status changes
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Watch() {
+	return window.wails.Touch.Watch();
+}
+
+/**
+ * Stops the events started with Watch
+ *
+ * @export
+ * @returns {Promise}
+ */
+function StopWatching() {
+	return window.wails.Touch.StopWatching();
+}
+
+/**
+ * Shows the on-screen keyboard
+ *
+ * @export
+ * @returns {Promise}
+ */
+function ShowKeyboard() {
+	return window.wails.Touch.ShowKeyboard();
+}
+
+module.exports = {
+	Status: Status,
+	Watch: Watch,
+	StopWatching: StopWatching,
+	ShowKeyboard: ShowKeyboard
+};
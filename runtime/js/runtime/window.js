@@ -0,0 +1,293 @@
+/*
+ _       __      _ __    
+| |     / /___ _(_) /____
+| | /| / / __ `/ / / ___/
+| |/ |/ / /_/ / / (__  ) 
+|__/|__/\__,_/_/_/____/  
+The lightweight framework for web-like apps
+(c) Lea Anthony 2019-present
+*/
+/* jshint esversion: 6 */
+
+
+/**
+ * Opens the system emoji and character picker for the focused input
+ *
+ * @export
+ * @returns {Promise}
+ */
+function ShowEmojiPicker() {
+	return window.wails.Window.ShowEmojiPicker();
+}
+
+/**
+ * Makes the window fullscreen
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Fullscreen() {
+	return window.wails.Window.Fullscreen();
+}
+
+/**
+ * Restores the window to its size and position before it was made fullscreen
+ *
+ * @export
+ * @returns {Promise}
+ */
+function UnFullscreen() {
+	return window.wails.Window.UnFullscreen();
+}
+
+/**
+ * Minimises the window
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Minimise() {
+	return window.wails.Window.Minimise();
+}
+
+/**
+ * Maximises the window
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Maximise() {
+	return window.wails.Window.Maximise();
+}
+
+/**
+ * Returns a minimised or maximised window to its previous size and position
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Restore() {
+	return window.wails.Window.Restore();
+}
+
+/**
+ * Shows the window and brings it to the front
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Show() {
+	return window.wails.Window.Show();
+}
+
+/**
+ * Hides the window without closing it
+ *
+ * @export
+ * @returns {Promise}
+ */
+function Hide() {
+	return window.wails.Window.Hide();
+}
+
+/**
+ * Sets the minimum size of a resizable window
+ *
+ * @export
+ * @param {number} width
+ * @param {number} height
+ * @returns {Promise}
+ */
+function SetMinSize(width, height) {
+	return window.wails.Window.SetMinSize(width, height);
+}
+
+/**
+ * Sets the maximum size of a resizable window
+ *
+ * @export
+ * @param {number} width
+ * @param {number} height
+ * @returns {Promise}
+ */
+function SetMaxSize(width, height) {
+	return window.wails.Window.SetMaxSize(width, height);
+}
+
+/**
+ * Keeps the content of a resizable window at the ratio of the given width
+ * and height as it is resized. Use 0 and 0 to unlock it.
+ *
+ * @export
+ * @param {number} width
+ * @param {number} height
+ * @returns {Promise}
+ */
+function SetAspectRatio(width, height) {
+	return window.wails.Window.SetAspectRatio(width, height);
+}
+
+/**
+ * Calls the callback with the paths of the files dropped on the window from
+ * the desktop, and where they were dropped
+ *
+ * @export
+ * @param {function} callback
+ */
+function OnFileDrop(callback) {
+	window.wails.Window.OnFileDrop(callback);
+}
+
+/**
+ * Turns the system's pen and touch gestures over the page on or off, EG:
+ * for drawing apps
+ *
+ * @export
+ * @param {boolean} enabled
+ * @returns {Promise}
+ */
+function SetSystemGestures(enabled) {
+	return window.wails.Window.SetSystemGestures(enabled);
+}
+
+/**
+ * Starts dragging the files out of the window, EG: to the file manager.
+ * It must be called while the mouse button is held.
+ *
+ * @export
+ * @param {string|string[]} files
+ * @returns {Promise}
+ */
+function StartDrag(files) {
+	return window.wails.Window.StartDrag(files);
+}
+
+/**
+ * Draws the user's attention to the window when it isn't focused, EG: by
+ * flashing its taskbar button or bouncing the dock icon. Critical requests
+ * continue until the user switches to the app.
+ *
+ * @export
+ * @param {boolean=} critical
+ * @returns {Promise}
+ */
+function RequestUserAttention(critical) {
+	return window.wails.Window.RequestUserAttention(critical);
+}
+
+/**
+ * Fades the whole window, from 0 for invisible to 1 for opaque
+ *
+ * @export
+ * @param {number} opacity
+ * @returns {Promise}
+ */
+function SetOpacity(opacity) {
+	return window.wails.Window.SetOpacity(opacity);
+}
+
+/**
+ * Shows the progress of a long task, from 0 to 1, on the taskbar button,
+ * dock icon or launcher entry. Negative progress hides it.
+ *
+ * @export
+ * @param {number} progress
+ * @returns {Promise}
+ */
+function SetProgress(progress) {
+	return window.wails.Window.SetProgress(progress);
+}
+
+/**
+ * Shows a short label, EG: an unread count, on the taskbar button, dock
+ * icon or launcher entry. An empty badge hides it.
+ *
+ * @export
+ * @param {string} badge
+ * @returns {Promise}
+ */
+function SetBadge(badge) {
+	return window.wails.Window.SetBadge(badge);
+}
+
+/**
+ * Fills the window's background with a material of the platform, EG:
+ * 'sidebar' on MacOS or 'mica' on Windows 11, or removes it with 'none'.
+ * It shows where the page leaves its background transparent.
+ *
+ * @export
+ * @param {string} material
+ * @returns {Promise}
+ */
+function SetMaterial(material) {
+	return window.wails.Window.SetMaterial(material);
+}
+
+/**
+ * Gets the materials the window supports
+ *
+ * @export
+ * @returns {Promise<string[]>}
+ */
+function Materials() {
+	return window.wails.Window.Materials();
+}
+
+/**
+ * Returns the displays attached to the system
+ *
+ * @export
+ * @returns {Promise<Object[]>}
+ */
+function Displays() {
+	return window.wails.Window.Displays();
+}
+
+/**
+ * Sets the window title
+ *
+ * @export
+ * @param {string} title
+ * @returns {Promise}
+ */
+function SetTitle(title) {
+	return window.wails.Window.SetTitle(title);
+}
+
+/**
+ * Sets the window icon to a PNG or ICO image, given as a data URL, base64 or
+ * bytes. An empty icon restores the app's icon.
+ *
+ * @export
+ * @param {string|Uint8Array|ArrayBuffer} icon
+ * @returns {Promise}
+ */
+function SetIcon(icon) {
+	return window.wails.Window.SetIcon(icon);
+}
+
+module.exports = {
+	ShowEmojiPicker: ShowEmojiPicker,
+	Fullscreen: Fullscreen,
+	UnFullscreen: UnFullscreen,
+	Minimise: Minimise,
+	Maximise: Maximise,
+	Restore: Restore,
+	Show: Show,
+	Hide: Hide,
+	SetMinSize: SetMinSize,
+	SetMaxSize: SetMaxSize,
+	SetAspectRatio: SetAspectRatio,
+	SetTitle: SetTitle,
+	SetIcon: SetIcon,
+	RequestUserAttention: RequestUserAttention,
+	SetSystemGestures: SetSystemGestures,
+	StartDrag: StartDrag,
+	OnFileDrop: OnFileDrop,
+	SetOpacity: SetOpacity,
+	SetProgress: SetProgress,
+	SetBadge: SetBadge,
+	SetMaterial: SetMaterial,
+	Materials: Materials,
+	Displays: Displays
+};
@@ -0,0 +1,166 @@
+/* jshint esversion: 8 */
+
+// Tests the call retries of the built runtime, runtime/assets/wails.js.
+// Run them with `npm test` after `npm run build`.
+
+const assert = require('assert');
+const fs = require('fs');
+const path = require('path');
+const test = require('node:test');
+const vm = require('vm');
+
+const bundle = fs.readFileSync(path.join(__dirname, '..', '..', 'assets', 'wails.js'), 'utf8');
+
+// stub returns an object whose properties and results are all stubs, for
+// the browser APIs the runtime touches while it loads
+function stub() {
+	const target = function () {
+		return stub();
+	};
+	return new Proxy(target, {
+		get: function (t, key) {
+			if (key === Symbol.toPrimitive) {
+				return function () {
+					return '';
+				};
+			}
+			if (key === 'then') {
+				return undefined;
+			}
+			if (!(key in t)) {
+				t[key] = stub();
+			}
+			return t[key];
+		},
+	});
+}
+
+// load runs the runtime in a new window whose backend then fails the next
+// `failures` messages. Timers only run when flushed, so the delays before
+// each retry are recorded.
+function load(failures) {
+	const window = {
+		sent: [],
+		delays: [],
+		timers: [],
+		encoded: 0,
+	};
+	Object.assign(window, {
+		window: window,
+		document: stub(),
+		navigator: { userAgent: 'node', platform: 'Linux' },
+		location: { href: 'http://localhost' },
+		performance: { getEntries: function () { return []; }, now: function () { return 0; } },
+		matchMedia: function () {
+			return { matches: false, addListener: function () {}, addEventListener: function () {} };
+		},
+		addEventListener: function () {},
+		console: { log: function () {}, error: function () {} },
+		external: {
+			invoke: function (message) {
+				if (window.failures > 0) {
+					window.failures--;
+					throw Error('not connected');
+				}
+				window.sent.push(JSON.parse(message));
+			},
+		},
+		btoa: function (data) {
+			window.encoded++;
+			return Buffer.from(data, 'binary').toString('base64');
+		},
+		setTimeout: function (callback, delay) {
+			window.delays.push(delay);
+			window.timers.push(callback);
+			return window.timers.length;
+		},
+		clearTimeout: function () {},
+	});
+	window.flush = function () {
+		while (window.timers.length > 0) {
+			window.timers.shift()();
+		}
+	};
+	vm.runInNewContext(bundle, window);
+	window.wails._.NewBinding('main.Images.Save');
+	window.sent = [];
+	window.failures = failures;
+	return window;
+}
+
+test('retries with a doubling backoff up to the maximum delay', function () {
+	const window = load(3);
+	window.wails.Calls.SetRetry({ retries: 5, delay: 100, maxDelay: 300 });
+	window.backend.Images.Save('image');
+	window.flush();
+
+	assert.deepStrictEqual(window.delays, [100, 200, 300]);
+	assert.strictEqual(window.sent.length, 1);
+	assert.strictEqual(window.sent[0].payload.data, '["image"]');
+});
+
+test('rejects the call once the retries run out', async function () {
+	const window = load(Infinity);
+	window.wails.Calls.SetRetry({ retries: 2, delay: 50 });
+	const result = window.backend.Images.Save('image');
+	window.flush();
+
+	assert.deepStrictEqual(window.delays, [50, 100]);
+	await assert.rejects(result, /failed after 2 retries: not connected/);
+});
+
+test('binding retry options override the global ones', async function () {
+	const window = load(Infinity);
+	window.wails.Calls.SetRetry({ retries: 5 });
+	window.backend.Images.Save.setRetry({ retries: 1, delay: 10 });
+	const result = window.backend.Images.Save('image');
+	window.flush();
+
+	assert.deepStrictEqual(window.delays, [10]);
+	await assert.rejects(result, /failed after 1 retries/);
+});
+
+test('does not retry by default', function () {
+	const window = load(1);
+	window.backend.Images.Save('image');
+	window.flush();
+
+	assert.deepStrictEqual(window.delays, []);
+	assert.strictEqual(window.sent.length, 0);
+});
+
+test('encodes bytes once for every attempt', function () {
+	const window = load(2);
+	window.wails.Calls.SetRetry({ retries: 2, delay: 10 });
+	window.backend.Images.Save(new Uint8Array([1, 2, 3]));
+	window.flush();
+
+	assert.strictEqual(window.encoded, 1);
+	assert.strictEqual(window.sent.length, 1);
+	assert.deepStrictEqual(window.sent[0].payload.binary, ['AQID']);
+});
+
+test('does not send the chunks again when the call is retried', function () {
+	const window = load(0);
+	window.wails._.SetMaxPayloadSize(4);
+	window.wails.Calls.SetRetry({ retries: 2, delay: 10 });
+
+	// Fail the call after its chunks have been sent
+	const invoke = window.external.invoke;
+	var failed = false;
+	window.external.invoke = function (message) {
+		if (!failed && JSON.parse(message).type === 'call') {
+			failed = true;
+			throw Error('not connected');
+		}
+		invoke(message);
+	};
+	window.backend.Images.Save('abcdefghij');
+	window.flush();
+
+	const sent = window.sent.map(function (message) {
+		return message.type + ':' + message.payload.data;
+	});
+	assert.deepStrictEqual(sent, ['chunk:["ab', 'chunk:cdef', 'chunk:ghij', 'call:"]']);
+	assert.deepStrictEqual(window.delays, [10]);
+});
@@ -0,0 +1,459 @@
+package runtime
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+)
+
+// MenuClickEvent is emitted with the number of the menu bar item that was
+// chosen
+const MenuClickEvent = "wails:menu:click"
+
+// MenuCheckedEvent is emitted with the ID of a checkbox or radio item and
+// whether it's now checked, for each item changed by choosing one
+const MenuCheckedEvent = "wails:menu:checked"
+
+// MenuItem is an item in the menu bar. The items of the bar itself are its
+// menus, EG: File, Edit and Help, and their items are in SubMenu. An item
+// with a SubMenu opens it rather than being chosen.
+//
+// Accelerator is a shortcut for the item, EG: "CmdOrCtrl+S", "Alt+F4" or
+// "Shift+Delete". CmdOrCtrl is Cmd on MacOS and Ctrl elsewhere.
+//
+// A Checkbox item is checked and unchecked when chosen. Choosing an item
+// with a RadioGroup checks it and unchecks the other items in the group.
+//
+// An item with a Role does what the platform's standard item does, and its
+// Label and Accelerator default to the standard ones. Its OnClick isn't
+// called.
+type MenuItem struct {
+	ID          string
+	Label       string
+	Accelerator string
+	Role        MenuRole
+	Disabled    bool
+	Checked     bool
+	Checkbox    bool
+	RadioGroup  string
+	Separator   bool
+	SubMenu     []*MenuItem
+
+	// Called when the item is chosen
+	OnClick func()
+}
+
+// MenuRole is a standard menu item's behaviour, which is done natively
+type MenuRole string
+
+// The roles of standard menu items
+const (
+	// MenuRoleCut, MenuRoleCopy, MenuRolePaste and MenuRoleSelectAll edit
+	// the page's selection, or the focused text field
+	MenuRoleCut       MenuRole = "cut"
+	MenuRoleCopy      MenuRole = "copy"
+	MenuRolePaste     MenuRole = "paste"
+	MenuRoleSelectAll MenuRole = "selectall"
+	// MenuRoleQuit closes the window as if the user had, so
+	// AppConfig.OnBeforeClose is still called
+	MenuRoleQuit MenuRole = "quit"
+	// MenuRoleMinimize minimises the window
+	MenuRoleMinimize MenuRole = "minimize"
+	// MenuRoleAbout shows the about dialog, as Dialog.About does
+	MenuRoleAbout MenuRole = "about"
+)
+
+// menuRoleDefaults are the labels and accelerators of the standard items
+var menuRoleDefaults = map[MenuRole][2]string{
+	MenuRoleCut:       {"Cut", "CmdOrCtrl+X"},
+	MenuRoleCopy:      {"Copy", "CmdOrCtrl+C"},
+	MenuRolePaste:     {"Paste", "CmdOrCtrl+V"},
+	MenuRoleSelectAll: {"Select All", "CmdOrCtrl+A"},
+	MenuRoleQuit:      {"Quit", "CmdOrCtrl+Q"},
+	MenuRoleMinimize:  {"Minimize", "CmdOrCtrl+M"},
+	MenuRoleAbout:     {"About", ""},
+}
+
+// EditMenu returns an Edit menu with the standard Cut, Copy, Paste and
+// Select All items
+func EditMenu() *MenuItem {
+	return &MenuItem{
+		Label: "Edit",
+		SubMenu: []*MenuItem{
+			{Role: MenuRoleCut},
+			{Role: MenuRoleCopy},
+			{Role: MenuRolePaste},
+			{Separator: true},
+			{Role: MenuRoleSelectAll},
+		},
+	}
+}
+
+// Menu is a menu bar for the app. Set it with AppConfig.Menu or
+// Runtime.MenuBar. Changes made with its methods are shown straight away.
+// On MacOS, its menus follow the app menu and the Edit menu.
+type Menu struct {
+	lock     sync.Mutex
+	items    []*MenuItem
+	renderer interfaces.Renderer
+}
+
+// NewMenu creates a new Menu with the given menus
+func NewMenu(items ...*MenuItem) *Menu {
+	return &Menu{
+		items: items,
+	}
+}
+
+// Items returns the menus in the bar
+func (m *Menu) Items() []*MenuItem {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]*MenuItem{}, m.items...)
+}
+
+// Append adds the items to the end of the parent's submenu, or adds menus
+// to the bar if the parent is nil
+func (m *Menu) Append(parent *MenuItem, items ...*MenuItem) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if parent == nil {
+		m.items = append(m.items, items...)
+	} else {
+		parent.SubMenu = append(parent.SubMenu, items...)
+	}
+	m.update()
+}
+
+// Remove takes the item out of the menu, wherever it is
+func (m *Menu) Remove(item *MenuItem) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.items = removeMenuItem(m.items, item)
+	m.update()
+}
+
+// SetEnabled enables or disables the item
+func (m *Menu) SetEnabled(item *MenuItem, enabled bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	item.Disabled = !enabled
+	m.update()
+}
+
+// SetChecked shows or hides the item's check mark. Checking a radio item
+// unchecks the other items in its group.
+func (m *Menu) SetChecked(item *MenuItem, checked bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if checked && item.RadioGroup != "" {
+		m.check(item)
+	} else {
+		item.Checked = checked
+	}
+	m.update()
+}
+
+// SetLabel changes the item's label
+func (m *Menu) SetLabel(item *MenuItem, label string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	item.Label = label
+	m.update()
+}
+
+// Update shows the changes made to the items directly
+func (m *Menu) Update() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.update()
+}
+
+// update gives the menu to the renderer, if it's shown. The lock must be
+// held.
+func (m *Menu) update() {
+	if m.renderer != nil {
+		m.renderer.SetMenu(encodeMenu(m.items))
+	}
+}
+
+// item returns the item with the given number
+func (m *Menu) item(number int) *MenuItem {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return findMenuItem(m.items, number)
+}
+
+// choose toggles a checkbox item or checks a radio item, returning the
+// items that changed
+func (m *Menu) choose(item *MenuItem) []*MenuItem {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var changed []*MenuItem
+	switch {
+	case item.Checkbox:
+		item.Checked = !item.Checked
+		changed = []*MenuItem{item}
+	case item.RadioGroup != "":
+		changed = m.check(item)
+	}
+	if len(changed) > 0 {
+		m.update()
+	}
+	return changed
+}
+
+// check checks the radio item and unchecks the others in its group,
+// returning the items that changed. The lock must be held.
+func (m *Menu) check(item *MenuItem) []*MenuItem {
+	var changed []*MenuItem
+	var walk func(items []*MenuItem)
+	walk = func(items []*MenuItem) {
+		for _, other := range items {
+			checked := other == item
+			if other.RadioGroup == item.RadioGroup && other.Checked != checked {
+				other.Checked = checked
+				changed = append(changed, other)
+			}
+			walk(other.SubMenu)
+		}
+	}
+	walk(m.items)
+	return changed
+}
+
+// findMenuItem returns the item with the given number, counting every item
+// in the order they are encoded
+func findMenuItem(items []*MenuItem, number int) *MenuItem {
+	var find func(items []*MenuItem) *MenuItem
+	find = func(items []*MenuItem) *MenuItem {
+		for _, item := range items {
+			if number == 0 {
+				return item
+			}
+			number--
+			if !item.Separator {
+				if found := find(item.SubMenu); found != nil {
+					return found
+				}
+			}
+		}
+		return nil
+	}
+	return find(items)
+}
+
+// removeMenuItem returns the items without the given item or its submenu
+func removeMenuItem(items []*MenuItem, item *MenuItem) []*MenuItem {
+	result := items[:0]
+	for _, existing := range items {
+		if existing == item {
+			continue
+		}
+		existing.SubMenu = removeMenuItem(existing.SubMenu, item)
+		result = append(result, existing)
+	}
+	return result
+}
+
+// encodeMenu encodes the items as expected by the renderer: a line per item
+// as for the tray's menu, indented with a tab per level. Radio items are
+// checked with "2" or "3" rather than "0" or "1", an item's accelerator
+// follows its label after a tab and its role follows after another.
+func encodeMenu(items []*MenuItem) string {
+	var lines []string
+	var encode func(items []*MenuItem, depth int)
+	encode = func(items []*MenuItem, depth int) {
+		indent := strings.Repeat("\t", depth)
+		for _, item := range items {
+			label, accelerator := item.Label, item.Accelerator
+			if defaults, ok := menuRoleDefaults[item.Role]; ok {
+				if label == "" {
+					label = defaults[0]
+				}
+				if accelerator == "" {
+					accelerator = defaults[1]
+				}
+			}
+			line := encodeMenuLine(label, item.Disabled, item.Checked, item.Separator)
+			if !item.Separator {
+				if item.RadioGroup != "" {
+					line = line[:1] + string(line[1]+2) + line[2:]
+				}
+				accelerator = encodeAccelerator(accelerator)
+				if item.Role != "" {
+					line += "\t" + accelerator + "\t" + string(item.Role)
+				} else if accelerator != "" {
+					line += "\t" + accelerator
+				}
+			}
+			lines = append(lines, indent+line)
+			if !item.Separator {
+				encode(item.SubMenu, depth+1)
+			}
+		}
+	}
+	encode(items, 0)
+	return strings.Join(lines, "\n")
+}
+
+// MenuBar shows the app's Menu
+type MenuBar struct {
+	eventManager interfaces.EventManager
+	renderer     interfaces.Renderer
+	lock         sync.Mutex
+	menu         *Menu
+}
+
+// NewMenuBar creates a new MenuBar struct
+func NewMenuBar(eventManager interfaces.EventManager, renderer interfaces.Renderer) *MenuBar {
+	result := &MenuBar{
+		eventManager: eventManager,
+		renderer:     renderer,
+	}
+	eventManager.On(MenuClickEvent, result.clicked)
+	return result
+}
+
+// Set shows the menu, replacing the current one. A nil menu removes the
+// menu bar.
+func (r *MenuBar) Set(menu *Menu) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.menu != nil {
+		r.menu.lock.Lock()
+		r.menu.renderer = nil
+		r.menu.lock.Unlock()
+	}
+	r.menu = menu
+	if menu == nil {
+		r.renderer.SetMenu("")
+		return
+	}
+	menu.lock.Lock()
+	defer menu.lock.Unlock()
+	menu.renderer = r.renderer
+	menu.update()
+}
+
+// Get returns the menu being shown, or nil if there isn't one
+func (r *MenuBar) Get() *Menu {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.menu
+}
+
+// clicked updates the chosen item's check mark and calls its handler
+func (r *MenuBar) clicked(data ...interface{}) {
+	if len(data) == 0 {
+		return
+	}
+	var number int
+	switch value := data[0].(type) {
+	case int:
+		number = value
+	case float64:
+		number = int(value)
+	default:
+		return
+	}
+
+	r.lock.Lock()
+	menu := r.menu
+	r.lock.Unlock()
+	if menu == nil {
+		return
+	}
+	item := menu.item(number)
+	if item == nil || item.Separator {
+		return
+	}
+	for _, changed := range menu.choose(item) {
+		r.eventManager.Emit(MenuCheckedEvent, changed.ID, changed.Checked)
+	}
+	if item.OnClick != nil {
+		item.OnClick()
+	}
+}
+
+// acceleratorKeys are the names of keys that aren't a single character, as
+// understood by the renderer
+var acceleratorKeys = map[string]string{
+	"enter":      "Enter",
+	"return":     "Enter",
+	"escape":     "Escape",
+	"esc":        "Escape",
+	"tab":        "Tab",
+	"backspace":  "Backspace",
+	"delete":     "Delete",
+	"del":        "Delete",
+	"insert":     "Insert",
+	"home":       "Home",
+	"end":        "End",
+	"pageup":     "PageUp",
+	"pagedown":   "PageDown",
+	"up":         "ArrowUp",
+	"down":       "ArrowDown",
+	"left":       "ArrowLeft",
+	"right":      "ArrowRight",
+	"arrowup":    "ArrowUp",
+	"arrowdown":  "ArrowDown",
+	"arrowleft":  "ArrowLeft",
+	"arrowright": "ArrowRight",
+	"space":      "Space",
+	"plus":       "+",
+}
+
+// encodeAccelerator encodes an accelerator such as "CmdOrCtrl+Shift+S" as
+// expected by the renderer: a hex digit of the modifier flags followed by
+// the key. An invalid accelerator gives "".
+func encodeAccelerator(accelerator string) string {
+	if accelerator == "" {
+		return ""
+	}
+	parts := strings.Split(accelerator, "+")
+	// "CmdOrCtrl++" ends with the + key
+	if strings.HasSuffix(accelerator, "++") {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+	modifiers := 0
+	for _, modifier := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(modifier)) {
+		case "cmdorctrl", "commandorcontrol":
+			if runtime.GOOS == "darwin" {
+				modifiers |= 8
+			} else {
+				modifiers |= 2
+			}
+		case "cmd", "command", "super", "meta":
+			modifiers |= 8
+		case "ctrl", "control":
+			modifiers |= 2
+		case "alt", "option":
+			modifiers |= 4
+		case "shift":
+			modifiers |= 1
+		default:
+			return ""
+		}
+	}
+	key := strings.TrimSpace(parts[len(parts)-1])
+	if name, ok := acceleratorKeys[strings.ToLower(key)]; ok {
+		key = name
+	} else if len(key) > 1 && (key[0] == 'F' || key[0] == 'f') {
+		number, err := strconv.Atoi(key[1:])
+		if err != nil || number < 1 || number > 24 || strconv.Itoa(number) != key[1:] {
+			return ""
+		}
+		key = "F" + key[1:]
+	} else if len(key) == 1 && key[0] > ' ' && key[0] < 0x7f {
+		key = strings.ToLower(key)
+	} else {
+		return ""
+	}
+	return fmt.Sprintf("%x%s", modifiers, key)
+}
@@ -0,0 +1,202 @@
+package runtime
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+)
+
+// fakeMenuRenderer records the menus given to the renderer
+type fakeMenuRenderer struct {
+	interfaces.Renderer
+	menus []string
+}
+
+func (f *fakeMenuRenderer) SetMenu(menu string) {
+	f.menus = append(f.menus, menu)
+}
+
+// fakeMenuEvents records the events emitted and keeps the handlers
+type fakeMenuEvents struct {
+	interfaces.EventManager
+	handlers map[string]func(...interface{})
+	emitted  [][]interface{}
+}
+
+func (f *fakeMenuEvents) On(eventName string, callback func(...interface{})) {
+	f.handlers[eventName] = callback
+}
+
+func (f *fakeMenuEvents) Emit(eventName string, optionalData ...interface{}) {
+	f.emitted = append(f.emitted, append([]interface{}{eventName}, optionalData...))
+}
+
+func TestEncodeAccelerator(t *testing.T) {
+	cmdOrCtrl, cmdOrCtrlShift := "2", "3"
+	if runtime.GOOS == "darwin" {
+		cmdOrCtrl, cmdOrCtrlShift = "8", "9"
+	}
+	tests := []struct {
+		accelerator string
+		want        string
+	}{
+		{"", ""},
+		{"CmdOrCtrl+S", cmdOrCtrl + "s"},
+		{"CmdOrCtrl+Shift+S", cmdOrCtrlShift + "s"},
+		{"Cmd+Q", "8q"},
+		{"Alt+F4", "4F4"},
+		{"Shift+Delete", "1Delete"},
+		{"ctrl + esc", "2Escape"},
+		{"Control+Option+Up", "6ArrowUp"},
+		{"CmdOrCtrl++", cmdOrCtrl + "+"},
+		{"Ctrl+Plus", "2+"},
+		{"F12", "0F12"},
+		{"F25", ""},
+		{"F01", ""},
+		{"Hyper+S", ""},
+		{"Ctrl+Escape2", ""},
+		{"Ctrl+é", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accelerator, func(t *testing.T) {
+			if got := encodeAccelerator(tt.accelerator); got != tt.want {
+				t.Errorf("encodeAccelerator(%q) = %q, want %q", tt.accelerator, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeMenu(t *testing.T) {
+	menu := []*MenuItem{
+		{
+			Label: "File",
+			SubMenu: []*MenuItem{
+				{Label: "Save", Accelerator: "Ctrl+S"},
+				{Label: "Autosave", Checkbox: true, Checked: true},
+				{Separator: true},
+				{Label: "Print", Disabled: true},
+				{Role: MenuRoleQuit, Accelerator: "Alt+F4"},
+			},
+		},
+		{
+			Label: "View",
+			SubMenu: []*MenuItem{
+				{Label: "Light", RadioGroup: "theme", Checked: true},
+				{Label: "Dark", RadioGroup: "theme"},
+			},
+		},
+		{Label: "Help\twith\nlines"},
+	}
+	want := "10File\n" +
+		"\t10Save\t2s\n" +
+		"\t11Autosave\n" +
+		"\t-\n" +
+		"\t00Print\n" +
+		"\t10Quit\t4F4\tquit\n" +
+		"10View\n" +
+		"\t13Light\n" +
+		"\t12Dark\n" +
+		"10Help with lines"
+	if got := encodeMenu(menu); got != want {
+		t.Errorf("encodeMenu() = %q, want %q", got, want)
+	}
+}
+
+func TestMenuConstruction(t *testing.T) {
+	save := &MenuItem{Label: "Save"}
+	file := &MenuItem{Label: "File", SubMenu: []*MenuItem{save}}
+	menu := NewMenu(file)
+	renderer := &fakeMenuRenderer{}
+	bar := NewMenuBar(&fakeMenuEvents{handlers: map[string]func(...interface{}){}}, renderer)
+
+	// Changes aren't given to the renderer until the menu is shown
+	menu.Append(nil, EditMenu())
+	if len(renderer.menus) != 0 {
+		t.Fatalf("a menu that isn't shown was given to the renderer")
+	}
+	bar.Set(menu)
+	if len(menu.Items()) != 2 || bar.Get() != menu {
+		t.Fatalf("Set() showed %d menus", len(menu.Items()))
+	}
+
+	closeItem := &MenuItem{Label: "Close"}
+	menu.Append(file, closeItem)
+	menu.SetEnabled(save, false)
+	menu.SetLabel(closeItem, "Close Window")
+	menu.Remove(file.SubMenu[0])
+	want := "10File\n\t10Close Window\n10Edit\n\t10Cut\t2x\tcut\n\t10Copy\t2c\tcopy\n\t10Paste\t2v\tpaste\n\t-\n\t10Select All\t2a\tselectall"
+	if runtime.GOOS == "darwin" {
+		want = "10File\n\t10Close Window\n10Edit\n\t10Cut\t8x\tcut\n\t10Copy\t8c\tcopy\n\t10Paste\t8v\tpaste\n\t-\n\t10Select All\t8a\tselectall"
+	}
+	if got := renderer.menus[len(renderer.menus)-1]; got != want {
+		t.Errorf("the renderer was given %q, want %q", got, want)
+	}
+	if len(renderer.menus) != 5 {
+		t.Errorf("the renderer was given %d menus, want one for each change", len(renderer.menus))
+	}
+
+	// Replacing the menu detaches the old one
+	bar.Set(nil)
+	menu.SetLabel(file, "Document")
+	if got := renderer.menus[len(renderer.menus)-1]; got != "" {
+		t.Errorf("the renderer was given %q after the menu was removed", got)
+	}
+}
+
+func TestMenuClick(t *testing.T) {
+	var clicked []string
+	click := func(name string) func() {
+		return func() { clicked = append(clicked, name) }
+	}
+	menu := NewMenu(
+		&MenuItem{
+			Label: "File",
+			SubMenu: []*MenuItem{
+				{Label: "Save", OnClick: click("save")},
+				{Separator: true},
+				{ID: "autosave", Label: "Autosave", Checkbox: true, OnClick: click("autosave")},
+			},
+		},
+		&MenuItem{
+			Label: "View",
+			SubMenu: []*MenuItem{
+				{ID: "light", Label: "Light", RadioGroup: "theme", Checked: true},
+				{ID: "dark", Label: "Dark", RadioGroup: "theme", OnClick: click("dark")},
+			},
+		},
+	)
+	events := &fakeMenuEvents{handlers: map[string]func(...interface{}){}}
+	bar := NewMenuBar(events, &fakeMenuRenderer{})
+	bar.Set(menu)
+
+	tests := []struct {
+		name    string
+		item    interface{}
+		clicked []string
+		emitted [][]interface{}
+	}{
+		{"item", 1, []string{"save"}, nil},
+		{"from the frontend", float64(1), []string{"save"}, nil},
+		{"menu", 0, nil, nil},
+		{"separator", 2, nil, nil},
+		{"checkbox", 3, []string{"autosave"}, [][]interface{}{{MenuCheckedEvent, "autosave", true}}},
+		{"radio", 6, []string{"dark"}, [][]interface{}{{MenuCheckedEvent, "light", false}, {MenuCheckedEvent, "dark", true}}},
+		{"checked radio", 6, []string{"dark"}, nil},
+		{"out of range", 7, nil, nil},
+		{"invalid", "1", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clicked, events.emitted = nil, nil
+			events.handlers[MenuClickEvent](tt.item)
+			if !reflect.DeepEqual(clicked, tt.clicked) {
+				t.Errorf("clicked %v, want %v", clicked, tt.clicked)
+			}
+			if !reflect.DeepEqual(events.emitted, tt.emitted) {
+				t.Errorf("emitted %v, want %v", events.emitted, tt.emitted)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+)
+
+// NotificationClickEvent is emitted with the ID of a notification sent with
+// Notification.Send when it is clicked
+const NotificationClickEvent = "wails:notification:click"
+
+// NotificationOptions are the optional settings of a notification
+type NotificationOptions struct {
+	// Identifies the notification in NotificationClickEvent, EG: the route
+	// to navigate to when it's clicked. One is made up if it's empty.
+	ID string
+
+	// Shows the notification without playing a sound
+	Silent bool
+
+	// Shows and focuses the window when the notification is clicked
+	Focus bool
+
+	// Called when the notification is clicked
+	OnClick func()
+}
+
+// Notification shows notifications from the app: toasts from the tray icon
+// on Windows, the Notification Center on MacOS and the desktop's
+// notifications on Linux
+type Notification struct {
+	renderer interfaces.Renderer
+	lock     sync.Mutex
+	count    int
+	pending  map[string]*NotificationOptions
+}
+
+// NewNotification creates a new Notification struct
+func NewNotification(eventManager interfaces.EventManager, renderer interfaces.Renderer) *Notification {
+	result := &Notification{
+		renderer: renderer,
+		pending:  map[string]*NotificationOptions{},
+	}
+	eventManager.On(NotificationClickEvent, result.clicked)
+	return result
+}
+
+// Send shows a notification with the given title and body, returning its
+// ID. Options may be nil. On Windows, a notification replaces the last one
+// and the window's icon is shown in the tray while it's shown. On MacOS,
+// the app must be bundled.
+func (r *Notification) Send(title, body string, options *NotificationOptions) (string, error) {
+	if options == nil {
+		options = &NotificationOptions{}
+	}
+	r.lock.Lock()
+	r.count++
+	id := options.ID
+	if id == "" {
+		id = "notification-" + strconv.Itoa(r.count)
+	}
+	r.lock.Unlock()
+
+	err := r.renderer.Notify(id, title, body, options.Silent)
+	if err != nil {
+		return "", fmt.Errorf("unable to send notification '%s': %s", id, err)
+	}
+
+	// Only the notifications that do something when clicked are kept
+	if options.Focus || options.OnClick != nil {
+		r.lock.Lock()
+		r.pending[id] = options
+		r.lock.Unlock()
+	}
+	return id, nil
+}
+
+// clicked focuses the window and calls the handler of the clicked
+// notification
+func (r *Notification) clicked(data ...interface{}) {
+	if len(data) == 0 {
+		return
+	}
+	id, ok := data[0].(string)
+	if !ok {
+		return
+	}
+
+	r.lock.Lock()
+	options := r.pending[id]
+	delete(r.pending, id)
+	r.lock.Unlock()
+
+	if options == nil {
+		return
+	}
+	if options.Focus {
+		r.renderer.Show()
+	}
+	if options.OnClick != nil {
+		options.OnClick()
+	}
+}
@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"errors"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// The permissions of the user's data, which are asked for through the
+// provider set for the Calendar and Reminders rather than Permissions
+const (
+	// PermissionCalendar allows the app to read and change calendar events
+	PermissionCalendar Permission = "calendar"
+	// PermissionReminders allows the app to read and change reminders
+	PermissionReminders Permission = "reminders"
+)
+
+// ErrDataUnavailable is returned when no provider is set for the data
+var ErrDataUnavailable = errors.New("no provider is set for this data")
+
+// ErrAccessDenied is returned when the user hasn't allowed the app to
+// access the data
+var ErrAccessDenied = errors.New("access to this data has not been granted")
+
+// DataAccess is how a provider of the user's data, EG: their calendar, asks
+// the user to allow the app to access it
+type DataAccess interface {
+	// AccessStatus returns whether the user has allowed the app to access
+	// the data
+	AccessStatus() PermissionStatus
+
+	// RequestAccess asks the user to allow access, EG: with the OS's
+	// prompt, returning the status once they have answered
+	RequestAccess() (PermissionStatus, error)
+}
+
+// dataAccess runs the permission flow of a provider. PermissionChangedEvent
+// is emitted when a request changes the status.
+type dataAccess struct {
+	eventManager interfaces.EventManager
+	log          *logger.CustomLogger
+	permission   Permission
+}
+
+// request asks the provider for access if it isn't granted yet
+func (d *dataAccess) request(provider DataAccess) (PermissionStatus, error) {
+	status := provider.AccessStatus()
+	if status == PermissionGranted {
+		return status, nil
+	}
+	d.log.Debugf("Requesting '%s' permission", d.permission)
+	current, err := provider.RequestAccess()
+	if err != nil {
+		return status, err
+	}
+	if current != status {
+		d.log.Debugf("Permission '%s' changed to '%s'", d.permission, current)
+		d.eventManager.Emit(PermissionChangedEvent, string(d.permission), string(current))
+	}
+	return current, nil
+}
+
+// check returns ErrAccessDenied unless the provider has been granted access
+func (d *dataAccess) check(provider DataAccess) error {
+	if provider.AccessStatus() != PermissionGranted {
+		return ErrAccessDenied
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/patch"
+)
+
+// Patches updates the frontend assets of the app without replacing its
+// binary, EG: to ship a UI fix between releases. A patch is a zip archive
+// of index.html, app.js and/or app.css, with a patch.json giving the version
+// of the app it is for, signed with the private key matching
+// AppConfig.AssetPatchKey.
+type Patches struct {
+	patcher *patch.Patcher
+	err     error // Why patches can't be staged, if they can't
+}
+
+// NewPatches creates a new Patches struct. Patches are kept in the user's
+// config directory, under the app's title.
+func NewPatches(config interfaces.AppConfig) *Patches {
+	result := &Patches{}
+	if config == nil || config.GetAssetPatchKey() == "" {
+		result.err = fmt.Errorf("asset patches are off: AppConfig.AssetPatchKey is not set")
+		return result
+	}
+	dir, err := patch.Dir(config.GetTitle())
+	if err == nil {
+		result.patcher, err = patch.New(dir, config.GetAssetPatchKey(), config.GetVersion())
+	}
+	result.err = err
+	return result
+}
+
+// Stage verifies the patch against its ed25519 signature and saves it to be
+// switched to when the app next starts. It replaces any patch already staged.
+func (p *Patches) Stage(archive, signature []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.patcher.Stage(archive, signature)
+}
+
+// Discard removes the current and staged patches, so
+// the app's own assets are used when it next starts
+func (p *Patches) Discard() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.patcher.Discard()
+}
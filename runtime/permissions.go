@@ -0,0 +1,168 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/browser"
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// Permission is an OS permission the app may need
+type Permission string
+
+const (
+	// PermissionScreenRecording allows the app to capture the screen
+	PermissionScreenRecording Permission = "screen-recording"
+	// PermissionNotifications allows the app to show notifications
+	PermissionNotifications Permission = "notifications"
+	// PermissionCamera allows the app to use the camera
+	PermissionCamera Permission = "camera"
+)
+
+// PermissionStatus is the current state of a Permission
+type PermissionStatus string
+
+const (
+	// PermissionGranted means the user has granted the permission
+	PermissionGranted PermissionStatus = "granted"
+	// PermissionDenied means the user has denied the permission
+	PermissionDenied PermissionStatus = "denied"
+	// PermissionNotDetermined means the user has not been asked yet
+	PermissionNotDetermined PermissionStatus = "not-determined"
+	// PermissionUnknown means the status can't be determined on this platform
+	PermissionUnknown PermissionStatus = "unknown"
+)
+
+// PermissionChangedEvent is emitted with the permission and its new
+// status when a permission's status changes after a request
+const PermissionChangedEvent = "wails:permission:changed"
+
+// How often and for how long a permission is watched after a request
+var (
+	permissionPollInterval = time.Second
+	permissionWatchTimeout = 2 * time.Minute
+)
+
+// Permissions exposes OS permission prompts to the runtime
+type Permissions struct {
+	eventManager interfaces.EventManager
+	log          *logger.CustomLogger
+	lock         sync.Mutex
+	watching     map[Permission]bool
+	disabled     error
+}
+
+// NewPermissions creates a new Permissions struct
+func NewPermissions(eventManager interfaces.EventManager) *Permissions {
+	return &Permissions{
+		eventManager: eventManager,
+		log:          logger.NewCustomLogger("Permissions"),
+		watching:     make(map[Permission]bool),
+	}
+}
+
+// Status returns the current status of the given permission
+func (r *Permissions) Status(permission Permission) (PermissionStatus, error) {
+	if r.disabled != nil {
+		return PermissionUnknown, r.disabled
+	}
+	if !isValidPermission(permission) {
+		return PermissionUnknown, fmt.Errorf("unknown permission '%s'", permission)
+	}
+	return permissionStatus(permission), nil
+}
+
+// Request asks the user for the given permission. If the OS is able to
+// prompt for it, the native prompt is shown, otherwise the relevant
+// Settings pane is opened. The permission is then watched and
+// PermissionChangedEvent is emitted when its status changes.
+// The status at the time of the request is returned.
+func (r *Permissions) Request(permission Permission) (PermissionStatus, error) {
+	status, err := r.Status(permission)
+	if err != nil || status == PermissionGranted {
+		return status, err
+	}
+
+	if status == PermissionNotDetermined && requestPermission(permission) {
+		r.log.Debugf("Prompting for '%s' permission", permission)
+	} else {
+		err = r.OpenSettings(permission)
+		if err != nil {
+			return status, err
+		}
+	}
+
+	r.watch(permission, status)
+	return status, nil
+}
+
+// OpenSettings opens the Settings pane where the user
+// may change the given permission
+func (r *Permissions) OpenSettings(permission Permission) error {
+	if r.disabled != nil {
+		return r.disabled
+	}
+	if !isValidPermission(permission) {
+		return fmt.Errorf("unknown permission '%s'", permission)
+	}
+	url := permissionSettingsURL(permission)
+	if url == "" {
+		return fmt.Errorf("no settings available for '%s' permission on this platform", permission)
+	}
+	r.log.Debugf("Opening settings for '%s' permission", permission)
+	return browser.OpenURL(url)
+}
+
+// watch polls the given permission until its status differs from the given
+// status or the watch times out. Only one watch per permission is run.
+func (r *Permissions) watch(permission Permission, status PermissionStatus) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.watching[permission] {
+		return
+	}
+	r.watching[permission] = true
+
+	go func() {
+		defer func() {
+			r.lock.Lock()
+			delete(r.watching, permission)
+			r.lock.Unlock()
+		}()
+
+		deadline := time.Now().Add(permissionWatchTimeout)
+		for time.Now().Before(deadline) {
+			time.Sleep(permissionPollInterval)
+			current := permissionStatus(permission)
+			if current != status {
+				r.log.Debugf("Permission '%s' changed to '%s'", permission, current)
+				r.eventManager.Emit(PermissionChangedEvent, string(permission), string(current))
+				return
+			}
+		}
+	}()
+}
+
+// toPermissionStatus maps the status returned by the native code,
+// 0 for denied, 1 for granted and 2 for not determined
+func toPermissionStatus(status int) PermissionStatus {
+	switch status {
+	case 0:
+		return PermissionDenied
+	case 1:
+		return PermissionGranted
+	default:
+		return PermissionNotDetermined
+	}
+}
+
+func isValidPermission(permission Permission) bool {
+	switch permission {
+	case PermissionScreenRecording, PermissionNotifications, PermissionCamera:
+		return true
+	}
+	return false
+}
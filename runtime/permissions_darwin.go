@@ -0,0 +1,87 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework CoreGraphics -framework AVFoundation
+
+#import <CoreGraphics/CoreGraphics.h>
+#import <AVFoundation/AVFoundation.h>
+
+// Returns 0 if denied, 1 if granted and 2 if not determined
+static int screenRecordingStatus() {
+	if (@available(macOS 10.15, *)) {
+		return CGPreflightScreenCaptureAccess() ? 1 : 0;
+	}
+	return 1;
+}
+
+static void requestScreenRecording() {
+	if (@available(macOS 10.15, *)) {
+		CGRequestScreenCaptureAccess();
+	}
+}
+
+static int cameraStatus() {
+	if (@available(macOS 10.14, *)) {
+		switch ([AVCaptureDevice authorizationStatusForMediaType:AVMediaTypeVideo]) {
+		case AVAuthorizationStatusAuthorized:
+			return 1;
+		case AVAuthorizationStatusNotDetermined:
+			return 2;
+		default:
+			return 0;
+		}
+	}
+	return 1;
+}
+
+static void requestCamera() {
+	if (@available(macOS 10.14, *)) {
+		[AVCaptureDevice requestAccessForMediaType:AVMediaTypeVideo completionHandler:^(BOOL granted) {}];
+	}
+}
+*/
+import "C"
+
+func permissionStatus(permission Permission) PermissionStatus {
+	switch permission {
+	case PermissionScreenRecording:
+		// macOS doesn't distinguish between denied and not yet asked
+		if status := toPermissionStatus(int(C.screenRecordingStatus())); status != PermissionGranted {
+			return PermissionNotDetermined
+		}
+		return PermissionGranted
+	case PermissionCamera:
+		return toPermissionStatus(int(C.cameraStatus()))
+	default:
+		// Notification settings may only be queried asynchronously by bundled apps
+		return PermissionUnknown
+	}
+}
+
+func requestPermission(permission Permission) bool {
+	switch permission {
+	case PermissionScreenRecording:
+		C.requestScreenRecording()
+		return true
+	case PermissionCamera:
+		C.requestCamera()
+		return true
+	}
+	return false
+}
+
+func permissionSettingsURL(permission Permission) string {
+	switch permission {
+	case PermissionScreenRecording:
+		return "x-apple.systempreferences:com.apple.preference.security?Privacy_ScreenCapture"
+	case PermissionCamera:
+		return "x-apple.systempreferences:com.apple.preference.security?Privacy_Camera"
+	case PermissionNotifications:
+		return "x-apple.systempreferences:com.apple.preference.notifications"
+	}
+	return ""
+}
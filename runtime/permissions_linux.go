@@ -0,0 +1,18 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+// Desktop Linux has no permission system for these
+// outside of sandboxes, so they are always granted
+func permissionStatus(permission Permission) PermissionStatus {
+	return PermissionGranted
+}
+
+func requestPermission(permission Permission) bool {
+	return false
+}
+
+func permissionSettingsURL(permission Permission) string {
+	return ""
+}
@@ -0,0 +1,32 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+import (
+	"testing"
+)
+
+// Linux has no permission system, so every permission is granted
+// and there are no prompts or settings to open
+func TestPermissionsUnsupported(t *testing.T) {
+	permissions := NewPermissions(nil)
+	for _, permission := range []Permission{PermissionScreenRecording, PermissionNotifications, PermissionCamera} {
+		t.Run(string(permission), func(t *testing.T) {
+			status, err := permissions.Status(permission)
+			if err != nil || status != PermissionGranted {
+				t.Errorf("Status() = %q, %v, want %q", status, err, PermissionGranted)
+			}
+			status, err = permissions.Request(permission)
+			if err != nil || status != PermissionGranted {
+				t.Errorf("Request() = %q, %v, want %q", status, err, PermissionGranted)
+			}
+			if len(permissions.watching) != 0 {
+				t.Error("Request() watched a granted permission")
+			}
+			if err := permissions.OpenSettings(permission); err == nil {
+				t.Error("OpenSettings() error = nil, want no settings available")
+			}
+		})
+	}
+}
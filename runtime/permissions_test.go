@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"testing"
+)
+
+func TestToPermissionStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   PermissionStatus
+	}{
+		{0, PermissionDenied},
+		{1, PermissionGranted},
+		{2, PermissionNotDetermined},
+		{3, PermissionNotDetermined},
+	}
+	for _, tt := range tests {
+		if got := toPermissionStatus(tt.status); got != tt.want {
+			t.Errorf("toPermissionStatus(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestPermissionsInvalid(t *testing.T) {
+	disabled := NewPermissions(nil)
+	disabled.disabled = disabledError("Permissions", true)
+
+	tests := []struct {
+		name        string
+		permissions *Permissions
+		permission  Permission
+	}{
+		{"unknown permission", NewPermissions(nil), Permission("microphone")},
+		{"disabled", disabled, PermissionCamera},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := tt.permissions.Status(tt.permission)
+			if err == nil || status != PermissionUnknown {
+				t.Errorf("Status() = %q, %v, want %q and an error", status, err, PermissionUnknown)
+			}
+			status, err = tt.permissions.Request(tt.permission)
+			if err == nil || status != PermissionUnknown {
+				t.Errorf("Request() = %q, %v, want %q and an error", status, err, PermissionUnknown)
+			}
+			if err := tt.permissions.OpenSettings(tt.permission); err == nil {
+				t.Error("OpenSettings() error = nil, want an error")
+			}
+		})
+	}
+
+	if _, err := disabled.Status(PermissionCamera); err.(*SubsystemDisabledError).Subsystem != "Permissions" {
+		t.Errorf("Status() error = %v, want the Permissions subsystem to be disabled", err)
+	}
+}
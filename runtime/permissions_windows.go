@@ -0,0 +1,60 @@
+//go:build windows
+// +build windows
+
+package runtime
+
+import "golang.org/x/sys/windows/registry"
+
+const (
+	webcamConsentKey = `Software\Microsoft\Windows\CurrentVersion\CapabilityAccessManager\ConsentStore\webcam`
+	notificationsKey = `Software\Microsoft\Windows\CurrentVersion\PushNotifications`
+)
+
+func permissionStatus(permission Permission) PermissionStatus {
+	switch permission {
+	case PermissionScreenRecording:
+		// Windows has no screen recording permission
+		return PermissionGranted
+	case PermissionCamera:
+		key, err := registry.OpenKey(registry.CURRENT_USER, webcamConsentKey, registry.QUERY_VALUE)
+		if err != nil {
+			return PermissionUnknown
+		}
+		defer key.Close()
+		value, _, err := key.GetStringValue("Value")
+		if err != nil {
+			return PermissionUnknown
+		}
+		if value == "Deny" {
+			return PermissionDenied
+		}
+		return PermissionGranted
+	case PermissionNotifications:
+		key, err := registry.OpenKey(registry.CURRENT_USER, notificationsKey, registry.QUERY_VALUE)
+		if err != nil {
+			return PermissionGranted
+		}
+		defer key.Close()
+		enabled, _, err := key.GetIntegerValue("ToastEnabled")
+		if err == nil && enabled == 0 {
+			return PermissionDenied
+		}
+		return PermissionGranted
+	}
+	return PermissionUnknown
+}
+
+// Windows has no prompts for these permissions
+func requestPermission(permission Permission) bool {
+	return false
+}
+
+func permissionSettingsURL(permission Permission) string {
+	switch permission {
+	case PermissionCamera:
+		return "ms-settings:privacy-webcam"
+	case PermissionNotifications:
+		return "ms-settings:notifications"
+	}
+	return ""
+}
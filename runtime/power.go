@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// PowerChangedEvent is emitted with the new PowerStatus when it changes,
+// while it's being watched
+const PowerChangedEvent = "wails:power:changed"
+
+// ThermalState is how hot the machine is running, as reported by MacOS
+type ThermalState string
+
+const (
+	// ThermalNominal means the machine is running normally
+	ThermalNominal ThermalState = "nominal"
+	// ThermalFair means the machine is slightly hot, EG: its fans are running
+	ThermalFair ThermalState = "fair"
+	// ThermalSerious means the system is slowing the machine to cool it down
+	ThermalSerious ThermalState = "serious"
+	// ThermalCritical means the machine needs to cool down immediately
+	ThermalCritical ThermalState = "critical"
+	// ThermalUnknown means the state isn't available on this platform
+	ThermalUnknown ThermalState = "unknown"
+)
+
+// How often the power status is read while it's being watched
+var powerPollInterval = 10 * time.Second
+
+// PowerStatus is the state of the machine's battery and power
+type PowerStatus struct {
+	// False for machines without a battery, EG: desktops
+	HasBattery bool `json:"hasBattery"`
+
+	// The battery's charge from 0 to 100, or -1 without a battery
+	Battery int `json:"battery"`
+
+	Charging bool `json:"charging"`
+
+	// True when the machine runs on mains power rather than its battery
+	PluggedIn bool `json:"pluggedIn"`
+
+	// True when the user has turned on the OS's low power mode or battery
+	// saver
+	LowPowerMode bool `json:"lowPowerMode"`
+
+	Thermal ThermalState `json:"thermal"`
+}
+
+// Power exposes the machine's battery and power state, so apps can throttle
+// background work when the battery is low or the machine is hot
+type Power struct {
+	eventManager interfaces.EventManager
+	log          *logger.CustomLogger
+	lock         sync.Mutex
+	stop         chan struct{}
+}
+
+// NewPower creates a new Power struct
+func NewPower(eventManager interfaces.EventManager) *Power {
+	return &Power{
+		eventManager: eventManager,
+		log:          logger.NewCustomLogger("Power"),
+	}
+}
+
+// Status returns the machine's current battery and power state
+func (r *Power) Status() (*PowerStatus, error) {
+	status := &PowerStatus{
+		Battery: -1,
+		Thermal: ThermalUnknown,
+	}
+	err := powerStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Watch emits PowerChangedEvent with the PowerStatus whenever it changes,
+// until StopWatching is called
+func (r *Power) Watch() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	r.stop = stop
+	go func() {
+		last, _ := r.Status()
+		ticker := time.NewTicker(powerPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				status, err := r.Status()
+				if err != nil {
+					r.log.Errorf("Unable to read power status: %s", err.Error())
+					continue
+				}
+				if last == nil || *status != *last {
+					r.log.Debugf("Power status changed: %+v", *status)
+					r.eventManager.Emit(PowerChangedEvent, status)
+				}
+				last = status
+			}
+		}
+	}()
+}
+
+// StopWatching stops the events started with Watch
+func (r *Power) StopWatching() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
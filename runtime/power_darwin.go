@@ -0,0 +1,79 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation -framework IOKit
+
+#import <Foundation/Foundation.h>
+#import <IOKit/ps/IOPowerSources.h>
+#import <IOKit/ps/IOPSKeys.h>
+
+// Reads the internal battery. Returns 0 if the machine has none.
+static int batteryStatus(int *capacity, int *charging, int *pluggedIn) {
+	int found = 0;
+	CFTypeRef info = IOPSCopyPowerSourcesInfo();
+	if (info == NULL) {
+		return 0;
+	}
+	CFStringRef source = IOPSGetProvidingPowerSourceType(info);
+	*pluggedIn = source != NULL && CFStringCompare(source, CFSTR(kIOPSACPowerValue), 0) == kCFCompareEqualTo;
+	CFArrayRef sources = IOPSCopyPowerSourcesList(info);
+	if (sources != NULL) {
+		for (CFIndex i = 0; i < CFArrayGetCount(sources) && !found; i++) {
+			NSDictionary *description = (NSDictionary *)IOPSGetPowerSourceDescription(info, CFArrayGetValueAtIndex(sources, i));
+			if (![[description objectForKey:@kIOPSTypeKey] isEqualToString:@kIOPSInternalBatteryType]) {
+				continue;
+			}
+			int current = [[description objectForKey:@kIOPSCurrentCapacityKey] intValue];
+			int max = [[description objectForKey:@kIOPSMaxCapacityKey] intValue];
+			*capacity = max > 0 ? current * 100 / max : current;
+			*charging = [[description objectForKey:@kIOPSIsChargingKey] boolValue];
+			found = 1;
+		}
+		CFRelease(sources);
+	}
+	CFRelease(info);
+	return found;
+}
+
+// Returns the NSProcessInfoThermalState, or -1 if it isn't available
+static int thermalState() {
+	NSProcessInfo *info = [NSProcessInfo processInfo];
+	if (![info respondsToSelector:@selector(thermalState)]) {
+		return -1;
+	}
+	return (int)[info thermalState];
+}
+
+static int lowPowerMode() {
+	NSProcessInfo *info = [NSProcessInfo processInfo];
+	if (![info respondsToSelector:@selector(isLowPowerModeEnabled)]) {
+		return 0;
+	}
+	return [info isLowPowerModeEnabled];
+}
+*/
+import "C"
+
+// The thermal states in the order of NSProcessInfoThermalState
+var thermalStates = []ThermalState{ThermalNominal, ThermalFair, ThermalSerious, ThermalCritical}
+
+func powerStatus(status *PowerStatus) error {
+	var capacity, charging, pluggedIn C.int
+	if C.batteryStatus(&capacity, &charging, &pluggedIn) == 1 {
+		status.HasBattery = true
+		status.Battery = int(capacity)
+		status.Charging = charging == 1
+		status.PluggedIn = pluggedIn == 1
+	} else {
+		status.PluggedIn = true
+	}
+	if state := int(C.thermalState()); state >= 0 && state < len(thermalStates) {
+		status.Thermal = thermalStates[state]
+	}
+	status.LowPowerMode = C.lowPowerMode() == 1
+	return nil
+}
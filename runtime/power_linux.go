@@ -0,0 +1,57 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readPowerFile returns the trimmed contents of a sysfs file, or "" if it
+// can't be read
+func readPowerFile(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// The batteries and chargers are read from sysfs. Laptops with more than one
+// battery report the first. Thermal pressure isn't available.
+func powerStatus(status *PowerStatus) error {
+	supplies, err := filepath.Glob("/sys/class/power_supply/*")
+	if err != nil {
+		return err
+	}
+	for _, supply := range supplies {
+		switch readPowerFile(filepath.Join(supply, "type")) {
+		case "Mains":
+			if readPowerFile(filepath.Join(supply, "online")) == "1" {
+				status.PluggedIn = true
+			}
+		case "Battery":
+			// Peripherals, EG: mice, report their batteries too
+			if readPowerFile(filepath.Join(supply, "scope")) == "Device" || status.HasBattery {
+				continue
+			}
+			capacity, err := strconv.Atoi(readPowerFile(filepath.Join(supply, "capacity")))
+			if err != nil {
+				continue
+			}
+			status.HasBattery = true
+			status.Battery = capacity
+			status.Charging = readPowerFile(filepath.Join(supply, "status")) == "Charging"
+		}
+	}
+	if !status.HasBattery {
+		status.PluggedIn = true
+	}
+
+	// Set by power-profiles-daemon on machines that support it
+	status.LowPowerMode = readPowerFile("/sys/firmware/acpi/platform_profile") == "low-power"
+	return nil
+}
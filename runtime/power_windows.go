@@ -0,0 +1,49 @@
+//go:build windows
+// +build windows
+
+package runtime
+
+import (
+	"unsafe"
+)
+
+var procGetSystemPowerStatus = kernel32.NewProc("GetSystemPowerStatus")
+
+// systemPowerStatus is SYSTEM_POWER_STATUS
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+// Flags of systemPowerStatus
+const (
+	batteryFlagCharging  = 8
+	batteryFlagNoBattery = 128
+	batteryFlagUnknown   = 255
+	batteryLifeUnknown   = 255
+	systemStatusSaver    = 1
+)
+
+// Thermal pressure isn't available on Windows
+func powerStatus(status *PowerStatus) error {
+	var power systemPowerStatus
+	result, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&power)))
+	if result == 0 {
+		return err
+	}
+	status.PluggedIn = power.ACLineStatus == 1
+	if power.BatteryFlag != batteryFlagUnknown && power.BatteryFlag&batteryFlagNoBattery == 0 {
+		status.HasBattery = true
+		status.Charging = power.BatteryFlag&batteryFlagCharging != 0
+		if power.BatteryLifePercent != batteryLifeUnknown {
+			status.Battery = int(power.BatteryLifePercent)
+		}
+	}
+	// Battery saver is only reported by Windows 10 and later
+	status.LowPowerMode = power.SystemStatusFlag&systemStatusSaver != 0
+	return nil
+}
@@ -0,0 +1,168 @@
+package runtime
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// PurchaseUpdatedEvent is emitted with a *Transaction whenever
+// the state of a purchase changes
+const PurchaseUpdatedEvent = "wails:purchase:updated"
+
+// ErrBillingUnavailable is returned when no BillingProvider is set
+var ErrBillingUnavailable = errors.New("in-app purchases are not available")
+
+// Product is an item sold through the store
+type Product struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+
+	// The price formatted for the user's store region, EG: "£4.99"
+	Price string `json:"price"`
+}
+
+// TransactionState is the state of a purchase
+type TransactionState string
+
+const (
+	// TransactionPurchased means the product was paid for and should be unlocked
+	TransactionPurchased TransactionState = "purchased"
+
+	// TransactionPending means the purchase is waiting on approval, EG: by a parent
+	TransactionPending TransactionState = "pending"
+
+	// TransactionRestored means a previous purchase was restored
+	TransactionRestored TransactionState = "restored"
+
+	// TransactionCancelled means the user cancelled the purchase
+	TransactionCancelled TransactionState = "cancelled"
+
+	// TransactionFailed means the purchase failed. Error holds the reason.
+	TransactionFailed TransactionState = "failed"
+)
+
+// Transaction is a purchase of a product
+type Transaction struct {
+	ProductID     string           `json:"productId"`
+	TransactionID string           `json:"transactionId"`
+	State         TransactionState `json:"state"`
+
+	// The store's proof of purchase, for verification by a server
+	Receipt string `json:"receipt,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// BillingProvider connects the runtime to a store's billing API,
+// EG: StoreKit for the Mac App Store or Windows.Services.Store
+type BillingProvider interface {
+	// Start is called once with the function to report transactions to.
+	// Transactions completed while the app wasn't running should be reported.
+	Start(update func(*Transaction)) error
+
+	// CanMakePayments returns false if the user may not make purchases
+	CanMakePayments() bool
+
+	// Products returns the details of the given products
+	Products(ids []string) ([]*Product, error)
+
+	// Purchase starts a purchase of the given product. The result
+	// is reported as a Transaction.
+	Purchase(productID string) error
+
+	// Restore reports the user's previous purchases as restored Transactions
+	Restore() error
+}
+
+// noBilling is used when no BillingProvider is set
+type noBilling struct{}
+
+func (noBilling) Start(func(*Transaction)) error        { return nil }
+func (noBilling) CanMakePayments() bool                 { return false }
+func (noBilling) Products([]string) ([]*Product, error) { return nil, ErrBillingUnavailable }
+func (noBilling) Purchase(string) error                 { return ErrBillingUnavailable }
+func (noBilling) Restore() error                        { return ErrBillingUnavailable }
+
+// disabledBilling is used when the Purchases subsystem is disabled
+type disabledBilling struct {
+	err error
+}
+
+func (disabledBilling) Start(func(*Transaction)) error          { return nil }
+func (disabledBilling) CanMakePayments() bool                   { return false }
+func (b disabledBilling) Products([]string) ([]*Product, error) { return nil, b.err }
+func (b disabledBilling) Purchase(string) error                 { return b.err }
+func (b disabledBilling) Restore() error                        { return b.err }
+
+// Purchases exposes in-app purchases through the BillingProvider set for
+// the app. Transactions are emitted as PurchaseUpdatedEvent.
+type Purchases struct {
+	eventManager interfaces.EventManager
+	log          *logger.CustomLogger
+	lock         sync.Mutex
+	provider     BillingProvider
+	disabled     error
+}
+
+// NewPurchases creates a new Purchases struct
+func NewPurchases(eventManager interfaces.EventManager) *Purchases {
+	return &Purchases{
+		eventManager: eventManager,
+		log:          logger.NewCustomLogger("Purchases"),
+		provider:     noBilling{},
+	}
+}
+
+// SetProvider sets the BillingProvider and starts it
+func (r *Purchases) SetProvider(provider BillingProvider) error {
+	if r.disabled != nil {
+		return r.disabled
+	}
+	if provider == nil {
+		provider = noBilling{}
+	}
+	r.lock.Lock()
+	r.provider = provider
+	r.lock.Unlock()
+	return provider.Start(r.update)
+}
+
+// update emits the given transaction
+func (r *Purchases) update(transaction *Transaction) {
+	r.log.Debugf("Purchase of '%s' is %s", transaction.ProductID, transaction.State)
+	r.eventManager.Emit(PurchaseUpdatedEvent, transaction)
+}
+
+func (r *Purchases) getProvider() BillingProvider {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.disabled != nil {
+		return disabledBilling{r.disabled}
+	}
+	return r.provider
+}
+
+// CanMakePayments returns true if purchases are available to the user
+func (r *Purchases) CanMakePayments() bool {
+	return r.getProvider().CanMakePayments()
+}
+
+// Products returns the details of the given products
+func (r *Purchases) Products(ids ...string) ([]*Product, error) {
+	return r.getProvider().Products(ids)
+}
+
+// Purchase starts a purchase of the given product.
+// The result is emitted as PurchaseUpdatedEvent.
+func (r *Purchases) Purchase(productID string) error {
+	return r.getProvider().Purchase(productID)
+}
+
+// Restore emits the user's previous purchases as PurchaseUpdatedEvent
+func (r *Purchases) Restore() error {
+	return r.getProvider().Restore()
+}
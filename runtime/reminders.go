@@ -0,0 +1,136 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// Reminder is an item in one of the user's reminder lists
+type Reminder struct {
+	// Set by the provider. Reminders without one are added by SaveReminder.
+	ID string `json:"id"`
+
+	// The name of the list. Reminders are added to the user's default list
+	// if it's empty.
+	List string `json:"list"`
+
+	Title     string     `json:"title"`
+	Notes     string     `json:"notes,omitempty"`
+	Due       *time.Time `json:"due,omitempty"`
+	Completed bool       `json:"completed"`
+}
+
+// RemindersProvider connects the runtime to the user's reminders, EG:
+// EventKit on MacOS
+type RemindersProvider interface {
+	DataAccess
+
+	// Reminders returns the user's reminders, with those that are
+	// completed if includeCompleted is true
+	Reminders(includeCompleted bool) ([]*Reminder, error)
+
+	// SaveReminder adds the reminder, or updates it if it has an ID,
+	// returning its ID
+	SaveReminder(reminder *Reminder) (string, error)
+
+	// DeleteReminder removes the reminder with the given ID
+	DeleteReminder(id string) error
+}
+
+// noReminders is used when no RemindersProvider is set
+type noReminders struct{}
+
+func (noReminders) AccessStatus() PermissionStatus { return PermissionUnknown }
+func (noReminders) RequestAccess() (PermissionStatus, error) {
+	return PermissionUnknown, ErrDataUnavailable
+}
+func (noReminders) Reminders(bool) ([]*Reminder, error)    { return nil, ErrDataUnavailable }
+func (noReminders) SaveReminder(*Reminder) (string, error) { return "", ErrDataUnavailable }
+func (noReminders) DeleteReminder(string) error            { return ErrDataUnavailable }
+
+// Reminders exposes the user's reminders through the RemindersProvider set
+// for the app. The user must allow access with Request first.
+type Reminders struct {
+	access   dataAccess
+	lock     sync.Mutex
+	provider RemindersProvider
+}
+
+// NewReminders creates a new Reminders struct
+func NewReminders(eventManager interfaces.EventManager) *Reminders {
+	return &Reminders{
+		access: dataAccess{
+			eventManager: eventManager,
+			log:          logger.NewCustomLogger("Reminders"),
+			permission:   PermissionReminders,
+		},
+		provider: noReminders{},
+	}
+}
+
+// SetProvider sets the RemindersProvider
+func (r *Reminders) SetProvider(provider RemindersProvider) {
+	if provider == nil {
+		provider = noReminders{}
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.provider = provider
+}
+
+func (r *Reminders) getProvider() RemindersProvider {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.provider
+}
+
+// Status returns whether the user has allowed the app to access their
+// reminders
+func (r *Reminders) Status() PermissionStatus {
+	return r.getProvider().AccessStatus()
+}
+
+// Request asks the user to allow the app to access their reminders,
+// returning the status once they have answered. PermissionChangedEvent is
+// emitted with PermissionReminders if the status changes.
+func (r *Reminders) Request() (PermissionStatus, error) {
+	return r.access.request(r.getProvider())
+}
+
+// List returns the user's reminders, with those that are completed if
+// includeCompleted is true
+func (r *Reminders) List(includeCompleted bool) ([]*Reminder, error) {
+	provider := r.getProvider()
+	err := r.access.check(provider)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Reminders(includeCompleted)
+}
+
+// Save adds the reminder, or updates it if it has an ID, returning its ID
+func (r *Reminders) Save(reminder *Reminder) (string, error) {
+	provider := r.getProvider()
+	err := r.access.check(provider)
+	if err != nil {
+		return "", err
+	}
+	if reminder == nil {
+		return "", fmt.Errorf("no reminder given")
+	}
+	return provider.SaveReminder(reminder)
+}
+
+// Delete removes the reminder with the given ID
+func (r *Reminders) Delete(id string) error {
+	provider := r.getProvider()
+	err := r.access.check(provider)
+	if err != nil {
+		return err
+	}
+	return provider.DeleteReminder(id)
+}
@@ -1,29 +1,95 @@
 package runtime
 
-import "github.com/wailsapp/wails/lib/interfaces"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/pkg/cli"
+)
 
 // Runtime is the Wails Runtime Interface, given to a user who has defined the WailsInit method
 type Runtime struct {
-	Events     *Events
-	Log        *Log
-	Dialog     *Dialog
-	Window     *Window
-	Browser    *Browser
-	FileSystem *FileSystem
-	Store      *StoreProvider
+	Events       *Events
+	Log          *Log
+	Dialog       *Dialog
+	Window       *Window
+	Browser      *Browser
+	FileSystem   *FileSystem
+	Store        *StoreProvider
+	Support      *Support
+	Permissions  *Permissions
+	Bookmarks    *Bookmarks
+	Fonts        *Fonts
+	System       *System
+	Purchases    *Purchases
+	Patches      *Patches
+	Tray         *Tray
+	MenuBar      *MenuBar
+	ContextMenu  *ContextMenu
+	Notification *Notification
+	Share        *Share
+	Calendar     *Calendar
+	Reminders    *Reminders
+	Power        *Power
+	Feedback     *Feedback
+	Touch        *Touch
+	Clipboard    *Clipboard
+	Gamepads     *Gamepads
+
+	// The flags the app was launched with
+	Flags *cli.Flags
 }
 
 // NewRuntime creates a new Runtime struct
-func NewRuntime(eventManager interfaces.EventManager, renderer interfaces.Renderer) *Runtime {
+func NewRuntime(eventManager interfaces.EventManager, renderer interfaces.Renderer, config interfaces.AppConfig) *Runtime {
+	bookmarks := NewBookmarks(config)
 	result := &Runtime{
-		Events:     NewEvents(eventManager),
-		Log:        NewLog(),
-		Dialog:     NewDialog(renderer),
-		Window:     NewWindow(renderer),
-		Browser:    NewBrowser(),
-		FileSystem: NewFileSystem(),
+		Events:       NewEvents(eventManager),
+		Log:          NewLog(),
+		Dialog:       NewDialog(renderer, bookmarks),
+		Window:       NewWindow(renderer, config),
+		Browser:      NewBrowser(),
+		FileSystem:   NewFileSystem(),
+		Support:      NewSupport(config),
+		Permissions:  NewPermissions(eventManager),
+		Bookmarks:    bookmarks,
+		Fonts:        NewFonts(),
+		System:       NewSystem(eventManager, config),
+		Purchases:    NewPurchases(eventManager),
+		Patches:      NewPatches(config),
+		Tray:         NewTray(eventManager, renderer),
+		MenuBar:      NewMenuBar(eventManager, renderer),
+		ContextMenu:  NewContextMenu(eventManager, renderer),
+		Notification: NewNotification(eventManager, renderer),
+		Share:        NewShare(eventManager, config),
+		Calendar:     NewCalendar(eventManager),
+		Reminders:    NewReminders(eventManager),
+		Power:        NewPower(eventManager),
+		Feedback:     NewFeedback(),
+		Touch:        NewTouch(eventManager),
+		Clipboard:    NewClipboard(),
+		Gamepads:     NewGamepads(eventManager),
 	}
 	// We need a reference to itself
 	result.Store = NewStoreProvider(result)
+
+	// Persisted stores are saved under the app's title in the user's config
+	// directory
+	if configDir, err := os.UserConfigDir(); err == nil && config != nil {
+		name := strings.ToLower(strings.Replace(config.GetTitle(), " ", "-", -1))
+		result.Store.dir = filepath.Join(configDir, name)
+	}
+
+	// Disable the subsystems the app doesn't use
+	if config != nil {
+		subsystems := config.GetSubsystems()
+		result.Permissions.disabled = disabledError("Permissions", subsystems.DisablePermissions)
+		result.Bookmarks.disabled = disabledError("Bookmarks", subsystems.DisableBookmarks)
+		result.Fonts.disabled = disabledError("Fonts", subsystems.DisableFonts)
+		result.Purchases.disabled = disabledError("Purchases", subsystems.DisablePurchases)
+		result.System.statsDisabled = disabledError("Stats", subsystems.DisableStats)
+	}
 	return result
 }
@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+)
+
+// ShareEvent is emitted with the SharedContent when another app shares
+// something with this one
+const ShareEvent = "wails:share"
+
+// SharedContent is the text or files another app shared with this one
+type SharedContent struct {
+	Text  string   `json:"text"`
+	Files []string `json:"files"`
+}
+
+// Share receives the text and files other apps share with this one, once it
+// is registered as a share target with Register. On Linux and Windows, the
+// files are given to a new instance of the app, launched with --share.
+//
+// Shares that arrive before anything handles them, EG: the share that
+// launched the app, are queued until OnShare is called or the frontend asks
+// for them. Later shares are emitted as ShareEvent.
+type Share struct {
+	name    string
+	lock    sync.Mutex
+	claimed bool
+	pending []*SharedContent
+	onShare func(*SharedContent)
+}
+
+// NewShare creates a new Share struct
+func NewShare(eventManager interfaces.EventManager, config interfaces.AppConfig) *Share {
+	result := &Share{
+		name: "Wails",
+	}
+	if config != nil && config.GetTitle() != "" {
+		result.name = config.GetTitle()
+	}
+	eventManager.On(ShareEvent, result.received)
+	return result
+}
+
+// Register adds the app to the places other apps share from: a "Send to"
+// item in the context menu of files in Explorer on Windows, and an
+// application for any file on Linux. On MacOS, the services the app
+// declares in its info.plist with "share" as their NSMessage are refreshed.
+func (r *Share) Register() error {
+	return registerShareTarget(r.name)
+}
+
+// Unregister removes what Register added. On MacOS, the services must be
+// removed from the app's info.plist instead.
+func (r *Share) Unregister() error {
+	return unregisterShareTarget(r.name)
+}
+
+// OnShare sets the function called with what other apps share, first with
+// the shares queued until now
+func (r *Share) OnShare(callback func(*SharedContent)) {
+	r.lock.Lock()
+	r.onShare = callback
+	pending := r.take()
+	r.lock.Unlock()
+
+	for _, content := range pending {
+		callback(content)
+	}
+}
+
+// Pending returns the shares queued until now and stops queueing them, so
+// the frontend can handle the shares it missed before it loaded
+func (r *Share) Pending() []*SharedContent {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.take()
+}
+
+// take returns the queued shares and stops queueing them. The lock must be
+// held.
+func (r *Share) take() []*SharedContent {
+	pending := r.pending
+	if pending == nil {
+		pending = []*SharedContent{}
+	}
+	r.pending = nil
+	r.claimed = true
+	return pending
+}
+
+// received queues the share until it's claimed, or passes it to the handler
+func (r *Share) received(data ...interface{}) {
+	if len(data) == 0 {
+		return
+	}
+	content, ok := data[0].(*SharedContent)
+	if !ok {
+		return
+	}
+
+	r.lock.Lock()
+	if !r.claimed {
+		r.pending = append(r.pending, content)
+	}
+	callback := r.onShare
+	r.lock.Unlock()
+
+	if callback != nil {
+		callback(content)
+	}
+}
+
+// shareTargetName is the app's name without the characters that can't be
+// in a file or registry key name
+func shareTargetName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`\/:*?"<>|`, r) {
+			return -1
+		}
+		return r
+	}, name)
+}
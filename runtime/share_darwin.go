@@ -0,0 +1,37 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+
+#import <AppKit/AppKit.h>
+
+// Returns 0 if the app declares no services
+static int updateServices() {
+	@autoreleasepool {
+		if ([[NSBundle mainBundle] objectForInfoDictionaryKey:@"NSServices"] == nil) {
+			return 0;
+		}
+		NSUpdateDynamicServices();
+		return 1;
+	}
+}
+*/
+import "C"
+
+import "fmt"
+
+// Services are declared in the app's info.plist, so they are only refreshed
+func registerShareTarget(name string) error {
+	if C.updateServices() == 0 {
+		return fmt.Errorf("the app's info.plist declares no NSServices")
+	}
+	return nil
+}
+
+func unregisterShareTarget(name string) error {
+	return fmt.Errorf("the services in the app's info.plist can't be unregistered")
+}
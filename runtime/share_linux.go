@@ -0,0 +1,85 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The app is offered for any file by a desktop entry that isn't shown in
+// the menus
+const shareDesktopEntry = `[Desktop Entry]
+Type=Application
+Name=%s
+Exec=%s --share %%F
+MimeType=application/octet-stream;text/plain;inode/directory;
+NoDisplay=true
+`
+
+// shareDesktopFile is where the desktop entry is written, in the user's
+// applications directory
+func shareDesktopFile(name string) (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	filename := strings.ToLower(strings.Replace(shareTargetName(name), " ", "-", -1))
+	return filepath.Join(dir, "applications", filename+"-share.desktop"), nil
+}
+
+// desktopQuote quotes an argument of the Exec key
+func desktopQuote(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`).Replace(arg) + `"`
+}
+
+func registerShareTarget(name string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	filename, err := shareDesktopFile(name)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return err
+	}
+	name = strings.NewReplacer("\r", " ", "\n", " ").Replace(name)
+	entry := fmt.Sprintf(shareDesktopEntry, name, desktopQuote(exe))
+	err = ioutil.WriteFile(filename, []byte(entry), 0644)
+	if err != nil {
+		return fmt.Errorf("unable to register the share target: %s", err)
+	}
+	updateDesktopDatabase(filepath.Dir(filename))
+	return nil
+}
+
+func unregisterShareTarget(name string) error {
+	filename, err := shareDesktopFile(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	updateDesktopDatabase(filepath.Dir(filename))
+	return nil
+}
+
+// updateDesktopDatabase updates the cache of the applications for each file
+// type, where the desktop has one
+func updateDesktopDatabase(dir string) {
+	exec.Command("update-desktop-database", dir).Run()
+}
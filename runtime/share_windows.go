@@ -0,0 +1,55 @@
+//go:build windows
+// +build windows
+
+package runtime
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// The files are shared by a verb for every file type, which Explorer shows
+// in the files' context menu
+const shareVerbKey = `Software\Classes\*\shell\`
+
+func registerShareTarget(name string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	path := shareVerbKey + shareTargetName(name) + ".Share"
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, path, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("unable to register the share target: %s", err)
+	}
+	defer key.Close()
+	err = key.SetStringValue("", "Send to "+name)
+	if err != nil {
+		return err
+	}
+	err = key.SetStringValue("Icon", exe)
+	if err != nil {
+		return err
+	}
+	command, _, err := registry.CreateKey(key, "command", registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer command.Close()
+	return command.SetStringValue("", fmt.Sprintf(`"%s" --share "%%1"`, exe))
+}
+
+func unregisterShareTarget(name string) error {
+	path := shareVerbKey + shareTargetName(name) + ".Share"
+	err := registry.DeleteKey(registry.CURRENT_USER, path+`\command`)
+	if err != nil && err != registry.ErrNotExist {
+		return err
+	}
+	err = registry.DeleteKey(registry.CURRENT_USER, path)
+	if err != nil && err != registry.ErrNotExist {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"runtime"
+	"time"
+)
+
+// StatsEvent is emitted with the app's Stats while they are being watched
+const StatsEvent = "wails:system:stats"
+
+// Stats holds the resource usage of the app
+type Stats struct {
+	// The CPU usage since the previous sample, as a percentage of one core
+	CPU float64 `json:"cpu"`
+
+	// The resident memory of the app's process in bytes
+	Memory uint64 `json:"memory"`
+
+	Goroutines int `json:"goroutines"`
+
+	// The resident memory of the webview's processes in bytes. This is only
+	// available on Linux, as the webview runs in the app's process on Windows
+	// and MacOS so is included in Memory.
+	WebViewMemory uint64 `json:"webviewMemory"`
+}
+
+// Stats returns the resource usage of the app. The CPU usage is measured
+// since the previous call, or since the app started for the first call.
+func (r *System) Stats() (*Stats, error) {
+	if r.statsDisabled != nil {
+		return nil, r.statsDisabled
+	}
+	cpuTime, memory, err := processUsage()
+	if err != nil {
+		return nil, err
+	}
+
+	r.statsLock.Lock()
+	now := time.Now()
+	elapsed := now.Sub(r.lastSample)
+	var cpu float64
+	if elapsed > 0 {
+		cpu = 100 * float64(cpuTime-r.lastCPUTime) / float64(elapsed)
+	}
+	r.lastSample, r.lastCPUTime = now, cpuTime
+	r.statsLock.Unlock()
+
+	return &Stats{
+		CPU:           cpu,
+		Memory:        memory,
+		Goroutines:    runtime.NumGoroutine(),
+		WebViewMemory: webviewMemory(),
+	}, nil
+}
+
+// WatchStats emits StatsEvent with the app's Stats at the given interval until
+// StopWatchingStats is called. Calling it again changes the interval.
+func (r *System) WatchStats(interval time.Duration) {
+	if r.statsDisabled != nil {
+		r.log.Warn(r.statsDisabled.Error())
+		return
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	r.StopWatchingStats()
+
+	r.statsLock.Lock()
+	defer r.statsLock.Unlock()
+	stop := make(chan struct{})
+	r.stopStats = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				stats, err := r.Stats()
+				if err != nil {
+					r.log.Errorf("Unable to read stats: %s", err.Error())
+					continue
+				}
+				r.eventManager.Emit(StatsEvent, stats)
+			}
+		}
+	}()
+}
+
+// StopWatchingStats stops the events started with WatchStats
+func (r *System) StopWatchingStats() {
+	r.statsLock.Lock()
+	defer r.statsLock.Unlock()
+	if r.stopStats != nil {
+		close(r.stopStats)
+		r.stopStats = nil
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sync"
 )
@@ -30,6 +32,9 @@ type Options struct {
 // StoreProvider is a struct that creates Stores
 type StoreProvider struct {
 	runtime *Runtime
+
+	// The directory persisted stores are saved in by default
+	dir string
 }
 
 // NewStoreProvider creates new stores using the provided Runtime reference.
@@ -47,8 +52,12 @@ type Store struct {
 	eventPrefix         string
 	callbacks           []reflect.Value
 	runtime             *Runtime
+	provider            *StoreProvider
 	notifySynchronously bool
 
+	// The file the data is saved in, see Persist
+	filename string
+
 	// Lock
 	mux sync.Mutex
 
@@ -64,6 +73,7 @@ func (p *StoreProvider) New(name string, defaultValue interface{}) *Store {
 	result := Store{
 		name:     name,
 		runtime:  p.runtime,
+		provider: p,
 		data:     reflect.ValueOf(defaultValue),
 		dataType: dataType,
 	}
@@ -113,9 +123,80 @@ func (s *Store) processUpdatedData(data string) error {
 	// Unlock mutex
 	s.mux.Unlock()
 
+	return s.save()
+}
+
+// Persist saves the store's data in the given file whenever it changes,
+// first loading the data that was saved. Relative filenames are in the app's
+// directory in the user's config directory. The file is never left partly
+// written, even by a power loss, and if it is corrupt anyway the data is
+// loaded from the latest good backup, or reset to the default value, and
+// StoreRecoveredEvent is emitted.
+func (s *Store) Persist(filename string) error {
+	if !filepath.IsAbs(filename) {
+		if s.provider.dir == "" {
+			return fmt.Errorf("unable to find the user's config directory for '%s'", filename)
+		}
+		filename = filepath.Join(s.provider.dir, filename)
+	}
+
+	data, source, err := loadStoreFile(filename)
+	var recovery *StoreRecovery
+	if corrupt, ok := err.(*storeCorruptError); ok {
+		recovery = &StoreRecovery{
+			Name:     s.name,
+			Filename: filename,
+			Backup:   source,
+			Error:    corrupt.Error(),
+		}
+		// Kept for inspection, rather than becoming a backup
+		os.Rename(filename, filename+".corrupt")
+	} else if err != nil {
+		return err
+	}
+	if data != nil {
+		err = s.processUpdatedData(string(data))
+		if err != nil {
+			return err
+		}
+	}
+
+	s.mux.Lock()
+	s.filename = filename
+	s.mux.Unlock()
+
+	// The data is saved unless it was loaded from the file
+	if source != filename {
+		err = s.save()
+		if err != nil {
+			return err
+		}
+	}
+
+	if data != nil {
+		s.runtime.Events.Emit("wails:sync:store:updatedbybackend:"+s.name, string(data))
+		s.notify()
+	}
+	if recovery != nil {
+		s.runtime.Events.Emit(StoreRecoveredEvent, recovery)
+	}
 	return nil
 }
 
+// save writes the data to the store's file, if it is persisted
+func (s *Store) save() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.filename == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.data.Interface())
+	if err != nil {
+		return err
+	}
+	return writeStoreFile(s.filename, data)
+}
+
 // Setup listener for front end changes
 func (s *Store) setupListener() {
 
@@ -135,6 +216,20 @@ func (s *Store) setupListener() {
 		// Notify listeners
 		s.notify()
 	})
+
+	// Resend the data to a page reloaded after the webview crashed
+	s.runtime.Events.On("wails:webview-restored", func(...interface{}) {
+		s.mux.Lock()
+		data, err := json.Marshal(s.data.Interface())
+		s.mux.Unlock()
+		if err != nil {
+			if s.errorHandler != nil {
+				s.errorHandler(err)
+			}
+			return
+		}
+		s.runtime.Events.Emit("wails:sync:store:updatedbybackend:"+s.name, string(data))
+	})
 }
 
 // notify the listeners of the current data state
@@ -169,6 +264,10 @@ func (s *Store) Set(data interface{}) error {
 	s.mux.Lock()
 	s.data = reflect.ValueOf(data)
 	s.mux.Unlock()
+	err := s.save()
+	if err != nil && s.errorHandler != nil {
+		s.errorHandler(err)
+	}
 
 	// Stringify data
 	newdata, err := json.Marshal(data)
@@ -0,0 +1,187 @@
+package runtime
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// StoreRecoveredEvent is emitted with a StoreRecovery when a persisted
+// store's file was corrupt, EG: after a power loss, and its data was loaded
+// from a backup or reset to its default value
+const StoreRecoveredEvent = "store:recovered"
+
+// StoreRecovery describes how a store's data was recovered
+type StoreRecovery struct {
+	// The store's name and file
+	Name     string `json:"name"`
+	Filename string `json:"filename"`
+
+	// The backup the data was loaded from, or empty if there was no good
+	// backup and the store has its default value
+	Backup string `json:"backup"`
+
+	// Why the store's file couldn't be loaded
+	Error string `json:"error"`
+}
+
+// The number of previous versions of a store's file that are kept, as
+// <file>.1 to <file>.3 with <file>.1 the latest
+const storeBackups = 3
+
+// storeFile is how a store's data is saved, with the checksum of its data
+// to find files that were only partly written
+type storeFile struct {
+	Checksum string          `json:"checksum"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// storeCorruptError is returned for store files that can't be loaded as
+// they were only partly written
+type storeCorruptError struct {
+	filename string
+	reason   string
+}
+
+func (e *storeCorruptError) Error() string {
+	return fmt.Sprintf("'%s' is corrupt: %s", e.filename, e.reason)
+}
+
+func storeChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// readStoreFile returns the data saved in the given file. Files without a
+// checksum are plain JSON, EG: settings an app saved itself before using a
+// store, and their JSON is the data.
+func readStoreFile(filename string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var file storeFile
+	if json.Unmarshal(contents, &file) == nil && file.Checksum != "" {
+		var data bytes.Buffer
+		err = json.Compact(&data, file.Data)
+		if err != nil || storeChecksum(data.Bytes()) != file.Checksum {
+			return nil, &storeCorruptError{filename, "its checksum doesn't match"}
+		}
+		return data.Bytes(), nil
+	}
+	if !json.Valid(contents) {
+		return nil, &storeCorruptError{filename, "it isn't valid JSON"}
+	}
+	return contents, nil
+}
+
+// loadStoreFile returns the data saved in the given file, along with the
+// file it was read from. A temp file left by a write that wasn't finished is
+// used if it is good, as it has the latest data, and deleted otherwise. If
+// the file is missing or corrupt, the data is read from the latest good
+// backup, with a storeCorruptError if the file was corrupt. It returns nil
+// if nothing has been saved.
+func loadStoreFile(filename string) ([]byte, string, error) {
+	temp := filename + ".tmp"
+	if _, err := os.Stat(temp); err == nil {
+		if _, err := readStoreFile(temp); err == nil {
+			if err := os.Rename(temp, filename); err != nil {
+				return nil, "", err
+			}
+		} else {
+			os.Remove(temp)
+		}
+	}
+
+	data, err := readStoreFile(filename)
+	if err == nil {
+		return data, filename, nil
+	}
+	if _, corrupt := err.(*storeCorruptError); !corrupt && !os.IsNotExist(err) {
+		return nil, "", err
+	}
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	for i := 1; i <= storeBackups; i++ {
+		backup := fmt.Sprintf("%s.%d", filename, i)
+		if data, backupErr := readStoreFile(backup); backupErr == nil {
+			return data, backup, err
+		}
+	}
+	return nil, "", err
+}
+
+// writeStoreFile saves the data in the given file so it is never left
+// partly written. The data is written to a temp file, which is synced to
+// disk before it replaces the file, and the previous versions are kept as
+// backups.
+func writeStoreFile(filename string, data []byte) error {
+	dir := filepath.Dir(filename)
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+	// The data is compacted when it is marshalled, so the checksum is of
+	// the compact data, as when it is read
+	var compact bytes.Buffer
+	err = json.Compact(&compact, data)
+	if err != nil {
+		return err
+	}
+	contents, err := json.Marshal(storeFile{Checksum: storeChecksum(compact.Bytes()), Data: compact.Bytes()})
+	if err != nil {
+		return err
+	}
+
+	temp := filename + ".tmp"
+	file, err := os.OpenFile(temp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(contents)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temp)
+		return err
+	}
+
+	// Rotate the backups, the oldest being replaced
+	for i := storeBackups - 1; i >= 1; i-- {
+		backup := fmt.Sprintf("%s.%d", filename, i)
+		if _, err := os.Stat(backup); err == nil {
+			err = os.Rename(backup, fmt.Sprintf("%s.%d", filename, i+1))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := os.Stat(filename); err == nil {
+		err = os.Rename(filename, filename+".1")
+		if err != nil {
+			return err
+		}
+	}
+	err = os.Rename(temp, filename)
+	if err != nil {
+		return err
+	}
+
+	// The renames are only durable once the directory is synced, which
+	// Windows doesn't support, nor need
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// savedStoreFile returns the contents of a store file holding the given data
+func savedStoreFile(t *testing.T, data string) string {
+	contents, err := json.Marshal(storeFile{Checksum: storeChecksum([]byte(data)), Data: json.RawMessage(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(contents)
+}
+
+func TestLoadStoreFile(t *testing.T) {
+	v1, v2, v3 := savedStoreFile(t, `{"v":1}`), savedStoreFile(t, `{"v":2}`), savedStoreFile(t, `{"v":3}`)
+	badChecksum := `{"checksum":"0000","data":{"v":0}}`
+	truncated := v1[:len(v1)/2]
+
+	tests := []struct {
+		name    string
+		files   map[string]string // The contents of the store's files, by suffix
+		want    string
+		source  string // The suffix of the file the data was read from
+		corrupt bool
+		left    []string // The suffixes of the files left afterwards
+	}{
+		{name: "nothing saved", files: map[string]string{}},
+		{name: "saved", files: map[string]string{"": v1}, want: `{"v":1}`, left: []string{""}},
+		{name: "plain JSON", files: map[string]string{"": `{"theme": "dark"}`}, want: `{"theme": "dark"}`, left: []string{""}},
+		{name: "truncated", files: map[string]string{"": truncated, ".1": v2}, want: `{"v":2}`, source: ".1", corrupt: true, left: []string{"", ".1"}},
+		{
+			name:    "checksum mismatch",
+			files:   map[string]string{"": badChecksum, ".1": truncated, ".2": badChecksum, ".3": v3},
+			want:    `{"v":3}`,
+			source:  ".3",
+			corrupt: true,
+			left:    []string{"", ".1", ".2", ".3"},
+		},
+		{name: "no good backup", files: map[string]string{"": badChecksum, ".1": truncated}, corrupt: true, left: []string{"", ".1"}},
+		{name: "missing", files: map[string]string{".1": v2, ".2": v1}, want: `{"v":2}`, source: ".1", left: []string{".1", ".2"}},
+		{name: "temp file promoted", files: map[string]string{"": v1, ".tmp": v2}, want: `{"v":2}`, left: []string{""}},
+		{name: "temp file discarded", files: map[string]string{"": v1, ".tmp": truncated}, want: `{"v":1}`, left: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "store.json")
+			for suffix, contents := range tt.files {
+				if err := ioutil.WriteFile(filename+suffix, []byte(contents), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			data, source, err := loadStoreFile(filename)
+			if _, corrupt := err.(*storeCorruptError); corrupt != tt.corrupt || (err != nil && !corrupt) {
+				t.Errorf("loadStoreFile() error = %v, want corrupt %v", err, tt.corrupt)
+			}
+			if string(data) != tt.want {
+				t.Errorf("loadStoreFile() = %s, want %s", data, tt.want)
+			}
+			wantSource := ""
+			if tt.want != "" {
+				wantSource = filename + tt.source
+			}
+			if source != wantSource {
+				t.Errorf("loadStoreFile() read %q, want %q", source, wantSource)
+			}
+
+			matches, _ := filepath.Glob(filename + "*")
+			if len(matches) != len(tt.left) {
+				t.Errorf("files left = %v, want %q", matches, tt.left)
+			}
+			for _, suffix := range tt.left {
+				if _, err := os.Stat(filename + suffix); err != nil {
+					t.Errorf("%q was removed", filename+suffix)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteStoreFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "stores", "store.json")
+	for version := 1; version <= 5; version++ {
+		if err := writeStoreFile(filename, []byte(fmt.Sprintf(`{ "v": %d }`, version))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The latest three previous versions are kept as backups
+	for suffix, want := range map[string]string{"": `{"v":5}`, ".1": `{"v":4}`, ".2": `{"v":3}`, ".3": `{"v":2}`} {
+		data, err := readStoreFile(filename + suffix)
+		if err != nil {
+			t.Errorf("readStoreFile(%q) error = %v", filename+suffix, err)
+			continue
+		}
+		if string(data) != want {
+			t.Errorf("readStoreFile(%q) = %s, want %s", filename+suffix, data, want)
+		}
+	}
+	for _, suffix := range []string{".4", ".tmp"} {
+		if _, err := os.Stat(filename + suffix); !os.IsNotExist(err) {
+			t.Errorf("%q exists", filename+suffix)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package runtime
+
+import "context"
+
+// Stream sends the results of a bound method to the frontend as they are
+// made, rather than all at once when the method returns, EG: the rows of a
+// large query or the progress of a long running task. Bound methods are
+// given one by taking a *Stream as their first parameter, after the context
+// if they take one, which isn't sent by the frontend. Methods returning a
+// channel stream its values until it is closed. The call's promise resolves
+// once the method returns. If the frontend doesn't handle the results as
+// they are streamed, they are returned as the call's result, unless the
+// method has one.
+type Stream struct {
+	ctx  context.Context
+	send func(data interface{}) error
+}
+
+// NewStream returns a stream for the call with the given context, which
+// sends the data with the given function
+func NewStream(ctx context.Context, send func(data interface{}) error) *Stream {
+	return &Stream{ctx: ctx, send: send}
+}
+
+// Send sends the data to the frontend. It returns an error once the call
+// has been cancelled, after which nothing more is sent.
+func (s *Stream) Send(data interface{}) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	return s.send(data)
+}
+
+// Context returns the context of the call, which is cancelled when the
+// frontend cancels the call
+func (s *Stream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,38 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+)
+
+// Subsystems turns off optional subsystems of the runtime
+type Subsystems = interfaces.Subsystems
+
+// ErrSubsystemDisabled matches, using errors.Is, the errors returned by calls
+// to a subsystem that is disabled in the app's config
+var ErrSubsystemDisabled = errors.New("subsystem disabled")
+
+// SubsystemDisabledError is returned by calls to a disabled subsystem
+type SubsystemDisabledError struct {
+	Subsystem string
+}
+
+func (e *SubsystemDisabledError) Error() string {
+	return fmt.Sprintf("the %s subsystem is disabled", e.Subsystem)
+}
+
+// Is returns true for ErrSubsystemDisabled
+func (e *SubsystemDisabledError) Is(target error) bool {
+	return target == ErrSubsystemDisabled
+}
+
+// disabledError returns the error for calls to the named subsystem,
+// which is nil if it is enabled
+func disabledError(subsystem string, disabled bool) error {
+	if !disabled {
+		return nil
+	}
+	return &SubsystemDisabledError{Subsystem: subsystem}
+}
@@ -0,0 +1,211 @@
+package runtime
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/browser"
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// SystemReport holds the diagnostics included in bug reports.
+// All values are sanitized so they do not contain the user's
+// name or home directory.
+type SystemReport struct {
+	AppName      string   `json:"appName"`
+	AppVersion   string   `json:"appVersion"`
+	Platform     string   `json:"platform"`
+	Arch         string   `json:"arch"`
+	GoVersion    string   `json:"goVersion"`
+	CPUs         int      `json:"cpus"`
+	RecentErrors []string `json:"recentErrors"`
+}
+
+// Markdown returns the report formatted as a markdown table
+// followed by the recent errors, if any
+func (s *SystemReport) Markdown() string {
+	var str strings.Builder
+	str.WriteString("| Name   | Value |\n| ----- | ----- |\n")
+	str.WriteString(fmt.Sprintf("| App         | %s |\n", s.AppName))
+	str.WriteString(fmt.Sprintf("| Version     | %s |\n", s.AppVersion))
+	str.WriteString(fmt.Sprintf("| Platform    | %s |\n", s.Platform))
+	str.WriteString(fmt.Sprintf("| Arch        | %s |\n", s.Arch))
+	str.WriteString(fmt.Sprintf("| Go Version  | %s |\n", s.GoVersion))
+	str.WriteString(fmt.Sprintf("| CPUs        | %d |\n", s.CPUs))
+	if len(s.RecentErrors) > 0 {
+		str.WriteString("\n**Recent Errors**\n```\n")
+		str.WriteString(strings.Join(s.RecentErrors, "\n"))
+		str.WriteString("\n```\n")
+	}
+	return str.String()
+}
+
+// Support exposes bug reporting and support helpers to the runtime
+type Support struct {
+	config interfaces.AppConfig
+	log    *logger.CustomLogger
+}
+
+// NewSupport creates a new Support struct
+func NewSupport(config interfaces.AppConfig) *Support {
+	return &Support{
+		config: config,
+		log:    logger.NewCustomLogger("Support"),
+	}
+}
+
+// sanitize removes the user's home directory and username from the paths in
+// the given string
+func sanitize(text string) string {
+	home, _ := os.UserHomeDir()
+	username := ""
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+	return redactPaths(text, home, username)
+}
+
+// The end of a path component: a separator, or a character that can't be in
+// a username, EG: a quote or space, or the end of the text
+const componentEnd = `([^\w.-]|$)`
+
+// redactPaths replaces the home directory with ~ and the username where it is
+// the directory of a user's profile, EG: /Users/<name>/ or C:\Users\<name>\.
+// Elsewhere the username is left as it is, as it may be part of other words,
+// EG: "dev" in "device".
+func redactPaths(text string, home string, username string) string {
+	if home != "" {
+		homePattern := regexp.MustCompile(regexp.QuoteMeta(home) + componentEnd)
+		text = homePattern.ReplaceAllString(text, "~${1}")
+	}
+
+	// Windows usernames include the domain, EG: DOMAIN\name
+	username = username[strings.LastIndex(username, `\`)+1:]
+	if username == "" {
+		return text
+	}
+	profilePattern := regexp.MustCompile(`(?i)([\\/](?:Users|home|Documents and Settings)[\\/]+)` + regexp.QuoteMeta(username) + componentEnd)
+	return profilePattern.ReplaceAllString(text, "${1}<user>${2}")
+}
+
+// SystemReport returns a sanitized report of the system the app is running on
+func (r *Support) SystemReport() *SystemReport {
+	result := &SystemReport{
+		Platform:     runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		GoVersion:    runtime.Version(),
+		CPUs:         runtime.NumCPU(),
+		RecentErrors: []string{},
+	}
+	if r.config != nil {
+		result.AppName = r.config.GetTitle()
+		result.AppVersion = r.config.GetVersion()
+	}
+	for _, message := range logger.RecentErrors() {
+		result.RecentErrors = append(result.RecentErrors, sanitize(message))
+	}
+	return result
+}
+
+// ReportBug opens the app's issue tracker with a new issue prefilled with the
+// given title, description and the system report. If no issue tracker is
+// configured, a support email is composed instead.
+func (r *Support) ReportBug(title, description string) error {
+	issueTracker := ""
+	if r.config != nil {
+		issueTracker = r.config.GetIssueTracker()
+	}
+	if issueTracker == "" {
+		_, err := r.EmailSupport(title, description)
+		return err
+	}
+
+	body := description + "\n\n**System Details**\n\n" + r.SystemReport().Markdown()
+	params := url.Values{}
+	params.Set("title", title)
+	params.Set("body", body)
+
+	separator := "?"
+	if strings.Contains(issueTracker, "?") {
+		separator = "&"
+	}
+	r.log.Debug("Opening issue tracker")
+	return browser.OpenURL(issueTracker + separator + params.Encode())
+}
+
+// EmailSupport writes a diagnostics bundle and composes a support email in the
+// user's mail client. As mail links cannot carry attachments, the location of
+// the bundle is included in the email so the user may attach it.
+// The path to the bundle is returned.
+func (r *Support) EmailSupport(subject, description string) (string, error) {
+	supportEmail := ""
+	if r.config != nil {
+		supportEmail = r.config.GetSupportEmail()
+	}
+	if supportEmail == "" {
+		return "", fmt.Errorf("no IssueTracker or SupportEmail set in the app config")
+	}
+
+	bundle, err := r.DiagnosticsBundle()
+	if err != nil {
+		return "", err
+	}
+
+	body := description + "\n\n" +
+		"Please attach the diagnostics bundle: " + bundle + "\n"
+
+	// Use PathEscape as mail clients don't treat '+' as a space
+	mailto := "mailto:" + supportEmail +
+		"?subject=" + url.PathEscape(subject) +
+		"&body=" + url.PathEscape(body)
+	r.log.Debug("Composing support email")
+	return bundle, browser.OpenURL(mailto)
+}
+
+// DiagnosticsBundle writes a zip file containing the system report to the
+// temporary directory and returns its path
+func (r *Support) DiagnosticsBundle() (string, error) {
+	report := r.SystemReport()
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	name := "diagnostics"
+	if report.AppName != "" {
+		name = strings.ToLower(strings.Replace(report.AppName, " ", "-", -1)) + "-" + name
+	}
+	filename := filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d.zip", name, time.Now().Unix()))
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	for entry, data := range map[string][]byte{
+		"report.json": reportJSON,
+		"errors.log":  []byte(strings.Join(report.RecentErrors, "\n")),
+	} {
+		writer, err := archive.Create(entry)
+		if err != nil {
+			return "", err
+		}
+		_, err = writer.Write(data)
+		if err != nil {
+			return "", err
+		}
+	}
+	return filename, archive.Close()
+}
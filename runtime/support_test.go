@@ -0,0 +1,33 @@
+package runtime
+
+import (
+	"testing"
+)
+
+func TestRedactPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		home     string
+		username string
+		want     string
+	}{
+		{"home", "open /home/dev/.config/app.json: denied", "/home/dev", "dev", "open ~/.config/app.json: denied"},
+		{"other words", "device developer dev", "/home/dev", "dev", "device developer dev"},
+		{"home prefix", "/home/developer/notes.txt", "/home/dev", "dev", "/home/developer/notes.txt"},
+		{"macos profile", "reading /Users/dev/Library: denied", "", "dev", "reading /Users/<user>/Library: denied"},
+		{"windows profile", `C:\Users\dev\AppData\Roaming`, "", `DOMAIN\dev`, `C:\Users\<user>\AppData\Roaming`},
+		{"windows home", `C:\Users\dev\AppData`, `C:\Users\dev`, "dev", `~\AppData`},
+		{"end of text", "cd /Users/dev", "", "dev", "cd /Users/<user>"},
+		{"quoted", `"/home/dev"`, "", "dev", `"/home/<user>"`},
+		{"other user", "/home/devon/x", "", "dev", "/home/devon/x"},
+		{"no username", "/home/dev/x", "", "", "/home/dev/x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactPaths(tt.text, tt.home, tt.username); got != tt.want {
+				t.Errorf("redactPaths() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package runtime
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// Locale holds the user's language, region and formatting preferences
+type Locale struct {
+	// The language and region as a BCP 47 tag, EG: "en-GB"
+	Tag string `json:"tag"`
+
+	// The ISO 639 language code, EG: "en"
+	Language string `json:"language"`
+
+	// The ISO 3166 region code, EG: "GB". May be empty.
+	Region string `json:"region"`
+
+	// The first day of the week, where Sunday is 0
+	FirstDayOfWeek time.Weekday `json:"firstDayOfWeek"`
+
+	DecimalSeparator string `json:"decimalSeparator"`
+
+	// True if times should be shown with the 24-hour clock
+	Uses24HourClock bool `json:"uses24HourClock"`
+}
+
+// System exposes information about the system the app is running on
+type System struct {
+	config       interfaces.AppConfig
+	eventManager interfaces.EventManager
+	log          *logger.CustomLogger
+
+	machineIDOnce sync.Once
+	machineID     string
+	machineIDErr  error
+
+	ids *ulidGenerator
+
+	statsLock   sync.Mutex
+	lastSample  time.Time
+	lastCPUTime time.Duration
+	stopStats   chan struct{}
+
+	statsDisabled error
+}
+
+// NewSystem creates a new System struct
+func NewSystem(eventManager interfaces.EventManager, config interfaces.AppConfig) *System {
+	return &System{
+		config:       config,
+		eventManager: eventManager,
+		log:          logger.NewCustomLogger("System"),
+		ids:          newULIDGenerator(),
+		lastSample:   time.Now(),
+	}
+}
+
+// MachineID returns an ID for the machine that is stable across launches and
+// reinstalls. The OS machine ID is hashed with the app's title so it can't be
+// used to match the machine across apps.
+func (r *System) MachineID() (string, error) {
+	r.machineIDOnce.Do(func() {
+		id, err := machineID()
+		if err != nil {
+			r.log.Errorf("Unable to read machine ID: %s", err.Error())
+			r.machineIDErr = err
+			return
+		}
+		key := "wails"
+		if r.config != nil {
+			key = r.config.GetTitle()
+		}
+		hash := hmac.New(sha256.New, []byte(key))
+		hash.Write([]byte(strings.TrimSpace(id)))
+		r.machineID = hex.EncodeToString(hash.Sum(nil))
+	})
+	return r.machineID, r.machineIDErr
+}
+
+// NewID returns a new ULID: a 26 character ID that sorts by creation time.
+// IDs created in the same millisecond are still sorted by creation
+// order as they share a single generator for the app.
+func (r *System) NewID() string {
+	return r.ids.next(time.Now())
+}
+
+// Locale returns the user's locale as set in the OS settings.
+// If they cannot be read, the defaults for "en-US" are returned.
+func (r *System) Locale() *Locale {
+	result := &Locale{
+		Tag:              "en-US",
+		Language:         "en",
+		Region:           "US",
+		FirstDayOfWeek:   time.Sunday,
+		DecimalSeparator: ".",
+	}
+	err := systemLocale(result)
+	if err != nil {
+		r.log.Errorf("Unable to read locale: %s", err.Error())
+	}
+	return result
+}
+
+// setLocaleTag sets the tag, language and region of the locale from a locale
+// name in either POSIX or BCP 47 format, EG: "en_GB.UTF-8" or "en-GB"
+func (l *Locale) setLocaleTag(name string) {
+	if index := strings.IndexAny(name, ".@"); index >= 0 {
+		name = name[:index]
+	}
+	if name == "" || name == "C" || name == "POSIX" {
+		return
+	}
+	parts := strings.Split(strings.Replace(name, "_", "-", -1), "-")
+	l.Language = strings.ToLower(parts[0])
+	l.Region = ""
+	// Skip script subtags, EG: "zh-Hant-TW"
+	for _, part := range parts[1:] {
+		if len(part) == 2 || len(part) == 3 && part[0] >= '0' && part[0] <= '9' {
+			l.Region = strings.ToUpper(part)
+			break
+		}
+	}
+	l.Tag = l.Language
+	if l.Region != "" {
+		l.Tag += "-" + l.Region
+	}
+}
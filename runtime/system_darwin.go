@@ -0,0 +1,91 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Foundation
+
+#import <Foundation/Foundation.h>
+#include <mach/mach.h>
+#include <stdlib.h>
+
+// Returns the locale identifier and decimal separator. The caller must free them.
+static void currentLocale(char **identifier, char **decimal, int *firstWeekday, int *uses24Hour) {
+	@autoreleasepool {
+		NSLocale *locale = [NSLocale autoupdatingCurrentLocale];
+		*identifier = strdup([[locale localeIdentifier] UTF8String]);
+		*decimal = strdup([[locale decimalSeparator] UTF8String]);
+		*firstWeekday = (int)[[NSCalendar autoupdatingCurrentCalendar] firstWeekday];
+		NSString *format = [NSDateFormatter dateFormatFromTemplate:@"j" options:0 locale:locale];
+		*uses24Hour = [format rangeOfString:@"a"].location == NSNotFound;
+	}
+}
+
+static unsigned long long residentMemory() {
+	struct mach_task_basic_info info;
+	mach_msg_type_number_t count = MACH_TASK_BASIC_INFO_COUNT;
+	if (task_info(mach_task_self(), MACH_TASK_BASIC_INFO, (task_info_t)&info, &count) != KERN_SUCCESS) {
+		return 0;
+	}
+	return info.resident_size;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+func systemLocale(locale *Locale) error {
+	var identifier, decimal *C.char
+	var firstWeekday, uses24Hour C.int
+	C.currentLocale(&identifier, &decimal, &firstWeekday, &uses24Hour)
+	defer C.free(unsafe.Pointer(identifier))
+	defer C.free(unsafe.Pointer(decimal))
+
+	locale.setLocaleTag(C.GoString(identifier))
+	locale.DecimalSeparator = C.GoString(decimal)
+	// NSCalendar numbers the days from 1 for Sunday
+	locale.FirstDayOfWeek = time.Weekday(firstWeekday-1) % 7
+	locale.Uses24HourClock = uses24Hour == 1
+	return nil
+}
+
+func machineID() (string, error) {
+	output, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.Contains(line, `"IOPlatformUUID"`) {
+			fields := strings.Split(line, `"`)
+			if len(fields) >= 4 {
+				return fields[3], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("IOPlatformUUID not found")
+}
+
+// processUsage returns the CPU time and resident memory of the app's process
+func processUsage() (time.Duration, uint64, error) {
+	var usage syscall.Rusage
+	err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage)
+	if err != nil {
+		return 0, 0, err
+	}
+	cpuTime := time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+	return cpuTime, uint64(C.residentMemory()), nil
+}
+
+// The webview runs in the app's process
+func webviewMemory() uint64 {
+	return 0
+}
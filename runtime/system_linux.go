@@ -0,0 +1,120 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// localeEnv returns the value of the first set locale variable for the given
+// category, following the precedence used by the C library
+func localeEnv(category string) string {
+	for _, name := range []string{"LC_ALL", category, "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// The formatting preferences are read from the C library with the locale
+// command, as desktop settings apply them through the locale variables
+func systemLocale(locale *Locale) error {
+	locale.setLocaleTag(localeEnv("LC_MESSAGES"))
+	locale.Uses24HourClock = locale.Region != "US"
+
+	output, err := exec.Command("locale", "decimal_point", "first_weekday", "week-1stday", "t_fmt").Output()
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 4 {
+		return nil
+	}
+
+	if lines[0] != "" {
+		locale.DecimalSeparator = lines[0]
+	}
+	// The first day is first_weekday days on from week-1stday, counting from 1
+	firstWeekday, err := strconv.Atoi(lines[1])
+	if err == nil {
+		if weekStart, err := time.Parse("20060102", lines[2]); err == nil {
+			locale.FirstDayOfWeek = (weekStart.Weekday() + time.Weekday(firstWeekday-1)) % 7
+		}
+	}
+	// 12-hour formats use %I or %r for the hour
+	format := lines[3]
+	locale.Uses24HourClock = !strings.Contains(format, "%I") && !strings.Contains(format, "%r") &&
+		!strings.Contains(format, "%l")
+	return nil
+}
+
+func machineID() (string, error) {
+	id, err := ioutil.ReadFile("/etc/machine-id")
+	if err != nil {
+		id, err = ioutil.ReadFile("/var/lib/dbus/machine-id")
+	}
+	return string(id), err
+}
+
+// processUsage returns the CPU time and resident memory of the app's process
+func processUsage() (time.Duration, uint64, error) {
+	var usage syscall.Rusage
+	err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage)
+	if err != nil {
+		return 0, 0, err
+	}
+	cpuTime := time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+	memory, err := residentMemory("/proc/self/statm")
+	return cpuTime, memory, err
+}
+
+// residentMemory returns the resident memory in the given statm file
+func residentMemory(statm string) (uint64, error) {
+	data, err := ioutil.ReadFile(statm)
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("invalid statm: %s", statm)
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	return pages * uint64(os.Getpagesize()), err
+}
+
+// webviewMemory returns the resident memory of the WebKit processes,
+// which are children of the app's process
+func webviewMemory() uint64 {
+	stats, _ := filepath.Glob("/proc/[0-9]*/stat")
+	parent := strconv.Itoa(os.Getpid())
+	var total uint64
+	for _, stat := range stats {
+		data, err := ioutil.ReadFile(stat)
+		if err != nil {
+			continue
+		}
+		// The parent PID is the second field after the command, which is in brackets
+		end := strings.LastIndex(string(data), ")")
+		if end < 0 {
+			continue
+		}
+		fields := strings.Fields(string(data[end+1:]))
+		if len(fields) < 2 || fields[1] != parent {
+			continue
+		}
+		if memory, err := residentMemory(filepath.Join(filepath.Dir(stat), "statm")); err == nil {
+			total += memory
+		}
+	}
+	return total
+}
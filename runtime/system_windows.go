@@ -0,0 +1,112 @@
+//go:build windows
+// +build windows
+
+package runtime
+
+import (
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+const (
+	localeSDecimal        = 0x0000000E
+	localeSName           = 0x0000005C
+	localeSTimeFormat     = 0x00001003
+	localeIFirstDayOfWeek = 0x0000100C
+)
+
+var (
+	kernel32                    = windows.NewLazySystemDLL("kernel32.dll")
+	procGetLocaleInfoEx         = kernel32.NewProc("GetLocaleInfoEx")
+	procK32GetProcessMemoryInfo = kernel32.NewProc("K32GetProcessMemoryInfo")
+	user32                      = windows.NewLazySystemDLL("user32.dll")
+)
+
+// processMemoryCounters is PROCESS_MEMORY_COUNTERS
+type processMemoryCounters struct {
+	cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// localeInfo returns the given information for the user's default locale
+func localeInfo(lctype uint32) (string, error) {
+	buffer := make([]uint16, 128)
+	length, _, err := procGetLocaleInfoEx.Call(0, uintptr(lctype),
+		uintptr(unsafe.Pointer(&buffer[0])), uintptr(len(buffer)))
+	if length == 0 {
+		return "", err
+	}
+	return syscall.UTF16ToString(buffer), nil
+}
+
+func systemLocale(locale *Locale) error {
+	name, err := localeInfo(localeSName)
+	if err != nil {
+		return err
+	}
+	locale.setLocaleTag(name)
+
+	if decimal, err := localeInfo(localeSDecimal); err == nil {
+		locale.DecimalSeparator = decimal
+	}
+	// Windows numbers the days from 0 for Monday
+	if day, err := localeInfo(localeIFirstDayOfWeek); err == nil && len(day) == 1 {
+		locale.FirstDayOfWeek = time.Weekday(day[0]-'0'+1) % 7
+	}
+	// 24-hour formats use "H" for the hour
+	if format, err := localeInfo(localeSTimeFormat); err == nil {
+		locale.Uses24HourClock = strings.Contains(format, "H")
+	}
+	return nil
+}
+
+func machineID() (string, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Cryptography`,
+		registry.QUERY_VALUE|registry.WOW64_64KEY)
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+	id, _, err := key.GetStringValue("MachineGuid")
+	return id, err
+}
+
+// processUsage returns the CPU time and working set of the app's process
+func processUsage() (time.Duration, uint64, error) {
+	process := windows.CurrentProcess()
+	var creation, exit, kernel, user windows.Filetime
+	err := windows.GetProcessTimes(process, &creation, &exit, &kernel, &user)
+	if err != nil {
+		return 0, 0, err
+	}
+	// Filetimes are in 100ns intervals
+	cpuTime := 100 * time.Duration(int64(kernel.HighDateTime)<<32+int64(kernel.LowDateTime)+
+		int64(user.HighDateTime)<<32+int64(user.LowDateTime))
+
+	var counters processMemoryCounters
+	counters.cb = uint32(unsafe.Sizeof(counters))
+	ok, _, err := procK32GetProcessMemoryInfo.Call(uintptr(process),
+		uintptr(unsafe.Pointer(&counters)), uintptr(counters.cb))
+	if ok == 0 {
+		return 0, 0, err
+	}
+	return cpuTime, uint64(counters.WorkingSetSize), nil
+}
+
+// The webview runs in the app's process
+func webviewMemory() uint64 {
+	return 0
+}
@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/logger"
+)
+
+// TouchChangedEvent is emitted with the new TouchStatus when it changes,
+// while it's being watched
+const TouchChangedEvent = "wails:touch:changed"
+
+// ErrKeyboardUnavailable is returned by ShowKeyboard when there is no
+// on-screen keyboard to show
+var ErrKeyboardUnavailable = errors.New("no on-screen keyboard is available")
+
+// How often the touch status is read while it's being watched. Tablet mode
+// is switched by folding the device, so it's read more often than power.
+var touchPollInterval = 2 * time.Second
+
+// TouchStatus is whether the machine can be used by touch, and whether it's
+// being used that way
+type TouchStatus struct {
+	// True when a touchscreen is attached
+	Touchscreen bool `json:"touchscreen"`
+
+	// The number of fingers the touchscreen tracks at once, or 0 if unknown
+	MaxTouchPoints int `json:"maxTouchPoints"`
+
+	// True when the device is folded or detached into a tablet, or Windows
+	// is in tablet mode. Apps should switch to touch friendly layouts.
+	TabletMode bool `json:"tabletMode"`
+}
+
+// Touch exposes the machine's touchscreen and tablet mode, so apps can
+// switch to touch friendly layouts, and shows the on-screen keyboard.
+// MacOS has no touchscreens, so it's never in tablet mode.
+type Touch struct {
+	eventManager interfaces.EventManager
+	log          *logger.CustomLogger
+	lock         sync.Mutex
+	stop         chan struct{}
+}
+
+// NewTouch creates a new Touch struct
+func NewTouch(eventManager interfaces.EventManager) *Touch {
+	return &Touch{
+		eventManager: eventManager,
+		log:          logger.NewCustomLogger("Touch"),
+	}
+}
+
+// Status returns whether a touchscreen is attached and the device is in
+// tablet mode
+func (r *Touch) Status() (*TouchStatus, error) {
+	status := &TouchStatus{}
+	err := touchStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// ShowKeyboard shows the on-screen keyboard: the touch keyboard on Windows
+// and Onboard or Squeekboard on Linux. The runtime calls it when an input
+// is focused in tablet mode, as the webview may not.
+func (r *Touch) ShowKeyboard() error {
+	return showKeyboard()
+}
+
+// Watch emits TouchChangedEvent with the TouchStatus whenever it changes,
+// EG: when the device is folded into a tablet, until StopWatching is called
+func (r *Touch) Watch() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	r.stop = stop
+	go func() {
+		last, _ := r.Status()
+		ticker := time.NewTicker(touchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				status, err := r.Status()
+				if err != nil {
+					r.log.Errorf("Unable to read touch status: %s", err.Error())
+					continue
+				}
+				if last == nil || *status != *last {
+					r.log.Debugf("Touch status changed: %+v", *status)
+					r.eventManager.Emit(TouchChangedEvent, status)
+				}
+				last = status
+			}
+		}
+	}()
+}
+
+// StopWatching stops the events started with Watch
+func (r *Touch) StopWatching() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
@@ -0,0 +1,13 @@
+//go:build darwin
+// +build darwin
+
+package runtime
+
+// Macs have no touchscreens or on-screen keyboard for touch
+func touchStatus(status *TouchStatus) error {
+	return nil
+}
+
+func showKeyboard() error {
+	return ErrKeyboardUnavailable
+}
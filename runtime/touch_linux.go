@@ -0,0 +1,104 @@
+//go:build linux
+// +build linux
+
+package runtime
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// swTabletMode is the bit of SW_TABLET_MODE in an input device's switches
+const swTabletMode = 1 << 0x01
+
+// evIOCGSW is the EVIOCGSW(8) ioctl, reading the state of a device's
+// switches
+const evIOCGSW = 2<<30 | 8<<16 | 'E'<<8 | 0x1b
+
+// The D-Bus calls showing the on-screen keyboards of Phosh and other
+// desktops
+var keyboardCalls = [][]string{
+	{"--dest", "sm.puri.OSK0", "--object-path", "/sm/puri/OSK0", "--method", "sm.puri.OSK0.SetVisible", "true"},
+	{"--dest", "org.onboard.Onboard", "--object-path", "/org/onboard/Onboard/Keyboard", "--method", "org.onboard.Onboard.Keyboard.Show"},
+}
+
+// Touchscreens are found in udev's database. Tablet mode is read from the
+// switch of convertibles, which may need the user to be in the input group.
+func touchStatus(status *TouchStatus) error {
+	devices, _ := filepath.Glob("/run/udev/data/c13:*")
+	for _, device := range devices {
+		data, err := ioutil.ReadFile(device)
+		if err == nil && strings.Contains(string(data), "E:ID_INPUT_TOUCHSCREEN=1\n") {
+			status.Touchscreen = true
+			break
+		}
+	}
+	status.TabletMode = tabletModeSwitch()
+	return nil
+}
+
+// tabletModeSwitch returns true if an input device with a tablet mode
+// switch has it turned on
+func tabletModeSwitch() bool {
+	file, err := os.Open("/proc/bus/input/devices")
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	// Devices are blocks of lines, EG: "H: Handlers=event5" and "B: SW=2"
+	var handler string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			handler = ""
+		case strings.HasPrefix(line, "H: Handlers="):
+			for _, name := range strings.Fields(strings.TrimPrefix(line, "H: Handlers=")) {
+				if strings.HasPrefix(name, "event") {
+					handler = name
+				}
+			}
+		case strings.HasPrefix(line, "B: SW="):
+			switches, err := strconv.ParseUint(strings.TrimPrefix(line, "B: SW="), 16, 64)
+			if err == nil && switches&swTabletMode != 0 && handler != "" && readTabletMode(handler) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readTabletMode reads the tablet mode switch of the given event device
+func readTabletMode(handler string) bool {
+	device, err := os.Open("/dev/input/" + handler)
+	if err != nil {
+		return false
+	}
+	defer device.Close()
+	var state uint64
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, device.Fd(), evIOCGSW, uintptr(unsafe.Pointer(&state)))
+	return errno == 0 && state&swTabletMode != 0
+}
+
+func showKeyboard() error {
+	gdbus, err := exec.LookPath("gdbus")
+	if err != nil {
+		return ErrKeyboardUnavailable
+	}
+	for _, call := range keyboardCalls {
+		args := append([]string{"call", "--session"}, call...)
+		if exec.Command(gdbus, args...).Run() == nil {
+			return nil
+		}
+	}
+	return ErrKeyboardUnavailable
+}
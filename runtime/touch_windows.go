@@ -0,0 +1,65 @@
+//go:build windows
+// +build windows
+
+package runtime
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+var procGetSystemMetrics = user32.NewProc("GetSystemMetrics")
+
+// GetSystemMetrics indexes and SM_DIGITIZER flags
+const (
+	smDigitizer            = 94
+	smMaximumTouches       = 95
+	smConvertibleSlateMode = 0x2003
+	nidIntegratedTouch     = 0x01
+	nidExternalTouch       = 0x02
+	nidReady               = 0x80
+)
+
+const immersiveShellKey = `Software\Microsoft\Windows\CurrentVersion\ImmersiveShell`
+
+// Windows 10 has a tablet mode the user turns on. Windows 11 dropped it, so
+// convertibles folded into slates are reported too.
+func touchStatus(status *TouchStatus) error {
+	digitizer, _, _ := procGetSystemMetrics.Call(smDigitizer)
+	status.Touchscreen = digitizer&nidReady != 0 && digitizer&(nidIntegratedTouch|nidExternalTouch) != 0
+	if status.Touchscreen {
+		touches, _, _ := procGetSystemMetrics.Call(smMaximumTouches)
+		status.MaxTouchPoints = int(touches)
+	}
+
+	key, err := registry.OpenKey(registry.CURRENT_USER, immersiveShellKey, registry.QUERY_VALUE)
+	if err == nil {
+		mode, _, err := key.GetIntegerValue("TabletMode")
+		key.Close()
+		status.TabletMode = err == nil && mode == 1
+	}
+	if !status.TabletMode && digitizer&nidIntegratedTouch != 0 {
+		slate, _, _ := procGetSystemMetrics.Call(smConvertibleSlateMode)
+		status.TabletMode = slate == 0
+	}
+	return nil
+}
+
+// The touch keyboard is shown by starting TabTip, which does nothing if
+// it's already shown
+func showKeyboard() error {
+	tabTip := filepath.Join(os.Getenv("CommonProgramFiles"), "microsoft shared", "ink", "TabTip.exe")
+	if _, err := os.Stat(tabTip); err != nil {
+		return ErrKeyboardUnavailable
+	}
+	command := exec.Command(tabTip)
+	err := command.Start()
+	if err != nil {
+		return err
+	}
+	go command.Wait()
+	return nil
+}
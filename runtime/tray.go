@@ -0,0 +1,164 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+)
+
+// TrayClickEvent is emitted with the number of the tray menu item that was
+// chosen, or -1 when the tray icon itself is clicked
+const TrayClickEvent = "wails:tray:click"
+
+// TrayMenuItem is an item in the menu of the tray icon
+type TrayMenuItem struct {
+	Label     string
+	Disabled  bool
+	Checked   bool
+	Separator bool
+
+	// Called when the item is chosen
+	OnClick func()
+}
+
+// Tray shows an icon for the app in the system tray, or the menu bar on
+// MacOS, EG: for apps that run in the background. Nothing is shown until
+// SetIcon is called.
+type Tray struct {
+	renderer interfaces.Renderer
+	lock     sync.Mutex
+	shown    bool
+	tooltip  string
+	menu     []*TrayMenuItem
+	onClick  func()
+}
+
+// NewTray creates a new Tray struct
+func NewTray(eventManager interfaces.EventManager, renderer interfaces.Renderer) *Tray {
+	result := &Tray{
+		renderer: renderer,
+	}
+	eventManager.On(TrayClickEvent, result.clicked)
+	return result
+}
+
+// SetIcon shows the given PNG or ICO data as the tray icon
+func (r *Tray) SetIcon(icon []byte) error {
+	if len(icon) == 0 {
+		return fmt.Errorf("the tray icon is empty")
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	err := r.renderer.SetTrayIcon(icon)
+	if err != nil {
+		return err
+	}
+
+	// The tooltip and menu are given to the icon when it is added
+	if !r.shown {
+		r.shown = true
+		r.renderer.SetTrayTooltip(r.tooltip)
+		r.renderer.SetTrayMenu(encodeTrayMenu(r.menu))
+	}
+	return nil
+}
+
+// SetTooltip sets the text shown when hovering over the tray icon
+func (r *Tray) SetTooltip(tooltip string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.tooltip = tooltip
+	if r.shown {
+		r.renderer.SetTrayTooltip(tooltip)
+	}
+}
+
+// SetMenu replaces the menu shown by the tray icon. The menu is shown by
+// right clicking the icon on Linux and Windows, and by clicking it on MacOS.
+// Call SetMenu again to update the items, EG: to check one.
+func (r *Tray) SetMenu(items []*TrayMenuItem) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.menu = items
+	if r.shown {
+		r.renderer.SetTrayMenu(encodeTrayMenu(items))
+	}
+}
+
+// OnClick sets the function called when the tray icon is clicked. It isn't
+// called on MacOS while the icon has a menu.
+func (r *Tray) OnClick(callback func()) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.onClick = callback
+}
+
+// Remove removes the tray icon. Its tooltip and menu are kept for when
+// SetIcon is next called.
+func (r *Tray) Remove() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.shown {
+		r.shown = false
+		r.renderer.RemoveTray()
+	}
+}
+
+// clicked calls the handler of the icon or the chosen menu item
+func (r *Tray) clicked(data ...interface{}) {
+	if len(data) == 0 {
+		return
+	}
+	var item int
+	switch value := data[0].(type) {
+	case int:
+		item = value
+	case float64:
+		item = int(value)
+	default:
+		return
+	}
+
+	r.lock.Lock()
+	callback := r.onClick
+	if item >= 0 {
+		callback = nil
+		if item < len(r.menu) && !r.menu[item].Separator {
+			callback = r.menu[item].OnClick
+		}
+	}
+	r.lock.Unlock()
+
+	if callback != nil {
+		callback()
+	}
+}
+
+// encodeTrayMenu encodes the items as expected by the renderer, a line per
+// item
+func encodeTrayMenu(items []*TrayMenuItem) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, encodeMenuLine(item.Label, item.Disabled, item.Checked, item.Separator))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// encodeMenuLine encodes a menu item as expected by the renderer: "-" for a
+// separator or whether the item is enabled then whether it's checked, each
+// "0" or "1", followed by its label
+func encodeMenuLine(label string, disabled, checked, separator bool) string {
+	flag := func(set bool) string {
+		if set {
+			return "1"
+		}
+		return "0"
+	}
+	if separator {
+		return "-"
+	}
+	label = strings.NewReplacer("\r", " ", "\n", " ", "\t", " ").Replace(label)
+	return flag(!disabled) + flag(checked) + label
+}
@@ -0,0 +1,74 @@
+package runtime
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// crockford is the base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidGenerator creates monotonic ULIDs as described at https://github.com/ulid/spec
+type ulidGenerator struct {
+	lock       sync.Mutex
+	lastTime   uint64
+	lastRandom [10]byte
+}
+
+func newULIDGenerator() *ulidGenerator {
+	return &ulidGenerator{}
+}
+
+// next returns the ULID for the given time. Within the same millisecond,
+// the random part of the previous ID is incremented instead of regenerated.
+func (g *ulidGenerator) next(now time.Time) string {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	timestamp := uint64(now.UnixNano() / int64(time.Millisecond))
+	if timestamp > g.lastTime || !g.increment() {
+		// If the random part overflows, move on to the next millisecond
+		if timestamp <= g.lastTime {
+			timestamp = g.lastTime + 1
+		}
+		g.lastTime = timestamp
+		rand.Read(g.lastRandom[:])
+	}
+
+	var id [16]byte
+	for index := 0; index < 6; index++ {
+		id[index] = byte(g.lastTime >> uint(40-8*index))
+	}
+	copy(id[6:], g.lastRandom[:])
+	return encodeULID(id)
+}
+
+// increment adds one to the random part, returning false if it overflowed
+func (g *ulidGenerator) increment() bool {
+	for index := len(g.lastRandom) - 1; index >= 0; index-- {
+		g.lastRandom[index]++
+		if g.lastRandom[index] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID encodes the 128 bits of the ID as 26 base32 characters
+func encodeULID(id [16]byte) string {
+	var result [26]byte
+	// 130 bits are encoded, so the value is treated as having 2 leading zero bits
+	for index := 0; index < 26; index++ {
+		bit := index*5 - 2
+		var value int
+		for offset := 0; offset < 5; offset++ {
+			value <<= 1
+			if position := bit + offset; position >= 0 {
+				value |= int(id[position/8]>>uint(7-position%8)) & 1
+			}
+		}
+		result[index] = crockford[value]
+	}
+	return string(result[:])
+}
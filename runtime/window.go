@@ -2,7 +2,14 @@ package runtime
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
 	"runtime"
+	"sync"
+	"unsafe"
 
 	"github.com/abadojack/whatlanggo"
 	"github.com/wailsapp/wails/lib/interfaces"
@@ -50,16 +57,118 @@ func ProcessEncoding(text string) string {
 	return text
 }
 
+// Corner is a corner of the screen
+type Corner int
+
+// The corners a window may be snapped to
+const (
+	CornerTopLeft Corner = iota
+	CornerTopRight
+	CornerBottomLeft
+	CornerBottomRight
+)
+
+// Edge is an edge of the screen
+type Edge int
+
+// The edges a window may be snapped to
+const (
+	EdgeTop Edge = iota + 1
+	EdgeBottom
+	EdgeLeft
+	EdgeRight
+)
+
+// Display describes a display attached to the system. Areas are relative
+// to the top left of the primary display.
+type Display struct {
+	// The ID of the display, as used by Window.MoveToDisplay
+	ID int
+
+	// The whole area of the display
+	Bounds image.Rectangle
+
+	// The area of the display that windows may use,
+	// EG: excluding the taskbar or menu bar
+	WorkArea image.Rectangle
+
+	// The number of physical pixels per logical pixel, EG: 2 for a Retina display
+	ScaleFactor float64
+
+	// True for the display with the menu bar or taskbar
+	Primary bool
+}
+
+// displayArea is an area of a display as given to the frontend
+type displayArea struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+func newDisplayArea(r image.Rectangle) displayArea {
+	return displayArea{X: r.Min.X, Y: r.Min.Y, Width: r.Dx(), Height: r.Dy()}
+}
+
+// MarshalJSON gives the display to the frontend with the
+// areas as x, y, width and height
+func (d Display) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID          int         `json:"id"`
+		Bounds      displayArea `json:"bounds"`
+		WorkArea    displayArea `json:"workArea"`
+		ScaleFactor float64     `json:"scaleFactor"`
+		Primary     bool        `json:"primary"`
+	}{d.ID, newDisplayArea(d.Bounds), newDisplayArea(d.WorkArea), d.ScaleFactor, d.Primary})
+}
+
+// MiniPlayerOptions configures the mini player mode of the window
+type MiniPlayerOptions struct {
+	// The size of the mini player. Defaults to 320x180.
+	Width, Height int
+
+	// The corner of the screen to snap to. Defaults to CornerTopLeft.
+	Corner Corner
+
+	// The distance to keep from the edges of the screen. Defaults to 0.
+	Margin int
+
+	// Pass mouse input through to the windows below
+	ClickThrough bool
+}
+
 // Window exposes an interface for manipulating the window
 type Window struct {
-	renderer interfaces.Renderer
+	renderer    interfaces.Renderer
+	config      interfaces.AppConfig
+	lock        sync.Mutex
+	alwaysOnTop bool
+
+	// Set in mini player mode, with the state of the window
+	// to restore when it is left
+	miniPlayer bool
+	restore    miniPlayerRestore
+}
+
+// miniPlayerRestore is the state of the window before it
+// became a mini player
+type miniPlayerRestore struct {
+	alwaysOnTop   bool
+	width, height int
+	x, y          int
 }
 
 // NewWindow creates a new Window struct
-func NewWindow(renderer interfaces.Renderer) *Window {
-	return &Window{
+func NewWindow(renderer interfaces.Renderer, config interfaces.AppConfig) *Window {
+	result := &Window{
 		renderer: renderer,
+		config:   config,
+	}
+	if config != nil {
+		result.alwaysOnTop = config.GetAlwaysOnTop()
 	}
+	return result
 }
 
 // SetColour sets the the window colour
@@ -77,6 +186,13 @@ func (r *Window) SetMaxSize(width, height int) {
 	r.renderer.SetMaxSize(width, height)
 }
 
+// SetAspectRatio keeps the content of a resizable window at the ratio of the
+// given width and height as it is resized, EG: 16 and 9 for a video player.
+// Use 0 and 0 to unlock it.
+func (r *Window) SetAspectRatio(width, height int) {
+	r.renderer.SetAspectRatio(width, height)
+}
+
 // Fullscreen makes the window fullscreen
 func (r *Window) Fullscreen() {
 	r.renderer.Fullscreen()
@@ -87,12 +203,357 @@ func (r *Window) UnFullscreen() {
 	r.renderer.UnFullscreen()
 }
 
+// Minimise minimises the window
+func (r *Window) Minimise() {
+	r.renderer.Minimise()
+}
+
+// Maximise maximises a resizable window
+func (r *Window) Maximise() {
+	r.renderer.Maximise()
+}
+
+// Restore returns a minimised or maximised window to its previous size and position
+func (r *Window) Restore() {
+	r.renderer.Restore()
+}
+
+// Show shows the window and brings it to the front, EG: when the app was
+// started with StartHidden or the window was hidden with Hide
+func (r *Window) Show() {
+	r.renderer.Show()
+}
+
+// Hide hides the window without closing it
+func (r *Window) Hide() {
+	r.renderer.Hide()
+}
+
 // SetTitle sets the the window title
 func (r *Window) SetTitle(title string) {
 	title = ProcessEncoding(title)
 	r.renderer.SetTitle(title)
 }
 
+// SetIcon sets the window icon to the given PNG or ICO image, EG: to show
+// there are unsaved changes. An empty icon restores the app's icon. On MacOS,
+// where windows don't have icons, the dock icon is changed.
+func (r *Window) SetIcon(icon []byte) error {
+	return r.renderer.SetIcon(icon)
+}
+
+// SetSystemGestures turns the system's pen and touch gestures over the page
+// on or off. Drawing apps turn them off so that panning, press-and-hold and
+// pen flicks reach the page as pointer events, with their pressure and
+// tilt, rather than scrolling it or opening menus.
+func (r *Window) SetSystemGestures(enabled bool) {
+	r.renderer.SetSystemGestures(enabled)
+}
+
+// StartDrag starts dragging the files out of the window, so they can be
+// dropped in the file manager, an email or another app, which gets a copy.
+// It's called while the mouse button is held, EG: from the page's dragstart
+// or mousemove handler, and returns an error if the drag didn't start.
+func (r *Window) StartDrag(files ...string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no files given")
+	}
+	paths := make([]string, len(files))
+	for i, file := range files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+		paths[i] = path
+	}
+	if !r.renderer.StartDrag(paths) {
+		return fmt.Errorf("the drag didn't start as the mouse button isn't held")
+	}
+	return nil
+}
+
+// SetAlwaysOnTop keeps the window above all other windows
+func (r *Window) SetAlwaysOnTop(onTop bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.alwaysOnTop = onTop
+	r.renderer.SetAlwaysOnTop(onTop)
+}
+
+// SetSize sets the size of the window
+func (r *Window) SetSize(width, height int) {
+	r.renderer.SetSize(width, height)
+}
+
+// SnapToCorner moves the window to the given corner of the
+// screen, leaving the given margin
+func (r *Window) SnapToCorner(corner Corner, margin int) {
+	r.renderer.SnapToCorner(int(corner), margin)
+}
+
+// SetIgnoreMouseEvents makes the window pass mouse input through to the
+// windows below it, for transparent overlays such as HUDs. If forwardMove
+// is set, the frontend still receives 'mousemove' events.
+func (r *Window) SetIgnoreMouseEvents(ignore bool, forwardMove bool) {
+	r.renderer.SetIgnoreMouseEvents(ignore, forwardMove)
+}
+
+// SetInputRegions limits mouse input to the given regions of the window.
+// Input elsewhere passes through to the windows below. Calling it without
+// regions makes the whole window receive input again.
+func (r *Window) SetInputRegions(regions ...image.Rectangle) {
+	r.renderer.SetInputRegions(regions)
+}
+
+// Displays returns the displays attached to the system. The
+// "wails:screen:added" and "wails:screen:removed" events are emitted when
+// displays are connected or disconnected, and "wails:screen:changed" when
+// they change in any way, including their resolution or arrangement.
+func (r *Window) Displays() []Display {
+	var result []Display
+	for id, display := range r.renderer.Displays() {
+		result = append(result, Display{
+			ID:          id,
+			Bounds:      display.Bounds,
+			WorkArea:    display.WorkArea,
+			ScaleFactor: display.Scale,
+			Primary:     display.Primary,
+		})
+	}
+	return result
+}
+
+// MoveToDisplay centers the window on the display with the given ID
+func (r *Window) MoveToDisplay(id int) error {
+	if id < 0 || id >= len(r.renderer.Displays()) {
+		return fmt.Errorf("no display with ID %d", id)
+	}
+	r.renderer.PlaceOnDisplay(id, 0, 0)
+	return nil
+}
+
+// SnapTo moves the window to the given edge of the display it is on
+func (r *Window) SnapTo(edge Edge) {
+	r.renderer.PlaceOnDisplay(-1, int(edge), 0)
+}
+
+// CenterOnCursorDisplay centers the window on the display the mouse cursor is on
+func (r *Window) CenterOnCursorDisplay() {
+	r.renderer.PlaceOnDisplay(r.renderer.CursorDisplay(), 0, 0)
+}
+
+// SetPosition moves the top left of the window to the given position,
+// relative to the top left of the primary display
+func (r *Window) SetPosition(x, y int) {
+	r.renderer.SetPosition(x, y)
+}
+
+// GetPosition returns the position of the top left of the window, so it can
+// be restored with AppConfig.StartX and StartY when the app is next started
+func (r *Window) GetPosition() (x, y int) {
+	return r.renderer.Position()
+}
+
+// SetTrafficLightPosition moves the window buttons to the given
+// offset from the top left of the window (MacOS)
+func (r *Window) SetTrafficLightPosition(x, y int) {
+	r.renderer.SetTrafficLightPosition(x, y)
+}
+
+// TitleBarButtonArea returns the area of the window covered by the window
+// buttons when AppConfig.TitleBarOverlay is set, so custom title bars can
+// leave space for them
+func (r *Window) TitleBarButtonArea() image.Rectangle {
+	return r.renderer.TitleBarButtonArea()
+}
+
+// SetTitleBarColour sets the background and symbol colours of the title bar
+// to match a custom theme (Windows 11). Takes the same formats as SetColour.
+func (r *Window) SetTitleBarColour(background, symbol string) error {
+	return r.renderer.SetTitleBarColour(background, symbol)
+}
+
+// ShowEmojiPicker opens the system emoji and character picker, inserting the
+// chosen character into the focused input. Use this where the standard
+// shortcuts don't reach the webview. Requires WebKitGTK 2.28+ on Linux.
+func (r *Window) ShowEmojiPicker() {
+	r.renderer.ShowEmojiPicker()
+}
+
+// RequestUserAttention draws the user's attention to the window when it isn't
+// focused, EG: when a long running task finishes. It flashes the taskbar
+// button on Windows, bounces the dock icon on MacOS and marks the window as
+// urgent on Linux. Critical requests continue until the user switches to the
+// app, on Windows and MacOS.
+func (r *Window) RequestUserAttention(critical bool) {
+	r.renderer.RequestUserAttention(critical)
+}
+
+// SetOpacity fades the whole window, from 0 for invisible to 1 for opaque.
+// Values outside that range are clamped. Fading needs a compositing window
+// manager on Linux.
+func (r *Window) SetOpacity(opacity float64) {
+	r.renderer.SetOpacity(opacity)
+}
+
+// SetProgress shows the progress of a long task, EG: a download, from 0 to
+// 1. It's shown on the window's taskbar button on Windows, the app's dock
+// icon on MacOS and the app's launcher entry on Linux, where the desktop
+// supports the Unity launcher API. Negative progress hides it.
+func (r *Window) SetProgress(progress float64) {
+	r.renderer.SetProgress(progress)
+}
+
+// SetBadge shows a short label where SetProgress shows the progress, EG: a
+// count of unread messages. Linux launchers only show numbers. An empty
+// badge hides it.
+func (r *Window) SetBadge(badge string) {
+	r.renderer.SetBadge(badge)
+}
+
+// Material is a background material of the platform, which the desktop
+// shows through
+type Material string
+
+// The materials of MacOS, from NSVisualEffectMaterial, and of Windows 11.
+// MaterialNone removes the material on every platform.
+const (
+	MaterialNone                  Material = "none"
+	MaterialTitlebar              Material = "titlebar"
+	MaterialSelection             Material = "selection"
+	MaterialMenu                  Material = "menu"
+	MaterialPopover               Material = "popover"
+	MaterialSidebar               Material = "sidebar"
+	MaterialHeaderView            Material = "headerView"
+	MaterialSheet                 Material = "sheet"
+	MaterialWindowBackground      Material = "windowBackground"
+	MaterialHUDWindow             Material = "hudWindow"
+	MaterialFullScreenUI          Material = "fullScreenUI"
+	MaterialToolTip               Material = "toolTip"
+	MaterialContentBackground     Material = "contentBackground"
+	MaterialUnderWindowBackground Material = "underWindowBackground"
+	MaterialUnderPageBackground   Material = "underPageBackground"
+	MaterialMica                  Material = "mica"
+	MaterialAcrylic               Material = "acrylic"
+	MaterialTabbed                Material = "tabbed"
+)
+
+var materials = []Material{
+	MaterialNone, MaterialTitlebar, MaterialSelection, MaterialMenu,
+	MaterialPopover, MaterialSidebar, MaterialHeaderView, MaterialSheet,
+	MaterialWindowBackground, MaterialHUDWindow, MaterialFullScreenUI,
+	MaterialToolTip, MaterialContentBackground,
+	MaterialUnderWindowBackground, MaterialUnderPageBackground,
+	MaterialMica, MaterialAcrylic, MaterialTabbed,
+}
+
+// SetMaterial fills the window's background with the material, EG: the
+// sidebar material on MacOS or Mica on Windows 11, or removes it with
+// MaterialNone. It shows where the page leaves its background transparent
+// and, on Windows, in the title bar. It returns an error if the material
+// isn't supported, see Materials.
+func (r *Window) SetMaterial(material Material) error {
+	return r.renderer.SetMaterial(string(material))
+}
+
+// Materials returns the materials that the window supports on this
+// platform and version of the OS. Linux only has MaterialNone.
+func (r *Window) Materials() []Material {
+	var result []Material
+	for _, material := range materials {
+		if r.renderer.MaterialSupported(string(material)) {
+			result = append(result, material)
+		}
+	}
+	return result
+}
+
+// AttachOverlay shows a native view over the element of the page matching
+// the CSS selector, for content HTML can't show well, EG: a video surface
+// or map view from another library. The view follows the element as the
+// page scrolls and changes, and is hidden while the element is hidden or
+// missing. The view is an NSView* on MacOS, an HWND on Windows and a
+// GtkWidget* on Linux. Attaching another view with the same ID replaces it.
+func (r *Window) AttachOverlay(id string, view unsafe.Pointer, selector string) {
+	r.renderer.AttachOverlay(id, view, selector)
+}
+
+// PlaceOverlay moves the overlay to the given position in the page, in CSS
+// pixels. It is called by the frontend as the overlay's element moves.
+func (r *Window) PlaceOverlay(id string, x, y, width, height int, visible bool) {
+	r.renderer.PlaceOverlay(id, x, y, width, height, visible)
+}
+
+// DetachOverlay takes the overlay off the window. On Linux, the view is
+// destroyed unless a reference to it is held.
+func (r *Window) DetachOverlay(id string) {
+	r.renderer.DetachOverlay(id)
+}
+
+// OnMainThread runs the given function on the main (UI) thread and waits for
+// it to complete. Use this for native calls that must be made from the main
+// thread. It must not be called from the main thread itself.
+func (r *Window) OnMainThread(fn func()) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	r.renderer.Dispatch(func() {
+		defer wg.Done()
+		fn()
+	})
+	wg.Wait()
+}
+
+// MiniPlayer turns the window into a small, borderless window that stays
+// on top of all other windows, as used for media and meeting apps
+func (r *Window) MiniPlayer(options *MiniPlayerOptions) {
+	if options == nil {
+		options = &MiniPlayerOptions{}
+	}
+	width, height := options.Width, options.Height
+	if width <= 0 || height <= 0 {
+		width, height = 320, 180
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.miniPlayer {
+		r.restore.alwaysOnTop = r.alwaysOnTop
+		r.restore.width, r.restore.height = r.renderer.Size()
+		r.restore.x, r.restore.y = r.renderer.Position()
+	}
+	r.miniPlayer = true
+	r.renderer.SetBorderless(true)
+	r.renderer.SetAlwaysOnTop(true)
+	r.renderer.SetSize(width, height)
+	r.renderer.SnapToCorner(int(options.Corner), options.Margin)
+	r.renderer.SetIgnoreMouseEvents(options.ClickThrough, false)
+}
+
+// ExitMiniPlayer restores the window to the size, position and
+// always on top state it had before it became a mini player
+func (r *Window) ExitMiniPlayer() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.miniPlayer {
+		return
+	}
+	r.miniPlayer = false
+	r.renderer.SetIgnoreMouseEvents(false, false)
+	r.renderer.SetAlwaysOnTop(r.restore.alwaysOnTop)
+	r.renderer.SetBorderless(false)
+	r.renderer.SetSize(r.restore.width, r.restore.height)
+	r.renderer.SetPosition(r.restore.x, r.restore.y)
+}
+
+// IsMiniPlayer returns true if the window is in mini player mode
+func (r *Window) IsMiniPlayer() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.miniPlayer
+}
+
 // Close shuts down the window and therefore the app
 func (r *Window) Close() {
 	r.renderer.Close()
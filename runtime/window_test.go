@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/wailsapp/wails/lib/interfaces"
+)
+
+// fakeWindowRenderer records the changes made to the window
+type fakeWindowRenderer struct {
+	interfaces.Renderer
+	calls []string
+}
+
+func (f *fakeWindowRenderer) record(format string, a ...interface{}) {
+	f.calls = append(f.calls, fmt.Sprintf(format, a...))
+}
+
+func (f *fakeWindowRenderer) SetAlwaysOnTop(onTop bool) {
+	f.record("on top %v", onTop)
+}
+
+func (f *fakeWindowRenderer) SetBorderless(borderless bool) {
+	f.record("borderless %v", borderless)
+}
+
+func (f *fakeWindowRenderer) SetSize(width, height int) {
+	f.record("size %dx%d", width, height)
+}
+
+func (f *fakeWindowRenderer) SetPosition(x, y int) {
+	f.record("position %d,%d", x, y)
+}
+
+func (f *fakeWindowRenderer) SnapToCorner(corner, margin int) {}
+
+func (f *fakeWindowRenderer) SetIgnoreMouseEvents(ignore bool, forwardMove bool) {}
+
+func (f *fakeWindowRenderer) Size() (width, height int) {
+	return 800, 600
+}
+
+func (f *fakeWindowRenderer) Position() (x, y int) {
+	return 10, 20
+}
+
+func (f *fakeWindowRenderer) Displays() []interfaces.Display {
+	return []interfaces.Display{
+		{Bounds: image.Rect(0, 0, 1920, 1080), WorkArea: image.Rect(0, 0, 1920, 1040), Scale: 1, Primary: true},
+		{Bounds: image.Rect(1920, 0, 3200, 800), WorkArea: image.Rect(1920, 25, 3200, 800), Scale: 2},
+	}
+}
+
+func TestMiniPlayerRestore(t *testing.T) {
+	renderer := &fakeWindowRenderer{}
+	window := NewWindow(renderer, nil)
+	window.SetAlwaysOnTop(true)
+	window.MiniPlayer(&MiniPlayerOptions{Width: 200, Height: 100})
+	window.MiniPlayer(nil)
+	if !window.IsMiniPlayer() {
+		t.Fatalf("IsMiniPlayer() = false after MiniPlayer()")
+	}
+
+	renderer.calls = nil
+	window.ExitMiniPlayer()
+	window.ExitMiniPlayer()
+	want := []string{"on top true", "borderless false", "size 800x600", "position 10,20"}
+	if !reflect.DeepEqual(renderer.calls, want) {
+		t.Errorf("ExitMiniPlayer() made the changes %q, want %q", renderer.calls, want)
+	}
+	if window.IsMiniPlayer() {
+		t.Errorf("IsMiniPlayer() = true after ExitMiniPlayer()")
+	}
+}
+
+func TestDisplays(t *testing.T) {
+	window := NewWindow(&fakeWindowRenderer{}, nil)
+	displays := window.Displays()
+	if len(displays) != 2 || displays[1].ID != 1 || displays[1].ScaleFactor != 2 || displays[1].Primary {
+		t.Fatalf("Displays() = %+v", displays)
+	}
+
+	// The frontend is given the areas' positions and sizes
+	data, err := json.Marshal(displays[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":1,"bounds":{"x":1920,"y":0,"width":1280,"height":800},` +
+		`"workArea":{"x":1920,"y":25,"width":1280,"height":775},"scaleFactor":2,"primary":false}`
+	if string(data) != want {
+		t.Errorf("Display JSON = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,243 @@
+package wails
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/wailsapp/wails/lib/binding"
+	"github.com/wailsapp/wails/lib/event"
+	"github.com/wailsapp/wails/lib/interfaces"
+	"github.com/wailsapp/wails/lib/ipc"
+	"github.com/wailsapp/wails/lib/logger"
+	"github.com/wailsapp/wails/lib/renderer"
+	wailsruntime "github.com/wailsapp/wails/runtime"
+)
+
+// WindowOptions is the configuration of a window created with App.NewWindow.
+// Fields that aren't set take the same defaults as AppConfig.
+type WindowOptions struct {
+	// The width and height of the window in pixels
+	Width, Height int
+
+	// The title to put in the title bar
+	Title string
+
+	// The HTML, Javascript and CSS of the window, as in AppConfig
+	HTML string
+	JS   string
+	CSS  string
+
+	// The colour of the window. Can take "#fff", "rgb(255,255,255)", "rgba(255,255,255,1)" formats
+	Colour string
+
+	// Indicates whether the window should be resizable
+	Resizable bool
+
+	// Minimum and maximum size of a resizable window
+	MinWidth, MinHeight int
+	MaxWidth, MaxHeight int
+
+	// Keeps the window above all other windows
+	AlwaysOnTop bool
+
+	// The position the window opens at, or whether it opens centred, as in AppConfig
+	StartX, StartY int
+	Centre         bool
+
+	// The background colour of the window, including its alpha, and whether
+	// it is see-through. The backdrops are those of the app.
+	RGBA        *color.RGBA
+	Transparent bool
+}
+
+// Window is an additional window of the app. Each window has its own bound
+// structs and events, so events emitted in one window are not seen by the
+// others. Bound structs are given the window's runtime in WailsInit, and their
+// lifecycle hooks follow the window rather than the app.
+type Window struct {
+	app            *App
+	config         *AppConfig
+	renderer       *renderer.WebView
+	ipc            interfaces.IPCManager
+	bindingManager interfaces.BindingManager
+	eventManager   interfaces.EventManager
+	log            *logger.CustomLogger
+	lock           sync.Mutex
+	opened         bool // Set when Open is called
+	started        bool // Set once the window has been created
+	shutdownOnce   sync.Once
+
+	// Set for panes, which are docked in the host window or, without one,
+	// the main window
+	pane *PaneOptions
+	host *Window
+}
+
+// NewWindow creates an additional window with the given options. Bind the
+// window's structs then call Open to show it.
+func (a *App) NewWindow(options *WindowOptions) *Window {
+	if options == nil {
+		options = &WindowOptions{}
+	}
+	config, _ := newConfig(&AppConfig{
+		Width:            options.Width,
+		Height:           options.Height,
+		Title:            options.Title,
+		HTML:             options.HTML,
+		JS:               options.JS,
+		CSS:              options.CSS,
+		Colour:           options.Colour,
+		Resizable:        options.Resizable,
+		MinWidth:         options.MinWidth,
+		MinHeight:        options.MinHeight,
+		MaxWidth:         options.MaxWidth,
+		MaxHeight:        options.MaxHeight,
+		AlwaysOnTop:      options.AlwaysOnTop,
+		StartX:           options.StartX,
+		StartY:           options.StartY,
+		Centre:           options.Centre,
+		RGBA:             options.RGBA,
+		Transparent:      options.Transparent,
+		MacBackdrop:      a.config.MacBackdrop,
+		WindowsBackdrop:  a.config.WindowsBackdrop,
+		DisableInspector: a.config.DisableInspector,
+		Version:          a.config.Version,
+		IssueTracker:     a.config.IssueTracker,
+		SupportEmail:     a.config.SupportEmail,
+		Subsystems:       a.config.Subsystems,
+		MaxPayloadSize:   a.config.MaxPayloadSize,
+		BinaryData:       a.config.BinaryData,
+	})
+
+	eventManager := event.NewManager()
+	if config.Subsystems.DisableEventBuffering {
+		eventManager = event.NewManagerWithBuffer(0)
+	}
+
+	return &Window{
+		app:            a,
+		config:         config,
+		renderer:       renderer.NewWindowWebView(),
+		ipc:            ipc.NewManager(),
+		bindingManager: binding.NewManager(),
+		eventManager:   eventManager,
+		log:            logger.NewCustomLogger("Window"),
+	}
+}
+
+// Bind allows the user to bind the given object
+// with the window
+func (w *Window) Bind(object interface{}, options ...BindOption) {
+	w.bindingManager.Bind(object, options...)
+}
+
+// Open shows the window. If the app isn't running yet, the window is opened
+// after the main window. A window may only be opened once.
+func (w *Window) Open() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.opened {
+		w.log.Warn("The window has already been opened")
+		return
+	}
+	w.opened = true
+	w.app.openWindow(w)
+}
+
+// Close closes the window. Its bound structs are shut down.
+func (w *Window) Close() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.started {
+		w.renderer.Close()
+	}
+}
+
+// Reload reloads the page of the window, EG: to restart a pane without
+// affecting the rest of its window
+func (w *Window) Reload() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.started {
+		w.renderer.Reload()
+	}
+}
+
+// openWindow opens the window now if the app is running, or queues it
+func (a *App) openWindow(w *Window) {
+	a.windowsLock.Lock()
+	defer a.windowsLock.Unlock()
+	a.windows = append(a.windows, w)
+	if a.running {
+		w.create()
+	}
+}
+
+// create initialises the window on the main thread then starts its managers
+func (w *Window) create() {
+	host, ok := w.app.renderer.(*renderer.WebView)
+	if !ok {
+		w.log.Error("Windows cannot be opened in bridge mode")
+		return
+	}
+	if w.pane != nil {
+		if w.host != nil {
+			host = w.host.renderer
+		}
+		w.renderer.DockInto(host, w.pane.Vertical, w.pane.Size)
+	}
+	w.app.renderer.Dispatch(func() {
+		err := w.renderer.Initialise(w.config, w.ipc, w.eventManager)
+		if err != nil {
+			w.log.Errorf("Unable to open the window: %s", err.Error())
+			return
+		}
+		go w.start()
+	})
+}
+
+func (w *Window) start() {
+	w.eventManager.Start(w.renderer)
+
+	// Shut the window's bound structs down when it closes
+	w.eventManager.On("wails:window:closed", func(...interface{}) {
+		w.shutdown()
+	})
+
+	w.ipc.SetMaxPayloadSize(w.config.GetMaxPayloadSize())
+	w.ipc.SetBinaryResults(w.config.GetBinaryData())
+	w.ipc.Start(w.eventManager, w.bindingManager)
+
+	w.lock.Lock()
+	w.started = true
+	w.lock.Unlock()
+
+	rt := wailsruntime.NewRuntime(w.eventManager, w.renderer, w.config)
+	rt.Flags = w.app.flags
+	err := w.bindingManager.Start(w.renderer, rt)
+	if err != nil {
+		w.log.Errorf("Unable to start the window: %s", err.Error())
+		w.renderer.Close()
+		return
+	}
+
+	err = w.renderer.Run()
+	if err != nil {
+		w.log.Error(err.Error())
+	}
+}
+
+// shutdown stops the window's managers
+func (w *Window) shutdown() {
+	w.lock.Lock()
+	started := w.started
+	w.lock.Unlock()
+	if !started {
+		return
+	}
+	w.shutdownOnce.Do(func() {
+		w.bindingManager.Shutdown()
+		w.ipc.Shutdown()
+		w.eventManager.Shutdown()
+	})
+}